@@ -22,6 +22,9 @@ func (s *AgentSpecStage) Execute(ctx context.Context, bc *pipeline.BuildContext)
 	if bc.PluginConfig != nil {
 		compiler.MergePluginConfig(spec, bc.PluginConfig)
 	}
+	for _, w := range compiler.CompileWarnings(bc.Config, spec) {
+		bc.AddWarning(w)
+	}
 	if bc.WrapperFile != "" {
 		spec.Runtime.Entrypoint = compiler.WrapperEntrypoint(bc.WrapperFile)
 	}
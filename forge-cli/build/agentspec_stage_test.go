@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/initializ/forge/forge-core/agentspec"
@@ -116,6 +117,25 @@ func TestAgentSpecStage_Execute(t *testing.T) {
 	}
 }
 
+func TestAgentSpecStage_CompileWarnings(t *testing.T) {
+	bc := pipeline.NewBuildContext(pipeline.PipelineOptions{OutputDir: t.TempDir()})
+	bc.Config = &types.ForgeConfig{
+		AgentID:    "test-agent",
+		Version:    "0.1.0",
+		Framework:  "custom",
+		Entrypoint: "node index.js",
+	}
+
+	stage := &AgentSpecStage{}
+	if err := stage.Execute(context.Background(), bc); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+
+	if len(bc.Warnings) != 1 || !strings.Contains(bc.Warnings[0], "node:20-slim") {
+		t.Errorf("Warnings = %v, want one node:20-slim inference warning", bc.Warnings)
+	}
+}
+
 func TestMergePluginConfig_FillsGaps(t *testing.T) {
 	spec := &agentspec.AgentSpec{
 		AgentID: "test-agent",
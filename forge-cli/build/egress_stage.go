@@ -8,6 +8,7 @@ import (
 
 	"github.com/initializ/forge/forge-core/pipeline"
 	"github.com/initializ/forge/forge-core/security"
+	coreskills "github.com/initializ/forge/forge-core/skills"
 )
 
 // EgressStage resolves egress configuration and generates allowlist artifacts.
@@ -16,13 +17,6 @@ type EgressStage struct{}
 func (s *EgressStage) Name() string { return "resolve-egress" }
 
 func (s *EgressStage) Execute(ctx context.Context, bc *pipeline.BuildContext) error {
-	cfg := bc.Config.Egress
-
-	// No-op if no egress config
-	if cfg.Profile == "" && cfg.Mode == "" {
-		return nil
-	}
-
 	// Collect tool names for domain inference
 	var toolNames []string
 	if bc.Spec != nil {
@@ -31,9 +25,15 @@ func (s *EgressStage) Execute(ctx context.Context, bc *pipeline.BuildContext) er
 		}
 	}
 
-	resolved, err := security.Resolve(cfg.Profile, cfg.Mode, cfg.AllowedDomains, toolNames, cfg.Capabilities)
+	// Skills declare the domains they call in their frontmatter
+	reqs, _ := bc.SkillRequirements.(*coreskills.AggregatedRequirements)
+	resolved, err := coreskills.ResolveEgress(bc.Config.Egress, toolNames, reqs)
 	if err != nil {
-		return fmt.Errorf("resolving egress: %w", err)
+		return err
+	}
+	// No-op if no egress config
+	if resolved == nil {
+		return nil
 	}
 
 	bc.EgressResolved = resolved
@@ -4,11 +4,13 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/initializ/forge/forge-core/agentspec"
 	"github.com/initializ/forge/forge-core/pipeline"
 	"github.com/initializ/forge/forge-core/security"
+	coreskills "github.com/initializ/forge/forge-core/skills"
 	"github.com/initializ/forge/forge-core/types"
 )
 
@@ -137,3 +139,37 @@ func TestEgressStage_AllowlistWithCapabilities(t *testing.T) {
 		}
 	}
 }
+
+func TestEgressStage_AllowlistWithSkillDomains(t *testing.T) {
+	tmpDir := t.TempDir()
+	bc := pipeline.NewBuildContext(pipeline.PipelineOptions{OutputDir: tmpDir, WorkDir: tmpDir})
+	bc.Config = &types.ForgeConfig{
+		AgentID:    "test",
+		Version:    "1.0.0",
+		Entrypoint: "python main.py",
+		Egress: types.EgressRef{
+			Profile:        "standard",
+			Mode:           "allowlist",
+			AllowedDomains: []string{"api.example.com"},
+		},
+	}
+	bc.Spec = &agentspec.AgentSpec{AgentID: "test"}
+	bc.SkillRequirements = &coreskills.AggregatedRequirements{EgressDomains: []string{"api.weather.example"}}
+
+	stage := &EgressStage{}
+	if err := stage.Execute(context.Background(), bc); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	resolved, ok := bc.EgressResolved.(*security.EgressConfig)
+	if !ok {
+		t.Fatalf("EgressResolved has unexpected type %T", bc.EgressResolved)
+	}
+	for _, d := range []string{"api.example.com", "api.weather.example"} {
+		if !slices.Contains(resolved.AllDomains, d) {
+			t.Errorf("expected %q in AllDomains, got %v", d, resolved.AllDomains)
+		}
+	}
+	if len(bc.Config.Egress.AllowedDomains) != 1 {
+		t.Errorf("config AllowedDomains modified: %v", bc.Config.Egress.AllowedDomains)
+	}
+}
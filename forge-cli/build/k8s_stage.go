@@ -39,23 +39,6 @@ func (s *K8sStage) Execute(ctx context.Context, bc *pipeline.BuildContext) error
 	}
 
 	for _, m := range manifests {
-		// Special handling for network-policy: use egress resolver if available
-		if m.outFile == "network-policy.yaml" && bc.EgressResolved != nil {
-			egressCfg, ok := bc.EgressResolved.(*security.EgressConfig)
-			if ok {
-				policyData, err := security.GenerateK8sNetworkPolicy(bc.Spec.AgentID, egressCfg)
-				if err != nil {
-					return fmt.Errorf("generating network policy from egress config: %w", err)
-				}
-				outPath := filepath.Join(k8sDir, m.outFile)
-				if err := os.WriteFile(outPath, policyData, 0644); err != nil {
-					return fmt.Errorf("writing %s: %w", m.outFile, err)
-				}
-				bc.AddFile(filepath.Join("k8s", m.outFile), outPath)
-				continue
-			}
-		}
-
 		tmplData, err := templates.FS.ReadFile(m.tmplFile)
 		if err != nil {
 			if m.optional {
@@ -87,3 +70,23 @@ func (s *K8sStage) Execute(ctx context.Context, bc *pipeline.BuildContext) error
 
 	return nil
 }
+
+// RenderNetworkPolicy renders k8s/network-policy.yaml for an agent from the
+// network-policy template, the same way forge build does. A nil cfg denies
+// all egress.
+func RenderNetworkPolicy(agentID string, cfg *security.EgressConfig, cni string) ([]byte, error) {
+	tmplData, err := templates.FS.ReadFile("network-policy.yaml.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("reading network policy template: %w", err)
+	}
+	tmpl, err := template.New("network-policy.yaml.tmpl").Parse(string(tmplData))
+	if err != nil {
+		return nil, fmt.Errorf("parsing network policy template: %w", err)
+	}
+	data := &compiler.TemplateSpecData{AgentID: agentID, NetworkPolicy: compiler.NewNetworkPolicyData(cfg, cni)}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering network policy: %w", err)
+	}
+	return buf.Bytes(), nil
+}
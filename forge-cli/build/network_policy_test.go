@@ -2,15 +2,23 @@ package build
 
 import (
 	"context"
+	"flag"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/compiler"
 	"github.com/initializ/forge/forge-core/pipeline"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
 )
 
+var update = flag.Bool("update", false, "rewrite golden files")
+
 func TestK8sStage_NetworkPolicy(t *testing.T) {
 	outDir := t.TempDir()
 	bc := pipeline.NewBuildContext(pipeline.PipelineOptions{OutputDir: outDir})
@@ -87,3 +95,80 @@ func TestK8sStage_NetworkPolicyAllowEgress(t *testing.T) {
 		t.Error("expected deny-all egress for agent without network tools")
 	}
 }
+
+func TestRenderNetworkPolicy_Golden(t *testing.T) {
+	allowlist := &security.EgressConfig{
+		Profile:    security.ProfileStandard,
+		Mode:       security.ModeAllowlist,
+		AllDomains: []string{"*.slack.com", "api.openai.com", "api.tavily.com"},
+	}
+	tests := []struct {
+		golden string
+		cfg    *security.EgressConfig
+		cni    string
+	}{
+		{"netpol_allowlist.golden", allowlist, ""},
+		{"netpol_allowlist_cilium.golden", allowlist, compiler.CNICilium},
+		{"netpol_deny_all.golden", &security.EgressConfig{Profile: security.ProfileStrict, Mode: security.ModeDenyAll}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got, err := RenderNetworkPolicy("support-bot", tt.cfg, tt.cni)
+			if err != nil {
+				t.Fatalf("RenderNetworkPolicy: %v", err)
+			}
+			var doc map[string]any
+			if err := yaml.Unmarshal(got, &doc); err != nil {
+				t.Fatalf("output is not valid YAML: %v\n%s", err, got)
+			}
+
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading golden file (run with -update to create it): %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("output differs from %s:\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestK8sStage_NetworkPolicyFromEgress(t *testing.T) {
+	outDir := t.TempDir()
+	bc := pipeline.NewBuildContext(pipeline.PipelineOptions{OutputDir: outDir})
+	bc.Config = &types.ForgeConfig{Egress: types.EgressRef{Mode: "allowlist", CNI: "cilium"}}
+	bc.Spec = &agentspec.AgentSpec{
+		AgentID: "web-agent",
+		Version: "0.2.0",
+		Runtime: &agentspec.RuntimeConfig{Image: "python:3.12-slim", Entrypoint: []string{"python", "agent.py"}, Port: 8080},
+	}
+	bc.EgressResolved = &security.EgressConfig{
+		Profile:    security.ProfileStandard,
+		Mode:       security.ModeAllowlist,
+		AllDomains: []string{"api.openai.com"},
+	}
+
+	if err := (&K8sStage{}).Execute(context.Background(), bc); err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "k8s", "network-policy.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := RenderNetworkPolicy("web-agent", bc.EgressResolved.(*security.EgressConfig), "cilium")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(want) {
+		t.Errorf("network-policy.yaml differs from RenderNetworkPolicy:\n%s", data)
+	}
+	if !strings.Contains(string(data), "name: web-agent-network") || !strings.Contains(string(data), `matchName: "api.openai.com"`) {
+		t.Errorf("network-policy.yaml = \n%s", data)
+	}
+}
@@ -42,7 +42,7 @@ func (s *SkillsStage) Execute(ctx context.Context, bc *pipeline.BuildContext) er
 
 	// Aggregate skill requirements and store in build context
 	reqs := coreskills.AggregateRequirements(entries)
-	if len(reqs.Bins) > 0 || len(reqs.EnvRequired) > 0 || len(reqs.EnvOneOf) > 0 || len(reqs.EnvOptional) > 0 {
+	if len(reqs.Bins) > 0 || len(reqs.EnvRequired) > 0 || len(reqs.EnvOneOf) > 0 || len(reqs.EnvOptional) > 0 || len(reqs.EgressDomains) > 0 {
 		bc.SkillRequirements = reqs
 	}
 
@@ -2,10 +2,12 @@ package build
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/initializ/forge/forge-core/agentspec"
 	"github.com/initializ/forge/forge-core/pipeline"
 	"github.com/initializ/forge/forge-core/validate"
 )
@@ -30,6 +32,20 @@ func (s *ValidateStage) Execute(ctx context.Context, bc *pipeline.BuildContext)
 		return fmt.Errorf("agent.json validation failed: %v", errs)
 	}
 
+	var spec agentspec.AgentSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return fmt.Errorf("parsing agent.json: %w", err)
+	}
+	// agent.json is the source of truth for image tags and export filenames
+	if bc.Config != nil {
+		if err := validate.CheckSpecIdentity(bc.Config, &spec); err != nil {
+			return err
+		}
+	}
+	if err := validate.CheckGuardrails(spec.PolicyScaffold); err != nil {
+		return err
+	}
+
 	requiredFiles := []string{"agent.json", "Dockerfile"}
 	for _, f := range requiredFiles {
 		path := filepath.Join(bc.Opts.OutputDir, f)
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/initializ/forge/forge-core/agentspec"
 	"github.com/initializ/forge/forge-core/pipeline"
+	"github.com/initializ/forge/forge-core/types"
 )
 
 func TestValidateStage_Valid(t *testing.T) {
@@ -41,6 +43,28 @@ func TestValidateStage_Valid(t *testing.T) {
 	}
 }
 
+func TestValidateStage_VersionDrift(t *testing.T) {
+	outDir := t.TempDir()
+	bc := pipeline.NewBuildContext(pipeline.PipelineOptions{OutputDir: outDir})
+	bc.Config = &types.ForgeConfig{AgentID: "test-agent", Version: "0.2.0"}
+
+	spec := &agentspec.AgentSpec{
+		ForgeVersion: "1.0",
+		AgentID:      "test-agent",
+		Version:      "0.1.0",
+		Name:         "test-agent",
+	}
+	data, _ := json.MarshalIndent(spec, "", "  ")
+	_ = os.WriteFile(filepath.Join(outDir, "agent.json"), data, 0644)
+	_ = os.WriteFile(filepath.Join(outDir, "Dockerfile"), []byte("FROM python:3.12-slim\n"), 0644)
+
+	stage := &ValidateStage{}
+	err := stage.Execute(context.Background(), bc)
+	if err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("expected version drift error, got %v", err)
+	}
+}
+
 func TestValidateStage_InvalidSpec(t *testing.T) {
 	outDir := t.TempDir()
 	bc := pipeline.NewBuildContext(pipeline.PipelineOptions{OutputDir: outDir})
@@ -0,0 +1,42 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// ErrNotAllowed is returned by an Allowlist handler for events from users
+// or workspaces outside the configured lists.
+var ErrNotAllowed = errors.New("not allowed")
+
+// Allowlist returns an EventHandler that passes events to next only when
+// their user and workspace are in cfg's allowed_users and
+// allowed_workspaces lists; an empty list allows everyone. Other events are
+// answered with cfg's refusal_message, or dropped with ErrNotAllowed when
+// none is set.
+func Allowlist(cfg channels.ChannelConfig, next channels.EventHandler) channels.EventHandler {
+	if len(cfg.AllowedUsers) == 0 && len(cfg.AllowedWorkspaces) == 0 {
+		return next
+	}
+	return func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+		if allowed(cfg.AllowedWorkspaces, event.WorkspaceID) && allowed(cfg.AllowedUsers, event.UserID) {
+			return next(ctx, event)
+		}
+		if cfg.RefusalMessage != "" {
+			return &a2a.Message{
+				Role:  a2a.MessageRoleAgent,
+				Parts: []a2a.Part{a2a.NewTextPart(cfg.RefusalMessage)},
+			}, nil
+		}
+		return nil, fmt.Errorf("user %s in %s: %w", event.UserID, event.WorkspaceID, ErrNotAllowed)
+	}
+}
+
+func allowed(list []string, id string) bool {
+	return len(list) == 0 || slices.Contains(list, id)
+}
@@ -0,0 +1,127 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// countingHandler returns an EventHandler that counts its calls.
+func countingHandler(calls *int) channels.EventHandler {
+	return func(_ context.Context, _ *channels.ChannelEvent) (*a2a.Message, error) {
+		*calls++
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("agent reply")}}, nil
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	cfg := channels.ChannelConfig{
+		AllowedUsers:      []string{"U1", "U2"},
+		AllowedWorkspaces: []string{"W1"},
+	}
+	tests := []struct {
+		name      string
+		user      string
+		workspace string
+		allowed   bool
+	}{
+		{"allowed user and workspace", "U1", "W1", true},
+		{"user not allowed", "U3", "W1", false},
+		{"workspace not allowed", "U2", "W2", false},
+		{"neither allowed", "U3", "W2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := Allowlist(cfg, countingHandler(&calls))
+			resp, err := handler(context.Background(), &channels.ChannelEvent{UserID: tt.user, WorkspaceID: tt.workspace})
+			if tt.allowed {
+				if err != nil || calls != 1 || resp == nil {
+					t.Errorf("allowed event: calls = %d, err = %v", calls, err)
+				}
+				return
+			}
+			if calls != 0 {
+				t.Errorf("agent called %d times for a disallowed event", calls)
+			}
+			if !errors.Is(err, ErrNotAllowed) {
+				t.Errorf("err = %v, want ErrNotAllowed", err)
+			}
+		})
+	}
+}
+
+func TestAllowlist_EmptyListsAllowAll(t *testing.T) {
+	calls := 0
+	handler := Allowlist(channels.ChannelConfig{}, countingHandler(&calls))
+	if _, err := handler(context.Background(), &channels.ChannelEvent{UserID: "anyone", WorkspaceID: "anywhere"}); err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestAllowlist_RefusalMessage(t *testing.T) {
+	calls := 0
+	cfg := channels.ChannelConfig{AllowedUsers: []string{"U1"}, RefusalMessage: "Sorry, this assistant is private."}
+	resp, err := Allowlist(cfg, countingHandler(&calls))(context.Background(), &channels.ChannelEvent{UserID: "U9"})
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("agent called %d times for a disallowed user", calls)
+	}
+	if resp == nil || resp.Parts[0].Text != "Sorry, this assistant is private." {
+		t.Errorf("resp = %+v, want the refusal message", resp)
+	}
+}
+
+func TestAllowlist_NoAgentRequest(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	handler := Allowlist(channels.ChannelConfig{AllowedUsers: []string{"U1"}}, NewRouter(srv.URL).Handler())
+	if _, err := handler(context.Background(), &channels.ChannelEvent{Channel: "slack", UserID: "U2"}); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("err = %v, want ErrNotAllowed", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("A2A server received %d requests for a disallowed user", n)
+	}
+}
+
+func TestLoadChannelConfig_Allowlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telegram-config.yaml")
+	data := `adapter: telegram
+allowed_users: ["12345", "67890"]
+allowed_workspaces:
+  - "-100200300"
+refusal_message: Not for you.
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadChannelConfig(path)
+	if err != nil {
+		t.Fatalf("LoadChannelConfig() error: %v", err)
+	}
+	if !slices.Equal(cfg.AllowedUsers, []string{"12345", "67890"}) || !slices.Equal(cfg.AllowedWorkspaces, []string{"-100200300"}) {
+		t.Errorf("allowlists = %v, %v", cfg.AllowedUsers, cfg.AllowedWorkspaces)
+	}
+	if cfg.RefusalMessage != "Not for you." {
+		t.Errorf("RefusalMessage = %q", cfg.RefusalMessage)
+	}
+}
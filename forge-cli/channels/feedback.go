@@ -0,0 +1,37 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+// feedbackRecord is one line written by FeedbackLog.
+type feedbackRecord struct {
+	Time time.Time `json:"time"`
+	*channels.FeedbackEvent
+}
+
+// FeedbackLog returns a FeedbackHandler that appends each feedback event to w
+// as a JSON line stamped with the time it was received. The lines can be
+// joined with task transcripts by task_id to build eval datasets.
+func FeedbackLog(w io.Writer) channels.FeedbackHandler {
+	var mu sync.Mutex
+	return func(_ context.Context, fb *channels.FeedbackEvent) error {
+		line, err := json.Marshal(feedbackRecord{Time: time.Now().UTC(), FeedbackEvent: fb})
+		if err != nil {
+			return fmt.Errorf("marshalling feedback: %w", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("writing feedback: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+func TestFeedbackLog(t *testing.T) {
+	var buf bytes.Buffer
+	handler := FeedbackLog(&buf)
+
+	events := []*channels.FeedbackEvent{
+		{Channel: "slack", WorkspaceID: "C1", UserID: "U1", MessageID: "1.2", TaskID: "slack-C1-1", Reaction: "+1", Rating: 1},
+		{Channel: "telegram", WorkspaceID: "42", UserID: "7", MessageID: "9", TaskID: "telegram-42-2", Reaction: "👎", Rating: -1},
+	}
+	for _, fb := range events {
+		if err := handler(context.Background(), fb); err != nil {
+			t.Fatalf("handler: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	if rec["task_id"] != "telegram-42-2" || rec["rating"] != float64(-1) || rec["time"] == nil {
+		t.Errorf("record = %v", rec)
+	}
+}
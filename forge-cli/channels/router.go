@@ -4,19 +4,51 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/channels"
 )
 
+// RetryPolicy controls how the router retries agent calls that fail
+// transiently: connection errors, 5xx and 429 responses. The
+// delay before retry n is BaseDelay doubled n-1 times, capped at MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int // total attempts, including the first; 1 disables retries
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy is the policy NewRouter uses.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 500 * time.Millisecond, MaxDelay: 5 * time.Second}
+
+// unavailableText is sent to the channel when the agent cannot be reached.
+const unavailableText = "The agent is temporarily unavailable. Please try again in a moment."
+
+// timeoutText is sent to the channel when the agent does not reply within
+// the router's HTTP timeout.
+const timeoutText = "The agent did not reply in time. It may still be working on your request."
+
+// dedupTTL is how long the router remembers a delivered event, so a
+// redelivery within it is not forwarded again.
+const dedupTTL = 10 * time.Minute
+
 // Router forwards channel events to an A2A agent server via JSON-RPC over HTTP.
 type Router struct {
 	agentURL string
 	client   *http.Client
+	dedup    *channels.Deduplicator
+	retry    RetryPolicy
+
+	mu       sync.Mutex
+	sessions map[string]string // conversation key → session ID issued by the agent
 }
 
 // NewRouter creates a Router that forwards events to the A2A server at agentURL.
@@ -26,24 +58,44 @@ func NewRouter(agentURL string) *Router {
 		client: &http.Client{
 			Timeout: 120 * time.Second,
 		},
+		dedup:    channels.NewDeduplicator(dedupTTL),
+		retry:    DefaultRetryPolicy,
+		sessions: make(map[string]string),
 	}
 }
 
+// SetRetryPolicy replaces the policy for retrying failed agent calls.
+func (r *Router) SetRetryPolicy(p RetryPolicy) {
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = 1
+	}
+	r.retry = p
+}
+
 // Handler returns an EventHandler suitable for passing to ChannelPlugin.Start().
+// Repeated deliveries of an event are dropped with channels.ErrDuplicateEvent.
 func (r *Router) Handler() channels.EventHandler {
-	return r.forwardToA2A
+	return channels.Dedup(r.dedup, r.forwardToA2A)
 }
 
 // forwardToA2A sends a tasks/send JSON-RPC request to the A2A server and
-// extracts the agent's response message from the returned task.
+// extracts the agent's response message from the returned task. Transient
+// failures are retried with the same task ID, which the server treats as a
+// resend; when every attempt fails the user is told the agent is
+// unavailable. A timeout is not retried, since the resend would only return
+// the task still working; the user is told the reply is late. Other
+// failures are returned as errors.
 func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
 	taskID := fmt.Sprintf("%s-%s-%d", event.Channel, event.WorkspaceID, time.Now().UnixMilli())
+	event.TaskID = taskID
 
+	conversation := conversationKey(event)
 	params := a2a.SendTaskParams{
-		ID: taskID,
+		ID:        taskID,
+		SessionID: r.sessionFor(conversation),
 		Message: a2a.Message{
 			Role:  a2a.MessageRoleUser,
-			Parts: []a2a.Part{a2a.NewTextPart(event.Message)},
+			Parts: append([]a2a.Part{a2a.NewTextPart(event.PromptText())}, attachmentParts(event)...),
 		},
 	}
 
@@ -64,21 +116,25 @@ func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent)
 		return nil, fmt.Errorf("marshalling request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.agentURL, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	resp, err := r.client.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("sending request to A2A server: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := r.postWithRetry(ctx, body)
 	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+		var transient *transientError
+		var timeout *timeoutError
+		if errors.As(err, &timeout) && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "channels: agent timed out for %s task %s: %v\n", event.Channel, taskID, err)
+			return &a2a.Message{
+				Role:  a2a.MessageRoleAgent,
+				Parts: []a2a.Part{a2a.NewTextPart(timeoutText)},
+			}, nil
+		}
+		if errors.As(err, &transient) && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "channels: agent unavailable for %s task %s: %v\n", event.Channel, taskID, err)
+			return &a2a.Message{
+				Role:  a2a.MessageRoleAgent,
+				Parts: []a2a.Part{a2a.NewTextPart(unavailableText)},
+			}, nil
+		}
+		return nil, err
 	}
 
 	var rpcResp a2a.JSONRPCResponse
@@ -100,6 +156,7 @@ func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent)
 	if err := json.Unmarshal(resultJSON, &task); err != nil {
 		return nil, fmt.Errorf("parsing task from result: %w", err)
 	}
+	r.rememberSession(conversation, task.SessionID)
 
 	if task.Status.Message != nil {
 		return task.Status.Message, nil
@@ -110,3 +167,121 @@ func (r *Router) forwardToA2A(ctx context.Context, event *channels.ChannelEvent)
 		Parts: []a2a.Part{a2a.NewTextPart("(no response)")},
 	}, nil
 }
+
+// transientError is a failed agent call that may succeed if retried.
+type transientError struct{ err error }
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// timeoutError is an agent call that got no reply within the HTTP timeout.
+// The agent may still be running the task, so it is not retried.
+type timeoutError struct{ err error }
+
+func (e *timeoutError) Error() string { return e.err.Error() }
+func (e *timeoutError) Unwrap() error { return e.err }
+
+// postWithRetry posts body to the agent, retrying transient failures with
+// exponential backoff. The last error is returned once attempts run out.
+func (r *Router) postWithRetry(ctx context.Context, body []byte) ([]byte, error) {
+	delay := r.retry.BaseDelay
+	for attempt := 1; ; attempt++ {
+		respBody, err := r.post(ctx, body)
+		var transient *transientError
+		if err == nil || !errors.As(err, &transient) || attempt >= r.retry.MaxAttempts {
+			if err != nil && attempt > 1 {
+				err = fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+			return respBody, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, r.retry.MaxDelay)
+	}
+}
+
+// post makes one call to the agent. Connection errors, 5xx and 429
+// responses are returned as transientError, and a timeout as timeoutError.
+func (r *Router) post(ctx context.Context, body []byte) ([]byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.agentURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("sending request to A2A server: %w", err)
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, &timeoutError{fmt.Errorf("sending request to A2A server: %w", err)}
+		}
+		return nil, &transientError{fmt.Errorf("sending request to A2A server: %w", err)}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &transientError{fmt.Errorf("reading response: %w", err)}
+	}
+
+	switch {
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return nil, &transientError{fmt.Errorf("A2A server returned status %d", resp.StatusCode)}
+	case resp.StatusCode >= 400:
+		return nil, fmt.Errorf("A2A server returned status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	return respBody, nil
+}
+
+// conversationKey identifies a user's conversation in one chat.
+func conversationKey(event *channels.ChannelEvent) string {
+	return fmt.Sprintf("%s-%s-%s", event.Channel, event.WorkspaceID, event.UserID)
+}
+
+// sessionFor returns the session ID to send for a conversation, so the agent
+// remembers it across tasks. The agent issues session IDs itself; until it
+// has issued one, or after it expires, the conversation key asks for a new
+// session.
+func (r *Router) sessionFor(conversation string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id, ok := r.sessions[conversation]; ok {
+		return id
+	}
+	return conversation
+}
+
+// rememberSession records the session ID the agent issued for a
+// conversation.
+func (r *Router) rememberSession(conversation, id string) {
+	if id == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[conversation] = id
+}
+
+// attachmentParts returns a file part, by URI, for each attachment of event
+// that has a URL. The executor passes images among them to vision-capable
+// models.
+func attachmentParts(event *channels.ChannelEvent) []a2a.Part {
+	var parts []a2a.Part
+	for _, a := range event.Attachments {
+		if a.URL == "" {
+			continue
+		}
+		parts = append(parts, a2a.Part{
+			Kind: a2a.PartKindFile,
+			File: &a2a.FileContent{Name: a.Name, MimeType: a.MimeType, URI: a.URL},
+		})
+	}
+	return parts
+}
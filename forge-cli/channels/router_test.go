@@ -3,15 +3,20 @@ package channels
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/channels"
 )
 
 func TestRouter_ForwardToA2A_Success(t *testing.T) {
+	var sentID string
 	// Mock A2A server
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var req a2a.JSONRPCRequest
@@ -31,9 +36,14 @@ func TestRouter_ForwardToA2A_Success(t *testing.T) {
 		if params.Message.Parts[0].Text != "hello agent" {
 			t.Errorf("unexpected message text: %s", params.Message.Parts[0].Text)
 		}
+		sentID = params.ID
+		if params.SessionID != "test-W123-U456" {
+			t.Errorf("session id = %q, want test-W123-U456", params.SessionID)
+		}
 
 		task := a2a.Task{
-			ID: params.ID,
+			ID:        params.ID,
+			SessionID: "session-issued",
 			Status: a2a.TaskStatus{
 				State: a2a.TaskStateCompleted,
 				Message: &a2a.Message{
@@ -56,6 +66,9 @@ func TestRouter_ForwardToA2A_Success(t *testing.T) {
 		UserID:      "U456",
 		Message:     "hello agent",
 	}
+	if got := router.sessionFor(conversationKey(event)); got != "test-W123-U456" {
+		t.Errorf("session before the first reply = %q, want the conversation key", got)
+	}
 
 	msg, err := router.forwardToA2A(context.Background(), event)
 	if err != nil {
@@ -69,6 +82,13 @@ func TestRouter_ForwardToA2A_Success(t *testing.T) {
 	if len(msg.Parts) != 1 || msg.Parts[0].Text != "hello user" {
 		t.Errorf("unexpected response text: %v", msg.Parts)
 	}
+	if event.TaskID == "" || event.TaskID != sentID {
+		t.Errorf("event.TaskID = %q, want the sent task ID %q", event.TaskID, sentID)
+	}
+	// Later messages use the session the agent issued
+	if got := router.sessionFor(conversationKey(event)); got != "session-issued" {
+		t.Errorf("session after the reply = %q, want session-issued", got)
+	}
 }
 
 func TestRouter_ForwardToA2A_Error(t *testing.T) {
@@ -129,6 +149,75 @@ func TestRouter_ForwardToA2A_NoMessage(t *testing.T) {
 	}
 }
 
+func TestRouter_ForwardToA2A_MessageContext(t *testing.T) {
+	var gotText string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		var params a2a.SendTaskParams
+		json.Unmarshal(req.Params, &params) //nolint:errcheck
+		gotText = params.Message.Parts[0].Text
+
+		resp := a2a.NewResponse(req.ID, a2a.Task{ID: params.ID, Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	router := NewRouter(srv.URL)
+	event := &channels.ChannelEvent{
+		Channel:     "test",
+		WorkspaceID: "W123",
+		Message:     "is this right?",
+		Context: &channels.MessageContext{
+			ReplyToText:   "the deploy is at 5pm",
+			ForwardedFrom: "Alice",
+		},
+	}
+
+	if _, err := router.forwardToA2A(context.Background(), event); err != nil {
+		t.Fatalf("forwardToA2A() error: %v", err)
+	}
+	want := "[Forwarded from Alice]\n[Replying to: \"the deploy is at 5pm\"]\nis this right?"
+	if gotText != want {
+		t.Errorf("forwarded text = %q, want %q", gotText, want)
+	}
+}
+
+func TestRouter_ForwardToA2A_Attachments(t *testing.T) {
+	var gotParts []a2a.Part
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		var params a2a.SendTaskParams
+		json.Unmarshal(req.Params, &params) //nolint:errcheck
+		gotParts = params.Message.Parts
+
+		resp := a2a.NewResponse(req.ID, a2a.Task{ID: params.ID, Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	event := &channels.ChannelEvent{
+		Channel: "test",
+		Message: "what is this?",
+		Attachments: []channels.Attachment{
+			{Name: "cat.png", MimeType: "image/png", URL: "https://cdn.example.com/cat.png"},
+			{Name: "no-url.png", MimeType: "image/png"},
+		},
+	}
+	if _, err := NewRouter(srv.URL).forwardToA2A(context.Background(), event); err != nil {
+		t.Fatalf("forwardToA2A() error: %v", err)
+	}
+	if len(gotParts) != 2 || gotParts[1].Kind != a2a.PartKindFile || gotParts[1].File == nil {
+		t.Fatalf("parts = %+v, want the text and one file part", gotParts)
+	}
+	if f := gotParts[1].File; f.URI != "https://cdn.example.com/cat.png" || f.MimeType != "image/png" || f.Name != "cat.png" {
+		t.Errorf("file part = %+v", f)
+	}
+}
+
 func TestRouter_Handler(t *testing.T) {
 	router := NewRouter("http://localhost:9999")
 	handler := router.Handler()
@@ -136,3 +225,145 @@ func TestRouter_Handler(t *testing.T) {
 		t.Fatal("Handler() returned nil")
 	}
 }
+
+func TestRouter_Handler_DropsDuplicateDelivery(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req a2a.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		task := a2a.Task{Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}}
+		json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, task)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	handler := NewRouter(srv.URL).Handler()
+	event := func() *channels.ChannelEvent {
+		return &channels.ChannelEvent{Channel: "telegram", WorkspaceID: "555", UserID: "99", MessageID: "100", Message: "hi"}
+	}
+
+	if _, err := handler(context.Background(), event()); err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+	if _, err := handler(context.Background(), event()); !errors.Is(err, channels.ErrDuplicateEvent) {
+		t.Errorf("redelivery err = %v, want ErrDuplicateEvent", err)
+	}
+	if requests != 1 {
+		t.Errorf("A2A server received %d requests, want 1", requests)
+	}
+}
+
+// fastRetry keeps retry tests quick.
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+func TestRouter_RetriesFlakyAgent(t *testing.T) {
+	var taskIDs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		var params a2a.SendTaskParams
+		json.Unmarshal(req.Params, &params) //nolint:errcheck
+		taskIDs = append(taskIDs, params.ID)
+
+		// Fail twice, then answer
+		if len(taskIDs) <= 2 {
+			http.Error(w, "starting up", http.StatusServiceUnavailable)
+			return
+		}
+		task := a2a.Task{ID: params.ID, Status: a2a.TaskStatus{
+			State:   a2a.TaskStateCompleted,
+			Message: &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("hello user")}},
+		}}
+		json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, task)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	router := NewRouter(srv.URL)
+	router.SetRetryPolicy(fastRetry)
+	msg, err := router.forwardToA2A(context.Background(), &channels.ChannelEvent{Channel: "test", WorkspaceID: "W1", Message: "hello"})
+	if err != nil {
+		t.Fatalf("forwardToA2A() error: %v", err)
+	}
+	if msg.Parts[0].Text != "hello user" {
+		t.Errorf("response = %q, want hello user", msg.Parts[0].Text)
+	}
+	if len(taskIDs) != 3 {
+		t.Fatalf("agent called %d times, want 3", len(taskIDs))
+	}
+	if taskIDs[0] != taskIDs[1] || taskIDs[1] != taskIDs[2] {
+		t.Errorf("retries used different task IDs: %v", taskIDs)
+	}
+}
+
+func TestRouter_ClientErrorFailsFast(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	router := NewRouter(srv.URL)
+	router.SetRetryPolicy(fastRetry)
+	_, err := router.forwardToA2A(context.Background(), &channels.ChannelEvent{Channel: "test", Message: "hello"})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("err = %v, want the 401 status", err)
+	}
+	if calls != 1 {
+		t.Errorf("agent called %d times, want 1", calls)
+	}
+}
+
+func TestRouter_AgentUnavailable(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	router := NewRouter(srv.URL)
+	router.SetRetryPolicy(fastRetry)
+	msg, err := router.forwardToA2A(context.Background(), &channels.ChannelEvent{Channel: "test", Message: "hello"})
+	if err != nil {
+		t.Fatalf("forwardToA2A() error: %v", err)
+	}
+	if msg.Parts[0].Text != unavailableText {
+		t.Errorf("response = %q, want the unavailable message", msg.Parts[0].Text)
+	}
+	if calls != fastRetry.MaxAttempts {
+		t.Errorf("agent called %d times, want %d", calls, fastRetry.MaxAttempts)
+	}
+
+	// A server that is not listening at all is unavailable too
+	srv.Close()
+	msg, err = router.forwardToA2A(context.Background(), &channels.ChannelEvent{Channel: "test", Message: "hello"})
+	if err != nil || msg.Parts[0].Text != unavailableText {
+		t.Errorf("connection refused: msg = %+v, err = %v", msg, err)
+	}
+}
+
+func TestRouter_TimeoutIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	router := NewRouter(srv.URL)
+	router.SetRetryPolicy(fastRetry)
+	router.client.Timeout = 50 * time.Millisecond
+	msg, err := router.forwardToA2A(context.Background(), &channels.ChannelEvent{Channel: "test", Message: "hello"})
+	if err != nil {
+		t.Fatalf("forwardToA2A() error: %v", err)
+	}
+	if msg.Parts[0].Text != timeoutText {
+		t.Errorf("response = %q, want the timeout message", msg.Parts[0].Text)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("agent called %d times, want 1", n)
+	}
+}
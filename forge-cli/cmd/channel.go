@@ -6,12 +6,15 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
 	"syscall"
 
 	"github.com/initializ/forge/forge-cli/channels"
 	"github.com/initializ/forge/forge-cli/templates"
 	corechannels "github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-plugins/channels/discord"
 	"github.com/initializ/forge/forge-plugins/channels/slack"
 	"github.com/initializ/forge/forge-plugins/channels/telegram"
 	"github.com/spf13/cobra"
@@ -21,22 +24,22 @@ import (
 var channelCmd = &cobra.Command{
 	Use:   "channel",
 	Short: "Manage agent communication channels",
-	Long:  "Add and serve channel adapters (Slack, Telegram) for your agent.",
+	Long:  "Add and serve channel adapters (Slack, Telegram, Discord) for your agent.",
 }
 
 var channelAddCmd = &cobra.Command{
-	Use:       "add <slack|telegram>",
+	Use:       "add <slack|telegram|discord>",
 	Short:     "Add a channel adapter to the project",
 	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"slack", "telegram"},
+	ValidArgs: []string{"slack", "telegram", "discord"},
 	RunE:      runChannelAdd,
 }
 
 var channelServeCmd = &cobra.Command{
-	Use:       "serve <slack|telegram>",
+	Use:       "serve <slack|telegram|discord>",
 	Short:     "Run a standalone channel adapter (for container use)",
 	Args:      cobra.ExactArgs(1),
-	ValidArgs: []string{"slack", "telegram"},
+	ValidArgs: []string{"slack", "telegram", "discord"},
 	RunE:      runChannelServe,
 }
 
@@ -47,8 +50,8 @@ func init() {
 
 func runChannelAdd(cmd *cobra.Command, args []string) error {
 	adapter := args[0]
-	if adapter != "slack" && adapter != "telegram" {
-		return fmt.Errorf("unsupported adapter: %s (supported: slack, telegram)", adapter)
+	if createPlugin(adapter) == nil {
+		return fmt.Errorf("unsupported adapter: %s (supported: slack, telegram, discord)", adapter)
 	}
 
 	wd, err := os.Getwd()
@@ -100,8 +103,8 @@ func runChannelAdd(cmd *cobra.Command, args []string) error {
 
 func runChannelServe(cmd *cobra.Command, args []string) error {
 	adapter := args[0]
-	if adapter != "slack" && adapter != "telegram" {
-		return fmt.Errorf("unsupported adapter: %s (supported: slack, telegram)", adapter)
+	if createPlugin(adapter) == nil {
+		return fmt.Errorf("unsupported adapter: %s (supported: slack, telegram, discord)", adapter)
 	}
 
 	// Load channel config
@@ -131,6 +134,9 @@ func runChannelServe(cmd *cobra.Command, args []string) error {
 	if err := plugin.Init(*cfg); err != nil {
 		return fmt.Errorf("initialising %s plugin: %w", adapter, err)
 	}
+	if fp, ok := plugin.(corechannels.FeedbackPlugin); ok {
+		fp.SetFeedbackHandler(channels.FeedbackLog(os.Stderr))
+	}
 
 	// Create router
 	router := channels.NewRouter(agentURL)
@@ -148,7 +154,7 @@ func runChannelServe(cmd *cobra.Command, args []string) error {
 	}()
 
 	fmt.Fprintf(os.Stderr, "Starting %s adapter (agent: %s)\n", adapter, agentURL)
-	return plugin.Start(ctx, router.Handler())
+	return plugin.Start(ctx, channels.Allowlist(*cfg, router.Handler()))
 }
 
 // createPlugin returns a new ChannelPlugin for the named adapter.
@@ -158,6 +164,8 @@ func createPlugin(name string) corechannels.ChannelPlugin {
 		return slack.New()
 	case "telegram":
 		return telegram.New()
+	case "discord":
+		return discord.New()
 	default:
 		return nil
 	}
@@ -168,6 +176,7 @@ func defaultRegistry() *corechannels.Registry {
 	r := corechannels.NewRegistry()
 	r.Register(slack.New())
 	r.Register(telegram.New())
+	r.Register(discord.New())
 	return r
 }
 
@@ -282,30 +291,11 @@ func addChannelEgressToForgeYAML(path, adapter string) error {
 		}
 		egressMap["capabilities"] = capsAny
 
-	case "telegram":
-		// Add "api.telegram.org" to egress.allowed_domains
-		var domains []string
-		if existing, ok := egressMap["allowed_domains"]; ok {
-			if arr, ok := existing.([]any); ok {
-				for _, v := range arr {
-					if s, ok := v.(string); ok {
-						domains = append(domains, s)
-					}
-				}
-			}
-		}
-		// Check if already present
-		for _, d := range domains {
-			if d == "api.telegram.org" {
-				return nil // already present
-			}
-		}
-		domains = append(domains, "api.telegram.org")
-		domainsAny := make([]any, len(domains))
-		for i, s := range domains {
-			domainsAny[i] = s
+	case "telegram", "discord":
+		// Add the adapter's API hosts to egress.allowed_domains
+		if len(mergeAllowedDomains(egressMap, security.DefaultCapabilityBundles[adapter])) == 0 {
+			return nil // already present
 		}
-		egressMap["allowed_domains"] = domainsAny
 	}
 
 	doc["egress"] = egressMap
@@ -318,6 +308,35 @@ func addChannelEgressToForgeYAML(path, adapter string) error {
 	return os.WriteFile(path, out, 0644)
 }
 
+// mergeAllowedDomains adds the hosts missing from egressMap's
+// allowed_domains, keeping the existing order, and returns those it added.
+func mergeAllowedDomains(egressMap map[string]any, hosts []string) []string {
+	var domains []string
+	if arr, ok := egressMap["allowed_domains"].([]any); ok {
+		for _, v := range arr {
+			if s, ok := v.(string); ok {
+				domains = append(domains, s)
+			}
+		}
+	}
+	var added []string
+	for _, host := range hosts {
+		if !slices.Contains(domains, host) {
+			domains = append(domains, host)
+			added = append(added, host)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	domainsAny := make([]any, len(domains))
+	for i, s := range domains {
+		domainsAny[i] = s
+	}
+	egressMap["allowed_domains"] = domainsAny
+	return added
+}
+
 func printSetupInstructions(adapter string) {
 	fmt.Println()
 	switch adapter {
@@ -338,7 +357,18 @@ func printSetupInstructions(adapter string) {
 		fmt.Println()
 		fmt.Println("  For webhook mode (requires public URL):")
 		fmt.Println("    Set mode: webhook in telegram-config.yaml")
-		fmt.Println("    Set your webhook URL via Telegram Bot API")
+		fmt.Println("    Set webhook_url to your public webhook URL in telegram-config.yaml")
+		fmt.Println("    Set webhook_secret_env to a variable holding a secret token")
+	case "discord":
+		fmt.Println("Discord setup instructions:")
+		fmt.Println("  1. Create an application at https://discord.com/developers/applications")
+		fmt.Println("  2. Under Bot, enable the Message Content intent")
+		fmt.Println("  3. Invite the bot to your server with the Send Messages permission")
+		fmt.Println("  4. Copy the bot token into .env")
+		fmt.Println("  5. Run: forge run --with discord")
+		fmt.Println()
+		fmt.Println("  In server channels the bot only answers when mentioned;")
+		fmt.Println("  set require_mention: \"false\" in discord-config.yaml to answer every message.")
 	}
 	fmt.Println()
 	fmt.Println(strings.Repeat("─", 40))
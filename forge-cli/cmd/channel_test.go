@@ -128,7 +128,7 @@ entrypoint: python agent.py
 }
 
 func TestChannelAddUnsupported(t *testing.T) {
-	err := runChannelAdd(nil, []string{"discord"})
+	err := runChannelAdd(nil, []string{"whatsapp"})
 	if err == nil {
 		t.Fatal("expected error for unsupported adapter")
 	}
@@ -272,6 +272,36 @@ entrypoint: python agent.py
 	}
 }
 
+func TestAddChannelEgress_Discord(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestForgeYAML(t, dir, `
+agent_id: test-agent
+version: 0.1.0
+framework: custom
+entrypoint: python agent.py
+egress:
+  allowed_domains:
+    - discord.com
+`)
+
+	if err := addChannelEgressToForgeYAML(path, "discord"); err != nil {
+		t.Fatalf("addChannelEgressToForgeYAML(discord) error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading forge.yaml: %v", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("parsing forge.yaml: %v", err)
+	}
+	domains := doc["egress"].(map[string]any)["allowed_domains"].([]any)
+	if len(domains) != 2 || domains[0] != "discord.com" || domains[1] != "gateway.discord.gg" {
+		t.Errorf("allowed_domains = %v, want [discord.com gateway.discord.gg]", domains)
+	}
+}
+
 func TestChannelAddTelegram_UpdatesEgress(t *testing.T) {
 	dir := t.TempDir()
 	origDir, _ := os.Getwd()
@@ -384,7 +414,12 @@ func TestGenerateChannelConfig(t *testing.T) {
 		t.Error("telegram config missing adapter")
 	}
 
-	unknown := generateChannelConfig("discord")
+	dc := generateChannelConfig("discord")
+	if !strings.Contains(dc, "adapter: discord") || !strings.Contains(dc, "DISCORD_BOT_TOKEN") {
+		t.Error("discord config missing adapter or bot token")
+	}
+
+	unknown := generateChannelConfig("whatsapp")
 	if unknown != "" {
 		t.Errorf("unknown adapter should return empty, got %q", unknown)
 	}
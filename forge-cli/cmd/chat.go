@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/initializ/forge/forge-cli/internal/tui"
+	"github.com/initializ/forge/forge-cli/runtime"
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/spf13/cobra"
+)
+
+var (
+	chatURL      string
+	chatNoStream bool
+)
+
+var chatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Chat with a running agent from the terminal",
+	Long: "Chat sends each line you type to a running agent over A2A and prints its reply.\n\n" +
+		"All messages share one session, so the agent remembers the conversation. Replies are " +
+		"streamed with tasks/sendSubscribe unless --no-stream is set. Type /reset to start a new " +
+		"session and /exit (or Ctrl-D) to quit.",
+	Args: cobra.NoArgs,
+	RunE: runChat,
+}
+
+func init() {
+	chatCmd.Flags().StringVar(&chatURL, "url", "", "agent URL (default: $AGENT_URL, the port of a local forge run, or http://localhost:8080)")
+	chatCmd.Flags().BoolVar(&chatNoStream, "no-stream", false, "wait for each complete reply instead of streaming it")
+}
+
+func runChat(cmd *cobra.Command, args []string) error {
+	url := chatURL
+	if url == "" {
+		url = defaultChatURL(filepath.Dir(cfgFile))
+	}
+
+	out := cmd.OutOrStdout()
+	c := &chatClient{
+		url:    url,
+		client: &http.Client{},
+		stream: !chatNoStream,
+		out:    out,
+		render: newTermMarkdown(out, tui.DetectTheme(themeOverride)),
+	}
+	c.newSession()
+	return c.run(cmd.Context(), cmd.InOrStdin())
+}
+
+// defaultChatURL returns AGENT_URL if set, then the port recorded by a
+// forge run in workDir, then the forge run default.
+func defaultChatURL(workDir string) string {
+	if u := os.Getenv("AGENT_URL"); u != "" {
+		return u
+	}
+	if port, err := runtime.ReadPortFile(workDir); err == nil {
+		return fmt.Sprintf("http://localhost:%d", port)
+	}
+	return "http://localhost:8080"
+}
+
+// chatClient is an interactive session with one agent.
+type chatClient struct {
+	url       string
+	client    *http.Client
+	chatID    string // prefix of this session's task IDs
+	sessionID string // issued by the agent after the first reply
+	stream    bool
+	turn      int
+	out       io.Writer
+	render    *termMarkdown
+}
+
+// newSession starts a new conversation. Until the agent replies with the
+// session ID it issued, the chat ID asks it for a new session.
+func (c *chatClient) newSession() {
+	c.chatID = fmt.Sprintf("chat-%d", time.Now().UnixNano())
+	c.sessionID = c.chatID
+	c.turn = 0
+}
+
+// adoptSession keeps the session ID the agent issued with task.
+func (c *chatClient) adoptSession(task *a2a.Task) {
+	if task.SessionID != "" {
+		c.sessionID = task.SessionID
+	}
+}
+
+// run reads lines from in until /exit or end of input. A failed message is
+// reported and the session continues.
+func (c *chatClient) run(ctx context.Context, in io.Reader) error {
+	fmt.Fprintf(c.out, "Chatting with %s. Type /reset for a new session, /exit to quit.\n", c.url)
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for {
+		fmt.Fprint(c.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(c.out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case line == "/exit" || line == "/quit":
+			return nil
+		case line == "/reset":
+			c.newSession()
+			fmt.Fprintln(c.out, "Started a new session.")
+			continue
+		case strings.HasPrefix(line, "/"):
+			fmt.Fprintf(c.out, "Unknown command %s. Use /reset or /exit.\n", line)
+			continue
+		}
+
+		if err := c.send(ctx, line); err != nil {
+			c.render.flush()
+			fmt.Fprintln(c.out, c.render.errorStyle.Render("error: "+err.Error()))
+		}
+	}
+}
+
+// send delivers one message in the current session and prints the reply.
+func (c *chatClient) send(ctx context.Context, text string) error {
+	c.turn++
+	taskID := fmt.Sprintf("%s-%d", c.chatID, c.turn)
+	method := "tasks/send"
+	if c.stream {
+		method = "tasks/sendSubscribe"
+	}
+
+	params, err := json.Marshal(a2a.SendTaskParams{
+		ID:        taskID,
+		SessionID: c.sessionID,
+		Message: a2a.Message{
+			Role:  a2a.MessageRoleUser,
+			Parts: []a2a.Part{a2a.NewTextPart(text)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling params: %w", err)
+	}
+	body, err := json.Marshal(a2a.JSONRPCRequest{JSONRPC: "2.0", ID: taskID, Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("marshalling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream, application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request to agent: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("agent returned status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return c.readEvents(resp.Body)
+	}
+
+	var rpcResp a2a.JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("parsing JSON-RPC response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("A2A error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	var task a2a.Task
+	if err := remarshal(rpcResp.Result, &task); err != nil {
+		return fmt.Errorf("parsing task from result: %w", err)
+	}
+	c.adoptSession(&task)
+	c.printTask(&task)
+	return nil
+}
+
+// readEvents prints a tasks/sendSubscribe stream: partial status messages as
+// they arrive, then the final task if nothing was streamed.
+func (c *chatClient) readEvents(r io.Reader) error {
+	streamed := false
+	var event string
+	var data strings.Builder
+
+	dispatch := func() error {
+		defer func() { event = ""; data.Reset() }()
+		switch event {
+		case "status":
+			var task a2a.Task
+			if err := json.Unmarshal([]byte(data.String()), &task); err != nil {
+				return nil
+			}
+			if msg := task.Status.Message; a2a.IsPartial(msg) {
+				c.render.write(messageText(msg))
+				streamed = true
+			}
+		case "result":
+			var task a2a.Task
+			if err := json.Unmarshal([]byte(data.String()), &task); err != nil {
+				return fmt.Errorf("parsing result: %w", err)
+			}
+			c.adoptSession(&task)
+			c.render.flush()
+			if !streamed || task.Status.State != a2a.TaskStateCompleted {
+				c.printTask(&task)
+			}
+		case "error":
+			var rpcResp a2a.JSONRPCResponse
+			if err := json.Unmarshal([]byte(data.String()), &rpcResp); err == nil && rpcResp.Error != nil {
+				return fmt.Errorf("A2A error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+			}
+			return fmt.Errorf("agent error: %s", data.String())
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if err := dispatch(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading stream: %w", err)
+	}
+	if err := dispatch(); err != nil {
+		return err
+	}
+	c.render.flush()
+	return nil
+}
+
+// printTask prints the reply carried by a finished task.
+func (c *chatClient) printTask(task *a2a.Task) {
+	text := messageText(task.Status.Message)
+	switch task.Status.State {
+	case a2a.TaskStateFailed, a2a.TaskStateRejected, a2a.TaskStateCanceled:
+		if text == "" {
+			text = string(task.Status.State)
+		}
+		fmt.Fprintln(c.out, c.render.errorStyle.Render("error: "+text))
+		return
+	}
+	if text == "" {
+		text = "(no response)"
+	}
+	c.render.write(text)
+	c.render.flush()
+}
+
+// messageText joins the text parts of msg.
+func messageText(msg *a2a.Message) string {
+	if msg == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, p := range msg.Parts {
+		if p.Kind == a2a.PartKindText {
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
+
+func remarshal(in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+var (
+	mdHeaderRe = regexp.MustCompile(`^#{1,6}\s+(.+)$`)
+	mdBulletRe = regexp.MustCompile(`^(\s*)[-*+]\s+(.+)$`)
+	mdQuoteRe  = regexp.MustCompile(`^>\s?(.*)$`)
+	mdCodeRe   = regexp.MustCompile("`([^`]+)`")
+	mdBoldRe   = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdLinkRe   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// termMarkdown renders markdown for the terminal line by line, so streamed
+// text can be printed as soon as each line is complete. Colors are dropped
+// when the output is not a terminal.
+type termMarkdown struct {
+	out    io.Writer
+	buf    string
+	inCode bool
+
+	headerStyle lipgloss.Style
+	boldStyle   lipgloss.Style
+	codeStyle   lipgloss.Style
+	blockStyle  lipgloss.Style
+	quoteStyle  lipgloss.Style
+	errorStyle  lipgloss.Style
+}
+
+func newTermMarkdown(out io.Writer, theme tui.TermTheme) *termMarkdown {
+	r := lipgloss.NewRenderer(out)
+	return &termMarkdown{
+		out:         out,
+		headerStyle: r.NewStyle().Bold(true).Foreground(theme.Accent),
+		boldStyle:   r.NewStyle().Bold(true),
+		codeStyle:   r.NewStyle().Foreground(theme.Accent),
+		blockStyle:  r.NewStyle().Foreground(theme.Secondary),
+		quoteStyle:  r.NewStyle().Foreground(theme.Dim),
+		errorStyle:  r.NewStyle().Foreground(theme.Error),
+	}
+}
+
+// write renders every complete line in text, buffering the remainder.
+func (m *termMarkdown) write(text string) {
+	m.buf += text
+	for {
+		i := strings.IndexByte(m.buf, '\n')
+		if i < 0 {
+			return
+		}
+		m.emit(m.buf[:i])
+		m.buf = m.buf[i+1:]
+	}
+}
+
+// flush renders any buffered partial line and ends the reply.
+func (m *termMarkdown) flush() {
+	if m.buf != "" {
+		m.emit(m.buf)
+		m.buf = ""
+	}
+	m.inCode = false
+}
+
+func (m *termMarkdown) emit(line string) {
+	if strings.HasPrefix(strings.TrimSpace(line), "```") {
+		m.inCode = !m.inCode
+		return
+	}
+	fmt.Fprintln(m.out, m.renderLine(line))
+}
+
+func (m *termMarkdown) renderLine(line string) string {
+	if m.inCode {
+		return "    " + m.blockStyle.Render(line)
+	}
+	if sm := mdHeaderRe.FindStringSubmatch(line); sm != nil {
+		return m.headerStyle.Render(m.inline(sm[1]))
+	}
+	if sm := mdBulletRe.FindStringSubmatch(line); sm != nil {
+		return sm[1] + "• " + m.inline(sm[2])
+	}
+	if sm := mdQuoteRe.FindStringSubmatch(line); sm != nil {
+		return m.quoteStyle.Render("│ " + sm[1])
+	}
+	return m.inline(line)
+}
+
+// inline renders code spans, bold text and links. Text inside code spans
+// is left as written.
+func (m *termMarkdown) inline(s string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range mdCodeRe.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(m.inlineText(s[last:loc[0]]))
+		sb.WriteString(m.codeStyle.Render(s[loc[2]:loc[3]]))
+		last = loc[1]
+	}
+	sb.WriteString(m.inlineText(s[last:]))
+	return sb.String()
+}
+
+func (m *termMarkdown) inlineText(s string) string {
+	s = mdLinkRe.ReplaceAllString(s, "$1 ($2)")
+	return mdBoldRe.ReplaceAllStringFunc(s, func(match string) string {
+		return m.boldStyle.Render(match[2 : len(match)-2])
+	})
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/initializ/forge/forge-cli/internal/tui"
+	"github.com/initializ/forge/forge-cli/runtime"
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// fakeChatAgent answers tasks/send and tasks/sendSubscribe, recording the
+// messages it receives. Like forge run, it issues its own session ID for a
+// session it does not know.
+type fakeChatAgent struct {
+	mu       sync.Mutex
+	methods  []string
+	messages []a2a.SendTaskParams
+}
+
+func (f *fakeChatAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req a2a.JSONRPCRequest
+	json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+	var params a2a.SendTaskParams
+	json.Unmarshal(req.Params, &params) //nolint:errcheck
+
+	f.mu.Lock()
+	f.methods = append(f.methods, req.Method)
+	f.messages = append(f.messages, params)
+	turn := len(f.messages)
+	f.mu.Unlock()
+
+	session := params.SessionID
+	if !strings.HasPrefix(session, "session-") {
+		session = fmt.Sprintf("session-%d", turn)
+	}
+
+	reply := func(text string, partial bool) *a2a.Message {
+		msg := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(text)}}
+		if partial {
+			msg.Metadata = map[string]any{a2a.MetadataPartial: true}
+		}
+		return msg
+	}
+
+	if req.Method == "tasks/send" {
+		task := a2a.Task{ID: params.ID, SessionID: session, Status: a2a.TaskStatus{
+			State:   a2a.TaskStateCompleted,
+			Message: reply("# Summary\n- first `item`\n```\ncode line\n```", false),
+		}}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(a2a.NewResponse(req.ID, task)) //nolint:errcheck
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	flusher := w.(http.Flusher)
+	status := func(msg *a2a.Message) a2a.Task {
+		return a2a.Task{ID: params.ID, Status: a2a.TaskStatus{State: a2a.TaskStateWorking, Message: msg}}
+	}
+	server.WriteSSEEvent(w, flusher, "status", status(nil))                                 //nolint:errcheck
+	server.WriteSSEEvent(w, flusher, "status", status(reply("Hello, ", true)))              //nolint:errcheck
+	server.WriteSSEEvent(w, flusher, "status", status(reply("**world** #", true)))          //nolint:errcheck
+	server.WriteSSEEvent(w, flusher, "status", status(reply(string(rune('0'+turn)), true))) //nolint:errcheck
+	final := a2a.Task{ID: params.ID, SessionID: session, Status: a2a.TaskStatus{
+		State:   a2a.TaskStateCompleted,
+		Message: reply("Hello, **world** #"+string(rune('0'+turn)), false),
+	}}
+	server.WriteSSEEvent(w, flusher, "result", final) //nolint:errcheck
+}
+
+func runTestChat(t *testing.T, agent http.Handler, stream bool, input string) string {
+	t.Helper()
+	srv := httptest.NewServer(agent)
+	defer srv.Close()
+
+	var out bytes.Buffer
+	c := &chatClient{
+		url:    srv.URL,
+		client: srv.Client(),
+		stream: stream,
+		out:    &out,
+		render: newTermMarkdown(&out, tui.DarkTheme),
+	}
+	c.newSession()
+	if err := c.run(context.Background(), strings.NewReader(input)); err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	return out.String()
+}
+
+func TestChat_StreamsRepliesInOneSession(t *testing.T) {
+	agent := &fakeChatAgent{}
+	out := runTestChat(t, agent, true, "hi\n\nagain\n/reset\nthird\n/exit\nnever sent\n")
+
+	if len(agent.messages) != 3 {
+		t.Fatalf("agent got %d messages, want 3", len(agent.messages))
+	}
+	for _, m := range agent.methods {
+		if m != "tasks/sendSubscribe" {
+			t.Errorf("method = %q, want tasks/sendSubscribe", m)
+		}
+	}
+	first, second, third := agent.messages[0], agent.messages[1], agent.messages[2]
+	if !strings.HasPrefix(first.SessionID, "chat-") || second.SessionID != "session-1" {
+		t.Errorf("session IDs = %q, %q; want a new session, then the one the agent issued", first.SessionID, second.SessionID)
+	}
+	if !strings.HasPrefix(third.SessionID, "chat-") || third.SessionID == first.SessionID {
+		t.Errorf("session ID after /reset = %q, want a new one", third.SessionID)
+	}
+	if first.ID == second.ID {
+		t.Errorf("task IDs both %q, want one per message", first.ID)
+	}
+	if got := messageText(&second.Message); got != "again" {
+		t.Errorf("second message = %q, want %q", got, "again")
+	}
+
+	for _, want := range []string{"Hello, world #1\n", "Hello, world #2\n", "Hello, world #3\n", "Started a new session."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "**") {
+		t.Errorf("output contains raw markdown:\n%s", out)
+	}
+	// The final result repeats the streamed text and must not be printed twice.
+	if n := strings.Count(out, "Hello, world #1"); n != 1 {
+		t.Errorf("reply printed %d times, want 1:\n%s", n, out)
+	}
+}
+
+func TestChat_NoStreamRendersMarkdown(t *testing.T) {
+	agent := &fakeChatAgent{}
+	out := runTestChat(t, agent, false, "hi\n")
+
+	if len(agent.methods) != 1 || agent.methods[0] != "tasks/send" {
+		t.Fatalf("methods = %v, want [tasks/send]", agent.methods)
+	}
+	for _, want := range []string{"Summary\n", "• first item\n", "    code line\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "```") || strings.Contains(out, "# Summary") {
+		t.Errorf("output contains raw markdown:\n%s", out)
+	}
+}
+
+func TestChat_ReportsErrorsAndContinues(t *testing.T) {
+	calls := 0
+	agent := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(a2a.NewErrorResponse("1", a2a.ErrCodeInternal, "model unavailable")) //nolint:errcheck
+			return
+		}
+		task := a2a.Task{Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: &a2a.Message{
+			Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("recovered")},
+		}}}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(a2a.NewResponse("2", task)) //nolint:errcheck
+	})
+	out := runTestChat(t, agent, false, "one\ntwo\n")
+
+	if !strings.Contains(out, "error: A2A error -32603: model unavailable") {
+		t.Errorf("output missing error:\n%s", out)
+	}
+	if !strings.Contains(out, "recovered\n") {
+		t.Errorf("output missing second reply:\n%s", out)
+	}
+}
+
+func TestDefaultChatURL(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AGENT_URL", "")
+	if got := defaultChatURL(dir); got != "http://localhost:8080" {
+		t.Errorf("no port file: got %q", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, ".forge-output"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, runtime.PortFile), []byte("51234\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := defaultChatURL(dir); got != "http://localhost:51234" {
+		t.Errorf("with port file: got %q", got)
+	}
+
+	t.Setenv("AGENT_URL", "http://agent.internal:9000")
+	if got := defaultChatURL(dir); got != "http://agent.internal:9000" {
+		t.Errorf("with AGENT_URL: got %q", got)
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: "Completion prints a completion script for the given shell. Besides commands and flags, " +
+		"it completes channel adapters for forge channel add, registry skills for forge skills add, " +
+		"and tool names for forge tool describe.\n\n" +
+		"To load completions in the current shell:\n\n" +
+		"  bash:       source <(forge completion bash)\n" +
+		"  zsh:        source <(forge completion zsh)\n" +
+		"  fish:       forge completion fish | source\n" +
+		"  powershell: forge completion powershell | Out-String | Invoke-Expression",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	RunE:      runCompletion,
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	root, out := cmd.Root(), cmd.OutOrStdout()
+	var err error
+	switch args[0] {
+	case "bash":
+		err = root.GenBashCompletionV2(out, true)
+	case "zsh":
+		err = root.GenZshCompletion(out)
+	case "fish":
+		err = root.GenFishCompletion(out, true)
+	case "powershell":
+		err = root.GenPowerShellCompletionWithDesc(out)
+	default:
+		return fmt.Errorf("unsupported shell: %s (supported: bash, zsh, fish, powershell)", args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("generating %s completion: %w", args[0], err)
+	}
+	return nil
+}
+
+// completeSkillNames completes the first argument with the names of the
+// skills in the registry forge skills add would use.
+func completeSkillNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	skills, err := skillRegistry(loadProjectConfig()).LoadIndex()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var names []string
+	for _, s := range skills {
+		if strings.HasPrefix(s.Name, toComplete) {
+			names = append(names, s.Name+"\t"+s.Description)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeToolNames completes the first argument with builtin tool names.
+func completeToolNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	reg := tools.NewRegistry()
+	if err := builtins.RegisterAll(reg); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var names []string
+	for _, name := range reg.List() {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name+"\t"+reg.Get(name).Description())
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRunCompletion_AllShells(t *testing.T) {
+	for _, shell := range completionCmd.ValidArgs {
+		var out bytes.Buffer
+		completionCmd.SetOut(&out)
+		if err := runCompletion(completionCmd, []string{shell}); err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		if !strings.Contains(out.String(), "forge") {
+			t.Errorf("%s completion script does not mention forge:\n%.200s", shell, out.String())
+		}
+	}
+	completionCmd.SetOut(nil)
+
+	if err := runCompletion(completionCmd, []string{"tcsh"}); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+func TestCompleteSkillNames(t *testing.T) {
+	t.Setenv("FORGE_SKILL_REGISTRY", "")
+	names, directive := completeSkillNames(skillsAddCmd, nil, "git")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+	found := slices.ContainsFunc(names, func(n string) bool { return strings.HasPrefix(n, "github\t") })
+	if !found {
+		t.Errorf("github missing from completions %v", names)
+	}
+	for _, n := range names {
+		if !strings.HasPrefix(n, "git") {
+			t.Errorf("completion %q does not match prefix", n)
+		}
+	}
+
+	if names, _ := completeSkillNames(skillsAddCmd, []string{"github"}, ""); len(names) != 0 {
+		t.Errorf("completions after the first argument = %v", names)
+	}
+}
+
+func TestCompleteToolNames(t *testing.T) {
+	names, _ := completeToolNames(toolDescribeCmd, nil, "json_")
+	if len(names) == 0 {
+		t.Fatal("no completions for json_")
+	}
+	for _, n := range names {
+		if !strings.HasPrefix(n, "json_") {
+			t.Errorf("completion %q does not match prefix", n)
+		}
+	}
+}
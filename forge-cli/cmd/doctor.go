@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-cli/container"
+	"github.com/initializ/forge/forge-cli/runtime"
+	cliskills "github.com/initializ/forge/forge-cli/skills"
+	clitools "github.com/initializ/forge/forge-cli/tools"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	coreskills "github.com/initializ/forge/forge-core/skills"
+	"github.com/initializ/forge/forge-core/types"
+	"github.com/initializ/forge/forge-core/validate"
+	"github.com/spf13/cobra"
+)
+
+var doctorEnvFile string
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment and config for common problems",
+	Long: "Doctor checks that forge.yaml validates, that the API key for the configured model " +
+		"provider is set, that the environment variables and binaries required by skills and " +
+		"cli_execute are available, and that a container builder is installed.\n\n" +
+		"Each check is reported as OK, WARN, or FAIL. The command exits non-zero if any check fails.",
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().StringVar(&doctorEnvFile, "env", ".env", "path to .env file")
+}
+
+// Doctor check statuses.
+const (
+	doctorOK   = "OK"
+	doctorWarn = "WARN"
+	doctorFail = "FAIL"
+)
+
+// doctorCheck is the outcome of one diagnostic.
+type doctorCheck struct {
+	Status string
+	Name   string
+	Detail string
+}
+
+// detectBuilder finds a container builder; tests replace it.
+var detectBuilder = container.Detect
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfgPath := cfgFile
+	if !filepath.IsAbs(cfgPath) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w", err)
+		}
+		cfgPath = filepath.Join(wd, cfgPath)
+	}
+	workDir := filepath.Dir(cfgPath)
+
+	envPath := doctorEnvFile
+	if !filepath.IsAbs(envPath) {
+		envPath = filepath.Join(workDir, envPath)
+	}
+	dotEnv, err := runtime.LoadEnvFile(envPath)
+	if err != nil {
+		return fmt.Errorf("loading env file: %w", err)
+	}
+
+	checks := doctorChecks(cfgPath, envFromOS(), dotEnv)
+	return reportDoctor(cmd.OutOrStdout(), checks)
+}
+
+// doctorChecks runs every diagnostic. Checks that need the config are
+// skipped when forge.yaml cannot be loaded.
+func doctorChecks(cfgPath string, osEnv, dotEnv map[string]string) []doctorCheck {
+	cfg, checks := checkConfig(cfgPath)
+	if cfg != nil {
+		env := make(map[string]string, len(osEnv)+len(dotEnv))
+		for k, v := range dotEnv {
+			env[k] = v
+		}
+		for k, v := range osEnv {
+			env[k] = v
+		}
+		checks = append(checks, checkProvider(cfg, env)...)
+		checks = append(checks, checkSkills(cfg, filepath.Dir(cfgPath), osEnv, dotEnv)...)
+		checks = append(checks, checkCLIExecute(cfg)...)
+	}
+	return append(checks, checkContainerBuilder())
+}
+
+// reportDoctor prints checks as a table and fails if any check failed.
+func reportDoctor(w io.Writer, checks []doctorCheck) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "STATUS\tCHECK\tDETAIL\n")
+	failed, warned := 0, 0
+	for _, c := range checks {
+		switch c.Status {
+		case doctorFail:
+			failed++
+		case doctorWarn:
+			warned++
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Status, c.Name, c.Detail)
+	}
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintf(w, "\n%d check(s): %d failed, %d warning(s)\n", len(checks), failed, warned)
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// checkConfig loads and validates forge.yaml. The config is returned when
+// it loads, even if validation fails, so the remaining checks can run.
+func checkConfig(cfgPath string) (*types.ForgeConfig, []doctorCheck) {
+	cfg, err := config.LoadForgeConfig(cfgPath)
+	if err != nil {
+		return nil, []doctorCheck{{doctorFail, "config", err.Error()}}
+	}
+
+	result := validate.ValidateForgeConfig(cfg)
+	var checks []doctorCheck
+	for _, e := range result.Errors {
+		checks = append(checks, doctorCheck{doctorFail, "config", e})
+	}
+	for _, w := range result.Warnings {
+		checks = append(checks, doctorCheck{doctorWarn, "config", w})
+	}
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{doctorOK, "config", filepath.Base(cfgPath) + " is valid"})
+	}
+	return cfg, checks
+}
+
+// providerKeyEnv names the API key variable of each provider that needs one.
+var providerKeyEnv = map[string]string{
+	"openai":    "OPENAI_API_KEY",
+	"anthropic": "ANTHROPIC_API_KEY",
+	"gemini":    "GEMINI_API_KEY",
+}
+
+// checkProvider checks that the model provider and each fallback can be
+// reached with the credentials in env, resolved the same way forge run does.
+func checkProvider(cfg *types.ForgeConfig, env map[string]string) []doctorCheck {
+	mc := coreruntime.ResolveModelConfig(cfg, env, "")
+	if mc == nil {
+		return []doctorCheck{{doctorWarn, "model provider", "no provider configured; forge run will use a stub executor"}}
+	}
+
+	checks := []doctorCheck{checkModelCredentials("model provider", mc)}
+	for i := range mc.Fallbacks {
+		checks = append(checks, checkModelCredentials("model fallback", &mc.Fallbacks[i]))
+	}
+	return checks
+}
+
+func checkModelCredentials(name string, mc *coreruntime.ModelConfig) doctorCheck {
+	label := mc.Provider + " (" + mc.Client.Model + ")"
+	if key, ok := providerKeyEnv[mc.Provider]; ok && mc.Client.APIKey == "" {
+		return doctorCheck{doctorFail, name, fmt.Sprintf("%s: %s is not set", label, key)}
+	}
+	if mc.Provider == "bedrock" && mc.Client.Region == "" {
+		return doctorCheck{doctorFail, name, label + ": AWS_REGION is not set"}
+	}
+	return doctorCheck{doctorOK, name, label}
+}
+
+// checkSkills checks the environment variables and binaries required by
+// the skills file, if there is one.
+func checkSkills(cfg *types.ForgeConfig, workDir string, osEnv, dotEnv map[string]string) []doctorCheck {
+	skillsPath := "skills.md"
+	if cfg.Skills.Path != "" {
+		skillsPath = cfg.Skills.Path
+	}
+	if !filepath.IsAbs(skillsPath) {
+		skillsPath = filepath.Join(workDir, skillsPath)
+	}
+	if _, err := os.Stat(skillsPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	entries, _, err := cliskills.ParseFileWithMetadata(skillsPath)
+	if err != nil {
+		return []doctorCheck{{doctorFail, "skills", fmt.Sprintf("parsing %s: %v", filepath.Base(skillsPath), err)}}
+	}
+	reqs := coreskills.AggregateRequirements(entries)
+
+	checks := checkSkillEnv(reqs, osEnv, dotEnv)
+	checks = append(checks, checkBinaries("skill binaries", reqs.Bins)...)
+	return checks
+}
+
+// checkSkillEnv reports each missing skill environment variable: FAIL when
+// required, WARN when optional.
+func checkSkillEnv(reqs *coreskills.AggregatedRequirements, osEnv, dotEnv map[string]string) []doctorCheck {
+	if len(reqs.EnvRequired) == 0 && len(reqs.EnvOneOf) == 0 && len(reqs.EnvOptional) == 0 {
+		return nil
+	}
+	diags := coreskills.NewEnvResolver(osEnv, dotEnv, nil).Resolve(reqs)
+	if len(diags) == 0 {
+		return []doctorCheck{{doctorOK, "skill env", "all environment requirements satisfied"}}
+	}
+	var checks []doctorCheck
+	for _, d := range diags {
+		checks = append(checks, doctorCheck{diagnosticStatus(d), "skill env", d.Message})
+	}
+	return checks
+}
+
+// checkCLIExecute checks the binaries allowed for cli_execute in forge.yaml.
+func checkCLIExecute(cfg *types.ForgeConfig) []doctorCheck {
+	for _, t := range cfg.Tools {
+		if t.Name == "cli_execute" && t.Config != nil {
+			return checkBinaries("cli_execute", clitools.ParseCLIExecuteConfig(t.Config).AllowedBinaries)
+		}
+	}
+	return nil
+}
+
+// checkBinaries reports whether each binary is on PATH. Missing binaries
+// are warnings: the agent runs, but calls that need them fail.
+func checkBinaries(name string, bins []string) []doctorCheck {
+	if len(bins) == 0 {
+		return nil
+	}
+	diags := coreskills.BinDiagnostics(bins)
+	if len(diags) == 0 {
+		return []doctorCheck{{doctorOK, name, "found " + strings.Join(bins, ", ")}}
+	}
+	var checks []doctorCheck
+	for _, d := range diags {
+		checks = append(checks, doctorCheck{diagnosticStatus(d), name, d.Message})
+	}
+	return checks
+}
+
+func diagnosticStatus(d coreskills.ValidationDiagnostic) string {
+	switch d.Level {
+	case "error":
+		return doctorFail
+	case "warning":
+		return doctorWarn
+	}
+	return doctorOK
+}
+
+// checkContainerBuilder looks for docker, podman, or buildah, which forge
+// package needs. Without one, forge build and forge run still work.
+func checkContainerBuilder() doctorCheck {
+	b := detectBuilder()
+	if b == nil {
+		return doctorCheck{doctorWarn, "container builder", "docker, podman, or buildah not found; forge package will not work"}
+	}
+	return doctorCheck{doctorOK, "container builder", b.Name()}
+}
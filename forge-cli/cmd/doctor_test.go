@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-cli/container"
+	coreskills "github.com/initializ/forge/forge-core/skills"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// fakeBinDir puts executables with the given names on an otherwise empty
+// PATH.
+func fakeBinDir(t *testing.T, names ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+}
+
+func findCheck(checks []doctorCheck, name string) []doctorCheck {
+	var found []doctorCheck
+	for _, c := range checks {
+		if c.Name == name {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+func TestCheckBinaries(t *testing.T) {
+	fakeBinDir(t, "curl", "jq")
+
+	checks := checkBinaries("cli_execute", []string{"curl", "jq"})
+	if len(checks) != 1 || checks[0].Status != doctorOK {
+		t.Errorf("all present: checks = %+v, want one OK", checks)
+	}
+
+	checks = checkBinaries("cli_execute", []string{"curl", "gh", "kubectl"})
+	if len(checks) != 2 {
+		t.Fatalf("checks = %+v, want one per missing binary", checks)
+	}
+	for i, bin := range []string{"gh", "kubectl"} {
+		if checks[i].Status != doctorWarn || !strings.Contains(checks[i].Detail, bin) {
+			t.Errorf("checks[%d] = %+v, want WARN for %s", i, checks[i], bin)
+		}
+	}
+
+	if checks := checkBinaries("cli_execute", nil); checks != nil {
+		t.Errorf("no binaries: checks = %+v, want none", checks)
+	}
+}
+
+func TestCheckCLIExecute(t *testing.T) {
+	fakeBinDir(t, "curl")
+	cfg := &types.ForgeConfig{Tools: []types.ToolRef{{
+		Name:   "cli_execute",
+		Config: map[string]any{"allowed_binaries": []any{"curl", "terraform"}},
+	}}}
+
+	checks := checkCLIExecute(cfg)
+	if len(checks) != 1 || checks[0].Status != doctorWarn || !strings.Contains(checks[0].Detail, "terraform") {
+		t.Errorf("checks = %+v, want WARN for terraform", checks)
+	}
+}
+
+func TestCheckSkillEnv(t *testing.T) {
+	reqs := &coreskills.AggregatedRequirements{
+		EnvRequired: []string{"GITHUB_TOKEN", "SET_IN_DOTENV"},
+		EnvOneOf:    [][]string{{"OPENAI_API_KEY", "ANTHROPIC_API_KEY"}},
+		EnvOptional: []string{"FIRECRAWL_API_KEY"},
+	}
+	osEnv := map[string]string{"ANTHROPIC_API_KEY": "sk"}
+	dotEnv := map[string]string{"SET_IN_DOTENV": "x"}
+
+	checks := checkSkillEnv(reqs, osEnv, dotEnv)
+	if len(checks) != 2 {
+		t.Fatalf("checks = %+v, want 2", checks)
+	}
+	if checks[0].Status != doctorFail || !strings.Contains(checks[0].Detail, "GITHUB_TOKEN") {
+		t.Errorf("checks[0] = %+v, want FAIL for GITHUB_TOKEN", checks[0])
+	}
+	if checks[1].Status != doctorWarn || !strings.Contains(checks[1].Detail, "FIRECRAWL_API_KEY") {
+		t.Errorf("checks[1] = %+v, want WARN for FIRECRAWL_API_KEY", checks[1])
+	}
+
+	osEnv["GITHUB_TOKEN"] = "ghp"
+	osEnv["FIRECRAWL_API_KEY"] = "fc"
+	checks = checkSkillEnv(reqs, osEnv, dotEnv)
+	if len(checks) != 1 || checks[0].Status != doctorOK {
+		t.Errorf("all set: checks = %+v, want one OK", checks)
+	}
+}
+
+func TestCheckProvider(t *testing.T) {
+	cfg := &types.ForgeConfig{Model: types.ModelRef{
+		Provider:  "anthropic",
+		Name:      "claude-sonnet-4-20250514",
+		Fallbacks: []types.ModelFallbackRef{{Provider: "openai", Name: "gpt-4o"}},
+	}}
+
+	checks := checkProvider(cfg, map[string]string{"ANTHROPIC_API_KEY": "sk-ant"})
+	if len(checks) != 2 {
+		t.Fatalf("checks = %+v, want primary and fallback", checks)
+	}
+	if checks[0].Status != doctorOK {
+		t.Errorf("primary = %+v, want OK", checks[0])
+	}
+	if checks[1].Status != doctorFail || !strings.Contains(checks[1].Detail, "OPENAI_API_KEY is not set") {
+		t.Errorf("fallback = %+v, want FAIL for OPENAI_API_KEY", checks[1])
+	}
+
+	checks = checkProvider(&types.ForgeConfig{}, map[string]string{})
+	if len(checks) != 1 || checks[0].Status != doctorWarn {
+		t.Errorf("no provider: checks = %+v, want WARN", checks)
+	}
+}
+
+func TestDoctorChecks(t *testing.T) {
+	fakeBinDir(t, "jq")
+	orig := detectBuilder
+	detectBuilder = func() container.Builder { return nil }
+	defer func() { detectBuilder = orig }()
+
+	dir := t.TempDir()
+	cfgPath := writeTestForgeYAML(t, dir, `
+agent_id: test-agent
+version: 0.1.0
+framework: custom
+entrypoint: python agent.py
+model:
+  provider: openai
+  name: gpt-4o
+`)
+	skills := `---
+name: gh
+metadata:
+  forge:
+    requires:
+      bins:
+        - gh
+        - jq
+      env:
+        required:
+          - GITHUB_TOKEN
+---
+## Tool: gh
+Runs gh.
+`
+	if err := os.WriteFile(filepath.Join(dir, "skills.md"), []byte(skills), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := doctorChecks(cfgPath, map[string]string{}, map[string]string{"OPENAI_API_KEY": "sk", "GITHUB_TOKEN": "ghp"})
+
+	if c := findCheck(checks, "config"); len(c) != 1 || c[0].Status != doctorOK {
+		t.Errorf("config = %+v, want OK", c)
+	}
+	if c := findCheck(checks, "model provider"); len(c) != 1 || c[0].Status != doctorOK {
+		t.Errorf("model provider = %+v, want OK", c)
+	}
+	if c := findCheck(checks, "skill env"); len(c) != 1 || c[0].Status != doctorOK {
+		t.Errorf("skill env = %+v, want OK", c)
+	}
+	if c := findCheck(checks, "skill binaries"); len(c) != 1 || c[0].Status != doctorWarn || !strings.Contains(c[0].Detail, `"gh"`) {
+		t.Errorf("skill binaries = %+v, want WARN for gh", c)
+	}
+	if c := findCheck(checks, "container builder"); len(c) != 1 || c[0].Status != doctorWarn {
+		t.Errorf("container builder = %+v, want WARN", c)
+	}
+
+	var out bytes.Buffer
+	if err := reportDoctor(&out, checks); err != nil {
+		t.Errorf("reportDoctor() error = %v, want nil with only warnings", err)
+	}
+	if !strings.Contains(out.String(), "STATUS") || !strings.Contains(out.String(), "0 failed, 2 warning(s)") {
+		t.Errorf("report:\n%s", out.String())
+	}
+}
+
+func TestDoctorChecks_MissingConfig(t *testing.T) {
+	orig := detectBuilder
+	detectBuilder = func() container.Builder { return &container.DockerBuilder{} }
+	defer func() { detectBuilder = orig }()
+
+	checks := doctorChecks(filepath.Join(t.TempDir(), "forge.yaml"), nil, nil)
+	if len(checks) != 2 || checks[0].Status != doctorFail || checks[1].Detail != "docker" {
+		t.Fatalf("checks = %+v, want config FAIL and the container builder", checks)
+	}
+
+	var out bytes.Buffer
+	if err := reportDoctor(&out, checks); err == nil {
+		t.Error("reportDoctor() = nil, want error when a check fails")
+	}
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-cli/runtime"
+	skillreg "github.com/initializ/forge/forge-core/registry"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var egressSyncDryRun bool
+
+var egressCmd = &cobra.Command{
+	Use:   "egress",
+	Short: "Manage egress configuration",
+}
+
+var egressSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Reconcile egress.allowed_domains with the current provider, channels, tools, and skills",
+	Long: "Sync recomputes the egress domains the agent needs from its model provider, channels, " +
+		"builtin tools, and vendored registry skills, adds any missing ones to egress.allowed_domains " +
+		"in forge.yaml, and reports allowed domains nothing requires. Extra domains are never removed.",
+	RunE: runEgressSync,
+}
+
+func init() {
+	egressSyncCmd.Flags().BoolVar(&egressSyncDryRun, "dry-run", false, "report changes without writing forge.yaml")
+	egressCheckCmd.Flags().DurationVar(&egressCheckTimeout, "timeout", 5*time.Second, "timeout for each domain probe")
+	egressCheckCmd.Flags().BoolVar(&egressCheckJSON, "json", false, "print URL checks as JSON")
+	egressCmd.AddCommand(egressSyncCmd)
+	egressCmd.AddCommand(egressCheckCmd)
+}
+
+// egressSyncResult describes the outcome of an egress sync.
+type egressSyncResult struct {
+	Added []string // derived domains that were missing from allowed_domains
+	Extra []string // allowed domains that nothing in the project requires
+}
+
+func runEgressSync(cmd *cobra.Command, args []string) error {
+	cfgPath := cfgFile
+	if !filepath.IsAbs(cfgPath) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w", err)
+		}
+		cfgPath = filepath.Join(wd, cfgPath)
+	}
+
+	result, err := syncEgress(cfgPath, egressSyncDryRun)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Added) == 0 && len(result.Extra) == 0 {
+		fmt.Println("Egress is in sync.")
+		return nil
+	}
+	if len(result.Added) > 0 {
+		verb := "Added"
+		if egressSyncDryRun {
+			verb = "Would add"
+		}
+		fmt.Printf("%s %d domain(s) to egress.allowed_domains:\n", verb, len(result.Added))
+		for _, d := range result.Added {
+			fmt.Printf("  + %s\n", d)
+		}
+	}
+	if len(result.Extra) > 0 {
+		fmt.Printf("%d allowed domain(s) not required by the provider, channels, tools, or skills:\n", len(result.Extra))
+		for _, d := range result.Extra {
+			fmt.Printf("  ? %s\n", d)
+		}
+	}
+	return nil
+}
+
+// syncEgress reconciles the allowed_domains in the forge.yaml at cfgPath with
+// the domains derived from the project. It is idempotent.
+func syncEgress(cfgPath string, dryRun bool) (*egressSyncResult, error) {
+	cfg, err := config.LoadForgeConfig(cfgPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	workDir := filepath.Dir(cfgPath)
+
+	envVars, err := runtime.LoadEnvFile(filepath.Join(workDir, ".env"))
+	if err != nil {
+		return nil, fmt.Errorf("loading env file: %w", err)
+	}
+
+	derived := deriveEgressDomains(egressOptionsFromConfig(cfg, envVars), vendoredRegistrySkills(skillRegistry(cfg), workDir))
+	capDomains := security.ResolveCapabilities(cfg.Egress.Capabilities)
+	missing, extra := diffEgressDomains(cfg.Egress.AllowedDomains, capDomains, derived)
+
+	result := &egressSyncResult{Added: missing, Extra: extra}
+	if len(missing) > 0 && !dryRun {
+		if _, err := addAllowedDomainsToForgeYAML(cfgPath, missing); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// egressOptionsFromConfig adapts a ForgeConfig to the initOptions consumed by
+// deriveEgressDomains.
+func egressOptionsFromConfig(cfg *types.ForgeConfig, envVars map[string]string) *initOptions {
+	opts := &initOptions{
+		ModelProvider: cfg.Model.Provider,
+		Channels:      cfg.Channels,
+		EnvVars:       envVars,
+	}
+	for _, t := range cfg.Tools {
+		opts.BuiltinTools = append(opts.BuiltinTools, t.Name)
+	}
+	return opts
+}
+
+// vendoredRegistrySkills returns registry entries for skills vendored into
+// the project's skills/ directory.
+func vendoredRegistrySkills(reg skillreg.Source, workDir string) []skillreg.SkillInfo {
+	entries, err := os.ReadDir(filepath.Join(workDir, "skills"))
+	if err != nil {
+		return nil
+	}
+	var infos []skillreg.SkillInfo
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".md")
+		if e.IsDir() || !ok {
+			continue
+		}
+		if info := reg.GetSkillByName(name); info != nil {
+			infos = append(infos, *info)
+		}
+	}
+	return infos
+}
+
+// diffEgressDomains compares allowed domains against derived requirements.
+// Domains granted by capability bundles count as allowed. It returns derived
+// domains that are missing and allowed domains that nothing requires, sorted.
+func diffEgressDomains(allowed, capDomains, derived []string) (missing, extra []string) {
+	have := make(map[string]bool, len(allowed)+len(capDomains))
+	for _, d := range allowed {
+		have[d] = true
+	}
+	for _, d := range capDomains {
+		have[d] = true
+	}
+	need := make(map[string]bool, len(derived))
+	for _, d := range derived {
+		need[d] = true
+		if !have[d] {
+			missing = append(missing, d)
+		}
+	}
+	for _, d := range allowed {
+		if !need[d] {
+			extra = append(extra, d)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return missing, extra
+}
+
+// addAllowedDomainsToForgeYAML appends the domains not already in
+// egress.allowed_domains and returns those it added. forge.yaml is left
+// untouched when there are none.
+func addAllowedDomainsToForgeYAML(path string, domains []string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading forge.yaml: %w", err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing forge.yaml: %w", err)
+	}
+
+	egressMap, ok := doc["egress"].(map[string]any)
+	if !ok {
+		egressMap = map[string]any{}
+	}
+	added := mergeAllowedDomains(egressMap, domains)
+	if len(added) == 0 {
+		return nil, nil
+	}
+	doc["egress"] = egressMap
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling forge.yaml: %w", err)
+	}
+
+	return added, os.WriteFile(path, out, 0644)
+}
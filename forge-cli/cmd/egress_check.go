@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/config"
+	cliskills "github.com/initializ/forge/forge-cli/skills"
+	"github.com/initializ/forge/forge-core/security"
+	coreskills "github.com/initializ/forge/forge-core/skills"
+	"github.com/spf13/cobra"
+)
+
+var (
+	egressCheckTimeout time.Duration
+	egressCheckJSON    bool
+)
+
+var egressCheckCmd = &cobra.Command{
+	Use:   "check [url...]",
+	Short: "Check URLs against the egress policy, or probe whether allowed domains are reachable",
+	Long: "Check resolves the egress allowlist from forge.yaml the same way forge build does.\n\n" +
+		"Given URLs, it reports whether the policy allows or denies each one and the rule that " +
+		"decided it, without sending any requests. It exits non-zero if any URL is denied.\n\n" +
+		"Without arguments, it sends a lightweight HEAD request to each allowed domain, honoring " +
+		"HTTP_PROXY, HTTPS_PROXY, and NO_PROXY. Any HTTP response counts as reachable; DNS, " +
+		"connection, and proxy failures are reported as blocked. The agent does not need to be running.",
+	RunE: runEgressCheck,
+}
+
+// egressDecision is the policy outcome for one URL.
+type egressDecision struct {
+	URL     string `json:"url"`
+	Host    string `json:"host"`
+	Allowed bool   `json:"allowed"`
+	Rule    string `json:"rule"`
+}
+
+// egressProber checks connectivity to a single domain.
+type egressProber func(ctx context.Context, domain string) error
+
+// egressProbeResult is the outcome of probing one domain.
+type egressProbeResult struct {
+	Domain string
+	Err    error // nil when the domain is reachable
+}
+
+func runEgressCheck(cmd *cobra.Command, args []string) error {
+	cfgPath := cfgFile
+	if !filepath.IsAbs(cfgPath) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w", err)
+		}
+		cfgPath = filepath.Join(wd, cfgPath)
+	}
+
+	resolved, err := resolveEgressAllowlist(cfgPath)
+	if err != nil {
+		return err
+	}
+	if resolved == nil {
+		fmt.Println("forge.yaml has no egress section, so egress is not restricted; every URL is allowed.")
+		return nil
+	}
+	if len(args) > 0 {
+		return reportEgressDecisions(resolved, args)
+	}
+
+	switch resolved.Mode {
+	case security.ModeDenyAll:
+		fmt.Println("Egress mode is deny-all; there are no domains to check.")
+		return nil
+	case security.ModeDevOpen:
+		fmt.Println("Egress mode is dev-open; all domains are allowed and there is no allowlist to check.")
+		return nil
+	}
+	if len(resolved.AllDomains) == 0 {
+		fmt.Println("The egress allowlist is empty; there are no domains to check.")
+		return nil
+	}
+
+	results := checkEgress(cmd.Context(), resolved.AllDomains, httpEgressProber(egressCheckTimeout))
+	blocked := 0
+	for _, r := range results {
+		if r.Err != nil {
+			blocked++
+			fmt.Printf("  blocked    %s: %v\n", r.Domain, r.Err)
+		} else {
+			fmt.Printf("  reachable  %s\n", r.Domain)
+		}
+	}
+	if blocked > 0 {
+		return fmt.Errorf("%d of %d egress domain(s) unreachable", blocked, len(results))
+	}
+	fmt.Printf("All %d egress domain(s) reachable.\n", len(results))
+	return nil
+}
+
+// reportEgressDecisions prints the policy decision for each URL, as a table
+// or as JSON, and fails if any is denied.
+func reportEgressDecisions(resolved *security.EgressConfig, urls []string) error {
+	decisions, err := decideEgress(resolved, urls)
+	if err != nil {
+		return err
+	}
+	denied := 0
+	for _, d := range decisions {
+		if !d.Allowed {
+			denied++
+		}
+	}
+
+	if egressCheckJSON {
+		out, err := json.MarshalIndent(map[string]any{
+			"profile": resolved.Profile,
+			"mode":    resolved.Mode,
+			"results": decisions,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding results: %w", err)
+		}
+		fmt.Println(string(out))
+	} else {
+		fmt.Printf("Egress policy: %s / %s\n", resolved.Profile, resolved.Mode)
+		for _, d := range decisions {
+			verdict := "ALLOW"
+			if !d.Allowed {
+				verdict = "DENY "
+			}
+			fmt.Printf("  %s  %s  (%s)\n", verdict, d.URL, d.Rule)
+		}
+	}
+	if denied > 0 {
+		return fmt.Errorf("%d of %d URL(s) denied by the egress policy", denied, len(decisions))
+	}
+	return nil
+}
+
+// decideEgress matches each URL's host against the resolved policy. Bare
+// hosts such as "api.example.com" are accepted as well as full URLs.
+func decideEgress(resolved *security.EgressConfig, urls []string) ([]egressDecision, error) {
+	enforcer := security.NewEgressEnforcer(resolved)
+	decisions := make([]egressDecision, 0, len(urls))
+	for _, raw := range urls {
+		target := raw
+		if !strings.Contains(target, "://") {
+			target = "https://" + target
+		}
+		u, err := url.Parse(target)
+		if err != nil || u.Hostname() == "" {
+			return nil, fmt.Errorf("invalid URL %q", raw)
+		}
+		allowed, rule := enforcer.Match(u.Hostname())
+		decisions = append(decisions, egressDecision{URL: raw, Host: u.Hostname(), Allowed: allowed, Rule: rule})
+	}
+	return decisions, nil
+}
+
+// resolveEgressAllowlist resolves the egress configuration in the forge.yaml
+// at cfgPath, plus the egress domains its skills declare, into the
+// allowlist forge build would compile and forge run enforces. It returns
+// nil when forge.yaml has no egress section.
+func resolveEgressAllowlist(cfgPath string) (*security.EgressConfig, error) {
+	cfg, err := config.LoadForgeConfig(cfgPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	var toolNames []string
+	for _, t := range cfg.Tools {
+		toolNames = append(toolNames, t.Name)
+	}
+
+	skillsPath := "skills.md"
+	if cfg.Skills.Path != "" {
+		skillsPath = cfg.Skills.Path
+	}
+	if !filepath.IsAbs(skillsPath) {
+		skillsPath = filepath.Join(filepath.Dir(cfgPath), skillsPath)
+	}
+	var reqs *coreskills.AggregatedRequirements
+	if _, err := os.Stat(skillsPath); err == nil {
+		entries, _, err := cliskills.ParseFileWithMetadata(skillsPath)
+		if err != nil {
+			return nil, fmt.Errorf("parsing skills file: %w", err)
+		}
+		reqs = coreskills.AggregateRequirements(entries)
+	}
+	return coreskills.ResolveEgress(cfg.Egress, toolNames, reqs)
+}
+
+// checkEgress probes each domain concurrently and returns results in the
+// order of domains. Wildcard entries ("*.example.com") probe the base domain.
+func checkEgress(ctx context.Context, domains []string, probe egressProber) []egressProbeResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	results := make([]egressProbeResult, len(domains))
+	var wg sync.WaitGroup
+	for i, d := range domains {
+		results[i].Domain = d
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			results[i].Err = probe(ctx, host)
+		}(i, strings.TrimPrefix(d, "*."))
+	}
+	wg.Wait()
+	return results
+}
+
+// httpEgressProber returns a prober that sends a HEAD request to
+// https://domain/ through the proxy configured in the environment. When no
+// proxy applies, it resolves the domain first so DNS failures are reported
+// distinctly from connection failures.
+func httpEgressProber(timeout time.Duration) egressProber {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			TLSHandshakeTimeout: timeout,
+		},
+		// The first response is enough to prove reachability
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	return func(ctx context.Context, domain string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://"+domain+"/", nil)
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+		if proxy, _ := http.ProxyFromEnvironment(req); proxy == nil {
+			lookupCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			if _, err := net.DefaultResolver.LookupHost(lookupCtx, req.URL.Hostname()); err != nil {
+				return fmt.Errorf("DNS lookup failed: %w", err)
+			}
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		return nil
+	}
+}
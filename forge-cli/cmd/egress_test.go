@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-core/security"
+)
+
+func TestSyncEgress_AddsMissingAndReportsExtra(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeTestForgeYAML(t, dir, `
+agent_id: test-agent
+version: 0.1.0
+framework: custom
+entrypoint: python agent.py
+model:
+  provider: anthropic
+  name: claude-sonnet-4-20250514
+channels:
+  - slack
+egress:
+  profile: standard
+  mode: allowlist
+  capabilities:
+    - slack
+  allowed_domains:
+    - legacy.internal.example.com
+`)
+
+	result, err := syncEgress(cfgPath, false)
+	if err != nil {
+		t.Fatalf("syncEgress() error: %v", err)
+	}
+
+	if len(result.Added) != 1 || result.Added[0] != "api.anthropic.com" {
+		t.Errorf("Added = %v, want [api.anthropic.com]", result.Added)
+	}
+	if len(result.Extra) != 1 || result.Extra[0] != "legacy.internal.example.com" {
+		t.Errorf("Extra = %v, want [legacy.internal.example.com]", result.Extra)
+	}
+
+	cfg, err := config.LoadForgeConfig(cfgPath)
+	if err != nil {
+		t.Fatalf("reloading config: %v", err)
+	}
+	got := strings.Join(cfg.Egress.AllowedDomains, ",")
+	if got != "legacy.internal.example.com,api.anthropic.com" {
+		t.Errorf("allowed_domains = %s", got)
+	}
+	if len(cfg.Egress.Capabilities) != 1 || cfg.Egress.Capabilities[0] != "slack" {
+		t.Errorf("capabilities not preserved: %v", cfg.Egress.Capabilities)
+	}
+
+	// Second run is a no-op for additions
+	again, err := syncEgress(cfgPath, false)
+	if err != nil {
+		t.Fatalf("second syncEgress() error: %v", err)
+	}
+	if len(again.Added) != 0 {
+		t.Errorf("second run Added = %v, want none", again.Added)
+	}
+	if len(again.Extra) != 1 {
+		t.Errorf("second run Extra = %v, want the unused domain", again.Extra)
+	}
+}
+
+func TestSyncEgress_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	content := `
+agent_id: test-agent
+version: 0.1.0
+framework: custom
+entrypoint: python agent.py
+model:
+  provider: openai
+  name: gpt-4o
+`
+	cfgPath := writeTestForgeYAML(t, dir, content)
+
+	result, err := syncEgress(cfgPath, true)
+	if err != nil {
+		t.Fatalf("syncEgress() error: %v", err)
+	}
+	if len(result.Added) != 1 || result.Added[0] != "api.openai.com" {
+		t.Errorf("Added = %v, want [api.openai.com]", result.Added)
+	}
+
+	data, _ := os.ReadFile(cfgPath)
+	if string(data) != content {
+		t.Error("dry run must not modify forge.yaml")
+	}
+}
+
+func TestDiffEgressDomains(t *testing.T) {
+	missing, extra := diffEgressDomains(
+		[]string{"b.com", "x.com"},
+		[]string{"slack.com"},
+		[]string{"a.com", "b.com", "slack.com"},
+	)
+	if strings.Join(missing, ",") != "a.com" {
+		t.Errorf("missing = %v", missing)
+	}
+	if strings.Join(extra, ",") != "x.com" {
+		t.Errorf("extra = %v", extra)
+	}
+}
+
+func TestResolveEgressAllowlist(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeTestForgeYAML(t, dir, `
+agent_id: test-agent
+version: 0.1.0
+framework: custom
+entrypoint: python agent.py
+egress:
+  mode: allowlist
+  capabilities:
+    - telegram
+  allowed_domains:
+    - api.example.com
+`)
+
+	resolved, err := resolveEgressAllowlist(cfgPath)
+	if err != nil {
+		t.Fatalf("resolveEgressAllowlist() error: %v", err)
+	}
+	got := strings.Join(resolved.AllDomains, ",")
+	if got != "api.example.com,api.telegram.org" {
+		t.Errorf("AllDomains = %s", got)
+	}
+}
+
+func TestResolveEgressAllowlist_SkillsAndNoSection(t *testing.T) {
+	dir := t.TempDir()
+	skillsMD := "---\nname: deploy\negress_domains:\n  - deploy.internal\n---\n## Tool: deploy_service\nDeploy a service.\n"
+	if err := os.WriteFile(filepath.Join(dir, "skills.md"), []byte(skillsMD), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := writeTestForgeYAML(t, dir, `
+agent_id: test-agent
+version: 0.1.0
+framework: custom
+entrypoint: python agent.py
+egress:
+  mode: allowlist
+`)
+	resolved, err := resolveEgressAllowlist(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(resolved.AllDomains, "deploy.internal") {
+		t.Errorf("AllDomains = %v, want the skill's deploy.internal", resolved.AllDomains)
+	}
+
+	// Without an egress section nothing is enforced, as in forge run
+	cfgPath = writeTestForgeYAML(t, dir, `
+agent_id: test-agent
+version: 0.1.0
+framework: custom
+entrypoint: python agent.py
+`)
+	resolved, err = resolveEgressAllowlist(cfgPath)
+	if err != nil || resolved != nil {
+		t.Errorf("resolveEgressAllowlist() = %+v, %v; want nil for no egress section", resolved, err)
+	}
+}
+
+func TestCheckEgress_ReportsPerDomainResults(t *testing.T) {
+	var mu sync.Mutex
+	var probed []string
+	probe := func(ctx context.Context, domain string) error {
+		mu.Lock()
+		probed = append(probed, domain)
+		mu.Unlock()
+		if domain == "blocked.example.com" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	results := checkEgress(context.Background(), []string{"api.example.com", "blocked.example.com", "*.cdn.example.com"}, probe)
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if results[0].Domain != "api.example.com" || results[0].Err != nil {
+		t.Errorf("results[0] = %+v, want reachable api.example.com", results[0])
+	}
+	if results[1].Domain != "blocked.example.com" || results[1].Err == nil {
+		t.Errorf("results[1] = %+v, want blocked", results[1])
+	}
+	if results[2].Domain != "*.cdn.example.com" || results[2].Err != nil {
+		t.Errorf("results[2] = %+v, want reachable wildcard entry", results[2])
+	}
+
+	sort.Strings(probed)
+	if strings.Join(probed, ",") != "api.example.com,blocked.example.com,cdn.example.com" {
+		t.Errorf("probed = %v, want wildcard probed by base domain", probed)
+	}
+}
+
+func TestDecideEgress(t *testing.T) {
+	resolved := &security.EgressConfig{
+		Profile:    security.ProfileStandard,
+		Mode:       security.ModeAllowlist,
+		AllDomains: []string{"api.openai.com", "*.slack.com"},
+	}
+	decisions, err := decideEgress(resolved, []string{
+		"https://api.openai.com/v1/chat/completions",
+		"hooks.slack.com",
+		"http://evil.example.com:8080/x",
+	})
+	if err != nil {
+		t.Fatalf("decideEgress() error: %v", err)
+	}
+	want := []egressDecision{
+		{URL: "https://api.openai.com/v1/chat/completions", Host: "api.openai.com", Allowed: true, Rule: "api.openai.com"},
+		{URL: "hooks.slack.com", Host: "hooks.slack.com", Allowed: true, Rule: "*.slack.com"},
+		{URL: "http://evil.example.com:8080/x", Host: "evil.example.com", Allowed: false, Rule: "default: not in allowlist"},
+	}
+	if !reflect.DeepEqual(decisions, want) {
+		t.Errorf("decisions = %+v, want %+v", decisions, want)
+	}
+
+	if _, err := decideEgress(resolved, []string{"https://"}); err == nil {
+		t.Error("expected an error for a URL without a host")
+	}
+	if err := reportEgressDecisions(resolved, []string{"api.openai.com"}); err != nil {
+		t.Errorf("reportEgressDecisions() allowed URL: %v", err)
+	}
+	if err := reportEgressDecisions(resolved, []string{"api.openai.com", "evil.example.com"}); err == nil {
+		t.Error("expected an error when a URL is denied")
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/initializ/forge/forge-cli/build"
 	"github.com/initializ/forge/forge-cli/config"
 	"github.com/initializ/forge/forge-core/agentspec"
 	"github.com/initializ/forge/forge-core/export"
+	"github.com/initializ/forge/forge-core/security"
 	"github.com/initializ/forge/forge-core/validate"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +22,8 @@ var (
 	exportIncludeSchemas bool
 	exportSimulateImport bool
 	exportDevMode        bool
+	exportSchemaBundle   bool
+	exportK8sNetpol      bool
 )
 
 var exportCmd = &cobra.Command{
@@ -34,6 +39,8 @@ func init() {
 	exportCmd.Flags().BoolVar(&exportIncludeSchemas, "include-schemas", false, "embed tool schemas inline from build output")
 	exportCmd.Flags().BoolVar(&exportSimulateImport, "simulate-import", false, "print simulated Command import result to stdout")
 	exportCmd.Flags().BoolVar(&exportDevMode, "dev", false, "include dev-category tools in export")
+	exportCmd.Flags().BoolVar(&exportSchemaBundle, "schema-bundle", false, "also write a consolidated JSON Schema of all tool inputs ({output}.tools.schema.json)")
+	exportCmd.Flags().BoolVar(&exportK8sNetpol, "k8s-netpol", false, "also write the Kubernetes NetworkPolicy for the egress config ({output}.netpol.yaml)")
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
@@ -47,7 +54,7 @@ func runExport(cmd *cobra.Command, args []string) error {
 		cfgPath = filepath.Join(wd, cfgPath)
 	}
 
-	// 2. Load config (needed for agent_id default filename)
+	// 2. Load config (checked against the built agent.json)
 	cfg, err := config.LoadForgeConfig(cfgPath)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
@@ -87,6 +94,9 @@ func runExport(cmd *cobra.Command, args []string) error {
 	if err := json.Unmarshal(agentData, &spec); err != nil {
 		return fmt.Errorf("parsing agent.json: %w", err)
 	}
+	if err := validate.CheckSpecIdentity(cfg, &spec); err != nil {
+		return err
+	}
 
 	// 6a. Export validation
 	exportVal := export.ValidateForExport(&spec, exportDevMode)
@@ -168,7 +178,7 @@ func runExport(cmd *cobra.Command, args []string) error {
 	// 13. Determine output filename
 	outFile := exportOutput
 	if outFile == "" {
-		outFile = fmt.Sprintf("%s-forge.json", cfg.AgentID)
+		outFile = fmt.Sprintf("%s-forge.json", spec.AgentID)
 	}
 
 	if err := os.WriteFile(outFile, exportData, 0644); err != nil {
@@ -176,6 +186,44 @@ func runExport(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Exported: %s\n", outFile)
+
+	// 14. Write consolidated tool schema bundle if requested
+	if exportSchemaBundle {
+		bundle, err := export.BuildToolSchemaBundle(&spec)
+		if err != nil {
+			return fmt.Errorf("building tool schema bundle: %w", err)
+		}
+		bundleData, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling tool schema bundle: %w", err)
+		}
+		bundleFile := strings.TrimSuffix(outFile, ".json") + ".tools.schema.json"
+		if err := os.WriteFile(bundleFile, append(bundleData, '\n'), 0644); err != nil {
+			return fmt.Errorf("writing tool schema bundle: %w", err)
+		}
+		fmt.Printf("Tool schemas: %s\n", bundleFile)
+	}
+
+	// 15. Write the NetworkPolicy forge build renders for the egress config if requested
+	if exportK8sNetpol {
+		if spec.EgressMode == "" {
+			return fmt.Errorf("--k8s-netpol needs an egress section in forge.yaml")
+		}
+		egressCfg := &security.EgressConfig{
+			Profile:    security.EgressProfile(spec.EgressProfile),
+			Mode:       security.EgressMode(spec.EgressMode),
+			AllDomains: allowlistDomains,
+		}
+		policy, err := build.RenderNetworkPolicy(spec.AgentID, egressCfg, cfg.Egress.CNI)
+		if err != nil {
+			return fmt.Errorf("building network policy: %w", err)
+		}
+		policyFile := strings.TrimSuffix(outFile, ".json") + ".netpol.yaml"
+		if err := os.WriteFile(policyFile, policy, 0644); err != nil {
+			return fmt.Errorf("writing network policy: %w", err)
+		}
+		fmt.Printf("Network policy: %s\n", policyFile)
+	}
 	return nil
 }
 
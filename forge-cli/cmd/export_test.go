@@ -96,6 +96,8 @@ tools:
 		exportIncludeSchemas = false
 		exportSimulateImport = false
 		exportDevMode = false
+		exportSchemaBundle = false
+		exportK8sNetpol = false
 	}
 
 	return dir, cleanup
@@ -253,6 +255,37 @@ func TestRunExport_IncludeSchemas(t *testing.T) {
 	}
 }
 
+func TestRunExport_SchemaBundle(t *testing.T) {
+	dir, cleanup := setupExportTest(t)
+	defer cleanup()
+
+	cfgFile = filepath.Join(dir, "forge.yaml")
+	outputDir = "."
+	exportOutput = filepath.Join(dir, "bundle.json")
+	exportSchemaBundle = true
+
+	if err := runExport(nil, nil); err != nil {
+		t.Fatalf("runExport() error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "bundle.tools.schema.json"))
+	if err != nil {
+		t.Fatalf("reading schema bundle: %v", err)
+	}
+
+	var bundle map[string]any
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatalf("parsing schema bundle: %v", err)
+	}
+	defs, _ := bundle["$defs"].(map[string]any)
+	if _, ok := defs["web-search"]; !ok {
+		t.Errorf("expected $defs to contain web-search, got %v", defs)
+	}
+	if id, _ := bundle["$id"].(string); !strings.Contains(id, "test-agent") {
+		t.Errorf("$id = %q, want agent id", id)
+	}
+}
+
 func TestRunExport_SimulateImport(t *testing.T) {
 	dir, cleanup := setupExportTest(t)
 	defer cleanup()
@@ -418,6 +451,8 @@ tools:
 		exportIncludeSchemas = false
 		exportSimulateImport = false
 		exportDevMode = false
+		exportSchemaBundle = false
+		exportK8sNetpol = false
 	}
 
 	return dir, cleanup
@@ -533,6 +568,52 @@ func TestRunExport_NetworkPolicyBlock(t *testing.T) {
 	}
 }
 
+func TestRunExport_K8sNetpol(t *testing.T) {
+	dir, cleanup := setupExportTest(t)
+	defer cleanup()
+
+	writeTestForgeYAML(t, dir, `
+agent_id: test-agent
+version: 0.1.0
+framework: custom
+entrypoint: python agent.py
+model:
+  provider: openai
+  name: gpt-4
+tools:
+  - name: web-search
+egress:
+  mode: allowlist
+  cni: cilium
+`)
+	cfgFile = filepath.Join(dir, "forge.yaml")
+	outputDir = "."
+	exportOutput = filepath.Join(dir, "agent.json")
+	exportK8sNetpol = true
+
+	compiled := filepath.Join(dir, ".forge-output", "compiled")
+	if err := os.MkdirAll(compiled, 0755); err != nil {
+		t.Fatal(err)
+	}
+	allowlist := `{"profile": "standard", "mode": "allowlist", "all_domains": ["api.openai.com"]}`
+	if err := os.WriteFile(filepath.Join(compiled, "egress_allowlist.json"), []byte(allowlist), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runExport(nil, nil); err != nil {
+		t.Fatalf("runExport() error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "agent.netpol.yaml"))
+	if err != nil {
+		t.Fatalf("reading network policy: %v", err)
+	}
+	for _, want := range []string{"kind: CiliumNetworkPolicy", "name: test-agent-network", `matchName: "api.openai.com"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("network policy missing %q:\n%s", want, data)
+		}
+	}
+}
+
 func TestRunExport_EnrichedMeta(t *testing.T) {
 	dir, cleanup := setupExportTest(t)
 	defer cleanup()
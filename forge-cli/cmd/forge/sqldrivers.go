@@ -0,0 +1,10 @@
+package main
+
+// Database drivers for the sql_query builtin tool, which picks one by the
+// scheme of FORGE_SQL_DSN. The sqlite driver also backs the task store set
+// by task_store.path.
+import (
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
@@ -17,6 +17,7 @@ import (
 	"github.com/initializ/forge/forge-cli/skills"
 	"github.com/initializ/forge/forge-cli/templates"
 	skillreg "github.com/initializ/forge/forge-core/registry"
+	coreskills "github.com/initializ/forge/forge-core/skills"
 	"github.com/initializ/forge/forge-core/tools/builtins"
 	"github.com/initializ/forge/forge-core/util"
 )
@@ -38,6 +39,7 @@ type initOptions struct {
 	NonInteractive bool   // skip auto-run in non-interactive mode
 	Force          bool   // overwrite existing directory
 	CustomModel    string // custom provider model name
+	CI             string // CI system to scaffold a workflow for ("github")
 }
 
 // toolEntry represents a tool parsed from a skills file.
@@ -103,6 +105,7 @@ func init() {
 	initCmd.Flags().StringSlice("skills", nil, "registry skills to include (e.g., github,weather)")
 	initCmd.Flags().String("api-key", "", "LLM provider API key")
 	initCmd.Flags().Bool("force", false, "overwrite existing directory")
+	initCmd.Flags().String("ci", "", "scaffold a CI workflow that validates, builds, and packages the agent (github)")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -131,6 +134,13 @@ func runInit(cmd *cobra.Command, args []string) error {
 	nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
 	opts.NonInteractive = nonInteractive
 	opts.Force, _ = cmd.Flags().GetBool("force")
+	opts.CI, _ = cmd.Flags().GetString("ci")
+
+	switch opts.CI {
+	case "", "github":
+	default:
+		return fmt.Errorf("invalid ci %q: must be github", opts.CI)
+	}
 
 	// TTY detection: require a terminal for interactive mode
 	if !nonInteractive && !term.IsTerminal(int(os.Stdout.Fd())) {
@@ -179,7 +189,7 @@ func collectInteractive(opts *initOptions) error {
 
 	// Load skill info for the skills step
 	var skillInfos []steps.SkillInfo
-	regSkills, err := skillreg.LoadIndex()
+	regSkills, err := skillRegistry(nil).LoadIndex()
 	if err == nil {
 		for _, s := range regSkills {
 			skillInfos = append(skillInfos, steps.SkillInfo{
@@ -371,7 +381,7 @@ func collectNonInteractive(opts *initOptions) error {
 
 	// Validate skill names and check requirements
 	if len(opts.Skills) > 0 {
-		regSkills, err := skillreg.LoadIndex()
+		regSkills, err := skillRegistry(nil).LoadIndex()
 		if err != nil {
 			fmt.Printf("Warning: could not load skill registry: %s\n", err)
 		} else {
@@ -409,7 +419,7 @@ func storeProviderEnvVar(opts *initOptions) {
 // checkSkillRequirements checks binary and env requirements for selected skills.
 func checkSkillRequirements(opts *initOptions) {
 	for _, skillName := range opts.Skills {
-		info := skillreg.GetSkillByName(skillName)
+		info := skillRegistry(nil).GetSkillByName(skillName)
 		if info == nil {
 			continue
 		}
@@ -417,7 +427,7 @@ func checkSkillRequirements(opts *initOptions) {
 		// Check required binaries
 		for _, bin := range info.RequiredBins {
 			if _, err := exec.LookPath(bin); err != nil {
-				fmt.Printf("  Warning: skill %q requires %q binary (not found in PATH)\n", skillName, bin)
+				fmt.Printf("  Warning: skill %q requires %q binary (not found in PATH); %s\n", skillName, bin, coreskills.InstallHint(bin))
 			}
 		}
 
@@ -457,7 +467,7 @@ func checkSkillRequirements(opts *initOptions) {
 func lookupSelectedSkills(skillNames []string) []skillreg.SkillInfo {
 	var result []skillreg.SkillInfo
 	for _, name := range skillNames {
-		info := skillreg.GetSkillByName(name)
+		info := skillRegistry(nil).GetSkillByName(name)
 		if info != nil {
 			result = append(result, *info)
 		}
@@ -536,7 +546,7 @@ func scaffold(opts *initOptions) error {
 
 	// Vendor selected registry skills
 	for _, skillName := range opts.Skills {
-		content, err := skillreg.LoadSkillFile(skillName)
+		content, err := skillRegistry(nil).LoadSkillFile(skillName)
 		if err != nil {
 			fmt.Printf("Warning: could not load skill file for %q: %s\n", skillName, err)
 			continue
@@ -547,8 +557,8 @@ func scaffold(opts *initOptions) error {
 		}
 
 		// Vendor script if the skill has one
-		if skillreg.HasSkillScript(skillName) {
-			scriptContent, sErr := skillreg.LoadSkillScript(skillName)
+		if skillRegistry(nil).HasSkillScript(skillName) {
+			scriptContent, sErr := skillRegistry(nil).LoadSkillScript(skillName)
 			if sErr == nil {
 				scriptDir := filepath.Join(dir, "skills", "scripts")
 				_ = os.MkdirAll(scriptDir, 0o755)
@@ -658,6 +668,13 @@ func getFileManifest(opts *initOptions) []fileToRender {
 		})
 	}
 
+	if opts.CI == "github" {
+		files = append(files, fileToRender{
+			TemplatePath: "ci/github.yml.tmpl",
+			OutputPath:   ".github/workflows/forge.yml",
+		})
+	}
+
 	return files
 }
 
@@ -708,7 +725,7 @@ func buildTemplateData(opts *initOptions) templateData {
 
 	// Build skill entries for templates
 	for _, skillName := range opts.Skills {
-		info := skillreg.GetSkillByName(skillName)
+		info := skillRegistry(nil).GetSkillByName(skillName)
 		if info != nil {
 			data.SkillEntries = append(data.SkillEntries, skillTmplData{
 				Name:        info.Name,
@@ -773,15 +790,17 @@ func buildEnvVars(opts *initOptions) []envVarEntry {
 
 	// Web search provider key if web_search selected
 	if containsStr(opts.BuiltinTools, "web_search") {
-		provider := opts.EnvVars["WEB_SEARCH_PROVIDER"]
-		if provider == "perplexity" {
+		switch opts.EnvVars["WEB_SEARCH_PROVIDER"] {
+		case "duckduckgo":
+			vars = append(vars, envVarEntry{Key: "WEB_SEARCH_PROVIDER", Value: "duckduckgo", Comment: "Web search provider (no API key needed)"})
+		case "perplexity":
 			val := opts.EnvVars["PERPLEXITY_API_KEY"]
 			if val == "" {
 				val = "your-perplexity-key-here"
 			}
 			vars = append(vars, envVarEntry{Key: "PERPLEXITY_API_KEY", Value: val, Comment: "Perplexity API key for web_search"})
 			vars = append(vars, envVarEntry{Key: "WEB_SEARCH_PROVIDER", Value: "perplexity", Comment: "Web search provider"})
-		} else {
+		default:
 			// Default to Tavily
 			val := opts.EnvVars["TAVILY_API_KEY"]
 			if val == "" {
@@ -802,6 +821,9 @@ func buildEnvVars(opts *initOptions) []envVarEntry {
 			vars = append(vars, envVarEntry{Key: "SLACK_APP_TOKEN", Value: appVal, Comment: "Slack app-level token (xapp-...)"})
 			botVal := opts.EnvVars["SLACK_BOT_TOKEN"]
 			vars = append(vars, envVarEntry{Key: "SLACK_BOT_TOKEN", Value: botVal, Comment: "Slack bot token (xoxb-...)"})
+		case "discord":
+			val := opts.EnvVars["DISCORD_BOT_TOKEN"]
+			vars = append(vars, envVarEntry{Key: "DISCORD_BOT_TOKEN", Value: val, Comment: "Discord bot token"})
 		}
 	}
 
@@ -811,7 +833,7 @@ func buildEnvVars(opts *initOptions) []envVarEntry {
 		written[v.Key] = true
 	}
 	for _, skillName := range opts.Skills {
-		info := skillreg.GetSkillByName(skillName)
+		info := skillRegistry(nil).GetSkillByName(skillName)
 		if info == nil {
 			continue
 		}
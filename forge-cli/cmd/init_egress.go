@@ -7,58 +7,27 @@ import (
 	"github.com/initializ/forge/forge-core/security"
 )
 
-// providerDomains maps model provider names to their API domains.
-var providerDomains = map[string]string{
-	"openai":    "api.openai.com",
-	"anthropic": "api.anthropic.com",
-	"gemini":    "generativelanguage.googleapis.com",
-	// ollama is local, no egress needed
-}
-
 // deriveEgressDomains computes the full set of egress domains needed based on
-// the provider, channels, builtin tools, and selected registry skills.
+// the provider, channels, builtin tools, and selected registry skills. The
+// provider, channel, and tool domains come from security's shared table.
 func deriveEgressDomains(opts *initOptions, skills []skillreg.SkillInfo) []string {
-	seen := make(map[string]bool)
-	var domains []string
-
-	add := func(d string) {
-		if d != "" && !seen[d] {
-			seen[d] = true
-			domains = append(domains, d)
-		}
-	}
-
-	// 1. Provider domain
-	if d, ok := providerDomains[opts.ModelProvider]; ok {
-		add(d)
-	}
-
-	// 2. Channel domains
-	for _, d := range security.ResolveCapabilities(opts.Channels) {
-		add(d)
+	domains := security.DeriveEgressDomains(security.EgressSource{
+		Provider: opts.ModelProvider,
+		Channels: opts.Channels,
+		Tools:    opts.BuiltinTools,
+		Env:      opts.EnvVars,
+	})
+
+	seen := make(map[string]bool, len(domains))
+	for _, d := range domains {
+		seen[d] = true
 	}
-
-	// 3. Tool domains (web_search filtered by provider)
-	for _, toolName := range opts.BuiltinTools {
-		if toolName == "web_search" || toolName == "web-search" {
-			provider := opts.EnvVars["WEB_SEARCH_PROVIDER"]
-			switch provider {
-			case "perplexity":
-				add("api.perplexity.ai")
-			default:
-				add("api.tavily.com")
-			}
-			continue
-		}
-		for _, d := range security.DefaultToolDomains[toolName] {
-			add(d)
-		}
-	}
-
-	// 4. Skill domains
 	for _, s := range skills {
 		for _, d := range s.EgressDomains {
-			add(d)
+			if d != "" && !seen[d] {
+				seen[d] = true
+				domains = append(domains, d)
+			}
 		}
 	}
 
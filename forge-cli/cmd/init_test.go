@@ -3,10 +3,12 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
 	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-core/security"
 )
 
 func TestParseSkillsFileHeadings(t *testing.T) {
@@ -492,6 +494,67 @@ func TestScaffold_GitignoreIncludesEnv(t *testing.T) {
 	}
 }
 
+func TestScaffold_GitHubWorkflow(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(origDir) }()
+
+	opts := &initOptions{
+		Name:           "ci-test",
+		AgentID:        "ci-test",
+		Framework:      "custom",
+		Language:       "python",
+		ModelProvider:  "openai",
+		CI:             "github",
+		EnvVars:        map[string]string{},
+		NonInteractive: true,
+	}
+
+	if err := scaffold(opts); err != nil {
+		t.Fatalf("scaffold error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join("ci-test", ".github", "workflows", "forge.yml"))
+	if err != nil {
+		t.Fatalf("reading workflow: %v", err)
+	}
+	wf := string(content)
+	for _, want := range []string{
+		"forge validate",
+		"forge build",
+		"forge package --skip-build --registry \"$REGISTRY\"",
+		"forge package --skip-build --push --registry \"$REGISTRY\"",
+		`REGISTRY=ghcr.io/${GITHUB_REPOSITORY_OWNER,,}`,
+		"if: github.event_name != 'pull_request'",
+	} {
+		if !strings.Contains(wf, want) {
+			t.Errorf("workflow missing %q:\n%s", want, wf)
+		}
+	}
+	// The image tag comes from forge package, never from parsing forge.yaml
+	for _, unwanted := range []string{"docker push", "awk"} {
+		if strings.Contains(wf, unwanted) {
+			t.Errorf("workflow should not use %q:\n%s", unwanted, wf)
+		}
+	}
+	// Pushing needs the registry login first
+	if strings.Index(wf, "docker/login-action") > strings.Index(wf, "--push") {
+		t.Errorf("workflow pushes before logging in:\n%s", wf)
+	}
+}
+
+func TestGetFileManifestNoCIByDefault(t *testing.T) {
+	opts := &initOptions{Framework: "custom", Language: "python"}
+	for _, f := range getFileManifest(opts) {
+		if strings.HasPrefix(f.OutputPath, ".github/") {
+			t.Errorf("unexpected CI file %s without --ci", f.OutputPath)
+		}
+	}
+}
+
 func TestDeriveEgressDomains(t *testing.T) {
 	opts := &initOptions{
 		ModelProvider: "openai",
@@ -511,6 +574,8 @@ func TestDeriveEgressDomains(t *testing.T) {
 		"api.tavily.com":  true,
 		"api.github.com":  true,
 		"github.com":      true,
+		// DuckDuckGo, web_search's keyless fallback
+		"html.duckduckgo.com": true,
 	}
 	for _, d := range domains {
 		if !expected[d] {
@@ -523,6 +588,30 @@ func TestDeriveEgressDomains(t *testing.T) {
 	}
 }
 
+func TestDeriveEgressDomains_SharedTable(t *testing.T) {
+	opts := &initOptions{
+		ModelProvider: "openai",
+		Channels:      []string{"slack"},
+		BuiltinTools:  []string{"web_search"},
+		EnvVars:       map[string]string{},
+	}
+	skillInfos := lookupSelectedSkills([]string{"github"})
+	got := deriveEgressDomains(opts, skillInfos)
+
+	want := security.DeriveEgressDomains(security.EgressSource{
+		Provider: "openai",
+		Channels: []string{"slack"},
+		Tools:    []string{"web_search"},
+	})
+	for _, s := range skillInfos {
+		want = append(want, s.EgressDomains...)
+	}
+	sort.Strings(want)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("deriveEgressDomains = %v, shared table gives %v", got, want)
+	}
+}
+
 func TestDeriveEgressDomains_Empty(t *testing.T) {
 	opts := &initOptions{
 		ModelProvider: "ollama",
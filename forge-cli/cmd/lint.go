@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/initializ/forge/forge-core/types"
+	"github.com/initializ/forge/forge-core/validate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintJSON   bool
+	lintStrict bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Lint forge.yaml with line numbers and suggested fixes",
+	Long: "Lint runs the same checks as forge validate on forge.yaml, plus checks for settings " +
+		"that do not work together, such as cli_execute without allowed_binaries. Each issue is " +
+		"reported with its line in the file and, where possible, a suggested fix.\n\n" +
+		"The command exits non-zero if there are errors, or any issues at all with --strict.",
+	Args: cobra.NoArgs,
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintJSON, "json", false, "output issues as JSON")
+	lintCmd.Flags().BoolVar(&lintStrict, "strict", false, "treat warnings as errors")
+}
+
+// lintReport is the --json output of forge lint.
+type lintReport struct {
+	File     string               `json:"file"`
+	Issues   []validate.LintIssue `json:"issues"`
+	Errors   int                  `json:"errors"`
+	Warnings int                  `json:"warnings"`
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	cfgPath := cfgFile
+	if !filepath.IsAbs(cfgPath) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w", err)
+		}
+		cfgPath = filepath.Join(wd, cfgPath)
+	}
+
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("reading forge config %s: %w", cfgPath, err)
+	}
+	cfg, err := types.ParseForgeConfig(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cfgFile, err)
+	}
+
+	report := lintReport{File: cfgFile, Issues: validate.LintForgeConfig(cfg, data)}
+	if report.Issues == nil {
+		report.Issues = []validate.LintIssue{}
+	}
+	for _, is := range report.Issues {
+		if is.Severity == validate.SeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if lintJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("encoding issues: %w", err)
+		}
+	} else {
+		printLintReport(out, report)
+	}
+
+	switch {
+	case report.Errors > 0:
+		return fmt.Errorf("lint failed: %d error(s), %d warning(s)", report.Errors, report.Warnings)
+	case lintStrict && report.Warnings > 0:
+		return fmt.Errorf("lint failed: %d warning(s) with --strict", report.Warnings)
+	}
+	return nil
+}
+
+// printLintReport prints one issue per line in file:line:column form, with
+// the suggested fix indented below it.
+func printLintReport(w io.Writer, report lintReport) {
+	for _, is := range report.Issues {
+		loc := report.File
+		if is.Line > 0 {
+			loc = fmt.Sprintf("%s:%d:%d", report.File, is.Line, is.Column)
+		}
+		fmt.Fprintf(w, "%s: %s: %s\n", loc, is.Severity, is.Message) //nolint:errcheck
+		if is.Suggestion != "" {
+			fmt.Fprintf(w, "    suggestion: %s\n", is.Suggestion) //nolint:errcheck
+		}
+	}
+	if len(report.Issues) == 0 {
+		fmt.Fprintf(w, "%s: no issues found\n", report.File) //nolint:errcheck
+		return
+	}
+	fmt.Fprintf(w, "\n%d error(s), %d warning(s)\n", report.Errors, report.Warnings) //nolint:errcheck
+}
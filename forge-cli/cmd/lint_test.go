@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func runTestLint(t *testing.T, content string, jsonOut, strictMode bool) (string, error) {
+	t.Helper()
+	cfgPath := writeTestForgeYAML(t, t.TempDir(), content)
+
+	oldCfg, oldJSON, oldStrict := cfgFile, lintJSON, lintStrict
+	cfgFile, lintJSON, lintStrict = cfgPath, jsonOut, strictMode
+	defer func() { cfgFile, lintJSON, lintStrict = oldCfg, oldJSON, oldStrict }()
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	err := runLint(cmd, nil)
+	return out.String(), err
+}
+
+func TestRunLint_Suggestions(t *testing.T) {
+	out, err := runTestLint(t, `agent_id: test-agent
+version: 0.1.0
+framework: langchian
+entrypoint: python agent.py
+tools:
+  - name: cli_execute
+`, false, false)
+	if err != nil {
+		t.Fatalf("warnings only: error = %v, want nil", err)
+	}
+	for _, want := range []string{
+		`:3:1: warning: unknown framework "langchian"`,
+		`    suggestion: did you mean "langchain"?`,
+		":6:5: warning: cli_execute has no allowed_binaries",
+		"0 error(s), 2 warning(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunLint_Strict(t *testing.T) {
+	cfg := `agent_id: test-agent
+version: 0.1.0
+entrypoint: python agent.py
+egress:
+  mode: allowlist
+`
+	if _, err := runTestLint(t, cfg, false, false); err != nil {
+		t.Errorf("warnings only: error = %v, want nil", err)
+	}
+	if _, err := runTestLint(t, cfg, false, true); err == nil {
+		t.Error("expected error for warnings with --strict")
+	}
+}
+
+func TestRunLint_JSON(t *testing.T) {
+	out, err := runTestLint(t, `agent_id: test-agent
+version: 0.1.0
+entrypoint: python agent.py
+egress:
+  profile: standrd
+`, true, false)
+	if err == nil {
+		t.Fatal("expected error for an unknown egress profile")
+	}
+
+	var report lintReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if report.Errors != 1 || len(report.Issues) != 1 {
+		t.Fatalf("report = %+v, want one error", report)
+	}
+	is := report.Issues[0]
+	if is.Path != "egress.profile" || is.Line != 5 || is.Suggestion != `did you mean "standard"?` {
+		t.Errorf("issue = %+v", is)
+	}
+}
+
+func TestRunLint_Clean(t *testing.T) {
+	out, err := runTestLint(t, `agent_id: test-agent
+version: 0.1.0
+entrypoint: python agent.py
+`, true, true)
+	if err != nil {
+		t.Fatalf("runLint() error: %v", err)
+	}
+	if !strings.Contains(out, `"issues": []`) {
+		t.Errorf("output = %s, want an empty issues list", out)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,8 +13,10 @@ import (
 	"github.com/initializ/forge/forge-cli/config"
 	"github.com/initializ/forge/forge-cli/container"
 	"github.com/initializ/forge/forge-cli/templates"
+	"github.com/initializ/forge/forge-core/agentspec"
 	"github.com/initializ/forge/forge-core/export"
 	"github.com/initializ/forge/forge-core/types"
+	"github.com/initializ/forge/forge-core/validate"
 	"github.com/spf13/cobra"
 )
 
@@ -117,8 +120,12 @@ func runPackage(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Compute image tag
-	imageTag := computeImageTag(cfg.AgentID, cfg.Version, reg)
+	// Compute image tag from the built spec, which must match forge.yaml
+	spec, err := loadBuiltSpec(outDir, cfg)
+	if err != nil {
+		return err
+	}
+	imageTag := computeImageTag(spec.AgentID, spec.Version, reg)
 
 	// Build args
 	buildArgs := map[string]string{}
@@ -162,8 +169,8 @@ func runPackage(cmd *cobra.Command, args []string) error {
 
 	// Write image manifest
 	manifest := &container.ImageManifest{
-		AgentID:  cfg.AgentID,
-		Version:  cfg.Version,
+		AgentID:  spec.AgentID,
+		Version:  spec.Version,
 		ImageTag: imageTag,
 		Builder:  builder.Name(),
 		Platform: platform,
@@ -252,7 +259,7 @@ func generateDockerCompose(path string, imageTag string, cfg *types.ForgeConfig,
 
 	var channels []channelComposeData
 	for _, ch := range cfg.Channels {
-		if ch != "slack" && ch != "telegram" {
+		if ch != "slack" && ch != "telegram" && ch != "discord" {
 			continue
 		}
 		cd := channelComposeData{Name: ch}
@@ -266,6 +273,10 @@ func generateDockerCompose(path string, imageTag string, cfg *types.ForgeConfig,
 			cd.EnvVars = []string{
 				"TELEGRAM_BOT_TOKEN=${TELEGRAM_BOT_TOKEN}",
 			}
+		case "discord":
+			cd.EnvVars = []string{
+				"DISCORD_BOT_TOKEN=${DISCORD_BOT_TOKEN}",
+			}
 		}
 		channels = append(channels, cd)
 	}
@@ -319,6 +330,23 @@ func ensureBuildOutput(outDir, cfgPath string) error {
 	return nil
 }
 
+// loadBuiltSpec reads agent.json from the build output and verifies its
+// agent_id and version match forge.yaml.
+func loadBuiltSpec(outDir string, cfg *types.ForgeConfig) (*agentspec.AgentSpec, error) {
+	data, err := os.ReadFile(filepath.Join(outDir, "agent.json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading agent.json: %w", err)
+	}
+	var spec agentspec.AgentSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parsing agent.json: %w", err)
+	}
+	if err := validate.CheckSpecIdentity(cfg, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
 // computeImageTag constructs the image tag from agent ID, version, and optional registry.
 func computeImageTag(agentID, version, reg string) string {
 	if reg != "" {
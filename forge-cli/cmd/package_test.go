@@ -122,6 +122,33 @@ tools:
 	}
 }
 
+func TestLoadBuiltSpec_ImageTagFromSpec(t *testing.T) {
+	outDir := t.TempDir()
+	writeAgentJSON := func(version string) {
+		t.Helper()
+		data := `{"forge_version":"1.0","agent_id":"test-agent","version":"` + version + `","name":"test-agent"}`
+		if err := os.WriteFile(filepath.Join(outDir, "agent.json"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &types.ForgeConfig{AgentID: "test-agent", Version: "0.2.0"}
+
+	// Stale build output from before a version bump
+	writeAgentJSON("0.1.0")
+	if _, err := loadBuiltSpec(outDir, cfg); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("expected version mismatch error, got %v", err)
+	}
+
+	writeAgentJSON("0.2.0")
+	spec, err := loadBuiltSpec(outDir, cfg)
+	if err != nil {
+		t.Fatalf("loadBuiltSpec() error: %v", err)
+	}
+	if tag := computeImageTag(spec.AgentID, spec.Version, "ghcr.io/org"); tag != "ghcr.io/org/test-agent:0.2.0" {
+		t.Errorf("image tag = %q, want ghcr.io/org/test-agent:0.2.0", tag)
+	}
+}
+
 func TestWithChannelsFlagDefault(t *testing.T) {
 	if withChannels {
 		t.Error("--with-channels should default to false")
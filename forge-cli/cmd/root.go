@@ -31,13 +31,19 @@ func init() {
 
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(lintCmd)
 	rootCmd.AddCommand(buildCmd)
 	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(chatCmd)
+	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(toolCmd)
 	rootCmd.AddCommand(packageCmd)
 	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(channelCmd)
 	rootCmd.AddCommand(skillsCmd)
+	rootCmd.AddCommand(egressCmd)
+	rootCmd.AddCommand(completionCmd)
 }
 
 // SetVersionInfo sets the version and commit for display.
@@ -8,22 +8,36 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/initializ/forge/forge-cli/channels"
 	"github.com/initializ/forge/forge-cli/config"
 	"github.com/initializ/forge/forge-cli/runtime"
+	corechannels "github.com/initializ/forge/forge-core/channels"
 	"github.com/initializ/forge/forge-core/validate"
 	"github.com/spf13/cobra"
 )
 
 var (
-	runPort              int
-	runMockTools         bool
-	runEnforceGuardrails bool
-	runModel             string
-	runProvider          string
-	runEnvFile           string
-	runWithChannels      string
+	runPort               int
+	runMockTools          bool
+	runEnforceGuardrails  bool
+	runModel              string
+	runProvider           string
+	runEnvFile            string
+	runWithChannels       string
+	runDebugStream        bool
+	runMaxCallDepth       int
+	runToolBudget         float64
+	runMaxHistory         int
+	runTaskTimeout        time.Duration
+	runArtifactsDir       string
+	runSessionDir         string
+	runTraceOpenInference bool
+	runSafe               bool
+	runAllowTools         []string
+	runDescribe           bool
+	runFeedbackFile       string
 )
 
 var runCmd = &cobra.Command{
@@ -33,13 +47,25 @@ var runCmd = &cobra.Command{
 }
 
 func init() {
-	runCmd.Flags().IntVar(&runPort, "port", 8080, "port for the A2A dev server")
+	runCmd.Flags().IntVar(&runPort, "port", 8080, "port for the A2A dev server (0 = pick a free port)")
 	runCmd.Flags().BoolVar(&runMockTools, "mock-tools", false, "use mock runtime instead of subprocess")
 	runCmd.Flags().BoolVar(&runEnforceGuardrails, "enforce-guardrails", false, "enforce guardrail violations as errors")
 	runCmd.Flags().StringVar(&runModel, "model", "", "override model name (sets MODEL_NAME env var)")
 	runCmd.Flags().StringVar(&runProvider, "provider", "", "LLM provider (openai, anthropic, ollama)")
 	runCmd.Flags().StringVar(&runEnvFile, "env", ".env", "path to .env file")
 	runCmd.Flags().StringVar(&runWithChannels, "with", "", "comma-separated channel adapters to start (e.g. slack,telegram)")
+	runCmd.Flags().BoolVar(&runDebugStream, "debug-stream", false, "stream tool calls and status updates as SSE \"debug\" events")
+	runCmd.Flags().IntVar(&runMaxCallDepth, "max-call-depth", 5, "maximum agent-to-agent delegation depth before tasks are rejected")
+	runCmd.Flags().Float64Var(&runToolBudget, "tool-budget", 0, "per-task budget for tool costs declared via tools[].config.cost (0 = unlimited)")
+	runCmd.Flags().DurationVar(&runTaskTimeout, "task-timeout", 0, "overall deadline per task, e.g. 5m (0 = task_timeout from forge.yaml or none)")
+	runCmd.Flags().IntVar(&runMaxHistory, "max-history", 0, "maximum prior task messages replayed to the model (0 = memory.max_history or unlimited)")
+	runCmd.Flags().StringVar(&runArtifactsDir, "artifacts-dir", "", "write artifacts of completed tasks to <dir>/<task-id>/")
+	runCmd.Flags().StringVar(&runSessionDir, "session-dir", "", "directory passed to crewai/langchain agents as FORGE_SESSION_DIR so state survives restarts")
+	runCmd.Flags().BoolVar(&runTraceOpenInference, "trace-openinference", false, "export agent loop traces in OpenInference format via OTLP/HTTP")
+	runCmd.Flags().BoolVar(&runSafe, "safe", false, "register only read-only tools; mutating tools are disabled")
+	runCmd.Flags().StringSliceVar(&runAllowTools, "allow-tool", nil, "mutating tool to keep enabled in --safe mode (repeatable)")
+	runCmd.Flags().StringVar(&runFeedbackFile, "feedback-file", "", "append reaction feedback from channel adapters to this JSONL file (default: stderr)")
+	runCmd.Flags().BoolVar(&runDescribe, "describe", false, "print the system prompt and tool schemas the model will receive, then exit")
 }
 
 func runRun(cmd *cobra.Command, args []string) error {
@@ -95,20 +121,39 @@ func runRun(cmd *cobra.Command, args []string) error {
 	}
 
 	runner, err := runtime.NewRunner(runtime.RunnerConfig{
-		Config:            cfg,
-		WorkDir:           workDir,
-		Port:              runPort,
-		MockTools:         runMockTools,
-		EnforceGuardrails: runEnforceGuardrails,
-		ModelOverride:     runModel,
-		ProviderOverride:  runProvider,
-		EnvFilePath:       envPath,
-		Verbose:           verbose,
-		Channels:          activeChannels,
+		Config:             cfg,
+		WorkDir:            workDir,
+		Port:               runPort,
+		AutoPort:           runPort == 0,
+		MockTools:          runMockTools,
+		EnforceGuardrails:  runEnforceGuardrails,
+		ModelOverride:      runModel,
+		ProviderOverride:   runProvider,
+		EnvFilePath:        envPath,
+		Verbose:            verbose,
+		Channels:           activeChannels,
+		DebugStream:        runDebugStream,
+		MaxCallDepth:       runMaxCallDepth,
+		ToolBudget:         runToolBudget,
+		MaxHistory:         runMaxHistory,
+		TaskTimeout:        runTaskTimeout,
+		ArtifactsDir:       runArtifactsDir,
+		SessionDir:         runSessionDir,
+		TraceOpenInference: runTraceOpenInference,
+		SafeMode:           runSafe,
+		SafeModeAllow:      runAllowTools,
 	})
 	if err != nil {
 		return fmt.Errorf("creating runner: %w", err)
 	}
+	if runDescribe {
+		return runner.Describe(os.Stdout)
+	}
+
+	// Bind now so channel adapters know the port when it is chosen by the OS
+	if err := runner.Listen(); err != nil {
+		return err
+	}
 
 	// Set up signal handling
 	ctx, cancel := context.WithCancel(context.Background())
@@ -125,9 +170,20 @@ func runRun(cmd *cobra.Command, args []string) error {
 	// Start channel adapters if --with flag is set
 	if runWithChannels != "" {
 		registry := defaultRegistry()
-		agentURL := fmt.Sprintf("http://localhost:%d", runPort)
+		agentURL := fmt.Sprintf("http://localhost:%d", runner.Port())
 		router := channels.NewRouter(agentURL)
 
+		feedbackOut := os.Stderr
+		if runFeedbackFile != "" {
+			f, err := os.OpenFile(runFeedbackFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				return fmt.Errorf("opening feedback file: %w", err)
+			}
+			defer f.Close() //nolint:errcheck
+			feedbackOut = f
+		}
+		feedback := channels.FeedbackLog(feedbackOut)
+
 		names := strings.Split(runWithChannels, ",")
 		for _, name := range names {
 			name = strings.TrimSpace(name)
@@ -149,11 +205,14 @@ func runRun(cmd *cobra.Command, args []string) error {
 			if err := plugin.Init(*chCfg); err != nil {
 				return fmt.Errorf("initialising %s: %w", name, err)
 			}
+			if fp, ok := plugin.(corechannels.FeedbackPlugin); ok {
+				fp.SetFeedbackHandler(feedback)
+			}
 
 			defer plugin.Stop() //nolint:errcheck
 
 			go func() {
-				if err := plugin.Start(ctx, router.Handler()); err != nil {
+				if err := plugin.Start(ctx, channels.Allowlist(*chCfg, router.Handler())); err != nil {
 					fmt.Fprintf(os.Stderr, "channel %s error: %v\n", plugin.Name(), err)
 				}
 			}()
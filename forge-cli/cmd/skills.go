@@ -2,17 +2,24 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-cli/runtime"
 	cliskills "github.com/initializ/forge/forge-cli/skills"
 	skillreg "github.com/initializ/forge/forge-core/registry"
 	coreskills "github.com/initializ/forge/forge-core/skills"
+	"github.com/initializ/forge/forge-core/types"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var skillsCmd = &cobra.Command{
@@ -29,36 +36,147 @@ var skillsValidateCmd = &cobra.Command{
 var skillsAddCmd = &cobra.Command{
 	Use:   "add <name>",
 	Short: "Add a registry skill to the current project",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runSkillsAdd,
+	Long: "Add vendors a skill from the registry into skills/<name>.md, records its required " +
+		"binaries and environment variables in skills.md, adds those variables to .env, and " +
+		"adds the hosts it calls to egress.allowed_domains in forge.yaml.\n\n" +
+		"Running it again for the same skill changes nothing but the vendored skill file.",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSkillNames,
+	RunE:              runSkillsAdd,
+}
+
+var skillsListJSON bool
+
+var skillsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the skills available in the registry",
+	Args:  cobra.NoArgs,
+	RunE:  runSkillsList,
 }
 
 func init() {
 	skillsCmd.AddCommand(skillsValidateCmd)
 	skillsCmd.AddCommand(skillsAddCmd)
+	skillsCmd.AddCommand(skillsListCmd)
+
+	skillsListCmd.Flags().BoolVar(&skillsListJSON, "json", false, "output as JSON")
+}
+
+// remoteRegistries holds the hosted registries opened by skillRegistry, so
+// an unreachable one is reported once per command.
+var remoteRegistries = map[string]*skillreg.Remote{}
+
+// skillRegistry returns the registry set by FORGE_SKILL_REGISTRY or, when
+// cfg is not nil, skills.registry in forge.yaml, and otherwise the bundled
+// registry. Hosted registries are cached under the user cache directory.
+func skillRegistry(cfg *types.ForgeConfig) skillreg.Source {
+	url := os.Getenv("FORGE_SKILL_REGISTRY")
+	if url == "" && cfg != nil {
+		url = cfg.Skills.Registry
+	}
+	if url == "" {
+		return skillreg.Bundled
+	}
+	if remote, ok := remoteRegistries[url]; ok {
+		return remote
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	remote := skillreg.NewRemote(url, filepath.Join(cacheDir, "forge", "skill-registry"))
+	var warned bool
+	remote.OnError = func(err error) {
+		if !warned {
+			warned = true
+			fmt.Fprintf(os.Stderr, "Warning: skill registry unavailable, using cached or bundled skills: %v\n", err)
+		}
+	}
+	remoteRegistries[url] = remote
+	return remote
+}
+
+// loadProjectConfig loads forge.yaml if there is one, for settings such as
+// skills.registry that commands use when run inside a project.
+func loadProjectConfig() *types.ForgeConfig {
+	cfgPath := cfgFile
+	if !filepath.IsAbs(cfgPath) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil
+		}
+		cfgPath = filepath.Join(wd, cfgPath)
+	}
+	cfg, err := config.LoadForgeConfig(cfgPath)
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+func runSkillsList(cmd *cobra.Command, args []string) error {
+	skills, err := skillRegistry(loadProjectConfig()).LoadIndex()
+	if err != nil {
+		return fmt.Errorf("loading skill registry: %w", err)
+	}
+	if skillsListJSON {
+		return writeJSONOutput(cmd.OutOrStdout(), skills)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "NAME\tDESCRIPTION\tENV\tBINARIES\tEGRESS\n")
+	for _, s := range skills {
+		env := slices.Clone(s.RequiredEnv)
+		if len(s.OneOfEnv) > 0 {
+			env = append(env, strings.Join(s.OneOfEnv, "|"))
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, s.Description,
+			listOrDash(env), listOrDash(s.RequiredBins), listOrDash(s.EgressDomains))
+	}
+	return w.Flush()
+}
+
+// listOrDash joins values with commas, or returns "-" when there are none.
+func listOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ",")
 }
 
 func runSkillsAdd(cmd *cobra.Command, args []string) error {
 	name := args[0]
+	out := cmd.OutOrStdout()
+
+	cfgPath := cfgFile
+	if !filepath.IsAbs(cfgPath) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w", err)
+		}
+		cfgPath = filepath.Join(wd, cfgPath)
+	}
+	cfg, err := config.LoadForgeConfig(cfgPath)
+	if err != nil {
+		return fmt.Errorf("loading forge config (run forge skills add in a forge project): %w", err)
+	}
+	workDir := filepath.Dir(cfgPath)
 
 	// Look up skill in registry
-	info := skillreg.GetSkillByName(name)
+	reg := skillRegistry(cfg)
+	info := reg.GetSkillByName(name)
 	if info == nil {
 		return fmt.Errorf("skill %q not found in registry", name)
 	}
 
-	wd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("getting working directory: %w", err)
-	}
-
 	// Write skill markdown
-	skillDir := filepath.Join(wd, "skills")
+	skillDir := filepath.Join(workDir, "skills")
 	if err := os.MkdirAll(skillDir, 0o755); err != nil {
 		return fmt.Errorf("creating skills directory: %w", err)
 	}
 
-	content, err := skillreg.LoadSkillFile(name)
+	content, err := reg.LoadSkillFile(name)
 	if err != nil {
 		return fmt.Errorf("loading skill file: %w", err)
 	}
@@ -67,74 +185,192 @@ func runSkillsAdd(cmd *cobra.Command, args []string) error {
 	if err := os.WriteFile(skillPath, content, 0o644); err != nil {
 		return fmt.Errorf("writing skill file: %w", err)
 	}
-	fmt.Printf("  Added skill file: skills/%s.md\n", name)
+	_, _ = fmt.Fprintf(out, "  Added skill file: skills/%s.md\n", name)
 
 	// Write script if the skill has one
-	if skillreg.HasSkillScript(name) {
-		scriptContent, sErr := skillreg.LoadSkillScript(name)
+	if reg.HasSkillScript(name) {
+		scriptContent, sErr := reg.LoadSkillScript(name)
 		if sErr == nil {
 			scriptDir := filepath.Join(skillDir, "scripts")
 			if mkErr := os.MkdirAll(scriptDir, 0o755); mkErr != nil {
-				fmt.Printf("  Warning: could not create scripts directory: %s\n", mkErr)
+				_, _ = fmt.Fprintf(out, "  Warning: could not create scripts directory: %s\n", mkErr)
 			} else {
 				scriptPath := filepath.Join(scriptDir, name+".sh")
 				if wErr := os.WriteFile(scriptPath, scriptContent, 0o755); wErr != nil {
-					fmt.Printf("  Warning: could not write script: %s\n", wErr)
+					_, _ = fmt.Fprintf(out, "  Warning: could not write script: %s\n", wErr)
 				} else {
-					fmt.Printf("  Added script:     skills/scripts/%s.sh\n", name)
+					_, _ = fmt.Fprintf(out, "  Added script:     skills/scripts/%s.sh\n", name)
 				}
 			}
 		}
 	}
 
+	// Record the skill's requirements in skills.md
+	skillsPath := "skills.md"
+	if cfg.Skills.Path != "" {
+		skillsPath = cfg.Skills.Path
+	}
+	if !filepath.IsAbs(skillsPath) {
+		skillsPath = filepath.Join(workDir, skillsPath)
+	}
+	if changed, err := addSkillRequirements(skillsPath, info); err != nil {
+		return fmt.Errorf("updating %s: %w", filepath.Base(skillsPath), err)
+	} else if changed {
+		_, _ = fmt.Fprintf(out, "  Updated requirements in %s\n", filepath.Base(skillsPath))
+	}
+
+	// Add the skill's environment variables to .env
+	added, err := addSkillEnvVars(filepath.Join(workDir, ".env"), info, envFromOS(), cmd.InOrStdin(), out)
+	if err != nil {
+		return fmt.Errorf("updating .env: %w", err)
+	}
+	if len(added) > 0 {
+		_, _ = fmt.Fprintf(out, "  Added to .env:    %s\n", strings.Join(added, ", "))
+	}
+
+	// Allow the skill's API hosts
+	domains, err := addAllowedDomainsToForgeYAML(cfgPath, info.EgressDomains)
+	if err != nil {
+		return fmt.Errorf("updating egress: %w", err)
+	}
+	if len(domains) > 0 {
+		_, _ = fmt.Fprintf(out, "  Added to egress.allowed_domains: %s\n", strings.Join(domains, ", "))
+	}
+
 	// Check binary requirements
-	if len(info.RequiredBins) > 0 {
-		fmt.Println("\n  Binary requirements:")
-		for _, bin := range info.RequiredBins {
-			if _, lookErr := exec.LookPath(bin); lookErr != nil {
-				fmt.Printf("    %s — MISSING (not found in PATH)\n", bin)
-			} else {
-				fmt.Printf("    %s — ok\n", bin)
-			}
+	for _, bin := range info.RequiredBins {
+		if _, lookErr := exec.LookPath(bin); lookErr != nil {
+			_, _ = fmt.Fprintf(out, "  Warning: the %s skill needs %s, which is not on PATH; %s\n", name, bin, coreskills.InstallHint(bin))
 		}
 	}
 
-	// Check env var requirements
-	missingEnvs := []string{}
-	if len(info.RequiredEnv) > 0 {
-		fmt.Println("\n  Environment requirements:")
-		for _, env := range info.RequiredEnv {
-			if os.Getenv(env) == "" {
-				fmt.Printf("    %s — NOT SET\n", env)
-				missingEnvs = append(missingEnvs, env)
-			} else {
-				fmt.Printf("    %s — ok\n", env)
-			}
+	_, _ = fmt.Fprintf(out, "\nSkill %q added successfully.\n", info.DisplayName)
+	return nil
+}
+
+// addSkillRequirements merges the skill's binaries and required and one-of
+// environment variables into the metadata.forge.requires frontmatter of the
+// skills file, creating the file or its frontmatter if needed. It reports
+// whether the file changed.
+func addSkillRequirements(path string, info *skillreg.SkillInfo) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	fm, body, _ := coreskills.SplitFrontmatter(content)
+
+	doc := map[string]any{}
+	if err := yaml.Unmarshal(fm, &doc); err != nil {
+		return false, fmt.Errorf("parsing frontmatter: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	requires := childMap(childMap(childMap(doc, "metadata"), "forge"), "requires")
+	env := childMap(requires, "env")
+	changed := mergeStringList(requires, "bins", info.RequiredBins)
+	changed = mergeStringList(env, "required", info.RequiredEnv) || changed
+	changed = mergeStringList(env, "one_of", info.OneOfEnv) || changed
+	if !changed {
+		return false, nil
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return false, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(data)
+	buf.WriteString("---\n")
+	buf.Write(body)
+	return true, os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// childMap returns m[key] as a map, creating it if it is missing.
+func childMap(m map[string]any, key string) map[string]any {
+	if child, ok := m[key].(map[string]any); ok {
+		return child
+	}
+	child := map[string]any{}
+	m[key] = child
+	return child
+}
+
+// mergeStringList appends the values missing from the list m[key] and
+// reports whether any were added.
+func mergeStringList(m map[string]any, key string, values []string) bool {
+	list, _ := m[key].([]any)
+	changed := false
+	for _, v := range values {
+		if !slices.Contains(list, any(v)) {
+			list = append(list, v)
+			changed = true
 		}
 	}
+	if changed {
+		m[key] = list
+	}
+	return changed
+}
 
-	// Prompt for missing env vars
-	if len(missingEnvs) > 0 {
-		reader := bufio.NewReader(os.Stdin)
-		for _, env := range missingEnvs {
-			fmt.Printf("\n  Enter value for %s (or press Enter to skip): ", env)
-			val, _ := reader.ReadString('\n')
-			val = strings.TrimSpace(val)
-			if val != "" {
-				// Append to .env file
-				envPath := filepath.Join(wd, ".env")
-				f, fErr := os.OpenFile(envPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-				if fErr == nil {
-					_, _ = fmt.Fprintf(f, "# Required by %s skill\n%s=%s\n", name, env, val)
-					_ = f.Close()
-					fmt.Printf("  Added %s to .env\n", env)
-				}
-			}
+// addSkillEnvVars appends the skill's environment variables that are set
+// neither in the .env file nor in osEnv, prompting on in for each value.
+// A one-of group is skipped when any of its variables is set. It returns
+// the keys added.
+func addSkillEnvVars(envPath string, info *skillreg.SkillInfo, osEnv map[string]string, in io.Reader, out io.Writer) ([]string, error) {
+	dotEnv, err := runtime.LoadEnvFile(envPath)
+	if err != nil {
+		return nil, err
+	}
+	isSet := func(key string) bool {
+		_, inFile := dotEnv[key]
+		return inFile || osEnv[key] != ""
+	}
+
+	var entries []envVarEntry
+	for _, env := range info.RequiredEnv {
+		if !isSet(env) {
+			entries = append(entries, envVarEntry{Key: env, Comment: fmt.Sprintf("Required by %s skill", info.Name)})
+		}
+	}
+	if !slices.ContainsFunc(info.OneOfEnv, isSet) {
+		for _, env := range info.OneOfEnv {
+			entries = append(entries, envVarEntry{Key: env, Comment: fmt.Sprintf("One of required by %s skill", info.Name)})
 		}
 	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
 
-	fmt.Printf("\nSkill %q added successfully.\n", info.DisplayName)
-	return nil
+	reader := bufio.NewReader(in)
+	for i := range entries {
+		_, _ = fmt.Fprintf(out, "\n  Enter value for %s (or press Enter to skip): ", entries[i].Key)
+		val, _ := reader.ReadString('\n')
+		entries[i].Value = strings.TrimSpace(val)
+	}
+	_, _ = fmt.Fprintln(out)
+
+	existing, err := os.ReadFile(envPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	f, err := os.OpenFile(envPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		_, _ = fmt.Fprintln(f)
+	}
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		if _, err := fmt.Fprintf(f, "# %s\n%s=%s\n", e.Comment, e.Key, e.Value); err != nil {
+			return nil, err
+		}
+		keys[i] = e.Key
+	}
+	return keys, nil
 }
 
 func runSkillsValidate(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-cli/config"
+	cliskills "github.com/initializ/forge/forge-cli/skills"
+	skillreg "github.com/initializ/forge/forge-core/registry"
+	coreskills "github.com/initializ/forge/forge-core/skills"
+	"github.com/spf13/cobra"
+)
+
+func TestRunSkillsAdd_GitHub(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeTestForgeYAML(t, dir, `agent_id: test-agent
+version: 0.1.0
+framework: custom
+entrypoint: python agent.py
+egress:
+  mode: allowlist
+  allowed_domains:
+    - api.github.com
+`)
+	skillsMD := "# Test Skills\n\n## Tool: example_tool\n\nA sample tool.\n"
+	if err := os.WriteFile(filepath.Join(dir, "skills.md"), []byte(skillsMD), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("OPENAI_API_KEY=sk-test"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("PATH", t.TempDir()) // gh is missing
+
+	oldCfg := cfgFile
+	cfgFile = cfgPath
+	defer func() { cfgFile = oldCfg }()
+
+	add := func(input string) string {
+		t.Helper()
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		cmd.SetIn(strings.NewReader(input))
+		if err := runSkillsAdd(cmd, []string{"github"}); err != nil {
+			t.Fatalf("runSkillsAdd: %v\n%s", err, out.String())
+		}
+		return out.String()
+	}
+	if got := add("ghp_test\n"); !strings.Contains(got, "needs gh, which is not on PATH; "+coreskills.InstallHint("gh")) {
+		t.Errorf("missing-binary warning lacks the install hint:\n%s", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "skills", "github.md")); err != nil {
+		t.Errorf("skill file not vendored: %v", err)
+	}
+
+	entries, _, err := cliskills.ParseFileWithMetadata(filepath.Join(dir, "skills.md"))
+	if err != nil {
+		t.Fatalf("parsing skills.md: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "example_tool" {
+		t.Fatalf("skills.md entries = %+v, want the existing example_tool", entries)
+	}
+	reqs := entries[0].ForgeReqs
+	if reqs == nil || !slices.Equal(reqs.Bins, []string{"gh"}) || reqs.Env == nil || !slices.Equal(reqs.Env.Required, []string{"GH_TOKEN"}) {
+		t.Errorf("skills.md requirements = %+v, want gh and GH_TOKEN", reqs)
+	}
+
+	env, _ := os.ReadFile(filepath.Join(dir, ".env"))
+	if want := "OPENAI_API_KEY=sk-test\n# Required by github skill\nGH_TOKEN=ghp_test\n"; string(env) != want {
+		t.Errorf(".env = %q, want %q", env, want)
+	}
+
+	cfg, err := config.LoadForgeConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"api.github.com", "github.com"}; !slices.Equal(cfg.Egress.AllowedDomains, want) {
+		t.Errorf("allowed_domains = %v, want %v", cfg.Egress.AllowedDomains, want)
+	}
+	if cfg.Egress.Mode != "allowlist" || cfg.AgentID != "test-agent" {
+		t.Errorf("other settings not preserved: %+v", cfg)
+	}
+
+	// Adding it again changes nothing
+	skillsBefore, _ := os.ReadFile(filepath.Join(dir, "skills.md"))
+	cfgBefore, _ := os.ReadFile(cfgPath)
+	out := add("")
+	if strings.Contains(out, "Enter value") {
+		t.Errorf("second add prompted again:\n%s", out)
+	}
+	skillsAfter, _ := os.ReadFile(filepath.Join(dir, "skills.md"))
+	cfgAfter, _ := os.ReadFile(cfgPath)
+	envAfter, _ := os.ReadFile(filepath.Join(dir, ".env"))
+	if !bytes.Equal(skillsBefore, skillsAfter) || !bytes.Equal(cfgBefore, cfgAfter) || !bytes.Equal(env, envAfter) {
+		t.Error("second add modified skills.md, forge.yaml, or .env")
+	}
+}
+
+func TestRunSkillsAdd_UnknownSkill(t *testing.T) {
+	cfgPath := writeTestForgeYAML(t, t.TempDir(), "agent_id: test-agent\nversion: 0.1.0\nentrypoint: python agent.py\n")
+	oldCfg := cfgFile
+	cfgFile = cfgPath
+	defer func() { cfgFile = oldCfg }()
+
+	err := runSkillsAdd(&cobra.Command{}, []string{"no-such-skill"})
+	if err == nil || !strings.Contains(err.Error(), "not found in registry") {
+		t.Errorf("error = %v, want not found in registry", err)
+	}
+}
+
+func TestRunSkillsList(t *testing.T) {
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	if err := runSkillsList(cmd, nil); err != nil {
+		t.Fatalf("runSkillsList: %v", err)
+	}
+	if !strings.Contains(out.String(), "github") || !strings.Contains(out.String(), "GH_TOKEN") ||
+		!strings.Contains(out.String(), "api.github.com,github.com") {
+		t.Errorf("output missing the github skill:\n%s", out.String())
+	}
+
+	skillsListJSON = true
+	defer func() { skillsListJSON = false }()
+	out.Reset()
+	if err := runSkillsList(cmd, nil); err != nil {
+		t.Fatalf("runSkillsList --json: %v", err)
+	}
+	var skills []skillreg.SkillInfo
+	if err := json.Unmarshal(out.Bytes(), &skills); err != nil {
+		t.Fatalf("decoding JSON: %v\n%s", err, out.String())
+	}
+	i := slices.IndexFunc(skills, func(s skillreg.SkillInfo) bool { return s.Name == "github" })
+	if i < 0 || !slices.Equal(skills[i].RequiredBins, []string{"gh"}) {
+		t.Errorf("github entry missing or incomplete: %+v", skills)
+	}
+}
+
+func TestRunSkillsAdd_RemoteRegistry(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"deploy","display_name":"Deploy","skill_file":"deploy.md","egress_domains":["deploy.internal"]}]`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/skills/deploy.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("## Tool: deploy_service\n\nDeploy a service.\n")) //nolint:errcheck
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := writeTestForgeYAML(t, dir, "agent_id: test-agent\nversion: 0.1.0\nentrypoint: python agent.py\nskills:\n  registry: "+srv.URL+"\n")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	oldCfg := cfgFile
+	cfgFile = cfgPath
+	defer func() { cfgFile = oldCfg }()
+
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+	if err := runSkillsAdd(cmd, []string{"deploy"}); err != nil {
+		t.Fatalf("runSkillsAdd: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "skills", "deploy.md"))
+	if err != nil || !strings.Contains(string(data), "deploy_service") {
+		t.Errorf("skills/deploy.md = %q, %v; want the remote file", data, err)
+	}
+	cfg, _ := config.LoadForgeConfig(cfgPath)
+	if !slices.Contains(cfg.Egress.AllowedDomains, "deploy.internal") {
+		t.Errorf("allowed_domains = %v, want deploy.internal", cfg.Egress.AllowedDomains)
+	}
+}
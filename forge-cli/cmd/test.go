@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/initializ/forge/forge-cli/config"
+	"github.com/initializ/forge/forge-cli/runtime"
+	"github.com/spf13/cobra"
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [dir]",
+	Short: "Run the agent against recorded test fixtures",
+	Long: "Test runs each fixture in dir (default: tests/ next to forge.yaml) through the LLM " +
+		"executor with the agent's system prompt and tools. The model is replaced by the " +
+		"fixture's scripted responses and tool calls return the fixture's canned outputs, so " +
+		"no LLM or network calls are made. It exits non-zero if any fixture fails.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTest,
+}
+
+func runTest(cmd *cobra.Command, args []string) error {
+	cfgPath := cfgFile
+	if !filepath.IsAbs(cfgPath) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting working directory: %w", err)
+		}
+		cfgPath = filepath.Join(wd, cfgPath)
+	}
+
+	cfg, err := config.LoadForgeConfig(cfgPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	workDir := filepath.Dir(cfgPath)
+
+	dir := filepath.Join(workDir, "tests")
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	fixtures, err := runtime.LoadTestFixtures(dir)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return fmt.Errorf("no test fixtures found in %s", dir)
+	}
+
+	runner, err := runtime.NewRunner(runtime.RunnerConfig{Config: cfg, WorkDir: workDir, Verbose: verbose})
+	if err != nil {
+		return fmt.Errorf("creating runner: %w", err)
+	}
+	return runFixtures(cmd.Context(), runner, fixtures, cmd.OutOrStdout())
+}
+
+// runFixtures runs each fixture and prints a PASS or FAIL line for it,
+// followed by the reasons a fixture failed.
+func runFixtures(ctx context.Context, runner *runtime.Runner, fixtures []*runtime.TestFixture, w io.Writer) error {
+	failed := 0
+	for _, f := range fixtures {
+		res := runner.RunFixture(ctx, f)
+		if res.Passed() {
+			fmt.Fprintf(w, "PASS  %s\n", f.Name) //nolint:errcheck
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "FAIL  %s (%s)\n", f.Name, f.Path) //nolint:errcheck
+		for _, msg := range res.Failures {
+			fmt.Fprintf(w, "      %s\n", msg) //nolint:errcheck
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d passed, %d failed\n", len(fixtures)-failed, failed) //nolint:errcheck
+	if failed > 0 {
+		return fmt.Errorf("%d of %d test(s) failed", failed, len(fixtures))
+	}
+	return nil
+}
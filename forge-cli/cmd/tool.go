@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"text/tabwriter"
 
@@ -12,26 +13,40 @@ import (
 )
 
 var toolCmd = &cobra.Command{
-	Use:   "tool",
-	Short: "Manage and inspect agent tools",
+	Use:     "tool",
+	Aliases: []string{"tools"},
+	Short:   "Manage and inspect agent tools",
 }
 
+var toolListJSON bool
+
 var toolListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all available tools",
+	Args:  cobra.NoArgs,
 	RunE:  toolListRun,
 }
 
 var toolDescribeCmd = &cobra.Command{
-	Use:   "describe <name>",
-	Short: "Show tool details and schema",
-	Args:  cobra.ExactArgs(1),
-	RunE:  toolDescribeRun,
+	Use:               "describe <name>",
+	Short:             "Show tool details and schema",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeToolNames,
+	RunE:              toolDescribeRun,
 }
 
 func init() {
 	toolCmd.AddCommand(toolListCmd)
 	toolCmd.AddCommand(toolDescribeCmd)
+
+	toolListCmd.Flags().BoolVar(&toolListJSON, "json", false, "output as JSON")
+}
+
+// toolListEntry is one tool in the forge tool list --json output.
+type toolListEntry struct {
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
 }
 
 func toolListRun(cmd *cobra.Command, args []string) error {
@@ -40,16 +55,33 @@ func toolListRun(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("registering builtins: %w", err)
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintf(w, "NAME\tCATEGORY\tDESCRIPTION\n")
-
+	entries := make([]toolListEntry, 0, len(reg.List()))
 	for _, name := range reg.List() {
 		t := reg.Get(name)
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", t.Name(), t.Category(), t.Description())
+		entries = append(entries, toolListEntry{Name: t.Name(), Category: string(t.Category()), Description: t.Description()})
+	}
+	if toolListJSON {
+		return writeJSONOutput(cmd.OutOrStdout(), entries)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "NAME\tCATEGORY\tDESCRIPTION\n")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", e.Name, e.Category, e.Description)
 	}
 	return w.Flush()
 }
 
+// writeJSONOutput writes v to w as indented JSON.
+func writeJSONOutput(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding output: %w", err)
+	}
+	return nil
+}
+
 func toolDescribeRun(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	t := builtins.GetByName(name)
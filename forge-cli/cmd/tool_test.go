@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
+
+	"github.com/spf13/cobra"
 )
 
 func TestToolListCmd(t *testing.T) {
@@ -23,6 +27,51 @@ func TestToolListCmd(t *testing.T) {
 	}
 }
 
+func TestToolListRun_WebSearch(t *testing.T) {
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	if err := toolListRun(cmd, nil); err != nil {
+		t.Fatalf("toolListRun: %v", err)
+	}
+	if !strings.Contains(out.String(), "web_search") {
+		t.Errorf("output missing web_search:\n%s", out.String())
+	}
+
+	toolListJSON = true
+	defer func() { toolListJSON = false }()
+	out.Reset()
+	if err := toolListRun(cmd, nil); err != nil {
+		t.Fatalf("toolListRun --json: %v", err)
+	}
+	var entries []toolListEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatalf("decoding JSON: %v\n%s", err, out.String())
+	}
+	found := false
+	for _, e := range entries {
+		if e.Name == "web_search" && e.Description != "" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("web_search missing from %+v", entries)
+	}
+}
+
+func TestToolsAlias(t *testing.T) {
+	rootCmd.SetArgs([]string{"tools", "list"})
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&out)
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("tools list error: %v", err)
+	}
+	if !strings.Contains(out.String(), "web_search") {
+		t.Errorf("forge tools list output missing web_search:\n%s", out.String())
+	}
+}
+
 func TestToolDescribeCmd_KnownTool(t *testing.T) {
 	rootCmd.SetArgs([]string{"tool", "describe", "json_parse"})
 	var out bytes.Buffer
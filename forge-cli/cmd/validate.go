@@ -63,6 +63,12 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		for _, e := range errs {
 			result.Errors = append(result.Errors, fmt.Sprintf("agent.json: %s", e))
 		}
+		var spec agentspec.AgentSpec
+		if json.Unmarshal(data, &spec) == nil {
+			if err := validate.CheckGuardrails(spec.PolicyScaffold); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("agent.json: %v", err))
+			}
+		}
 		break
 	}
 
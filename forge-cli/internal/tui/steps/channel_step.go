@@ -35,6 +35,7 @@ func NewChannelStep(styles *tui.StyleSet) *ChannelStep {
 		{Label: "None", Value: "none", Description: "CLI / API only", Icon: "🚫"},
 		{Label: "Telegram", Value: "telegram", Description: "Easy setup, no public URL needed", Icon: "✈️"},
 		{Label: "Slack", Value: "slack", Description: "Socket Mode, no public URL needed", Icon: "💬"},
+		{Label: "Discord", Value: "discord", Description: "Gateway connection, no public URL needed", Icon: "🎮"},
 	}
 
 	selector := components.NewSingleSelect(
@@ -111,6 +112,24 @@ func (s *ChannelStep) updateSelectPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
 				s.styles.KbdDesc,
 			)
 			return s, s.keyInput.Init()
+		case "discord":
+			s.phase = channelTokenPhase
+			s.keyInput = components.NewSecretInput(
+				"Discord Bot Token (from the Developer Portal)",
+				true,
+				s.styles.Theme.Accent,
+				s.styles.Theme.Success,
+				s.styles.Theme.Error,
+				s.styles.Theme.Border,
+				s.styles.AccentTxt,
+				s.styles.InactiveBorder,
+				s.styles.SuccessTxt,
+				s.styles.ErrorTxt,
+				s.styles.DimTxt,
+				s.styles.KbdKey,
+				s.styles.KbdDesc,
+			)
+			return s, s.keyInput.Init()
 		case "slack":
 			s.phase = channelTokenPhase
 			s.keyInput = components.NewSecretInput(
@@ -149,6 +168,12 @@ func (s *ChannelStep) updateTokenPhase(msg tea.Msg) (tui.Step, tea.Cmd) {
 			}
 			s.complete = true
 			return s, func() tea.Msg { return tui.StepCompleteMsg{} }
+		case "discord":
+			if val != "" {
+				s.tokens["DISCORD_BOT_TOKEN"] = val
+			}
+			s.complete = true
+			return s, func() tea.Msg { return tui.StepCompleteMsg{} }
 		case "slack":
 			if val != "" {
 				s.tokens["SLACK_APP_TOKEN"] = val
@@ -206,6 +231,13 @@ func (s *ChannelStep) View(width int) string {
 				s.styles.DimTxt.Render("2. Send /newbot and follow prompts"),
 				s.styles.DimTxt.Render("3. Copy the bot token"),
 			)
+		case "discord":
+			instructions = fmt.Sprintf("  %s\n  %s\n  %s\n  %s\n\n",
+				s.styles.SecondaryTxt.Render("Discord Bot Setup:"),
+				s.styles.DimTxt.Render("1. Create an application at https://discord.com/developers/applications"),
+				s.styles.DimTxt.Render("2. Add a bot and enable the Message Content intent"),
+				s.styles.DimTxt.Render("3. Copy the bot token"),
+			)
 		case "slack":
 			instructions = fmt.Sprintf("  %s\n  %s\n  %s\n  %s\n\n",
 				s.styles.SecondaryTxt.Render("Slack Socket Mode Setup:"),
@@ -233,6 +265,8 @@ func (s *ChannelStep) Summary() string {
 		return "Telegram"
 	case "slack":
 		return "Slack"
+	case "discord":
+		return "Discord"
 	}
 	return s.channel
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/initializ/forge/forge-cli/internal/tui"
 	"github.com/initializ/forge/forge-cli/internal/tui/components"
+	"github.com/initializ/forge/forge-core/security"
 )
 
 // DeriveEgressFunc computes egress domains from wizard context.
@@ -126,34 +127,12 @@ func (s *EgressStep) Apply(ctx *tui.WizardContext) {
 
 // inferSource guesses the source of an egress domain based on context.
 func inferSource(domain string, ctx *tui.WizardContext) string {
-	// Provider domains
-	providerDomains := map[string]string{
-		"api.openai.com":                    "model provider",
-		"api.anthropic.com":                 "model provider",
-		"generativelanguage.googleapis.com": "model provider",
-	}
-	if src, ok := providerDomains[domain]; ok {
-		return src
-	}
-
-	// Channel domains
-	channelDomains := map[string]string{
-		"api.telegram.org": "channel",
-		"slack.com":        "channel",
-		"hooks.slack.com":  "channel",
-		"api.slack.com":    "channel",
-	}
-	if src, ok := channelDomains[domain]; ok {
-		return src
-	}
-
-	// Tool domains
-	toolDomains := map[string]string{
-		"api.tavily.com":    "web_search tool",
-		"api.perplexity.ai": "web_search tool",
-	}
-	if src, ok := toolDomains[domain]; ok {
-		return src
+	kind, name, ok := security.DomainOrigin(domain)
+	switch {
+	case ok && kind == "provider":
+		return "model provider"
+	case ok && kind == "channel":
+		return "channel"
 	}
 
 	// Skill domains
@@ -167,5 +146,8 @@ func inferSource(domain string, ctx *tui.WizardContext) string {
 		return src
 	}
 
+	if ok {
+		return name + " tool"
+	}
 	return "configured"
 }
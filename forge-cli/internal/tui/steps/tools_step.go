@@ -41,7 +41,7 @@ type ToolsStep struct {
 	selected          []string
 	webSearchKey      string
 	webSearchKeyName  string // "TAVILY_API_KEY" or "PERPLEXITY_API_KEY"
-	webSearchProvider string // "tavily" or "perplexity"
+	webSearchProvider string // "tavily", "perplexity", or "duckduckgo"
 	validateFn        ValidateWebSearchKeyFunc
 	validating        bool
 }
@@ -109,6 +109,7 @@ func (s *ToolsStep) Update(msg tea.Msg) (tui.Step, tea.Cmd) {
 					[]components.SingleSelectItem{
 						{Label: "Tavily (Recommended)", Value: "tavily", Description: "LLM-optimized search with structured results", Icon: "🔍"},
 						{Label: "Perplexity", Value: "perplexity", Description: "AI-powered search with citations", Icon: "🌐"},
+						{Label: "DuckDuckGo", Value: "duckduckgo", Description: "No API key needed; best for trying things out", Icon: "🦆"},
 					},
 					s.styles.Theme.Accent,
 					s.styles.Theme.Primary,
@@ -144,6 +145,10 @@ func (s *ToolsStep) Update(msg tea.Msg) (tui.Step, tea.Cmd) {
 
 		if s.providerSelect.Done() {
 			_, s.webSearchProvider = s.providerSelect.Selected()
+			if s.webSearchProvider == "duckduckgo" {
+				s.complete = true
+				return s, func() tea.Msg { return tui.StepCompleteMsg{} }
+			}
 			s.initKeyInput("")
 			return s, s.keyInput.Init()
 		}
@@ -284,11 +289,16 @@ func toolIcon(name string) string {
 	icons := map[string]string{
 		"http_request":   "🌐",
 		"json_parse":     "📋",
+		"json_transform": "🔀",
 		"csv_parse":      "📊",
 		"datetime_now":   "🕐",
 		"uuid_generate":  "🔑",
 		"math_calculate": "🔢",
 		"web_search":     "🔍",
+		"pdf_extract":    "📄",
+		"file_read":      "📖",
+		"file_write":     "📝",
+		"sql_query":      "🗄️",
 	}
 	if icon, ok := icons[name]; ok {
 		return icon
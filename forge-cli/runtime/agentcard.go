@@ -18,14 +18,21 @@ import (
 func BuildAgentCard(workDir string, cfg *types.ForgeConfig, port int) (*a2a.AgentCard, error) {
 	baseURL := fmt.Sprintf("http://localhost:%d", port)
 
-	// Try loading from a prior build
+	// Try loading from a prior build, falling back to forge.yaml config
 	card, err := agentCardFromDisk(workDir, baseURL)
-	if err == nil && card != nil {
-		return card, nil
+	if err != nil || card == nil {
+		card = coreruntime.AgentCardFromConfig(cfg, baseURL)
 	}
 
-	// Fall back to forge.yaml config
-	return coreruntime.AgentCardFromConfig(cfg, baseURL), nil
+	// Push notifications are served by this runtime, whatever the build says
+	push := cfg != nil && cfg.PushNotifications.Enabled
+	if card.Capabilities == nil && push {
+		card.Capabilities = &a2a.AgentCapabilities{}
+	}
+	if card.Capabilities != nil {
+		card.Capabilities.PushNotifications = push
+	}
+	return card, nil
 }
 
 func agentCardFromDisk(workDir string, baseURL string) (*a2a.AgentCard, error) {
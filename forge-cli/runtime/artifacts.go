@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// exportArtifacts writes the artifacts of a completed task to
+// <dir>/<task-id>/. Text parts are written as <name>.txt, data parts as
+// <name>.json, and inline file parts under the file's own name (or the
+// artifact name). File parts that only carry a URI are skipped.
+func exportArtifacts(dir string, task *a2a.Task) ([]string, error) {
+	taskDir := filepath.Join(dir, safeArtifactName(task.ID, "task"))
+	var written []string
+	used := make(map[string]bool)
+
+	for i, art := range task.Artifacts {
+		base := safeArtifactName(art.Name, fmt.Sprintf("artifact-%d", i+1))
+		for _, part := range art.Parts {
+			var name string
+			var data []byte
+			switch part.Kind {
+			case a2a.PartKindText:
+				name, data = base+".txt", []byte(part.Text)
+			case a2a.PartKindData:
+				b, err := json.MarshalIndent(part.Data, "", "  ")
+				if err != nil {
+					return written, fmt.Errorf("encoding artifact %s: %w", base, err)
+				}
+				name, data = base+".json", b
+			case a2a.PartKindFile:
+				if part.File == nil || part.File.Bytes == nil {
+					continue
+				}
+				name, data = safeArtifactName(part.File.Name, base), part.File.Bytes
+			default:
+				continue
+			}
+
+			name = uniqueArtifactName(name, used)
+			if len(written) == 0 {
+				if err := os.MkdirAll(taskDir, 0755); err != nil {
+					return written, fmt.Errorf("creating artifacts directory: %w", err)
+				}
+			}
+			path := filepath.Join(taskDir, name)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return written, fmt.Errorf("writing artifact %s: %w", name, err)
+			}
+			written = append(written, path)
+		}
+	}
+	return written, nil
+}
+
+// safeArtifactName reduces name to its final path element so artifacts
+// cannot escape the export directory. Empty or unusable names get fallback.
+func safeArtifactName(name, fallback string) string {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimLeft(name, ".")
+	if name == "" || name == "/" || !filepath.IsLocal(name) {
+		return fallback
+	}
+	return name
+}
+
+// uniqueArtifactName appends -2, -3, ... before the extension when name has
+// already been written for this task.
+func uniqueArtifactName(name string, used map[string]bool) string {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	candidate := name
+	for n := 2; used[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d%s", stem, n, ext)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// saveArtifacts exports a completed task's artifacts when --artifacts-dir is
+// set. Failures are logged rather than failing the task.
+func (r *Runner) saveArtifacts(task *a2a.Task) {
+	if r.cfg.ArtifactsDir == "" {
+		return
+	}
+	written, err := exportArtifacts(r.cfg.ArtifactsDir, task)
+	if err != nil {
+		r.logger.Warn("artifact export failed", map[string]any{"task_id": task.ID, "error": err.Error()})
+	}
+	if len(written) > 0 {
+		r.logger.Info("artifacts exported", map[string]any{"task_id": task.ID, "files": written})
+	}
+}
@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// fileExecutor replies with a text part and an inline file part.
+type fileExecutor struct{}
+
+func (e *fileExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	return &a2a.Message{
+		Role: a2a.MessageRoleAgent,
+		Parts: []a2a.Part{
+			a2a.NewTextPart("Report attached."),
+			a2a.NewFilePart("../report.csv", "text/csv", []byte("a,b\n1,2\n")),
+		},
+	}, nil
+}
+
+func (e *fileExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *fileExecutor) Close() error { return nil }
+
+func TestRunner_ArtifactsDir(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	runner, err := NewRunner(RunnerConfig{
+		Config:       &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:         port,
+		ArtifactsDir: dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURL := startHandlerServer(t, runner, &fileExecutor{})
+
+	task := sendTask(t, baseURL, a2a.SendTaskParams{
+		ID:      "export-1",
+		Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("build the report")}},
+	})
+	if task.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("state = %q, want completed", task.Status.State)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "export-1", "report.csv"))
+	if err != nil {
+		t.Fatalf("reading exported file: %v", err)
+	}
+	if string(got) != "a,b\n1,2\n" {
+		t.Errorf("file contents = %q", got)
+	}
+	text, err := os.ReadFile(filepath.Join(dir, "export-1", "response.txt"))
+	if err != nil {
+		t.Fatalf("reading exported text: %v", err)
+	}
+	if string(text) != "Report attached." {
+		t.Errorf("text contents = %q", text)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "report.csv")); !os.IsNotExist(err) {
+		t.Error("artifact name escaped the task directory")
+	}
+}
+
+func TestSafeArtifactName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"../../etc/passwd", "passwd"},
+		{"/abs/path.txt", "path.txt"},
+		{"..", "fallback"},
+		{"  ", "fallback"},
+		{".hidden", "hidden"},
+		{`dir\file`, "file"},
+		{"/", "fallback"},
+	}
+	for _, tt := range tests {
+		if got := safeArtifactName(tt.name, "fallback"); got != tt.want {
+			t.Errorf("safeArtifactName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
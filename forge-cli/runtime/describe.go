@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Describe writes the system prompt and tool definitions the LLM executor
+// would receive, without starting the server. Subprocess agents define their
+// own tools, so for them only the tools listed in forge.yaml are shown.
+func (r *Runner) Describe(w io.Writer) error {
+	switch r.cfg.Config.Framework {
+	case "crewai", "langchain":
+		fmt.Fprintf(w, "Framework %s defines its tools in the agent process.\n\n", r.cfg.Config.Framework) //nolint:errcheck
+		fmt.Fprintf(w, "Configured tools (%d):\n", len(r.cfg.Config.Tools))                                //nolint:errcheck
+		for _, name := range r.configToolNames() {
+			fmt.Fprintf(w, "  - %s\n", name) //nolint:errcheck
+		}
+		return nil
+	}
+
+	reg := r.buildToolRegistry()
+	fmt.Fprintf(w, "System prompt:\n%s\n", indent(r.systemPrompt(reg), "  ")) //nolint:errcheck
+
+	defs := reg.ToolDefinitions()
+	fmt.Fprintf(w, "\nTools (%d):\n", len(defs)) //nolint:errcheck
+	for _, def := range defs {
+		fmt.Fprintf(w, "\n  %s\n", def.Function.Name) //nolint:errcheck
+		if def.Function.Description != "" {
+			fmt.Fprintf(w, "%s\n", indent(def.Function.Description, "    ")) //nolint:errcheck
+		}
+		schema := def.Function.Parameters
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, schema, "", "  "); err == nil {
+			schema = buf.Bytes()
+		}
+		if len(schema) > 0 {
+			fmt.Fprintf(w, "    schema:\n%s\n", indent(string(schema), "      ")) //nolint:errcheck
+		}
+	}
+	return nil
+}
+
+// indent prefixes every line of s with prefix.
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
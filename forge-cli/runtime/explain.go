@@ -0,0 +1,92 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// maxTranscripts bounds how many task transcripts the runner keeps for
+// tasks/explain. The oldest are dropped first.
+const maxTranscripts = 500
+
+// TaskExplanation is the result of tasks/explain: the recorded steps of the
+// agent loop for a task, in the order they happened.
+type TaskExplanation struct {
+	TaskID string                        `json:"task_id"`
+	State  a2a.TaskState                 `json:"state"`
+	Steps  []coreruntime.TranscriptEntry `json:"steps"`
+}
+
+// transcriptLog holds the transcripts of recent tasks. Its zero value is
+// ready to use.
+type transcriptLog struct {
+	mu    sync.Mutex
+	byID  map[string]*coreruntime.Transcript
+	order []string
+}
+
+// start returns the transcript for a task, creating it on first use. A task
+// resumed after input-required keeps adding to the same transcript.
+func (l *transcriptLog) start(taskID string) *coreruntime.Transcript {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t, ok := l.byID[taskID]; ok {
+		return t
+	}
+	if l.byID == nil {
+		l.byID = make(map[string]*coreruntime.Transcript)
+	}
+	t := &coreruntime.Transcript{}
+	l.byID[taskID] = t
+	l.order = append(l.order, taskID)
+	for len(l.order) > maxTranscripts {
+		delete(l.byID, l.order[0])
+		l.order = l.order[1:]
+	}
+	return t
+}
+
+// get returns the transcript for a task, or nil if none was recorded.
+func (l *transcriptLog) get(taskID string) *coreruntime.Transcript {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.byID[taskID]
+}
+
+// withTranscript records the agent loop steps of a task run with ctx when
+// explain is enabled in forge.yaml.
+func (r *Runner) withTranscript(ctx context.Context, taskID string) context.Context {
+	if !r.cfg.Config.Explain {
+		return ctx
+	}
+	return coreruntime.WithTranscript(ctx, r.transcripts.start(taskID))
+}
+
+// registerExplainHandler adds tasks/explain, which returns the recorded LLM
+// calls and tool calls of a task so authors can see why it answered as it
+// did. Tasks run by subprocess agents have no steps.
+func (r *Runner) registerExplainHandler(srv *server.Server) {
+	store := srv.TaskStore()
+	srv.RegisterHandler("tasks/explain", func(ctx context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
+		var params a2a.GetTaskParams
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
+		}
+
+		task := store.Get(params.ID)
+		if task == nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "task not found: "+params.ID)
+		}
+
+		explanation := &TaskExplanation{TaskID: task.ID, State: task.Status.State, Steps: []coreruntime.TranscriptEntry{}}
+		if t := r.transcripts.get(task.ID); t != nil {
+			explanation.Steps = t.Entries()
+		}
+		return a2a.NewResponse(id, explanation)
+	})
+}
@@ -0,0 +1,182 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// scriptedClient returns its responses in order, one per Chat call.
+type scriptedClient struct {
+	mu        sync.Mutex
+	responses []*llm.ChatResponse
+}
+
+func (c *scriptedClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.responses) == 0 {
+		return nil, fmt.Errorf("no scripted response left")
+	}
+	resp := c.responses[0]
+	c.responses = c.responses[1:]
+	return resp, nil
+}
+
+func (c *scriptedClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (c *scriptedClient) ModelID() string { return "scripted" }
+
+// explainTask calls tasks/explain and returns the explanation, or the
+// JSON-RPC error if there is one.
+func explainTask(t *testing.T, baseURL, taskID string) (*TaskExplanation, *a2a.JSONRPCError) {
+	t.Helper()
+	body, _ := json.Marshal(a2a.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      "explain",
+		Method:  "tasks/explain",
+		Params:  mustMarshal(a2a.GetTaskParams{ID: taskID}),
+	})
+	resp, err := http.Post(baseURL+"/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("tasks/explain: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var rpcResp struct {
+		Result *TaskExplanation  `json:"result"`
+		Error  *a2a.JSONRPCError `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return rpcResp.Result, rpcResp.Error
+}
+
+func TestRunner_ExplainTask(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py", Explain: true},
+		Port:   port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg := tools.NewRegistry()
+	if err := builtins.RegisterAll(reg); err != nil {
+		t.Fatal(err)
+	}
+	client := &scriptedClient{responses: []*llm.ChatResponse{
+		{
+			Message: llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{
+				ID: "call-1", Type: "function",
+				Function: llm.FunctionCall{Name: "math_calculate", Arguments: `{"expression":"6*7"}`},
+			}}},
+			FinishReason: "tool_calls",
+		},
+		{
+			Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "The answer is 42."},
+			FinishReason: "stop",
+		},
+	}}
+	executor := coreruntime.NewLLMExecutor(coreruntime.LLMExecutorConfig{Client: client, Tools: reg, SystemPrompt: "You do math."})
+	baseURL := startHandlerServer(t, runner, executor)
+
+	task := sendTask(t, baseURL, a2a.SendTaskParams{
+		ID:      "explain-1",
+		Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("what is 6 times 7?")}},
+	})
+	if task.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("task state = %s, want completed", task.Status.State)
+	}
+
+	exp, rpcErr := explainTask(t, baseURL, "explain-1")
+	if rpcErr != nil {
+		t.Fatalf("tasks/explain error: %s", rpcErr.Message)
+	}
+	if exp.TaskID != "explain-1" || exp.State != a2a.TaskStateCompleted {
+		t.Errorf("explanation = %s/%s", exp.TaskID, exp.State)
+	}
+
+	wantTypes := []string{coreruntime.TranscriptLLMCall, coreruntime.TranscriptToolCall, coreruntime.TranscriptLLMCall}
+	if len(exp.Steps) != len(wantTypes) {
+		t.Fatalf("got %d steps, want %d: %+v", len(exp.Steps), len(wantTypes), exp.Steps)
+	}
+	for i, want := range wantTypes {
+		if exp.Steps[i].Type != want {
+			t.Errorf("step %d type = %s, want %s", i, exp.Steps[i].Type, want)
+		}
+	}
+
+	first, tool, second := exp.Steps[0], exp.Steps[1], exp.Steps[2]
+	if first.Iteration != 1 || first.FinishReason != "tool_calls" || len(first.ToolCalls) != 1 {
+		t.Errorf("first LLM call = %+v", first)
+	}
+	if len(first.Messages) != 2 || first.Messages[0].Content != "You do math." || first.Messages[1].Content != "what is 6 times 7?" {
+		t.Errorf("first call messages = %+v", first.Messages)
+	}
+	if tool.ToolName != "math_calculate" || tool.ToolCallID != "call-1" || tool.ToolOutput != "42" || tool.IsError {
+		t.Errorf("tool call = %+v", tool)
+	}
+	if second.Iteration != 2 || second.FinishReason != "stop" || second.Content != "The answer is 42." {
+		t.Errorf("second LLM call = %+v", second)
+	}
+	if n := len(second.Messages); n != 4 || second.Messages[n-1].Content != "42" {
+		t.Errorf("second call messages = %+v", second.Messages)
+	}
+
+	if _, rpcErr := explainTask(t, baseURL, "missing"); rpcErr == nil {
+		t.Error("expected an error for an unknown task")
+	}
+}
+
+func TestRunner_ExplainDisabledByDefault(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:   port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &scriptedClient{responses: []*llm.ChatResponse{{
+		Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "hi"},
+		FinishReason: "stop",
+	}}}
+	executor := coreruntime.NewLLMExecutor(coreruntime.LLMExecutorConfig{Client: client, Tools: tools.NewRegistry()})
+	baseURL := startHandlerServer(t, runner, executor)
+
+	sendTask(t, baseURL, a2a.SendTaskParams{
+		ID:      "explain-off",
+		Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hello")}},
+	})
+
+	_, rpcErr := explainTask(t, baseURL, "explain-off")
+	if rpcErr == nil || rpcErr.Code != a2a.ErrCodeMethodNotFound {
+		t.Errorf("tasks/explain error = %+v, want method not found", rpcErr)
+	}
+	if runner.transcripts.get("explain-off") != nil {
+		t.Error("transcript recorded with explain off")
+	}
+}
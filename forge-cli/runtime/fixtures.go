@@ -0,0 +1,236 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/llm/providers"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// TestFixture is a recorded agent conversation run by forge test: an input
+// message, the model responses to replay, canned tool outputs, and the
+// assertions the final reply must satisfy.
+type TestFixture struct {
+	Name      string            `yaml:"name"`
+	Input     string            `yaml:"input"`
+	Responses []FixtureResponse `yaml:"responses"`
+	Tools     map[string]string `yaml:"tools,omitempty"` // tool name -> output returned to the model
+	Expect    FixtureExpect     `yaml:"expect"`
+
+	Path string `yaml:"-"`
+}
+
+// FixtureResponse is one scripted model response.
+type FixtureResponse struct {
+	Content   string            `yaml:"content,omitempty"`
+	ToolCalls []FixtureToolCall `yaml:"tool_calls,omitempty"`
+}
+
+// FixtureToolCall is a tool call made by a scripted response.
+type FixtureToolCall struct {
+	Name      string         `yaml:"name"`
+	Arguments map[string]any `yaml:"arguments,omitempty"`
+}
+
+// FixtureExpect holds the assertions on the agent's final reply. Every
+// assertion that is set must hold.
+type FixtureExpect struct {
+	Text     string   `yaml:"text,omitempty"`     // exact match, ignoring surrounding whitespace
+	Contains []string `yaml:"contains,omitempty"` // substrings that must all appear
+	Regex    string   `yaml:"regex,omitempty"`    // Go regular expression the reply must match
+}
+
+// FixtureResult is the outcome of running one fixture.
+type FixtureResult struct {
+	Fixture  *TestFixture
+	Reply    string
+	Failures []string // empty when the fixture passed
+}
+
+// Passed reports whether every assertion held.
+func (r FixtureResult) Passed() bool { return len(r.Failures) == 0 }
+
+// LoadTestFixtures reads every *.yaml and *.yml fixture in dir, sorted by
+// file name.
+func LoadTestFixtures(dir string) ([]*TestFixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading test directory: %w", err)
+	}
+	var fixtures []*TestFixture
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		f, err := loadTestFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+func loadTestFixture(path string) (*TestFixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+	// Unknown fields are rejected, so a misspelled assertion is not
+	// silently skipped
+	var f TestFixture
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+	}
+	f.Path = path
+	if f.Name == "" {
+		f.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if f.Input == "" {
+		return nil, fmt.Errorf("fixture %s: input is required", path)
+	}
+	if len(f.Responses) == 0 {
+		return nil, fmt.Errorf("fixture %s: at least one response is required", path)
+	}
+	if f.Expect.Text == "" && len(f.Expect.Contains) == 0 && f.Expect.Regex == "" {
+		return nil, fmt.Errorf("fixture %s: expect needs at least one of text, contains, or regex", path)
+	}
+	if f.Expect.Regex != "" {
+		if _, err := regexp.Compile(f.Expect.Regex); err != nil {
+			return nil, fmt.Errorf("fixture %s: invalid expect.regex: %w", path, err)
+		}
+	}
+	return &f, nil
+}
+
+// chatResponses converts the fixture's scripted responses for the client.
+func (f *TestFixture) chatResponses() ([]*llm.ChatResponse, error) {
+	resps := make([]*llm.ChatResponse, len(f.Responses))
+	for i, r := range f.Responses {
+		msg := llm.ChatMessage{Role: llm.RoleAssistant, Content: r.Content}
+		for j, tc := range r.ToolCalls {
+			args := []byte("{}")
+			if tc.Arguments != nil {
+				var err error
+				if args, err = json.Marshal(tc.Arguments); err != nil {
+					return nil, fmt.Errorf("response %d: tool call %s: %w", i+1, tc.Name, err)
+				}
+			}
+			msg.ToolCalls = append(msg.ToolCalls, llm.ToolCall{
+				ID:       fmt.Sprintf("call_%d_%d", i+1, j+1),
+				Type:     "function",
+				Function: llm.FunctionCall{Name: tc.Name, Arguments: string(args)},
+			})
+		}
+		resps[i] = &llm.ChatResponse{Message: msg}
+	}
+	return resps, nil
+}
+
+// check applies the fixture's assertions to reply.
+func (f *TestFixture) check(reply string) []string {
+	var failures []string
+	if f.Expect.Text != "" && strings.TrimSpace(reply) != strings.TrimSpace(f.Expect.Text) {
+		failures = append(failures, fmt.Sprintf("reply = %q, want %q", reply, f.Expect.Text))
+	}
+	for _, s := range f.Expect.Contains {
+		if !strings.Contains(reply, s) {
+			failures = append(failures, fmt.Sprintf("reply does not contain %q", s))
+		}
+	}
+	if f.Expect.Regex != "" && !regexp.MustCompile(f.Expect.Regex).MatchString(reply) {
+		failures = append(failures, fmt.Sprintf("reply does not match /%s/", f.Expect.Regex))
+	}
+	return failures
+}
+
+// fixtureTools offers the agent's real tool definitions to the model but
+// answers calls with the fixture's canned outputs, so tests never reach the
+// network or the file system.
+type fixtureTools struct {
+	defs    []llm.ToolDefinition
+	outputs map[string]string
+}
+
+func (t *fixtureTools) Execute(_ context.Context, name string, _ json.RawMessage) (string, error) {
+	out, ok := t.outputs[name]
+	if !ok {
+		return "", fmt.Errorf("no fixture output for tool %q", name)
+	}
+	return out, nil
+}
+
+func (t *fixtureTools) ToolDefinitions() []llm.ToolDefinition { return t.defs }
+
+// RunFixture runs f through the LLM executor with the agent's system prompt
+// and tool definitions, replaying the fixture's responses in place of the
+// model. Failing to consume every scripted response also fails the fixture,
+// since the agent then took a different path than the one recorded.
+func (r *Runner) RunFixture(ctx context.Context, f *TestFixture) FixtureResult {
+	result := FixtureResult{Fixture: f}
+	resps, err := f.chatResponses()
+	if err != nil {
+		result.Failures = append(result.Failures, err.Error())
+		return result
+	}
+	client := providers.NewScriptedClient(resps)
+
+	// The executor hides model errors behind a generic message; keep the
+	// raw one for the report.
+	var llmErr error
+	hooks := coreruntime.NewHookRegistry()
+	hooks.Register(coreruntime.OnError, func(_ context.Context, hctx *coreruntime.HookContext) error {
+		llmErr = hctx.Error
+		return nil
+	})
+
+	reg := r.buildToolRegistry()
+	executor := coreruntime.NewLLMExecutor(coreruntime.LLMExecutorConfig{
+		Client:         client,
+		Hooks:          hooks,
+		Tools:          &fixtureTools{defs: reg.ToolDefinitions(), outputs: f.Tools},
+		SystemPrompt:   r.systemPrompt(reg),
+		ResponseFormat: r.responseFormat(),
+		ParallelTools:  r.cfg.Config.ParallelTools,
+		MaxIterations:  len(resps) + 1,
+	})
+	defer executor.Close() //nolint:errcheck
+
+	task := &a2a.Task{ID: "test-" + f.Name}
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(f.Input)}}
+	reply, err := executor.Execute(ctx, task, msg)
+	if err != nil {
+		if llmErr != nil {
+			err = llmErr
+		}
+		result.Failures = append(result.Failures, fmt.Sprintf("agent error: %v", err))
+		return result
+	}
+	for _, p := range reply.Parts {
+		if p.Kind == a2a.PartKindText {
+			result.Reply += p.Text
+		}
+	}
+
+	result.Failures = f.check(result.Reply)
+	if n := client.Remaining(); n > 0 {
+		result.Failures = append(result.Failures, fmt.Sprintf("%d scripted response(s) were not used", n))
+	}
+	return result
+}
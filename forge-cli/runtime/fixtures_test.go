@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func newFixtureRunner(t *testing.T) *Runner {
+	t.Helper()
+	runner, err := NewRunner(RunnerConfig{
+		Config:  &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		WorkDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runner
+}
+
+func TestLoadTestFixtures(t *testing.T) {
+	fixtures, err := LoadTestFixtures(filepath.Join("testdata", "fixtures"))
+	if err != nil {
+		t.Fatalf("LoadTestFixtures() error: %v", err)
+	}
+	if len(fixtures) != 2 {
+		t.Fatalf("loaded %d fixtures, want 2", len(fixtures))
+	}
+	// Unnamed fixtures take their file name.
+	if fixtures[0].Name != "greeting" {
+		t.Errorf("fixtures[0].Name = %q, want greeting", fixtures[0].Name)
+	}
+	weather := fixtures[1]
+	if weather.Name != "answers with the forecast" {
+		t.Errorf("fixtures[1].Name = %q", weather.Name)
+	}
+	if len(weather.Responses) != 2 || len(weather.Responses[0].ToolCalls) != 1 {
+		t.Fatalf("responses = %+v", weather.Responses)
+	}
+	if got := weather.Responses[0].ToolCalls[0].Arguments["query"]; got != "weather in Paris" {
+		t.Errorf("tool call query = %v", got)
+	}
+}
+
+func TestLoadTestFixtures_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no input":      "responses:\n  - content: hi\nexpect:\n  text: hi\n",
+		"no responses":  "input: hi\nexpect:\n  text: hi\n",
+		"bad regex":     "input: hi\nresponses:\n  - content: hi\nexpect:\n  regex: '('\n",
+		"no assertions": "input: hi\nresponses:\n  - content: hi\n",
+		"unknown field": "input: hi\nresponses:\n  - content: hi\nexpect:\n  contain: [hi]\n",
+		"empty":         "",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadTestFixtures(dir); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestRunFixture_Passes(t *testing.T) {
+	fixtures, err := LoadTestFixtures(filepath.Join("testdata", "fixtures"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := newFixtureRunner(t)
+	for _, f := range fixtures {
+		res := runner.RunFixture(context.Background(), f)
+		if !res.Passed() {
+			t.Errorf("fixture %q failed: %v (reply %q)", f.Name, res.Failures, res.Reply)
+		}
+	}
+}
+
+func TestRunFixture_ReportsFailures(t *testing.T) {
+	f := &TestFixture{
+		Name:  "wrong answer",
+		Input: "What's 2+2?",
+		Responses: []FixtureResponse{
+			{ToolCalls: []FixtureToolCall{{Name: "calculator"}}},
+			{Content: "It is 5."},
+			{Content: "never reached"},
+		},
+		Expect: FixtureExpect{Text: "It is 4.", Contains: []string{"4"}, Regex: `^It is \d\.$`},
+	}
+	res := newFixtureRunner(t).RunFixture(context.Background(), f)
+	if res.Reply != "It is 5." {
+		t.Errorf("Reply = %q, want %q", res.Reply, "It is 5.")
+	}
+	got := strings.Join(res.Failures, "\n")
+	for _, want := range []string{`want "It is 4."`, `does not contain "4"`, "1 scripted response(s) were not used"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("failures missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "does not match") {
+		t.Errorf("regex assertion should pass:\n%s", got)
+	}
+}
+
+func TestRunFixture_ScriptExhausted(t *testing.T) {
+	f := &TestFixture{
+		Name:      "loops",
+		Input:     "hi",
+		Responses: []FixtureResponse{{ToolCalls: []FixtureToolCall{{Name: "web_search"}}}},
+		Tools:     map[string]string{"web_search": "{}"},
+	}
+	res := newFixtureRunner(t).RunFixture(context.Background(), f)
+	if res.Passed() || !strings.Contains(strings.Join(res.Failures, "\n"), "no response left") {
+		t.Errorf("failures = %v, want script exhausted", res.Failures)
+	}
+}
@@ -0,0 +1,82 @@
+package runtime
+
+import (
+	"time"
+)
+
+// HealthStatus is the detailed payload served on /health.
+type HealthStatus struct {
+	Status        string   `json:"status"`
+	AgentID       string   `json:"agent_id"`
+	Version       string   `json:"version"`
+	Framework     string   `json:"framework,omitempty"`
+	Executor      string   `json:"executor"`
+	Provider      string   `json:"provider,omitempty"`
+	Model         string   `json:"model,omitempty"`
+	LLMReady      bool     `json:"llm_ready"`
+	Tools         []string `json:"tools"`
+	Channels      []string `json:"channels"`
+	EgressProfile string   `json:"egress_profile"`
+	EgressMode    string   `json:"egress_mode"`
+	UptimeSeconds float64  `json:"uptime_seconds"`
+	Error         string   `json:"error,omitempty"` // why Status is "unavailable"
+}
+
+// healthState records what the runner resolved at startup for /health.
+type healthState struct {
+	startedAt time.Time
+	executor  string
+	provider  string
+	model     string
+	llmReady  bool
+	tools     []string
+	check     func() error // reports a backend that can no longer serve; nil when always ready
+}
+
+// healthErr returns why the agent cannot serve requests, or nil.
+func (r *Runner) healthErr() error {
+	if r.health.check == nil {
+		return nil
+	}
+	return r.health.check()
+}
+
+// healthStatus builds the current HealthStatus.
+func (r *Runner) healthStatus() HealthStatus {
+	tools := r.health.tools
+	if tools == nil {
+		tools = []string{}
+	}
+	channels := r.cfg.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+	status, errMsg := "ok", ""
+	if err := r.healthErr(); err != nil {
+		status, errMsg = "unavailable", err.Error()
+	}
+	return HealthStatus{
+		Status:        status,
+		AgentID:       r.cfg.Config.AgentID,
+		Version:       r.cfg.Config.Version,
+		Framework:     r.cfg.Config.Framework,
+		Executor:      r.health.executor,
+		Provider:      r.health.provider,
+		Model:         r.health.model,
+		LLMReady:      r.health.llmReady,
+		Tools:         tools,
+		Channels:      channels,
+		EgressProfile: defaultStr(r.cfg.Config.Egress.Profile, "strict"),
+		EgressMode:    defaultStr(r.cfg.Config.Egress.Mode, "deny-all"),
+		UptimeSeconds: time.Since(r.health.startedAt).Seconds(),
+		Error:         errMsg,
+	}
+}
+
+func (r *Runner) configToolNames() []string {
+	names := make([]string, 0, len(r.cfg.Config.Tools))
+	for _, t := range r.cfg.Config.Tools {
+		names = append(names, t.Name)
+	}
+	return names
+}
@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// defaultHelpTriggers are the messages answered with the help response when
+// help.triggers is not set. The empty string matches a blank message.
+var defaultHelpTriggers = []string{"", "help", "/help", "/start"}
+
+// helpResponse returns the help message for msg if its text matches a help
+// trigger. Matching ignores case and surrounding whitespace, and a bot
+// mention suffix on slash commands ("/start@my_bot").
+func (r *Runner) helpResponse(msg *a2a.Message, card *a2a.AgentCard) (*a2a.Message, bool) {
+	help := r.cfg.Config.Help
+	if help.Disabled {
+		return nil, false
+	}
+	triggers := help.Triggers
+	if len(triggers) == 0 {
+		triggers = defaultHelpTriggers
+	}
+
+	for _, p := range msg.Parts {
+		if p.Kind != a2a.PartKindText {
+			// Files or data make this more than a bare help request
+			return nil, false
+		}
+	}
+
+	text := strings.ToLower(strings.TrimSpace(messageText(msg)))
+	if strings.HasPrefix(text, "/") {
+		text, _, _ = strings.Cut(text, "@")
+	}
+	for _, t := range triggers {
+		if text == strings.ToLower(strings.TrimSpace(t)) {
+			reply := help.Message
+			if reply == "" {
+				reply = buildHelpText(card)
+			}
+			return &a2a.Message{
+				Role:  a2a.MessageRoleAgent,
+				Parts: []a2a.Part{a2a.NewTextPart(reply)},
+			}, true
+		}
+	}
+	return nil, false
+}
+
+// buildHelpText describes the agent from its card: name, description, and
+// the tools and skills it offers.
+func buildHelpText(card *a2a.AgentCard) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi! I'm %s.", card.Name)
+	if card.Description != "" {
+		fmt.Fprintf(&b, " %s", card.Description)
+	}
+	if len(card.Skills) > 0 {
+		b.WriteString("\n\nI can help with:\n")
+		for _, s := range card.Skills {
+			name := s.Name
+			if name == "" {
+				name = s.ID
+			}
+			if s.Description != "" {
+				fmt.Fprintf(&b, "- %s: %s\n", name, s.Description)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", name)
+			}
+		}
+	} else {
+		b.WriteString("\n")
+	}
+	b.WriteString("\nTell me what you need and I'll get started.")
+	return b.String()
+}
+
+// messageText joins the text parts of msg.
+func messageText(msg *a2a.Message) string {
+	var parts []string
+	for _, p := range msg.Parts {
+		if p.Kind == a2a.PartKindText {
+			parts = append(parts, p.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
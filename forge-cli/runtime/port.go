@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PortFile is where forge run records the port it is serving on, relative
+// to the project directory, so other commands can find the agent.
+var PortFile = filepath.Join(".forge-output", "port")
+
+// Listen binds the server port, asking the OS for a free one when
+// RunnerConfig.AutoPort is set. Run calls it if it has not been called;
+// calling it first lets the caller learn the port before the server starts.
+func (r *Runner) Listen() error {
+	if r.ln != nil {
+		return nil
+	}
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", r.cfg.Port))
+	if err != nil {
+		return fmt.Errorf("listen on :%d: %w", r.cfg.Port, err)
+	}
+	r.ln = ln
+	r.cfg.Port = ln.Addr().(*net.TCPAddr).Port
+	return nil
+}
+
+// Port returns the server port. With AutoPort it is 0 until Listen is
+// called.
+func (r *Runner) Port() int { return r.cfg.Port }
+
+// writePortFile records port in the project's PortFile and returns a
+// function removing it again, unless another runner has replaced it since.
+func writePortFile(workDir string, port int) (func(), error) {
+	path := filepath.Join(workDir, PortFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+	content := strconv.Itoa(port) + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return nil, fmt.Errorf("writing port file: %w", err)
+	}
+	return func() {
+		if data, err := os.ReadFile(path); err == nil && string(data) == content {
+			_ = os.Remove(path)
+		}
+	}, nil
+}
+
+// ReadPortFile returns the port recorded by a running forge run in
+// workDir.
+func ReadPortFile(workDir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(workDir, PortFile))
+	if err != nil {
+		return 0, err
+	}
+	port, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || port <= 0 {
+		return 0, fmt.Errorf("invalid port file %s: %q", PortFile, strings.TrimSpace(string(data)))
+	}
+	return port, nil
+}
@@ -0,0 +1,118 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestRunner_AutoPortDoesNotCollide(t *testing.T) {
+	type started struct {
+		runner *Runner
+		dir    string
+		errCh  chan error
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runners []started
+	for i := range 2 {
+		dir := t.TempDir()
+		runner, err := NewRunner(RunnerConfig{
+			Config:    &types.ForgeConfig{AgentID: fmt.Sprintf("agent-%d", i), Version: "0.1.0", Entrypoint: "main.py"},
+			WorkDir:   dir,
+			Port:      8080, // ignored with AutoPort
+			AutoPort:  true,
+			MockTools: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if runner.Port() != 0 {
+			t.Errorf("Port() before Listen = %d, want 0", runner.Port())
+		}
+		s := started{runner: runner, dir: dir, errCh: make(chan error, 1)}
+		go func() { s.errCh <- runner.Run(ctx) }()
+		runners = append(runners, s)
+	}
+
+	ports := make(map[int]bool)
+	for _, s := range runners {
+		// Run binds before it writes the port file.
+		var port int
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			p, err := ReadPortFile(s.dir)
+			if err == nil {
+				port = p
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("port file not written: %v", err)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if port == 8080 || ports[port] {
+			t.Fatalf("port %d reused; ports so far %v", port, ports)
+		}
+		ports[port] = true
+
+		baseURL := fmt.Sprintf("http://localhost:%d", port)
+		waitForServer(t, baseURL, 5*time.Second)
+
+		resp, err := http.Get(baseURL + "/.well-known/agent.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var card a2a.AgentCard
+		json.NewDecoder(resp.Body).Decode(&card) //nolint:errcheck
+		_ = resp.Body.Close()
+		if card.URL != baseURL {
+			t.Errorf("agent card URL = %q, want %q", card.URL, baseURL)
+		}
+	}
+
+	cancel()
+	for _, s := range runners {
+		select {
+		case <-s.errCh:
+		case <-time.After(5 * time.Second):
+			t.Fatal("runner did not stop")
+		}
+		if _, err := os.Stat(filepath.Join(s.dir, PortFile)); !os.IsNotExist(err) {
+			t.Errorf("port file not removed on shutdown: %v", err)
+		}
+	}
+}
+
+func TestReadPortFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ReadPortFile(dir); err == nil {
+		t.Error("expected error without a port file")
+	}
+
+	remove, err := writePortFile(dir, 41234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if port, err := ReadPortFile(dir); err != nil || port != 41234 {
+		t.Errorf("ReadPortFile() = %d, %v; want 41234", port, err)
+	}
+
+	// Another runner took over the file; removing ours leaves it alone.
+	if _, err := writePortFile(dir, 41235); err != nil {
+		t.Fatal(err)
+	}
+	remove()
+	if port, _ := ReadPortFile(dir); port != 41235 {
+		t.Errorf("port file = %d after removing a replaced file, want 41235", port)
+	}
+}
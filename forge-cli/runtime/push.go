@@ -0,0 +1,212 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-cli/server"
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// pushNotifier POSTs a task to the webhook its client registered once the
+// task reaches a terminal state. Webhooks are limited to the configured
+// allowed hosts, if any, and requests go through the egress enforcer.
+type pushNotifier struct {
+	enabled bool
+	hosts   *security.EgressEnforcer // nil allows any host
+	headers map[string]string
+	client  *http.Client
+	logger  coreruntime.Logger
+
+	mu      sync.Mutex
+	configs map[string]a2a.PushNotificationConfig // removed once the task is sent
+}
+
+func newPushNotifier(cfg types.PushNotificationsRef, egress *security.EgressEnforcer, logger coreruntime.Logger) *pushNotifier {
+	p := &pushNotifier{
+		enabled: cfg.Enabled,
+		client:  &http.Client{Timeout: 10 * time.Second, Transport: egress.Transport(nil)},
+		logger:  logger,
+		configs: make(map[string]a2a.PushNotificationConfig),
+	}
+	switch {
+	case len(cfg.AllowedHosts) > 0:
+		p.hosts = security.NewEgressEnforcer(&security.EgressConfig{Mode: security.ModeAllowlist, AllDomains: cfg.AllowedHosts})
+		p.headers = cfg.Headers
+	case len(cfg.Headers) > 0:
+		logger.Warn("push_notifications.headers ignored without push_notifications.allowed_hosts", nil)
+	}
+	return p
+}
+
+// set registers the webhook for a task.
+func (p *pushNotifier) set(taskID string, cfg a2a.PushNotificationConfig) error {
+	if err := p.check(taskID, cfg); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.configs[taskID] = cfg
+	return nil
+}
+
+// check validates the webhook for a task without registering it.
+func (p *pushNotifier) check(taskID string, cfg a2a.PushNotificationConfig) error {
+	if taskID == "" {
+		return fmt.Errorf("task id is required")
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("push notification url must be an absolute http or https URL")
+	}
+	if p.hosts != nil && !p.hosts.Allowed(u.Hostname()) {
+		return fmt.Errorf("push notification host %q is not in push_notifications.allowed_hosts", u.Hostname())
+	}
+	if auth := cfg.Authentication; auth != nil && auth.Credentials != "" && len(auth.Schemes) == 0 {
+		return fmt.Errorf("push notification authentication needs a scheme, such as Bearer")
+	}
+	return nil
+}
+
+// checkParams validates the webhook a tasks/send request carries, if any.
+func (p *pushNotifier) checkParams(params *a2a.SendTaskParams) error {
+	if params.PushNotification == nil {
+		return nil
+	}
+	if !p.enabled {
+		return fmt.Errorf("push notifications are not enabled")
+	}
+	return p.check(params.ID, *params.PushNotification)
+}
+
+// setFromParams registers the webhook a tasks/send request carries, if any,
+// once checkParams accepted it and the task was claimed.
+func (p *pushNotifier) setFromParams(params *a2a.SendTaskParams) {
+	if params.PushNotification == nil || !p.enabled {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.configs[params.ID] = *params.PushNotification
+}
+
+func (p *pushNotifier) get(taskID string) (a2a.PushNotificationConfig, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cfg, ok := p.configs[taskID]
+	return cfg, ok
+}
+
+// taskUpdated is the task store's update hook. It sends the task once, when
+// it reaches a terminal state, and then forgets the webhook.
+func (p *pushNotifier) taskUpdated(task *a2a.Task) {
+	if !isTerminalState(task.Status.State) {
+		return
+	}
+	p.mu.Lock()
+	cfg, ok := p.configs[task.ID]
+	delete(p.configs, task.ID)
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	// Deliver in the background so the handler's response is not delayed
+	go func() {
+		if err := p.send(cfg, task); err != nil {
+			p.logger.Warn("push notification failed", map[string]any{"task_id": task.ID, "error": err.Error()})
+			return
+		}
+		p.logger.Info("push notification sent", map[string]any{"task_id": task.ID, "state": string(task.Status.State)})
+	}()
+}
+
+func (p *pushNotifier) send(cfg a2a.PushNotificationConfig, task *a2a.Task) error {
+	body, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("encoding task: %w", err)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if auth := cfg.Authentication; auth != nil && auth.Credentials != "" {
+		req.Header.Set("Authorization", strings.TrimSpace(auth.Schemes[0]+" "+auth.Credentials))
+	}
+	// Operator headers, sent only to allowed hosts, take precedence
+	for k, v := range p.headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	if cfg.Token != "" {
+		req.Header.Set("X-A2A-Notification-Token", cfg.Token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// isTerminalState reports whether a task in state s has finished.
+func isTerminalState(s a2a.TaskState) bool {
+	switch s {
+	case a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected:
+		return true
+	}
+	return false
+}
+
+// registerPushHandlers adds tasks/pushNotification/set and get. When push
+// notifications are disabled they answer with the A2A not-supported error.
+func (r *Runner) registerPushHandlers(srv *server.Server, push *pushNotifier) {
+	notSupported := func(id any) *a2a.JSONRPCResponse {
+		return a2a.NewErrorResponse(id, a2a.ErrCodePushNotificationNotSupported,
+			"push notifications are not enabled; set push_notifications.enabled in forge.yaml")
+	}
+
+	srv.RegisterHandler("tasks/pushNotification/set", func(ctx context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
+		if !push.enabled {
+			return notSupported(id)
+		}
+		var params a2a.TaskPushNotificationConfig
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
+		}
+		if err := push.set(params.ID, params.PushNotificationConfig); err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())
+		}
+		r.logger.Info("push notification registered", map[string]any{"task_id": params.ID})
+		return a2a.NewResponse(id, params)
+	})
+
+	srv.RegisterHandler("tasks/pushNotification/get", func(ctx context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
+		if !push.enabled {
+			return notSupported(id)
+		}
+		var params a2a.GetTaskParams
+		if err := json.Unmarshal(rawParams, &params); err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
+		}
+		cfg, ok := push.get(params.ID)
+		if !ok {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "no push notification config for task: "+params.ID)
+		}
+		return a2a.NewResponse(id, a2a.TaskPushNotificationConfig{ID: params.ID, PushNotificationConfig: cfg})
+	})
+}
@@ -0,0 +1,222 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// rpc posts a JSON-RPC request and decodes the response.
+func rpc(t *testing.T, baseURL, method string, params any) a2a.JSONRPCResponse {
+	t.Helper()
+	body, _ := json.Marshal(a2a.JSONRPCRequest{JSONRPC: "2.0", ID: "1", Method: method, Params: mustMarshal(params)})
+	resp, err := http.Post(baseURL+"/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("%s: %v", method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var rpcResp a2a.JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		t.Fatalf("%s: decode: %v", method, err)
+	}
+	return rpcResp
+}
+
+type pushCallback struct {
+	task   a2a.Task
+	header http.Header
+}
+
+func TestRunner_PushNotifications(t *testing.T) {
+	callbacks := make(chan pushCallback, 4)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var task a2a.Task
+		json.NewDecoder(r.Body).Decode(&task) //nolint:errcheck
+		callbacks <- pushCallback{task: task, header: r.Header}
+	}))
+	defer sink.Close()
+
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PUSH_SECRET", "s3cret")
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py",
+			PushNotifications: types.PushNotificationsRef{
+				Enabled:      true,
+				AllowedHosts: []string{"127.0.0.1"},
+				Headers:      map[string]string{"Authorization": "Bearer ${PUSH_SECRET}"},
+			},
+		},
+		Port: port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURL := startHandlerServer(t, runner, &depthExecutor{})
+
+	cfg := a2a.TaskPushNotificationConfig{ID: "t-1", PushNotificationConfig: a2a.PushNotificationConfig{URL: sink.URL, Token: "client-token"}}
+	if resp := rpc(t, baseURL, "tasks/pushNotification/set", cfg); resp.Error != nil {
+		t.Fatalf("set: %+v", resp.Error)
+	}
+	resp := rpc(t, baseURL, "tasks/pushNotification/get", a2a.GetTaskParams{ID: "t-1"})
+	if got, _ := json.Marshal(resp.Result); !bytes.Contains(got, []byte(sink.URL)) {
+		t.Errorf("get = %s", got)
+	}
+
+	sendTask(t, baseURL, a2a.SendTaskParams{ID: "t-1", Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}})
+	select {
+	case cb := <-callbacks:
+		if cb.task.ID != "t-1" || cb.task.Status.State != a2a.TaskStateCompleted {
+			t.Errorf("callback task = %+v", cb.task)
+		}
+		if cb.header.Get("Authorization") != "Bearer s3cret" || cb.header.Get("X-A2A-Notification-Token") != "client-token" {
+			t.Errorf("callback headers = %v", cb.header)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no push notification received")
+	}
+
+	// A config sent with the task works like set
+	sendTask(t, baseURL, a2a.SendTaskParams{
+		ID:               "t-2",
+		Message:          a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}},
+		PushNotification: &a2a.PushNotificationConfig{URL: sink.URL},
+	})
+	select {
+	case cb := <-callbacks:
+		if cb.task.ID != "t-2" {
+			t.Errorf("callback for %s, want only t-2", cb.task.ID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no push notification for t-2")
+	}
+	select {
+	case cb := <-callbacks:
+		t.Errorf("unexpected callback for %s", cb.task.ID)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	card, err := BuildAgentCard(t.TempDir(), runner.cfg.Config, port)
+	if err != nil || card.Capabilities == nil || !card.Capabilities.PushNotifications {
+		t.Errorf("agent card capabilities = %+v, %v", card.Capabilities, err)
+	}
+}
+
+func TestRunner_PushNotificationsDisabled(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:   port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURL := startHandlerServer(t, runner, &depthExecutor{})
+
+	cfg := a2a.TaskPushNotificationConfig{ID: "t-1", PushNotificationConfig: a2a.PushNotificationConfig{URL: "http://localhost:1/"}}
+	resp := rpc(t, baseURL, "tasks/pushNotification/set", cfg)
+	if resp.Error == nil || resp.Error.Code != a2a.ErrCodePushNotificationNotSupported {
+		t.Errorf("set error = %+v, want push notifications not supported", resp.Error)
+	}
+	if card, _ := BuildAgentCard(t.TempDir(), runner.cfg.Config, port); card.Capabilities != nil && card.Capabilities.PushNotifications {
+		t.Error("agent card advertises push notifications")
+	}
+}
+
+func TestClaimTask_DuplicateKeepsWebhook(t *testing.T) {
+	store := a2a.NewMemoryTaskStore()
+	push := newPushNotifier(types.PushNotificationsRef{Enabled: true}, nil, coreruntime.NewJSONLogger(io.Discard, false))
+	store.OnUpdate(push.taskUpdated)
+	sessions := newSessionStore(types.MemoryRef{})
+	params := func(hook string) *a2a.SendTaskParams {
+		return &a2a.SendTaskParams{ID: "t-1", PushNotification: &a2a.PushNotificationConfig{URL: hook}}
+	}
+
+	first := params("http://127.0.0.1:1/hook")
+	if _, ok := claimTask(store, sessions, push, &a2a.Task{ID: "t-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}}, first); !ok {
+		t.Fatal("first submission not claimed")
+	}
+	// A duplicate for the running task does not replace its webhook
+	if _, ok := claimTask(store, sessions, push, &a2a.Task{ID: "t-1"}, params("https://second.example.com/hook")); ok {
+		t.Fatal("duplicate submission claimed")
+	}
+	if cfg, _ := push.get("t-1"); cfg.URL != "http://127.0.0.1:1/hook" {
+		t.Errorf("webhook = %q, want the first submission's", cfg.URL)
+	}
+
+	// Nor is one kept for a finished task, where it would never fire
+	store.UpdateStatus("t-1", a2a.TaskStatus{State: a2a.TaskStateCompleted})
+	if _, ok := claimTask(store, sessions, push, &a2a.Task{ID: "t-1"}, params("https://third.example.com/hook")); ok {
+		t.Fatal("resubmission of a completed task claimed")
+	}
+	if _, ok := push.get("t-1"); ok {
+		t.Error("webhook registered for a completed task")
+	}
+}
+
+func TestPushNotifier_CallerAuthAndAllowedHosts(t *testing.T) {
+	callbacks := make(chan http.Header, 1)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callbacks <- r.Header
+	}))
+	defer sink.Close()
+
+	logger := coreruntime.NewJSONLogger(io.Discard, false)
+	t.Setenv("PUSH_SECRET", "s3cret")
+
+	// Without allowed hosts any URL may be registered, but operator headers
+	// are never sent
+	open := newPushNotifier(types.PushNotificationsRef{Enabled: true, Headers: map[string]string{"X-Secret": "${PUSH_SECRET}"}}, nil, logger)
+	auth := &a2a.AuthenticationInfo{Schemes: []string{"Bearer"}, Credentials: "caller-token"}
+	if err := open.set("t-1", a2a.PushNotificationConfig{URL: sink.URL, Authentication: auth}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	open.taskUpdated(&a2a.Task{ID: "t-1", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}})
+	select {
+	case h := <-callbacks:
+		if h.Get("Authorization") != "Bearer caller-token" {
+			t.Errorf("Authorization = %q, want the caller's credentials", h.Get("Authorization"))
+		}
+		if h.Get("X-Secret") != "" {
+			t.Error("operator header sent to a host that is not allowed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no push notification received")
+	}
+	if _, ok := open.get("t-1"); ok {
+		t.Error("config kept after the task finished")
+	}
+
+	restricted := newPushNotifier(types.PushNotificationsRef{Enabled: true, AllowedHosts: []string{"*.example.com"}}, nil, logger)
+	if err := restricted.set("t-2", a2a.PushNotificationConfig{URL: "https://attacker.test/hook"}); err == nil {
+		t.Error("expected a host outside allowed_hosts to be rejected")
+	}
+	if err := restricted.set("t-2", a2a.PushNotificationConfig{URL: "https://hooks.example.com/hook"}); err != nil {
+		t.Errorf("allowed host rejected: %v", err)
+	}
+}
+
+func TestPushNotifier_EgressEnforced(t *testing.T) {
+	egress := security.NewEgressEnforcer(&security.EgressConfig{Mode: security.ModeDenyAll})
+	p := newPushNotifier(types.PushNotificationsRef{Enabled: true}, egress, coreruntime.NewJSONLogger(io.Discard, false))
+	err := p.send(a2a.PushNotificationConfig{URL: "https://hooks.example.com/hook"}, &a2a.Task{ID: "t-1"})
+	if !errors.Is(err, security.ErrEgressDenied) {
+		t.Errorf("send error = %v, want egress denied", err)
+	}
+}
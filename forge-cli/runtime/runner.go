@@ -3,44 +3,75 @@ package runtime
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/initializ/forge/forge-cli/server"
 	cliskills "github.com/initializ/forge/forge-cli/skills"
 	clitools "github.com/initializ/forge/forge-cli/tools"
 	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/llm"
 	"github.com/initializ/forge/forge-core/llm/providers"
+	"github.com/initializ/forge/forge-core/memory"
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/security"
 	coreskills "github.com/initializ/forge/forge-core/skills"
 	"github.com/initializ/forge/forge-core/tools"
 	"github.com/initializ/forge/forge-core/tools/builtins"
 	"github.com/initializ/forge/forge-core/types"
+	"github.com/initializ/forge/forge-core/validate"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // RunnerConfig holds configuration for the Runner.
 type RunnerConfig struct {
-	Config            *types.ForgeConfig
-	WorkDir           string
-	Port              int
-	MockTools         bool
-	EnforceGuardrails bool
-	ModelOverride     string
-	ProviderOverride  string
-	EnvFilePath       string
-	Verbose           bool
-	Channels          []string // active channel adapters from --with flag
+	Config             *types.ForgeConfig
+	WorkDir            string
+	Port               int
+	AutoPort           bool // listen on a free port chosen by the OS; Port is ignored
+	MockTools          bool
+	EnforceGuardrails  bool
+	ModelOverride      string
+	ProviderOverride   string
+	EnvFilePath        string
+	Verbose            bool
+	Channels           []string        // active channel adapters from --with flag
+	DebugStream        bool            // stream agent loop status to a side channel
+	MaxCallDepth       int             // maximum agent-to-agent delegation depth (default 5)
+	ToolBudget         float64         // per-run tool cost budget; 0 disables
+	MaxHistory         int             // prior task messages replayed per request; 0 uses memory.max_history
+	TaskTimeout        time.Duration   // overall deadline per task; 0 uses task_timeout from forge.yaml
+	ArtifactsDir       string          // directory completed task artifacts are written to; empty disables
+	SessionDir         string          // session state directory kept across subprocess restarts; empty disables
+	TraceOpenInference bool            // export OpenInference traces even when tracing.format is unset
+	SafeMode           bool            // register only read-only tools
+	SafeModeAllow      []string        // mutating tools kept in safe mode
+	ClientPool         *providers.Pool // shared LLM clients; nil uses providers.DefaultPool
 }
 
 // Runner orchestrates the local A2A development server.
 type Runner struct {
 	cfg         RunnerConfig
 	logger      coreruntime.Logger
+	ln          net.Listener
 	cliExecTool *clitools.CLIExecuteTool
+	derivedCLI  *coreskills.DerivedCLIConfig       // cli_execute config implied by skill requirements
+	skillReqs   *coreskills.AggregatedRequirements // requirements declared by skills; nil without a skills file
+	egress      *security.EgressEnforcer           // resolved egress policy; nil enforces nothing
+	memory      *memory.Store                      // backs the memory tools; nil uses keyword search
+	health      healthState
+	usage       sessionUsage
+	transcripts transcriptLog
 }
 
 // NewRunner creates a Runner from the given config.
@@ -48,9 +79,14 @@ func NewRunner(cfg RunnerConfig) (*Runner, error) {
 	if cfg.Config == nil {
 		return nil, fmt.Errorf("config is required")
 	}
-	if cfg.Port <= 0 {
+	if cfg.AutoPort {
+		cfg.Port = 0
+	} else if cfg.Port <= 0 {
 		cfg.Port = 8080
 	}
+	if cfg.MaxCallDepth <= 0 {
+		cfg.MaxCallDepth = a2a.DefaultMaxCallDepth
+	}
 	logger := coreruntime.NewJSONLogger(os.Stderr, cfg.Verbose)
 	return &Runner{cfg: cfg, logger: logger}, nil
 }
@@ -73,98 +109,139 @@ func (r *Runner) Run(ctx context.Context) error {
 		return err
 	}
 
+	// 1c. Resolve the egress policy enforced on tools and webhooks
+	if r.egress, err = r.egressEnforcer(); err != nil {
+		return err
+	}
+
 	// 2. Load policy scaffold
 	scaffold, err := LoadPolicyScaffold(r.cfg.WorkDir)
 	if err != nil {
 		r.logger.Warn("failed to load policy scaffold", map[string]any{"error": err.Error()})
 	}
+	if err := validate.CheckGuardrails(scaffold); err != nil {
+		return err
+	}
 	guardrails := coreruntime.NewGuardrailEngine(scaffold, r.cfg.EnforceGuardrails, r.logger)
+	if guardrails.HasGuardrail("moderation") {
+		r.setupModeration(guardrails, envVars)
+	}
+	r.memory = r.newMemoryStore(envVars)
+
+	// 3. Bind the port and build the agent card for it
+	if err := r.Listen(); err != nil {
+		return err
+	}
+	defer r.ln.Close() //nolint:errcheck
+	if r.cfg.WorkDir != "" {
+		removePortFile, err := writePortFile(r.cfg.WorkDir, r.cfg.Port)
+		if err != nil {
+			r.logger.Warn("failed to write port file", map[string]any{"error": err.Error()})
+		} else {
+			defer removePortFile()
+		}
+	}
 
-	// 3. Build agent card
 	card, err := BuildAgentCard(r.cfg.WorkDir, r.cfg.Config, r.cfg.Port)
 	if err != nil {
 		return fmt.Errorf("building agent card: %w", err)
 	}
 
+	r.health.startedAt = time.Now()
+
+	// Both formats are OpenTelemetry spans. OpenInference spans come from
+	// hooks around any executor; otel spans are recorded by the LLM executor
+	// itself
+	var oiTracer, otelTracer trace.Tracer
+	if provider := r.newTracerProvider(); provider != nil {
+		defer r.shutdownTracer(provider)
+		if r.traceFormat() == "otel" {
+			otelTracer = provider.Tracer(otelScope)
+		} else {
+			oiTracer = provider.Tracer(otelScope)
+		}
+	}
+
 	// 4. Choose executor and optional lifecycle runtime
 	var executor coreruntime.AgentExecutor
 	var lifecycle coreruntime.AgentRuntime // optional, for subprocess lifecycle management
 	if r.cfg.MockTools {
 		toolSpecs := r.loadToolSpecs()
 		executor = NewMockExecutor(toolSpecs)
+		r.health.executor = "mock"
+		r.health.tools = r.configToolNames()
 		r.logger.Info("using mock executor", map[string]any{"tools": len(toolSpecs)})
 	} else {
 		switch r.cfg.Config.Framework {
 		case "crewai", "langchain":
 			rt := NewSubprocessRuntime(r.cfg.Config.Entrypoint, r.cfg.WorkDir, envVars, r.logger)
+			if r.cfg.SessionDir != "" {
+				dir := r.cfg.SessionDir
+				if !filepath.IsAbs(dir) {
+					dir = filepath.Join(r.cfg.WorkDir, dir)
+				}
+				rt.SetSessionDir(dir)
+			}
+			readyTimeout, _ := time.ParseDuration(r.cfg.Config.Readiness.Timeout)
+			rt.SetReadiness(r.cfg.Config.Readiness.Path, readyTimeout)
 			lifecycle = rt
 			executor = NewSubprocessExecutor(rt)
+			if r.cfg.SafeMode {
+				r.logger.Warn("safe mode has no effect on subprocess agents; their tools run in the agent process", map[string]any{
+					"framework": r.cfg.Config.Framework,
+				})
+			}
+			r.health.executor = "subprocess"
+			r.health.check = rt.Err
+			r.health.tools = r.configToolNames()
 		default:
 			// Custom framework — build tool registry and try LLM executor
-			reg := tools.NewRegistry()
-			if err := builtins.RegisterAll(reg); err != nil {
-				r.logger.Warn("failed to register builtin tools", map[string]any{"error": err.Error()})
-			}
-
-			// Register cli_execute if configured
-			for _, toolRef := range r.cfg.Config.Tools {
-				if toolRef.Name == "cli_execute" && toolRef.Config != nil {
-					cliCfg := clitools.ParseCLIExecuteConfig(toolRef.Config)
-					if len(cliCfg.AllowedBinaries) > 0 {
-						r.cliExecTool = clitools.NewCLIExecuteTool(cliCfg)
-						if regErr := reg.Register(r.cliExecTool); regErr != nil {
-							r.logger.Warn("failed to register cli_execute", map[string]any{"error": regErr.Error()})
-						} else {
-							avail, missing := r.cliExecTool.Availability()
-							r.logger.Info("cli_execute registered", map[string]any{
-								"available": len(avail), "missing": len(missing),
-							})
-						}
-					}
-					break
-				}
-			}
-
-			// Discover custom tools in tools/ directory
-			toolsDir := filepath.Join(r.cfg.WorkDir, "tools")
-			discovered := clitools.DiscoverTools(toolsDir)
-			cmdExec := &clitools.OSCommandExecutor{}
-			for _, dt := range discovered {
-				// Entrypoint must be relative to WorkDir so execution from agent root finds the file
-				dtCopy := dt
-				dtCopy.Entrypoint = filepath.Join("tools", dt.Entrypoint)
-				ct := tools.NewCustomTool(dtCopy, cmdExec)
-				if regErr := reg.Register(ct); regErr != nil {
-					r.logger.Warn("failed to register custom tool", map[string]any{
-						"tool": dt.Name, "error": regErr.Error(),
-					})
-				}
-			}
-			if len(discovered) > 0 {
-				r.logger.Info("discovered custom tools", map[string]any{"count": len(discovered)})
-			}
+			reg := r.buildToolRegistry()
 
 			// Log registered tool names
 			toolNames := reg.List()
+			r.health.tools = toolNames
 			r.logger.Info("registered tools", map[string]any{"tools": toolNames})
 
 			// Try LLM executor, fall back to stub
 			mc := coreruntime.ResolveModelConfig(r.cfg.Config, envVars, r.cfg.ProviderOverride)
 			if mc != nil {
-				llmClient, llmErr := providers.NewClient(mc.Provider, mc.Client)
+				r.health.provider = mc.Provider
+				r.health.model = mc.Client.Model
+				llmClient, llmErr := r.newLLMClient(mc)
 				if llmErr != nil {
 					r.logger.Warn("failed to create LLM client, using stub", map[string]any{"error": llmErr.Error()})
 					executor = NewStubExecutor(r.cfg.Config.Framework)
+					r.health.executor = "stub"
 				} else {
+					r.health.executor = "llm"
+					r.health.llmReady = true
 					// Build logging hooks for agent loop observability
 					hooks := coreruntime.NewHookRegistry()
 					r.registerLoggingHooks(hooks)
+					if oiTracer != nil {
+						coreruntime.RegisterOpenInferenceHooks(hooks, oiTracer, mc.Provider, mc.Client.Model)
+					}
 
 					executor = coreruntime.NewLLMExecutor(coreruntime.LLMExecutorConfig{
-						Client:       llmClient,
-						Tools:        reg,
-						Hooks:        hooks,
-						SystemPrompt: fmt.Sprintf("You are %s, an AI agent.", r.cfg.Config.AgentID),
+						Client:         llmClient,
+						Tools:          reg,
+						Hooks:          hooks,
+						SystemPrompt:   r.systemPrompt(reg),
+						ToolBudget:     r.cfg.ToolBudget,
+						ToolCosts:      r.toolCosts(),
+						MaxHistory:     r.maxHistory(),
+						KeepFirst:      r.cfg.Config.Memory.KeepFirstMessage,
+						Temperature:    r.cfg.Config.Model.Temperature,
+						TopP:           r.cfg.Config.Model.TopP,
+						Stop:           r.cfg.Config.Model.Stop,
+						CachePrompt:    r.cfg.Config.Model.CacheSystemPrompt,
+						ResponseFormat: r.responseFormat(),
+						ContextWindow:  r.cfg.Config.Model.ContextWindow,
+						ParallelTools:  r.cfg.Config.ParallelTools,
+						AskForInputs:   r.cfg.Config.AskForInputs,
+						Pricing:        r.pricingTable(),
+						Tracer:         otelTracer,
 					})
 					r.logger.Info("using LLM executor", map[string]any{
 						"provider": mc.Provider,
@@ -174,12 +251,16 @@ func (r *Runner) Run(ctx context.Context) error {
 				}
 			} else {
 				executor = NewStubExecutor(r.cfg.Config.Framework)
+				r.health.executor = "stub"
 				r.logger.Warn("no LLM provider configured, using stub executor", map[string]any{
 					"framework": r.cfg.Config.Framework,
 				})
 			}
 		}
 	}
+	if oiTracer != nil {
+		executor = coreruntime.NewTracingExecutor(executor, oiTracer, r.cfg.Config.AgentID)
+	}
 	defer executor.Close() //nolint:errcheck
 
 	// Start lifecycle runtime if present
@@ -191,11 +272,22 @@ func (r *Runner) Run(ctx context.Context) error {
 	}
 
 	// 5. Create A2A server
+	store, err := r.openTaskStore()
+	if err != nil {
+		return err
+	}
+	if closer, ok := store.(io.Closer); ok {
+		defer closer.Close() //nolint:errcheck
+	}
 	srv := server.NewServer(server.ServerConfig{
-		Port:      r.cfg.Port,
+		Listener:  r.ln,
 		AgentCard: card,
+		TaskStore: store,
 	})
 
+	srv.SetHealthFunc(func() any { return r.healthStatus() })
+	srv.SetReadyFunc(r.healthErr)
+
 	// 6. Register JSON-RPC handlers
 	r.registerHandlers(srv, executor, guardrails)
 
@@ -204,6 +296,16 @@ func (r *Runner) Run(ctx context.Context) error {
 	defer watchCancel()
 
 	watcher := NewFileWatcher(r.cfg.WorkDir, func() {
+		// Restart subprocess lifecycle (no-op if lifecycle is nil). Restart
+		// returns once the new process passes its readiness check.
+		if lifecycle != nil {
+			if err := lifecycle.Restart(ctx); err != nil {
+				r.logger.Error("failed to restart runtime", map[string]any{"error": err.Error()})
+				return
+			}
+			r.logger.Info("runtime restarted", nil)
+		}
+
 		// Reload config and agent card
 		newCard, err := BuildAgentCard(r.cfg.WorkDir, r.cfg.Config, r.cfg.Port)
 		if err != nil {
@@ -212,13 +314,6 @@ func (r *Runner) Run(ctx context.Context) error {
 			srv.UpdateAgentCard(newCard)
 			r.logger.Info("agent card reloaded", nil)
 		}
-
-		// Restart subprocess lifecycle (no-op if lifecycle is nil)
-		if lifecycle != nil {
-			if err := lifecycle.Restart(ctx); err != nil {
-				r.logger.Error("failed to restart runtime", map[string]any{"error": err.Error()})
-			}
-		}
 	}, r.logger)
 	go watcher.Watch(watchCtx)
 
@@ -226,11 +321,43 @@ func (r *Runner) Run(ctx context.Context) error {
 	r.printBanner()
 
 	// 9. Start server (blocks)
-	return srv.Start(ctx)
+	err = srv.Start(ctx)
+	r.printUsageSummary()
+	return err
+}
+
+// maxListTasks caps the page size of tasks/list.
+const maxListTasks = 100
+
+// openTaskStore opens the SQLite task store when task_store.path is set,
+// and otherwise returns an in-memory store.
+func (r *Runner) openTaskStore() (a2a.TaskStore, error) {
+	path := r.cfg.Config.TaskStore.Path
+	if path == "" {
+		return a2a.NewMemoryTaskStore(), nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.cfg.WorkDir, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating task store directory: %w", err)
+	}
+	store, err := a2a.OpenSQLiteTaskStore(path)
+	if err != nil {
+		return nil, err
+	}
+	store.OnError(func(err error) {
+		r.logger.Error("task store error", map[string]any{"error": err.Error()})
+	})
+	r.logger.Info("using sqlite task store", map[string]any{"path": path})
+	return store, nil
 }
 
 func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.AgentExecutor, guardrails *coreruntime.GuardrailEngine) {
 	store := srv.TaskStore()
+	push := newPushNotifier(r.cfg.Config.PushNotifications, r.egress, r.logger)
+	store.OnUpdate(push.taskUpdated)
+	sessions := newSessionStore(r.cfg.Config.Memory)
 
 	// tasks/send — synchronous request
 	srv.RegisterHandler("tasks/send", func(ctx context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
@@ -239,14 +366,48 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
 		}
 
-		r.logger.Info("tasks/send", map[string]any{"task_id": params.ID})
+		r.logger.Info("tasks/send", map[string]any{"task_id": params.ID, "session_id": params.SessionID})
+		if err := push.checkParams(&params); err != nil {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())
+		}
+
+		if r.cfg.DebugStream {
+			ctx = coreruntime.WithStatusFunc(ctx, func(ev coreruntime.StatusEvent) {
+				r.logger.Info("agent status", map[string]any{
+					"task_id": params.ID, "type": ev.Type, "iteration": ev.Iteration, "tool": ev.ToolName,
+				})
+			})
+		}
 
-		// Create task in submitted state
+		// Create task in submitted state; a retried submission of an in-flight
+		// or finished task returns it instead of executing again.
 		task := &a2a.Task{
-			ID:     params.ID,
-			Status: a2a.TaskStatus{State: a2a.TaskStateSubmitted},
+			ID:        params.ID,
+			SessionID: params.SessionID,
+			Status:    a2a.TaskStatus{State: a2a.TaskStateSubmitted},
+			History:   resumedHistory(store, params.ID),
+			Metadata:  params.Metadata,
+		}
+		if existing, ok := claimTask(store, sessions, push, task, &params); !ok {
+			r.logger.Info("duplicate task submission", map[string]any{"task_id": params.ID, "state": string(existing.Status.State)})
+			return a2a.NewResponse(id, existing)
 		}
-		store.Put(task)
+
+		// Delegation depth check
+		depth := a2a.CallDepth(params.Metadata)
+		if err := a2a.CheckCallDepth(depth, r.cfg.MaxCallDepth); err != nil {
+			r.logger.Warn("delegation depth exceeded", map[string]any{"task_id": params.ID, "depth": depth})
+			task.Status = a2a.TaskStatus{
+				State: a2a.TaskStateFailed,
+				Message: &a2a.Message{
+					Role:  a2a.MessageRoleAgent,
+					Parts: []a2a.Part{a2a.NewTextPart(err.Error())},
+				},
+			}
+			store.Put(task)
+			return a2a.NewResponse(id, task)
+		}
+		ctx = a2a.WithCallDepth(ctx, depth)
 
 		// Guardrail check inbound
 		if err := guardrails.CheckInbound(&params.Message); err != nil {
@@ -261,19 +422,33 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 			return a2a.NewResponse(id, task)
 		}
 
+		// Help triggers get a deterministic reply without invoking the executor
+		if reply, ok := r.helpResponse(&params.Message, srv.AgentCard()); ok {
+			task.Status = a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: reply}
+			task.Artifacts = []a2a.Artifact{{Name: "response", Parts: reply.Parts}}
+			store.Put(task)
+			return a2a.NewResponse(id, task)
+		}
+
 		// Update to working
 		store.UpdateStatus(params.ID, a2a.TaskStatus{State: a2a.TaskStateWorking})
 		task.Status = a2a.TaskStatus{State: a2a.TaskStateWorking}
 
-		// Execute via executor
-		respMsg, err := executor.Execute(ctx, task, &params.Message)
+		// Execute via executor, bounded by the task deadline
+		ctx, cancel := r.withTaskDeadline(ctx)
+		defer cancel()
+		tracker := &coreruntime.UsageTracker{}
+		ctx = coreruntime.WithUsageTracker(ctx, tracker)
+		ctx = r.withTranscript(ctx, params.ID)
+		respMsg, err := executeWithDeadline(ctx, executor, withSessionHistory(task, sessions.history(task.SessionID)), &params.Message)
+		r.recordTaskUsage(task, tracker)
 		if err != nil {
 			r.logger.Error("execute failed", map[string]any{"task_id": params.ID, "error": err.Error()})
 			task.Status = a2a.TaskStatus{
 				State: a2a.TaskStateFailed,
 				Message: &a2a.Message{
 					Role:  a2a.MessageRoleAgent,
-					Parts: []a2a.Part{a2a.NewTextPart(err.Error())},
+					Parts: []a2a.Part{a2a.NewTextPart(r.taskErrorText(ctx, err))},
 				},
 			}
 			store.Put(task)
@@ -295,20 +470,10 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 			}
 		}
 
-		// Build completed task
-		task.Status = a2a.TaskStatus{
-			State:   a2a.TaskStateCompleted,
-			Message: respMsg,
-		}
-		if respMsg != nil {
-			task.Artifacts = []a2a.Artifact{
-				{
-					Name:  "response",
-					Parts: respMsg.Parts,
-				},
-			}
-		}
+		finishTask(task, &params.Message, respMsg)
 		store.Put(task)
+		sessions.recordTask(task.SessionID, task, &params.Message)
+		r.saveArtifacts(task)
 		r.logger.Info("task completed", map[string]any{"task_id": params.ID, "state": string(task.Status.State)})
 		return a2a.NewResponse(id, task)
 	})
@@ -321,15 +486,57 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 			return
 		}
 
-		r.logger.Info("tasks/sendSubscribe", map[string]any{"task_id": params.ID})
+		r.logger.Info("tasks/sendSubscribe", map[string]any{"task_id": params.ID, "session_id": params.SessionID})
+		if err := push.checkParams(&params); err != nil {
+			server.WriteSSEEvent(w, flusher, "error", a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, err.Error())) //nolint:errcheck
+			return
+		}
 
-		// Create task
+		// Intermediate status goes out as "debug" events; the executor emits
+		// them from its own goroutine, so serialize writes to w.
+		var writeMu sync.Mutex
+		writeEvent := func(event string, data any) {
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			server.WriteSSEEvent(w, flusher, event, data) //nolint:errcheck
+		}
+		if r.cfg.DebugStream {
+			ctx = coreruntime.WithStatusFunc(ctx, func(ev coreruntime.StatusEvent) {
+				writeEvent("debug", ev)
+			})
+		}
+
+		// Create task; a retried submission gets the existing task as its result
 		task := &a2a.Task{
-			ID:     params.ID,
-			Status: a2a.TaskStatus{State: a2a.TaskStateSubmitted},
+			ID:        params.ID,
+			SessionID: params.SessionID,
+			Status:    a2a.TaskStatus{State: a2a.TaskStateSubmitted},
+			History:   resumedHistory(store, params.ID),
+			Metadata:  params.Metadata,
 		}
-		store.Put(task)
-		server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
+		if existing, ok := claimTask(store, sessions, push, task, &params); !ok {
+			r.logger.Info("duplicate task submission", map[string]any{"task_id": params.ID, "state": string(existing.Status.State)})
+			writeEvent("result", existing)
+			return
+		}
+		writeEvent("status", task)
+
+		// Delegation depth check
+		depth := a2a.CallDepth(params.Metadata)
+		if err := a2a.CheckCallDepth(depth, r.cfg.MaxCallDepth); err != nil {
+			r.logger.Warn("delegation depth exceeded", map[string]any{"task_id": params.ID, "depth": depth})
+			task.Status = a2a.TaskStatus{
+				State: a2a.TaskStateFailed,
+				Message: &a2a.Message{
+					Role:  a2a.MessageRoleAgent,
+					Parts: []a2a.Part{a2a.NewTextPart(err.Error())},
+				},
+			}
+			store.Put(task)
+			writeEvent("status", task)
+			return
+		}
+		ctx = a2a.WithCallDepth(ctx, depth)
 
 		// Guardrail check inbound
 		if err := guardrails.CheckInbound(&params.Message); err != nil {
@@ -341,17 +548,33 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 				},
 			}
 			store.Put(task)
-			server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
+			writeEvent("status", task)
+			return
+		}
+
+		// Help triggers get a deterministic reply without invoking the executor
+		if reply, ok := r.helpResponse(&params.Message, srv.AgentCard()); ok {
+			task.Status = a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: reply}
+			task.Artifacts = []a2a.Artifact{{Name: "response", Parts: reply.Parts}}
+			store.Put(task)
+			writeEvent("result", task)
 			return
 		}
 
 		// Update to working
 		task.Status = a2a.TaskStatus{State: a2a.TaskStateWorking}
 		store.Put(task)
-		server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
-
-		// Stream from executor
-		ch, err := executor.ExecuteStream(ctx, task, &params.Message)
+		writeEvent("status", task)
+
+		// Stream from executor, bounded by the task deadline
+		ctx, cancel := r.withTaskDeadline(ctx)
+		defer cancel()
+		tracker := &coreruntime.UsageTracker{}
+		ctx = coreruntime.WithUsageTracker(ctx, tracker)
+		ctx = r.withTranscript(ctx, params.ID)
+		// Usage is complete once the executor produces its result
+		recordUsage := sync.OnceFunc(func() { r.recordTaskUsage(task, tracker) })
+		ch, err := executor.ExecuteStream(ctx, withSessionHistory(task, sessions.history(task.SessionID)), &params.Message)
 		if err != nil {
 			task.Status = a2a.TaskStatus{
 				State: a2a.TaskStateFailed,
@@ -361,13 +584,41 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 				},
 			}
 			store.Put(task)
-			server.WriteSSEEvent(w, flusher, "status", task) //nolint:errcheck
+			writeEvent("status", task)
 			return
 		}
+		// Redact across chunk boundaries before guardrails see each chunk
+		if redactor := guardrails.StreamRedactor(); redactor != nil {
+			ch = coreruntime.RedactStream(ch, redactor)
+		}
+
+		for {
+			var respMsg *a2a.Message
+			var ok bool
+			select {
+			case respMsg, ok = <-ch:
+			case <-ctx.Done():
+				r.logger.Error("execute failed", map[string]any{"task_id": params.ID, "error": ctx.Err().Error()})
+				recordUsage()
+				task.Status = a2a.TaskStatus{
+					State: a2a.TaskStateFailed,
+					Message: &a2a.Message{
+						Role:  a2a.MessageRoleAgent,
+						Parts: []a2a.Part{a2a.NewTextPart(r.taskErrorText(ctx, ctx.Err()))},
+					},
+				}
+				store.Put(task)
+				writeEvent("result", task)
+				return
+			}
+			if !ok {
+				recordUsage()
+				return
+			}
 
-		for respMsg := range ch {
 			// Guardrail check outbound
 			if grErr := guardrails.CheckOutbound(respMsg); grErr != nil {
+				recordUsage()
 				task.Status = a2a.TaskStatus{
 					State: a2a.TaskStateFailed,
 					Message: &a2a.Message{
@@ -376,23 +627,26 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 					},
 				}
 				store.Put(task)
-				server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
+				writeEvent("result", task)
 				return
 			}
 
-			// Build completed result
-			task.Status = a2a.TaskStatus{
-				State:   a2a.TaskStateCompleted,
-				Message: respMsg,
-			}
-			task.Artifacts = []a2a.Artifact{
-				{
-					Name:  "response",
-					Parts: respMsg.Parts,
-				},
+			// Partial text goes out as working status updates; the complete
+			// reply that follows is the result
+			if a2a.IsPartial(respMsg) {
+				writeEvent("status", &a2a.Task{
+					ID:     task.ID,
+					Status: a2a.TaskStatus{State: a2a.TaskStateWorking, Message: respMsg},
+				})
+				continue
 			}
+
+			recordUsage()
+			finishTask(task, &params.Message, respMsg)
 			store.Put(task)
-			server.WriteSSEEvent(w, flusher, "result", task) //nolint:errcheck
+			sessions.recordTask(task.SessionID, task, &params.Message)
+			r.saveArtifacts(task)
+			writeEvent("result", task)
 		}
 	})
 
@@ -410,6 +664,33 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		return a2a.NewResponse(id, task)
 	})
 
+	// tasks/list — page through stored tasks, newest first, optionally by state
+	srv.RegisterHandler("tasks/list", func(ctx context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
+		var params a2a.ListTasksParams
+		if len(rawParams) > 0 {
+			if err := json.Unmarshal(rawParams, &params); err != nil {
+				return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "invalid params: "+err.Error())
+			}
+		}
+		if params.Offset < 0 || params.Limit < 0 {
+			return a2a.NewErrorResponse(id, a2a.ErrCodeInvalidParams, "offset and limit must not be negative")
+		}
+		if params.Limit == 0 || params.Limit > maxListTasks {
+			params.Limit = maxListTasks
+		}
+
+		entries, total := store.List(a2a.TaskListOptions{State: params.State, Offset: params.Offset, Limit: params.Limit})
+		result := a2a.ListTasksResult{Tasks: make([]a2a.TaskSummary, 0, len(entries)), Total: total}
+		for _, e := range entries {
+			summary := a2a.TaskSummary{ID: e.Task.ID, State: e.Task.Status.State, CreatedAt: e.CreatedAt, UpdatedAt: e.UpdatedAt}
+			if params.Verbose {
+				summary.Task = e.Task
+			}
+			result.Tasks = append(result.Tasks, summary)
+		}
+		return a2a.NewResponse(id, result)
+	})
+
 	// tasks/cancel — cancel a task
 	srv.RegisterHandler("tasks/cancel", func(ctx context.Context, id any, rawParams json.RawMessage) *a2a.JSONRPCResponse {
 		var params a2a.CancelTaskParams
@@ -427,6 +708,306 @@ func (r *Runner) registerHandlers(srv *server.Server, executor coreruntime.Agent
 		r.logger.Info("task canceled", map[string]any{"task_id": params.ID})
 		return a2a.NewResponse(id, task)
 	})
+
+	// tasks/pushNotification/set and get — task completion webhooks
+	r.registerPushHandlers(srv, push)
+
+	// tasks/explain — recorded agent loop steps of a task, opt-in since
+	// they hold full prompts and tool output
+	if r.cfg.Config.Explain {
+		r.registerExplainHandler(srv)
+	}
+}
+
+// taskTimeout returns the overall task deadline, preferring --task-timeout
+// over task_timeout in forge.yaml. Zero means no deadline.
+func (r *Runner) taskTimeout() time.Duration {
+	if r.cfg.TaskTimeout > 0 {
+		return r.cfg.TaskTimeout
+	}
+	d, _ := time.ParseDuration(r.cfg.Config.TaskTimeout)
+	return d
+}
+
+// withTaskDeadline bounds ctx by the task timeout, if one is set.
+func (r *Runner) withTaskDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d := r.taskTimeout(); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// taskErrorText describes a failed execution, replacing deadline errors with
+// a clear time limit message.
+func (r *Runner) taskErrorText(ctx context.Context, err error) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Sprintf("task exceeded time limit of %s", r.taskTimeout())
+	}
+	return err.Error()
+}
+
+// executeWithDeadline runs executor.Execute but returns as soon as ctx is
+// done, so an executor that ignores cancellation cannot hold the task open.
+func executeWithDeadline(ctx context.Context, executor coreruntime.AgentExecutor, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	type result struct {
+		msg *a2a.Message
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		m, err := executor.Execute(ctx, task, msg)
+		done <- result{m, err}
+	}()
+	select {
+	case res := <-done:
+		return res.msg, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// claimTask stores task unless a task with its ID is in flight or finished,
+// in which case it returns that task and false. Only a claimed task gets its
+// session and the webhook params carries, so a duplicate submission neither
+// replaces the webhook of a running task nor leaves one that never fires.
+func claimTask(store a2a.TaskStore, sessions *sessionStore, push *pushNotifier, task *a2a.Task, params *a2a.SendTaskParams) (*a2a.Task, bool) {
+	if existing, ok := store.Claim(task); !ok {
+		return existing, false
+	}
+	push.setFromParams(params)
+	if sid := sessions.resolve(params.SessionID); sid != task.SessionID {
+		task.SessionID = sid
+		store.Put(task)
+	}
+	return nil, true
+}
+
+// resumedHistory returns the conversation of a task that paused for input, so
+// a follow-up message with the same task ID continues it. Other tasks start
+// without history.
+func resumedHistory(store a2a.TaskStore, id string) []a2a.Message {
+	prior := store.Get(id)
+	if prior == nil || prior.Status.State != a2a.TaskStateInputRequired {
+		return nil
+	}
+	history := prior.History
+	if prior.Status.Message != nil {
+		history = append(history, *prior.Status.Message)
+	}
+	return history
+}
+
+// finishTask records reply as the result of task. A reply that asks for
+// input leaves the task input-required, keeping msg in its history for the
+// follow-up; any other reply completes it.
+func finishTask(task *a2a.Task, msg, reply *a2a.Message) {
+	if a2a.IsInputRequired(reply) {
+		// A streamed question may arrive in several chunks; record msg once
+		if task.Status.State != a2a.TaskStateInputRequired {
+			task.History = append(task.History, *msg)
+		}
+		task.Status = a2a.TaskStatus{State: a2a.TaskStateInputRequired, Message: reply}
+		return
+	}
+	task.Status = a2a.TaskStatus{
+		State:   a2a.TaskStateCompleted,
+		Message: reply,
+	}
+	if reply != nil {
+		task.Artifacts = []a2a.Artifact{
+			{
+				Name:  "response",
+				Parts: reply.Parts,
+			},
+		}
+	}
+}
+
+// buildToolRegistry registers the builtin tools with their forge.yaml
+// config, cli_execute when configured, and custom tools discovered in
+// tools/, then applies the safe-mode filter.
+func (r *Runner) buildToolRegistry() *tools.Registry {
+	reg := tools.NewRegistry()
+	configs := make(map[string]map[string]any)
+	for _, t := range r.cfg.Config.Tools {
+		if t.Config != nil {
+			configs[t.Name] = t.Config
+		}
+	}
+	if err := builtins.RegisterAllWithConfig(reg, configs); err != nil {
+		r.logger.Warn("failed to register builtin tools", map[string]any{"error": err.Error()})
+	}
+	unavailable := builtins.Unavailable()
+	for _, t := range r.cfg.Config.Tools {
+		if env, ok := unavailable[t.Name]; ok {
+			r.logger.Warn("builtin tool not registered", map[string]any{"tool": t.Name, "reason": env + " is not set"})
+		}
+	}
+
+	// Register cli_execute if configured. An entry without a config uses
+	// the defaults, plus the binaries skills require
+	for _, toolRef := range r.cfg.Config.Tools {
+		if toolRef.Name == "cli_execute" {
+			cliCfg := clitools.ParseCLIExecuteConfig(toolRef.Config)
+			if added := mergeDerivedCLI(&cliCfg, r.derivedCLI); len(added) > 0 {
+				r.logger.Info("cli_execute: allowed binaries required by skills", map[string]any{"binaries": added})
+			}
+			if len(cliCfg.AllowedBinaries) > 0 {
+				r.cliExecTool = clitools.NewCLIExecuteTool(cliCfg)
+				if regErr := reg.Register(r.cliExecTool); regErr != nil {
+					r.logger.Warn("failed to register cli_execute", map[string]any{"error": regErr.Error()})
+				} else {
+					avail, missing := r.cliExecTool.Availability()
+					r.logger.Info("cli_execute registered", map[string]any{
+						"available": len(avail), "missing": len(missing),
+					})
+				}
+			}
+			break
+		}
+	}
+
+	// Register memory_save and memory_search if memory is configured
+	for _, toolRef := range r.cfg.Config.Tools {
+		if toolRef.Name == "memory" {
+			store := r.memory
+			if store == nil {
+				store = memory.NewStore(nil)
+			}
+			for _, t := range memory.NewTools(store) {
+				if regErr := reg.Register(t); regErr != nil {
+					r.logger.Warn("failed to register memory tool", map[string]any{"tool": t.Name(), "error": regErr.Error()})
+				}
+			}
+			break
+		}
+	}
+
+	// Discover custom tools in tools/ directory
+	toolsDir := filepath.Join(r.cfg.WorkDir, "tools")
+	discovered := clitools.DiscoverTools(toolsDir)
+	cmdExec := &clitools.OSCommandExecutor{}
+	for _, dt := range discovered {
+		// Entrypoint must be relative to WorkDir so execution from agent root finds the file
+		dtCopy := dt
+		dtCopy.Entrypoint = filepath.Join("tools", dt.Entrypoint)
+		ct := tools.NewCustomTool(dtCopy, cmdExec)
+		if regErr := reg.Register(ct); regErr != nil {
+			r.logger.Warn("failed to register custom tool", map[string]any{
+				"tool": dt.Name, "error": regErr.Error(),
+			})
+		}
+	}
+	if len(discovered) > 0 {
+		r.logger.Info("discovered custom tools", map[string]any{"count": len(discovered)})
+	}
+
+	reg.ValidateInputs(r.cfg.Config.ValidateToolInputs)
+	if tc := r.cfg.Config.ToolCache; tc.TTL != "" {
+		ttl, _ := time.ParseDuration(tc.TTL)
+		reg.EnableCache(tools.CacheConfig{TTL: ttl, MaxEntries: tc.MaxEntries})
+	}
+	if r.egress != nil {
+		reg.SetEgress(r.egress)
+	}
+
+	if r.cfg.SafeMode {
+		all := reg.List()
+		reg = reg.ReadOnly(r.cfg.SafeModeAllow)
+		if reg.Get("cli_execute") == nil {
+			r.cliExecTool = nil
+		}
+		var disabled []string
+		for _, name := range all {
+			if reg.Get(name) == nil {
+				disabled = append(disabled, name)
+			}
+		}
+		r.logger.Info("safe mode: mutating tools disabled", map[string]any{"disabled": disabled})
+	}
+	return reg
+}
+
+// egressEnforcer resolves the egress config in forge.yaml, plus the egress
+// domains skills declare, the way forge build does. It returns nil,
+// enforcing nothing, when no egress section is set, and an error when the
+// section cannot be resolved.
+func (r *Runner) egressEnforcer() (*security.EgressEnforcer, error) {
+	var toolNames []string
+	for _, t := range r.cfg.Config.Tools {
+		toolNames = append(toolNames, t.Name)
+	}
+	resolved, err := coreskills.ResolveEgress(r.cfg.Config.Egress, toolNames, r.skillReqs)
+	if err != nil || resolved == nil {
+		return nil, err
+	}
+	if resolved.Mode == security.ModeDevOpen {
+		r.logger.Warn("egress mode dev-open: tools may reach any host", nil)
+	} else {
+		r.logger.Info("egress enforced", map[string]any{"mode": string(resolved.Mode), "domains": resolved.AllDomains})
+	}
+	return security.NewEgressEnforcer(resolved), nil
+}
+
+// systemPrompt assembles the LLM system prompt, appending usage guidance for
+// the tools registered in reg. Guidance set via tools[].config.guidance in
+// forge.yaml replaces a tool's built-in guidance.
+func (r *Runner) systemPrompt(reg *tools.Registry) string {
+	prompt := fmt.Sprintf("You are %s, an AI agent.", r.cfg.Config.AgentID)
+
+	overrides := make(map[string]string)
+	for _, t := range r.cfg.Config.Tools {
+		if g, ok := t.Config["guidance"].(string); ok {
+			overrides[t.Name] = g
+		}
+	}
+	if guidance := reg.Guidance(overrides); guidance != "" {
+		prompt += "\n\nTool usage guidance:\n" + guidance
+	}
+	return prompt
+}
+
+// maxHistory returns the history cap, preferring the --max-history flag over
+// memory.max_history in forge.yaml.
+func (r *Runner) maxHistory() int {
+	if r.cfg.MaxHistory > 0 {
+		return r.cfg.MaxHistory
+	}
+	return r.cfg.Config.Memory.MaxHistory
+}
+
+// responseFormat converts model.response_format from forge.yaml, returning
+// nil for plain text.
+func (r *Runner) responseFormat() *llm.ResponseFormat {
+	rf := r.cfg.Config.Model.ResponseFormat
+	if rf.Type == "" || rf.Type == llm.ResponseFormatText {
+		return nil
+	}
+	f := &llm.ResponseFormat{Type: rf.Type, Name: rf.Name}
+	if len(rf.Schema) > 0 {
+		schema, err := json.Marshal(rf.Schema)
+		if err != nil {
+			r.logger.Warn("invalid response_format schema, using json_object", map[string]any{"error": err.Error()})
+			return &llm.ResponseFormat{Type: llm.ResponseFormatJSONObject}
+		}
+		f.Schema = schema
+	}
+	return f
+}
+
+// toolCosts reads per-tool cost weights from the "cost" key of each tool's
+// config in forge.yaml.
+func (r *Runner) toolCosts() map[string]float64 {
+	costs := make(map[string]float64)
+	for _, t := range r.cfg.Config.Tools {
+		switch v := t.Config["cost"].(type) {
+		case int:
+			costs[t.Name] = float64(v)
+		case float64:
+			costs[t.Name] = v
+		}
+	}
+	return costs
 }
 
 func (r *Runner) loadToolSpecs() []agentspec.ToolSpec {
@@ -444,10 +1025,16 @@ func (r *Runner) registerLoggingHooks(hooks *coreruntime.HookRegistry) {
 			return nil
 		}
 		fields := map[string]any{
+			"model":         hctx.Response.Model,
 			"finish_reason": hctx.Response.FinishReason,
 		}
-		if hctx.Response.Usage.TotalTokens > 0 {
-			fields["tokens"] = hctx.Response.Usage.TotalTokens
+		if usage := hctx.Response.Usage; usage.TotalTokens > 0 {
+			fields["tokens"] = usage.TotalTokens
+			if usage.CacheCreationTokens > 0 || usage.CacheReadTokens > 0 {
+				fields["cache_write_tokens"] = usage.CacheCreationTokens
+				fields["cache_read_tokens"] = usage.CacheReadTokens
+				fields["cache_hit_rate"] = float64(usage.CacheReadTokens) / float64(usage.PromptTokens)
+			}
 		}
 		if len(hctx.Response.Message.ToolCalls) > 0 {
 			names := make([]string, len(hctx.Response.Message.ToolCalls))
@@ -492,6 +1079,9 @@ func (r *Runner) registerLoggingHooks(hooks *coreruntime.HookRegistry) {
 			}
 			fields["output_length"] = len(hctx.ToolOutput)
 			fields["output"] = output
+			if hctx.CacheHit {
+				fields["cached"] = true
+			}
 			r.logger.Info("tool result", fields)
 		}
 		return nil
@@ -503,6 +1093,11 @@ func (r *Runner) registerLoggingHooks(hooks *coreruntime.HookRegistry) {
 		}
 		return nil
 	})
+
+	hooks.Register(coreruntime.OnWarning, func(_ context.Context, hctx *coreruntime.HookContext) error {
+		r.logger.Warn("agent loop warning", map[string]any{"warning": hctx.Warning})
+		return nil
+	})
 }
 
 func (r *Runner) printBanner() {
@@ -517,6 +1112,9 @@ func (r *Runner) printBanner() {
 	} else {
 		fmt.Fprintf(os.Stderr, "  Entrypoint: %s\n", r.cfg.Config.Entrypoint)
 	}
+	if r.cfg.SafeMode {
+		fmt.Fprintf(os.Stderr, "  Safe mode:  read-only tools only\n")
+	}
 	// Tools
 	if len(r.cfg.Config.Tools) > 0 {
 		names := make([]string, 0, len(r.cfg.Config.Tools))
@@ -551,11 +1149,73 @@ func (r *Runner) printBanner() {
 	fmt.Fprintf(os.Stderr, "  ────────────────────────────────────────\n")
 	fmt.Fprintf(os.Stderr, "  Agent Card: http://localhost:%d/.well-known/agent.json\n", r.cfg.Port)
 	fmt.Fprintf(os.Stderr, "  Health:     http://localhost:%d/healthz\n", r.cfg.Port)
+	fmt.Fprintf(os.Stderr, "  Status:     http://localhost:%d/health\n", r.cfg.Port)
 	fmt.Fprintf(os.Stderr, "  JSON-RPC:   POST http://localhost:%d/\n", r.cfg.Port)
 	fmt.Fprintf(os.Stderr, "  ────────────────────────────────────────\n")
 	fmt.Fprintf(os.Stderr, "  Press Ctrl+C to stop\n\n")
 }
 
+// newLLMClient returns the chat client for mc from the client pool. With
+// model.fallbacks configured, it is a FallbackClient trying the primary
+// first.
+func (r *Runner) newLLMClient(mc *coreruntime.ModelConfig) (llm.Client, error) {
+	pool := r.cfg.ClientPool
+	if pool == nil {
+		pool = providers.DefaultPool
+	}
+	primary, err := pool.Client(mc.Provider, mc.Client)
+	if err != nil || len(mc.Fallbacks) == 0 {
+		return primary, err
+	}
+	chain := []providers.NamedClient{{Provider: mc.Provider, Client: primary}}
+	for _, fb := range mc.Fallbacks {
+		c, err := pool.Client(fb.Provider, fb.Client)
+		if err != nil {
+			return nil, fmt.Errorf("model fallback: %w", err)
+		}
+		chain = append(chain, providers.NamedClient{Provider: fb.Provider, Client: c})
+	}
+	r.logger.Info("model fallbacks configured", map[string]any{"fallbacks": len(mc.Fallbacks)})
+	return providers.NewFallbackClient(chain, r.logger), nil
+}
+
+// setupModeration gives moderation guardrails a moderator for the configured
+// model provider. If the provider has no moderation API the guardrails stay
+// disabled with a warning.
+func (r *Runner) setupModeration(guardrails *coreruntime.GuardrailEngine, envVars map[string]string) {
+	mc := coreruntime.ResolveModelConfig(r.cfg.Config, envVars, r.cfg.ProviderOverride)
+	if mc == nil {
+		r.logger.Warn("moderation guardrail disabled: no model provider configured", map[string]any{})
+		return
+	}
+	modCfg := mc.Client
+	modCfg.Model = "" // the chat model is not a moderation model
+	moderator, err := providers.NewModerator(mc.Provider, modCfg)
+	if err != nil {
+		r.logger.Warn("moderation guardrail disabled", map[string]any{"error": err.Error()})
+		return
+	}
+	guardrails.SetModerator(moderator)
+	r.logger.Info("moderation guardrail enabled", map[string]any{"provider": mc.Provider})
+}
+
+// newMemoryStore builds the store behind the memory tools. With
+// memory.embedding set it ranks by embedding similarity; if the embedder
+// cannot be created it falls back to keyword search with a warning.
+func (r *Runner) newMemoryStore(envVars map[string]string) *memory.Store {
+	mc := coreruntime.ResolveEmbeddingConfig(r.cfg.Config, envVars)
+	if mc == nil {
+		return memory.NewStore(nil)
+	}
+	embedder, err := providers.NewEmbedder(mc.Provider, mc.Client)
+	if err != nil {
+		r.logger.Warn("memory embedding disabled, using keyword search", map[string]any{"error": err.Error()})
+		return memory.NewStore(nil)
+	}
+	r.logger.Info("memory embedding enabled", map[string]any{"provider": mc.Provider, "model": embedder.ModelID()})
+	return memory.NewStore(embedder)
+}
+
 // validateSkillRequirements loads skill requirements and validates them.
 // It also auto-derives cli_execute config from skill requirements.
 func (r *Runner) validateSkillRequirements(envVars map[string]string) error {
@@ -580,6 +1240,7 @@ func (r *Runner) validateSkillRequirements(envVars map[string]string) error {
 	}
 
 	reqs := coreskills.AggregateRequirements(entries)
+	r.skillReqs = reqs
 	if len(reqs.Bins) == 0 && len(reqs.EnvRequired) == 0 && len(reqs.EnvOneOf) == 0 && len(reqs.EnvOptional) == 0 {
 		return nil
 	}
@@ -605,9 +1266,12 @@ func (r *Runner) validateSkillRequirements(envVars map[string]string) error {
 		}
 	}
 
-	// Auto-derive cli_execute config from skill requirements
+	// Auto-derive cli_execute config from skill requirements. It is merged
+	// into an explicit cli_execute config by buildToolRegistry.
 	derived := coreskills.DeriveCLIConfig(reqs)
 	if derived != nil && len(derived.AllowedBinaries) > 0 {
+		r.derivedCLI = derived
+
 		// Check if cli_execute is already explicitly configured
 		hasExplicit := false
 		for _, toolRef := range r.cfg.Config.Tools {
@@ -628,6 +1292,28 @@ func (r *Runner) validateSkillRequirements(envVars map[string]string) error {
 	return nil
 }
 
+// mergeDerivedCLI adds the binaries and env vars derived from skill
+// requirements to an explicit cli_execute config, keeping its timeout and
+// output limits. It returns the binaries that were added.
+func mergeDerivedCLI(cfg *clitools.CLIExecuteConfig, derived *coreskills.DerivedCLIConfig) []string {
+	if derived == nil {
+		return nil
+	}
+	var added []string
+	for _, b := range derived.AllowedBinaries {
+		if !slices.Contains(cfg.AllowedBinaries, b) {
+			cfg.AllowedBinaries = append(cfg.AllowedBinaries, b)
+			added = append(added, b)
+		}
+	}
+	for _, e := range derived.EnvPassthrough {
+		if !slices.Contains(cfg.EnvPassthrough, e) {
+			cfg.EnvPassthrough = append(cfg.EnvPassthrough, e)
+		}
+	}
+	return added
+}
+
 func envFromOS() map[string]string {
 	env := make(map[string]string)
 	for _, e := range os.Environ() {
@@ -1,16 +1,32 @@
 package runtime
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/initializ/forge/forge-cli/server"
+	clitools "github.com/initializ/forge/forge-cli/tools"
 	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/llm/providers"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/tools"
+	"github.com/initializ/forge/forge-core/tools/builtins"
 	"github.com/initializ/forge/forge-core/types"
+
+	_ "modernc.org/sqlite"
 )
 
 func TestRunner_MockIntegration(t *testing.T) {
@@ -191,6 +207,57 @@ func TestNewRunner_NilConfig(t *testing.T) {
 	}
 }
 
+func TestRunner_InvalidEgressFailsRun(t *testing.T) {
+	dir := t.TempDir()
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID:    "test",
+			Version:    "0.1.0",
+			Entrypoint: "python main.py",
+			Egress:     types.EgressRef{Mode: "allow-some"},
+		},
+		WorkDir:     dir,
+		EnvFilePath: filepath.Join(dir, ".env"),
+		AutoPort:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = runner.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `invalid egress mode "allow-some"`) {
+		t.Errorf("Run error = %v, want invalid egress mode", err)
+	}
+}
+
+func TestRunner_InvalidGuardrailPatternFailsRun(t *testing.T) {
+	dir := t.TempDir()
+	outDir := filepath.Join(dir, ".forge-output")
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	scaffold := `{"guardrails":[{"type":"content_filter","config":{"patterns":["ACCT-(\\d+"]}}]}`
+	if err := os.WriteFile(filepath.Join(outDir, "policy-scaffold.json"), []byte(scaffold), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID:    "test",
+			Version:    "0.1.0",
+			Entrypoint: "python main.py",
+		},
+		WorkDir:     dir,
+		EnvFilePath: filepath.Join(dir, ".env"),
+		AutoPort:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = runner.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid content filter patterns") {
+		t.Errorf("Run error = %v, want invalid content filter patterns", err)
+	}
+}
+
 func TestNewRunner_DefaultPort(t *testing.T) {
 	runner, err := NewRunner(RunnerConfig{
 		Config: &types.ForgeConfig{
@@ -226,3 +293,1124 @@ func waitForServer(t *testing.T, baseURL string, timeout time.Duration) {
 		time.Sleep(50 * time.Millisecond)
 	}
 }
+
+// statusExecutor emits status events for each tool and returns a final answer.
+type statusExecutor struct{}
+
+func (e *statusExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	for _, name := range []string{"web_search", "math_calculate"} {
+		coreruntime.EmitStatus(ctx, coreruntime.StatusEvent{Type: coreruntime.StatusToolStart, ToolName: name})
+		coreruntime.EmitStatus(ctx, coreruntime.StatusEvent{Type: coreruntime.StatusToolEnd, ToolName: name, ToolOutput: "ok"})
+	}
+	return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("final answer")}}, nil
+}
+
+func (e *statusExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	ch := make(chan *a2a.Message, 1)
+	go func() {
+		defer close(ch)
+		resp, _ := e.Execute(ctx, task, msg)
+		ch <- resp
+	}()
+	return ch, nil
+}
+
+func (e *statusExecutor) Close() error { return nil }
+
+func TestRunner_DebugStream(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config:      &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:        port,
+		DebugStream: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseURL := startHandlerServer(t, runner, &statusExecutor{})
+
+	body, _ := json.Marshal(a2a.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      "1",
+		Method:  "tasks/sendSubscribe",
+		Params: mustMarshal(a2a.SendTaskParams{
+			ID:      "t-debug",
+			Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}},
+		}),
+	})
+	resp, err := http.Post(baseURL+"/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("sendSubscribe: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var debugEvents []coreruntime.StatusEvent
+	var result *a2a.Task
+	scanner := bufio.NewScanner(resp.Body)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data := []byte(strings.TrimPrefix(line, "data: "))
+			switch event {
+			case "debug":
+				var ev coreruntime.StatusEvent
+				if err := json.Unmarshal(data, &ev); err != nil {
+					t.Fatalf("decoding debug event: %v", err)
+				}
+				debugEvents = append(debugEvents, ev)
+			case "result":
+				result = &a2a.Task{}
+				if err := json.Unmarshal(data, result); err != nil {
+					t.Fatalf("decoding result: %v", err)
+				}
+			}
+		}
+	}
+
+	if len(debugEvents) != 4 {
+		t.Fatalf("got %d debug events, want 4: %+v", len(debugEvents), debugEvents)
+	}
+	if debugEvents[0].ToolName != "web_search" || debugEvents[2].ToolName != "math_calculate" {
+		t.Errorf("unexpected tool order: %+v", debugEvents)
+	}
+	if result == nil || result.Status.Message == nil {
+		t.Fatal("expected result event with message")
+	}
+	parts := result.Status.Message.Parts
+	if len(parts) != 1 || parts[0].Text != "final answer" {
+		t.Errorf("result message = %+v, want only final text", parts)
+	}
+}
+
+// startHandlerServer serves the runner's JSON-RPC handlers backed by executor
+// on the runner's port and returns the base URL.
+func startHandlerServer(t *testing.T, runner *Runner, executor coreruntime.AgentExecutor) string {
+	t.Helper()
+	srv := server.NewServer(server.ServerConfig{Port: runner.cfg.Port, AgentCard: &a2a.AgentCard{Name: "test-agent"}})
+	runner.registerHandlers(srv, executor, coreruntime.NewGuardrailEngine(nil, false, runner.logger))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go srv.Start(ctx) //nolint:errcheck
+
+	baseURL := fmt.Sprintf("http://localhost:%d", runner.cfg.Port)
+	waitForServer(t, baseURL, 5*time.Second)
+	return baseURL
+}
+
+// depthExecutor records the delegation depth it was invoked with.
+type depthExecutor struct {
+	depth int
+}
+
+func (e *depthExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	e.depth = a2a.CallDepthFromContext(ctx)
+	return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("ok")}}, nil
+}
+
+func (e *depthExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *depthExecutor) Close() error { return nil }
+
+func TestRunner_MaxCallDepth(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config:       &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:         port,
+		MaxCallDepth: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := &depthExecutor{}
+	baseURL := startHandlerServer(t, runner, exec)
+
+	send := func(depth int) a2a.Task {
+		t.Helper()
+		body, _ := json.Marshal(a2a.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      "1",
+			Method:  "tasks/send",
+			Params: mustMarshal(a2a.SendTaskParams{
+				ID:       fmt.Sprintf("t-%d", depth),
+				Message:  a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}},
+				Metadata: map[string]any{a2a.MetadataKeyCallDepth: depth},
+			}),
+		})
+		resp, err := http.Post(baseURL+"/", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("send: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var rpcResp a2a.JSONRPCResponse
+		json.NewDecoder(resp.Body).Decode(&rpcResp) //nolint:errcheck
+		resultData, _ := json.Marshal(rpcResp.Result)
+		var task a2a.Task
+		json.Unmarshal(resultData, &task) //nolint:errcheck
+		return task
+	}
+
+	// Within the limit: executes and sees the propagated depth
+	task := send(2)
+	if task.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("depth 2: state = %q, want completed", task.Status.State)
+	}
+	if exec.depth != 2 {
+		t.Errorf("executor depth = %d, want 2", exec.depth)
+	}
+
+	// Past the limit: blocked with a descriptive error
+	exec.depth = -1
+	task = send(3)
+	if task.Status.State != a2a.TaskStateFailed {
+		t.Fatalf("depth 3: state = %q, want failed", task.Status.State)
+	}
+	if exec.depth != -1 {
+		t.Error("executor should not run when depth is exceeded")
+	}
+	if task.Status.Message == nil || !strings.Contains(task.Status.Message.Parts[0].Text, "exceeds maximum of 2") {
+		t.Errorf("unexpected failure message: %+v", task.Status.Message)
+	}
+}
+
+// countingExecutor counts how many times it is invoked.
+type countingExecutor struct {
+	calls atomic.Int32
+}
+
+func (e *countingExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	n := e.calls.Add(1)
+	return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(fmt.Sprintf("run %d", n))}}, nil
+}
+
+func (e *countingExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *countingExecutor) Close() error { return nil }
+
+func TestRunner_IdempotentSend(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:   port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := &countingExecutor{}
+	baseURL := startHandlerServer(t, runner, exec)
+
+	send := func() a2a.Task {
+		t.Helper()
+		body, _ := json.Marshal(a2a.JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      "1",
+			Method:  "tasks/send",
+			Params: mustMarshal(a2a.SendTaskParams{
+				ID:      "dup-1",
+				Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}},
+			}),
+		})
+		resp, err := http.Post(baseURL+"/", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("send: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var rpcResp a2a.JSONRPCResponse
+		json.NewDecoder(resp.Body).Decode(&rpcResp) //nolint:errcheck
+		resultData, _ := json.Marshal(rpcResp.Result)
+		var task a2a.Task
+		json.Unmarshal(resultData, &task) //nolint:errcheck
+		return task
+	}
+
+	first := send()
+	second := send()
+
+	if n := exec.calls.Load(); n != 1 {
+		t.Fatalf("executor ran %d times, want 1", n)
+	}
+	if second.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("retry state = %q, want completed", second.Status.State)
+	}
+	if len(second.Artifacts) == 0 || second.Artifacts[0].Parts[0].Text != first.Artifacts[0].Parts[0].Text {
+		t.Errorf("retry should return the original result, got %+v", second.Artifacts)
+	}
+}
+
+func TestRunner_HealthEndpoint(t *testing.T) {
+	dir := t.TempDir()
+	envPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envPath, []byte("OPENAI_API_KEY=sk-test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID:    "health-agent",
+			Version:    "1.2.3",
+			Framework:  "custom",
+			Entrypoint: "python main.py",
+			Egress:     types.EgressRef{Profile: "standard", Mode: "allowlist"},
+		},
+		WorkDir:     dir,
+		Port:        port,
+		EnvFilePath: envPath,
+		Channels:    []string{"slack"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runner.Run(ctx) //nolint:errcheck
+
+	baseURL := fmt.Sprintf("http://localhost:%d", port)
+	waitForServer(t, baseURL, 5*time.Second)
+
+	resp, err := http.Get(baseURL + "/health")
+	if err != nil {
+		t.Fatalf("health request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var health HealthStatus
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		t.Fatalf("decoding health: %v", err)
+	}
+
+	if health.Status != "ok" || health.AgentID != "health-agent" || health.Version != "1.2.3" {
+		t.Errorf("identity fields: %+v", health)
+	}
+	if health.Provider != "openai" || health.Model != "gpt-4o" {
+		t.Errorf("model: got %s/%s, want openai/gpt-4o", health.Provider, health.Model)
+	}
+	if !health.LLMReady || health.Executor != "llm" {
+		t.Errorf("llm_ready=%v executor=%q, want true/llm", health.LLMReady, health.Executor)
+	}
+	hasMath := false
+	for _, name := range health.Tools {
+		if name == "math_calculate" {
+			hasMath = true
+		}
+	}
+	if !hasMath {
+		t.Errorf("tools %v missing math_calculate", health.Tools)
+	}
+	if len(health.Channels) != 1 || health.Channels[0] != "slack" {
+		t.Errorf("channels: got %v", health.Channels)
+	}
+	if health.EgressProfile != "standard" || health.EgressMode != "allowlist" {
+		t.Errorf("egress: got %s/%s", health.EgressProfile, health.EgressMode)
+	}
+	if health.UptimeSeconds < 0 {
+		t.Errorf("uptime: got %v", health.UptimeSeconds)
+	}
+
+	// /healthz stays minimal
+	resp2, err := http.Get(baseURL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp2.Body.Close() }()
+	var minimal map[string]any
+	json.NewDecoder(resp2.Body).Decode(&minimal) //nolint:errcheck
+	if len(minimal) != 1 || minimal["status"] != "ok" {
+		t.Errorf("healthz: got %v", minimal)
+	}
+}
+
+func TestRunner_ToolCosts(t *testing.T) {
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID:    "test",
+			Version:    "0.1.0",
+			Entrypoint: "main.py",
+			Tools: []types.ToolRef{
+				{Name: "web_search", Config: map[string]any{"cost": 3}},
+				{Name: "http_request", Config: map[string]any{"cost": 0.5}},
+				{Name: "math_calculate"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	costs := runner.toolCosts()
+	if costs["web_search"] != 3 || costs["http_request"] != 0.5 {
+		t.Errorf("costs = %v", costs)
+	}
+	if _, ok := costs["math_calculate"]; ok {
+		t.Error("tool without cost should not be budgeted")
+	}
+}
+
+func TestRunner_SystemPromptToolGuidance(t *testing.T) {
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID:    "test",
+			Version:    "0.1.0",
+			Entrypoint: "main.py",
+			Tools: []types.ToolRef{
+				{Name: "web_search", Config: map[string]any{"guidance": "Cite the URLs you used."}},
+				{Name: "not_registered", Config: map[string]any{"guidance": "should not appear"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg := tools.NewRegistry()
+	if err := builtins.RegisterAll(reg); err != nil {
+		t.Fatal(err)
+	}
+
+	// cli_execute not enabled: no guidance for it
+	prompt := runner.systemPrompt(reg)
+	if strings.Contains(prompt, "cli_execute") {
+		t.Errorf("cli_execute guidance present without the tool:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "- web_search: Cite the URLs you used.") {
+		t.Errorf("configured guidance missing:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "should not appear") {
+		t.Errorf("guidance for unregistered tool included:\n%s", prompt)
+	}
+
+	// cli_execute enabled: its guidance is appended
+	if err := reg.Register(clitools.NewCLIExecuteTool(clitools.CLIExecuteConfig{AllowedBinaries: []string{"echo"}})); err != nil {
+		t.Fatal(err)
+	}
+	prompt = runner.systemPrompt(reg)
+	if !strings.Contains(prompt, "- cli_execute: Use cli_execute only for the allowed binaries") {
+		t.Errorf("cli_execute guidance missing:\n%s", prompt)
+	}
+	if !strings.HasPrefix(prompt, "You are test, an AI agent.") {
+		t.Errorf("base prompt missing:\n%s", prompt)
+	}
+}
+
+func TestRunner_Describe(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "tools"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tools", "tool_lookup.py"), []byte("print('ok')\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID:    "describe-agent",
+			Version:    "0.1.0",
+			Framework:  "custom",
+			Entrypoint: "main.py",
+			Tools: []types.ToolRef{
+				{Name: "cli_execute", Config: map[string]any{"allowed_binaries": []any{"echo"}}},
+			},
+		},
+		WorkDir: dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := runner.Describe(&buf); err != nil {
+		t.Fatalf("Describe: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "You are describe-agent, an AI agent.") {
+		t.Errorf("system prompt missing:\n%s", out)
+	}
+
+	// Tool names are the lines indented by exactly two spaces after the
+	// "Tools (N):" header.
+	_, toolSection, ok := strings.Cut(out, "\nTools (")
+	if !ok {
+		t.Fatalf("tools section missing:\n%s", out)
+	}
+	var described []string
+	for _, line := range strings.Split(toolSection, "\n") {
+		if strings.HasPrefix(line, "  ") && !strings.HasPrefix(line, "   ") {
+			described = append(described, strings.TrimSpace(line))
+		}
+	}
+
+	want := runner.buildToolRegistry().List()
+	if strings.Join(described, ",") != strings.Join(want, ",") {
+		t.Errorf("described tools = %v, want %v", described, want)
+	}
+	for _, name := range []string{"lookup", "cli_execute", "web_search"} {
+		if !slices.Contains(described, name) {
+			t.Errorf("tool %q not described: %v", name, described)
+		}
+	}
+	if !strings.Contains(out, `"type": "object"`) {
+		t.Errorf("indented schema missing:\n%s", out)
+	}
+}
+
+func TestRunner_CLIExecuteMergesSkillBinaries(t *testing.T) {
+	dir := t.TempDir()
+	skills := "---\nrequired_bins: [jq]\nrequired_env: [JQ_TOKEN]\n---\n## Tool: filter\nFilter JSON.\n"
+	if err := os.WriteFile(filepath.Join(dir, "skills.md"), []byte(skills), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ref  types.ToolRef
+		want []string
+	}{
+		{"explicit config", types.ToolRef{Name: "cli_execute", Config: map[string]any{"allowed_binaries": []any{"curl"}, "timeout": 5}}, []string{"curl", "jq"}},
+		{"bare entry", types.ToolRef{Name: "cli_execute"}, []string{"jq"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, err := NewRunner(RunnerConfig{
+				Config: &types.ForgeConfig{
+					AgentID:    "test",
+					Version:    "0.1.0",
+					Entrypoint: "main.py",
+					Tools:      []types.ToolRef{tt.ref},
+				},
+				WorkDir: dir,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := runner.validateSkillRequirements(map[string]string{"JQ_TOKEN": "x"}); err != nil {
+				t.Fatalf("validateSkillRequirements: %v", err)
+			}
+			runner.buildToolRegistry()
+			if runner.cliExecTool == nil {
+				t.Fatal("cli_execute not registered")
+			}
+
+			avail, missing := runner.cliExecTool.Availability()
+			allowed := append(avail, missing...)
+			for _, bin := range tt.want {
+				if !slices.Contains(allowed, bin) {
+					t.Errorf("%s not allowed: %v", bin, allowed)
+				}
+			}
+		})
+	}
+}
+
+func TestRunner_MemoryToolsUseEmbedding(t *testing.T) {
+	var embedded atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		embedded.Add(int32(len(req.Input)))
+		var data []string
+		for i, text := range req.Input {
+			vec := "[0,1]"
+			if strings.Contains(text, "seat") || strings.Contains(text, "sit") {
+				vec = "[1,0]"
+			}
+			data = append(data, fmt.Sprintf(`{"index":%d,"embedding":%s}`, i, vec))
+		}
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ",")) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID: "test", Version: "0.1.0", Entrypoint: "main.py",
+			Tools:  []types.ToolRef{{Name: "memory"}},
+			Memory: types.MemoryRef{Embedding: types.EmbeddingRef{Provider: "openai"}},
+		},
+		WorkDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner.memory = runner.newMemoryStore(map[string]string{"OPENAI_API_KEY": "sk-test", "OPENAI_BASE_URL": srv.URL})
+	reg := runner.buildToolRegistry()
+
+	ctx := context.Background()
+	for _, text := range []string{"Report is due Friday", "Prefers a window seat"} {
+		if _, err := reg.Execute(ctx, "memory_save", json.RawMessage(fmt.Sprintf(`{"text":%q}`, text))); err != nil {
+			t.Fatalf("memory_save: %v", err)
+		}
+	}
+	out, err := reg.Execute(ctx, "memory_search", json.RawMessage(`{"query":"where do they like to sit","limit":1}`))
+	if err != nil {
+		t.Fatalf("memory_search: %v", err)
+	}
+	if !strings.Contains(out, "window seat") || strings.Contains(out, "Friday") {
+		t.Errorf("memory_search = %s, want only the seat memory", out)
+	}
+	if embedded.Load() != 3 {
+		t.Errorf("embedded %d texts, want 3", embedded.Load())
+	}
+}
+
+func TestRunner_MemoryToolsNeedConfig(t *testing.T) {
+	runner := newFixtureRunner(t)
+	reg := runner.buildToolRegistry()
+	if reg.Get("memory_save") != nil || reg.Get("memory_search") != nil {
+		t.Errorf("memory tools registered without a memory tools entry: %v", reg.List())
+	}
+}
+
+func TestRunner_EgressIncludesSkillDomains(t *testing.T) {
+	dir := t.TempDir()
+	skills := "---\negress_domains: [api.github.com]\n---\n## Tool: issues\nList issues.\n"
+	if err := os.WriteFile(filepath.Join(dir, "skills.md"), []byte(skills), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID:    "test",
+			Version:    "0.1.0",
+			Entrypoint: "main.py",
+			Egress:     types.EgressRef{Profile: "strict", Mode: "allowlist"},
+		},
+		WorkDir: dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.validateSkillRequirements(nil); err != nil {
+		t.Fatalf("validateSkillRequirements: %v", err)
+	}
+	egress, err := runner.egressEnforcer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !egress.Allowed("api.github.com") {
+		t.Error("skill egress domain api.github.com not allowed at runtime")
+	}
+	if egress.Allowed("example.org") {
+		t.Error("example.org allowed")
+	}
+}
+
+// slowExecutor blocks until its context is canceled and reports the cancellation.
+type slowExecutor struct {
+	canceled chan struct{}
+}
+
+func (e *slowExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	select {
+	case <-ctx.Done():
+		close(e.canceled)
+		return nil, ctx.Err()
+	case <-time.After(10 * time.Second):
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("too late")}}, nil
+	}
+}
+
+func (e *slowExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	ch := make(chan *a2a.Message, 1)
+	go func() {
+		defer close(ch)
+		if resp, err := e.Execute(ctx, task, msg); err == nil {
+			ch <- resp
+		}
+	}()
+	return ch, nil
+}
+
+func (e *slowExecutor) Close() error { return nil }
+
+func TestRunner_TaskTimeout(t *testing.T) {
+	for _, method := range []string{"tasks/send", "tasks/sendSubscribe"} {
+		t.Run(method, func(t *testing.T) {
+			port, err := findFreePort()
+			if err != nil {
+				t.Fatal(err)
+			}
+			runner, err := NewRunner(RunnerConfig{
+				Config:      &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+				Port:        port,
+				TaskTimeout: 100 * time.Millisecond,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			exec := &slowExecutor{canceled: make(chan struct{})}
+			baseURL := startHandlerServer(t, runner, exec)
+
+			body, _ := json.Marshal(a2a.JSONRPCRequest{
+				JSONRPC: "2.0",
+				ID:      "1",
+				Method:  method,
+				Params: mustMarshal(a2a.SendTaskParams{
+					ID:      "slow",
+					Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}},
+				}),
+			})
+			start := time.Now()
+			resp, err := http.Post(baseURL+"/", "application/json", bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("post: %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			var task a2a.Task
+			if method == "tasks/send" {
+				var rpcResp a2a.JSONRPCResponse
+				json.NewDecoder(resp.Body).Decode(&rpcResp) //nolint:errcheck
+				resultData, _ := json.Marshal(rpcResp.Result)
+				json.Unmarshal(resultData, &task) //nolint:errcheck
+			} else {
+				scanner := bufio.NewScanner(resp.Body)
+				var event string
+				for scanner.Scan() {
+					line := scanner.Text()
+					if strings.HasPrefix(line, "event: ") {
+						event = strings.TrimPrefix(line, "event: ")
+					} else if strings.HasPrefix(line, "data: ") && event == "result" {
+						json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &task) //nolint:errcheck
+					}
+				}
+			}
+
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("task ran for %s, expected to stop at the deadline", elapsed)
+			}
+			if task.Status.State != a2a.TaskStateFailed {
+				t.Fatalf("state = %q, want failed", task.Status.State)
+			}
+			if task.Status.Message == nil || !strings.Contains(task.Status.Message.Parts[0].Text, "task exceeded time limit of 100ms") {
+				t.Errorf("unexpected failure message: %+v", task.Status.Message)
+			}
+			select {
+			case <-exec.canceled:
+			case <-time.After(2 * time.Second):
+				t.Error("executor context was not canceled")
+			}
+		})
+	}
+}
+
+// sendTask posts a tasks/send request and decodes the resulting task.
+func sendTask(t *testing.T, baseURL string, params a2a.SendTaskParams) a2a.Task {
+	t.Helper()
+	body, _ := json.Marshal(a2a.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      "1",
+		Method:  "tasks/send",
+		Params:  mustMarshal(params),
+	})
+	resp, err := http.Post(baseURL+"/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var rpcResp a2a.JSONRPCResponse
+	json.NewDecoder(resp.Body).Decode(&rpcResp) //nolint:errcheck
+	resultData, _ := json.Marshal(rpcResp.Result)
+	var task a2a.Task
+	json.Unmarshal(resultData, &task) //nolint:errcheck
+	return task
+}
+
+func TestRunner_HelpResponse(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:   port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := &countingExecutor{}
+	baseURL := startHandlerServer(t, runner, exec)
+
+	task := sendTask(t, baseURL, a2a.SendTaskParams{
+		ID:      "help-1",
+		Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("/start")}},
+	})
+	if task.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("state = %q, want completed", task.Status.State)
+	}
+	if task.Status.Message == nil || !strings.HasPrefix(task.Status.Message.Parts[0].Text, "Hi! I'm test-agent.") {
+		t.Errorf("unexpected help text: %+v", task.Status.Message)
+	}
+	if n := exec.calls.Load(); n != 0 {
+		t.Errorf("executor invoked %d times for /start, want 0", n)
+	}
+
+	// Ordinary messages still reach the executor
+	sendTask(t, baseURL, a2a.SendTaskParams{
+		ID:      "help-2",
+		Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("help me plan a trip")}},
+	})
+	if n := exec.calls.Load(); n != 1 {
+		t.Errorf("executor invoked %d times, want 1", n)
+	}
+}
+
+func TestRunner_HelpResponseConfig(t *testing.T) {
+	card := &a2a.AgentCard{
+		Name:        "researcher",
+		Description: "I research topics on the web.",
+		Skills:      []a2a.Skill{{ID: "web_search", Name: "web_search", Description: "Search the web"}},
+	}
+	msg := func(text string) *a2a.Message {
+		return &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(text)}}
+	}
+
+	runner := &Runner{cfg: RunnerConfig{Config: &types.ForgeConfig{}}}
+	reply, ok := runner.helpResponse(msg("  HELP "), card)
+	if !ok {
+		t.Fatal("expected default trigger to match")
+	}
+	want := "Hi! I'm researcher. I research topics on the web.\n\nI can help with:\n- web_search: Search the web\n\nTell me what you need and I'll get started."
+	if got := reply.Parts[0].Text; got != want {
+		t.Errorf("help text = %q, want %q", got, want)
+	}
+	if _, ok := runner.helpResponse(msg("/start@research_bot"), card); !ok {
+		t.Error("expected /start with bot mention to match")
+	}
+
+	runner.cfg.Config.Help = types.HelpRef{Triggers: []string{"menu"}, Message: "Ask me anything."}
+	if _, ok := runner.helpResponse(msg("help"), card); ok {
+		t.Error("default trigger should not match when triggers are configured")
+	}
+	if reply, ok := runner.helpResponse(msg("Menu"), card); !ok || reply.Parts[0].Text != "Ask me anything." {
+		t.Errorf("configured trigger/message not used: %v %+v", ok, reply)
+	}
+
+	runner.cfg.Config.Help = types.HelpRef{Disabled: true}
+	if _, ok := runner.helpResponse(msg(""), card); ok {
+		t.Error("help should not match when disabled")
+	}
+}
+
+// clarifyingExecutor asks a question until the task history holds one, then
+// answers with the history it was given.
+type clarifyingExecutor struct{}
+
+func (e *clarifyingExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	if len(task.History) == 0 {
+		return &a2a.Message{
+			Role:     a2a.MessageRoleAgent,
+			Parts:    []a2a.Part{a2a.NewTextPart("Which city?")},
+			Metadata: map[string]any{a2a.MetadataInputRequired: true},
+		}, nil
+	}
+	var texts []string
+	for _, m := range append(task.History, *msg) {
+		texts = append(texts, m.Parts[0].Text)
+	}
+	return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(strings.Join(texts, " | "))}}, nil
+}
+
+func (e *clarifyingExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *clarifyingExecutor) Close() error { return nil }
+
+func TestRunner_InputRequiredResumes(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:   port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURL := startHandlerServer(t, runner, &clarifyingExecutor{})
+
+	first := sendTask(t, baseURL, a2a.SendTaskParams{
+		ID:      "ask-1",
+		Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("weather please")}},
+	})
+	if first.Status.State != a2a.TaskStateInputRequired {
+		t.Fatalf("state = %q, want input-required", first.Status.State)
+	}
+	if len(first.Artifacts) != 0 {
+		t.Errorf("question should not be a response artifact: %+v", first.Artifacts)
+	}
+
+	second := sendTask(t, baseURL, a2a.SendTaskParams{
+		ID:      "ask-1",
+		Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("Paris")}},
+	})
+	if second.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("follow-up state = %q, want completed", second.Status.State)
+	}
+	want := "weather please | Which city? | Paris"
+	if got := second.Status.Message.Parts[0].Text; got != want {
+		t.Errorf("executor saw %q, want %q", got, want)
+	}
+}
+
+func TestRunner_ReusesPooledClient(t *testing.T) {
+	runner, err := NewRunner(RunnerConfig{
+		Config:     &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		ClientPool: providers.NewPool(providers.PoolConfig{}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolve := func() *coreruntime.ModelConfig {
+		return &coreruntime.ModelConfig{Provider: "openai", Client: llm.ClientConfig{APIKey: "k", Model: "gpt-4o"}}
+	}
+
+	first, err := runner.newLLMClient(resolve())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := runner.newLLMClient(resolve())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("identical model config should reuse the pooled client")
+	}
+	if n := runner.cfg.ClientPool.Len(); n != 1 {
+		t.Errorf("pool holds %d clients, want 1", n)
+	}
+}
+
+// partialExecutor streams its reply in pieces, then sends it whole.
+type partialExecutor struct{}
+
+func (e *partialExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *partialExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	ch := make(chan *a2a.Message, 4)
+	for _, text := range []string{"Hello ", "there", "."} {
+		ch <- &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(text)}, Metadata: map[string]any{a2a.MetadataPartial: true}}
+	}
+	ch <- &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("Hello there.")}}
+	close(ch)
+	return ch, nil
+}
+
+func (e *partialExecutor) Close() error { return nil }
+
+func TestRunner_StreamsPartialMessages(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:   port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURL := startHandlerServer(t, runner, &partialExecutor{})
+
+	body, _ := json.Marshal(a2a.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      "1",
+		Method:  "tasks/sendSubscribe",
+		Params: mustMarshal(a2a.SendTaskParams{
+			ID:      "t-partial",
+			Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}},
+		}),
+	})
+	resp, err := http.Post(baseURL+"/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("sendSubscribe: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var streamed []string
+	var results []a2a.Task
+	scanner := bufio.NewScanner(resp.Body)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			var task a2a.Task
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &task); err != nil {
+				t.Fatalf("decoding %s event: %v", event, err)
+			}
+			switch {
+			case event == "status" && task.Status.Message != nil:
+				if task.Status.State != a2a.TaskStateWorking || !a2a.IsPartial(task.Status.Message) {
+					t.Errorf("partial update = %+v", task.Status)
+				}
+				streamed = append(streamed, task.Status.Message.Parts[0].Text)
+			case event == "result":
+				results = append(results, task)
+			}
+		}
+	}
+
+	if got := strings.Join(streamed, ""); got != "Hello there." || len(streamed) != 3 {
+		t.Errorf("streamed %q, want three pieces of %q", streamed, "Hello there.")
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d result events, want 1", len(results))
+	}
+	if results[0].Status.State != a2a.TaskStateCompleted || results[0].Status.Message.Parts[0].Text != "Hello there." {
+		t.Errorf("result = %+v", results[0].Status)
+	}
+}
+
+func TestRunner_SQLiteTaskStore(t *testing.T) {
+	dir := t.TempDir()
+	newRunner := func() *Runner {
+		port, err := findFreePort()
+		if err != nil {
+			t.Fatal(err)
+		}
+		runner, err := NewRunner(RunnerConfig{
+			Config: &types.ForgeConfig{
+				AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py",
+				TaskStore: types.TaskStoreRef{Path: ".forge/tasks.db"},
+			},
+			WorkDir: dir,
+			Port:    port,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return runner
+	}
+	serve := func(runner *Runner) (string, context.CancelFunc) {
+		store, err := runner.openTaskStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv := server.NewServer(server.ServerConfig{Port: runner.cfg.Port, AgentCard: &a2a.AgentCard{Name: "test-agent"}, TaskStore: store})
+		runner.registerHandlers(srv, &depthExecutor{}, coreruntime.NewGuardrailEngine(nil, false, runner.logger))
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			srv.Start(ctx)                       //nolint:errcheck
+			store.(*a2a.SQLiteTaskStore).Close() //nolint:errcheck
+			close(done)
+		}()
+		baseURL := fmt.Sprintf("http://localhost:%d", runner.cfg.Port)
+		waitForServer(t, baseURL, 5*time.Second)
+		return baseURL, func() { cancel(); <-done }
+	}
+
+	baseURL, stop := serve(newRunner())
+	for _, id := range []string{"t1", "t2", "t3"} {
+		sendTask(t, baseURL, a2a.SendTaskParams{ID: id, Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}})
+	}
+	stop()
+
+	// A restarted server still has the tasks
+	baseURL, stop = serve(newRunner())
+	defer stop()
+	resp := rpc(t, baseURL, "tasks/get", a2a.GetTaskParams{ID: "t2"})
+	var task a2a.Task
+	json.Unmarshal(mustMarshal(resp.Result), &task) //nolint:errcheck
+	if task.Status.State != a2a.TaskStateCompleted || len(task.Artifacts) == 0 {
+		t.Errorf("t2 after restart = %+v (error %+v)", task, resp.Error)
+	}
+
+	resp = rpc(t, baseURL, "tasks/list", a2a.ListTasksParams{Offset: 1, Limit: 1})
+	var list a2a.ListTasksResult
+	json.Unmarshal(mustMarshal(resp.Result), &list) //nolint:errcheck
+	if list.Total != 3 || len(list.Tasks) != 1 || list.Tasks[0].ID != "t2" {
+		t.Errorf("tasks/list = %+v (error %+v)", list, resp.Error)
+	}
+}
+
+func TestRunner_TasksList(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:   port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURL := startHandlerServer(t, runner, &depthExecutor{})
+	for _, id := range []string{"t1", "t2", "t3"} {
+		sendTask(t, baseURL, a2a.SendTaskParams{ID: id, Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}})
+	}
+	rpc(t, baseURL, "tasks/cancel", a2a.CancelTaskParams{ID: "t2"})
+
+	list := func(params a2a.ListTasksParams) a2a.ListTasksResult {
+		t.Helper()
+		resp := rpc(t, baseURL, "tasks/list", params)
+		if resp.Error != nil {
+			t.Fatalf("tasks/list: %+v", resp.Error)
+		}
+		var result a2a.ListTasksResult
+		json.Unmarshal(mustMarshal(resp.Result), &result) //nolint:errcheck
+		return result
+	}
+	states := func(result a2a.ListTasksResult) string {
+		var out []string
+		for _, s := range result.Tasks {
+			out = append(out, s.ID+":"+string(s.State))
+		}
+		return strings.Join(out, " ")
+	}
+
+	all := list(a2a.ListTasksParams{})
+	if all.Total != 3 || states(all) != "t3:completed t2:canceled t1:completed" {
+		t.Errorf("tasks/list = %s, total %d", states(all), all.Total)
+	}
+	for _, s := range all.Tasks {
+		if s.CreatedAt.IsZero() || s.UpdatedAt.Before(s.CreatedAt) {
+			t.Errorf("%s timestamps created %v, updated %v", s.ID, s.CreatedAt, s.UpdatedAt)
+		}
+		if s.Task != nil {
+			t.Errorf("%s: full task included without verbose", s.ID)
+		}
+	}
+
+	completed := list(a2a.ListTasksParams{State: a2a.TaskStateCompleted, Limit: 1, Verbose: true})
+	if completed.Total != 2 || states(completed) != "t3:completed" {
+		t.Errorf("completed page = %s, total %d", states(completed), completed.Total)
+	}
+	if task := completed.Tasks[0].Task; task == nil || len(task.Artifacts) == 0 {
+		t.Errorf("verbose entry = %+v, want the task with its artifacts", task)
+	}
+
+	if resp := rpc(t, baseURL, "tasks/list", a2a.ListTasksParams{Offset: -1}); resp.Error == nil {
+		t.Error("negative offset accepted")
+	}
+}
@@ -0,0 +1,168 @@
+package runtime
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+const (
+	defaultSessionIdleTimeout = 30 * time.Minute
+	defaultSessionMaxTurns    = 20
+)
+
+// sessionStore keeps the conversation of each session across tasks, so a
+// task sent with a sessionId sees the messages of the session's earlier
+// tasks. Session IDs are issued by the store, never taken from the client,
+// so a caller cannot read another conversation by guessing its ID. Sessions
+// unused for idleTimeout are dropped.
+type sessionStore struct {
+	idleTimeout time.Duration
+	maxTurns    int // user turns kept per session
+	now         func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+type session struct {
+	messages []a2a.Message
+	lastUsed time.Time
+}
+
+func newSessionStore(cfg types.MemoryRef) *sessionStore {
+	idle, _ := time.ParseDuration(cfg.SessionIdleTimeout)
+	if idle <= 0 {
+		idle = defaultSessionIdleTimeout
+	}
+	maxTurns := cfg.SessionMaxTurns
+	if maxTurns <= 0 {
+		maxTurns = defaultSessionMaxTurns
+	}
+	return &sessionStore{
+		idleTimeout: idle,
+		maxTurns:    maxTurns,
+		now:         time.Now,
+		sessions:    make(map[string]*session),
+	}
+}
+
+// resolve returns the session a task sent with id belongs to. A live
+// session the store issued is reused. Any other non-empty id, including an
+// expired one, starts a new session under a fresh unguessable ID. An empty
+// id means the task has no session.
+func (s *sessionStore) resolve(id string) string {
+	if id == "" {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.live(id) != nil {
+		return id
+	}
+	s.evictIdle()
+	id = newSessionID()
+	s.sessions[id] = &session{lastUsed: s.now()}
+	return id
+}
+
+// newSessionID returns a random session ID.
+func newSessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "session-" + hex.EncodeToString(b)
+}
+
+// history returns a copy of the session's messages, oldest first.
+func (s *sessionStore) history(id string) []a2a.Message {
+	if id == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess := s.live(id)
+	if sess == nil {
+		return nil
+	}
+	return append([]a2a.Message(nil), sess.messages...)
+}
+
+// record appends a finished exchange to a session issued by resolve,
+// dropping the oldest turns beyond maxTurns. A session that expired while
+// the task ran is not recreated.
+func (s *sessionStore) record(id string, messages ...a2a.Message) {
+	if id == "" || len(messages) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictIdle()
+	sess := s.sessions[id]
+	if sess == nil {
+		return
+	}
+	sess.messages = trimTurns(append(sess.messages, messages...), s.maxTurns)
+	sess.lastUsed = s.now()
+}
+
+// recordTask records a task's exchange once it completes: its earlier
+// messages, msg, and the reply. Unfinished and failed tasks are skipped.
+func (s *sessionStore) recordTask(id string, task *a2a.Task, msg *a2a.Message) {
+	if task.Status.State != a2a.TaskStateCompleted || task.Status.Message == nil {
+		return
+	}
+	messages := append(append([]a2a.Message(nil), task.History...), *msg, *task.Status.Message)
+	s.record(id, messages...)
+}
+
+// live returns the session unless it is missing or idle. The caller must
+// hold s.mu.
+func (s *sessionStore) live(id string) *session {
+	sess := s.sessions[id]
+	if sess == nil {
+		return nil
+	}
+	if s.now().Sub(sess.lastUsed) > s.idleTimeout {
+		delete(s.sessions, id)
+		return nil
+	}
+	return sess
+}
+
+// evictIdle drops every idle session. The caller must hold s.mu.
+func (s *sessionStore) evictIdle() {
+	for id := range s.sessions {
+		s.live(id)
+	}
+}
+
+// trimTurns keeps the last maxTurns user turns of messages, each with the
+// replies that followed it.
+func trimTurns(messages []a2a.Message, maxTurns int) []a2a.Message {
+	turns := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role != a2a.MessageRoleUser {
+			continue
+		}
+		turns++
+		if turns == maxTurns {
+			return messages[i:]
+		}
+	}
+	return messages
+}
+
+// withSessionHistory returns task with the session's earlier messages before
+// its own history, for the executor. The stored task keeps only its own.
+func withSessionHistory(task *a2a.Task, sessionHistory []a2a.Message) *a2a.Task {
+	if len(sessionHistory) == 0 {
+		return task
+	}
+	merged := *task
+	merged.History = append(sessionHistory, task.History...)
+	return &merged
+}
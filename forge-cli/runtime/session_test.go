@@ -0,0 +1,160 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// historyExecutor replies with the text of the history it was given.
+type historyExecutor struct{}
+
+func (e *historyExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	var seen []string
+	for _, m := range task.History {
+		seen = append(seen, m.Parts[0].Text)
+	}
+	return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("saw [" + strings.Join(seen, ", ") + "]")}}, nil
+}
+
+func (e *historyExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *historyExecutor) Close() error { return nil }
+
+func TestRunner_SessionHistory(t *testing.T) {
+	port, err := findFreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py"},
+		Port:   port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseURL := startHandlerServer(t, runner, &historyExecutor{})
+
+	send := func(taskID, sessionID, text string) (reply, session string) {
+		t.Helper()
+		task := sendTask(t, baseURL, a2a.SendTaskParams{
+			ID:        taskID,
+			SessionID: sessionID,
+			Message:   a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(text)}},
+		})
+		if task.Status.Message == nil {
+			t.Fatalf("task %s has no reply: %+v", taskID, task)
+		}
+		return task.Status.Message.Parts[0].Text, task.SessionID
+	}
+
+	got, alice := send("t1", "new", "my name is Alice")
+	if got != "saw []" {
+		t.Errorf("first reply = %q", got)
+	}
+	if alice == "" || alice == "new" {
+		t.Fatalf("session ID = %q, want one issued by the server", alice)
+	}
+	if got, sess := send("t2", alice, "what is my name?"); got != "saw [my name is Alice, saw []]" || sess != alice {
+		t.Errorf("same session reply = %q in %q, want the earlier exchange", got, sess)
+	}
+	if got, _ := send("t3", "new", "what is my name?"); got != "saw []" {
+		t.Errorf("other session reply = %q, want no history", got)
+	}
+	if got, sess := send("t4", "", "hello"); got != "saw []" || sess != "" {
+		t.Errorf("sessionless reply = %q in %q, want no history and no session", got, sess)
+	}
+
+	// The stored task keeps only its own history
+	resp := rpc(t, baseURL, "tasks/get", a2a.GetTaskParams{ID: "t2"})
+	var stored a2a.Task
+	json.Unmarshal(mustMarshal(resp.Result), &stored) //nolint:errcheck
+	if len(stored.History) != 0 {
+		t.Errorf("stored t2 history = %v, want none", stored.History)
+	}
+}
+
+func TestSessionStore_IssuesSessionIDs(t *testing.T) {
+	s := newSessionStore(types.MemoryRef{})
+
+	id := s.resolve("telegram-ws-alice")
+	if id == "telegram-ws-alice" || !strings.HasPrefix(id, "session-") {
+		t.Fatalf("resolve = %q, want a server-issued ID", id)
+	}
+	s.record(id, userMsg("secret"), agentMsg("noted"))
+
+	// A client-chosen ID never reaches another session's history
+	if other := s.resolve("telegram-ws-alice"); other == id || s.history(other) != nil {
+		t.Errorf("client-chosen ID resolved to %q with history %v", other, s.history(other))
+	}
+	if s.resolve(id) != id || len(s.history(id)) != 2 {
+		t.Error("issued ID did not resume its session")
+	}
+	if s.resolve("") != "" {
+		t.Error("empty ID started a session")
+	}
+
+	// Sessions are created only by resolve
+	s.record("made-up", userMsg("hi"), agentMsg("hello"))
+	if s.history("made-up") != nil {
+		t.Error("record created a session for an unissued ID")
+	}
+}
+
+func TestSessionStore_EvictsIdleSessions(t *testing.T) {
+	s := newSessionStore(types.MemoryRef{SessionIdleTimeout: "10m"})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	a, b := s.resolve("a"), s.resolve("b")
+	s.record(a, userMsg("hi"), agentMsg("hello"))
+	s.record(b, userMsg("hey"), agentMsg("hello"))
+
+	now = now.Add(9 * time.Minute)
+	s.record(b, userMsg("still here"), agentMsg("yes"))
+	if got := len(s.history(a)); got != 2 {
+		t.Errorf("session a before timeout has %d messages, want 2", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if got := s.history(a); got != nil {
+		t.Errorf("idle session a = %v, want evicted", got)
+	}
+	if s.resolve(a) == a {
+		t.Error("expired session ID was reused")
+	}
+	if got := len(s.history(b)); got != 4 {
+		t.Errorf("active session b has %d messages, want 4", got)
+	}
+}
+
+func TestSessionStore_MaxTurns(t *testing.T) {
+	s := newSessionStore(types.MemoryRef{SessionMaxTurns: 2})
+	a := s.resolve("a")
+	for i := 1; i <= 4; i++ {
+		s.record(a, userMsg(fmt.Sprintf("q%d", i)), agentMsg(fmt.Sprintf("a%d", i)))
+	}
+	var got []string
+	for _, m := range s.history(a) {
+		got = append(got, m.Parts[0].Text)
+	}
+	if strings.Join(got, " ") != "q3 a3 q4 a4" {
+		t.Errorf("history = %v, want the last two turns", got)
+	}
+}
+
+func userMsg(text string) a2a.Message {
+	return a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(text)}}
+}
+
+func agentMsg(text string) a2a.Message {
+	return a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(text)}}
+}
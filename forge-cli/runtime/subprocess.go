@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -27,28 +30,156 @@ type SubprocessRuntime struct {
 	internalPort int
 	logger       coreruntime.Logger
 
-	mu  sync.Mutex
-	cmd *exec.Cmd
+	// Readiness probe polled after every start
+	readyPath    string
+	readyTimeout time.Duration
+
+	// Crash restarts: up to maxRestarts in a row, waiting restartBackoff
+	// before the first and doubling after each. A process that stays up for
+	// stableAfter resets the count.
+	maxRestarts    int
+	restartBackoff time.Duration
+	stableAfter    time.Duration
+
+	// Session state survives restarts when sessionDir is set
+	sessionDir string
+	sessionID  string
+	starts     int
+
+	// lifeMu serializes starts and stops, including crash restarts
+	lifeMu  sync.Mutex
+	stopped bool
+
+	mu      sync.Mutex
+	proc    *subprocess
+	crashes int   // crashes since the process last stayed up for stableAfter
+	gaveUp  error // set when crash restarts are exhausted
+	// ready is closed once the current start has finished, with readyErr
+	// set if it failed. Requests wait on it so none reach a process that
+	// is still starting.
+	ready    chan struct{}
+	readyErr error
 }
 
+// subprocess is one run of the entrypoint.
+type subprocess struct {
+	cmd  *exec.Cmd
+	done chan struct{} // closed when the process exits
+	err  error         // exit error, set before done is closed
+}
+
+// Readiness probe and crash restart defaults.
+const (
+	defaultReadyPath      = "/healthz"
+	defaultReadyTimeout   = 60 * time.Second
+	defaultMaxRestarts    = 5
+	defaultRestartBackoff = time.Second
+	maxRestartBackoff     = 30 * time.Second
+	defaultStableAfter    = time.Minute
+)
+
 // NewSubprocessRuntime creates a runtime that will start the given entrypoint
 // command, passing PORT as an env var for the subprocess to listen on.
 func NewSubprocessRuntime(entrypoint, workDir string, env map[string]string, logger coreruntime.Logger) *SubprocessRuntime {
 	return &SubprocessRuntime{
-		entrypoint: entrypoint,
-		workDir:    workDir,
-		env:        env,
-		logger:     logger,
+		entrypoint:     entrypoint,
+		workDir:        workDir,
+		env:            env,
+		logger:         logger,
+		readyPath:      defaultReadyPath,
+		readyTimeout:   defaultReadyTimeout,
+		maxRestarts:    defaultMaxRestarts,
+		restartBackoff: defaultRestartBackoff,
+		stableAfter:    defaultStableAfter,
+	}
+}
+
+// SetRestartPolicy sets how many times in a row a crashed subprocess is
+// restarted before the runtime gives up, and the delay before the first
+// restart, which doubles after each (up to 30s). Zero values keep the
+// defaults of 5 restarts and 1s.
+func (s *SubprocessRuntime) SetRestartPolicy(maxRestarts int, backoff time.Duration) {
+	if maxRestarts > 0 {
+		s.maxRestarts = maxRestarts
+	}
+	if backoff > 0 {
+		s.restartBackoff = backoff
 	}
 }
 
-// Start launches the subprocess and waits for it to become healthy.
+// SetReadiness sets the HTTP path polled after each start until it returns
+// 200, and how long to wait for that before giving up. An empty path or a
+// zero timeout keeps the default.
+func (s *SubprocessRuntime) SetReadiness(path string, timeout time.Duration) {
+	if path != "" {
+		s.readyPath = path
+	}
+	if timeout > 0 {
+		s.readyTimeout = timeout
+	}
+}
+
+// sessionIDFile holds the session ID inside the session directory so it is
+// also stable across forge restarts.
+const sessionIDFile = "session-id"
+
+// SetSessionDir enables session resume. Every start of the subprocess,
+// including restarts, receives the same FORGE_SESSION_DIR and
+// FORGE_SESSION_ID, and FORGE_SESSION_RESUMED=true after the first start.
+// The subprocess persists its state under the directory and reloads it when
+// resumed.
+func (s *SubprocessRuntime) SetSessionDir(dir string) {
+	s.sessionDir = dir
+}
+
+// sessionEnv prepares the session directory and returns the session
+// environment variables, or nil when session resume is disabled.
+func (s *SubprocessRuntime) sessionEnv() ([]string, error) {
+	if s.sessionDir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(s.sessionDir, 0700); err != nil {
+		return nil, fmt.Errorf("creating session directory: %w", err)
+	}
+	if s.sessionID == "" {
+		idPath := filepath.Join(s.sessionDir, sessionIDFile)
+		if data, err := os.ReadFile(idPath); err == nil && len(bytes.TrimSpace(data)) > 0 {
+			s.sessionID = string(bytes.TrimSpace(data))
+		} else {
+			var b [16]byte
+			if _, err := rand.Read(b[:]); err != nil {
+				return nil, fmt.Errorf("generating session id: %w", err)
+			}
+			s.sessionID = hex.EncodeToString(b[:])
+			if err := os.WriteFile(idPath, []byte(s.sessionID+"\n"), 0600); err != nil {
+				return nil, fmt.Errorf("writing session id: %w", err)
+			}
+		}
+	}
+	return []string{
+		"FORGE_SESSION_DIR=" + s.sessionDir,
+		"FORGE_SESSION_ID=" + s.sessionID,
+		fmt.Sprintf("FORGE_SESSION_RESUMED=%t", s.starts > 0),
+	}, nil
+}
+
+// Start launches the subprocess and waits for it to become ready. Requests
+// made while it starts are held until it is ready, and fail if it never is.
 func (s *SubprocessRuntime) Start(ctx context.Context) error {
+	s.lifeMu.Lock()
+	defer s.lifeMu.Unlock()
+	s.stopped = false
+	s.notReady()
+	err := s.start(ctx)
+	s.markReady(err)
+	return err
+}
+
+func (s *SubprocessRuntime) start(ctx context.Context) error {
 	port, err := findFreePort()
 	if err != nil {
 		return fmt.Errorf("finding free port: %w", err)
 	}
-	s.internalPort = port
 
 	fields := strings.Fields(s.entrypoint)
 	if len(fields) == 0 {
@@ -56,50 +187,212 @@ func (s *SubprocessRuntime) Start(ctx context.Context) error {
 	}
 
 	s.mu.Lock()
-	s.cmd = exec.CommandContext(ctx, fields[0], fields[1:]...)
-	s.cmd.Dir = s.workDir
+	s.internalPort = port
+	cmd := exec.CommandContext(ctx, fields[0], fields[1:]...)
+	cmd.Dir = s.workDir
 
 	// Build environment
 	env := os.Environ()
 	for k, v := range s.env {
 		env = append(env, k+"="+v)
 	}
-	env = append(env, fmt.Sprintf("PORT=%d", s.internalPort))
-	s.cmd.Env = env
+	env = append(env, fmt.Sprintf("PORT=%d", port))
+	sessEnv, err := s.sessionEnv()
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	cmd.Env = append(env, sessEnv...)
 
 	// Pipe stderr through logger
-	stderr, err := s.cmd.StderrPipe()
+	stderr, err := cmd.StderrPipe()
 	if err != nil {
 		s.mu.Unlock()
 		return fmt.Errorf("stderr pipe: %w", err)
 	}
 	// Capture stdout too
-	s.cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stdout
 
-	if err := s.cmd.Start(); err != nil {
+	if err := cmd.Start(); err != nil {
 		s.mu.Unlock()
 		return fmt.Errorf("starting subprocess: %w", err)
 	}
+	proc := &subprocess{cmd: cmd, done: make(chan struct{})}
+	s.proc = proc
+	s.starts++
 	s.mu.Unlock()
 
-	// Log stderr in background
-	go s.pipeStderr(stderr)
+	// Log stderr in background, then reap the process once stderr closes
+	go func() {
+		s.pipeStderr(stderr)
+		proc.err = cmd.Wait()
+		close(proc.done)
+	}()
 
-	// Wait for subprocess to be healthy
 	s.logger.Info("waiting for subprocess", map[string]any{
-		"port":       s.internalPort,
+		"port":       port,
 		"entrypoint": s.entrypoint,
+		"ready_path": s.readyPath,
 	})
 
-	if err := s.waitForHealth(ctx); err != nil {
-		s.Stop() //nolint:errcheck
-		return fmt.Errorf("subprocess health check failed: %w", err)
+	if err := s.waitForReady(ctx, proc, port); err != nil {
+		s.stop()
+		return fmt.Errorf("subprocess readiness check failed: %w", err)
 	}
 
-	s.logger.Info("subprocess is healthy", map[string]any{"port": s.internalPort})
+	s.logger.Info("subprocess is ready", map[string]any{"port": port})
+	go s.supervise(ctx, proc, time.Now())
 	return nil
 }
 
+// supervise waits for proc to exit. An exit that was not asked for by Stop
+// or Restart is a crash: requests are held while the process is restarted
+// with backoff, and fail once restarts are exhausted.
+func (s *SubprocessRuntime) supervise(ctx context.Context, proc *subprocess, readyAt time.Time) {
+	<-proc.done
+	if ctx.Err() != nil {
+		return
+	}
+	s.mu.Lock()
+	if s.proc != proc {
+		s.mu.Unlock()
+		return // stopped on purpose
+	}
+	s.proc = nil
+	if time.Since(readyAt) >= s.stableAfter {
+		s.crashes = 0
+	}
+	s.mu.Unlock()
+
+	fields := map[string]any{"exit_code": proc.cmd.ProcessState.ExitCode()}
+	if proc.err != nil {
+		fields["error"] = proc.err.Error()
+	}
+	s.logger.Error("subprocess exited unexpectedly", fields)
+	s.notReady()
+	s.restartAfterCrash(ctx, proc.err)
+}
+
+// restartAfterCrash restarts the subprocess until it passes its readiness
+// check or maxRestarts crashes in a row have been reached.
+func (s *SubprocessRuntime) restartAfterCrash(ctx context.Context, cause error) {
+	for {
+		s.mu.Lock()
+		s.crashes++
+		crashes := s.crashes
+		s.mu.Unlock()
+
+		if crashes > s.maxRestarts {
+			err := fmt.Errorf("subprocess crashed %d times in a row; not restarting", crashes)
+			if cause != nil {
+				err = fmt.Errorf("%w (last error: %v)", err, cause)
+			}
+			s.logger.Error("giving up on subprocess", map[string]any{"error": err.Error()})
+			s.mu.Lock()
+			s.gaveUp = err
+			s.mu.Unlock()
+			s.markReady(err)
+			return
+		}
+
+		backoff := min(s.restartBackoff<<(crashes-1), maxRestartBackoff)
+		s.logger.Warn("restarting crashed subprocess", map[string]any{
+			"attempt": crashes,
+			"backoff": backoff.String(),
+		})
+		select {
+		case <-ctx.Done():
+			s.markReady(ctx.Err())
+			return
+		case <-time.After(backoff):
+		}
+
+		s.lifeMu.Lock()
+		if s.stopped || s.isRunning() {
+			// Stopped, or restarted by Restart meanwhile
+			s.lifeMu.Unlock()
+			return
+		}
+		err := s.start(ctx)
+		if err == nil {
+			s.markReady(nil)
+		}
+		s.lifeMu.Unlock()
+		if err == nil {
+			return
+		}
+		cause = err
+	}
+}
+
+func (s *SubprocessRuntime) isRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.proc != nil
+}
+
+// Err returns why the subprocess is no longer being restarted, or nil.
+func (s *SubprocessRuntime) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gaveUp
+}
+
+// notReady holds new requests until the next start finishes. It keeps an
+// existing hold, so Restart can take it before stopping the old process.
+func (s *SubprocessRuntime) notReady() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ready != nil {
+		select {
+		case <-s.ready:
+		default:
+			return // already held
+		}
+	}
+	s.ready = make(chan struct{})
+	s.readyErr = nil
+}
+
+// markReady releases held requests with the outcome of a start. It is a
+// no-op when no requests are held.
+func (s *SubprocessRuntime) markReady(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ready == nil {
+		return
+	}
+	select {
+	case <-s.ready:
+		return
+	default:
+	}
+	s.readyErr = err
+	close(s.ready)
+}
+
+// awaitReady waits for the current start to finish and returns the port of
+// the ready subprocess. A runtime that was never started is used as is.
+func (s *SubprocessRuntime) awaitReady(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	ready := s.ready
+	s.mu.Unlock()
+	if ready != nil {
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.readyErr != nil {
+		return 0, fmt.Errorf("subprocess is not ready: %w", s.readyErr)
+	}
+	return s.internalPort, nil
+}
+
 func (s *SubprocessRuntime) pipeStderr(r io.Reader) {
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
@@ -107,23 +400,29 @@ func (s *SubprocessRuntime) pipeStderr(r io.Reader) {
 	}
 }
 
-func (s *SubprocessRuntime) waitForHealth(ctx context.Context) error {
-	deadline := time.After(60 * time.Second)
+// waitForReady polls the readiness path with backoff until it returns 200.
+// It fails early if the process exits first.
+func (s *SubprocessRuntime) waitForReady(ctx context.Context, proc *subprocess, port int) error {
+	deadline := time.NewTimer(s.readyTimeout)
+	defer deadline.Stop()
 	interval := 100 * time.Millisecond
 
 	for {
+		if s.probe(ctx, port) {
+			return nil
+		}
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-deadline:
-			return fmt.Errorf("timeout waiting for subprocess on port %d", s.internalPort)
-		default:
-		}
-
-		if s.Healthy(ctx) {
-			return nil
+		case <-proc.done:
+			if proc.err != nil {
+				return fmt.Errorf("subprocess exited before becoming ready: %w", proc.err)
+			}
+			return fmt.Errorf("subprocess exited before becoming ready")
+		case <-deadline.C:
+			return fmt.Errorf("GET %s on port %d did not return 200 within %s", s.readyPath, port, s.readyTimeout)
+		case <-time.After(interval):
 		}
-		time.Sleep(interval)
 		// Exponential backoff, cap at 2s
 		if interval < 2*time.Second {
 			interval = interval * 2
@@ -140,8 +439,12 @@ func (s *SubprocessRuntime) Invoke(ctx context.Context, taskID string, msg *a2a.
 		Params:  mustMarshal(a2a.SendTaskParams{ID: taskID, Message: *msg}),
 	}
 
+	port, err := s.awaitReady(ctx)
+	if err != nil {
+		return nil, err
+	}
 	data, _ := json.Marshal(reqBody)
-	url := fmt.Sprintf("http://127.0.0.1:%d/", s.internalPort)
+	url := fmt.Sprintf("http://127.0.0.1:%d/", port)
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
@@ -186,8 +489,12 @@ func (s *SubprocessRuntime) Stream(ctx context.Context, taskID string, msg *a2a.
 		Params:  mustMarshal(a2a.SendTaskParams{ID: taskID, Message: *msg}),
 	}
 
+	port, err := s.awaitReady(ctx)
+	if err != nil {
+		return nil, err
+	}
 	data, _ := json.Marshal(reqBody)
-	url := fmt.Sprintf("http://127.0.0.1:%d/", s.internalPort)
+	url := fmt.Sprintf("http://127.0.0.1:%d/", port)
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
 	if err != nil {
 		return nil, err
@@ -250,9 +557,21 @@ func (s *SubprocessRuntime) readSSEEvents(r io.Reader, ch chan<- *a2a.Task) {
 	}
 }
 
-// Healthy checks if the subprocess is responding.
+// Healthy checks if the subprocess is responding on its readiness path.
+// It is false once crash restarts have been exhausted.
 func (s *SubprocessRuntime) Healthy(ctx context.Context) bool {
-	url := fmt.Sprintf("http://127.0.0.1:%d/healthz", s.internalPort)
+	s.mu.Lock()
+	port, gaveUp := s.internalPort, s.gaveUp
+	s.mu.Unlock()
+	return gaveUp == nil && s.probe(ctx, port)
+}
+
+func (s *SubprocessRuntime) probe(ctx context.Context, port int) bool {
+	path := s.readyPath
+	if path == "" {
+		path = defaultReadyPath
+	}
+	url := fmt.Sprintf("http://127.0.0.1:%d%s", port, path)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return false
@@ -266,37 +585,55 @@ func (s *SubprocessRuntime) Healthy(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-// Stop sends SIGTERM, waits 5s, then SIGKILL if needed.
+// Stop sends SIGTERM, waits 5s, then SIGKILL if needed. The subprocess is
+// not restarted after Stop, and requests held for it fail.
 func (s *SubprocessRuntime) Stop() error {
+	s.lifeMu.Lock()
+	defer s.lifeMu.Unlock()
+	s.stopped = true
+	s.stop()
+	s.markReady(fmt.Errorf("subprocess stopped"))
+	return nil
+}
+
+func (s *SubprocessRuntime) stop() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.cmd == nil || s.cmd.Process == nil {
-		return nil
+	if s.proc == nil {
+		return
 	}
+	proc := s.proc
+	s.proc = nil
 
 	// Send interrupt signal
-	s.cmd.Process.Signal(os.Interrupt) //nolint:errcheck
-
-	done := make(chan error, 1)
-	go func() { done <- s.cmd.Wait() }()
+	proc.cmd.Process.Signal(os.Interrupt) //nolint:errcheck
 
 	select {
-	case <-done:
-		return nil
+	case <-proc.done:
 	case <-time.After(5 * time.Second):
-		s.cmd.Process.Kill() //nolint:errcheck
-		return nil
+		proc.cmd.Process.Kill() //nolint:errcheck
+		<-proc.done
 	}
 }
 
-// Restart stops and re-starts the subprocess.
+// Restart stops and re-starts the subprocess. Requests made meanwhile wait
+// for the new process to pass its readiness check. Restart also clears a
+// previous give-up after repeated crashes.
 func (s *SubprocessRuntime) Restart(ctx context.Context) error {
 	s.logger.Info("restarting subprocess", nil)
-	if err := s.Stop(); err != nil {
-		s.logger.Warn("stop error during restart", map[string]any{"error": err.Error()})
-	}
-	return s.Start(ctx)
+	s.lifeMu.Lock()
+	defer s.lifeMu.Unlock()
+	s.notReady()
+	s.stop()
+
+	s.mu.Lock()
+	s.crashes, s.gaveUp = 0, nil
+	s.mu.Unlock()
+	s.stopped = false
+	err := s.start(ctx)
+	s.markReady(err)
+	return err
 }
 
 // findFreePort binds to port 0, reads the assigned port, and closes.
@@ -4,9 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
 	coreruntime "github.com/initializ/forge/forge-core/runtime"
@@ -124,3 +132,317 @@ func TestMustMarshal(t *testing.T) {
 		t.Errorf("id: got %q", decoded.ID)
 	}
 }
+
+// TestSubprocessHelper is not a real test. It runs as the agent subprocess
+// when FORGE_TEST_SUBPROCESS is set: it records its session environment in
+// the session directory and serves until interrupted. FORGE_TEST_EXIT makes
+// it exit at once, FORGE_TEST_READY_DELAY delays listening,
+// FORGE_TEST_READY_PATH replaces /healthz as the readiness path, and
+// FORGE_TEST_CRASH_AFTER makes it exit with status 1 after serving that
+// long. With FORGE_TEST_CRASH_ONCE set to a file path, it crashes only
+// while that file does not exist yet.
+func TestSubprocessHelper(t *testing.T) {
+	if os.Getenv("FORGE_TEST_SUBPROCESS") != "1" {
+		t.Skip("helper process only")
+	}
+	dir := os.Getenv("FORGE_SESSION_DIR")
+	f, err := os.OpenFile(filepath.Join(dir, "starts.log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		os.Exit(1)
+	}
+	fmt.Fprintf(f, "%s %s %s\n", dir, os.Getenv("FORGE_SESSION_ID"), os.Getenv("FORGE_SESSION_RESUMED")) //nolint:errcheck
+	_ = f.Close()
+
+	if os.Getenv("FORGE_TEST_EXIT") != "" {
+		os.Exit(3)
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	readyPath := "/healthz"
+	if p := os.Getenv("FORGE_TEST_READY_PATH"); p != "" {
+		readyPath = p
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(readyPath, func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var req a2a.JSONRPCRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(a2a.JSONRPCResponse{ //nolint:errcheck
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  a2a.Task{ID: fmt.Sprint(req.ID), Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}},
+		})
+	})
+	if d, err := time.ParseDuration(os.Getenv("FORGE_TEST_READY_DELAY")); err == nil {
+		select {
+		case <-time.After(d):
+		case <-sig:
+			os.Exit(0)
+		}
+	}
+	go http.ListenAndServe("127.0.0.1:"+os.Getenv("PORT"), mux) //nolint:errcheck
+
+	var crash <-chan time.Time
+	if d, err := time.ParseDuration(os.Getenv("FORGE_TEST_CRASH_AFTER")); err == nil {
+		crash = time.After(d)
+		if marker := os.Getenv("FORGE_TEST_CRASH_ONCE"); marker != "" {
+			if _, err := os.Stat(marker); err == nil {
+				crash = nil
+			} else {
+				_ = os.WriteFile(marker, nil, 0600)
+			}
+		}
+	}
+	select {
+	case <-sig:
+		os.Exit(0)
+	case <-crash:
+		os.Exit(1)
+	}
+}
+
+// recordLogger keeps log entries for assertions.
+type recordLogger struct {
+	mu      sync.Mutex
+	entries []recordedLog
+}
+
+type recordedLog struct {
+	msg    string
+	fields map[string]any
+}
+
+func (l *recordLogger) Info(msg string, fields map[string]any)  { l.add(msg, fields) }
+func (l *recordLogger) Warn(msg string, fields map[string]any)  { l.add(msg, fields) }
+func (l *recordLogger) Error(msg string, fields map[string]any) { l.add(msg, fields) }
+func (l *recordLogger) Debug(msg string, fields map[string]any) {}
+
+func (l *recordLogger) add(msg string, fields map[string]any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, recordedLog{msg, fields})
+}
+
+// find returns the entries with message msg.
+func (l *recordLogger) find(msg string) []recordedLog {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var found []recordedLog
+	for _, e := range l.entries {
+		if e.msg == msg {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// helperRuntime returns a runtime whose entrypoint is TestSubprocessHelper.
+func helperRuntime(t *testing.T, env map[string]string) *SubprocessRuntime {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil || strings.ContainsAny(exe, " \t") {
+		t.Skip("test binary path unusable as an entrypoint")
+	}
+	env["FORGE_TEST_SUBPROCESS"] = "1"
+	rt := NewSubprocessRuntime(exe+" -test.run=^TestSubprocessHelper$", t.TempDir(),
+		env, coreruntime.NewJSONLogger(&bytes.Buffer{}, false))
+	t.Cleanup(func() { _ = rt.Stop() })
+	return rt
+}
+
+func TestSubprocessRuntime_WaitsForDelayedReadiness(t *testing.T) {
+	rt := helperRuntime(t, map[string]string{
+		"FORGE_TEST_READY_DELAY": "500ms",
+		"FORGE_TEST_READY_PATH":  "/ready",
+	})
+	rt.SetReadiness("/ready", 10*time.Second)
+
+	start := time.Now()
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("Start returned after %s, before the subprocess was listening", elapsed)
+	}
+
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	task, err := rt.Invoke(context.Background(), "t-1", msg)
+	if err != nil {
+		t.Fatalf("Invoke: %v", err)
+	}
+	if task.ID != "t-1" {
+		t.Errorf("task id = %q, want t-1", task.ID)
+	}
+}
+
+func TestSubprocessRuntime_ExitBeforeReady(t *testing.T) {
+	rt := helperRuntime(t, map[string]string{"FORGE_TEST_EXIT": "1"})
+
+	start := time.Now()
+	err := rt.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "exited before becoming ready") {
+		t.Fatalf("Start error = %v, want exited before becoming ready", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Start took %s; an exited process should fail fast", elapsed)
+	}
+
+	// Requests fail with the reason instead of reaching a dead port
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	if _, err := rt.Invoke(context.Background(), "t-1", msg); err == nil || !strings.Contains(err.Error(), "not ready") {
+		t.Errorf("Invoke error = %v, want not ready", err)
+	}
+}
+
+func TestSubprocessRuntime_ReadinessTimeout(t *testing.T) {
+	rt := helperRuntime(t, map[string]string{"FORGE_TEST_READY_DELAY": "30s"})
+	rt.SetReadiness("", 300*time.Millisecond)
+
+	err := rt.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "GET /healthz") || !strings.Contains(err.Error(), "within 300ms") {
+		t.Fatalf("Start error = %v, want readiness timeout", err)
+	}
+}
+
+func TestSubprocessRuntime_RestartHoldsRequests(t *testing.T) {
+	rt := helperRuntime(t, map[string]string{"FORGE_TEST_READY_DELAY": "300ms"})
+	ctx := context.Background()
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	restarted := make(chan error, 1)
+	go func() { restarted <- rt.Restart(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+
+	// Sent while the new process is still starting: it must wait, not fail
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	if _, err := rt.Invoke(ctx, "t-1", msg); err != nil {
+		t.Errorf("Invoke during restart: %v", err)
+	}
+	if err := <-restarted; err != nil {
+		t.Fatalf("Restart: %v", err)
+	}
+}
+
+func TestSubprocessRuntime_RestartsAfterCrash(t *testing.T) {
+	rt := helperRuntime(t, map[string]string{
+		"FORGE_TEST_CRASH_AFTER": "200ms",
+		"FORGE_TEST_CRASH_ONCE":  filepath.Join(t.TempDir(), "crashed"),
+	})
+	logs := &recordLogger{}
+	rt.logger = logs
+	rt.SetRestartPolicy(3, 50*time.Millisecond)
+
+	ctx := context.Background()
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	// The request waits for the restart instead of reaching the dead process
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	if _, err := rt.Invoke(ctx, "t-1", msg); err != nil {
+		t.Fatalf("Invoke after crash: %v", err)
+	}
+	if !rt.Healthy(ctx) || rt.Err() != nil {
+		t.Errorf("Healthy = %v, Err = %v, want healthy after restart", rt.Healthy(ctx), rt.Err())
+	}
+
+	exits := logs.find("subprocess exited unexpectedly")
+	if len(exits) != 1 || exits[0].fields["exit_code"] != 1 {
+		t.Errorf("exit logs = %+v, want one with exit_code 1", exits)
+	}
+	if restarts := logs.find("restarting crashed subprocess"); len(restarts) != 1 {
+		t.Errorf("got %d restarts, want 1", len(restarts))
+	}
+}
+
+func TestSubprocessRuntime_GivesUpAfterRepeatedCrashes(t *testing.T) {
+	rt := helperRuntime(t, map[string]string{"FORGE_TEST_CRASH_AFTER": "100ms"})
+	logs := &recordLogger{}
+	rt.logger = logs
+	rt.SetRestartPolicy(3, 20*time.Millisecond)
+
+	ctx := context.Background()
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	deadline := time.Now().Add(20 * time.Second)
+	for rt.Err() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("runtime never gave up on the crashing subprocess")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !strings.Contains(rt.Err().Error(), "crashed 4 times in a row") {
+		t.Errorf("Err = %v, want crashed 4 times in a row", rt.Err())
+	}
+	if rt.Healthy(ctx) {
+		t.Error("Healthy = true after giving up")
+	}
+
+	var backoffs []string
+	for _, e := range logs.find("restarting crashed subprocess") {
+		backoffs = append(backoffs, e.fields["backoff"].(string))
+	}
+	if want := []string{"20ms", "40ms", "80ms"}; !slices.Equal(backoffs, want) {
+		t.Errorf("backoffs = %v, want %v", backoffs, want)
+	}
+
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	if _, err := rt.Invoke(ctx, "t-1", msg); err == nil || !strings.Contains(err.Error(), "not restarting") {
+		t.Errorf("Invoke error = %v, want the give-up reason", err)
+	}
+
+	// An explicit restart, as after a file change, tries again
+	rt.env["FORGE_TEST_CRASH_AFTER"] = ""
+	if err := rt.Restart(ctx); err != nil {
+		t.Fatalf("Restart: %v", err)
+	}
+	if rt.Err() != nil || !rt.Healthy(ctx) {
+		t.Errorf("Err = %v after Restart, want healthy", rt.Err())
+	}
+}
+
+func TestSubprocessRuntime_RestartKeepsSession(t *testing.T) {
+	sessionDir := filepath.Join(t.TempDir(), "session")
+	rt := helperRuntime(t, map[string]string{})
+	rt.SetSessionDir(sessionDir)
+
+	ctx := context.Background()
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := rt.Restart(ctx); err != nil {
+		t.Fatalf("Restart: %v", err)
+	}
+	_ = rt.Stop()
+
+	data, err := os.ReadFile(filepath.Join(sessionDir, "starts.log"))
+	if err != nil {
+		t.Fatalf("reading starts log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d starts, want 2:\n%s", len(lines), data)
+	}
+	first, second := strings.Fields(lines[0]), strings.Fields(lines[1])
+	if first[0] != sessionDir || second[0] != sessionDir {
+		t.Errorf("session dirs = %q, %q, want %q", first[0], second[0], sessionDir)
+	}
+	if first[1] == "" || first[1] != second[1] {
+		t.Errorf("session ids = %q, %q, want the same stable id", first[1], second[1])
+	}
+	if first[2] != "false" || second[2] != "true" {
+		t.Errorf("resumed = %q, %q, want false then true", first[2], second[2])
+	}
+
+	// The id is persisted so later forge runs reuse the same session
+	id, _ := os.ReadFile(filepath.Join(sessionDir, sessionIDFile))
+	if strings.TrimSpace(string(id)) != first[1] {
+		t.Errorf("persisted session id = %q, want %q", id, first[1])
+	}
+}
@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// defaultOTLPEndpoint is the standard local OTLP/HTTP collector address.
+const defaultOTLPEndpoint = "http://localhost:4318"
+
+// otelScope is the instrumentation scope of the executor's OpenTelemetry
+// spans.
+const otelScope = "github.com/initializ/forge"
+
+// newTracerProvider returns an OpenTelemetry tracer provider exporting
+// spans with OTLP/HTTP when tracing is enabled in forge.yaml or by
+// --trace-openinference, or nil otherwise.
+func (r *Runner) newTracerProvider() *sdktrace.TracerProvider {
+	tc := r.cfg.Config.Tracing
+	if tc.Format == "" && !r.cfg.TraceOpenInference {
+		return nil
+	}
+	endpoint := r.traceEndpoint()
+	r.logger.Info("exporting traces", map[string]any{"endpoint": endpoint, "format": r.traceFormat()})
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(otlpTracesURL(endpoint))}
+	if len(tc.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(tc.Headers))
+	}
+	// New only fails on invalid options; the client connects lazily
+	exp, _ := otlptracehttp.New(context.Background(), opts...)
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(reportingExporter{SpanExporter: exp, onError: r.traceExportFailed}),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(r.cfg.Config.AgentID))),
+	)
+}
+
+// traceEndpoint returns the OTLP/HTTP collector base URL: tracing.endpoint,
+// then OTEL_EXPORTER_OTLP_ENDPOINT, then the local default.
+func (r *Runner) traceEndpoint() string {
+	if endpoint := r.cfg.Config.Tracing.Endpoint; endpoint != "" {
+		return endpoint
+	}
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	return defaultOTLPEndpoint
+}
+
+// otlpTracesURL appends the OTLP traces path to a collector base URL unless
+// it is already there.
+func otlpTracesURL(endpoint string) string {
+	endpoint = strings.TrimRight(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint += "/v1/traces"
+	}
+	return endpoint
+}
+
+func (r *Runner) traceExportFailed(err error) {
+	r.logger.Warn("trace export failed", map[string]any{"error": err.Error()})
+}
+
+// traceFormat returns the span attributes to export: "otel" for the
+// executor's GenAI spans, or "openinference". --trace-openinference wins over forge.yaml.
+func (r *Runner) traceFormat() string {
+	if r.cfg.Config.Tracing.Format == "otel" && !r.cfg.TraceOpenInference {
+		return "otel"
+	}
+	return "openinference"
+}
+
+// shutdownTracer waits briefly for pending trace exports.
+func (r *Runner) shutdownTracer(t *sdktrace.TracerProvider) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := t.Shutdown(ctx); err != nil {
+		r.logger.Warn("trace export did not finish", map[string]any{"error": err.Error()})
+	}
+}
+
+// reportingExporter sends export failures to onError rather than to the
+// SDK's global error handler.
+type reportingExporter struct {
+	sdktrace.SpanExporter
+	onError func(error)
+}
+
+func (e reportingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if err := e.SpanExporter.ExportSpans(ctx, spans); err != nil && e.onError != nil {
+		e.onError(err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestNewTracerProvider_ExportsOTLP(t *testing.T) {
+	var mu sync.Mutex
+	var path, auth string
+	var body int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		path, auth, body = r.URL.Path, r.Header.Get("Authorization"), len(data)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	runner, err := NewRunner(RunnerConfig{
+		Config: &types.ForgeConfig{
+			AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py",
+			Tracing: types.TracingRef{Format: "otel", Endpoint: srv.URL, Headers: map[string]string{"Authorization": "Bearer t"}},
+		},
+		WorkDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := runner.newTracerProvider()
+	_, span := provider.Tracer(otelScope).Start(context.Background(), "invoke_agent gpt-4o")
+	span.End()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if path != "/v1/traces" || auth != "Bearer t" || body == 0 {
+		t.Errorf("collector got path %q, auth %q, %d bytes", path, auth, body)
+	}
+}
+
+func TestReportingExporter_ReportsFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	runner := newFixtureRunner(t)
+	runner.cfg.Config.Tracing = types.TracingRef{Format: "otel", Endpoint: srv.URL + "/v1/traces"}
+	var logs bytes.Buffer
+	runner.logger = coreruntime.NewJSONLogger(&logs, false)
+	provider := runner.newTracerProvider()
+	_, span := provider.Tracer(otelScope).Start(context.Background(), "chat gpt-4o")
+	span.End()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() = %v, want failures reported to the logger", err)
+	}
+	if !strings.Contains(logs.String(), "trace export failed") {
+		t.Errorf("export failure not logged: %s", logs.String())
+	}
+}
@@ -0,0 +1,110 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+)
+
+// TaskUsage is the token usage and estimated cost reported under the
+// "usage" key of task metadata.
+type TaskUsage struct {
+	Model            string                  `json:"model,omitempty"` // model of the most recent call
+	LLMCalls         int                     `json:"llm_calls"`
+	PromptTokens     int                     `json:"prompt_tokens"`
+	CompletionTokens int                     `json:"completion_tokens"`
+	TotalTokens      int                     `json:"total_tokens"`
+	EstimatedCostUSD *float64                `json:"estimated_cost_usd"` // null when a model has no pricing
+	Models           []coreruntime.ModelCost `json:"models,omitempty"`   // per-model breakdown when several models served the task
+}
+
+// sessionUsage accumulates usage across all tasks served by the runner.
+type sessionUsage struct {
+	mu       sync.Mutex
+	tasks    int
+	tokens   int
+	costUSD  float64
+	unpriced int // tasks using a model without pricing
+}
+
+// pricingTable returns the default pricing with overrides from forge.yaml.
+func (r *Runner) pricingTable() llm.PricingTable {
+	if len(r.cfg.Config.Pricing) == 0 {
+		return llm.DefaultPricing
+	}
+	overrides := make(llm.PricingTable, len(r.cfg.Config.Pricing))
+	for model, p := range r.cfg.Config.Pricing {
+		overrides[model] = llm.ModelPricing{
+			InputPerMillion:      p.Input,
+			OutputPerMillion:     p.Output,
+			CacheReadPerMillion:  p.CacheRead,
+			CacheWritePerMillion: p.CacheWrite,
+		}
+	}
+	return llm.DefaultPricing.Merge(overrides)
+}
+
+// recordTaskUsage stores the usage collected by tracker in the task's
+// metadata, logs it, and adds it to the session totals. Tasks that made no
+// LLM calls are left untouched.
+func (r *Runner) recordTaskUsage(task *a2a.Task, tracker *coreruntime.UsageTracker) {
+	model, _, calls := tracker.Totals()
+	if calls == 0 {
+		return
+	}
+
+	summary := tracker.Summary(r.pricingTable())
+	tu := &TaskUsage{
+		Model:            model,
+		LLMCalls:         summary.LLMCalls,
+		PromptTokens:     summary.PromptTokens,
+		CompletionTokens: summary.CompletionTokens,
+		TotalTokens:      summary.TotalTokens,
+	}
+	fields := map[string]any{"task_id": task.ID, "model": model, "tokens": summary.TotalTokens}
+	if len(summary.Models) > 1 {
+		tu.Models = summary.Models
+		fields["models"] = summary.Models
+	}
+	cost, priced := summary.EstimatedCostUSD, len(summary.Unpriced) == 0
+	if priced {
+		tu.EstimatedCostUSD = &cost
+		fields["estimated_cost_usd"] = cost
+	} else {
+		fields["estimated_cost_usd"] = "unavailable"
+	}
+
+	if task.Metadata == nil {
+		task.Metadata = map[string]any{}
+	}
+	task.Metadata["usage"] = tu
+	r.logger.Info("task usage", fields)
+
+	r.usage.mu.Lock()
+	defer r.usage.mu.Unlock()
+	r.usage.tasks++
+	r.usage.tokens += summary.TotalTokens
+	if priced {
+		r.usage.costUSD += cost
+	} else {
+		r.usage.unpriced++
+	}
+}
+
+// printUsageSummary prints the session's accumulated usage and cost.
+func (r *Runner) printUsageSummary() {
+	r.usage.mu.Lock()
+	defer r.usage.mu.Unlock()
+	if r.usage.tasks == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n  Session usage: %d task(s), %d tokens, estimated cost $%.4f\n",
+		r.usage.tasks, r.usage.tokens, r.usage.costUSD)
+	if r.usage.unpriced > 0 {
+		fmt.Fprintf(os.Stderr, "  Cost unavailable for %d task(s) using models without pricing\n", r.usage.unpriced)
+	}
+}
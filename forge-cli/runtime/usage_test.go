@@ -0,0 +1,113 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	coreruntime "github.com/initializ/forge/forge-core/runtime"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// usageExecutor reports fixed token usage for model, as an LLM executor would.
+type usageExecutor struct {
+	model string
+}
+
+func (e *usageExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	coreruntime.RecordUsage(ctx, e.model, llm.UsageInfo{PromptTokens: 10000, CompletionTokens: 2000, TotalTokens: 12000})
+	return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("done")}}, nil
+}
+
+func (e *usageExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (e *usageExecutor) Close() error { return nil }
+
+// getTaskUsage fetches a task with tasks/get and decodes its usage metadata.
+func getTaskUsage(t *testing.T, baseURL, taskID string) TaskUsage {
+	t.Helper()
+	body, _ := json.Marshal(a2a.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      "get",
+		Method:  "tasks/get",
+		Params:  mustMarshal(a2a.GetTaskParams{ID: taskID}),
+	})
+	resp, err := http.Post(baseURL+"/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("tasks/get: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var rpcResp struct {
+		Result struct {
+			Metadata struct {
+				Usage *TaskUsage `json:"usage"`
+			} `json:"metadata"`
+		} `json:"result"`
+	}
+	json.NewDecoder(resp.Body).Decode(&rpcResp) //nolint:errcheck
+	if rpcResp.Result.Metadata.Usage == nil {
+		t.Fatalf("task %s has no usage metadata", taskID)
+	}
+	return *rpcResp.Result.Metadata.Usage
+}
+
+func TestRunner_TaskUsageMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    string
+		pricing  map[string]types.ModelPriceRef
+		wantCost *float64
+	}{
+		{name: "default pricing", model: "gpt-4o", wantCost: ptr(0.045)}, // 10000*2.50/1M + 2000*10/1M
+		{name: "config override", model: "gpt-4o", pricing: map[string]types.ModelPriceRef{"gpt-4o": {Input: 1, Output: 1}}, wantCost: ptr(0.012)},
+		{name: "unknown model", model: "llama3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port, err := findFreePort()
+			if err != nil {
+				t.Fatal(err)
+			}
+			runner, err := NewRunner(RunnerConfig{
+				Config: &types.ForgeConfig{AgentID: "test-agent", Version: "0.1.0", Entrypoint: "main.py", Pricing: tt.pricing},
+				Port:   port,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			baseURL := startHandlerServer(t, runner, &usageExecutor{model: tt.model})
+
+			sendTask(t, baseURL, a2a.SendTaskParams{
+				ID:      "usage-1",
+				Message: a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("go")}},
+			})
+			usage := getTaskUsage(t, baseURL, "usage-1")
+
+			if usage.Model != tt.model || usage.TotalTokens != 12000 || usage.LLMCalls != 1 {
+				t.Errorf("usage = %+v", usage)
+			}
+			switch {
+			case tt.wantCost == nil && usage.EstimatedCostUSD != nil:
+				t.Errorf("cost = %v, want unavailable (null)", *usage.EstimatedCostUSD)
+			case tt.wantCost != nil && usage.EstimatedCostUSD == nil:
+				t.Errorf("cost unavailable, want %v", *tt.wantCost)
+			case tt.wantCost != nil && fmt.Sprintf("%.6f", *usage.EstimatedCostUSD) != fmt.Sprintf("%.6f", *tt.wantCost):
+				t.Errorf("cost = %v, want %v", *usage.EstimatedCostUSD, *tt.wantCost)
+			}
+
+			if runner.usage.tasks != 1 || runner.usage.tokens != 12000 {
+				t.Errorf("session totals = %d tasks, %d tokens", runner.usage.tasks, runner.usage.tokens)
+			}
+		})
+	}
+}
+
+func ptr(f float64) *float64 { return &f }
@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"github.com/initializ/forge/forge-core/a2a"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 // Handler processes a JSON-RPC request and returns a response.
@@ -21,17 +22,22 @@ type SSEHandler func(ctx context.Context, id any, rawParams json.RawMessage, w h
 // ServerConfig configures the A2A HTTP server.
 type ServerConfig struct {
 	Port      int
+	Listener  net.Listener // already bound listener; when set, Port is ignored
 	AgentCard *a2a.AgentCard
+	TaskStore a2a.TaskStore // nil uses an in-memory store
 }
 
 // Server is an A2A-compliant HTTP server with JSON-RPC 2.0 dispatch.
 type Server struct {
 	port        int
+	ln          net.Listener
 	card        *a2a.AgentCard
 	cardMu      sync.RWMutex
-	store       *a2a.TaskStore
+	store       a2a.TaskStore
 	handlers    map[string]Handler
 	sseHandlers map[string]SSEHandler
+	healthFn    func() any
+	readyFn     func() error
 	srv         *http.Server
 }
 
@@ -39,11 +45,15 @@ type Server struct {
 func NewServer(cfg ServerConfig) *Server {
 	s := &Server{
 		port:        cfg.Port,
+		ln:          cfg.Listener,
 		card:        cfg.AgentCard,
-		store:       a2a.NewTaskStore(),
+		store:       cfg.TaskStore,
 		handlers:    make(map[string]Handler),
 		sseHandlers: make(map[string]SSEHandler),
 	}
+	if s.store == nil {
+		s.store = a2a.NewMemoryTaskStore()
+	}
 	return s
 }
 
@@ -64,12 +74,25 @@ func (s *Server) UpdateAgentCard(card *a2a.AgentCard) {
 	s.card = card
 }
 
+// SetHealthFunc sets the function producing the detailed /health payload.
+// /healthz stays minimal for load balancers.
+func (s *Server) SetHealthFunc(fn func() any) {
+	s.healthFn = fn
+}
+
+// SetReadyFunc sets a check run on every /healthz and /health request.
+// While it returns an error, both respond 503 Service Unavailable.
+func (s *Server) SetReadyFunc(fn func() error) {
+	s.readyFn = fn
+}
+
 // TaskStore returns the server's task store.
-func (s *Server) TaskStore() *a2a.TaskStore {
+func (s *Server) TaskStore() a2a.TaskStore {
 	return s.store
 }
 
-func (s *Server) agentCard() *a2a.AgentCard {
+// AgentCard returns the current agent card.
+func (s *Server) AgentCard() *a2a.AgentCard {
 	s.cardMu.RLock()
 	defer s.cardMu.RUnlock()
 	return s.card
@@ -81,6 +104,7 @@ func (s *Server) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /.well-known/agent.json", s.handleAgentCard)
 	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /health", s.handleHealth)
 	mux.HandleFunc("POST /", s.handleJSONRPC)
 	mux.HandleFunc("GET /", s.handleAgentCard)
 
@@ -89,9 +113,12 @@ func (s *Server) Start(ctx context.Context) error {
 		Handler: corsMiddleware(mux),
 	}
 
-	ln, err := net.Listen("tcp", s.srv.Addr)
-	if err != nil {
-		return fmt.Errorf("listen on %s: %w", s.srv.Addr, err)
+	ln := s.ln
+	if ln == nil {
+		var err error
+		if ln, err = net.Listen("tcp", s.srv.Addr); err != nil {
+			return fmt.Errorf("listen on %s: %w", s.srv.Addr, err)
+		}
 	}
 
 	go func() {
@@ -115,14 +142,37 @@ func (s *Server) Shutdown(ctx context.Context) error {
 
 func (s *Server) handleAgentCard(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(s.agentCard()) //nolint:errcheck
+	json.NewEncoder(w).Encode(s.AgentCard()) //nolint:errcheck
 }
 
 func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if err := s.notReady(); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": err.Error()})
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.Write([]byte(`{"status":"ok"}`)) //nolint:errcheck
 }
 
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if s.healthFn == nil {
+		s.handleHealthz(w, r)
+		return
+	}
+	status := http.StatusOK
+	if s.notReady() != nil {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, s.healthFn())
+}
+
+func (s *Server) notReady() error {
+	if s.readyFn == nil {
+		return nil
+	}
+	return s.readyFn()
+}
+
 func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
 	var req a2a.JSONRPCRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -135,6 +185,9 @@ func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Continue the caller's trace, if it sent one
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
 	// Check SSE handlers first (for streaming methods)
 	if h, ok := s.sseHandlers[req.Method]; ok {
 		flusher, ok := w.(http.Flusher)
@@ -145,13 +198,13 @@ func (s *Server) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/event-stream")
 		w.Header().Set("Cache-Control", "no-cache")
 		w.Header().Set("Connection", "keep-alive")
-		h(r.Context(), req.ID, req.Params, w, flusher)
+		h(ctx, req.ID, req.Params, w, flusher)
 		return
 	}
 
 	// Check regular handlers
 	if h, ok := s.handlers[req.Method]; ok {
-		resp := h(r.Context(), req.ID, req.Params)
+		resp := h(ctx, req.ID, req.Params)
 		writeJSON(w, http.StatusOK, resp)
 		return
 	}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestServer_HealthzReflectsReadyFunc(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(ServerConfig{Listener: ln})
+	var failed atomic.Bool
+	srv.SetReadyFunc(func() error {
+		if failed.Load() {
+			return errors.New("subprocess crashed")
+		}
+		return nil
+	})
+	srv.SetHealthFunc(func() any { return map[string]string{"status": "detail"} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Start(ctx) //nolint:errcheck
+
+	baseURL := fmt.Sprintf("http://%s", ln.Addr())
+	get := func(path string) int {
+		t.Helper()
+		client := &http.Client{Timeout: 2 * time.Second}
+		resp, err := client.Get(baseURL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for _, path := range []string{"/healthz", "/health"} {
+		if code := get(path); code != http.StatusOK {
+			t.Errorf("%s = %d while ready, want 200", path, code)
+		}
+	}
+	failed.Store(true)
+	for _, path := range []string{"/healthz", "/health"} {
+		if code := get(path); code != http.StatusServiceUnavailable {
+			t.Errorf("%s = %d after the backend failed, want 503", path, code)
+		}
+	}
+}
+
+func TestServer_ContinuesCallerTrace(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(ServerConfig{Listener: ln})
+	var otelParent trace.SpanContext
+	srv.RegisterHandler("tasks/send", func(ctx context.Context, id any, _ json.RawMessage) *a2a.JSONRPCResponse {
+		otelParent = trace.SpanContextFromContext(ctx)
+		return a2a.NewResponse(id, map[string]string{})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Start(ctx) //nolint:errcheck
+
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/", ln.Addr()),
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tasks/send","params":{}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	client := &http.Client{Timeout: 2 * time.Second}
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = client.Do(req); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if !otelParent.IsRemote() || otelParent.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || otelParent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("OpenTelemetry parent = %v, want the caller's span", otelParent)
+	}
+}
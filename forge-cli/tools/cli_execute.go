@@ -82,6 +82,7 @@ func (t *CLIExecuteTool) Name() string { return "cli_execute" }
 
 // Category returns CategoryBuiltin.
 func (t *CLIExecuteTool) Category() coretools.Category { return coretools.CategoryBuiltin }
+func (t *CLIExecuteTool) Mutation() coretools.Mutation { return coretools.MutationMutating }
 
 // Description returns a dynamic description listing available binaries.
 func (t *CLIExecuteTool) Description() string {
@@ -91,6 +92,17 @@ func (t *CLIExecuteTool) Description() string {
 	return fmt.Sprintf("Execute pre-approved CLI binaries: %s", strings.Join(t.available, ", "))
 }
 
+// Guidance tells the model to stay within the allowlist and that no shell is
+// involved, so pipes and redirection are unavailable.
+func (t *CLIExecuteTool) Guidance() string {
+	bins := "none are currently available"
+	if len(t.available) > 0 {
+		bins = strings.Join(t.available, ", ")
+	}
+	return fmt.Sprintf("Use cli_execute only for the allowed binaries (%s). Commands run without a shell, "+
+		"so pass each argument separately in args and do not use pipes, redirection, or globbing.", bins)
+}
+
 // InputSchema returns a dynamic JSON schema with the binary field's enum
 // populated from AllowedBinaries.
 func (t *CLIExecuteTool) InputSchema() json.RawMessage {
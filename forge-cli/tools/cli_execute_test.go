@@ -6,6 +6,8 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+
+	coretools "github.com/initializ/forge/forge-core/tools"
 )
 
 func TestCLIExecute_Name(t *testing.T) {
@@ -26,6 +28,22 @@ func TestCLIExecute_Category(t *testing.T) {
 	}
 }
 
+func TestCLIExecute_ExcludedInSafeMode(t *testing.T) {
+	tool := NewCLIExecuteTool(CLIExecuteConfig{
+		AllowedBinaries: []string{"echo"},
+	})
+	reg := coretools.NewRegistry()
+	if err := reg.Register(tool); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if reg.ReadOnly(nil).Get("cli_execute") != nil {
+		t.Error("cli_execute should be excluded in safe mode")
+	}
+	if reg.ReadOnly([]string{"cli_execute"}).Get("cli_execute") == nil {
+		t.Error("cli_execute should remain when explicitly allowed")
+	}
+}
+
 func TestCLIExecute_DynamicSchema(t *testing.T) {
 	tool := NewCLIExecuteTool(CLIExecuteConfig{
 		AllowedBinaries: []string{"curl", "jq"},
@@ -31,6 +31,7 @@ func (t *LocalFileBrowserTool) Description() string {
 	return "Read and list files in the project directory"
 }
 func (t *LocalFileBrowserTool) Category() tools.Category { return tools.CategoryDev }
+func (t *LocalFileBrowserTool) Mutation() tools.Mutation { return tools.MutationReadOnly }
 
 func (t *LocalFileBrowserTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
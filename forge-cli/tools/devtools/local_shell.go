@@ -34,6 +34,7 @@ func (t *LocalShellTool) Description() string {
 	return "Execute shell commands in the project directory"
 }
 func (t *LocalShellTool) Category() tools.Category { return tools.CategoryDev }
+func (t *LocalShellTool) Mutation() tools.Mutation { return tools.MutationMutating }
 
 func (t *LocalShellTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -0,0 +1,57 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+)
+
+// MetadataKeyCallDepth is the task metadata key carrying the number of
+// agent-to-agent delegations that led to a task. A task sent directly by a
+// user has depth 0.
+const MetadataKeyCallDepth = "forge_call_depth"
+
+// DefaultMaxCallDepth is the delegation depth limit used when none is configured.
+const DefaultMaxCallDepth = 5
+
+type callDepthKey struct{}
+
+// CallDepth reads the delegation depth from task metadata. Missing or
+// malformed values yield 0.
+func CallDepth(metadata map[string]any) int {
+	switch v := metadata[MetadataKeyCallDepth].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64: // numbers decoded from JSON
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// CheckCallDepth returns an error if depth exceeds maxDepth.
+func CheckCallDepth(depth, maxDepth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("agent delegation depth %d exceeds maximum of %d; refusing to delegate further to prevent recursive agent calls", depth, maxDepth)
+	}
+	return nil
+}
+
+// WithCallDepth returns a context recording the delegation depth of the
+// task being executed.
+func WithCallDepth(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, callDepthKey{}, depth)
+}
+
+// CallDepthFromContext returns the delegation depth stored in ctx, or 0.
+func CallDepthFromContext(ctx context.Context) int {
+	depth, _ := ctx.Value(callDepthKey{}).(int)
+	return depth
+}
+
+// DelegationMetadata returns the metadata a delegating tool must attach to
+// an outgoing tasks/send request: the current depth plus one.
+func DelegationMetadata(ctx context.Context) map[string]any {
+	return map[string]any{MetadataKeyCallDepth: CallDepthFromContext(ctx) + 1}
+}
@@ -0,0 +1,73 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCallDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]any
+		want     int
+	}{
+		{"nil metadata", nil, 0},
+		{"missing key", map[string]any{"other": 1}, 0},
+		{"int", map[string]any{MetadataKeyCallDepth: 3}, 3},
+		{"float from JSON", map[string]any{MetadataKeyCallDepth: float64(2)}, 2},
+		{"wrong type", map[string]any{MetadataKeyCallDepth: "4"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CallDepth(tt.metadata); got != tt.want {
+				t.Errorf("CallDepth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCallDepth_NestedDelegation simulates agents delegating to each other
+// over the wire until the depth limit blocks the chain.
+func TestCallDepth_NestedDelegation(t *testing.T) {
+	const maxDepth = 3
+	ctx := context.Background()
+
+	hops := 0
+	var blockErr error
+	for i := 0; i < 10; i++ {
+		// Delegating agent builds the outgoing request
+		out, err := json.Marshal(SendTaskParams{
+			ID:       "t",
+			Message:  Message{Role: MessageRoleUser, Parts: []Part{NewTextPart("loop")}},
+			Metadata: DelegationMetadata(ctx),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Receiving agent decodes and enforces the limit
+		var params SendTaskParams
+		if err := json.Unmarshal(out, &params); err != nil {
+			t.Fatal(err)
+		}
+		depth := CallDepth(params.Metadata)
+		if err := CheckCallDepth(depth, maxDepth); err != nil {
+			blockErr = err
+			break
+		}
+		hops++
+		ctx = WithCallDepth(ctx, depth)
+	}
+
+	if hops != maxDepth {
+		t.Errorf("allowed %d hops, want %d", hops, maxDepth)
+	}
+	if blockErr == nil {
+		t.Fatal("expected delegation to be blocked")
+	}
+	if !strings.Contains(blockErr.Error(), "depth 4 exceeds maximum of 3") {
+		t.Errorf("error = %q, want descriptive depth message", blockErr)
+	}
+}
@@ -1,6 +1,9 @@
 package a2a
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // JSON-RPC 2.0 error codes.
 const (
@@ -9,6 +12,10 @@ const (
 	ErrCodeMethodNotFound = -32601
 	ErrCodeInvalidParams  = -32602
 	ErrCodeInternal       = -32603
+
+	// ErrCodePushNotificationNotSupported is the A2A error for push
+	// notification methods on an agent that does not offer them.
+	ErrCodePushNotificationNotSupported = -32003
 )
 
 // JSONRPCRequest is an incoming JSON-RPC 2.0 request.
@@ -36,8 +43,32 @@ type JSONRPCError struct {
 
 // SendTaskParams are the parameters for tasks/send and tasks/sendSubscribe.
 type SendTaskParams struct {
-	ID      string  `json:"id"`
-	Message Message `json:"message"`
+	ID               string                  `json:"id"`
+	SessionID        string                  `json:"sessionId,omitempty"` // a Task.SessionID from an earlier task, or any value to start a session
+	Message          Message                 `json:"message"`
+	Metadata         map[string]any          `json:"metadata,omitempty"`
+	PushNotification *PushNotificationConfig `json:"pushNotification,omitempty"`
+}
+
+// PushNotificationConfig is where the agent POSTs a task when it finishes.
+type PushNotificationConfig struct {
+	URL            string              `json:"url"`
+	Token          string              `json:"token,omitempty"` // sent back in the X-A2A-Notification-Token header
+	Authentication *AuthenticationInfo `json:"authentication,omitempty"`
+}
+
+// AuthenticationInfo is how the agent authenticates to a client's webhook.
+// The first scheme and the credentials are sent as the Authorization header.
+type AuthenticationInfo struct {
+	Schemes     []string `json:"schemes"`
+	Credentials string   `json:"credentials,omitempty"`
+}
+
+// TaskPushNotificationConfig is the params and result of
+// tasks/pushNotification/set, and the result of tasks/pushNotification/get.
+type TaskPushNotificationConfig struct {
+	ID                     string                 `json:"id"`
+	PushNotificationConfig PushNotificationConfig `json:"pushNotificationConfig"`
 }
 
 // GetTaskParams are the parameters for tasks/get.
@@ -45,6 +76,31 @@ type GetTaskParams struct {
 	ID string `json:"id"`
 }
 
+// ListTasksParams are the parameters for tasks/list.
+type ListTasksParams struct {
+	State   TaskState `json:"state,omitempty"` // only tasks in this state
+	Offset  int       `json:"offset,omitempty"`
+	Limit   int       `json:"limit,omitempty"`
+	Verbose bool      `json:"verbose,omitempty"` // include each full task
+}
+
+// ListTasksResult is the result of tasks/list: a page of tasks, newest
+// first, and the total number that match.
+type ListTasksResult struct {
+	Tasks []TaskSummary `json:"tasks"`
+	Total int           `json:"total"`
+}
+
+// TaskSummary describes a task in a tasks/list result. Task, with its
+// history and artifacts, is set only for verbose requests.
+type TaskSummary struct {
+	ID        string    `json:"id"`
+	State     TaskState `json:"state"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Task      *Task     `json:"task,omitempty"`
+}
+
 // CancelTaskParams are the parameters for tasks/cancel.
 type CancelTaskParams struct {
 	ID string `json:"id"`
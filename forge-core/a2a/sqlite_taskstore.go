@@ -0,0 +1,380 @@
+package a2a
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// sqliteSchema holds each task as JSON columns, plus every status it moved
+// through in task_status_history.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS tasks (
+	seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+	id         TEXT NOT NULL UNIQUE,
+	state      TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	history    TEXT NOT NULL,
+	artifacts  TEXT NOT NULL,
+	metadata   TEXT NOT NULL,
+	created_at TEXT NOT NULL,
+	updated_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS task_status_history (
+	task_id     TEXT NOT NULL,
+	status      TEXT NOT NULL,
+	recorded_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS task_status_history_task ON task_status_history (task_id);
+`
+
+// SQLiteTaskStore is a TaskStore kept in a SQLite database, so tasks
+// survive restarts. It needs a database/sql driver registered as "sqlite",
+// such as modernc.org/sqlite.
+type SQLiteTaskStore struct {
+	db *sql.DB
+
+	// mu serializes writes so Claim and UpdateStatus read and write a task
+	// atomically
+	mu       sync.Mutex
+	hookMu   sync.RWMutex
+	onUpdate func(*Task)
+	onError  func(error)
+}
+
+var _ TaskStore = (*SQLiteTaskStore)(nil)
+
+// OpenSQLiteTaskStore opens or creates the task database at path. Tasks a
+// previous process left submitted or working are marked failed, since
+// nothing is running them any more.
+func OpenSQLiteTaskStore(path string) (*SQLiteTaskStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening task store: %w", err)
+	}
+	// One connection avoids SQLITE_BUSY between the store's own writers
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("PRAGMA journal_mode=WAL; PRAGMA busy_timeout=5000;" + sqliteSchema); err != nil {
+		db.Close() //nolint:errcheck
+		return nil, fmt.Errorf("creating task store schema: %w", err)
+	}
+	s := &SQLiteTaskStore{db: db}
+	if err := s.failInterrupted(); err != nil {
+		db.Close() //nolint:errcheck
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the database.
+func (s *SQLiteTaskStore) Close() error {
+	return s.db.Close()
+}
+
+// OnUpdate sets a function called with a copy of a task each time it is
+// stored or changed.
+func (s *SQLiteTaskStore) OnUpdate(fn func(*Task)) {
+	s.hookMu.Lock()
+	defer s.hookMu.Unlock()
+	s.onUpdate = fn
+}
+
+// OnError sets a function called when a database operation fails. The
+// TaskStore methods have no error results, so a failed write is otherwise
+// only visible as a missing or stale task.
+func (s *SQLiteTaskStore) OnError(fn func(error)) {
+	s.hookMu.Lock()
+	defer s.hookMu.Unlock()
+	s.onError = fn
+}
+
+func (s *SQLiteTaskStore) reportError(err error) {
+	s.hookMu.RLock()
+	fn := s.onError
+	s.hookMu.RUnlock()
+	if fn != nil {
+		fn(err)
+	}
+}
+
+func (s *SQLiteTaskStore) notify(id string) {
+	s.hookMu.RLock()
+	fn := s.onUpdate
+	s.hookMu.RUnlock()
+	if fn == nil {
+		return
+	}
+	if t := s.Get(id); t != nil {
+		fn(t)
+	}
+}
+
+// Get returns the task with the given ID, or nil if not found.
+func (s *SQLiteTaskStore) Get(id string) *Task {
+	t, err := s.get(id)
+	if err != nil {
+		s.reportError(err)
+		return nil
+	}
+	return t
+}
+
+func (s *SQLiteTaskStore) get(id string) (*Task, error) {
+	row := s.db.QueryRow("SELECT id, status, history, artifacts, metadata FROM tasks WHERE id = ?", id)
+	t, err := scanTask(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return t, err
+}
+
+// Put stores a task. It overwrites any existing task with the same ID.
+func (s *SQLiteTaskStore) Put(t *Task) {
+	s.mu.Lock()
+	err := s.put(t)
+	s.mu.Unlock()
+	if err != nil {
+		s.reportError(err)
+		return
+	}
+	s.notify(t.ID)
+}
+
+// Claim stores t unless a task with the same ID is already in flight
+// (submitted or working) or finished (completed or canceled), with the same
+// rules as MemoryTaskStore.Claim.
+func (s *SQLiteTaskStore) Claim(t *Task) (*Task, bool) {
+	s.mu.Lock()
+	if t.ID != "" {
+		existing, err := s.get(t.ID)
+		if err != nil {
+			s.mu.Unlock()
+			s.reportError(err)
+			return nil, false
+		}
+		if existing != nil {
+			switch existing.Status.State {
+			case TaskStateSubmitted, TaskStateWorking, TaskStateCompleted, TaskStateCanceled:
+				s.mu.Unlock()
+				return existing, false
+			}
+		}
+	}
+	err := s.put(t)
+	s.mu.Unlock()
+	if err != nil {
+		s.reportError(err)
+		return nil, false
+	}
+	s.notify(t.ID)
+	return nil, true
+}
+
+// UpdateStatus updates the status of an existing task. Returns false if the
+// task does not exist.
+func (s *SQLiteTaskStore) UpdateStatus(id string, status TaskStatus) bool {
+	return s.update(id, func(t *Task) { t.Status = status })
+}
+
+// SetArtifacts replaces the artifacts for an existing task. Returns false if
+// the task does not exist.
+func (s *SQLiteTaskStore) SetArtifacts(id string, artifacts []Artifact) bool {
+	return s.update(id, func(t *Task) { t.Artifacts = artifacts })
+}
+
+func (s *SQLiteTaskStore) update(id string, fn func(*Task)) bool {
+	s.mu.Lock()
+	t, err := s.get(id)
+	if err == nil && t != nil {
+		fn(t)
+		err = s.put(t)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		s.reportError(err)
+		return false
+	}
+	if t == nil {
+		return false
+	}
+	s.notify(id)
+	return true
+}
+
+// List returns a page of tasks, newest first, and the number that match.
+func (s *SQLiteTaskStore) List(opts TaskListOptions) ([]TaskEntry, int) {
+	entries := []TaskEntry{}
+	where, args := "", []any{}
+	if opts.State != "" {
+		where, args = " WHERE state = ?", append(args, string(opts.State))
+	}
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM tasks"+where, args...).Scan(&total); err != nil {
+		s.reportError(fmt.Errorf("counting tasks: %w", err))
+		return entries, 0
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = -1 // no limit
+	}
+	rows, err := s.db.Query("SELECT id, status, history, artifacts, metadata, created_at, updated_at FROM tasks"+where+
+		" ORDER BY seq DESC LIMIT ? OFFSET ?", append(args, limit, max(opts.Offset, 0))...)
+	if err != nil {
+		s.reportError(fmt.Errorf("listing tasks: %w", err))
+		return entries, total
+	}
+	defer rows.Close() //nolint:errcheck
+	for rows.Next() {
+		var e TaskEntry
+		var err error
+		var created, updated string
+		e.Task, err = scanTask(rows, &created, &updated)
+		if err == nil {
+			e.CreatedAt, err = time.Parse(time.RFC3339Nano, created)
+		}
+		if err == nil {
+			e.UpdatedAt, err = time.Parse(time.RFC3339Nano, updated)
+		}
+		if err != nil {
+			s.reportError(err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		s.reportError(fmt.Errorf("listing tasks: %w", err))
+	}
+	return entries, total
+}
+
+// StatusHistory returns every status the task has been stored with, oldest
+// first.
+func (s *SQLiteTaskStore) StatusHistory(id string) ([]TaskStatus, error) {
+	rows, err := s.db.Query("SELECT status FROM task_status_history WHERE task_id = ? ORDER BY rowid", id)
+	if err != nil {
+		return nil, fmt.Errorf("reading status history: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+	var history []TaskStatus
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("reading status history: %w", err)
+		}
+		var st TaskStatus
+		if err := json.Unmarshal([]byte(raw), &st); err != nil {
+			return nil, fmt.Errorf("decoding status for task %s: %w", id, err)
+		}
+		history = append(history, st)
+	}
+	return history, rows.Err()
+}
+
+// put writes t and records its status. The caller must hold s.mu.
+func (s *SQLiteTaskStore) put(t *Task) error {
+	cols, err := encodeTaskColumns(t)
+	if err != nil {
+		return fmt.Errorf("encoding task %s: %w", t.ID, err)
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("storing task %s: %w", t.ID, err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+	_, err = tx.Exec(`INSERT INTO tasks (id, state, status, history, artifacts, metadata, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET state = excluded.state, status = excluded.status, history = excluded.history,
+			artifacts = excluded.artifacts, metadata = excluded.metadata, updated_at = excluded.updated_at`,
+		t.ID, string(t.Status.State), cols[0], cols[1], cols[2], cols[3], now, now)
+	if err != nil {
+		return fmt.Errorf("storing task %s: %w", t.ID, err)
+	}
+	if _, err := tx.Exec("INSERT INTO task_status_history (task_id, status, recorded_at) VALUES (?, ?, ?)",
+		t.ID, cols[0], now); err != nil {
+		return fmt.Errorf("storing status of task %s: %w", t.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("storing task %s: %w", t.ID, err)
+	}
+	return nil
+}
+
+// failInterrupted marks tasks left in flight by an earlier process failed.
+func (s *SQLiteTaskStore) failInterrupted() error {
+	rows, err := s.db.Query("SELECT id FROM tasks WHERE state IN (?, ?)",
+		string(TaskStateSubmitted), string(TaskStateWorking))
+	if err != nil {
+		return fmt.Errorf("reading interrupted tasks: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close() //nolint:errcheck
+			return fmt.Errorf("reading interrupted tasks: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close() //nolint:errcheck
+
+	status := TaskStatus{
+		State:   TaskStateFailed,
+		Message: &Message{Role: MessageRoleAgent, Parts: []Part{NewTextPart("task interrupted by a server restart")}},
+	}
+	for _, id := range ids {
+		t, err := s.get(id)
+		if err != nil {
+			return err
+		}
+		t.Status = status
+		if err := s.put(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeTaskColumns returns the status, history, artifacts, and metadata
+// columns for t.
+func encodeTaskColumns(t *Task) ([4]string, error) {
+	var cols [4]string
+	for i, v := range []any{t.Status, t.History, t.Artifacts, t.Metadata} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return cols, err
+		}
+		cols[i] = string(b)
+	}
+	return cols, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask reads a task from a row that selects id, status, history,
+// artifacts, and metadata, followed by any extra columns.
+func scanTask(row rowScanner, extra ...any) (*Task, error) {
+	var t Task
+	var status, history, artifacts, metadata string
+	if err := row.Scan(append([]any{&t.ID, &status, &history, &artifacts, &metadata}, extra...)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("reading task: %w", err)
+	}
+	for _, c := range []struct {
+		raw string
+		dst any
+	}{{status, &t.Status}, {history, &t.History}, {artifacts, &t.Artifacts}, {metadata, &t.Metadata}} {
+		if err := json.Unmarshal([]byte(c.raw), c.dst); err != nil {
+			return nil, fmt.Errorf("decoding task %s: %w", t.ID, err)
+		}
+	}
+	return &t, nil
+}
@@ -0,0 +1,142 @@
+package a2a
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func openTestSQLiteStore(t *testing.T, path string) *SQLiteTaskStore {
+	t.Helper()
+	s, err := OpenSQLiteTaskStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.OnError(func(err error) { t.Errorf("store error: %v", err) })
+	t.Cleanup(func() { s.Close() }) //nolint:errcheck
+	return s
+}
+
+func TestSQLiteTaskStore_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	s := openTestSQLiteStore(t, path)
+
+	task := &Task{
+		ID:       "t1",
+		Status:   TaskStatus{State: TaskStateSubmitted},
+		History:  []Message{{Role: MessageRoleUser, Parts: []Part{NewTextPart("hello")}}},
+		Metadata: map[string]any{"channel": "slack"},
+	}
+	if _, ok := s.Claim(task); !ok {
+		t.Fatal("first claim should succeed")
+	}
+	if _, ok := s.Claim(task); ok {
+		t.Fatal("claim of in-flight task should fail")
+	}
+	if !s.UpdateStatus("t1", TaskStatus{State: TaskStateWorking}) {
+		t.Fatal("UpdateStatus of stored task returned false")
+	}
+	if s.UpdateStatus("missing", TaskStatus{State: TaskStateWorking}) {
+		t.Error("UpdateStatus of missing task returned true")
+	}
+	s.SetArtifacts("t1", []Artifact{{Name: "response", Parts: []Part{NewTextPart("hi there")}}})
+	s.UpdateStatus("t1", TaskStatus{State: TaskStateCompleted})
+
+	// Reopen to read what the first process stored
+	s.Close() //nolint:errcheck
+	s = openTestSQLiteStore(t, path)
+	got := s.Get("t1")
+	if got == nil {
+		t.Fatal("task not found after reopen")
+	}
+	if got.Status.State != TaskStateCompleted || got.History[0].Parts[0].Text != "hello" ||
+		got.Artifacts[0].Parts[0].Text != "hi there" || got.Metadata["channel"] != "slack" {
+		t.Errorf("task after reopen = %+v", got)
+	}
+	if s.Get("missing") != nil {
+		t.Error("Get of missing task returned a task")
+	}
+
+	history, err := s.StatusHistory("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var states []TaskState
+	for _, st := range history {
+		states = append(states, st.State)
+	}
+	want := []TaskState{TaskStateSubmitted, TaskStateWorking, TaskStateWorking, TaskStateCompleted}
+	if fmt.Sprint(states) != fmt.Sprint(want) {
+		t.Errorf("status history = %v, want %v", states, want)
+	}
+}
+
+func TestSQLiteTaskStore_FailsInterruptedTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	s := openTestSQLiteStore(t, path)
+	s.Put(&Task{ID: "t1", Status: TaskStatus{State: TaskStateWorking}})
+	s.Close() //nolint:errcheck
+
+	s = openTestSQLiteStore(t, path)
+	if got := s.Get("t1"); got.Status.State != TaskStateFailed {
+		t.Errorf("state after restart = %q, want failed", got.Status.State)
+	}
+	// A failed task may be submitted again
+	if _, ok := s.Claim(&Task{ID: "t1", Status: TaskStatus{State: TaskStateSubmitted}}); !ok {
+		t.Error("claim of interrupted task should succeed")
+	}
+}
+
+func TestSQLiteTaskStore_List(t *testing.T) {
+	testTaskStoreList(t, openTestSQLiteStore(t, filepath.Join(t.TempDir(), "tasks.db")))
+}
+
+func TestSQLiteTaskStore_OnUpdate(t *testing.T) {
+	s := openTestSQLiteStore(t, filepath.Join(t.TempDir(), "tasks.db"))
+	var updated []string
+	s.OnUpdate(func(task *Task) { updated = append(updated, task.ID+":"+string(task.Status.State)) })
+	s.Put(&Task{ID: "t1", Status: TaskStatus{State: TaskStateSubmitted}})
+	s.UpdateStatus("t1", TaskStatus{State: TaskStateCompleted})
+	s.UpdateStatus("missing", TaskStatus{State: TaskStateCompleted})
+	if fmt.Sprint(updated) != "[t1:submitted t1:completed]" {
+		t.Errorf("updates = %v", updated)
+	}
+}
+
+func TestSQLiteTaskStore_Concurrent(t *testing.T) {
+	s := openTestSQLiteStore(t, filepath.Join(t.TempDir(), "tasks.db"))
+
+	var wg sync.WaitGroup
+	claimed := make(chan string, 40)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("t%d", i%5)
+			if _, ok := s.Claim(&Task{ID: id, Status: TaskStatus{State: TaskStateSubmitted}}); ok {
+				claimed <- id
+			}
+			s.UpdateStatus(id, TaskStatus{State: TaskStateWorking})
+			_ = s.Get(id)
+		}()
+	}
+	wg.Wait()
+	close(claimed)
+
+	// Each ID is claimed exactly once
+	seen := map[string]int{}
+	for id := range claimed {
+		seen[id]++
+	}
+	if len(seen) != 5 {
+		t.Errorf("claimed %v, want 5 distinct tasks", seen)
+	}
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("task %s claimed %d times", id, n)
+		}
+	}
+}
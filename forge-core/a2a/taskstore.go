@@ -3,61 +3,200 @@ package a2a
 import (
 	"encoding/json"
 	"sync"
+	"time"
 )
 
-// TaskStore is a thread-safe in-memory store for A2A tasks.
-type TaskStore struct {
-	mu    sync.RWMutex
-	tasks map[string]*Task
+// TaskStore stores A2A tasks for the server. Implementations must be safe
+// for concurrent use and return copies, so callers may modify the tasks
+// they get back.
+type TaskStore interface {
+	// Get returns the task with the given ID, or nil if not found.
+	Get(id string) *Task
+	// Put stores a task, overwriting any existing task with the same ID.
+	Put(t *Task)
+	// Claim stores t unless a task with the same ID is in flight or
+	// finished, in which case it returns that task and false.
+	Claim(t *Task) (*Task, bool)
+	// UpdateStatus sets an existing task's status. Returns false if the
+	// task does not exist.
+	UpdateStatus(id string, status TaskStatus) bool
+	// SetArtifacts replaces an existing task's artifacts. Returns false if
+	// the task does not exist.
+	SetArtifacts(id string, artifacts []Artifact) bool
+	// List returns a page of tasks, newest first, and the total number of
+	// stored tasks that match opts.
+	List(opts TaskListOptions) ([]TaskEntry, int)
+	// OnUpdate sets a function called with a copy of a task each time it
+	// is stored or changed.
+	OnUpdate(fn func(*Task))
 }
 
-// NewTaskStore creates an empty TaskStore.
-func NewTaskStore() *TaskStore {
-	return &TaskStore{tasks: make(map[string]*Task)}
+// TaskListOptions selects a page of tasks for TaskStore.List.
+type TaskListOptions struct {
+	State  TaskState // only tasks in this state; empty matches all
+	Offset int
+	Limit  int // 0 returns all tasks after Offset
+}
+
+// TaskEntry is a stored task with the times it was first stored and last
+// changed.
+type TaskEntry struct {
+	Task      *Task
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MemoryTaskStore is a thread-safe in-memory TaskStore. Tasks are lost when
+// the process exits.
+type MemoryTaskStore struct {
+	mu       sync.RWMutex
+	tasks    map[string]*memoryTask
+	order    []string // task IDs in the order first stored
+	onUpdate func(*Task)
+}
+
+type memoryTask struct {
+	task      *Task
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+var _ TaskStore = (*MemoryTaskStore)(nil)
+
+// NewMemoryTaskStore creates an empty MemoryTaskStore.
+func NewMemoryTaskStore() *MemoryTaskStore {
+	return &MemoryTaskStore{tasks: make(map[string]*memoryTask)}
+}
+
+// OnUpdate sets a function called with a copy of a task each time it is
+// stored or changed. It runs on the caller's goroutine after the store's
+// lock is released.
+func (s *MemoryTaskStore) OnUpdate(fn func(*Task)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onUpdate = fn
+}
+
+// notify passes a copy of t to the update function, if one is set. The
+// caller must not hold s.mu.
+func (s *MemoryTaskStore) notify(id string) {
+	s.mu.RLock()
+	var t *Task
+	fn, mt := s.onUpdate, s.tasks[id]
+	if fn != nil && mt != nil {
+		t = deepCopyTask(mt.task)
+	}
+	s.mu.RUnlock()
+	if fn != nil && t != nil {
+		fn(t)
+	}
 }
 
 // Get returns a deep copy of the task with the given ID, or nil if not found.
-func (s *TaskStore) Get(id string) *Task {
+func (s *MemoryTaskStore) Get(id string) *Task {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	t, ok := s.tasks[id]
+	mt, ok := s.tasks[id]
 	if !ok {
 		return nil
 	}
-	return deepCopyTask(t)
+	return deepCopyTask(mt.task)
 }
 
 // Put stores a task. It overwrites any existing task with the same ID.
-func (s *TaskStore) Put(t *Task) {
+func (s *MemoryTaskStore) Put(t *Task) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.tasks[t.ID] = deepCopyTask(t)
+	s.store(t)
+	s.mu.Unlock()
+	s.notify(t.ID)
+}
+
+// Claim stores t unless a task with the same ID is already in flight
+// (submitted or working) or finished (completed or canceled). In that case
+// it returns a copy of the existing task and false, so a retried submission
+// can return the earlier result instead of executing again. Failed tasks and
+// tasks awaiting input may be claimed again. Tasks without an ID are always
+// claimed.
+func (s *MemoryTaskStore) Claim(t *Task) (*Task, bool) {
+	s.mu.Lock()
+	if t.ID != "" {
+		if existing, ok := s.tasks[t.ID]; ok {
+			switch existing.task.Status.State {
+			case TaskStateSubmitted, TaskStateWorking, TaskStateCompleted, TaskStateCanceled:
+				s.mu.Unlock()
+				return deepCopyTask(existing.task), false
+			}
+		}
+	}
+	s.store(t)
+	s.mu.Unlock()
+	s.notify(t.ID)
+	return nil, true
+}
+
+// store saves a copy of t. The caller must hold s.mu.
+func (s *MemoryTaskStore) store(t *Task) {
+	now := time.Now().UTC()
+	mt, ok := s.tasks[t.ID]
+	if !ok {
+		mt = &memoryTask{createdAt: now}
+		s.tasks[t.ID] = mt
+		s.order = append(s.order, t.ID)
+	}
+	mt.task = deepCopyTask(t)
+	mt.updatedAt = now
+}
+
+// List returns a page of tasks, newest first, and the number that match.
+func (s *MemoryTaskStore) List(opts TaskListOptions) ([]TaskEntry, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := []TaskEntry{}
+	total := 0
+	for i := len(s.order) - 1; i >= 0; i-- {
+		mt := s.tasks[s.order[i]]
+		if opts.State != "" && mt.task.Status.State != opts.State {
+			continue
+		}
+		total++
+		if total <= opts.Offset || (opts.Limit > 0 && len(entries) == opts.Limit) {
+			continue
+		}
+		entries = append(entries, TaskEntry{Task: deepCopyTask(mt.task), CreatedAt: mt.createdAt, UpdatedAt: mt.updatedAt})
+	}
+	return entries, total
 }
 
 // UpdateStatus updates the status of an existing task. Returns false if the
 // task does not exist.
-func (s *TaskStore) UpdateStatus(id string, status TaskStatus) bool {
+func (s *MemoryTaskStore) UpdateStatus(id string, status TaskStatus) bool {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	t, ok := s.tasks[id]
-	if !ok {
-		return false
+	mt, ok := s.tasks[id]
+	if ok {
+		mt.task.Status = status
+		mt.updatedAt = time.Now().UTC()
 	}
-	t.Status = status
-	return true
+	s.mu.Unlock()
+	if ok {
+		s.notify(id)
+	}
+	return ok
 }
 
 // SetArtifacts replaces the artifacts for an existing task. Returns false if
 // the task does not exist.
-func (s *TaskStore) SetArtifacts(id string, artifacts []Artifact) bool {
+func (s *MemoryTaskStore) SetArtifacts(id string, artifacts []Artifact) bool {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	t, ok := s.tasks[id]
-	if !ok {
-		return false
+	mt, ok := s.tasks[id]
+	if ok {
+		mt.task.Artifacts = artifacts
+		mt.updatedAt = time.Now().UTC()
+	}
+	s.mu.Unlock()
+	if ok {
+		s.notify(id)
 	}
-	t.Artifacts = artifacts
-	return true
+	return ok
 }
 
 // deepCopyTask creates a deep copy by JSON round-tripping.
@@ -0,0 +1,122 @@
+package a2a
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTaskStore_Claim(t *testing.T) {
+	s := NewMemoryTaskStore()
+
+	task := &Task{ID: "t1", Status: TaskStatus{State: TaskStateSubmitted}}
+	if _, ok := s.Claim(task); !ok {
+		t.Fatal("first claim should succeed")
+	}
+
+	// In flight: a second submission gets the existing task
+	existing, ok := s.Claim(&Task{ID: "t1", Status: TaskStatus{State: TaskStateSubmitted}})
+	if ok {
+		t.Fatal("claim of in-flight task should fail")
+	}
+	if existing.ID != "t1" || existing.Status.State != TaskStateSubmitted {
+		t.Errorf("unexpected existing task: %+v", existing)
+	}
+
+	// Completed: returns the stored result
+	task.Status = TaskStatus{State: TaskStateCompleted}
+	task.Artifacts = []Artifact{{Name: "response", Parts: []Part{NewTextPart("done")}}}
+	s.Put(task)
+	existing, ok = s.Claim(&Task{ID: "t1", Status: TaskStatus{State: TaskStateSubmitted}})
+	if ok {
+		t.Fatal("claim of completed task should fail")
+	}
+	if existing.Status.State != TaskStateCompleted || existing.Artifacts[0].Parts[0].Text != "done" {
+		t.Errorf("expected completed result, got %+v", existing)
+	}
+
+	// Failed: may be retried
+	task.Status = TaskStatus{State: TaskStateFailed}
+	s.Put(task)
+	if _, ok := s.Claim(&Task{ID: "t1", Status: TaskStatus{State: TaskStateSubmitted}}); !ok {
+		t.Error("claim of failed task should succeed")
+	}
+	if got := s.Get("t1"); got.Status.State != TaskStateSubmitted {
+		t.Errorf("state after reclaim = %q, want submitted", got.Status.State)
+	}
+}
+
+func TestTaskStore_OnUpdate(t *testing.T) {
+	s := NewMemoryTaskStore()
+	var states []TaskState
+	s.OnUpdate(func(task *Task) {
+		// The store is unlocked, so the callback may read it
+		if s.Get(task.ID) == nil {
+			t.Error("task not readable from the update callback")
+		}
+		task.Status.State = "mutated"
+		states = append(states, s.Get(task.ID).Status.State)
+	})
+
+	s.Claim(&Task{ID: "t1", Status: TaskStatus{State: TaskStateSubmitted}})
+	s.UpdateStatus("t1", TaskStatus{State: TaskStateWorking})
+	s.Put(&Task{ID: "t1", Status: TaskStatus{State: TaskStateCompleted}})
+	s.Claim(&Task{ID: "t1"}) // rejected, no update
+	s.UpdateStatus("missing", TaskStatus{State: TaskStateFailed})
+
+	want := []TaskState{TaskStateSubmitted, TaskStateWorking, TaskStateCompleted}
+	if len(states) != len(want) {
+		t.Fatalf("updates = %v, want %v", states, want)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Errorf("updates = %v, want %v (callback must get a copy)", states, want)
+		}
+	}
+}
+
+func TestMemoryTaskStore_List(t *testing.T) {
+	testTaskStoreList(t, NewMemoryTaskStore())
+}
+
+// testTaskStoreList checks List paging, filtering, and timestamps on an
+// empty store.
+func testTaskStoreList(t *testing.T, s TaskStore) {
+	t.Helper()
+	before := time.Now().Add(-time.Second)
+	for _, id := range []string{"t1", "t2", "t3", "t4"} {
+		s.Put(&Task{ID: id, Status: TaskStatus{State: TaskStateCompleted}})
+	}
+	s.UpdateStatus("t1", TaskStatus{State: TaskStateFailed}) // keeps its place
+	s.Put(&Task{ID: "t3", Status: TaskStatus{State: TaskStateWorking}})
+
+	ids := func(entries []TaskEntry) string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Task.ID+":"+string(e.Task.Status.State))
+		}
+		return strings.Join(out, " ")
+	}
+
+	entries, total := s.List(TaskListOptions{})
+	if total != 4 || ids(entries) != "t4:completed t3:working t2:completed t1:failed" {
+		t.Errorf("List() = %s, total %d", ids(entries), total)
+	}
+	entries, total = s.List(TaskListOptions{Offset: 1, Limit: 2})
+	if total != 4 || ids(entries) != "t3:working t2:completed" {
+		t.Errorf("List(offset 1, limit 2) = %s, total %d", ids(entries), total)
+	}
+	entries, total = s.List(TaskListOptions{State: TaskStateCompleted, Offset: 1})
+	if total != 2 || ids(entries) != "t2:completed" {
+		t.Errorf("List(completed, offset 1) = %s, total %d", ids(entries), total)
+	}
+
+	entries, _ = s.List(TaskListOptions{State: TaskStateFailed})
+	if len(entries) != 1 {
+		t.Fatalf("List(failed) = %s", ids(entries))
+	}
+	e := entries[0]
+	if e.CreatedAt.Before(before) || e.UpdatedAt.Before(e.CreatedAt) || time.Since(e.UpdatedAt) > time.Minute {
+		t.Errorf("timestamps created %v, updated %v", e.CreatedAt, e.UpdatedAt)
+	}
+}
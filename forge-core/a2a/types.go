@@ -24,6 +24,7 @@ type TaskStatus struct {
 // Task represents an A2A task exchanged between agents.
 type Task struct {
 	ID        string         `json:"id"`
+	SessionID string         `json:"sessionId,omitempty"` // issued by the server; send it with later tasks to share history
 	Status    TaskStatus     `json:"status"`
 	History   []Message      `json:"history,omitempty"`
 	Artifacts []Artifact     `json:"artifacts,omitempty"`
@@ -38,10 +39,44 @@ const (
 	MessageRoleAgent MessageRole = "agent"
 )
 
+// MetadataEphemeral is the Message metadata key that asks channel adapters
+// to deliver a reply ephemerally (visible only to the requester, or deleted
+// after a delay), when the platform supports it.
+const MetadataEphemeral = "ephemeral"
+
+// MetadataInputRequired is the Message metadata key an executor sets on a
+// reply that asks the user for information. The task moves to input-required
+// and a follow-up message with the same task ID continues it.
+const MetadataInputRequired = "input_required"
+
+// MetadataPartial is the Message metadata key marking a streamed piece of a
+// reply still being generated. The complete reply follows as a message
+// without it.
+const MetadataPartial = "partial"
+
+// IsPartial reports whether msg is a streamed piece of a reply.
+func IsPartial(msg *Message) bool {
+	if msg == nil {
+		return false
+	}
+	v, _ := msg.Metadata[MetadataPartial].(bool)
+	return v
+}
+
+// IsInputRequired reports whether msg asks the user for input.
+func IsInputRequired(msg *Message) bool {
+	if msg == nil {
+		return false
+	}
+	v, _ := msg.Metadata[MetadataInputRequired].(bool)
+	return v
+}
+
 // Message is a single conversational turn in the A2A protocol.
 type Message struct {
-	Role  MessageRole `json:"role"`
-	Parts []Part      `json:"parts"`
+	Role     MessageRole    `json:"role"`
+	Parts    []Part         `json:"parts"`
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 // PartKind discriminates the content type of a Part.
@@ -62,7 +97,8 @@ type Part struct {
 	File *FileContent `json:"file,omitempty"`
 }
 
-// FileContent holds the contents or reference for a file part.
+// FileContent holds the contents or reference for a file part. Exactly one
+// of URI or Bytes should be set.
 type FileContent struct {
 	Name     string `json:"name,omitempty"`
 	MimeType string `json:"mimeType,omitempty"`
@@ -106,12 +142,13 @@ func NewTextPart(text string) Part {
 	return Part{Kind: PartKindText, Text: text}
 }
 
-// NewDataPart creates a Part containing structured data.
+// NewDataPart creates a Part containing structured data, sent as JSON.
 func NewDataPart(data any) Part {
 	return Part{Kind: PartKindData, Data: data}
 }
 
-// NewFilePart creates a Part referencing a file.
-func NewFilePart(file FileContent) Part {
-	return Part{Kind: PartKindFile, File: &file}
+// NewFilePart creates a Part carrying a file's contents inline. The bytes
+// are base64-encoded in JSON.
+func NewFilePart(name, mimeType string, data []byte) Part {
+	return Part{Kind: PartKindFile, File: &FileContent{Name: name, MimeType: mimeType, Bytes: data}}
 }
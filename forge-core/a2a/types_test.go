@@ -0,0 +1,63 @@
+package a2a
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPart_JSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		part Part
+		json string // expected encoding
+	}{
+		{"text", NewTextPart("hello"), `{"kind":"text","text":"hello"}`},
+		{"data", NewDataPart(map[string]any{"total": 42.0, "ok": true}), `{"kind":"data","data":{"ok":true,"total":42}}`},
+		{"file", NewFilePart("report.csv", "text/csv", []byte("a,b\n")),
+			`{"kind":"file","file":{"name":"report.csv","mimeType":"text/csv","bytes":"YSxiCg=="}}`},
+		{"file uri", Part{Kind: PartKindFile, File: &FileContent{Name: "logo.png", URI: "https://example.com/logo.png"}},
+			`{"kind":"file","file":{"name":"logo.png","uri":"https://example.com/logo.png"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.part)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.json {
+				t.Errorf("encoded = %s, want %s", b, tt.json)
+			}
+			var got Part
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.part) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.part)
+			}
+		})
+	}
+}
+
+func TestMessage_MixedPartsRoundTrip(t *testing.T) {
+	msg := Message{Role: MessageRoleAgent, Parts: []Part{
+		NewTextPart("chart attached"),
+		NewFilePart("chart.png", "image/png", []byte{0x89, 'P', 'N', 'G'}),
+		NewDataPart([]any{"a", "b"}),
+	}}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"bytes":"iVBORw=="`) {
+		t.Errorf("file bytes not base64-encoded: %s", b)
+	}
+	var got Message
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, msg) {
+		t.Errorf("round trip = %+v, want %+v", got, msg)
+	}
+}
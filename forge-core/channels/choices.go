@@ -0,0 +1,62 @@
+package channels
+
+import (
+	"encoding/json"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// ChoicesDataType is the "type" of a data part that offers the user a set of
+// choices, which channels can render as buttons:
+//
+//	{"type": "choices", "choices": [{"label": "Yes", "value": "yes"}]}
+const ChoicesDataType = "choices"
+
+// Choice is one option offered to the user. Picking it sends Value back to
+// the agent as the user's message; an empty Value sends the Label.
+type Choice struct {
+	Label string `json:"label"`
+	Value string `json:"value,omitempty"`
+}
+
+// Reply returns the text sent to the agent when the choice is picked.
+func (c Choice) Reply() string {
+	if c.Value != "" {
+		return c.Value
+	}
+	return c.Label
+}
+
+type choicesData struct {
+	Type    string   `json:"type"`
+	Choices []Choice `json:"choices"`
+}
+
+// NewChoicesPart returns a data part offering the given choices.
+func NewChoicesPart(choices ...Choice) a2a.Part {
+	return a2a.NewDataPart(choicesData{Type: ChoicesDataType, Choices: choices})
+}
+
+// PartChoices returns the choices a data part offers, or false when p is not
+// a choices part or offers none.
+func PartChoices(p a2a.Part) ([]Choice, bool) {
+	if p.Kind != a2a.PartKindData || p.Data == nil {
+		return nil, false
+	}
+	// Data is a map once it has crossed the wire, so decode via JSON
+	b, err := json.Marshal(p.Data)
+	if err != nil {
+		return nil, false
+	}
+	var d choicesData
+	if err := json.Unmarshal(b, &d); err != nil || d.Type != ChoicesDataType {
+		return nil, false
+	}
+	var choices []Choice
+	for _, c := range d.Choices {
+		if c.Label != "" {
+			choices = append(choices, c)
+		}
+	}
+	return choices, len(choices) > 0
+}
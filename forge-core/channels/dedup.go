@@ -0,0 +1,77 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// ErrDuplicateEvent is returned by a Dedup handler for an event it has
+// already passed on.
+var ErrDuplicateEvent = errors.New("duplicate event")
+
+// Deduplicator remembers event identities for a TTL, so a platform that
+// delivers at least once (Telegram polling, Slack retries) does not get the
+// same message answered twice.
+type Deduplicator struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewDeduplicator returns a Deduplicator that remembers identities for ttl.
+func NewDeduplicator(ttl time.Duration) *Deduplicator {
+	return &Deduplicator{ttl: ttl, now: time.Now, seen: make(map[string]time.Time)}
+}
+
+// Seen records key and reports whether it was already recorded within the
+// TTL. An empty key is never considered seen.
+func (d *Deduplicator) Seen(key string) bool {
+	if key == "" {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	if now.Sub(d.lastSweep) > d.ttl {
+		for k, t := range d.seen {
+			if now.Sub(t) > d.ttl {
+				delete(d.seen, k)
+			}
+		}
+		d.lastSweep = now
+	}
+	if t, ok := d.seen[key]; ok && now.Sub(t) <= d.ttl {
+		return true
+	}
+	d.seen[key] = now
+	return false
+}
+
+// eventKey identifies an event's delivery across plugins, or "" when the
+// plugin did not set a MessageID.
+func eventKey(event *ChannelEvent) string {
+	if event.MessageID == "" {
+		return ""
+	}
+	return event.Channel + "\x00" + event.WorkspaceID + "\x00" + event.MessageID
+}
+
+// Dedup returns an EventHandler that passes each event to next once.
+// Repeated deliveries of an event, matched by channel, workspace, and
+// MessageID, are dropped with ErrDuplicateEvent. Events without a MessageID
+// always pass.
+func Dedup(d *Deduplicator, next EventHandler) EventHandler {
+	return func(ctx context.Context, event *ChannelEvent) (*a2a.Message, error) {
+		if d.Seen(eventKey(event)) {
+			return nil, ErrDuplicateEvent
+		}
+		return next(ctx, event)
+	}
+}
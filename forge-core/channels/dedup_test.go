@@ -0,0 +1,73 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+func TestDeduplicator_Seen(t *testing.T) {
+	now := time.Unix(1000, 0)
+	d := NewDeduplicator(time.Minute)
+	d.now = func() time.Time { return now }
+
+	if d.Seen("a") {
+		t.Fatal("first delivery reported as seen")
+	}
+	if !d.Seen("a") {
+		t.Fatal("second delivery within the TTL not reported as seen")
+	}
+	if d.Seen("b") {
+		t.Fatal("different key reported as seen")
+	}
+	if d.Seen("") || d.Seen("") {
+		t.Fatal("empty key reported as seen")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if d.Seen("a") {
+		t.Fatal("delivery after the TTL reported as seen")
+	}
+	if len(d.seen) != 1 {
+		t.Errorf("expired entries kept: %d entries, want 1", len(d.seen))
+	}
+}
+
+func TestDedup_DropsDuplicateDelivery(t *testing.T) {
+	calls := 0
+	next := func(_ context.Context, _ *ChannelEvent) (*a2a.Message, error) {
+		calls++
+		return &a2a.Message{Role: a2a.MessageRoleAgent}, nil
+	}
+	handler := Dedup(NewDeduplicator(time.Minute), next)
+	ctx := context.Background()
+
+	event := func(channel, id string) *ChannelEvent {
+		return &ChannelEvent{Channel: channel, WorkspaceID: "W1", MessageID: id, Message: "hello"}
+	}
+
+	if _, err := handler(ctx, event("telegram", "100")); err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+	// The platform redelivers the same update
+	if _, err := handler(ctx, event("telegram", "100")); !errors.Is(err, ErrDuplicateEvent) {
+		t.Errorf("redelivery err = %v, want ErrDuplicateEvent", err)
+	}
+	// The same ID on another channel is a different message
+	if _, err := handler(ctx, event("slack", "100")); err != nil {
+		t.Errorf("other channel: %v", err)
+	}
+	// Events without an identity are never dropped
+	for range 2 {
+		if _, err := handler(ctx, event("telegram", "")); err != nil {
+			t.Errorf("event without MessageID: %v", err)
+		}
+	}
+
+	if calls != 4 {
+		t.Errorf("handler called %d times, want 4", calls)
+	}
+}
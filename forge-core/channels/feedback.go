@@ -0,0 +1,90 @@
+package channels
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// FeedbackEvent is a user's reaction to an agent reply, normalized into a
+// rating and linked to the A2A task that produced the reply.
+type FeedbackEvent struct {
+	Channel     string `json:"channel"`
+	WorkspaceID string `json:"workspace_id"`
+	UserID      string `json:"user_id"`
+	MessageID   string `json:"message_id"`        // platform ID of the reply reacted to
+	TaskID      string `json:"task_id,omitempty"` // empty when the reply is not known
+	Reaction    string `json:"reaction"`
+	Rating      int    `json:"rating"` // 1 thumbs up, -1 thumbs down, 0 any other reaction
+}
+
+// FeedbackHandler receives feedback events from channel plugins.
+type FeedbackHandler func(ctx context.Context, fb *FeedbackEvent) error
+
+// FeedbackPlugin is implemented by channel plugins that report reactions to
+// agent replies. SetFeedbackHandler is called before Start.
+type FeedbackPlugin interface {
+	SetFeedbackHandler(h FeedbackHandler)
+}
+
+// ReactionRating maps a reaction to a rating: 1 for thumbs up, -1 for thumbs
+// down, and 0 otherwise. It accepts emoji and Slack-style names, including
+// skin-tone variants such as "+1::skin-tone-2".
+func ReactionRating(reaction string) int {
+	name, _, _ := strings.Cut(strings.Trim(reaction, ":"), "::")
+	name = strings.TrimRight(name, "\U0001F3FB\U0001F3FC\U0001F3FD\U0001F3FE\U0001F3FF")
+	switch name {
+	case "+1", "thumbsup", "\U0001F44D":
+		return 1
+	case "-1", "thumbsdown", "\U0001F44E":
+		return -1
+	}
+	return 0
+}
+
+// defaultReplyIndexSize bounds a ReplyIndex created with a non-positive size.
+const defaultReplyIndexSize = 10_000
+
+// ReplyIndex remembers which task produced each reply a plugin sent, so
+// reactions can be linked back to the task. The oldest entries are evicted
+// once it is full. It is safe for concurrent use.
+type ReplyIndex struct {
+	mu    sync.Mutex
+	max   int
+	tasks map[string]string
+	order []string
+}
+
+// NewReplyIndex creates a ReplyIndex holding up to max replies.
+func NewReplyIndex(max int) *ReplyIndex {
+	if max <= 0 {
+		max = defaultReplyIndexSize
+	}
+	return &ReplyIndex{max: max, tasks: make(map[string]string)}
+}
+
+// Add records that the reply messageID in workspaceID was produced by taskID.
+// Empty IDs are ignored.
+func (x *ReplyIndex) Add(workspaceID, messageID, taskID string) {
+	if messageID == "" || taskID == "" {
+		return
+	}
+	key := workspaceID + "/" + messageID
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if _, ok := x.tasks[key]; !ok {
+		x.order = append(x.order, key)
+	}
+	x.tasks[key] = taskID
+	for len(x.order) > x.max {
+		delete(x.tasks, x.order[0])
+		x.order = x.order[1:]
+	}
+}
+
+// TaskID returns the task that produced the reply, or "" if it is unknown.
+func (x *ReplyIndex) TaskID(workspaceID, messageID string) string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.tasks[workspaceID+"/"+messageID]
+}
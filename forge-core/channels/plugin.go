@@ -5,6 +5,8 @@ package channels
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/initializ/forge/forge-core/a2a"
 )
@@ -37,6 +39,14 @@ type ChannelConfig struct {
 	WebhookPort int               `yaml:"webhook_port,omitempty"`
 	WebhookPath string            `yaml:"webhook_path,omitempty"`
 	Settings    map[string]string `yaml:"settings,omitempty"`
+
+	// AllowedUsers and AllowedWorkspaces restrict who the agent answers, by
+	// ChannelEvent UserID and WorkspaceID. An empty list allows everyone.
+	AllowedUsers      []string `yaml:"allowed_users,omitempty"`
+	AllowedWorkspaces []string `yaml:"allowed_workspaces,omitempty"`
+	// RefusalMessage is sent to users who are not allowed. When empty their
+	// messages are dropped without a reply.
+	RefusalMessage string `yaml:"refusal_message,omitempty"`
 }
 
 // ChannelEvent is the normalized representation of an inbound message
@@ -46,11 +56,102 @@ type ChannelEvent struct {
 	WorkspaceID string          `json:"workspace_id"`
 	UserID      string          `json:"user_id"`
 	ThreadID    string          `json:"thread_id,omitempty"`
+	MessageID   string          `json:"message_id,omitempty"` // platform's delivery identity, used to drop redeliveries
 	Message     string          `json:"message"`
 	Attachments []Attachment    `json:"attachments,omitempty"`
+	Context     *MessageContext `json:"context,omitempty"`
+	TaskID      string          `json:"task_id,omitempty"` // A2A task the router created; set once the handler runs
 	Raw         json.RawMessage `json:"raw,omitempty"`
 }
 
+// MessageContext carries conversational context that arrives alongside a
+// message, such as the message it replies to or where it was forwarded from.
+type MessageContext struct {
+	Caption       string `json:"caption,omitempty"`
+	ReplyToText   string `json:"reply_to_text,omitempty"`
+	QuotedText    string `json:"quoted_text,omitempty"`
+	ForwardedFrom string `json:"forwarded_from,omitempty"`
+}
+
+// PromptText returns the message with its context rendered as leading
+// annotations, for agents that only consume plain text.
+func (e *ChannelEvent) PromptText() string {
+	c := e.Context
+	if c == nil {
+		return e.Message
+	}
+	var b strings.Builder
+	if c.ForwardedFrom != "" {
+		fmt.Fprintf(&b, "[Forwarded from %s]\n", c.ForwardedFrom)
+	}
+	if c.ReplyToText != "" {
+		fmt.Fprintf(&b, "[Replying to: %q]\n", c.ReplyToText)
+	}
+	if c.QuotedText != "" {
+		fmt.Fprintf(&b, "[Quoting: %q]\n", c.QuotedText)
+	}
+	if c.Caption != "" && c.Caption != e.Message {
+		fmt.Fprintf(&b, "[Caption: %s]\n", c.Caption)
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// IsEphemeral reports whether msg is marked for ephemeral delivery via the
+// a2a.MetadataEphemeral metadata key.
+func IsEphemeral(msg *a2a.Message) bool {
+	if msg == nil {
+		return false
+	}
+	v, _ := msg.Metadata[a2a.MetadataEphemeral].(bool)
+	return v
+}
+
+// maxPartNoteData caps the JSON shown for a data part by PartNote.
+const maxPartNoteData = 200
+
+// PartNote returns a one-line mention of a file or data part, for channels
+// that only send text, or "" for text parts. Files are named with their
+// type and link; choices are listed by label; other data is shown as JSON,
+// truncated when long.
+func PartNote(p a2a.Part) string {
+	switch p.Kind {
+	case a2a.PartKindFile:
+		if p.File == nil {
+			return ""
+		}
+		name := p.File.Name
+		if name == "" {
+			name = "file"
+		}
+		if p.File.MimeType != "" {
+			name += " (" + p.File.MimeType + ")"
+		}
+		if p.File.URI != "" {
+			return fmt.Sprintf("[Attachment: %s: %s]", name, p.File.URI)
+		}
+		return fmt.Sprintf("[Attachment: %s]", name)
+	case a2a.PartKindData:
+		if choices, ok := PartChoices(p); ok {
+			labels := make([]string, len(choices))
+			for i, c := range choices {
+				labels[i] = c.Label
+			}
+			return "[Options: " + strings.Join(labels, " | ") + "]"
+		}
+		b, err := json.Marshal(p.Data)
+		if err != nil {
+			return "[Data attached]"
+		}
+		data := string(b)
+		if len(data) > maxPartNoteData {
+			data = strings.ToValidUTF8(data[:maxPartNoteData], "") + "..."
+		}
+		return "[Data: " + data + "]"
+	}
+	return ""
+}
+
 // Attachment represents a file or media item attached to a channel message.
 type Attachment struct {
 	Name     string `json:"name,omitempty"`
@@ -2,9 +2,11 @@ package compiler
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/initializ/forge/forge-core/agentspec"
 	"github.com/initializ/forge/forge-core/pipeline"
+	"github.com/initializ/forge/forge-core/security"
 )
 
 // TemplateSpecData holds data used by Dockerfile and K8s templates.
@@ -45,7 +47,52 @@ type TemplateRuntimeData struct {
 
 // NetworkPolicyData holds network policy template data.
 type NetworkPolicyData struct {
-	DenyAll bool
+	DenyAll  bool
+	AllowAll bool   // dev-open
+	Profile  string // egress profile and mode, set when forge.yaml has an egress section
+	Mode     string
+	Domains  []string   // allowlist domains
+	FQDNs    []FQDNRule // toFQDNs selectors for Domains
+	Cilium   bool       // render a CiliumNetworkPolicy instead of a standard one
+}
+
+// FQDNRule is one Cilium toFQDNs selector: matchPattern for wildcard
+// domains such as *.slack.com, matchName otherwise.
+type FQDNRule struct {
+	Match string
+	Value string
+}
+
+// CNICilium selects a CiliumNetworkPolicy for allowlist egress.
+const CNICilium = "cilium"
+
+// NewNetworkPolicyData returns the network policy for a resolved egress
+// config. deny-all blocks all egress and dev-open allows it. allowlist
+// allows DNS plus HTTP(S): with cni "cilium" only to the allowed domains,
+// otherwise to any address, since a standard NetworkPolicy cannot match DNS
+// names.
+func NewNetworkPolicyData(cfg *security.EgressConfig, cni string) *NetworkPolicyData {
+	if cfg == nil {
+		return &NetworkPolicyData{DenyAll: true}
+	}
+	np := &NetworkPolicyData{Profile: string(cfg.Profile), Mode: string(cfg.Mode)}
+	switch cfg.Mode {
+	case security.ModeDevOpen:
+		np.AllowAll = true
+	case security.ModeAllowlist:
+		np.Domains = cfg.AllDomains
+		np.Cilium = cni == CNICilium
+		for _, d := range cfg.AllDomains {
+			rule := FQDNRule{Match: "matchName", Value: d}
+			if strings.Contains(d, "*") {
+				rule.Match = "matchPattern"
+			}
+			np.FQDNs = append(np.FQDNs, rule)
+		}
+	default:
+		np.DenyAll = true
+	}
+	return np
 }
 
 // BuildTemplateDataFromSpec creates template data from an AgentSpec.
@@ -105,6 +152,13 @@ func BuildTemplateDataFromContext(spec *agentspec.AgentSpec, bc *pipeline.BuildC
 	d.EgressProfile = spec.EgressProfile
 	d.EgressMode = spec.EgressMode
 	d.ToolInterfaceVersion = spec.ToolInterfaceVersion
+	if egressCfg, ok := bc.EgressResolved.(*security.EgressConfig); ok {
+		var cni string
+		if bc.Config != nil {
+			cni = bc.Config.Egress.CNI
+		}
+		d.NetworkPolicy = NewNetworkPolicyData(egressCfg, cni)
+	}
 
 	// Populate skill requirements from build context
 	if spec.Requirements != nil {
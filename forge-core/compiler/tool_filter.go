@@ -20,6 +20,7 @@ var knownBuiltinTools = map[string]bool{
 	"code_interpreter": true,
 	"text_generation":  true,
 	"cli_execute":      true,
+	"memory":           true,
 }
 
 // Known adapter tools.
@@ -0,0 +1,73 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/tools/builtins"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// scaffoldedCommands lists the entrypoint commands forge init generates per
+// framework. Images inferred for other commands are guesses worth surfacing.
+var scaffoldedCommands = map[string][]string{
+	"crewai":    {"python"},
+	"langchain": {"python"},
+	"custom":    {"python", "bun", "go"},
+}
+
+// CompileWarnings returns notes about values compilation inferred or could
+// not resolve for cfg, such as a guessed runtime image. Unlike validation
+// warnings they never indicate an invalid config.
+func CompileWarnings(cfg *types.ForgeConfig, spec *agentspec.AgentSpec) []string {
+	var warnings []string
+
+	fields := strings.Fields(cfg.Entrypoint)
+	image := InferBaseImage(fields)
+	if spec.Runtime != nil {
+		image = spec.Runtime.Image
+	}
+	switch {
+	case len(fields) == 0:
+		warnings = append(warnings, fmt.Sprintf("entrypoint is empty; runtime image defaults to %s", image))
+	case image == InferBaseImage(nil):
+		warnings = append(warnings, fmt.Sprintf("could not infer a runtime image from entrypoint %q; defaulting to %s", cfg.Entrypoint, image))
+	case !isScaffoldedCommand(cfg.Framework, fields[0]):
+		warnings = append(warnings, fmt.Sprintf("runtime image %s was inferred from entrypoint %q, which is not a standard %s entrypoint; check the image matches the agent's runtime",
+			image, cfg.Entrypoint, frameworkLabel(cfg.Framework)))
+	}
+
+	// Custom agents only get builtin tools, cli_execute, memory, and tools/
+	// scripts
+	if cfg.Framework == "custom" || cfg.Framework == "" {
+		for _, t := range cfg.Tools {
+			if t.Name != "cli_execute" && t.Name != "memory" && builtins.GetByName(t.Name) == nil {
+				warnings = append(warnings, fmt.Sprintf("tool %q does not match a builtin tool; it must be provided by a script in tools/", t.Name))
+			}
+		}
+	}
+
+	return warnings
+}
+
+func isScaffoldedCommand(framework, command string) bool {
+	commands, ok := scaffoldedCommands[framework]
+	if !ok {
+		commands = scaffoldedCommands["custom"]
+	}
+	for _, c := range commands {
+		// python3, python3.12, etc. all count as python
+		if command == c || (c == "python" && strings.HasPrefix(command, c)) {
+			return true
+		}
+	}
+	return false
+}
+
+func frameworkLabel(framework string) string {
+	if framework == "" {
+		return "custom"
+	}
+	return framework
+}
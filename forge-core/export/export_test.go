@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 func TestValidateForExport_DevTool(t *testing.T) {
@@ -192,3 +193,132 @@ func TestValidateProdConfig_Clean(t *testing.T) {
 		t.Errorf("expected no errors, got: %v", v.Errors)
 	}
 }
+
+func TestBuildToolSchemaBundle(t *testing.T) {
+	spec := &agentspec.AgentSpec{
+		AgentID: "bundle-agent",
+		Version: "0.2.0",
+		Tools: []agentspec.ToolSpec{
+			{Name: "web_search", InputSchema: json.RawMessage(`{"type":"object","properties":{"query":{"type":"string"}},"required":["query"]}`)},
+			{Name: "math/calc", InputSchema: json.RawMessage(`{"type":"object","properties":{"expression":{"type":"string"}}}`)},
+			{Name: "prompt_only"},
+		},
+	}
+
+	bundle, err := BuildToolSchemaBundle(spec)
+	if err != nil {
+		t.Fatalf("BuildToolSchemaBundle() error: %v", err)
+	}
+
+	if bundle["$id"] != "urn:forge:agent:bundle-agent:0.2.0:tool-inputs" {
+		t.Errorf("$id = %v", bundle["$id"])
+	}
+	defs, ok := bundle["$defs"].(map[string]any)
+	if !ok {
+		t.Fatalf("$defs missing or wrong type: %T", bundle["$defs"])
+	}
+	for _, tool := range spec.Tools {
+		def, ok := defs[tool.Name]
+		if !ok {
+			t.Errorf("$defs missing tool %q", tool.Name)
+			continue
+		}
+		// Each definition must be a valid schema on its own
+		if _, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(def)); err != nil {
+			t.Errorf("definition %q is not a valid schema: %v", tool.Name, err)
+		}
+	}
+
+	// The whole bundle compiles and $ref wiring resolves
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(bundle))
+	if err != nil {
+		t.Fatalf("bundle is not a valid schema: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		doc   string
+		valid bool
+	}{
+		{"valid search", `{"tool":"web_search","input":{"query":"go"}}`, true},
+		{"escaped name", `{"tool":"math/calc","input":{"expression":"1+1"}}`, true},
+		{"prompt only", `{"tool":"prompt_only","input":{}}`, true},
+		{"missing required", `{"tool":"web_search","input":{}}`, false},
+		{"wrong type", `{"tool":"web_search","input":{"query":5}}`, false},
+		{"unknown tool", `{"tool":"nope","input":{}}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := schema.Validate(gojsonschema.NewStringLoader(tt.doc))
+			if err != nil {
+				t.Fatalf("Validate() error: %v", err)
+			}
+			if res.Valid() != tt.valid {
+				t.Errorf("valid = %v, want %v (%v)", res.Valid(), tt.valid, res.Errors())
+			}
+		})
+	}
+}
+
+func TestBuildToolSchemaBundle_NestedRefs(t *testing.T) {
+	spec := &agentspec.AgentSpec{
+		AgentID: "bundle-agent",
+		Version: "0.2.0",
+		Tools: []agentspec.ToolSpec{{
+			Name: "create/order",
+			InputSchema: json.RawMessage(`{
+				"type": "object",
+				"$defs": {
+					"address": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]},
+					"item": {"type": "object", "properties": {"sku": {"type": "string"}, "ship_to": {"$ref": "#/$defs/address"}}, "required": ["sku"]}
+				},
+				"properties": {
+					"items": {"type": "array", "items": {"$ref": "#/$defs/item"}},
+					"billing": {"$ref": "#/$defs/address"}
+				},
+				"required": ["items"]
+			}`),
+		}},
+	}
+
+	bundle, err := BuildToolSchemaBundle(spec)
+	if err != nil {
+		t.Fatalf("BuildToolSchemaBundle() error: %v", err)
+	}
+	def := bundle["$defs"].(map[string]any)["create/order"].(map[string]any)
+	items := def["properties"].(map[string]any)["items"].(map[string]any)["items"].(map[string]any)
+	if items["$ref"] != "#/$defs/create~1order/$defs/item" {
+		t.Errorf("items $ref = %v, want it rebased into the tool's definition", items["$ref"])
+	}
+	shipTo := def["$defs"].(map[string]any)["item"].(map[string]any)["properties"].(map[string]any)["ship_to"].(map[string]any)
+	if shipTo["$ref"] != "#/$defs/create~1order/$defs/address" {
+		t.Errorf("ship_to $ref = %v", shipTo["$ref"])
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(bundle))
+	if err != nil {
+		t.Fatalf("bundle is not a valid schema: %v", err)
+	}
+	for doc, valid := range map[string]bool{
+		`{"tool":"create/order","input":{"items":[{"sku":"a","ship_to":{"city":"Pune"}}],"billing":{"city":"Pune"}}}`: true,
+		`{"tool":"create/order","input":{"items":[{"sku":"a","ship_to":{}}]}}`:                                        false,
+		`{"tool":"create/order","input":{"items":[{"ship_to":{"city":"Pune"}}]}}`:                                     false,
+	} {
+		result, err := schema.Validate(gojsonschema.NewStringLoader(doc))
+		if err != nil {
+			t.Fatalf("Validate(%s): %v", doc, err)
+		}
+		if result.Valid() != valid {
+			t.Errorf("Validate(%s) = %v, want %v: %v", doc, result.Valid(), valid, result.Errors())
+		}
+	}
+}
+
+func TestBuildToolSchemaBundle_InvalidSchema(t *testing.T) {
+	spec := &agentspec.AgentSpec{
+		Tools: []agentspec.ToolSpec{{Name: "bad", InputSchema: json.RawMessage(`"string"`)}},
+	}
+	if _, err := BuildToolSchemaBundle(spec); err == nil {
+		t.Error("expected error for non-object input schema")
+	}
+}
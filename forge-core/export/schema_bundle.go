@@ -0,0 +1,95 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+)
+
+// schemaBundleDialect is the JSON Schema dialect of the consolidated tool schema.
+const schemaBundleDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// BuildToolSchemaBundle consolidates every tool's input schema into a single
+// JSON Schema document. Each tool is a definition under $defs, and the root
+// schema validates a {"tool": name, "input": {...}} invocation via oneOf.
+// Tools without an input schema get a permissive object schema. References
+// within a tool's schema ("#/...") are rewritten to point into its
+// definition.
+func BuildToolSchemaBundle(spec *agentspec.AgentSpec) (map[string]any, error) {
+	defs := make(map[string]any, len(spec.Tools))
+	variants := make([]any, 0, len(spec.Tools))
+
+	for _, tool := range spec.Tools {
+		if _, dup := defs[tool.Name]; dup {
+			return nil, fmt.Errorf("duplicate tool name %q", tool.Name)
+		}
+
+		var schema any = map[string]any{"type": "object"}
+		if len(tool.InputSchema) > 0 {
+			if err := json.Unmarshal(tool.InputSchema, &schema); err != nil {
+				return nil, fmt.Errorf("parsing input schema for tool %s: %w", tool.Name, err)
+			}
+			if _, ok := schema.(map[string]any); !ok {
+				if _, isBool := schema.(bool); !isBool {
+					return nil, fmt.Errorf("input schema for tool %s must be an object or boolean", tool.Name)
+				}
+			}
+		}
+		defPath := "#/$defs/" + escapeJSONPointer(tool.Name)
+		defs[tool.Name] = rebaseRefs(schema, defPath)
+
+		variants = append(variants, map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"tool":  map[string]any{"const": tool.Name},
+				"input": map[string]any{"$ref": defPath},
+			},
+			"required": []string{"tool", "input"},
+		})
+	}
+
+	bundle := map[string]any{
+		"$schema": schemaBundleDialect,
+		"$id":     fmt.Sprintf("urn:forge:agent:%s:%s:tool-inputs", spec.AgentID, spec.Version),
+		"title":   spec.AgentID + " tool inputs",
+		"$defs":   defs,
+	}
+	if len(variants) > 0 {
+		bundle["oneOf"] = variants
+	}
+	return bundle, nil
+}
+
+// rebaseRefs rewrites the document-local $ref values in schema ("#" and
+// "#/...") to resolve under base, the schema's new location in the bundle.
+// A schema or subschema with its own $id is a separate resource whose
+// references still resolve against it, so it is left unchanged.
+func rebaseRefs(schema any, base string) any {
+	switch v := schema.(type) {
+	case map[string]any:
+		if _, ok := v["$id"]; ok {
+			return v
+		}
+		for key, val := range v {
+			if ref, ok := val.(string); ok && key == "$ref" && strings.HasPrefix(ref, "#") {
+				if ref == "#" || strings.HasPrefix(ref, "#/") {
+					v[key] = base + ref[1:]
+				}
+				continue
+			}
+			v[key] = rebaseRefs(val, base)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = rebaseRefs(item, base)
+		}
+	}
+	return schema
+}
+
+// escapeJSONPointer escapes a reference token per RFC 6901.
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,94 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"2 + 3 * 4", 14},
+		{"(2 + 3) * 4", 20},
+		{"10 - 4 - 3", 3},
+		{"20 / 4 / 5", 1},
+		{"-3 + 5", 2},
+		{"2 * -3", -6},
+		{"1.5 * 2", 3},
+		{"sqrt(16)", 4},
+		{"pow(2, 10)", 1024},
+		{"abs(-5)", 5},
+		{"min(3, 7)", 3},
+		{"max(3, 7)", 7},
+		{"SQRT(9)", 3},
+		{"sqrt(pow(3, 2) + pow(4, 2))", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Eval(tt.expr, nil)
+			if err != nil {
+				t.Fatalf("Eval(%q) error: %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEval_Variables(t *testing.T) {
+	vars := map[string]float64{"rate": 10, "burst_size": 3, "x1": 2}
+
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"rate", 10},
+		{"rate * burst_size", 30},
+		{"pow(x1, burst_size) + rate", 18},
+		{"-rate", -10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Eval(tt.expr, vars)
+			if err != nil {
+				t.Fatalf("Eval(%q) error: %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"1 / 0", "division by zero"},
+		{"(1 + 2", "missing closing parenthesis"},
+		{"2 +", "unexpected end of expression"},
+		{"2 $ 3", "unexpected character"},
+		{"foo(1)", "unknown function"},
+		{"sqrt(1, 2)", "sqrt requires 1 argument"},
+		{"pow(2)", "pow requires 2 arguments"},
+		{"missing + 1", "unknown variable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Eval(tt.expr, nil)
+			if err == nil {
+				t.Fatalf("Eval(%q) expected error", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Eval(%q) error = %q, want containing %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/initializ/forge/forge-core/skills"
 	"github.com/initializ/forge/forge-core/types"
 	"github.com/initializ/forge/forge-core/validate"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ─── Compile API ──────────────────────────────────────────────────────
@@ -31,7 +32,8 @@ type CompileResult struct {
 	Spec           *agentspec.AgentSpec
 	CompiledSkills *skills.CompiledSkills // nil if no skills
 	EgressConfig   *security.EgressConfig
-	Allowlist      []byte // JSON-encoded allowlist
+	Allowlist      []byte   // JSON-encoded allowlist
+	Warnings       []string // notes about inferred or unresolved values
 }
 
 // Compile transforms a ForgeConfig into a fully-resolved AgentSpec with
@@ -85,6 +87,7 @@ func Compile(req CompileRequest) (*CompileResult, error) {
 		CompiledSkills: cs,
 		EgressConfig:   egressCfg,
 		Allowlist:      allowlist,
+		Warnings:       compiler.CompileWarnings(req.Config, spec),
 	}, nil
 }
 
@@ -121,6 +124,7 @@ type RuntimeConfig struct {
 	MaxIterations int
 	Guardrails    *runtime.GuardrailEngine // optional
 	Logger        runtime.Logger           // optional
+	Tracer        trace.Tracer             // optional; OpenTelemetry spans per task, LLM call, and tool call
 }
 
 // NewRuntime creates a new LLMExecutor configured for agent execution.
@@ -131,5 +135,6 @@ func NewRuntime(cfg RuntimeConfig) *runtime.LLMExecutor {
 		Hooks:         cfg.Hooks,
 		SystemPrompt:  cfg.SystemPrompt,
 		MaxIterations: cfg.MaxIterations,
+		Tracer:        cfg.Tracer,
 	})
 }
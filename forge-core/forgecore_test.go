@@ -3,6 +3,7 @@ package forgecore
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/initializ/forge/forge-core/a2a"
@@ -324,6 +325,58 @@ func TestCompile_NodeEntrypoint(t *testing.T) {
 	}
 }
 
+func TestCompile_Warnings(t *testing.T) {
+	tests := []struct {
+		name       string
+		entrypoint string
+		tools      []types.ToolRef
+		want       []string
+	}{
+		{
+			name:       "node entrypoint with custom framework",
+			entrypoint: "node index.js",
+			want: []string{
+				`runtime image node:20-slim was inferred from entrypoint "node index.js", which is not a standard custom entrypoint; check the image matches the agent's runtime`,
+			},
+		},
+		{
+			name:       "unknown command",
+			entrypoint: "ruby agent.rb",
+			want:       []string{`could not infer a runtime image from entrypoint "ruby agent.rb"; defaulting to ubuntu:latest`},
+		},
+		{
+			name:       "unknown tool",
+			entrypoint: "python3 main.py",
+			tools:      []types.ToolRef{{Name: "web_search"}, {Name: "cli_execute"}, {Name: "lookup_order"}},
+			want:       []string{`tool "lookup_order" does not match a builtin tool; it must be provided by a script in tools/`},
+		},
+		{
+			name:       "scaffolded entrypoint",
+			entrypoint: "python main.py",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &types.ForgeConfig{
+				AgentID:    "warn-agent",
+				Version:    "1.0.0",
+				Framework:  "custom",
+				Entrypoint: tt.entrypoint,
+				Tools:      tt.tools,
+			}
+
+			result, err := Compile(CompileRequest{Config: cfg})
+			if err != nil {
+				t.Fatalf("Compile() error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Warnings, tt.want) {
+				t.Errorf("Warnings = %q, want %q", result.Warnings, tt.want)
+			}
+		})
+	}
+}
+
 func TestCompile_InvalidEgressProfile(t *testing.T) {
 	cfg := &types.ForgeConfig{
 		AgentID:    "bad-egress",
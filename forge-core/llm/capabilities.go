@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Capabilities describes the optional features a model supports.
+type Capabilities struct {
+	Tools         bool `json:"tools"`
+	Vision        bool `json:"vision"`
+	Streaming     bool `json:"streaming"`
+	JSONMode      bool `json:"json_mode"`
+	PromptCaching bool `json:"prompt_caching"`
+}
+
+// DefaultCapabilities apply to models not found in the registry. They assume
+// tool calling, streaming and JSON output, which most chat models support.
+var DefaultCapabilities = Capabilities{Tools: true, Streaming: true, JSONMode: true}
+
+var (
+	capMu sync.RWMutex
+	// modelCapabilities maps model name prefixes to capabilities. The longest
+	// matching prefix wins, so "llama3.1" overrides "llama3".
+	modelCapabilities = map[string]Capabilities{
+		// OpenAI
+		"gpt-4o":        {Tools: true, Vision: true, Streaming: true, JSONMode: true},
+		"gpt-4.1":       {Tools: true, Vision: true, Streaming: true, JSONMode: true},
+		"gpt-4-turbo":   {Tools: true, Vision: true, Streaming: true, JSONMode: true},
+		"gpt-4":         {Tools: true, Streaming: true},
+		"gpt-3.5-turbo": {Tools: true, Streaming: true, JSONMode: true},
+		"o1":            {Tools: true, Vision: true, Streaming: true, JSONMode: true},
+		"o1-mini":       {Streaming: true},
+		"o1-preview":    {Streaming: true},
+		"o3":            {Tools: true, Vision: true, Streaming: true, JSONMode: true},
+		"o3-mini":       {Tools: true, Streaming: true, JSONMode: true},
+		"o4-mini":       {Tools: true, Vision: true, Streaming: true, JSONMode: true},
+
+		// Anthropic
+		"claude-": {Tools: true, Vision: true, Streaming: true, JSONMode: true, PromptCaching: true},
+
+		// Google
+		"gemini-": {Tools: true, Vision: true, Streaming: true, JSONMode: true},
+
+		// Ollama
+		"llama3":   {Streaming: true, JSONMode: true},
+		"llama3.1": {Tools: true, Streaming: true, JSONMode: true},
+		"llama3.2": {Tools: true, Streaming: true, JSONMode: true},
+		"llama3.3": {Tools: true, Streaming: true, JSONMode: true},
+		"llama2":   {Streaming: true, JSONMode: true},
+		"gemma":    {Streaming: true, JSONMode: true},
+		"llava":    {Vision: true, Streaming: true, JSONMode: true},
+		"mistral":  {Tools: true, Streaming: true, JSONMode: true},
+		"qwen2.5":  {Tools: true, Streaming: true, JSONMode: true},
+	}
+)
+
+// LookupCapabilities returns the capabilities of the named model, matching
+// the longest registered prefix. Unknown models get DefaultCapabilities.
+func LookupCapabilities(model string) Capabilities {
+	model = strings.ToLower(model)
+	// Strip provider-style namespaces such as "models/gemini-2.5-flash"
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+
+	capMu.RLock()
+	defer capMu.RUnlock()
+
+	best := -1
+	caps := DefaultCapabilities
+	for prefix, c := range modelCapabilities {
+		if strings.HasPrefix(model, prefix) && len(prefix) > best {
+			best = len(prefix)
+			caps = c
+		}
+	}
+	return caps
+}
+
+// RegisterModelCapabilities sets the capabilities for models matching prefix,
+// replacing any existing entry.
+func RegisterModelCapabilities(prefix string, caps Capabilities) {
+	capMu.Lock()
+	defer capMu.Unlock()
+	modelCapabilities[strings.ToLower(prefix)] = caps
+}
+
+// Gate adapts req to the capabilities of model. Optional features the model
+// lacks are dropped: tool definitions, images and the prompt cache marker.
+// Requests that cannot be sent without changing their meaning, such as a
+// conversation that already contains tool calls or a demand for JSON
+// output, return an error. Streaming is not a request field; callers check
+// c.Streaming before calling ChatStream.
+func (c Capabilities) Gate(model string, req *ChatRequest) error {
+	if !c.JSONMode && req.ResponseFormat.IsJSON() {
+		return fmt.Errorf("model %q does not support JSON output", model)
+	}
+	if !c.PromptCaching {
+		req.CacheSystemPrompt = false
+	}
+	if !c.Vision {
+		for i := range req.Messages {
+			req.Messages[i].Images = nil
+		}
+	}
+	if !c.Tools {
+		for _, m := range req.Messages {
+			if len(m.ToolCalls) > 0 || m.Role == RoleTool {
+				return fmt.Errorf("model %q does not support tool calling, but the conversation contains tool calls", model)
+			}
+		}
+		req.Tools = nil
+	}
+	return nil
+}
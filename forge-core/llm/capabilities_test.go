@@ -0,0 +1,115 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookupCapabilities(t *testing.T) {
+	tests := []struct {
+		model string
+		want  Capabilities
+	}{
+		{"gpt-4o", Capabilities{Tools: true, Vision: true, Streaming: true, JSONMode: true}},
+		{"gpt-4o-mini", Capabilities{Tools: true, Vision: true, Streaming: true, JSONMode: true}},
+		{"o1-mini", Capabilities{Streaming: true}},
+		{"o3-mini", Capabilities{Tools: true, Streaming: true, JSONMode: true}},
+		{"claude-sonnet-4-20250514", Capabilities{Tools: true, Vision: true, Streaming: true, JSONMode: true, PromptCaching: true}},
+		{"models/gemini-2.5-flash", Capabilities{Tools: true, Vision: true, Streaming: true, JSONMode: true}},
+		{"llama3", Capabilities{Streaming: true, JSONMode: true}},
+		{"llama3.1:8b", Capabilities{Tools: true, Streaming: true, JSONMode: true}},
+		{"GPT-4O", Capabilities{Tools: true, Vision: true, Streaming: true, JSONMode: true}},
+		{"some-custom-model", DefaultCapabilities},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if got := LookupCapabilities(tt.model); got != tt.want {
+				t.Errorf("LookupCapabilities(%q) = %+v, want %+v", tt.model, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterModelCapabilities(t *testing.T) {
+	RegisterModelCapabilities("acme-vision", Capabilities{Vision: true})
+	if got := LookupCapabilities("acme-vision-2"); !got.Vision || got.Tools {
+		t.Errorf("registered capabilities not applied: %+v", got)
+	}
+}
+
+func TestCapabilitiesGate_OmitsTools(t *testing.T) {
+	req := &ChatRequest{
+		Messages: []ChatMessage{{Role: RoleUser, Content: "hi"}},
+		Tools:    []ToolDefinition{{Type: "function", Function: FunctionSchema{Name: "search"}}},
+	}
+	if err := LookupCapabilities("llama3").Gate("llama3", req); err != nil {
+		t.Fatalf("Gate() error: %v", err)
+	}
+	if req.Tools != nil {
+		t.Errorf("expected tools omitted for model without tool support, got %v", req.Tools)
+	}
+
+	req.Tools = []ToolDefinition{{Type: "function", Function: FunctionSchema{Name: "search"}}}
+	if err := LookupCapabilities("gpt-4o").Gate("gpt-4o", req); err != nil {
+		t.Fatalf("Gate() error: %v", err)
+	}
+	if len(req.Tools) != 1 {
+		t.Error("tools should be kept for a tool-capable model")
+	}
+}
+
+func TestCapabilitiesGate_RejectsToolHistory(t *testing.T) {
+	req := &ChatRequest{
+		Messages: []ChatMessage{
+			{Role: RoleUser, Content: "hi"},
+			{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "1", Type: "function", Function: FunctionCall{Name: "search"}}}},
+			{Role: RoleTool, ToolCallID: "1", Content: "result"},
+		},
+	}
+	err := LookupCapabilities("o1-mini").Gate("o1-mini", req)
+	if err == nil || !strings.Contains(err.Error(), `model "o1-mini" does not support tool calling`) {
+		t.Errorf("expected descriptive tool calling error, got %v", err)
+	}
+}
+
+func TestCapabilitiesGate_OptionalFeatures(t *testing.T) {
+	newReq := func() *ChatRequest {
+		return &ChatRequest{
+			Messages:          []ChatMessage{{Role: RoleUser, Content: "what is this?", Images: []ImageContent{{URL: "https://example.com/a.png"}}}},
+			CacheSystemPrompt: true,
+		}
+	}
+
+	req := newReq()
+	if err := LookupCapabilities("llama3").Gate("llama3", req); err != nil {
+		t.Fatalf("Gate() error: %v", err)
+	}
+	if req.CacheSystemPrompt {
+		t.Error("prompt caching should be dropped for a model without it")
+	}
+	if req.Messages[0].Images != nil {
+		t.Error("images should be dropped for a model without vision")
+	}
+
+	req = newReq()
+	if err := LookupCapabilities("claude-sonnet-4").Gate("claude-sonnet-4", req); err != nil {
+		t.Fatalf("Gate() error: %v", err)
+	}
+	if !req.CacheSystemPrompt || len(req.Messages[0].Images) != 1 {
+		t.Errorf("caching and images should be kept for claude, got %+v", req)
+	}
+}
+
+func TestCapabilitiesGate_RejectsJSONMode(t *testing.T) {
+	req := &ChatRequest{
+		Messages:       []ChatMessage{{Role: RoleUser, Content: "hi"}},
+		ResponseFormat: &ResponseFormat{Type: ResponseFormatJSONObject},
+	}
+	err := LookupCapabilities("gpt-4").Gate("gpt-4", req)
+	if err == nil || !strings.Contains(err.Error(), `model "gpt-4" does not support JSON output`) {
+		t.Errorf("expected JSON output error, got %v", err)
+	}
+	if err := LookupCapabilities("gpt-4o").Gate("gpt-4o", req); err != nil {
+		t.Errorf("gpt-4o supports JSON output, got %v", err)
+	}
+}
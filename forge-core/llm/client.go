@@ -18,6 +18,22 @@ type ClientConfig struct {
 	BaseURL     string
 	Model       string
 	OrgID       string
+	Region      string // cloud region for providers such as Bedrock
 	MaxRetries  int
 	TimeoutSecs int
+
+	// APIStyle selects the OpenAI endpoint: APIStyleChat or
+	// APIStyleResponses. Empty picks Responses for o-series reasoning
+	// models and Chat Completions otherwise. Other providers ignore it.
+	APIStyle string
+
+	// ReasoningEffort is sent to OpenAI reasoning models: minimal, low,
+	// medium or high. Empty leaves the model default.
+	ReasoningEffort string
 }
+
+// OpenAI API styles for ClientConfig.APIStyle.
+const (
+	APIStyleChat      = "chat"
+	APIStyleResponses = "responses"
+)
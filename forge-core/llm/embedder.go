@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"context"
+	"math"
+)
+
+// Embedder converts text into embedding vectors for semantic retrieval.
+type Embedder interface {
+	// Embed returns one vector per input text, in input order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// ModelID returns the embedding model identifier.
+	ModelID() string
+}
+
+// UsageEmbedder is an Embedder that also reports the tokens its requests
+// used, for cost tracking.
+type UsageEmbedder interface {
+	Embedder
+	// EmbedWithUsage is Embed that also returns the token usage.
+	EmbedWithUsage(ctx context.Context, texts []string) ([][]float32, UsageInfo, error)
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or 0 if
+// the vectors differ in length or either is zero.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
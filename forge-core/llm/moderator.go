@@ -0,0 +1,19 @@
+package llm
+
+import "context"
+
+// Moderator classifies text against a provider's content-moderation policy.
+type Moderator interface {
+	// Moderate returns the moderation verdict for text.
+	Moderate(ctx context.Context, text string) (*ModerationResult, error)
+}
+
+// ModerationResult is a provider's moderation verdict for a piece of text.
+type ModerationResult struct {
+	// Flagged is the provider's overall verdict.
+	Flagged bool `json:"flagged"`
+	// Categories reports which categories the provider flagged.
+	Categories map[string]bool `json:"categories,omitempty"`
+	// Scores holds per-category confidence scores between 0 and 1.
+	Scores map[string]float64 `json:"scores,omitempty"`
+}
@@ -0,0 +1,112 @@
+package llm
+
+import "strings"
+
+// ModelPricing holds token rates for a model in USD per million tokens.
+// The cache rates apply to prompt tokens read from or written to the
+// provider's prompt cache; zero means the normal input rate.
+type ModelPricing struct {
+	InputPerMillion      float64 `json:"input_per_million" yaml:"input_per_million"`
+	OutputPerMillion     float64 `json:"output_per_million" yaml:"output_per_million"`
+	CacheReadPerMillion  float64 `json:"cache_read_per_million,omitempty" yaml:"cache_read_per_million,omitempty"`
+	CacheWritePerMillion float64 `json:"cache_write_per_million,omitempty" yaml:"cache_write_per_million,omitempty"`
+}
+
+// Cost returns the estimated USD cost of usage at these rates. Cached
+// tokens, which usage also counts in PromptTokens, are charged at the cache
+// rates instead of the input rate.
+func (p ModelPricing) Cost(usage UsageInfo) float64 {
+	read, write := p.CacheReadPerMillion, p.CacheWritePerMillion
+	if read == 0 {
+		read = p.InputPerMillion
+	}
+	if write == 0 {
+		write = p.InputPerMillion
+	}
+	uncached := max(usage.PromptTokens-usage.CacheReadTokens-usage.CacheCreationTokens, 0)
+	return (float64(uncached)*p.InputPerMillion +
+		float64(usage.CacheReadTokens)*read +
+		float64(usage.CacheCreationTokens)*write +
+		float64(usage.CompletionTokens)*p.OutputPerMillion) / 1e6
+}
+
+// PricingTable maps model name prefixes to pricing. The longest matching
+// prefix wins, so "gpt-4o-mini" overrides "gpt-4o".
+type PricingTable map[string]ModelPricing
+
+// DefaultPricing lists published list prices for common models. Prices
+// change; override them with the pricing section of forge.yaml. OpenAI and
+// Google charge nothing extra to write the cache; Anthropic charges 1.25
+// times the input rate for five-minute cache writes.
+var DefaultPricing = PricingTable{
+	// OpenAI
+	"gpt-4o":        {InputPerMillion: 2.50, OutputPerMillion: 10.00, CacheReadPerMillion: 1.25},
+	"gpt-4o-mini":   {InputPerMillion: 0.15, OutputPerMillion: 0.60, CacheReadPerMillion: 0.075},
+	"gpt-4.1":       {InputPerMillion: 2.00, OutputPerMillion: 8.00, CacheReadPerMillion: 0.50},
+	"gpt-4.1-mini":  {InputPerMillion: 0.40, OutputPerMillion: 1.60, CacheReadPerMillion: 0.10},
+	"gpt-4.1-nano":  {InputPerMillion: 0.10, OutputPerMillion: 0.40, CacheReadPerMillion: 0.025},
+	"gpt-4-turbo":   {InputPerMillion: 10.00, OutputPerMillion: 30.00},
+	"gpt-3.5-turbo": {InputPerMillion: 0.50, OutputPerMillion: 1.50},
+	"o1":            {InputPerMillion: 15.00, OutputPerMillion: 60.00, CacheReadPerMillion: 7.50},
+	"o1-mini":       {InputPerMillion: 1.10, OutputPerMillion: 4.40, CacheReadPerMillion: 0.55},
+	"o3":            {InputPerMillion: 2.00, OutputPerMillion: 8.00, CacheReadPerMillion: 0.50},
+	"o3-mini":       {InputPerMillion: 1.10, OutputPerMillion: 4.40, CacheReadPerMillion: 0.55},
+	"o4-mini":       {InputPerMillion: 1.10, OutputPerMillion: 4.40, CacheReadPerMillion: 0.275},
+
+	// Anthropic
+	"claude-opus-4":     {InputPerMillion: 15.00, OutputPerMillion: 75.00, CacheReadPerMillion: 1.50, CacheWritePerMillion: 18.75},
+	"claude-sonnet-4":   {InputPerMillion: 3.00, OutputPerMillion: 15.00, CacheReadPerMillion: 0.30, CacheWritePerMillion: 3.75},
+	"claude-3-7-sonnet": {InputPerMillion: 3.00, OutputPerMillion: 15.00, CacheReadPerMillion: 0.30, CacheWritePerMillion: 3.75},
+	"claude-3-5-sonnet": {InputPerMillion: 3.00, OutputPerMillion: 15.00, CacheReadPerMillion: 0.30, CacheWritePerMillion: 3.75},
+	"claude-3-5-haiku":  {InputPerMillion: 0.80, OutputPerMillion: 4.00, CacheReadPerMillion: 0.08, CacheWritePerMillion: 1.00},
+	"claude-3-haiku":    {InputPerMillion: 0.25, OutputPerMillion: 1.25, CacheReadPerMillion: 0.03, CacheWritePerMillion: 0.30},
+
+	// Google
+	"gemini-2.5-pro":        {InputPerMillion: 1.25, OutputPerMillion: 10.00, CacheReadPerMillion: 0.125},
+	"gemini-2.5-flash":      {InputPerMillion: 0.30, OutputPerMillion: 2.50, CacheReadPerMillion: 0.03},
+	"gemini-2.5-flash-lite": {InputPerMillion: 0.10, OutputPerMillion: 0.40, CacheReadPerMillion: 0.01},
+	"gemini-2.0-flash":      {InputPerMillion: 0.10, OutputPerMillion: 0.40, CacheReadPerMillion: 0.025},
+}
+
+// Lookup returns the pricing for model, matching the longest registered
+// prefix. The second result is false if no prefix matches.
+func (t PricingTable) Lookup(model string) (ModelPricing, bool) {
+	model = strings.ToLower(model)
+	// Strip provider-style namespaces such as "models/gemini-2.5-flash"
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		model = model[i+1:]
+	}
+
+	best := -1
+	var pricing ModelPricing
+	for prefix, p := range t {
+		if strings.HasPrefix(model, strings.ToLower(prefix)) && len(prefix) > best {
+			best = len(prefix)
+			pricing = p
+		}
+	}
+	return pricing, best >= 0
+}
+
+// EstimateCost returns the estimated USD cost of usage for model. The second
+// result is false when the model has no pricing, in which case the cost is
+// unavailable rather than zero.
+func (t PricingTable) EstimateCost(model string, usage UsageInfo) (float64, bool) {
+	p, ok := t.Lookup(model)
+	if !ok {
+		return 0, false
+	}
+	return p.Cost(usage), true
+}
+
+// Merge returns a new table with overrides applied on top of t.
+func (t PricingTable) Merge(overrides PricingTable) PricingTable {
+	merged := make(PricingTable, len(t)+len(overrides))
+	for k, v := range t {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
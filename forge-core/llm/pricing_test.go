@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPricingTable_EstimateCost(t *testing.T) {
+	usage := UsageInfo{PromptTokens: 12000, CompletionTokens: 3000, TotalTokens: 15000}
+
+	tests := []struct {
+		model string
+		want  float64
+	}{
+		{"gpt-4o", 0.06},                    // 12000*2.50/1M + 3000*10.00/1M
+		{"gpt-4o-mini-2024-07-18", 0.0036},  // longest prefix wins over gpt-4o
+		{"claude-sonnet-4-20250514", 0.081}, // 12000*3/1M + 3000*15/1M
+		{"models/gemini-2.5-flash", 0.0111}, // namespace stripped
+	}
+	for _, tt := range tests {
+		got, ok := DefaultPricing.EstimateCost(tt.model, usage)
+		if !ok {
+			t.Errorf("EstimateCost(%q) unavailable, want %v", tt.model, tt.want)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EstimateCost(%q) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestPricingTable_CachedTokens(t *testing.T) {
+	// 10000 prompt tokens, of which 6000 read from and 2000 written to the cache
+	usage := UsageInfo{PromptTokens: 10000, CompletionTokens: 1000, CacheReadTokens: 6000, CacheCreationTokens: 2000}
+
+	tests := []struct {
+		model string
+		want  float64
+	}{
+		{"claude-sonnet-4-20250514", 0.0303}, // 2000*3 + 6000*0.30 + 2000*3.75 + 1000*15, per 1M
+		{"gpt-4o", 0.0275},                   // 4000*2.50 + 6000*1.25 + 1000*10, writes at the input rate
+		{"gpt-4-turbo", 0.13},                // no cache rates: every prompt token at the input rate
+	}
+	for _, tt := range tests {
+		got, _ := DefaultPricing.EstimateCost(tt.model, usage)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EstimateCost(%q) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestPricingTable_O1Mini(t *testing.T) {
+	usage := UsageInfo{PromptTokens: 1e6}
+	if got, _ := DefaultPricing.EstimateCost("o1-mini-2024-09-12", usage); got != 1.10 {
+		t.Errorf("o1-mini cost = %v, want its own rate, not o1's", got)
+	}
+}
+
+func TestPricingTable_UnknownModel(t *testing.T) {
+	cost, ok := DefaultPricing.EstimateCost("llama3.1:8b", UsageInfo{PromptTokens: 100, CompletionTokens: 100})
+	if ok {
+		t.Errorf("EstimateCost(llama3.1:8b) = %v, want unavailable", cost)
+	}
+}
+
+func TestPricingTable_Merge(t *testing.T) {
+	table := DefaultPricing.Merge(PricingTable{
+		"gpt-4o":      {InputPerMillion: 1, OutputPerMillion: 2},
+		"my-finetune": {InputPerMillion: 4, OutputPerMillion: 4},
+	})
+	if cost, _ := table.EstimateCost("gpt-4o", UsageInfo{PromptTokens: 1e6, CompletionTokens: 1e6}); cost != 3 {
+		t.Errorf("overridden gpt-4o cost = %v, want 3", cost)
+	}
+	if _, ok := table.EstimateCost("my-finetune-v2", UsageInfo{}); !ok {
+		t.Error("added model should be priced")
+	}
+	if p, _ := DefaultPricing.Lookup("gpt-4o"); p.InputPerMillion != 2.50 {
+		t.Error("Merge must not modify the receiver")
+	}
+}
@@ -16,10 +16,11 @@ import (
 
 // AnthropicClient implements llm.Client for the Anthropic Messages API.
 type AnthropicClient struct {
-	apiKey  string
-	baseURL string
-	model   string
-	client  *http.Client
+	apiKey     string
+	baseURL    string
+	model      string
+	client     *http.Client
+	maxRetries int
 }
 
 // NewAnthropicClient creates a new Anthropic client.
@@ -33,10 +34,11 @@ func NewAnthropicClient(cfg llm.ClientConfig) *AnthropicClient {
 		timeout = 120 * time.Second
 	}
 	return &AnthropicClient{
-		apiKey:  cfg.APIKey,
-		baseURL: strings.TrimRight(baseURL, "/"),
-		model:   cfg.Model,
-		client:  &http.Client{Timeout: timeout},
+		apiKey:     cfg.APIKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		model:      cfg.Model,
+		client:     &http.Client{Timeout: timeout},
+		maxRetries: resolveMaxRetries(cfg.MaxRetries),
 	}
 }
 
@@ -50,13 +52,17 @@ func (c *AnthropicClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.
 		return nil, fmt.Errorf("marshalling request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(data))
-	if err != nil {
-		return nil, err
-	}
-	c.setHeaders(httpReq)
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
+	defer cancel()
 
-	resp, err := c.client.Do(httpReq)
+	resp, err := doWithRetry(ctx, client, c.maxRetries, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(httpReq)
+		return httpReq, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("anthropic request: %w", err)
 	}
@@ -64,10 +70,16 @@ func (c *AnthropicClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("anthropic error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, &StatusError{Op: "anthropic", StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
-	return c.parseAnthropicResponse(resp.Body)
+	result, err := c.parseAnthropicResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	unwrapResponseTool(result, req.ResponseFormat)
+	result.Model = c.model
+	return result, nil
 }
 
 // ChatStream sends a streaming messages request.
@@ -78,31 +90,91 @@ func (c *AnthropicClient) ChatStream(ctx context.Context, req *llm.ChatRequest)
 		return nil, fmt.Errorf("marshalling request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(data))
-	if err != nil {
-		return nil, err
-	}
-	c.setHeaders(httpReq)
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
 
-	resp, err := c.client.Do(httpReq)
+	resp, err := doWithRetry(ctx, client, c.maxRetries, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(httpReq)
+		return httpReq, nil
+	})
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("anthropic stream request: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return nil, fmt.Errorf("anthropic stream error (status %d): %s", resp.StatusCode, string(respBody))
+		cancel()
+		return nil, &StatusError{Op: "anthropic stream", StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
-	ch := make(chan llm.StreamDelta, 32)
+	ch := streamBody(ctx, cancel, resp.Body, c.readAnthropicStream)
+
+	return unwrapResponseToolStream(ch, req.ResponseFormat), nil
+}
+
+// unwrapResponseTool turns a call to the response tool injected for a JSON
+// response format back into message content. Other tool calls are kept; if
+// any remain the response call is dropped and the loop carries on.
+func unwrapResponseTool(resp *llm.ChatResponse, f *llm.ResponseFormat) {
+	if !f.IsJSON() {
+		return
+	}
+	var rest []llm.ToolCall
+	var content string
+	found := false
+	for _, tc := range resp.Message.ToolCalls {
+		if tc.Function.Name == f.SchemaName() && !found {
+			content, found = tc.Function.Arguments, true
+			continue
+		}
+		rest = append(rest, tc)
+	}
+	if !found {
+		return
+	}
+	resp.Message.ToolCalls = rest
+	if len(rest) == 0 {
+		resp.Message.Content = content
+		resp.FinishReason = "stop"
+	}
+}
+
+// unwrapResponseToolStream applies unwrapResponseTool to a stream, emitting
+// the response tool's input as content.
+func unwrapResponseToolStream(in <-chan llm.StreamDelta, f *llm.ResponseFormat) <-chan llm.StreamDelta {
+	if !f.IsJSON() {
+		return in
+	}
+	out := make(chan llm.StreamDelta, cap(in))
 	go func() {
-		defer func() { _ = resp.Body.Close() }()
-		defer close(ch)
-		c.readAnthropicStream(resp.Body, ch)
+		defer close(out)
+		otherCalls := false
+		for d := range in {
+			var rest []llm.ToolCall
+			for _, tc := range d.ToolCalls {
+				if tc.Function.Name == f.SchemaName() {
+					out <- llm.StreamDelta{Content: tc.Function.Arguments}
+					continue
+				}
+				rest = append(rest, tc)
+				otherCalls = true
+			}
+			d.ToolCalls = rest
+			if d.FinishReason == "tool_calls" && !otherCalls {
+				d.FinishReason = "stop"
+			}
+			if d.Content == "" && len(d.ToolCalls) == 0 && d.FinishReason == "" && d.Usage == nil && d.Err == nil && !d.Done {
+				continue
+			}
+			out <- d
+		}
 	}()
-
-	return ch, nil
+	return out
 }
 
 func (c *AnthropicClient) setHeaders(req *http.Request) {
@@ -113,12 +185,33 @@ func (c *AnthropicClient) setHeaders(req *http.Request) {
 
 // Anthropic-specific request types.
 type anthropicRequest struct {
-	Model     string             `json:"model"`
-	Messages  []anthropicMessage `json:"messages"`
-	System    string             `json:"system,omitempty"`
-	MaxTokens int                `json:"max_tokens"`
-	Tools     []anthropicTool    `json:"tools,omitempty"`
-	Stream    bool               `json:"stream,omitempty"`
+	Model         string               `json:"model,omitempty"`
+	Messages      []anthropicMessage   `json:"messages"`
+	System        json.RawMessage      `json:"system,omitempty"` // string, or text blocks when cached
+	MaxTokens     int                  `json:"max_tokens"`
+	Temperature   *float64             `json:"temperature,omitempty"`
+	TopP          *float64             `json:"top_p,omitempty"`
+	StopSequences []string             `json:"stop_sequences,omitempty"`
+	Tools         []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice    *anthropicToolChoice `json:"tool_choice,omitempty"`
+	Stream        bool                 `json:"stream,omitempty"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"` // "auto", "any", or "tool"
+	Name string `json:"name,omitempty"`
+}
+
+// anthropicCacheControl marks the end of a cacheable prompt prefix.
+type anthropicCacheControl struct {
+	Type string `json:"type"` // always "ephemeral"
+}
+
+// anthropicTextBlock is a text block in the system prompt.
+type anthropicTextBlock struct {
+	Type         string                 `json:"type"`
+	Text         string                 `json:"text"`
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
 }
 
 type anthropicMessage struct {
@@ -134,12 +227,25 @@ type anthropicContentBlock struct {
 	Input     json.RawMessage `json:"input,omitempty"`
 	ToolUseID string          `json:"tool_use_id,omitempty"`
 	Content   string          `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+
+	Source *anthropicImageSource `json:"source,omitempty"`
+}
+
+// anthropicImageSource is the source of an image block: inline base64 data
+// or a URL.
+type anthropicImageSource struct {
+	Type      string `json:"type"` // "base64" or "url"
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
 }
 
 type anthropicTool struct {
-	Name        string          `json:"name"`
-	Description string          `json:"description"`
-	InputSchema json.RawMessage `json:"input_schema"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	InputSchema  json.RawMessage        `json:"input_schema"`
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
 }
 
 func (c *AnthropicClient) toAnthropicRequest(req *llm.ChatRequest, stream bool) anthropicRequest {
@@ -154,19 +260,38 @@ func (c *AnthropicClient) toAnthropicRequest(req *llm.ChatRequest, stream bool)
 	}
 
 	r := anthropicRequest{
-		Model:     model,
-		MaxTokens: maxTokens,
-		Stream:    stream,
+		Model:         model,
+		MaxTokens:     maxTokens,
+		Temperature:   req.Temperature,
+		TopP:          req.TopP,
+		StopSequences: req.Stop,
+		Stream:        stream,
 	}
 
-	// Extract system message and convert remaining messages
+	// Extract system message and convert remaining messages. Consecutive
+	// tool results share one user turn, since Anthropic requires roles to
+	// alternate and expects every tool_use answered in the next message.
+	var system string
+	var toolResults []anthropicContentBlock
+	flushToolResults := func() {
+		if len(toolResults) > 0 {
+			data, _ := json.Marshal(toolResults)
+			r.Messages = append(r.Messages, anthropicMessage{Role: "user", Content: data})
+			toolResults = nil
+		}
+	}
 	for _, m := range req.Messages {
-		if m.Role == llm.RoleSystem {
-			r.System = m.Content
-			continue
+		switch m.Role {
+		case llm.RoleSystem:
+			system = m.Content
+		case llm.RoleTool:
+			toolResults = append(toolResults, toolResultBlock(m))
+		default:
+			flushToolResults()
+			r.Messages = append(r.Messages, c.convertMessage(m))
 		}
-		r.Messages = append(r.Messages, c.convertMessage(m))
 	}
+	flushToolResults()
 
 	// Convert tools
 	for _, t := range req.Tools {
@@ -177,6 +302,36 @@ func (c *AnthropicClient) toAnthropicRequest(req *llm.ChatRequest, stream bool)
 		})
 	}
 
+	// Anthropic has no JSON mode, so a JSON response format becomes a tool
+	// the model must call; its input is the response. With other tools
+	// present the model may still call those first.
+	if f := req.ResponseFormat; f.IsJSON() {
+		choice := &anthropicToolChoice{Type: "tool", Name: f.SchemaName()}
+		if len(r.Tools) > 0 {
+			choice = &anthropicToolChoice{Type: "any"}
+		}
+		r.Tools = append(r.Tools, anthropicTool{
+			Name:        f.SchemaName(),
+			Description: "Return your final response. Call this tool once, with the complete response as its input.",
+			InputSchema: f.ObjectSchema(),
+		})
+		r.ToolChoice = choice
+	}
+
+	// The cached prefix runs through tools, then system, so a breakpoint on
+	// the system prompt covers both; without one, mark the last tool.
+	cache := req.CacheSystemPrompt
+	switch {
+	case system != "" && cache:
+		r.System, _ = json.Marshal([]anthropicTextBlock{{
+			Type: "text", Text: system, CacheControl: &anthropicCacheControl{Type: "ephemeral"},
+		}})
+	case system != "":
+		r.System, _ = json.Marshal(system)
+	case cache && len(r.Tools) > 0:
+		r.Tools[len(r.Tools)-1].CacheControl = &anthropicCacheControl{Type: "ephemeral"}
+	}
+
 	return r
 }
 
@@ -188,14 +343,7 @@ func (c *AnthropicClient) convertMessage(m llm.ChatMessage) anthropicMessage {
 
 	// Tool result message
 	if m.Role == llm.RoleTool {
-		blocks := []anthropicContentBlock{
-			{
-				Type:      "tool_result",
-				ToolUseID: m.ToolCallID,
-				Content:   m.Content,
-			},
-		}
-		data, _ := json.Marshal(blocks)
+		data, _ := json.Marshal([]anthropicContentBlock{toolResultBlock(m)})
 		return anthropicMessage{Role: "user", Content: data}
 	}
 
@@ -217,20 +365,73 @@ func (c *AnthropicClient) convertMessage(m llm.ChatMessage) anthropicMessage {
 		return anthropicMessage{Role: "assistant", Content: data}
 	}
 
+	// Message with images, which go before the text as Anthropic recommends
+	if len(m.Images) > 0 {
+		var blocks []anthropicContentBlock
+		for _, img := range m.Images {
+			blocks = append(blocks, imageBlock(img))
+		}
+		if m.Content != "" {
+			blocks = append(blocks, anthropicContentBlock{Type: "text", Text: m.Content})
+		}
+		data, _ := json.Marshal(blocks)
+		return anthropicMessage{Role: role, Content: data}
+	}
+
 	// Simple text message
 	data, _ := json.Marshal(m.Content)
 	return anthropicMessage{Role: role, Content: data}
 }
 
+// imageBlock converts a canonical image to an image content block.
+func imageBlock(img llm.ImageContent) anthropicContentBlock {
+	if img.URL != "" {
+		return anthropicContentBlock{Type: "image", Source: &anthropicImageSource{Type: "url", URL: img.URL}}
+	}
+	return anthropicContentBlock{Type: "image", Source: &anthropicImageSource{
+		Type:      "base64",
+		MediaType: img.MediaType,
+		Data:      img.Data,
+	}}
+}
+
+// toolResultBlock converts a canonical tool result message to a tool_result
+// content block.
+func toolResultBlock(m llm.ChatMessage) anthropicContentBlock {
+	return anthropicContentBlock{
+		Type:      "tool_result",
+		ToolUseID: m.ToolCallID,
+		Content:   m.Content,
+		IsError:   m.IsError,
+	}
+}
+
 // Anthropic-specific response types.
 type anthropicResponse struct {
 	ID         string                  `json:"id"`
 	Content    []anthropicContentBlock `json:"content"`
 	StopReason string                  `json:"stop_reason"`
-	Usage      struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
+	Usage      anthropicUsage          `json:"usage"`
+}
+
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// toUsageInfo converts Anthropic usage, whose input_tokens excludes cached
+// tokens, to the canonical form where PromptTokens includes them.
+func (u anthropicUsage) toUsageInfo() llm.UsageInfo {
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return llm.UsageInfo{
+		PromptTokens:        prompt,
+		CompletionTokens:    u.OutputTokens,
+		TotalTokens:         prompt + u.OutputTokens,
+		CacheCreationTokens: u.CacheCreationInputTokens,
+		CacheReadTokens:     u.CacheReadInputTokens,
+	}
 }
 
 func (c *AnthropicClient) parseAnthropicResponse(body io.Reader) (*llm.ChatResponse, error) {
@@ -266,18 +467,20 @@ func (c *AnthropicClient) parseAnthropicResponse(body io.Reader) (*llm.ChatRespo
 	}
 
 	return &llm.ChatResponse{
-		ID:      resp.ID,
-		Message: msg,
-		Usage: llm.UsageInfo{
-			PromptTokens:     resp.Usage.InputTokens,
-			CompletionTokens: resp.Usage.OutputTokens,
-			TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
-		},
+		ID:           resp.ID,
+		Message:      msg,
+		Usage:        resp.Usage.toUsageInfo(),
 		FinishReason: finishReason,
 	}, nil
 }
 
 // Anthropic streaming event types.
+type anthropicMessageStart struct {
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+}
+
 type anthropicContentBlockStart struct {
 	Index        int                   `json:"index"`
 	ContentBlock anthropicContentBlock `json:"content_block"`
@@ -303,7 +506,7 @@ type anthropicMessageDelta struct {
 
 func (c *AnthropicClient) readAnthropicStream(r io.Reader, ch chan<- llm.StreamDelta) {
 	scanner := bufio.NewScanner(r)
-	var currentToolCall *llm.ToolCall
+	stream := anthropicStream{ch: ch}
 	var eventType string
 
 	for scanner.Scan() {
@@ -318,64 +521,86 @@ func (c *AnthropicClient) readAnthropicStream(r io.Reader, ch chan<- llm.StreamD
 		if !ok {
 			continue
 		}
+		if stream.handle(eventType, []byte(after)) {
+			return
+		}
+	}
+}
 
-		switch eventType {
-		case "content_block_start":
-			var ev anthropicContentBlockStart
-			if json.Unmarshal([]byte(after), &ev) != nil {
-				continue
-			}
-			if ev.ContentBlock.Type == "tool_use" {
-				currentToolCall = &llm.ToolCall{
-					ID:   ev.ContentBlock.ID,
-					Type: "function",
-					Function: llm.FunctionCall{
-						Name: ev.ContentBlock.Name,
-					},
-				}
-			}
+// anthropicStream converts Anthropic streaming events into deltas. It is
+// shared by the SSE stream of the Messages API and Bedrock's event stream,
+// which carry the same event payloads.
+type anthropicStream struct {
+	ch              chan<- llm.StreamDelta
+	currentToolCall *llm.ToolCall
+	usage           anthropicUsage // input usage from message_start
+}
 
-		case "content_block_delta":
-			var ev anthropicContentBlockDelta
-			if json.Unmarshal([]byte(after), &ev) != nil {
-				continue
-			}
-			switch ev.Delta.Type {
-			case "text_delta":
-				ch <- llm.StreamDelta{Content: ev.Delta.Text}
-			case "input_json_delta":
-				if currentToolCall != nil {
-					currentToolCall.Function.Arguments += ev.Delta.PartialJSON
-				}
-			}
+// handle processes one event and reports whether the message is complete.
+func (s *anthropicStream) handle(eventType string, data []byte) bool {
+	switch eventType {
+	case "message_start":
+		var ev anthropicMessageStart
+		if json.Unmarshal(data, &ev) == nil {
+			s.usage = ev.Message.Usage
+		}
 
-		case "content_block_stop":
-			if currentToolCall != nil {
-				ch <- llm.StreamDelta{
-					ToolCalls: []llm.ToolCall{*currentToolCall},
-				}
-				currentToolCall = nil
+	case "content_block_start":
+		var ev anthropicContentBlockStart
+		if json.Unmarshal(data, &ev) != nil {
+			return false
+		}
+		if ev.ContentBlock.Type == "tool_use" {
+			s.currentToolCall = &llm.ToolCall{
+				ID:   ev.ContentBlock.ID,
+				Type: "function",
+				Function: llm.FunctionCall{
+					Name: ev.ContentBlock.Name,
+				},
 			}
+		}
 
-		case "message_delta":
-			var ev anthropicMessageDelta
-			if json.Unmarshal([]byte(after), &ev) != nil {
-				continue
-			}
-			finishReason := "stop"
-			if ev.Delta.StopReason == "tool_use" {
-				finishReason = "tool_calls"
+	case "content_block_delta":
+		var ev anthropicContentBlockDelta
+		if json.Unmarshal(data, &ev) != nil {
+			return false
+		}
+		switch ev.Delta.Type {
+		case "text_delta":
+			s.ch <- llm.StreamDelta{Content: ev.Delta.Text}
+		case "input_json_delta":
+			if s.currentToolCall != nil {
+				s.currentToolCall.Function.Arguments += ev.Delta.PartialJSON
 			}
-			ch <- llm.StreamDelta{
-				FinishReason: finishReason,
-				Usage: &llm.UsageInfo{
-					CompletionTokens: ev.Usage.OutputTokens,
-				},
+		}
+
+	case "content_block_stop":
+		if s.currentToolCall != nil {
+			s.ch <- llm.StreamDelta{
+				ToolCalls: []llm.ToolCall{*s.currentToolCall},
 			}
+			s.currentToolCall = nil
+		}
 
-		case "message_stop":
-			ch <- llm.StreamDelta{Done: true}
-			return
+	case "message_delta":
+		var ev anthropicMessageDelta
+		if json.Unmarshal(data, &ev) != nil {
+			return false
 		}
+		finishReason := "stop"
+		if ev.Delta.StopReason == "tool_use" {
+			finishReason = "tool_calls"
+		}
+		s.usage.OutputTokens = ev.Usage.OutputTokens
+		usage := s.usage.toUsageInfo()
+		s.ch <- llm.StreamDelta{
+			FinishReason: finishReason,
+			Usage:        &usage,
+		}
+
+	case "message_stop":
+		s.ch <- llm.StreamDelta{Done: true}
+		return true
 	}
+	return false
 }
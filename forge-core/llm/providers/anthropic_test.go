@@ -0,0 +1,246 @@
+package providers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestAnthropicToolResultConversion(t *testing.T) {
+	c := NewAnthropicClient(llm.ClientConfig{APIKey: "k", Model: "claude-sonnet-4-20250514"})
+	calls := []llm.ToolCall{
+		{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "web_search", Arguments: `{"query":"go"}`}},
+		{ID: "call_2", Type: "function", Function: llm.FunctionCall{Name: "math_calculate", Arguments: `{"expression":"1/0"}`}},
+	}
+	req := &llm.ChatRequest{Messages: []llm.ChatMessage{
+		{Role: llm.RoleSystem, Content: "sys"},
+		{Role: llm.RoleUser, Content: "hi"},
+		{Role: llm.RoleAssistant, ToolCalls: calls},
+		llm.NewToolResultMessage(calls[0], "results", false),
+		llm.NewToolResultMessage(calls[1], "division by zero", true),
+	}}
+
+	r := c.toAnthropicRequest(req, false)
+	if string(r.System) != `"sys"` {
+		t.Errorf("System = %s", r.System)
+	}
+	if len(r.Messages) != 3 {
+		t.Fatalf("got %d messages, want 3 (tool results merged into one user turn)", len(r.Messages))
+	}
+
+	last := r.Messages[2]
+	if last.Role != "user" {
+		t.Errorf("tool result role = %q, want user", last.Role)
+	}
+	var blocks []anthropicContentBlock
+	if err := json.Unmarshal(last.Content, &blocks); err != nil {
+		t.Fatalf("decoding content: %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("got %d blocks, want 2", len(blocks))
+	}
+	if blocks[0].Type != "tool_result" || blocks[0].ToolUseID != "call_1" || blocks[0].Content != "results" || blocks[0].IsError {
+		t.Errorf("unexpected first block: %+v", blocks[0])
+	}
+	if blocks[1].ToolUseID != "call_2" || !blocks[1].IsError {
+		t.Errorf("unexpected second block: %+v", blocks[1])
+	}
+}
+
+func TestAnthropicImageBlocks(t *testing.T) {
+	c := NewAnthropicClient(llm.ClientConfig{APIKey: "k", Model: "claude-sonnet-4-20250514"})
+	req := &llm.ChatRequest{Messages: []llm.ChatMessage{{
+		Role:    llm.RoleUser,
+		Content: "compare these",
+		Images: []llm.ImageContent{
+			{MediaType: "image/png", Data: "iVBORw0KGgo="},
+			{URL: "https://cdn.example.com/dog.jpg", MediaType: "image/jpeg"},
+		},
+	}}}
+
+	data, err := json.Marshal(c.toAnthropicRequest(req, false).Messages[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"role":"user","content":[` +
+		`{"type":"image","source":{"type":"base64","media_type":"image/png","data":"iVBORw0KGgo="}},` +
+		`{"type":"image","source":{"type":"url","url":"https://cdn.example.com/dog.jpg"}},` +
+		`{"type":"text","text":"compare these"}]}`
+	if string(data) != want {
+		t.Errorf("message =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestAnthropicSamplingParams(t *testing.T) {
+	c := NewAnthropicClient(llm.ClientConfig{APIKey: "k", Model: "claude-sonnet-4-20250514"})
+	req := &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}}
+
+	body, err := json.Marshal(c.toAnthropicRequest(req, false))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	for _, key := range []string{"temperature", "top_p", "stop_sequences"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("unset %s should be omitted, got body %s", key, body)
+		}
+	}
+
+	zero, topP := 0.0, 0.9
+	req.Temperature = &zero
+	req.TopP = &topP
+	req.Stop = []string{"END"}
+	body, err = json.Marshal(c.toAnthropicRequest(req, false))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	fields = nil
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v, ok := fields["temperature"]; !ok || v != 0.0 {
+		t.Errorf("temperature = %v, want explicit 0", v)
+	}
+	if fields["top_p"] != 0.9 {
+		t.Errorf("top_p = %v, want 0.9", fields["top_p"])
+	}
+	if stop, _ := fields["stop_sequences"].([]any); len(stop) != 1 || stop[0] != "END" {
+		t.Errorf("stop_sequences = %v, want [END]", fields["stop_sequences"])
+	}
+}
+
+func TestAnthropicPromptCaching(t *testing.T) {
+	c := NewAnthropicClient(llm.ClientConfig{APIKey: "k", Model: "claude-sonnet-4-20250514"})
+	tools := []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "web_search", Parameters: json.RawMessage(`{"type":"object"}`)}}}
+	req := &llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleSystem, Content: "long static prompt"},
+			{Role: llm.RoleUser, Content: "hi"},
+		},
+		Tools:             tools,
+		CacheSystemPrompt: true,
+	}
+
+	r := c.toAnthropicRequest(req, false)
+	var system []anthropicTextBlock
+	if err := json.Unmarshal(r.System, &system); err != nil {
+		t.Fatalf("system is not a block array: %s", r.System)
+	}
+	if len(system) != 1 || system[0].Text != "long static prompt" || system[0].CacheControl == nil || system[0].CacheControl.Type != "ephemeral" {
+		t.Errorf("system = %+v, want one cached text block", system)
+	}
+	if r.Tools[0].CacheControl != nil {
+		t.Error("tools should be covered by the system breakpoint, not marked separately")
+	}
+
+	// Without a system prompt the last tool carries the breakpoint
+	req.Messages = req.Messages[1:]
+	r = c.toAnthropicRequest(req, false)
+	if r.System != nil {
+		t.Errorf("System = %s, want omitted", r.System)
+	}
+	if r.Tools[0].CacheControl == nil {
+		t.Error("last tool should be marked for caching")
+	}
+}
+
+func TestAnthropicCacheUsage(t *testing.T) {
+	var c AnthropicClient
+	body := `{"id":"msg_1","content":[{"type":"text","text":"ok"}],"stop_reason":"end_turn",
+		"usage":{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":0,"cache_read_input_tokens":2000}}`
+	resp, err := c.parseAnthropicResponse(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := llm.UsageInfo{PromptTokens: 2010, CompletionTokens: 5, TotalTokens: 2015, CacheReadTokens: 2000}
+	if resp.Usage != want {
+		t.Errorf("Usage = %+v, want %+v", resp.Usage, want)
+	}
+
+	// Streaming reports the same totals once the message ends
+	stream := `event: message_start
+data: {"type":"message_start","message":{"usage":{"input_tokens":10,"cache_creation_input_tokens":1500,"cache_read_input_tokens":0}}}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":7}}
+
+event: message_stop
+data: {"type":"message_stop"}
+`
+	ch := make(chan llm.StreamDelta, 8)
+	c.readAnthropicStream(strings.NewReader(stream), ch)
+	close(ch)
+	var got *llm.UsageInfo
+	for d := range ch {
+		if d.Usage != nil {
+			got = d.Usage
+		}
+	}
+	want = llm.UsageInfo{PromptTokens: 1510, CompletionTokens: 7, TotalTokens: 1517, CacheCreationTokens: 1500}
+	if got == nil || *got != want {
+		t.Errorf("stream Usage = %+v, want %+v", got, want)
+	}
+}
+
+func TestAnthropicResponseFormat(t *testing.T) {
+	c := NewAnthropicClient(llm.ClientConfig{APIKey: "k", Model: "claude-sonnet-4-20250514"})
+	format := &llm.ResponseFormat{
+		Type:   llm.ResponseFormatJSONSchema,
+		Name:   "verdict",
+		Schema: json.RawMessage(`{"type":"object","properties":{"ok":{"type":"boolean"}}}`),
+	}
+	req := &llm.ChatRequest{
+		Messages:       []llm.ChatMessage{{Role: llm.RoleUser, Content: "judge"}},
+		ResponseFormat: format,
+	}
+
+	r := c.toAnthropicRequest(req, false)
+	if len(r.Tools) != 1 || r.Tools[0].Name != "verdict" || string(r.Tools[0].InputSchema) != string(format.Schema) {
+		t.Fatalf("Tools = %+v, want the injected verdict tool", r.Tools)
+	}
+	if r.ToolChoice == nil || r.ToolChoice.Type != "tool" || r.ToolChoice.Name != "verdict" {
+		t.Errorf("ToolChoice = %+v, want forced verdict tool", r.ToolChoice)
+	}
+
+	// With agent tools the model may call those before answering
+	req.Tools = []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "web_search"}}}
+	r = c.toAnthropicRequest(req, false)
+	if len(r.Tools) != 2 || r.ToolChoice.Type != "any" {
+		t.Errorf("Tools = %d, ToolChoice = %+v, want 2 tools and any", len(r.Tools), r.ToolChoice)
+	}
+
+	resp := &llm.ChatResponse{
+		Message: llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+			{ID: "t1", Function: llm.FunctionCall{Name: "verdict", Arguments: `{"ok":true}`}},
+		}},
+		FinishReason: "tool_calls",
+	}
+	unwrapResponseTool(resp, format)
+	if resp.Message.Content != `{"ok":true}` || len(resp.Message.ToolCalls) != 0 || resp.FinishReason != "stop" {
+		t.Errorf("unwrapped response = %+v", resp)
+	}
+
+	in := make(chan llm.StreamDelta, 4)
+	in <- llm.StreamDelta{ToolCalls: []llm.ToolCall{{Function: llm.FunctionCall{Name: "verdict", Arguments: `{"ok":false}`}}}}
+	in <- llm.StreamDelta{FinishReason: "tool_calls"}
+	in <- llm.StreamDelta{Done: true}
+	close(in)
+	var content, finish string
+	for d := range unwrapResponseToolStream(in, format) {
+		content += d.Content
+		if d.FinishReason != "" {
+			finish = d.FinishReason
+		}
+		if len(d.ToolCalls) > 0 {
+			t.Errorf("stream leaked tool calls: %+v", d.ToolCalls)
+		}
+	}
+	if content != `{"ok":false}` || finish != "stop" {
+		t.Errorf("stream content = %q, finish = %q", content, finish)
+	}
+}
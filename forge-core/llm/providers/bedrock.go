@@ -0,0 +1,375 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// bedrockAnthropicVersion is the Anthropic API version Bedrock expects in
+// request bodies for Claude models.
+const bedrockAnthropicVersion = "bedrock-2023-05-31"
+
+// BedrockClient implements llm.Client for AWS Bedrock's InvokeModel API. It
+// supports Anthropic Claude models, using the Messages API body format, and
+// Amazon Titan Text models, which do not support tool calling. Requests are
+// signed with SigV4 using credentials from the AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN environment variables.
+type BedrockClient struct {
+	baseURL     string
+	region      string
+	model       string
+	client      *http.Client
+	maxRetries  int
+	credentials func() (awsCredentials, error)
+}
+
+// NewBedrockClient creates a new Bedrock client. The region falls back to
+// AWS_REGION or AWS_DEFAULT_REGION when not set in cfg.
+func NewBedrockClient(cfg llm.ClientConfig) *BedrockClient {
+	region := cfg.Region
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" && region != "" {
+		baseURL = fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)
+	}
+	timeout := time.Duration(cfg.TimeoutSecs) * time.Second
+	if timeout == 0 {
+		timeout = 120 * time.Second
+	}
+	return &BedrockClient{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		region:      region,
+		model:       cfg.Model,
+		client:      &http.Client{Timeout: timeout},
+		maxRetries:  resolveMaxRetries(cfg.MaxRetries),
+		credentials: awsCredentialsFromEnv,
+	}
+}
+
+func (c *BedrockClient) ModelID() string { return c.model }
+
+// Chat sends an InvokeModel request.
+func (c *BedrockClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	model, family, body, err := c.buildBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
+	defer cancel()
+
+	resp, err := doWithRetry(ctx, client, c.maxRetries, func() (*http.Request, error) {
+		return c.newRequest(ctx, model, "invoke", body)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bedrock request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &StatusError{Op: "bedrock", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result *llm.ChatResponse
+	if family == "titan" {
+		result, err = parseTitanResponse(resp.Body)
+	} else {
+		var a AnthropicClient
+		if result, err = a.parseAnthropicResponse(resp.Body); err == nil {
+			unwrapResponseTool(result, req.ResponseFormat)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Model = c.model
+	return result, nil
+}
+
+// ChatStream sends an InvokeModelWithResponseStream request and decodes its
+// event stream.
+func (c *BedrockClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	model, family, body, err := c.buildBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
+
+	resp, err := doWithRetry(ctx, client, c.maxRetries, func() (*http.Request, error) {
+		return c.newRequest(ctx, model, "invoke-with-response-stream", body)
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("bedrock stream request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		cancel()
+		return nil, &StatusError{Op: "bedrock stream", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	ch := streamBody(ctx, cancel, resp.Body, func(r io.Reader, ch chan<- llm.StreamDelta) {
+		readBedrockStream(r, family, ch)
+	})
+
+	if family == "titan" {
+		return ch, nil
+	}
+	return unwrapResponseToolStream(ch, req.ResponseFormat), nil
+}
+
+// bedrockFamily identifies the request format for a Bedrock model ID,
+// inference profile ID, or ARN.
+func bedrockFamily(model string) string {
+	switch {
+	case strings.Contains(model, "anthropic.claude"):
+		return "anthropic"
+	case strings.Contains(model, "amazon.titan-text"):
+		return "titan"
+	}
+	return ""
+}
+
+// bedrockAnthropicRequest is the Messages API body without model or stream,
+// which Bedrock takes from the URL instead.
+type bedrockAnthropicRequest struct {
+	AnthropicVersion string `json:"anthropic_version"`
+	anthropicRequest
+}
+
+func (c *BedrockClient) buildBody(req *llm.ChatRequest) (model, family string, body []byte, err error) {
+	model = req.Model
+	if model == "" {
+		model = c.model
+	}
+	family = bedrockFamily(model)
+
+	switch family {
+	case "anthropic":
+		var a AnthropicClient
+		ar := a.toAnthropicRequest(req, false)
+		ar.Model = ""
+		body, err = json.Marshal(bedrockAnthropicRequest{AnthropicVersion: bedrockAnthropicVersion, anthropicRequest: ar})
+	case "titan":
+		body, err = json.Marshal(toTitanRequest(req))
+	default:
+		return "", "", nil, fmt.Errorf("unsupported bedrock model %q: only Anthropic Claude and Amazon Titan Text models are supported", model)
+	}
+	if err != nil {
+		return "", "", nil, fmt.Errorf("marshalling request: %w", err)
+	}
+	return model, family, body, nil
+}
+
+// newRequest builds and signs a request for a model action such as "invoke".
+func (c *BedrockClient) newRequest(ctx context.Context, model, action string, body []byte) (*http.Request, error) {
+	if c.baseURL == "" {
+		return nil, fmt.Errorf("bedrock region is not set; set AWS_REGION")
+	}
+	creds, err := c.credentials()
+	if err != nil {
+		return nil, fmt.Errorf("bedrock credentials: %w", err)
+	}
+
+	url := c.baseURL + "/model/" + awsURIEncode(model) + "/" + action
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if action == "invoke" {
+		req.Header.Set("Accept", "application/json")
+	} else {
+		req.Header.Set("Accept", "application/vnd.amazon.eventstream")
+	}
+	signSigV4(req, body, creds, c.region, "bedrock", time.Now())
+	return req, nil
+}
+
+// readBedrockStream decodes event stream frames. Each "chunk" event carries
+// a base64 payload holding one model-native streaming event. An exception
+// frame, such as a throttling error mid-stream, ends the stream with an
+// error carrying its message.
+func readBedrockStream(r io.Reader, family string, ch chan<- llm.StreamDelta) {
+	stream := anthropicStream{ch: ch}
+	for {
+		msg, err := readEventStreamMessage(r)
+		if err != nil {
+			break
+		}
+		if msg.Headers[":message-type"] != "event" || msg.Headers[":event-type"] != "chunk" {
+			if msg.Headers[":message-type"] == "exception" {
+				ch <- llm.StreamDelta{Err: bedrockStreamException(msg.Headers[":exception-type"], msg.Payload)}
+				ch <- llm.StreamDelta{Done: true}
+				return
+			}
+			continue
+		}
+		var chunk struct {
+			Bytes []byte `json:"bytes"`
+		}
+		if json.Unmarshal(msg.Payload, &chunk) != nil {
+			continue
+		}
+
+		if family == "titan" {
+			if handleTitanChunk(chunk.Bytes, ch) {
+				return
+			}
+			continue
+		}
+		var ev struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(chunk.Bytes, &ev) != nil {
+			continue
+		}
+		if stream.handle(ev.Type, chunk.Bytes) {
+			return
+		}
+	}
+	ch <- llm.StreamDelta{Done: true}
+}
+
+// bedrockStreamException returns the error for an exception frame, whose
+// payload is a JSON object with a message.
+func bedrockStreamException(kind string, payload []byte) error {
+	var body struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(payload, &body)
+	if body.Message == "" {
+		body.Message = strings.TrimSpace(string(payload))
+	}
+	return fmt.Errorf("bedrock stream %s: %s", kind, body.Message)
+}
+
+// Titan Text request and response types.
+type titanRequest struct {
+	InputText            string                `json:"inputText"`
+	TextGenerationConfig titanGenerationConfig `json:"textGenerationConfig"`
+}
+
+type titanGenerationConfig struct {
+	MaxTokenCount int      `json:"maxTokenCount,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"topP,omitempty"`
+	StopSequences []string `json:"stopSequences,omitempty"`
+}
+
+type titanResponse struct {
+	InputTextTokenCount int `json:"inputTextTokenCount"`
+	Results             []struct {
+		TokenCount       int    `json:"tokenCount"`
+		OutputText       string `json:"outputText"`
+		CompletionReason string `json:"completionReason"`
+	} `json:"results"`
+}
+
+type titanStreamChunk struct {
+	OutputText                string  `json:"outputText"`
+	CompletionReason          *string `json:"completionReason"`
+	InputTextTokenCount       int     `json:"inputTextTokenCount"`
+	TotalOutputTextTokenCount int     `json:"totalOutputTextTokenCount"`
+}
+
+// toTitanRequest flattens the conversation into a single prompt, since Titan
+// Text has no message structure. Tools are not sent.
+func toTitanRequest(req *llm.ChatRequest) titanRequest {
+	var b strings.Builder
+	for _, m := range req.Messages {
+		switch m.Role {
+		case llm.RoleSystem:
+			b.WriteString(m.Content + "\n\n")
+		case llm.RoleUser:
+			b.WriteString("User: " + m.Content + "\n")
+		case llm.RoleAssistant:
+			if m.Content != "" {
+				b.WriteString("Bot: " + m.Content + "\n")
+			}
+		}
+	}
+	b.WriteString("Bot:")
+	return titanRequest{
+		InputText: b.String(),
+		TextGenerationConfig: titanGenerationConfig{
+			MaxTokenCount: req.MaxTokens,
+			Temperature:   req.Temperature,
+			TopP:          req.TopP,
+			StopSequences: req.Stop,
+		},
+	}
+}
+
+func titanFinishReason(reason string) string {
+	switch reason {
+	case "FINISH":
+		return "stop"
+	case "LENGTH":
+		return "length"
+	}
+	return strings.ToLower(reason)
+}
+
+func parseTitanResponse(body io.Reader) (*llm.ChatResponse, error) {
+	var resp titanResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("decoding titan response: %w", err)
+	}
+	if len(resp.Results) == 0 {
+		return nil, fmt.Errorf("titan response has no results")
+	}
+	res := resp.Results[0]
+	return &llm.ChatResponse{
+		Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: strings.TrimSpace(res.OutputText)},
+		Usage: llm.UsageInfo{
+			PromptTokens:     resp.InputTextTokenCount,
+			CompletionTokens: res.TokenCount,
+			TotalTokens:      resp.InputTextTokenCount + res.TokenCount,
+		},
+		FinishReason: titanFinishReason(res.CompletionReason),
+	}, nil
+}
+
+// handleTitanChunk emits the deltas for one Titan stream chunk and reports
+// whether generation has finished.
+func handleTitanChunk(data []byte, ch chan<- llm.StreamDelta) bool {
+	var chunk titanStreamChunk
+	if json.Unmarshal(data, &chunk) != nil {
+		return false
+	}
+	if chunk.OutputText != "" {
+		ch <- llm.StreamDelta{Content: chunk.OutputText}
+	}
+	if chunk.CompletionReason == nil {
+		return false
+	}
+	ch <- llm.StreamDelta{
+		FinishReason: titanFinishReason(*chunk.CompletionReason),
+		Usage: &llm.UsageInfo{
+			PromptTokens:     chunk.InputTextTokenCount,
+			CompletionTokens: chunk.TotalOutputTextTokenCount,
+			TotalTokens:      chunk.InputTextTokenCount + chunk.TotalOutputTextTokenCount,
+		},
+	}
+	ch <- llm.StreamDelta{Done: true}
+	return true
+}
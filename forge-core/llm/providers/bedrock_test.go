@@ -0,0 +1,237 @@
+package providers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestSignSigV4_GetVanilla(t *testing.T) {
+	// get-vanilla from the AWS Signature Version 4 test suite
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signSigV4(req, nil, creds, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// encodeEventStreamMessage encodes one AWS event stream frame with string
+// headers.
+func encodeEventStreamMessage(headers map[string]string, payload []byte) []byte {
+	var hdr []byte
+	for name, value := range headers {
+		hdr = append(hdr, byte(len(name)))
+		hdr = append(hdr, name...)
+		hdr = append(hdr, 7)
+		hdr = binary.BigEndian.AppendUint16(hdr, uint16(len(value)))
+		hdr = append(hdr, value...)
+	}
+	msg := binary.BigEndian.AppendUint32(nil, uint32(12+len(hdr)+len(payload)+4))
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(hdr)))
+	msg = binary.BigEndian.AppendUint32(msg, crc32.ChecksumIEEE(msg))
+	msg = append(msg, hdr...)
+	msg = append(msg, payload...)
+	return binary.BigEndian.AppendUint32(msg, crc32.ChecksumIEEE(msg))
+}
+
+// bedrockChunk wraps a model event the way InvokeModelWithResponseStream does.
+func bedrockChunk(event string) []byte {
+	payload, _ := json.Marshal(map[string]string{"bytes": base64.StdEncoding.EncodeToString([]byte(event))})
+	return encodeEventStreamMessage(map[string]string{
+		":message-type": "event",
+		":event-type":   "chunk",
+		":content-type": "application/json",
+	}, payload)
+}
+
+func newTestBedrockClient(baseURL, model string) *BedrockClient {
+	c := NewBedrockClient(llm.ClientConfig{BaseURL: baseURL, Region: "us-west-2", Model: model})
+	c.credentials = func() (awsCredentials, error) {
+		return awsCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "token"}, nil
+	}
+	return c
+}
+
+func TestBedrockChat_Claude(t *testing.T) {
+	model := "anthropic.claude-3-5-sonnet-20241022-v2:0"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/model/anthropic.claude-3-5-sonnet-20241022-v2%3A0/invoke" {
+			t.Errorf("path = %s", r.URL.EscapedPath())
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/us-west-2/bedrock/aws4_request") {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if r.Header.Get("X-Amz-Security-Token") != "token" {
+			t.Error("session token header missing")
+		}
+
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body) //nolint:errcheck
+		if body["anthropic_version"] != bedrockAnthropicVersion {
+			t.Errorf("anthropic_version = %v", body["anthropic_version"])
+		}
+		if _, ok := body["model"]; ok {
+			t.Error("body should not carry the model")
+		}
+		if body["system"] != "sys" {
+			t.Errorf("system = %v", body["system"])
+		}
+
+		fmt.Fprint(w, `{"id":"msg_1","content":[{"type":"tool_use","id":"tu_1","name":"web_search","input":{"q":"go"}}],"stop_reason":"tool_use","usage":{"input_tokens":9,"output_tokens":4}}`) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := newTestBedrockClient(srv.URL, model)
+	resp, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{
+		{Role: llm.RoleSystem, Content: "sys"},
+		{Role: llm.RoleUser, Content: "hi"},
+	}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.FinishReason != "tool_calls" || len(resp.Message.ToolCalls) != 1 || resp.Message.ToolCalls[0].Function.Name != "web_search" {
+		t.Errorf("response = %+v", resp)
+	}
+	if resp.Usage.TotalTokens != 13 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestBedrockChatStream_Claude(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/invoke-with-response-stream") {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		for _, ev := range []string{
+			`{"type":"message_start","message":{"id":"msg_1"}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo"}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"tu_1","name":"web_search"}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"q\":"}}`,
+			`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"go\"}"}}`,
+			`{"type":"content_block_stop","index":1}`,
+			`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":6}}`,
+			`{"type":"message_stop"}`,
+		} {
+			w.Write(bedrockChunk(ev)) //nolint:errcheck
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestBedrockClient(srv.URL, "us.anthropic.claude-sonnet-4-20250514-v1:0")
+	ch, err := c.ChatStream(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	var text, finish string
+	var calls []llm.ToolCall
+	var done bool
+	for d := range ch {
+		text += d.Content
+		calls = append(calls, d.ToolCalls...)
+		if d.FinishReason != "" {
+			finish = d.FinishReason
+		}
+		done = done || d.Done
+	}
+	if text != "Hello" || finish != "tool_calls" || !done {
+		t.Errorf("text = %q, finish = %q, done = %v", text, finish, done)
+	}
+	if len(calls) != 1 || calls[0].Function.Arguments != `{"q":"go"}` {
+		t.Errorf("tool calls = %+v", calls)
+	}
+}
+
+func TestBedrockChatStream_Exception(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		w.Write(bedrockChunk(`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}`)) //nolint:errcheck
+		exception := encodeEventStreamMessage(map[string]string{
+			":message-type":   "exception",
+			":exception-type": "throttlingException",
+			":content-type":   "application/json",
+		}, []byte(`{"message":"Too many tokens, please wait before trying again."}`))
+		w.Write(exception) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := newTestBedrockClient(srv.URL, "us.anthropic.claude-sonnet-4-20250514-v1:0")
+	ch, err := c.ChatStream(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	var streamErr error
+	var done bool
+	for d := range ch {
+		if d.Err != nil {
+			streamErr = d.Err
+		}
+		done = done || d.Done
+	}
+	if !done {
+		t.Error("stream did not finish")
+	}
+	if streamErr == nil || !strings.Contains(streamErr.Error(), "throttlingException: Too many tokens") {
+		t.Errorf("stream error = %v, want the exception message", streamErr)
+	}
+}
+
+func TestBedrockChat_Titan(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body titanRequest
+		json.NewDecoder(r.Body).Decode(&body) //nolint:errcheck
+		if body.InputText != "sys\n\nUser: hi\nBot:" {
+			t.Errorf("inputText = %q", body.InputText)
+		}
+		fmt.Fprint(w, `{"inputTextTokenCount":5,"results":[{"tokenCount":3,"outputText":" Hello!","completionReason":"FINISH"}]}`) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := newTestBedrockClient(srv.URL, "amazon.titan-text-express-v1")
+	resp, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{
+		{Role: llm.RoleSystem, Content: "sys"},
+		{Role: llm.RoleUser, Content: "hi"},
+	}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Message.Content != "Hello!" || resp.FinishReason != "stop" || resp.Usage.TotalTokens != 8 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestBedrockChat_UnsupportedModel(t *testing.T) {
+	c := newTestBedrockClient("http://127.0.0.1:0", "meta.llama3-70b-instruct-v1:0")
+	if _, err := c.Chat(context.Background(), &llm.ChatRequest{}); err == nil || !strings.Contains(err.Error(), "unsupported bedrock model") {
+		t.Errorf("err = %v, want unsupported model error", err)
+	}
+}
+
+func TestReadEventStreamMessage_Checksum(t *testing.T) {
+	frame := bedrockChunk(`{"type":"message_stop"}`)
+	frame[len(frame)-6] ^= 0xff // corrupt the payload
+	if _, err := readEventStreamMessage(strings.NewReader(string(frame))); err == nil {
+		t.Error("expected checksum error for corrupted frame")
+	}
+	if _, err := readEventStreamMessage(strings.NewReader("")); err != io.EOF {
+		t.Errorf("empty stream err = %v, want io.EOF", err)
+	}
+}
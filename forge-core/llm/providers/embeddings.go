@@ -0,0 +1,162 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// Default embedding models per provider.
+const (
+	defaultOpenAIEmbeddingModel = "text-embedding-3-small"
+	defaultGeminiEmbeddingModel = "text-embedding-004"
+)
+
+// maxEmbeddingBatch is the most inputs OpenAI accepts in one embeddings
+// request. Longer input lists are sent in several requests.
+const maxEmbeddingBatch = 2048
+
+// OpenAIEmbedder implements llm.Embedder for the OpenAI Embeddings API and
+// OpenAI-compatible endpoints, including Gemini's.
+type OpenAIEmbedder struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	orgID      string
+	client     *http.Client
+	maxRetries int
+	batchSize  int
+}
+
+// NewOpenAIEmbedder creates a new OpenAI embedder. The model defaults to
+// text-embedding-3-small.
+func NewOpenAIEmbedder(cfg llm.ClientConfig) *OpenAIEmbedder {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	model := cfg.Model
+	if model == "" {
+		model = defaultOpenAIEmbeddingModel
+	}
+	timeout := time.Duration(cfg.TimeoutSecs) * time.Second
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+	return &OpenAIEmbedder{
+		apiKey:     cfg.APIKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		model:      model,
+		orgID:      cfg.OrgID,
+		client:     &http.Client{Timeout: timeout},
+		maxRetries: resolveMaxRetries(cfg.MaxRetries),
+		batchSize:  maxEmbeddingBatch,
+	}
+}
+
+var _ llm.UsageEmbedder = (*OpenAIEmbedder)(nil)
+
+func (e *OpenAIEmbedder) ModelID() string { return e.model }
+
+// Embed requests embeddings for texts, in batches of up to 2048 inputs.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors, _, err := e.EmbedWithUsage(ctx, texts)
+	return vectors, err
+}
+
+// EmbedWithUsage is Embed that also returns the tokens used by all batches.
+// Embeddings have no output, so only PromptTokens and TotalTokens are set.
+func (e *OpenAIEmbedder) EmbedWithUsage(ctx context.Context, texts []string) ([][]float32, llm.UsageInfo, error) {
+	var usage llm.UsageInfo
+	if len(texts) == 0 {
+		return nil, usage, nil
+	}
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.batchSize {
+		batch := texts[start:min(start+e.batchSize, len(texts))]
+		v, u, err := e.embedBatch(ctx, batch)
+		if err != nil {
+			return nil, usage, err
+		}
+		vectors = append(vectors, v...)
+		usage.PromptTokens += u.PromptTokens
+		usage.TotalTokens += u.TotalTokens
+	}
+	return vectors, usage, nil
+}
+
+// embedBatch requests embeddings for texts in a single request.
+func (e *OpenAIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, llm.UsageInfo, error) {
+	var usage llm.UsageInfo
+	data, err := json.Marshal(openaiEmbeddingRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, usage, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	resp, err := doWithRetry(ctx, e.client, e.maxRetries, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		if e.apiKey != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+e.apiKey)
+		}
+		if e.orgID != "" {
+			httpReq.Header.Set("OpenAI-Organization", e.orgID)
+		}
+		return httpReq, nil
+	})
+	if err != nil {
+		return nil, usage, fmt.Errorf("embeddings request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, usage, &StatusError{Op: "embeddings", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result openaiEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, usage, fmt.Errorf("decoding embeddings response: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, usage, fmt.Errorf("embeddings response has %d vectors for %d inputs", len(result.Data), len(texts))
+	}
+
+	// The API may return vectors out of order; index restores input order
+	vectors := make([][]float32, len(texts))
+	for _, d := range result.Data {
+		if d.Index < 0 || d.Index >= len(texts) || vectors[d.Index] != nil {
+			return nil, usage, fmt.Errorf("embeddings response has invalid index %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	usage.PromptTokens = result.Usage.PromptTokens
+	usage.TotalTokens = result.Usage.TotalTokens
+	return vectors, usage, nil
+}
+
+type openaiEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openaiEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
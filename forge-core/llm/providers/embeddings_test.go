@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestOpenAIEmbedder(t *testing.T) {
+	var gotReq openaiEmbeddingRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("path = %s, want /embeddings", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		json.NewDecoder(r.Body).Decode(&gotReq) //nolint:errcheck
+		// Returned out of order to exercise index handling
+		w.Write([]byte(`{"object":"list","data":[` + //nolint:errcheck
+			`{"object":"embedding","index":1,"embedding":[0.3,0.4]},` +
+			`{"object":"embedding","index":0,"embedding":[0.1,0.2]}],` +
+			`"model":"text-embedding-3-small","usage":{"prompt_tokens":4,"total_tokens":4}}`))
+	}))
+	defer srv.Close()
+
+	e := NewOpenAIEmbedder(llm.ClientConfig{APIKey: "sk-test", BaseURL: srv.URL})
+	vectors, err := e.Embed(context.Background(), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	if gotReq.Model != "text-embedding-3-small" {
+		t.Errorf("model = %q, want default text-embedding-3-small", gotReq.Model)
+	}
+	if len(gotReq.Input) != 2 || gotReq.Input[0] != "first" || gotReq.Input[1] != "second" {
+		t.Errorf("input = %v", gotReq.Input)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 0.1 || vectors[1][1] != 0.4 {
+		t.Errorf("vectors = %v, want [[0.1 0.2] [0.3 0.4]]", vectors)
+	}
+}
+
+func TestOpenAIEmbedder_Batches(t *testing.T) {
+	const dims = 1536
+	var batches [][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openaiEmbeddingRequest
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		batches = append(batches, req.Input)
+
+		var resp openaiEmbeddingResponse
+		for i, text := range req.Input {
+			vec := make([]float32, dims)
+			vec[0] = float32(len(text))
+			resp.Data = append(resp.Data, struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			}{i, vec})
+		}
+		resp.Usage.PromptTokens = 2 * len(req.Input)
+		resp.Usage.TotalTokens = 2 * len(req.Input)
+		json.NewEncoder(w).Encode(resp) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	e := NewOpenAIEmbedder(llm.ClientConfig{BaseURL: srv.URL})
+	e.batchSize = 2
+	texts := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	vectors, usage, err := e.EmbedWithUsage(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("EmbedWithUsage: %v", err)
+	}
+
+	if len(batches) != 3 || len(batches[0]) != 2 || len(batches[2]) != 1 {
+		t.Errorf("batches = %v, want sizes 2, 2, 1", batches)
+	}
+	if len(vectors) != len(texts) {
+		t.Fatalf("got %d vectors, want %d", len(vectors), len(texts))
+	}
+	for i, v := range vectors {
+		if len(v) != dims {
+			t.Errorf("vector %d has %d dimensions, want %d", i, len(v), dims)
+		}
+		if v[0] != float32(len(texts[i])) {
+			t.Errorf("vector %d belongs to another input", i)
+		}
+	}
+	if usage.PromptTokens != 10 || usage.TotalTokens != 10 || usage.CompletionTokens != 0 {
+		t.Errorf("usage = %+v, want 10 prompt tokens summed over batches", usage)
+	}
+}
+
+func TestOpenAIEmbedder_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"invalid model"}}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	e := NewOpenAIEmbedder(llm.ClientConfig{BaseURL: srv.URL, Model: "nope"})
+	if _, err := e.Embed(context.Background(), []string{"x"}); err == nil {
+		t.Fatal("expected error for non-200 response")
+	}
+}
+
+func TestNewEmbedder(t *testing.T) {
+	e, err := NewEmbedder("gemini", llm.ClientConfig{APIKey: "k"})
+	if err != nil {
+		t.Fatalf("NewEmbedder(gemini): %v", err)
+	}
+	if e.ModelID() != "text-embedding-004" {
+		t.Errorf("gemini default model = %q", e.ModelID())
+	}
+	if _, err := NewEmbedder("anthropic", llm.ClientConfig{}); err == nil {
+		t.Error("expected error for provider without embeddings")
+	}
+}
@@ -0,0 +1,14 @@
+package providers
+
+import "fmt"
+
+// StatusError is returned when a provider answers with a non-200 status.
+type StatusError struct {
+	Op         string // what failed, e.g. "openai" or "anthropic stream"
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s error (status %d): %s", e.Op, e.StatusCode, e.Body)
+}
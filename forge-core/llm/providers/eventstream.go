@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// eventStreamMessage is one frame of the AWS event stream encoding
+// (application/vnd.amazon.eventstream) used by Bedrock streaming responses.
+type eventStreamMessage struct {
+	Headers map[string]string // string-valued headers only
+	Payload []byte
+}
+
+// maxEventStreamFrame bounds the size of a single frame.
+const maxEventStreamFrame = 16 << 20
+
+// readEventStreamMessage reads the next frame from r, verifying both CRCs.
+// It returns io.EOF when the stream ends cleanly between frames.
+func readEventStreamMessage(r io.Reader) (*eventStreamMessage, error) {
+	var prelude [12]byte
+	if _, err := io.ReadFull(r, prelude[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated event stream prelude")
+		}
+		return nil, err
+	}
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, fmt.Errorf("event stream prelude checksum mismatch")
+	}
+	if totalLen < 16 || totalLen > maxEventStreamFrame || headersLen > totalLen-16 {
+		return nil, fmt.Errorf("invalid event stream frame length %d", totalLen)
+	}
+
+	rest := make([]byte, totalLen-12)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, fmt.Errorf("reading event stream frame: %w", err)
+	}
+	body, msgCRC := rest[:len(rest)-4], binary.BigEndian.Uint32(rest[len(rest)-4:])
+	crc := crc32.Update(crc32.ChecksumIEEE(prelude[:]), crc32.IEEETable, body)
+	if crc != msgCRC {
+		return nil, fmt.Errorf("event stream message checksum mismatch")
+	}
+
+	headers, err := parseEventStreamHeaders(body[:headersLen])
+	if err != nil {
+		return nil, err
+	}
+	return &eventStreamMessage{Headers: headers, Payload: body[headersLen:]}, nil
+}
+
+// eventStreamValueSizes gives the fixed value size of each header type;
+// -1 marks length-prefixed types (bytes and string).
+var eventStreamValueSizes = map[byte]int{
+	0: 0, 1: 0, // bool true, bool false
+	2: 1, 3: 2, 4: 4, 5: 8, // byte, short, int, long
+	6: -1, 7: -1, // bytes, string
+	8: 8, 9: 16, // timestamp, uuid
+}
+
+func parseEventStreamHeaders(b []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	for len(b) > 0 {
+		nameLen := int(b[0])
+		if len(b) < 1+nameLen+1 {
+			return nil, fmt.Errorf("truncated event stream header")
+		}
+		name := string(b[1 : 1+nameLen])
+		typ := b[1+nameLen]
+		b = b[2+nameLen:]
+
+		size, ok := eventStreamValueSizes[typ]
+		if !ok {
+			return nil, fmt.Errorf("unknown event stream header type %d", typ)
+		}
+		if size < 0 {
+			if len(b) < 2 {
+				return nil, fmt.Errorf("truncated event stream header")
+			}
+			size = int(binary.BigEndian.Uint16(b[:2]))
+			b = b[2:]
+			if len(b) < size {
+				return nil, fmt.Errorf("truncated event stream header")
+			}
+			if typ == 7 {
+				headers[name] = string(b[:size])
+			}
+		} else if len(b) < size {
+			return nil, fmt.Errorf("truncated event stream header")
+		}
+		b = b[size:]
+	}
+	return headers, nil
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // NewClient creates an LLM client for the specified provider.
-// Supported providers: "openai", "anthropic", "ollama".
+// Supported providers: "openai", "anthropic", "gemini", "ollama", "bedrock".
 func NewClient(provider string, cfg llm.ClientConfig) (llm.Client, error) {
 	switch provider {
 	case "openai":
@@ -15,13 +15,42 @@ func NewClient(provider string, cfg llm.ClientConfig) (llm.Client, error) {
 	case "anthropic":
 		return NewAnthropicClient(cfg), nil
 	case "gemini":
-		if cfg.BaseURL == "" {
-			cfg.BaseURL = "https://generativelanguage.googleapis.com/v1beta/openai"
-		}
-		return NewOpenAIClient(cfg), nil
+		return NewGeminiClient(cfg), nil
 	case "ollama":
 		return NewOllamaClient(cfg), nil
+	case "bedrock":
+		return NewBedrockClient(cfg), nil
 	default:
 		return nil, fmt.Errorf("unknown LLM provider: %q", provider)
 	}
 }
+
+// NewEmbedder creates an embedder for the specified provider.
+// Supported providers: "openai", "gemini".
+func NewEmbedder(provider string, cfg llm.ClientConfig) (llm.Embedder, error) {
+	switch provider {
+	case "openai":
+		return NewOpenAIEmbedder(cfg), nil
+	case "gemini":
+		if cfg.BaseURL == "" {
+			cfg.BaseURL = "https://generativelanguage.googleapis.com/v1beta/openai"
+		}
+		if cfg.Model == "" {
+			cfg.Model = defaultGeminiEmbeddingModel
+		}
+		return NewOpenAIEmbedder(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider: %q", provider)
+	}
+}
+
+// NewModerator creates a content moderator for the specified provider.
+// Supported providers: "openai".
+func NewModerator(provider string, cfg llm.ClientConfig) (llm.Moderator, error) {
+	switch provider {
+	case "openai":
+		return NewOpenAIModerator(cfg), nil
+	default:
+		return nil, fmt.Errorf("provider %q does not support moderation", provider)
+	}
+}
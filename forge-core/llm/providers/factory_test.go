@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestNewClient_BaseURLOverride(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	tests := []struct {
+		provider string
+		model    string
+		wantPath string
+	}{
+		{"openai", "gpt-4o", "/gateway/chat/completions"},
+		{"anthropic", "claude-sonnet-4-20250514", "/gateway/v1/messages"},
+		{"gemini", "gemini-2.5-flash", "/gateway/models/gemini-2.5-flash:generateContent"},
+		{"ollama", "llama3", "/gateway/chat/completions"},
+		{"bedrock", "anthropic.claude-3-5-sonnet-20241022-v2:0", "/gateway/model/anthropic.claude-3-5-sonnet-20241022-v2%3A0/invoke"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			var gotPath string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.EscapedPath()
+				http.Error(w, "stop here", http.StatusBadRequest)
+			}))
+			defer srv.Close()
+
+			client, err := NewClient(tt.provider, llm.ClientConfig{
+				APIKey:     "k",
+				Model:      tt.model,
+				BaseURL:    srv.URL + "/gateway/",
+				Region:     "us-east-1",
+				MaxRetries: -1,
+			})
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			_, err = client.Chat(context.Background(), &llm.ChatRequest{
+				Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}},
+			})
+			if err == nil {
+				t.Fatal("expected error from stub server")
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("request path = %q, want %q", gotPath, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// FallbackLogger receives a log entry for each attempt of a FallbackClient.
+// runtime.Logger satisfies it.
+type FallbackLogger interface {
+	Info(msg string, fields map[string]any)
+	Warn(msg string, fields map[string]any)
+}
+
+// NamedClient is one entry in a fallback chain.
+type NamedClient struct {
+	Provider string
+	Client   llm.Client
+}
+
+// FallbackClient implements llm.Client over an ordered chain of clients. A
+// request goes to the first client; if that fails with a connection error or
+// a 5xx status (after the client's own retries), the same request is sent to
+// the next. Client errors such as a 400 for invalid arguments are returned
+// immediately, since every provider would reject the request too.
+type FallbackClient struct {
+	clients []NamedClient
+	logger  FallbackLogger
+}
+
+// NewFallbackClient creates a FallbackClient trying clients in order. logger
+// may be nil.
+func NewFallbackClient(clients []NamedClient, logger FallbackLogger) *FallbackClient {
+	return &FallbackClient{clients: clients, logger: logger}
+}
+
+// ModelID returns the primary client's model. Responses name the model
+// that actually served them in ChatResponse.Model and StreamDelta.Model.
+func (f *FallbackClient) ModelID() string {
+	if len(f.clients) == 0 {
+		return ""
+	}
+	return f.clients[0].Client.ModelID()
+}
+
+// Chat sends req to each client in turn until one succeeds or fails with an
+// error that should not fall back. Clients after the primary get the request
+// gated on their own model's capabilities.
+func (f *FallbackClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	return fallback(ctx, f, func(c llm.Client) (*llm.ChatResponse, error) {
+		gated, err := f.gateFor(c, req)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.Chat(ctx, gated)
+		if err == nil && resp.Model == "" {
+			resp.Model = c.ModelID()
+		}
+		return resp, err
+	})
+}
+
+// ChatStream opens a stream with each client in turn until one connects.
+// Once a stream has started, failures are not retried on another client.
+// Deltas from a client other than the primary carry its model.
+func (f *FallbackClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	return fallback(ctx, f, func(c llm.Client) (<-chan llm.StreamDelta, error) {
+		gated, err := f.gateFor(c, req)
+		if err != nil {
+			return nil, err
+		}
+		ch, err := c.ChatStream(ctx, gated)
+		if err != nil || c == f.clients[0].Client {
+			return ch, err
+		}
+		return withStreamModel(ch, c.ModelID()), nil
+	})
+}
+
+// gateFor returns req adapted to the capabilities of c's model. The caller
+// has already gated req for the primary, which gets it unchanged; other
+// clients get a copy.
+func (f *FallbackClient) gateFor(c llm.Client, req *llm.ChatRequest) (*llm.ChatRequest, error) {
+	if c == f.clients[0].Client {
+		return req, nil
+	}
+	gated := *req
+	gated.Messages = append([]llm.ChatMessage(nil), req.Messages...)
+	if err := llm.LookupCapabilities(c.ModelID()).Gate(c.ModelID(), &gated); err != nil {
+		return nil, err
+	}
+	return &gated, nil
+}
+
+// withStreamModel sets Model on every delta from in.
+func withStreamModel(in <-chan llm.StreamDelta, model string) <-chan llm.StreamDelta {
+	out := make(chan llm.StreamDelta, cap(in))
+	go func() {
+		defer close(out)
+		for d := range in {
+			d.Model = model
+			out <- d
+		}
+	}()
+	return out
+}
+
+func fallback[T any](ctx context.Context, f *FallbackClient, call func(llm.Client) (T, error)) (T, error) {
+	var zero T
+	if len(f.clients) == 0 {
+		return zero, fmt.Errorf("no LLM clients configured")
+	}
+	var err error
+	for i, nc := range f.clients {
+		fields := map[string]any{"provider": nc.Provider, "model": nc.Client.ModelID(), "attempt": i + 1}
+		var result T
+		result, err = call(nc.Client)
+		if err == nil {
+			f.log(false, "llm request served", fields)
+			return result, nil
+		}
+		fields["error"] = err.Error()
+		if !shouldFallBack(ctx, err) || i == len(f.clients)-1 {
+			f.log(true, "llm request failed", fields)
+			return zero, err
+		}
+		f.log(true, "llm request failed; falling back", fields)
+	}
+	return zero, err
+}
+
+func (f *FallbackClient) log(warn bool, msg string, fields map[string]any) {
+	switch {
+	case f.logger == nil:
+	case warn:
+		f.logger.Warn(msg, fields)
+	default:
+		f.logger.Info(msg, fields)
+	}
+}
+
+// shouldFallBack reports whether err means the provider is unavailable:
+// a 5xx status or a failure to reach it. Errors after the caller's own
+// context ended never fall back.
+func shouldFallBack(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode >= 500
+	}
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
@@ -0,0 +1,153 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// recordingLogger keeps the messages and providers of fallback log entries.
+type recordingLogger struct {
+	entries []string
+}
+
+func (l *recordingLogger) Info(msg string, fields map[string]any) {
+	l.entries = append(l.entries, fmt.Sprintf("%s %s", fields["provider"], msg))
+}
+
+func (l *recordingLogger) Warn(msg string, fields map[string]any) {
+	l.entries = append(l.entries, fmt.Sprintf("%s %s", fields["provider"], msg))
+}
+
+func fallbackChain(t *testing.T, primaryURL, secondaryURL string) *FallbackClient {
+	t.Helper()
+	return NewFallbackClient([]NamedClient{
+		{Provider: "openai", Client: NewOpenAIClient(llm.ClientConfig{APIKey: "k", BaseURL: primaryURL, Model: "gpt-4o"})},
+		{Provider: "ollama", Client: NewOllamaClient(llm.ClientConfig{BaseURL: secondaryURL, Model: "llama3.1"})},
+	}, nil)
+}
+
+func TestFallbackClient_ServerErrorFallsBack(t *testing.T) {
+	fastRetries(t)
+	primary, primaryCalls := flakyServer(t, 100, http.StatusServiceUnavailable, "", openAIOK)
+	secondary, secondaryCalls := flakyServer(t, 0, 0, "", openAIOK)
+
+	logger := &recordingLogger{}
+	fc := fallbackChain(t, primary.URL, secondary.URL)
+	fc.logger = logger
+
+	req := &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}}
+	resp, err := fc.Chat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Message.Content != "hello" || resp.Model != "llama3.1" {
+		t.Errorf("content = %q from %q, want the secondary's reply", resp.Message.Content, resp.Model)
+	}
+	// The primary exhausts its retries before the chain moves on
+	if got := primaryCalls.Load(); got != int32(defaultMaxRetries+1) {
+		t.Errorf("primary calls = %d, want %d", got, defaultMaxRetries+1)
+	}
+	if got := secondaryCalls.Load(); got != 1 {
+		t.Errorf("secondary calls = %d, want 1", got)
+	}
+	want := "openai llm request failed; falling back,ollama llm request served"
+	if got := strings.Join(logger.entries, ","); got != want {
+		t.Errorf("log = %q, want %q", got, want)
+	}
+	if fc.ModelID() != "gpt-4o" {
+		t.Errorf("ModelID = %q, want the primary's", fc.ModelID())
+	}
+}
+
+func TestFallbackClient_ClientErrorDoesNotFallBack(t *testing.T) {
+	primary, _ := flakyServer(t, 100, http.StatusBadRequest, "", openAIOK)
+	secondary, secondaryCalls := flakyServer(t, 0, 0, "", openAIOK)
+
+	_, err := fallbackChain(t, primary.URL, secondary.URL).Chat(context.Background(), &llm.ChatRequest{})
+	if err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Fatalf("err = %v, want the primary's 400", err)
+	}
+	if secondaryCalls.Load() != 0 {
+		t.Error("a 4xx must not fall back")
+	}
+}
+
+func TestFallbackClient_ConnectionErrorFallsBack(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	secondary, _ := flakyServer(t, 0, 0, "", openAIOK)
+
+	resp, err := fallbackChain(t, down.URL, secondary.URL).Chat(context.Background(), &llm.ChatRequest{})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Message.Content != "hello" {
+		t.Errorf("content = %q", resp.Message.Content)
+	}
+
+	// A cancelled caller is not a provider outage
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fallbackChain(t, down.URL, secondary.URL).Chat(ctx, &llm.ChatRequest{}); err == nil {
+		t.Error("expected an error for a cancelled context")
+	}
+}
+
+func TestFallbackClient_StreamFallsBack(t *testing.T) {
+	fastRetries(t)
+	primary, _ := flakyServer(t, 100, http.StatusInternalServerError, "", openAIOK)
+	secondary, _ := flakyServer(t, 0, 0, "", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"streamed\"}}]}\n\ndata: [DONE]\n\n") //nolint:errcheck
+	})
+
+	ch, err := fallbackChain(t, primary.URL, secondary.URL).ChatStream(context.Background(), &llm.ChatRequest{})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	var content string
+	for d := range ch {
+		content += d.Content
+		if d.Model != "llama3.1" {
+			t.Errorf("delta model = %q, want the secondary's", d.Model)
+		}
+	}
+	if content != "streamed" {
+		t.Errorf("content = %q, want the secondary's stream", content)
+	}
+}
+
+func TestFallbackClient_GatesFallbackRequest(t *testing.T) {
+	fastRetries(t)
+	primary, _ := flakyServer(t, 100, http.StatusServiceUnavailable, "", openAIOK)
+	var body string
+	secondary, _ := flakyServer(t, 0, 0, "", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		openAIOK(w, r)
+	})
+	fc := NewFallbackClient([]NamedClient{
+		{Provider: "openai", Client: NewOpenAIClient(llm.ClientConfig{APIKey: "k", BaseURL: primary.URL, Model: "gpt-4o"})},
+		{Provider: "ollama", Client: NewOllamaClient(llm.ClientConfig{BaseURL: secondary.URL, Model: "llama3"})},
+	}, nil)
+
+	req := &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}},
+		Tools:    []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "search"}}},
+	}
+	if _, err := fc.Chat(context.Background(), req); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if strings.Contains(body, `"tools"`) {
+		t.Errorf("llama3 has no tool calling, but got tools: %s", body)
+	}
+	if len(req.Tools) != 1 {
+		t.Error("gating for the fallback modified the caller's request")
+	}
+}
@@ -0,0 +1,441 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// GeminiClient implements llm.Client for the Google Gemini generateContent API.
+type GeminiClient struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	client     *http.Client
+	maxRetries int
+}
+
+// NewGeminiClient creates a new Gemini client.
+func NewGeminiClient(cfg llm.ClientConfig) *GeminiClient {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	timeout := time.Duration(cfg.TimeoutSecs) * time.Second
+	if timeout == 0 {
+		timeout = 120 * time.Second
+	}
+	return &GeminiClient{
+		apiKey:     cfg.APIKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		model:      cfg.Model,
+		client:     &http.Client{Timeout: timeout},
+		maxRetries: resolveMaxRetries(cfg.MaxRetries),
+	}
+}
+
+func (c *GeminiClient) ModelID() string { return c.model }
+
+// Chat sends a non-streaming generateContent request.
+func (c *GeminiClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	data, err := json.Marshal(c.toGeminiRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
+	defer cancel()
+
+	resp, err := doWithRetry(ctx, client, c.maxRetries, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(req, "generateContent"), bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(httpReq)
+		return httpReq, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gemini request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &StatusError{Op: "gemini", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var gr geminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return nil, fmt.Errorf("decoding gemini response: %w", err)
+	}
+	result := parseGeminiResponse(&gr)
+	result.Model = c.model
+	return result, nil
+}
+
+// ChatStream sends a streamGenerateContent request and reads its SSE stream.
+func (c *GeminiClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	data, err := json.Marshal(c.toGeminiRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
+
+	resp, err := doWithRetry(ctx, client, c.maxRetries, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(req, "streamGenerateContent")+"?alt=sse", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(httpReq)
+		return httpReq, nil
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("gemini stream request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		cancel()
+		return nil, &StatusError{Op: "gemini stream", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	return streamBody(ctx, cancel, resp.Body, readGeminiStream), nil
+}
+
+// endpoint returns the URL of a model method such as "generateContent".
+func (c *GeminiClient) endpoint(req *llm.ChatRequest, method string) string {
+	model := req.Model
+	if model == "" {
+		model = c.model
+	}
+	return fmt.Sprintf("%s/models/%s:%s", c.baseURL, strings.TrimPrefix(model, "models/"), method)
+}
+
+func (c *GeminiClient) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", c.apiKey)
+}
+
+// Gemini-specific request types.
+type geminiRequest struct {
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent         `json:"contents"`
+	Tools             []geminiTool            `json:"tools,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	ID       string         `json:"id,omitempty"`
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"topP,omitempty"`
+	StopSequences   []string `json:"stopSequences,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+
+	ResponseMimeType string          `json:"responseMimeType,omitempty"`
+	ResponseSchema   json.RawMessage `json:"responseSchema,omitempty"`
+}
+
+func (c *GeminiClient) toGeminiRequest(req *llm.ChatRequest) geminiRequest {
+	var r geminiRequest
+	if req.Temperature != nil || req.TopP != nil || len(req.Stop) > 0 || req.MaxTokens > 0 {
+		r.GenerationConfig = &geminiGenerationConfig{
+			Temperature:     req.Temperature,
+			TopP:            req.TopP,
+			StopSequences:   req.Stop,
+			MaxOutputTokens: req.MaxTokens,
+		}
+	}
+	// Gemini rejects a JSON response type alongside function calling, so
+	// requests with tools rely on the executor's validation instead
+	if f := req.ResponseFormat; f.IsJSON() && len(req.Tools) == 0 {
+		if r.GenerationConfig == nil {
+			r.GenerationConfig = &geminiGenerationConfig{}
+		}
+		r.GenerationConfig.ResponseMimeType = "application/json"
+		if f.Type == llm.ResponseFormatJSONSchema {
+			r.GenerationConfig.ResponseSchema = geminiSchema(f.ObjectSchema())
+		}
+	}
+
+	// Consecutive tool results share one user turn, mirroring the parallel
+	// function calls they answer. Gemini matches responses by function name,
+	// so names are recovered from earlier calls when a result lacks one.
+	callNames := make(map[string]string)
+	var results []geminiPart
+	flushResults := func() {
+		if len(results) > 0 {
+			r.Contents = append(r.Contents, geminiContent{Role: "user", Parts: results})
+			results = nil
+		}
+	}
+	for _, m := range req.Messages {
+		switch m.Role {
+		case llm.RoleSystem:
+			r.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: m.Content}}}
+		case llm.RoleTool:
+			name := m.Name
+			if name == "" {
+				name = callNames[m.ToolCallID]
+			}
+			key := "content"
+			if m.IsError {
+				key = "error"
+			}
+			results = append(results, geminiPart{FunctionResponse: &geminiFunctionResponse{
+				ID:       geminiCallID(m.ToolCallID),
+				Name:     name,
+				Response: map[string]any{key: m.Content},
+			}})
+		case llm.RoleAssistant:
+			flushResults()
+			content := geminiContent{Role: "model"}
+			if m.Content != "" {
+				content.Parts = append(content.Parts, geminiPart{Text: m.Content})
+			}
+			for _, tc := range m.ToolCalls {
+				callNames[tc.ID] = tc.Function.Name
+				args := json.RawMessage(tc.Function.Arguments)
+				if !json.Valid(args) {
+					args = json.RawMessage("{}")
+				}
+				content.Parts = append(content.Parts, geminiPart{FunctionCall: &geminiFunctionCall{
+					ID:   geminiCallID(tc.ID),
+					Name: tc.Function.Name,
+					Args: args,
+				}})
+			}
+			r.Contents = append(r.Contents, content)
+		default:
+			flushResults()
+			r.Contents = append(r.Contents, geminiContent{Role: "user", Parts: []geminiPart{{Text: m.Content}}})
+		}
+	}
+	flushResults()
+
+	if len(req.Tools) > 0 {
+		decls := make([]geminiFunctionDeclaration, 0, len(req.Tools))
+		for _, t := range req.Tools {
+			decls = append(decls, geminiFunctionDeclaration{
+				Name:        t.Function.Name,
+				Description: t.Function.Description,
+				Parameters:  geminiSchema(t.Function.Parameters),
+			})
+		}
+		r.Tools = []geminiTool{{FunctionDeclarations: decls}}
+	}
+
+	return r
+}
+
+// geminiCallID returns the ID to echo back to Gemini. IDs this client
+// synthesized for calls that arrived without one are not sent.
+func geminiCallID(id string) string {
+	if strings.HasPrefix(id, geminiSyntheticIDPrefix) {
+		return ""
+	}
+	return id
+}
+
+// geminiSchema strips JSON Schema keywords that Gemini's OpenAPI-subset
+// function parameters reject.
+func geminiSchema(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+	var schema any
+	if json.Unmarshal(raw, &schema) != nil {
+		return raw
+	}
+	var strip func(v any)
+	strip = func(v any) {
+		switch t := v.(type) {
+		case map[string]any:
+			delete(t, "$schema")
+			delete(t, "additionalProperties")
+			for _, child := range t {
+				strip(child)
+			}
+		case []any:
+			for _, child := range t {
+				strip(child)
+			}
+		}
+	}
+	strip(schema)
+	out, err := json.Marshal(schema)
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
+// Gemini-specific response types.
+type geminiResponse struct {
+	ResponseID string `json:"responseId"`
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// geminiSyntheticIDPrefix marks tool call IDs generated for function calls
+// that Gemini returned without an ID.
+const geminiSyntheticIDPrefix = "gemini-call-"
+
+// message collects the text and function calls of the first candidate.
+// callIndex numbers synthesized tool call IDs across stream chunks.
+func (gr *geminiResponse) message(callIndex *int) llm.ChatMessage {
+	msg := llm.ChatMessage{Role: llm.RoleAssistant}
+	if len(gr.Candidates) == 0 {
+		return msg
+	}
+	for _, p := range gr.Candidates[0].Content.Parts {
+		switch {
+		case p.FunctionCall != nil:
+			id := p.FunctionCall.ID
+			if id == "" {
+				id = fmt.Sprintf("%s%d", geminiSyntheticIDPrefix, *callIndex)
+			}
+			*callIndex++
+			args := string(p.FunctionCall.Args)
+			if args == "" || args == "null" {
+				args = "{}"
+			}
+			msg.ToolCalls = append(msg.ToolCalls, llm.ToolCall{
+				ID:       id,
+				Type:     "function",
+				Function: llm.FunctionCall{Name: p.FunctionCall.Name, Arguments: args},
+			})
+		case p.Text != "":
+			msg.Content += p.Text
+		}
+	}
+	return msg
+}
+
+// finishReason maps Gemini's finish reason to the canonical value.
+func (gr *geminiResponse) finishReason(msg llm.ChatMessage) string {
+	if len(msg.ToolCalls) > 0 {
+		return "tool_calls"
+	}
+	if len(gr.Candidates) == 0 {
+		return ""
+	}
+	switch reason := gr.Candidates[0].FinishReason; reason {
+	case "":
+		return ""
+	case "STOP":
+		return "stop"
+	case "MAX_TOKENS":
+		return "length"
+	default:
+		return strings.ToLower(reason)
+	}
+}
+
+func (gr *geminiResponse) usage() *llm.UsageInfo {
+	if gr.UsageMetadata == nil {
+		return nil
+	}
+	return &llm.UsageInfo{
+		PromptTokens:     gr.UsageMetadata.PromptTokenCount,
+		CompletionTokens: gr.UsageMetadata.CandidatesTokenCount,
+		TotalTokens:      gr.UsageMetadata.TotalTokenCount,
+	}
+}
+
+func parseGeminiResponse(gr *geminiResponse) *llm.ChatResponse {
+	var callIndex int
+	msg := gr.message(&callIndex)
+	resp := &llm.ChatResponse{
+		ID:           gr.ResponseID,
+		Message:      msg,
+		FinishReason: gr.finishReason(msg),
+	}
+	if u := gr.usage(); u != nil {
+		resp.Usage = *u
+	}
+	return resp
+}
+
+// readGeminiStream reads streamGenerateContent SSE events. Each event is a
+// partial response; function calls arrive whole, never split across events.
+func readGeminiStream(r io.Reader, ch chan<- llm.StreamDelta) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var callIndex int
+	var finish string
+
+	for scanner.Scan() {
+		after, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var gr geminiResponse
+		if json.Unmarshal([]byte(after), &gr) != nil {
+			continue
+		}
+
+		msg := gr.message(&callIndex)
+		delta := llm.StreamDelta{Content: msg.Content, ToolCalls: msg.ToolCalls, Usage: gr.usage()}
+		// A tool call anywhere in the stream makes the whole turn a tool turn
+		if reason := gr.finishReason(msg); reason != "" && finish != "tool_calls" {
+			finish = reason
+		}
+		if len(gr.Candidates) > 0 && gr.Candidates[0].FinishReason != "" {
+			delta.FinishReason = finish
+		}
+		if delta.Content != "" || len(delta.ToolCalls) > 0 || delta.Usage != nil || delta.FinishReason != "" {
+			ch <- delta
+		}
+	}
+	ch <- llm.StreamDelta{Done: true}
+}
@@ -0,0 +1,180 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestGeminiRequestConversion(t *testing.T) {
+	c := NewGeminiClient(llm.ClientConfig{APIKey: "k", Model: "gemini-2.5-flash"})
+	calls := []llm.ToolCall{
+		{ID: "gemini-call-0", Type: "function", Function: llm.FunctionCall{Name: "web_search", Arguments: `{"query":"go"}`}},
+		{ID: "call_2", Type: "function", Function: llm.FunctionCall{Name: "math_calculate", Arguments: `{"expression":"1/0"}`}},
+	}
+	req := &llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleSystem, Content: "sys"},
+			{Role: llm.RoleUser, Content: "hi"},
+			{Role: llm.RoleAssistant, ToolCalls: calls},
+			llm.NewToolResultMessage(calls[0], "results", false),
+			{Role: llm.RoleTool, ToolCallID: "call_2", Content: "division by zero", IsError: true},
+		},
+		Tools: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{
+			Name:       "web_search",
+			Parameters: json.RawMessage(`{"$schema":"x","type":"object","additionalProperties":false,"properties":{"query":{"type":"string"}}}`),
+		}}},
+	}
+
+	r := c.toGeminiRequest(req)
+	if r.SystemInstruction == nil || r.SystemInstruction.Parts[0].Text != "sys" {
+		t.Errorf("systemInstruction = %+v", r.SystemInstruction)
+	}
+	if len(r.Contents) != 3 {
+		t.Fatalf("got %d contents, want 3 (tool results merged into one user turn)", len(r.Contents))
+	}
+
+	model := r.Contents[1]
+	if model.Role != "model" || len(model.Parts) != 2 || model.Parts[0].FunctionCall.Name != "web_search" {
+		t.Errorf("model turn = %+v", model)
+	}
+	if model.Parts[0].FunctionCall.ID != "" || model.Parts[1].FunctionCall.ID != "call_2" {
+		t.Error("synthesized call IDs should not be sent; provider IDs should")
+	}
+
+	results := r.Contents[2]
+	if results.Role != "user" || len(results.Parts) != 2 {
+		t.Fatalf("tool result turn = %+v", results)
+	}
+	if fr := results.Parts[0].FunctionResponse; fr.Name != "web_search" || fr.Response["content"] != "results" {
+		t.Errorf("first result = %+v", fr)
+	}
+	// The name is recovered from the earlier call when the result lacks one
+	if fr := results.Parts[1].FunctionResponse; fr.Name != "math_calculate" || fr.Response["error"] != "division by zero" {
+		t.Errorf("error result = %+v", fr)
+	}
+
+	params := string(r.Tools[0].FunctionDeclarations[0].Parameters)
+	if params != `{"properties":{"query":{"type":"string"}},"type":"object"}` {
+		t.Errorf("parameters = %s", params)
+	}
+}
+
+func TestGeminiChat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-2.5-flash:generateContent" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if r.Header.Get("x-goog-api-key") != "k" {
+			t.Errorf("api key header = %q", r.Header.Get("x-goog-api-key"))
+		}
+		fmt.Fprint(w, `{
+			"responseId": "r1",
+			"candidates": [{"content": {"role": "model", "parts": [
+				{"text": "Searching."},
+				{"functionCall": {"name": "web_search", "args": {"query": "go"}}}
+			]}, "finishReason": "STOP"}],
+			"usageMetadata": {"promptTokenCount": 12, "candidatesTokenCount": 5, "totalTokenCount": 17}
+		}`) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := NewGeminiClient(llm.ClientConfig{APIKey: "k", BaseURL: srv.URL, Model: "models/gemini-2.5-flash"})
+	resp, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Message.Content != "Searching." || resp.FinishReason != "tool_calls" {
+		t.Errorf("response = %+v", resp)
+	}
+	if len(resp.Message.ToolCalls) != 1 {
+		t.Fatalf("tool calls = %+v", resp.Message.ToolCalls)
+	}
+	tc := resp.Message.ToolCalls[0]
+	if tc.ID == "" || tc.Function.Name != "web_search" || tc.Function.Arguments != `{"query": "go"}` {
+		t.Errorf("tool call = %+v", tc)
+	}
+	if resp.Usage != (llm.UsageInfo{PromptTokens: 12, CompletionTokens: 5, TotalTokens: 17}) {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestGeminiChatStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-2.5-flash:streamGenerateContent" || r.URL.Query().Get("alt") != "sse" {
+			t.Errorf("url = %s", r.URL)
+		}
+		fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Hel\"}]}}]}\n\n")                                                                          //nolint:errcheck
+		fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"lo\"}]}}]}\n\n")                                                                           //nolint:errcheck
+		fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"functionCall\":{\"name\":\"web_search\",\"args\":{\"q\":\"x\"}}}]}}]}\n\n")                         //nolint:errcheck
+		fmt.Fprint(w, "data: {\"candidates\":[{\"finishReason\":\"STOP\"}],\"usageMetadata\":{\"promptTokenCount\":3,\"candidatesTokenCount\":4,\"totalTokenCount\":7}}\n\n") //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := NewGeminiClient(llm.ClientConfig{APIKey: "k", BaseURL: srv.URL, Model: "gemini-2.5-flash"})
+	ch, err := c.ChatStream(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+
+	var text, finish string
+	var calls []llm.ToolCall
+	var usage *llm.UsageInfo
+	var done bool
+	for d := range ch {
+		text += d.Content
+		calls = append(calls, d.ToolCalls...)
+		if d.FinishReason != "" {
+			finish = d.FinishReason
+		}
+		if d.Usage != nil {
+			usage = d.Usage
+		}
+		done = done || d.Done
+	}
+	if text != "Hello" {
+		t.Errorf("text = %q", text)
+	}
+	if len(calls) != 1 || calls[0].Function.Name != "web_search" || calls[0].Function.Arguments != `{"q":"x"}` {
+		t.Errorf("tool calls = %+v", calls)
+	}
+	if finish != "tool_calls" {
+		t.Errorf("finish reason = %q, want tool_calls", finish)
+	}
+	if usage == nil || usage.TotalTokens != 7 {
+		t.Errorf("usage = %+v", usage)
+	}
+	if !done {
+		t.Error("stream did not signal done")
+	}
+}
+
+func TestGeminiResponseFormat(t *testing.T) {
+	c := NewGeminiClient(llm.ClientConfig{APIKey: "k", Model: "gemini-2.5-flash"})
+	req := &llm.ChatRequest{
+		Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}},
+		ResponseFormat: &llm.ResponseFormat{
+			Type:   llm.ResponseFormatJSONSchema,
+			Schema: json.RawMessage(`{"$schema":"http://json-schema.org/draft-07/schema#","type":"object","additionalProperties":false}`),
+		},
+	}
+
+	r := c.toGeminiRequest(req)
+	if r.GenerationConfig == nil || r.GenerationConfig.ResponseMimeType != "application/json" {
+		t.Fatalf("GenerationConfig = %+v, want JSON response type", r.GenerationConfig)
+	}
+	if string(r.GenerationConfig.ResponseSchema) != `{"type":"object"}` {
+		t.Errorf("ResponseSchema = %s, want cleaned schema", r.GenerationConfig.ResponseSchema)
+	}
+
+	// Function calling cannot be combined with a JSON response type
+	req.Tools = []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "web_search"}}}
+	if r := c.toGeminiRequest(req); r.GenerationConfig != nil {
+		t.Errorf("GenerationConfig = %+v, want none with tools", r.GenerationConfig)
+	}
+}
@@ -0,0 +1,106 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+const defaultOpenAIModerationModel = "omni-moderation-latest"
+
+// OpenAIModerator implements llm.Moderator for the OpenAI Moderations API.
+type OpenAIModerator struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	orgID      string
+	client     *http.Client
+	maxRetries int
+}
+
+// NewOpenAIModerator creates a new OpenAI moderator. The model defaults to
+// omni-moderation-latest.
+func NewOpenAIModerator(cfg llm.ClientConfig) *OpenAIModerator {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	model := cfg.Model
+	if model == "" {
+		model = defaultOpenAIModerationModel
+	}
+	timeout := time.Duration(cfg.TimeoutSecs) * time.Second
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	return &OpenAIModerator{
+		apiKey:     cfg.APIKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		model:      model,
+		orgID:      cfg.OrgID,
+		client:     &http.Client{Timeout: timeout},
+		maxRetries: resolveMaxRetries(cfg.MaxRetries),
+	}
+}
+
+// Moderate classifies text with the moderations endpoint.
+func (m *OpenAIModerator) Moderate(ctx context.Context, text string) (*llm.ModerationResult, error) {
+	data, err := json.Marshal(openaiModerationRequest{Model: m.model, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	resp, err := doWithRetry(ctx, m.client, m.maxRetries, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/moderations", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		if m.apiKey != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+m.apiKey)
+		}
+		if m.orgID != "" {
+			httpReq.Header.Set("OpenAI-Organization", m.orgID)
+		}
+		return httpReq, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("moderation request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &StatusError{Op: "moderation", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result openaiModerationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding moderation response: %w", err)
+	}
+	if len(result.Results) == 0 {
+		return nil, fmt.Errorf("moderation response has no results")
+	}
+	r := result.Results[0]
+	return &llm.ModerationResult{Flagged: r.Flagged, Categories: r.Categories, Scores: r.CategoryScores}, nil
+}
+
+type openaiModerationRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type openaiModerationResponse struct {
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		Categories     map[string]bool    `json:"categories"`
+		CategoryScores map[string]float64 `json:"category_scores"`
+	} `json:"results"`
+}
@@ -15,14 +15,19 @@ import (
 	"github.com/initializ/forge/forge-core/llm"
 )
 
-// OpenAIClient implements llm.Client for the OpenAI Chat Completions API.
-// Also works with Azure OpenAI and any OpenAI-compatible endpoint.
+// OpenAIClient implements llm.Client for the OpenAI Chat Completions API,
+// and for the Responses API when configured or when the model is an
+// o-series reasoning model. Also works with Azure OpenAI and any
+// OpenAI-compatible endpoint.
 type OpenAIClient struct {
-	apiKey  string
-	baseURL string
-	model   string
-	orgID   string
-	client  *http.Client
+	apiKey          string
+	baseURL         string
+	model           string
+	orgID           string
+	client          *http.Client
+	maxRetries      int
+	apiStyle        string
+	reasoningEffort string
 }
 
 // NewOpenAIClient creates a new OpenAI client.
@@ -36,11 +41,14 @@ func NewOpenAIClient(cfg llm.ClientConfig) *OpenAIClient {
 		timeout = 120 * time.Second
 	}
 	return &OpenAIClient{
-		apiKey:  cfg.APIKey,
-		baseURL: strings.TrimRight(baseURL, "/"),
-		model:   cfg.Model,
-		orgID:   cfg.OrgID,
-		client:  &http.Client{Timeout: timeout},
+		apiKey:          cfg.APIKey,
+		baseURL:         strings.TrimRight(baseURL, "/"),
+		model:           cfg.Model,
+		orgID:           cfg.OrgID,
+		client:          &http.Client{Timeout: timeout},
+		maxRetries:      resolveMaxRetries(cfg.MaxRetries),
+		apiStyle:        cfg.APIStyle,
+		reasoningEffort: cfg.ReasoningEffort,
 	}
 }
 
@@ -48,19 +56,26 @@ func (c *OpenAIClient) ModelID() string { return c.model }
 
 // Chat sends a non-streaming chat completion request.
 func (c *OpenAIClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	if c.useResponses(req) {
+		return c.chatResponses(ctx, req)
+	}
 	body := c.toOpenAIRequest(req, false)
 	data, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("marshalling request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(data))
-	if err != nil {
-		return nil, err
-	}
-	c.setHeaders(httpReq)
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
+	defer cancel()
 
-	resp, err := c.client.Do(httpReq)
+	resp, err := doWithRetry(ctx, client, c.maxRetries, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(httpReq)
+		return httpReq, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("openai request: %w", err)
 	}
@@ -68,45 +83,51 @@ func (c *OpenAIClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.Cha
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("openai error (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, &StatusError{Op: "openai", StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
-	return c.parseOpenAIResponse(resp.Body)
+	result, err := c.parseOpenAIResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result.Model = c.model
+	return result, nil
 }
 
 // ChatStream sends a streaming chat completion request.
 func (c *OpenAIClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	if c.useResponses(req) {
+		return c.chatResponsesStream(ctx, req)
+	}
 	body := c.toOpenAIRequest(req, true)
 	data, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("marshalling request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(data))
-	if err != nil {
-		return nil, err
-	}
-	c.setHeaders(httpReq)
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
 
-	resp, err := c.client.Do(httpReq)
+	resp, err := doWithRetry(ctx, client, c.maxRetries, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(httpReq)
+		return httpReq, nil
+	})
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("openai stream request: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
-		return nil, fmt.Errorf("openai stream error (status %d): %s", resp.StatusCode, string(respBody))
+		cancel()
+		return nil, &StatusError{Op: "openai stream", StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
-	ch := make(chan llm.StreamDelta, 32)
-	go func() {
-		defer func() { _ = resp.Body.Close() }()
-		defer close(ch)
-		c.readSSEStream(resp.Body, ch)
-	}()
-
-	return ch, nil
+	return streamBody(ctx, cancel, resp.Body, c.readSSEStream), nil
 }
 
 func (c *OpenAIClient) setHeaders(req *http.Request) {
@@ -121,13 +142,27 @@ func (c *OpenAIClient) setHeaders(req *http.Request) {
 
 // openaiRequest is the OpenAI-specific request format.
 type openaiRequest struct {
-	Model         string               `json:"model"`
-	Messages      []openaiMessage      `json:"messages"`
-	Tools         []llm.ToolDefinition `json:"tools,omitempty"`
-	Temperature   *float64             `json:"temperature,omitempty"`
-	MaxTokens     int                  `json:"max_tokens,omitempty"`
-	Stream        bool                 `json:"stream,omitempty"`
-	StreamOptions *streamOptions       `json:"stream_options,omitempty"`
+	Model           string                `json:"model"`
+	Messages        []openaiMessage       `json:"messages"`
+	ReasoningEffort string                `json:"reasoning_effort,omitempty"`
+	Tools           []llm.ToolDefinition  `json:"tools,omitempty"`
+	Temperature     *float64              `json:"temperature,omitempty"`
+	TopP            *float64              `json:"top_p,omitempty"`
+	Stop            []string              `json:"stop,omitempty"`
+	MaxTokens       int                   `json:"max_tokens,omitempty"`
+	Stream          bool                  `json:"stream,omitempty"`
+	StreamOptions   *streamOptions        `json:"stream_options,omitempty"`
+	ResponseFormat  *openaiResponseFormat `json:"response_format,omitempty"`
+}
+
+type openaiResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *openaiJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openaiJSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
 }
 
 type streamOptions struct {
@@ -136,7 +171,7 @@ type streamOptions struct {
 
 type openaiMessage struct {
 	Role       string         `json:"role"`
-	Content    string         `json:"content,omitempty"`
+	Content    any            `json:"content,omitempty"` // string, or []openaiContentPart with images
 	ToolCalls  []llm.ToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string         `json:"tool_call_id,omitempty"`
 	Name       string         `json:"name,omitempty"`
@@ -150,31 +185,80 @@ func (c *OpenAIClient) toOpenAIRequest(req *llm.ChatRequest, stream bool) openai
 
 	msgs := make([]openaiMessage, len(req.Messages))
 	for i, m := range req.Messages {
-		msgs[i] = openaiMessage{
-			Role:       m.Role,
-			Content:    m.Content,
-			ToolCalls:  m.ToolCalls,
-			ToolCallID: m.ToolCallID,
-			Name:       m.Name,
-		}
+		msgs[i] = toOpenAIMessage(m)
 	}
 
 	r := openaiRequest{
-		Model:       model,
-		Messages:    msgs,
-		Tools:       req.Tools,
-		Temperature: req.Temperature,
-		MaxTokens:   req.MaxTokens,
-		Stream:      stream,
+		Model:           model,
+		Messages:        msgs,
+		Tools:           req.Tools,
+		Temperature:     req.Temperature,
+		TopP:            req.TopP,
+		Stop:            req.Stop,
+		MaxTokens:       req.MaxTokens,
+		Stream:          stream,
+		ReasoningEffort: c.reasoningEffort,
 	}
 
 	if stream {
 		r.StreamOptions = &streamOptions{IncludeUsage: true}
 	}
+	if f := req.ResponseFormat; f.IsJSON() {
+		r.ResponseFormat = &openaiResponseFormat{Type: f.Type}
+		if f.Type == llm.ResponseFormatJSONSchema {
+			r.ResponseFormat.JSONSchema = &openaiJSONSchema{Name: f.SchemaName(), Schema: f.ObjectSchema()}
+		}
+	}
 
 	return r
 }
 
+// openaiContentPart is one part of a message whose content mixes text and
+// images.
+type openaiContentPart struct {
+	Type     string          `json:"type"` // "text" or "image_url"
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openaiImageURL `json:"image_url,omitempty"`
+}
+
+type openaiImageURL struct {
+	URL string `json:"url"` // http(s) URL or data: URL
+}
+
+// openaiContent returns a message's content: its text, or content parts
+// when it carries images. Empty text returns nil so it is omitted.
+func openaiContent(m llm.ChatMessage) any {
+	if len(m.Images) == 0 {
+		if m.Content == "" {
+			return nil
+		}
+		return m.Content
+	}
+	var parts []openaiContentPart
+	if m.Content != "" {
+		parts = append(parts, openaiContentPart{Type: "text", Text: m.Content})
+	}
+	for _, img := range m.Images {
+		parts = append(parts, openaiContentPart{Type: "image_url", ImageURL: &openaiImageURL{URL: img.DataURL()}})
+	}
+	return parts
+}
+
+// toOpenAIMessage converts a canonical message. Tool results map to the
+// "tool" role keyed by tool_call_id; OpenAI has no error flag, so failures
+// are conveyed by the content alone.
+func toOpenAIMessage(m llm.ChatMessage) openaiMessage {
+	if m.Role == llm.RoleTool {
+		return openaiMessage{Role: llm.RoleTool, Content: openaiContent(m), ToolCallID: m.ToolCallID}
+	}
+	return openaiMessage{
+		Role:      m.Role,
+		Content:   openaiContent(m),
+		ToolCalls: m.ToolCalls,
+		Name:      m.Name,
+	}
+}
+
 // openaiResponse is the OpenAI-specific response format.
 type openaiResponse struct {
 	ID      string `json:"id"`
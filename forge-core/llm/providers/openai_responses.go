@@ -0,0 +1,408 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// reasoningModelRe matches OpenAI's o-series reasoning models (o1, o3-mini,
+// o4-mini, ...), which are served through the Responses API unless
+// api_style says otherwise.
+var reasoningModelRe = regexp.MustCompile(`^o\d`)
+
+// useResponses reports whether req goes to the Responses API rather than
+// Chat Completions.
+func (c *OpenAIClient) useResponses(req *llm.ChatRequest) bool {
+	switch c.apiStyle {
+	case llm.APIStyleResponses:
+		return true
+	case llm.APIStyleChat:
+		return false
+	}
+	model := req.Model
+	if model == "" {
+		model = c.model
+	}
+	return reasoningModelRe.MatchString(model)
+}
+
+// chatResponses sends a non-streaming request to the Responses API.
+func (c *OpenAIClient) chatResponses(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	data, err := json.Marshal(c.toResponsesRequest(req, false))
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
+	defer cancel()
+
+	resp, err := c.postResponses(ctx, client, data)
+	if err != nil {
+		return nil, fmt.Errorf("openai request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &StatusError{Op: "openai", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	result, err := parseResponsesResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result.Model = c.model
+	return result, nil
+}
+
+// chatResponsesStream sends a streaming request to the Responses API.
+func (c *OpenAIClient) chatResponsesStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	data, err := json.Marshal(c.toResponsesRequest(req, true))
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	ctx, client, cancel := requestTimeout(ctx, c.client, req)
+
+	resp, err := c.postResponses(ctx, client, data)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("openai stream request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		cancel()
+		return nil, &StatusError{Op: "openai stream", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	return streamBody(ctx, cancel, resp.Body, readResponsesStream), nil
+}
+
+func (c *OpenAIClient) postResponses(ctx context.Context, client *http.Client, data []byte) (*http.Response, error) {
+	return doWithRetry(ctx, client, c.maxRetries, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/responses", bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(httpReq)
+		return httpReq, nil
+	})
+}
+
+// responsesRequest is the Responses API request format. It has no stop
+// sequences, so ChatRequest.Stop is not sent.
+type responsesRequest struct {
+	Model           string              `json:"model"`
+	Input           []responsesItem     `json:"input"`
+	Tools           []responsesTool     `json:"tools,omitempty"`
+	Temperature     *float64            `json:"temperature,omitempty"`
+	TopP            *float64            `json:"top_p,omitempty"`
+	MaxOutputTokens int                 `json:"max_output_tokens,omitempty"`
+	Reasoning       *responsesReasoning `json:"reasoning,omitempty"`
+	Text            *responsesText      `json:"text,omitempty"`
+	Stream          bool                `json:"stream,omitempty"`
+}
+
+// responsesItem is an input item: a message, a function call made by the
+// model, or the output of one.
+type responsesItem struct {
+	Type      string  `json:"type"`
+	Role      string  `json:"role,omitempty"`
+	Content   any     `json:"content,omitempty"` // string, or []responsesContentPart with images
+	CallID    string  `json:"call_id,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Arguments string  `json:"arguments,omitempty"`
+	Output    *string `json:"output,omitempty"`
+}
+
+type responsesContentPart struct {
+	Type     string `json:"type"` // "input_text" or "input_image"
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"` // http(s) URL or data: URL
+}
+
+type responsesTool struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type responsesReasoning struct {
+	Effort string `json:"effort"`
+}
+
+type responsesText struct {
+	Format responsesTextFormat `json:"format"`
+}
+
+type responsesTextFormat struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name,omitempty"`
+	Schema json.RawMessage `json:"schema,omitempty"`
+}
+
+func (c *OpenAIClient) toResponsesRequest(req *llm.ChatRequest, stream bool) responsesRequest {
+	model := req.Model
+	if model == "" {
+		model = c.model
+	}
+
+	var input []responsesItem
+	for _, m := range req.Messages {
+		input = append(input, toResponsesItems(m)...)
+	}
+
+	r := responsesRequest{
+		Model:           model,
+		Input:           input,
+		Temperature:     req.Temperature,
+		TopP:            req.TopP,
+		MaxOutputTokens: req.MaxTokens,
+		Stream:          stream,
+	}
+	for _, t := range req.Tools {
+		r.Tools = append(r.Tools, responsesTool{
+			Type:        "function",
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			Parameters:  t.Function.Parameters,
+		})
+	}
+	if c.reasoningEffort != "" {
+		r.Reasoning = &responsesReasoning{Effort: c.reasoningEffort}
+	}
+	if f := req.ResponseFormat; f.IsJSON() {
+		r.Text = &responsesText{Format: responsesTextFormat{Type: f.Type}}
+		if f.Type == llm.ResponseFormatJSONSchema {
+			r.Text.Format.Name = f.SchemaName()
+			r.Text.Format.Schema = f.ObjectSchema()
+		}
+	}
+
+	return r
+}
+
+// toResponsesItems converts a canonical message. An assistant turn becomes
+// its text, if any, followed by one function_call item per tool call, and a
+// tool result becomes a function_call_output keyed by call_id.
+func toResponsesItems(m llm.ChatMessage) []responsesItem {
+	if m.Role == llm.RoleTool {
+		output := m.Content
+		return []responsesItem{{Type: "function_call_output", CallID: m.ToolCallID, Output: &output}}
+	}
+	var items []responsesItem
+	if len(m.Images) > 0 {
+		var parts []responsesContentPart
+		if m.Content != "" {
+			parts = append(parts, responsesContentPart{Type: "input_text", Text: m.Content})
+		}
+		for _, img := range m.Images {
+			parts = append(parts, responsesContentPart{Type: "input_image", ImageURL: img.DataURL()})
+		}
+		items = append(items, responsesItem{Type: "message", Role: m.Role, Content: parts})
+	} else if m.Content != "" || len(m.ToolCalls) == 0 {
+		items = append(items, responsesItem{Type: "message", Role: m.Role, Content: m.Content})
+	}
+	for _, tc := range m.ToolCalls {
+		items = append(items, responsesItem{
+			Type:      "function_call",
+			CallID:    tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+		})
+	}
+	return items
+}
+
+// responsesResponse is the Responses API response envelope. Its output is
+// a list of items: reasoning summaries, messages with output_text content,
+// and function calls.
+type responsesResponse struct {
+	ID                string                `json:"id"`
+	Status            string                `json:"status"`
+	Output            []responsesOutputItem `json:"output"`
+	Usage             responsesUsage        `json:"usage"`
+	Error             *responsesError       `json:"error"`
+	IncompleteDetails *struct {
+		Reason string `json:"reason"`
+	} `json:"incomplete_details"`
+}
+
+type responsesOutputItem struct {
+	Type    string `json:"type"`
+	Role    string `json:"role,omitempty"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content,omitempty"`
+	CallID    string `json:"call_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
+type responsesUsage struct {
+	InputTokens        int `json:"input_tokens"`
+	OutputTokens       int `json:"output_tokens"`
+	TotalTokens        int `json:"total_tokens"`
+	InputTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"input_tokens_details"`
+}
+
+type responsesError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (u responsesUsage) toUsageInfo() llm.UsageInfo {
+	return llm.UsageInfo{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      u.TotalTokens,
+		CacheReadTokens:  u.InputTokensDetails.CachedTokens,
+	}
+}
+
+// finishReason maps the response status to a Chat Completions finish
+// reason.
+func (r *responsesResponse) finishReason(hasToolCalls bool) string {
+	switch {
+	case hasToolCalls:
+		return "tool_calls"
+	case r.Status == "incomplete" && r.IncompleteDetails != nil && r.IncompleteDetails.Reason == "max_output_tokens":
+		return "length"
+	case r.Status == "incomplete" && r.IncompleteDetails != nil && r.IncompleteDetails.Reason == "content_filter":
+		return "content_filter"
+	}
+	return "stop"
+}
+
+// toChatResponse converts the envelope, joining the text of every message
+// item and ignoring reasoning items.
+func (r *responsesResponse) toChatResponse() (*llm.ChatResponse, error) {
+	if r.Status == "failed" {
+		if r.Error != nil {
+			return nil, fmt.Errorf("openai response failed: %s: %s", r.Error.Code, r.Error.Message)
+		}
+		return nil, fmt.Errorf("openai response failed")
+	}
+
+	var content strings.Builder
+	var calls []llm.ToolCall
+	for _, item := range r.Output {
+		switch item.Type {
+		case "message":
+			for _, part := range item.Content {
+				if part.Type == "output_text" {
+					content.WriteString(part.Text)
+				}
+			}
+		case "function_call":
+			calls = append(calls, llm.ToolCall{
+				ID:       item.CallID,
+				Type:     "function",
+				Function: llm.FunctionCall{Name: item.Name, Arguments: item.Arguments},
+			})
+		}
+	}
+
+	return &llm.ChatResponse{
+		ID: r.ID,
+		Message: llm.ChatMessage{
+			Role:      llm.RoleAssistant,
+			Content:   content.String(),
+			ToolCalls: calls,
+		},
+		Usage:        r.Usage.toUsageInfo(),
+		FinishReason: r.finishReason(len(calls) > 0),
+	}, nil
+}
+
+func parseResponsesResponse(body io.Reader) (*llm.ChatResponse, error) {
+	var resp responsesResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("decoding openai response: %w", err)
+	}
+	return resp.toChatResponse()
+}
+
+// responsesStreamEvent is one server-sent event of a streaming response.
+// Only the fields of the events the client handles are decoded.
+type responsesStreamEvent struct {
+	Type     string              `json:"type"`
+	Delta    string              `json:"delta"`
+	Item     responsesOutputItem `json:"item"`
+	Response *responsesResponse  `json:"response"`
+	Message  string              `json:"message"`
+}
+
+// readResponsesStream converts Responses API events into deltas. Text
+// arrives as output_text deltas; each function call is sent whole once its
+// item is done. The final event carries the usage and status.
+func readResponsesStream(r io.Reader, ch chan<- llm.StreamDelta) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	hasToolCalls := false
+	for scanner.Scan() {
+		after, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var ev responsesStreamEvent
+		if err := json.Unmarshal([]byte(after), &ev); err != nil {
+			continue
+		}
+
+		switch ev.Type {
+		case "response.output_text.delta":
+			ch <- llm.StreamDelta{Content: ev.Delta}
+
+		case "response.output_item.done":
+			if ev.Item.Type == "function_call" {
+				hasToolCalls = true
+				ch <- llm.StreamDelta{ToolCalls: []llm.ToolCall{{
+					ID:       ev.Item.CallID,
+					Type:     "function",
+					Function: llm.FunctionCall{Name: ev.Item.Name, Arguments: ev.Item.Arguments},
+				}}}
+			}
+
+		case "response.completed", "response.incomplete":
+			if ev.Response == nil {
+				continue
+			}
+			usage := ev.Response.Usage.toUsageInfo()
+			ch <- llm.StreamDelta{FinishReason: ev.Response.finishReason(hasToolCalls), Usage: &usage}
+			ch <- llm.StreamDelta{Done: true}
+			return
+
+		case "response.failed":
+			err := fmt.Errorf("openai response failed")
+			if ev.Response != nil {
+				if _, rerr := ev.Response.toChatResponse(); rerr != nil {
+					err = rerr
+				}
+			}
+			ch <- llm.StreamDelta{Err: err}
+			ch <- llm.StreamDelta{Done: true}
+			return
+
+		case "error":
+			ch <- llm.StreamDelta{Err: fmt.Errorf("openai stream error: %s", ev.Message)}
+			ch <- llm.StreamDelta{Done: true}
+			return
+		}
+	}
+}
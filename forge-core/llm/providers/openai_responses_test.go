@@ -0,0 +1,186 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestOpenAIUseResponses(t *testing.T) {
+	tests := []struct {
+		style string
+		model string
+		want  bool
+	}{
+		{"", "gpt-4o", false},
+		{"", "o1", true},
+		{"", "o3-mini", true},
+		{"", "o4-mini-2025-04-16", true},
+		{"", "ollama-o1", false},
+		{llm.APIStyleChat, "o3-mini", false},
+		{llm.APIStyleResponses, "gpt-4o", true},
+	}
+	for _, tt := range tests {
+		c := NewOpenAIClient(llm.ClientConfig{Model: tt.model, APIStyle: tt.style})
+		if got := c.useResponses(&llm.ChatRequest{}); got != tt.want {
+			t.Errorf("useResponses(style=%q, model=%q) = %v, want %v", tt.style, tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestToResponsesRequest(t *testing.T) {
+	c := NewOpenAIClient(llm.ClientConfig{Model: "o3-mini", ReasoningEffort: "low"})
+	call := llm.ToolCall{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "get_weather", Arguments: `{"location":"Paris"}`}}
+	req := &llm.ChatRequest{
+		Messages: []llm.ChatMessage{
+			{Role: llm.RoleSystem, Content: "be brief"},
+			{Role: llm.RoleUser, Content: "weather?"},
+			{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{call}},
+			llm.NewToolResultMessage(call, "", false),
+		},
+		Tools: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{
+			Name: "get_weather", Description: "Get weather", Parameters: json.RawMessage(`{"type":"object"}`),
+		}}},
+		MaxTokens:      500,
+		Stop:           []string{"END"},
+		ResponseFormat: &llm.ResponseFormat{Type: llm.ResponseFormatJSONSchema, Schema: json.RawMessage(`{"type":"object"}`)},
+	}
+
+	data, err := json.Marshal(c.toResponsesRequest(req, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"model":"o3-mini","input":[` +
+		`{"type":"message","role":"system","content":"be brief"},` +
+		`{"type":"message","role":"user","content":"weather?"},` +
+		`{"type":"function_call","call_id":"call_1","name":"get_weather","arguments":"{\"location\":\"Paris\"}"},` +
+		`{"type":"function_call_output","call_id":"call_1","output":""}],` +
+		`"tools":[{"type":"function","name":"get_weather","description":"Get weather","parameters":{"type":"object"}}],` +
+		`"max_output_tokens":500,"reasoning":{"effort":"low"},` +
+		`"text":{"format":{"type":"json_schema","name":"response","schema":{"type":"object"}}}}`
+	if string(data) != want {
+		t.Errorf("request =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestParseResponsesResponse_Fixture(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "openai_responses.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	resp, err := parseResponsesResponse(f)
+	if err != nil {
+		t.Fatalf("parseResponsesResponse: %v", err)
+	}
+	if resp.ID != "resp_67ccd3a9da748190baa7f1570fe91ac604becb25c45c1d41" {
+		t.Errorf("ID = %q", resp.ID)
+	}
+	if resp.Message.Role != llm.RoleAssistant || resp.Message.Content != "The weather in Paris is 18°C. Checking Berlin as well." {
+		t.Errorf("message = %+v", resp.Message)
+	}
+	calls := resp.Message.ToolCalls
+	if len(calls) != 1 || calls[0].ID != "call_a8e2b6d0fe3c4b7f" || calls[0].Type != "function" ||
+		calls[0].Function.Name != "get_weather" || calls[0].Function.Arguments != `{"location":"Berlin, Germany"}` {
+		t.Errorf("tool calls = %+v", calls)
+	}
+	if resp.FinishReason != "tool_calls" {
+		t.Errorf("FinishReason = %q, want tool_calls", resp.FinishReason)
+	}
+	want := llm.UsageInfo{PromptTokens: 81, CompletionTokens: 1035, TotalTokens: 1116, CacheReadTokens: 64}
+	if resp.Usage != want {
+		t.Errorf("usage = %+v, want %+v", resp.Usage, want)
+	}
+}
+
+func TestParseResponsesResponse_Status(t *testing.T) {
+	resp, err := parseResponsesResponse(strings.NewReader(`{"id":"resp_1","status":"incomplete","incomplete_details":{"reason":"max_output_tokens"},"output":[{"type":"reasoning","summary":[]}]}`))
+	if err != nil {
+		t.Fatalf("parseResponsesResponse: %v", err)
+	}
+	if resp.FinishReason != "length" || resp.Message.Content != "" {
+		t.Errorf("resp = %+v, want an empty message cut short by length", resp)
+	}
+
+	_, err = parseResponsesResponse(strings.NewReader(`{"id":"resp_2","status":"failed","error":{"code":"server_error","message":"boom"},"output":[]}`))
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("err = %v, want the failure message", err)
+	}
+}
+
+func TestReadResponsesStream_Fixture(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "openai_responses_stream.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	ch := make(chan llm.StreamDelta, 32)
+	readResponsesStream(f, ch)
+	close(ch)
+
+	var content strings.Builder
+	var calls []llm.ToolCall
+	var last llm.StreamDelta
+	var finish string
+	for d := range ch {
+		content.WriteString(d.Content)
+		calls = append(calls, d.ToolCalls...)
+		if d.FinishReason != "" {
+			finish = d.FinishReason
+		}
+		if d.Usage != nil && d.Usage.TotalTokens != 268 {
+			t.Errorf("usage = %+v", d.Usage)
+		}
+		last = d
+	}
+	if content.String() != "Let me check." {
+		t.Errorf("content = %q", content.String())
+	}
+	if len(calls) != 1 || calls[0].ID != "call_Vx4zkNqzHgIaHmUMHWgGn5kb" || calls[0].Function.Arguments != `{"location":"Paris"}` {
+		t.Errorf("tool calls = %+v", calls)
+	}
+	if finish != "tool_calls" || !last.Done {
+		t.Errorf("finish = %q, last = %+v", finish, last)
+	}
+}
+
+func TestOpenAIChat_ResponsesEndpoint(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "openai_responses.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotPath string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &gotBody) //nolint:errcheck
+		w.Write(fixture)               //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	c := NewOpenAIClient(llm.ClientConfig{BaseURL: srv.URL, Model: "o3-mini", ReasoningEffort: "high"})
+	resp, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if gotPath != "/responses" {
+		t.Errorf("path = %q, want /responses", gotPath)
+	}
+	if r, _ := gotBody["reasoning"].(map[string]any); r["effort"] != "high" {
+		t.Errorf("reasoning = %v, want effort high", gotBody["reasoning"])
+	}
+	if resp.FinishReason != "tool_calls" {
+		t.Errorf("FinishReason = %q", resp.FinishReason)
+	}
+}
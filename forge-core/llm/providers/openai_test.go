@@ -0,0 +1,124 @@
+package providers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestOpenAIToolResultConversion(t *testing.T) {
+	c := NewOpenAIClient(llm.ClientConfig{APIKey: "k", Model: "gpt-4o"})
+	call := llm.ToolCall{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "web_search", Arguments: `{}`}}
+	req := &llm.ChatRequest{Messages: []llm.ChatMessage{
+		{Role: llm.RoleUser, Content: "hi"},
+		{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{call}},
+		llm.NewToolResultMessage(call, "failed: timeout", true),
+	}}
+
+	r := c.toOpenAIRequest(req, false)
+	if len(r.Messages) != 3 {
+		t.Fatalf("got %d messages, want 3", len(r.Messages))
+	}
+
+	data, _ := json.Marshal(r.Messages[2])
+	var got map[string]any
+	json.Unmarshal(data, &got) //nolint:errcheck
+	want := map[string]any{"role": "tool", "content": "failed: timeout", "tool_call_id": "call_1"}
+	if len(got) != len(want) {
+		t.Errorf("tool message = %s, want exactly %v", data, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("tool message %s = %v, want %v", k, got[k], v)
+		}
+	}
+
+	if len(r.Messages[1].ToolCalls) != 1 || r.Messages[1].ToolCalls[0].ID != "call_1" {
+		t.Errorf("assistant tool calls not preserved: %+v", r.Messages[1])
+	}
+}
+
+func TestOpenAIResponseFormat(t *testing.T) {
+	c := NewOpenAIClient(llm.ClientConfig{APIKey: "k", Model: "gpt-4o"})
+	req := &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}}
+
+	data, _ := json.Marshal(c.toOpenAIRequest(req, false))
+	if strings.Contains(string(data), "response_format") {
+		t.Errorf("text request should omit response_format: %s", data)
+	}
+
+	req.ResponseFormat = &llm.ResponseFormat{Type: llm.ResponseFormatJSONObject}
+	data, _ = json.Marshal(c.toOpenAIRequest(req, false))
+	if !strings.Contains(string(data), `"response_format":{"type":"json_object"}`) {
+		t.Errorf("json_object not mapped: %s", data)
+	}
+
+	req.ResponseFormat = &llm.ResponseFormat{Type: llm.ResponseFormatJSONSchema, Schema: json.RawMessage(`{"type":"object"}`)}
+	data, _ = json.Marshal(c.toOpenAIRequest(req, false))
+	want := `"response_format":{"type":"json_schema","json_schema":{"name":"response","schema":{"type":"object"}}}`
+	if !strings.Contains(string(data), want) {
+		t.Errorf("json_schema not mapped: %s", data)
+	}
+}
+
+func TestOpenAIImageContent(t *testing.T) {
+	c := NewOpenAIClient(llm.ClientConfig{APIKey: "k", Model: "gpt-4o"})
+	req := &llm.ChatRequest{Messages: []llm.ChatMessage{
+		{Role: llm.RoleUser, Content: "compare these", Images: []llm.ImageContent{
+			{MediaType: "image/png", Data: "iVBORw0KGgo="},
+			{URL: "https://cdn.example.com/dog.jpg"},
+		}},
+		{Role: llm.RoleUser, Content: "plain"},
+	}}
+
+	r := c.toOpenAIRequest(req, false)
+	data, _ := json.Marshal(r.Messages)
+	want := `[{"role":"user","content":[` +
+		`{"type":"text","text":"compare these"},` +
+		`{"type":"image_url","image_url":{"url":"data:image/png;base64,iVBORw0KGgo="}},` +
+		`{"type":"image_url","image_url":{"url":"https://cdn.example.com/dog.jpg"}}]},` +
+		`{"role":"user","content":"plain"}]`
+	if string(data) != want {
+		t.Errorf("messages =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestParseOpenAIResponse_Fixture(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "openai_chat_completion.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	resp, err := NewOpenAIClient(llm.ClientConfig{}).parseOpenAIResponse(f)
+	if err != nil {
+		t.Fatalf("parseOpenAIResponse: %v", err)
+	}
+	if resp.ID != "chatcmpl-B9MHDbslfkBeAs8l4bebGdFOJ6PeG" || resp.FinishReason != "tool_calls" {
+		t.Errorf("resp = %+v", resp)
+	}
+	calls := resp.Message.ToolCalls
+	if len(calls) != 1 || calls[0].ID != "call_12345xyz" || calls[0].Function.Name != "get_weather" || calls[0].Function.Arguments != `{"location":"Paris, France"}` {
+		t.Errorf("tool calls = %+v", calls)
+	}
+	if resp.Usage.PromptTokens != 82 || resp.Usage.CompletionTokens != 17 || resp.Usage.TotalTokens != 99 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestOpenAIReasoningEffort_ChatCompletions(t *testing.T) {
+	c := NewOpenAIClient(llm.ClientConfig{Model: "gpt-4o", ReasoningEffort: "high"})
+	data, _ := json.Marshal(c.toOpenAIRequest(&llm.ChatRequest{}, false))
+	if !strings.Contains(string(data), `"reasoning_effort":"high"`) {
+		t.Errorf("reasoning_effort not sent: %s", data)
+	}
+
+	data, _ = json.Marshal(NewOpenAIClient(llm.ClientConfig{Model: "gpt-4o"}).toOpenAIRequest(&llm.ChatRequest{}, false))
+	if strings.Contains(string(data), "reasoning_effort") {
+		t.Errorf("reasoning_effort sent without config: %s", data)
+	}
+}
@@ -0,0 +1,94 @@
+package providers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// PoolConfig tunes the connections a Pool keeps open to providers.
+type PoolConfig struct {
+	MaxIdleConnsPerHost int           // idle connections kept per provider host; 0 = 16
+	IdleConnTimeout     time.Duration // how long an idle connection is kept; 0 = 90s
+}
+
+// Pool caches chat clients by provider and client configuration, so callers
+// resolving the same model share one client. All pooled clients send
+// requests through one transport, reusing connections across tasks. A Pool
+// is safe for concurrent use.
+type Pool struct {
+	transport *http.Transport
+
+	mu      sync.Mutex
+	clients map[poolKey]llm.Client
+}
+
+type poolKey struct {
+	provider string
+	cfg      llm.ClientConfig
+}
+
+// DefaultPool is the process-wide pool used by the forge runtime.
+var DefaultPool = NewPool(PoolConfig{})
+
+// NewPool creates an empty pool.
+func NewPool(cfg PoolConfig) *Pool {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	if t.MaxIdleConnsPerHost == 0 {
+		t.MaxIdleConnsPerHost = 16
+	}
+	if cfg.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	return &Pool{
+		transport: t,
+		clients:   make(map[poolKey]llm.Client),
+	}
+}
+
+// Client returns the pooled client for provider and cfg, creating it on
+// first use.
+func (p *Pool) Client(provider string, cfg llm.ClientConfig) (llm.Client, error) {
+	key := poolKey{provider: provider, cfg: cfg}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.clients[key]; ok {
+		return c, nil
+	}
+	c, err := NewClient(provider, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := c.(transportUser); ok {
+		t.useTransport(p.transport)
+	}
+	p.clients[key] = c
+	return c, nil
+}
+
+// Len returns the number of pooled clients.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.clients)
+}
+
+// CloseIdleConnections closes connections the pool is holding idle.
+func (p *Pool) CloseIdleConnections() {
+	p.transport.CloseIdleConnections()
+}
+
+// transportUser is implemented by clients whose HTTP transport a Pool can
+// replace with its shared one.
+type transportUser interface {
+	useTransport(t http.RoundTripper)
+}
+
+func (c *OpenAIClient) useTransport(t http.RoundTripper)    { c.client.Transport = t }
+func (c *AnthropicClient) useTransport(t http.RoundTripper) { c.client.Transport = t }
+func (c *GeminiClient) useTransport(t http.RoundTripper)    { c.client.Transport = t }
+func (c *BedrockClient) useTransport(t http.RoundTripper)   { c.client.Transport = t }
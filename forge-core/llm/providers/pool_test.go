@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestPoolReusesClient(t *testing.T) {
+	p := NewPool(PoolConfig{})
+	cfg := llm.ClientConfig{APIKey: "k", Model: "gpt-4o", BaseURL: "http://localhost:1"}
+
+	// Concurrent tasks resolving the same model get one client
+	const tasks = 20
+	got := make([]llm.Client, tasks)
+	var wg sync.WaitGroup
+	for i := 0; i < tasks; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := p.Client("openai", cfg)
+			if err != nil {
+				t.Errorf("Client: %v", err)
+			}
+			got[i] = c
+		}(i)
+	}
+	wg.Wait()
+	for i := 1; i < tasks; i++ {
+		if got[i] != got[0] {
+			t.Fatalf("task %d got a different client", i)
+		}
+	}
+
+	other := cfg
+	other.Model = "gpt-4o-mini"
+	c, err := p.Client("openai", other)
+	if err != nil {
+		t.Fatalf("Client: %v", err)
+	}
+	if c == got[0] {
+		t.Error("different model config should get its own client")
+	}
+	if p.Len() != 2 {
+		t.Errorf("Len = %d, want 2", p.Len())
+	}
+
+	if _, err := p.Client("nope", cfg); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+	if p.Len() != 2 {
+		t.Errorf("failed lookups should not be pooled, Len = %d", p.Len())
+	}
+}
+
+func TestPoolSharesTransport(t *testing.T) {
+	p := NewPool(PoolConfig{MaxIdleConnsPerHost: 4, IdleConnTimeout: time.Minute})
+	if p.transport.MaxIdleConnsPerHost != 4 || p.transport.IdleConnTimeout != time.Minute {
+		t.Errorf("transport = %d idle/host, %s timeout", p.transport.MaxIdleConnsPerHost, p.transport.IdleConnTimeout)
+	}
+
+	for _, provider := range []string{"openai", "ollama", "anthropic", "gemini", "bedrock"} {
+		c, err := p.Client(provider, llm.ClientConfig{Model: "m"})
+		if err != nil {
+			t.Fatalf("%s: %v", provider, err)
+		}
+		var transport any
+		switch c := c.(type) {
+		case *OpenAIClient:
+			transport = c.client.Transport
+		case *OllamaClient:
+			transport = c.client.Transport
+		case *AnthropicClient:
+			transport = c.client.Transport
+		case *GeminiClient:
+			transport = c.client.Transport
+		case *BedrockClient:
+			transport = c.client.Transport
+		}
+		if transport != p.transport {
+			t.Errorf("%s client does not use the pool transport", provider)
+		}
+	}
+}
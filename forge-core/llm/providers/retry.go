@@ -0,0 +1,102 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultMaxRetries is used when llm.ClientConfig.MaxRetries is zero. A
+// negative MaxRetries disables retries.
+const defaultMaxRetries = 3
+
+// Backoff bounds for retries without a Retry-After header. Variables so tests
+// can shorten them.
+var (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// resolveMaxRetries applies the default to a configured retry count.
+func resolveMaxRetries(n int) int {
+	switch {
+	case n == 0:
+		return defaultMaxRetries
+	case n < 0:
+		return 0
+	default:
+		return n
+	}
+}
+
+// retryableStatus reports whether a response status is a transient failure
+// that is safe to retry.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// doWithRetry sends the request built by newReq, retrying up to maxRetries
+// times on 429, 500, 502 and 503 responses. It waits for the Retry-After
+// header when present and otherwise backs off exponentially with jitter.
+// The last response is returned when retries are exhausted, so callers
+// report the final error as before. Streaming callers only retry here, on
+// the initial connection; once a 200 arrives the body is theirs.
+func doWithRetry(ctx context.Context, client *http.Client, maxRetries int, newReq func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if attempt >= maxRetries || !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := retryDelay(resp.Header.Get("Retry-After"), attempt)
+		// Drain so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns how long to wait before retry attempt+1. A Retry-After
+// header in seconds or as an HTTP date takes precedence; otherwise the delay
+// doubles per attempt up to retryMaxDelay, with up to 50% random jitter.
+func retryDelay(retryAfter string, attempt int) time.Duration {
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(retryAfter); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+
+	delay := retryBaseDelay << attempt
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -0,0 +1,158 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// flakyServer fails the first failures requests with status and the given
+// Retry-After header (omitted when empty), then serves ok.
+func flakyServer(t *testing.T, failures, status int, retryAfter string, ok http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(calls.Add(1)) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, `{"error":"slow down"}`, status)
+			return
+		}
+		ok(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func fastRetries(t *testing.T) {
+	t.Helper()
+	base, max := retryBaseDelay, retryMaxDelay
+	retryBaseDelay, retryMaxDelay = time.Millisecond, 5*time.Millisecond
+	t.Cleanup(func() { retryBaseDelay, retryMaxDelay = base, max })
+}
+
+func openAIOK(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"hello"},"finish_reason":"stop"}]}`) //nolint:errcheck
+}
+
+func TestOpenAIChat_RetriesRateLimit(t *testing.T) {
+	srv, calls := flakyServer(t, 2, http.StatusTooManyRequests, "0", openAIOK)
+
+	c := NewOpenAIClient(llm.ClientConfig{BaseURL: srv.URL, Model: "gpt-4o"})
+	resp, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Message.Content != "hello" {
+		t.Errorf("content = %q", resp.Message.Content)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("requests = %d, want 3", calls.Load())
+	}
+}
+
+func TestAnthropicChat_RetriesRateLimit(t *testing.T) {
+	srv, calls := flakyServer(t, 2, http.StatusTooManyRequests, "0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"content":[{"type":"text","text":"hello"}],"stop_reason":"end_turn"}`) //nolint:errcheck
+	})
+
+	c := NewAnthropicClient(llm.ClientConfig{BaseURL: srv.URL, Model: "claude-sonnet-4-20250514"})
+	resp, err := c.Chat(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Message.Content != "hello" {
+		t.Errorf("content = %q", resp.Message.Content)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("requests = %d, want 3", calls.Load())
+	}
+}
+
+func TestOpenAIChatStream_RetriesInitialConnection(t *testing.T) {
+	fastRetries(t)
+	srv, calls := flakyServer(t, 2, http.StatusServiceUnavailable, "", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"hel\"}}]}\n\n") //nolint:errcheck
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n")  //nolint:errcheck
+		fmt.Fprint(w, "data: [DONE]\n\n")                                            //nolint:errcheck
+	})
+
+	c := NewOpenAIClient(llm.ClientConfig{BaseURL: srv.URL, Model: "gpt-4o"})
+	ch, err := c.ChatStream(context.Background(), &llm.ChatRequest{Messages: []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	var sb strings.Builder
+	for d := range ch {
+		sb.WriteString(d.Content)
+	}
+	if sb.String() != "hello" {
+		t.Errorf("streamed = %q", sb.String())
+	}
+	if calls.Load() != 3 {
+		t.Errorf("requests = %d, want 3", calls.Load())
+	}
+}
+
+func TestChat_RetriesExhausted(t *testing.T) {
+	srv, calls := flakyServer(t, 10, http.StatusTooManyRequests, "0", openAIOK)
+
+	c := NewOpenAIClient(llm.ClientConfig{BaseURL: srv.URL, Model: "gpt-4o", MaxRetries: 2})
+	_, err := c.Chat(context.Background(), &llm.ChatRequest{})
+	if err == nil || !strings.Contains(err.Error(), "status 429") {
+		t.Fatalf("err = %v, want final 429 error", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("requests = %d, want 3 (1 + 2 retries)", calls.Load())
+	}
+}
+
+func TestChat_NoRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		maxRetries int
+	}{
+		{"non-retryable status", http.StatusBadRequest, 3},
+		{"retries disabled", http.StatusTooManyRequests, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyServer(t, 1, tt.status, "0", openAIOK)
+			c := NewOpenAIClient(llm.ClientConfig{BaseURL: srv.URL, Model: "gpt-4o", MaxRetries: tt.maxRetries})
+			if _, err := c.Chat(context.Background(), &llm.ChatRequest{}); err == nil {
+				t.Fatal("expected error")
+			}
+			if calls.Load() != 1 {
+				t.Errorf("requests = %d, want 1", calls.Load())
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	if d := retryDelay("7", 0); d != 7*time.Second {
+		t.Errorf("Retry-After seconds: %v", d)
+	}
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if d := retryDelay(date, 0); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("Retry-After date: %v", d)
+	}
+	for attempt := 0; attempt < 3; attempt++ {
+		full := retryBaseDelay << attempt
+		if d := retryDelay("", attempt); d < full/2 || d > full {
+			t.Errorf("attempt %d backoff = %v, want in [%v, %v]", attempt, d, full/2, full)
+		}
+	}
+	if d := retryDelay("", 40); d > retryMaxDelay {
+		t.Errorf("backoff %v exceeds cap %v", d, retryMaxDelay)
+	}
+}
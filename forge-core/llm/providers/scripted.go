@@ -0,0 +1,89 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// ScriptedClient implements llm.Client by replaying a fixed sequence of
+// responses, one per call, regardless of the request. It makes agent runs
+// reproducible without a live model, as in forge test fixtures.
+type ScriptedClient struct {
+	mu        sync.Mutex
+	responses []*llm.ChatResponse
+	requests  []*llm.ChatRequest
+}
+
+// NewScriptedClient creates a client returning responses in order. A call
+// after the script is exhausted fails.
+func NewScriptedClient(responses []*llm.ChatResponse) *ScriptedClient {
+	return &ScriptedClient{responses: responses}
+}
+
+// ModelID returns a fixed identifier, so the default capabilities apply.
+func (c *ScriptedClient) ModelID() string { return "scripted" }
+
+// Chat returns the next scripted response.
+func (c *ScriptedClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	n := len(c.requests)
+	if n > len(c.responses) {
+		return nil, fmt.Errorf("scripted client: no response left for call %d (script has %d)", n, len(c.responses))
+	}
+	resp := *c.responses[n-1]
+	if resp.FinishReason == "" {
+		resp.FinishReason = "stop"
+		if len(resp.Message.ToolCalls) > 0 {
+			resp.FinishReason = "tool_calls"
+		}
+	}
+	if resp.Message.Role == "" {
+		resp.Message.Role = llm.RoleAssistant
+	}
+	if resp.Model == "" {
+		resp.Model = c.ModelID()
+	}
+	return &resp, nil
+}
+
+// ChatStream delivers the next scripted response as a single delta.
+func (c *ScriptedClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	resp, err := c.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan llm.StreamDelta, 1)
+	ch <- llm.StreamDelta{
+		Content:      resp.Message.Content,
+		ToolCalls:    resp.Message.ToolCalls,
+		FinishReason: resp.FinishReason,
+		Usage:        &resp.Usage,
+		Done:         true,
+	}
+	close(ch)
+	return ch, nil
+}
+
+// Requests returns the requests received so far, in order.
+func (c *ScriptedClient) Requests() []*llm.ChatRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*llm.ChatRequest(nil), c.requests...)
+}
+
+// Remaining reports how many scripted responses have not been used.
+func (c *ScriptedClient) Remaining() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return max(len(c.responses)-len(c.requests), 0)
+}
+
+var _ llm.Client = (*ScriptedClient)(nil)
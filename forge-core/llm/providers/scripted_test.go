@@ -0,0 +1,43 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestScriptedClient(t *testing.T) {
+	c := NewScriptedClient([]*llm.ChatResponse{
+		{Message: llm.ChatMessage{ToolCalls: []llm.ToolCall{{ID: "1", Function: llm.FunctionCall{Name: "search"}}}}},
+		{Message: llm.ChatMessage{Content: "done"}},
+	})
+	ctx := context.Background()
+
+	resp, err := c.Chat(ctx, &llm.ChatRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.FinishReason != "tool_calls" || resp.Message.Role != llm.RoleAssistant {
+		t.Errorf("first response = %+v, want tool_calls from the assistant", resp)
+	}
+	if c.Remaining() != 1 {
+		t.Errorf("Remaining() = %d, want 1", c.Remaining())
+	}
+
+	ch, err := c.ChatStream(ctx, &llm.ChatRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delta := <-ch
+	if delta.Content != "done" || delta.FinishReason != "stop" || !delta.Done {
+		t.Errorf("stream delta = %+v", delta)
+	}
+
+	if _, err := c.Chat(ctx, &llm.ChatRequest{}); err == nil {
+		t.Error("expected error once the script is exhausted")
+	}
+	if n := len(c.Requests()); n != 3 {
+		t.Errorf("Requests() has %d entries, want 3", n)
+	}
+}
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// requestTimeout applies a per-request timeout override. With
+// req.TimeoutSecs set it derives a context with that deadline and returns a
+// copy of client without its own timeout, so the override wins whether it
+// is shorter or longer. Otherwise ctx and client are returned unchanged.
+func requestTimeout(ctx context.Context, client *http.Client, req *llm.ChatRequest) (context.Context, *http.Client, context.CancelFunc) {
+	if req.TimeoutSecs <= 0 {
+		return ctx, client, func() {}
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(req.TimeoutSecs)*time.Second)
+	c := *client
+	c.Timeout = 0
+	return ctx, &c, cancel
+}
+
+// streamBody runs read over a streaming response body in the background.
+// Cancelling ctx aborts the in-flight read, and the stream then ends with a
+// Done delta carrying the context error, whether or not read sent its own.
+// The body is closed and cancel called when reading stops.
+func streamBody(ctx context.Context, cancel context.CancelFunc, body io.ReadCloser, read func(io.Reader, chan<- llm.StreamDelta)) <-chan llm.StreamDelta {
+	inner := make(chan llm.StreamDelta)
+	go func() {
+		defer close(inner)
+		defer func() { _ = body.Close() }()
+		read(body, inner)
+	}()
+
+	ch := make(chan llm.StreamDelta, 32)
+	go func() {
+		defer close(ch)
+		defer cancel()
+		done := false
+		for d := range inner {
+			if d.Done {
+				d.Err = ctx.Err()
+				done = true
+			}
+			ch <- d
+		}
+		if err := ctx.Err(); err != nil && !done {
+			ch <- llm.StreamDelta{Done: true, Err: err}
+		}
+	}()
+	return ch
+}
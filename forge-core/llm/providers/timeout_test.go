@@ -0,0 +1,106 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// stallingServer runs before, if set, and then holds the request open until
+// the client gives up.
+func stallingServer(t *testing.T, before func(w http.ResponseWriter)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Consume the body so the server notices when the client hangs up
+		_, _ = io.Copy(io.Discard, r.Body)
+		if before != nil {
+			before(w)
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestChat_RequestTimeoutOverride(t *testing.T) {
+	srv := stallingServer(t, nil)
+	c := NewOpenAIClient(llm.ClientConfig{APIKey: "k", BaseURL: srv.URL, Model: "gpt-4o"})
+
+	start := time.Now()
+	_, err := c.Chat(context.Background(), &llm.ChatRequest{
+		Messages:    []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}},
+		TimeoutSecs: 1,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %v, want about 1s", elapsed)
+	}
+}
+
+func TestChatStream_RequestTimeoutOverride(t *testing.T) {
+	srv := stallingServer(t, func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"partial\"}}]}\n\n") //nolint:errcheck
+		w.(http.Flusher).Flush()
+	})
+	c := NewOpenAIClient(llm.ClientConfig{APIKey: "k", BaseURL: srv.URL, Model: "gpt-4o"})
+
+	start := time.Now()
+	ch, err := c.ChatStream(context.Background(), &llm.ChatRequest{
+		Messages:    []llm.ChatMessage{{Role: llm.RoleUser, Content: "hi"}},
+		TimeoutSecs: 1,
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+
+	var deltas []llm.StreamDelta
+	for d := range ch {
+		deltas = append(deltas, d)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("stream took %v, want about 1s", elapsed)
+	}
+	if len(deltas) != 2 || deltas[0].Content != "partial" {
+		t.Fatalf("deltas = %+v, want the partial chunk then a final delta", deltas)
+	}
+	last := deltas[1]
+	if !last.Done || !errors.Is(last.Err, context.DeadlineExceeded) {
+		t.Errorf("final delta = %+v, want Done with deadline exceeded", last)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	base := &http.Client{Timeout: time.Second}
+
+	ctx, client, cancel := requestTimeout(context.Background(), base, &llm.ChatRequest{})
+	cancel()
+	if client != base {
+		t.Error("client replaced without an override")
+	}
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("deadline set without an override")
+	}
+
+	// A longer override must not be cut short by the client timeout
+	ctx, client, cancel = requestTimeout(context.Background(), base, &llm.ChatRequest{TimeoutSecs: 300})
+	defer cancel()
+	if client.Timeout != 0 || base.Timeout != time.Second {
+		t.Errorf("client timeout = %v (base %v), want 0 (base 1s)", client.Timeout, base.Timeout)
+	}
+	if d, ok := ctx.Deadline(); !ok || time.Until(d) < 299*time.Second {
+		t.Errorf("deadline = %v, want about 300s from now", d)
+	}
+}
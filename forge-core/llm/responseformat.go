@@ -0,0 +1,75 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Response format types.
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// ResponseFormat constrains the final assistant message. Type is "text"
+// (the default), "json_object" for any JSON object, or "json_schema" for
+// JSON matching Schema.
+type ResponseFormat struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name,omitempty"` // schema name; default "response"
+	Schema json.RawMessage `json:"schema,omitempty"`
+}
+
+// IsJSON reports whether f requires a JSON response.
+func (f *ResponseFormat) IsJSON() bool {
+	return f != nil && (f.Type == ResponseFormatJSONObject || f.Type == ResponseFormatJSONSchema)
+}
+
+// SchemaName returns the name sent to providers that label the schema.
+func (f *ResponseFormat) SchemaName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return "response"
+}
+
+// ObjectSchema returns the schema a JSON response must satisfy: Schema for
+// json_schema, and a bare object schema for json_object.
+func (f *ResponseFormat) ObjectSchema() json.RawMessage {
+	if f.Type == ResponseFormatJSONSchema && len(f.Schema) > 0 {
+		return f.Schema
+	}
+	return json.RawMessage(`{"type":"object"}`)
+}
+
+// Validate checks content against the format. Text formats accept anything.
+func (f *ResponseFormat) Validate(content string) error {
+	if !f.IsJSON() {
+		return nil
+	}
+	content = strings.TrimSpace(content)
+	if !json.Valid([]byte(content)) {
+		return fmt.Errorf("response is not valid JSON")
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(f.ObjectSchema()))
+	if err != nil {
+		return fmt.Errorf("compiling response schema: %w", err)
+	}
+	result, err := schema.Validate(gojsonschema.NewStringLoader(content))
+	if err != nil {
+		return fmt.Errorf("validating response: %w", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+	errs := make([]string, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		errs = append(errs, e.String())
+	}
+	return fmt.Errorf("response does not match schema: %s", strings.Join(errs, "; "))
+}
@@ -0,0 +1,43 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestResponseFormat_Validate(t *testing.T) {
+	schema := &ResponseFormat{
+		Type:   ResponseFormatJSONSchema,
+		Schema: json.RawMessage(`{"type":"object","properties":{"score":{"type":"integer"}},"required":["score"]}`),
+	}
+	tests := []struct {
+		name    string
+		format  *ResponseFormat
+		content string
+		wantErr string
+	}{
+		{"nil format accepts text", nil, "hello", ""},
+		{"text accepts text", &ResponseFormat{Type: ResponseFormatText}, "hello", ""},
+		{"json_object accepts object", &ResponseFormat{Type: ResponseFormatJSONObject}, ` {"a":1} `, ""},
+		{"json_object rejects array", &ResponseFormat{Type: ResponseFormatJSONObject}, `[1]`, "does not match schema"},
+		{"json_object rejects prose", &ResponseFormat{Type: ResponseFormatJSONObject}, "Sure! {\"a\":1}", "not valid JSON"},
+		{"schema match", schema, `{"score":3}`, ""},
+		{"schema mismatch", schema, `{"score":"high"}`, "score"},
+		{"schema missing field", schema, `{}`, "score is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.format.Validate(tt.content)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package llm
+
+// TokenCounter estimates the prompt tokens a request will use. Plug in an
+// exact tokenizer for a model family where the estimate matters.
+type TokenCounter interface {
+	CountTokens(req *ChatRequest) int
+}
+
+// HeuristicCounter estimates tokens as one per four characters of message
+// content, tool calls, and tool definitions, plus a small overhead per
+// message. It errs slightly high for English text.
+type HeuristicCounter struct{}
+
+// messageOverheadTokens covers role markers and separators per message.
+const messageOverheadTokens = 4
+
+// CountTokens returns the estimated prompt size of req.
+func (HeuristicCounter) CountTokens(req *ChatRequest) int {
+	chars := 0
+	tokens := 0
+	for _, m := range req.Messages {
+		tokens += messageOverheadTokens
+		chars += len(m.Content)
+		for _, tc := range m.ToolCalls {
+			chars += len(tc.Function.Name) + len(tc.Function.Arguments)
+		}
+	}
+	for _, t := range req.Tools {
+		chars += len(t.Function.Name) + len(t.Function.Description) + len(t.Function.Parameters)
+	}
+	return tokens + (chars+3)/4
+}
@@ -0,0 +1,30 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHeuristicCounter(t *testing.T) {
+	var c HeuristicCounter
+	if got := c.CountTokens(&ChatRequest{}); got != 0 {
+		t.Errorf("empty request = %d tokens, want 0", got)
+	}
+
+	req := &ChatRequest{Messages: []ChatMessage{{Role: RoleUser, Content: strings.Repeat("a", 400)}}}
+	if got := c.CountTokens(req); got != 104 {
+		t.Errorf("400 chars = %d tokens, want 104", got)
+	}
+
+	// Tool calls and definitions count toward the prompt
+	req.Messages = append(req.Messages, ChatMessage{Role: RoleAssistant, ToolCalls: []ToolCall{
+		{Function: FunctionCall{Name: "search", Arguments: `{"q":"forge agents"}`}},
+	}})
+	req.Tools = []ToolDefinition{{Function: FunctionSchema{
+		Name: "search", Description: "Search the web", Parameters: json.RawMessage(`{"type":"object"}`),
+	}}}
+	if got := c.CountTokens(req); got <= 108 {
+		t.Errorf("with tools = %d tokens, want more than 108", got)
+	}
+}
@@ -20,6 +20,41 @@ type ChatMessage struct {
 	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string     `json:"tool_call_id,omitempty"`
 	Name       string     `json:"name,omitempty"`
+	IsError    bool       `json:"is_error,omitempty"` // tool result reports a failure
+
+	// Images accompany Content in a user message to a vision-capable model.
+	Images []ImageContent `json:"images,omitempty"`
+}
+
+// ImageContent is an image sent to the model, either by URL or inline.
+// Exactly one of URL or Data should be set.
+type ImageContent struct {
+	URL       string `json:"url,omitempty"`
+	MediaType string `json:"media_type,omitempty"` // e.g. "image/png"; required with Data
+	Data      string `json:"data,omitempty"`       // base64-encoded image bytes
+}
+
+// DataURL returns the image as a URL: its URL, or a data: URL of its
+// inline bytes.
+func (img ImageContent) DataURL() string {
+	if img.URL != "" {
+		return img.URL
+	}
+	return "data:" + img.MediaType + ";base64," + img.Data
+}
+
+// NewToolResultMessage builds the canonical message returning the result of
+// call to the model. Clients convert it to their provider's native shape: a
+// "tool" role message for OpenAI, a tool_result block in a user turn for
+// Anthropic.
+func NewToolResultMessage(call ToolCall, content string, isError bool) ChatMessage {
+	return ChatMessage{
+		Role:       RoleTool,
+		Content:    content,
+		ToolCallID: call.ID,
+		Name:       call.Function.Name,
+		IsError:    isError,
+	}
 }
 
 // ToolCall represents an LLM request to invoke a tool.
@@ -54,13 +89,27 @@ type ChatRequest struct {
 	Messages    []ChatMessage    `json:"messages"`
 	Tools       []ToolDefinition `json:"tools,omitempty"`
 	Temperature *float64         `json:"temperature,omitempty"`
+	TopP        *float64         `json:"top_p,omitempty"`
+	Stop        []string         `json:"stop,omitempty"`
 	MaxTokens   int              `json:"max_tokens,omitempty"`
 	Stream      bool             `json:"stream,omitempty"`
+
+	// CacheSystemPrompt asks providers with explicit prompt caching
+	// (Anthropic) to cache the system prompt and tool definitions.
+	CacheSystemPrompt bool `json:"cache_system_prompt,omitempty"`
+
+	// ResponseFormat constrains the final message to JSON; nil means text.
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+
+	// TimeoutSecs overrides the client's timeout for this request when
+	// non-zero. It can be shorter or longer than the client default.
+	TimeoutSecs int `json:"timeout_secs,omitempty"`
 }
 
 // ChatResponse is a provider-agnostic chat completion response.
 type ChatResponse struct {
 	ID           string      `json:"id"`
+	Model        string      `json:"model,omitempty"` // model that served the call, which may differ from the client's ModelID behind a fallback
 	Message      ChatMessage `json:"message"`
 	Usage        UsageInfo   `json:"usage"`
 	FinishReason string      `json:"finish_reason"`
@@ -68,11 +117,13 @@ type ChatResponse struct {
 
 // StreamDelta represents a single chunk in a streaming response.
 type StreamDelta struct {
+	Model        string     `json:"model,omitempty"` // model serving the stream, when it differs from the client's ModelID
 	Content      string     `json:"content,omitempty"`
 	ToolCalls    []ToolCall `json:"tool_calls,omitempty"`
 	FinishReason string     `json:"finish_reason,omitempty"`
 	Done         bool       `json:"done,omitempty"`
 	Usage        *UsageInfo `json:"usage,omitempty"`
+	Err          error      `json:"-"` // set when the stream was cut short by its context or by a provider error
 }
 
 // UsageInfo contains token usage information.
@@ -80,4 +131,9 @@ type UsageInfo struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+
+	// Prompt tokens written to and read from the provider's prompt cache.
+	// Both are included in PromptTokens.
+	CacheCreationTokens int `json:"cache_creation_tokens,omitempty"`
+	CacheReadTokens     int `json:"cache_read_tokens,omitempty"`
 }
@@ -0,0 +1,137 @@
+// Package memory provides retrieval over text an agent has chosen to
+// remember, using embeddings when available and keyword matching otherwise.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// Result is a stored entry matched by a search.
+type Result struct {
+	ID    string  `json:"id"`
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+}
+
+type entry struct {
+	id     string
+	text   string
+	vector []float32
+}
+
+// Store holds remembered entries in memory and ranks them against queries.
+// With an embedder, entries are ranked by cosine similarity of their
+// embeddings; without one, by the fraction of query keywords they contain.
+type Store struct {
+	mu       sync.RWMutex
+	embedder llm.Embedder
+	entries  []entry
+}
+
+// NewStore creates a store. embedder may be nil for keyword search.
+func NewStore(embedder llm.Embedder) *Store {
+	return &Store{embedder: embedder}
+}
+
+// Add stores text under id, replacing any existing entry with the same id.
+func (s *Store) Add(ctx context.Context, id, text string) error {
+	e := entry{id: id, text: text}
+	if s.embedder != nil {
+		vectors, err := s.embedder.Embed(ctx, []string{text})
+		if err != nil {
+			return fmt.Errorf("embedding memory: %w", err)
+		}
+		if len(vectors) != 1 {
+			return fmt.Errorf("embedding memory: got %d vectors, want 1", len(vectors))
+		}
+		e.vector = vectors[0]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.entries {
+		if s.entries[i].id == id {
+			s.entries[i] = e
+			return nil
+		}
+	}
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+// Search returns up to limit entries most relevant to query, best first.
+// Entries with no relevance are omitted. A limit of 0 returns all matches.
+func (s *Store) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	var score func(entry) float64
+	if s.embedder != nil {
+		vectors, err := s.embedder.Embed(ctx, []string{query})
+		if err != nil {
+			return nil, fmt.Errorf("embedding query: %w", err)
+		}
+		if len(vectors) != 1 {
+			return nil, fmt.Errorf("embedding query: got %d vectors, want 1", len(vectors))
+		}
+		qv := vectors[0]
+		score = func(e entry) float64 { return llm.CosineSimilarity(qv, e.vector) }
+	} else {
+		terms := keywords(query)
+		score = func(e entry) float64 { return keywordScore(terms, e.text) }
+	}
+
+	s.mu.RLock()
+	var results []Result
+	for _, e := range s.entries {
+		if sc := score(e); sc > 0 {
+			results = append(results, Result{ID: e.id, Text: e.text, Score: sc})
+		}
+	}
+	s.mu.RUnlock()
+
+	// Stable sort keeps insertion order among equal scores
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// keywords splits text into distinct lowercase words.
+func keywords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]bool, len(fields))
+	var out []string
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// keywordScore is the fraction of terms that appear as words in text.
+func keywordScore(terms []string, text string) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+	words := make(map[string]bool)
+	for _, w := range keywords(text) {
+		words[w] = true
+	}
+	hits := 0
+	for _, t := range terms {
+		if words[t] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(terms))
+}
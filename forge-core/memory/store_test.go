@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"testing"
+)
+
+// stubEmbedder returns fixed vectors keyed by text.
+type stubEmbedder struct {
+	vectors map[string][]float32
+}
+
+func (e *stubEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = e.vectors[t]
+	}
+	return out, nil
+}
+
+func (e *stubEmbedder) ModelID() string { return "stub" }
+
+func TestStore_EmbeddingRanking(t *testing.T) {
+	emb := &stubEmbedder{vectors: map[string][]float32{
+		"The user prefers window seats":        {0.9, 0.1, 0},
+		"Quarterly report is due Friday":       {0, 0.2, 0.9},
+		"User is vegetarian":                   {0.6, 0.7, 0.1},
+		"Where does the customer like to sit?": {1, 0, 0},
+	}}
+	s := NewStore(emb)
+	ctx := context.Background()
+	for id, text := range map[string]string{
+		"seat":   "The user prefers window seats",
+		"report": "Quarterly report is due Friday",
+		"diet":   "User is vegetarian",
+	} {
+		if err := s.Add(ctx, id, text); err != nil {
+			t.Fatalf("Add(%s): %v", id, err)
+		}
+	}
+
+	// No keyword overlap with the best entry; only embeddings can rank it first
+	results, err := s.Search(ctx, "Where does the customer like to sit?", 2)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].ID != "seat" || results[1].ID != "diet" {
+		t.Errorf("ranking = [%s %s], want [seat diet]", results[0].ID, results[1].ID)
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("scores not descending: %v, %v", results[0].Score, results[1].Score)
+	}
+}
+
+func TestStore_KeywordFallback(t *testing.T) {
+	s := NewStore(nil)
+	ctx := context.Background()
+	s.Add(ctx, "a", "Deploy the billing service on Friday")       //nolint:errcheck
+	s.Add(ctx, "b", "Billing dashboards live in Grafana")         //nolint:errcheck
+	s.Add(ctx, "c", "Team lunch is on Thursday")                  //nolint:errcheck
+	s.Add(ctx, "b", "Billing service dashboards live in Grafana") //nolint:errcheck
+
+	results, err := s.Search(ctx, "billing service", 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+	if results[0].ID != "a" || results[1].ID != "b" {
+		t.Errorf("ranking = [%s %s], want [a b] (ties keep insertion order)", results[0].ID, results[1].ID)
+	}
+	if results[1].Text != "Billing service dashboards live in Grafana" {
+		t.Errorf("re-adding an id should replace its text, got %q", results[1].Text)
+	}
+}
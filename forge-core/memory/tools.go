@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// defaultSearchLimit is how many results memory_search returns when the
+// model does not ask for a number.
+const defaultSearchLimit = 5
+
+// NewTools returns the memory_save and memory_search tools, which let the
+// model remember text in s and retrieve it later.
+func NewTools(s *Store) []tools.Tool {
+	return []tools.Tool{&saveTool{store: s}, &searchTool{store: s}}
+}
+
+type saveTool struct{ store *Store }
+
+type saveInput struct {
+	ID   string `json:"id,omitempty"`
+	Text string `json:"text"`
+}
+
+func (t *saveTool) Name() string { return "memory_save" }
+func (t *saveTool) Description() string {
+	return "Remember a piece of text so it can be found later with memory_search"
+}
+func (t *saveTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *saveTool) Mutation() tools.Mutation { return tools.MutationMutating }
+
+func (t *saveTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"text": {"type": "string", "description": "Text to remember"},
+			"id": {"type": "string", "description": "Key to store the text under; saving again with the same id replaces it. Default: derived from the text"}
+		},
+		"required": ["text"]
+	}`)
+}
+
+func (t *saveTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input saveInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	if input.Text == "" {
+		return "", fmt.Errorf("text is required")
+	}
+	id := input.ID
+	if id == "" {
+		sum := sha256.Sum256([]byte(input.Text))
+		id = hex.EncodeToString(sum[:8])
+	}
+	if err := t.store.Add(ctx, id, input.Text); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`{"id":%q}`, id), nil
+}
+
+type searchTool struct{ store *Store }
+
+type searchInput struct {
+	Query string `json:"query"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+func (t *searchTool) Name() string { return "memory_search" }
+func (t *searchTool) Description() string {
+	return "Find remembered text relevant to a query, best match first"
+}
+func (t *searchTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *searchTool) Mutation() tools.Mutation { return tools.MutationReadOnly }
+
+func (t *searchTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string", "description": "What to look for"},
+			"limit": {"type": "integer", "description": "Maximum number of results. Default: 5"}
+		},
+		"required": ["query"]
+	}`)
+}
+
+func (t *searchTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input searchInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	results, err := t.store.Search(ctx, input.Query, limit)
+	if err != nil {
+		return "", err
+	}
+	if results == nil {
+		results = []Result{}
+	}
+	out, err := json.Marshal(results)
+	if err != nil {
+		return "", fmt.Errorf("encoding results: %w", err)
+	}
+	return string(out), nil
+}
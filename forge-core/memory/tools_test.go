@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTools_SaveAndSearch(t *testing.T) {
+	ts := NewTools(NewStore(nil))
+	save, search := ts[0], ts[1]
+	ctx := context.Background()
+
+	if _, err := save.Execute(ctx, json.RawMessage(`{"id":"diet","text":"User eats fish"}`)); err != nil {
+		t.Fatalf("memory_save: %v", err)
+	}
+	// Saving under the same id replaces the entry
+	if _, err := save.Execute(ctx, json.RawMessage(`{"id":"diet","text":"User is vegetarian"}`)); err != nil {
+		t.Fatalf("memory_save: %v", err)
+	}
+	out, err := save.Execute(ctx, json.RawMessage(`{"text":"Report is due Friday"}`))
+	if err != nil {
+		t.Fatalf("memory_save: %v", err)
+	}
+	if !strings.Contains(out, `"id":"`) {
+		t.Errorf("memory_save without id = %s, want a derived id", out)
+	}
+
+	out, err = search.Execute(ctx, json.RawMessage(`{"query":"is the user vegetarian"}`))
+	if err != nil {
+		t.Fatalf("memory_search: %v", err)
+	}
+	var results []Result
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("decoding %s: %v", out, err)
+	}
+	if len(results) != 2 || results[0].ID != "diet" || results[0].Text != "User is vegetarian" {
+		t.Errorf("memory_search = %s, want the replaced diet entry first", out)
+	}
+
+	if _, err := save.Execute(ctx, json.RawMessage(`{"text":""}`)); err == nil {
+		t.Error("memory_save with empty text: want error")
+	}
+}
@@ -1,5 +1,6 @@
 // Package registry provides an embedded skill registry for the forge init wizard.
 // Skills are embedded at compile time and can be vendored into new projects.
+// A Remote serves skills from a hosted registry with the same layout.
 package registry
 
 import (
@@ -0,0 +1,222 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Source is a skill registry: the bundled one or a hosted Remote.
+type Source interface {
+	LoadIndex() ([]SkillInfo, error)
+	GetSkillByName(name string) *SkillInfo
+	LoadSkillFile(name string) ([]byte, error)
+	HasSkillScript(name string) bool
+	LoadSkillScript(name string) ([]byte, error)
+}
+
+// Bundled is the registry embedded in the forge binary.
+var Bundled Source = bundled{}
+
+type bundled struct{}
+
+func (bundled) LoadIndex() ([]SkillInfo, error)             { return LoadIndex() }
+func (bundled) GetSkillByName(name string) *SkillInfo       { return GetSkillByName(name) }
+func (bundled) LoadSkillFile(name string) ([]byte, error)   { return LoadSkillFile(name) }
+func (bundled) HasSkillScript(name string) bool             { return HasSkillScript(name) }
+func (bundled) LoadSkillScript(name string) ([]byte, error) { return LoadSkillScript(name) }
+
+// DefaultRemoteTTL is how long a Remote serves cached files before
+// fetching them again.
+const DefaultRemoteTTL = time.Hour
+
+var skillNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// errNotFound is returned, wrapped, when the registry has no such file.
+var errNotFound = errors.New("404 Not Found")
+
+// Remote is a registry hosted over HTTP with the same layout as the bundled
+// one: <URL>/index.json, <URL>/skills/<skill_file>, and, for skills that
+// have one, <URL>/scripts/<name>.sh. Fetched files are cached under
+// CacheDir for TTL. When the registry cannot be reached, a stale cached
+// copy is used if there is one, and otherwise the bundled registry.
+type Remote struct {
+	URL      string
+	CacheDir string
+	TTL      time.Duration
+	Client   *http.Client
+
+	// OnError, if set, is called with the fetch error whenever a fallback
+	// is used.
+	OnError func(error)
+}
+
+// NewRemote creates a Remote for the registry at url, caching under
+// cacheDir with the default TTL.
+func NewRemote(url, cacheDir string) *Remote {
+	return &Remote{
+		URL:      strings.TrimSuffix(url, "/"),
+		CacheDir: cacheDir,
+		TTL:      DefaultRemoteTTL,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// LoadIndex returns the remote index.
+func (r *Remote) LoadIndex() ([]SkillInfo, error) {
+	data, err := r.fetch("index.json")
+	if err != nil {
+		return LoadIndex()
+	}
+	var skills []SkillInfo
+	if err := json.Unmarshal(data, &skills); err != nil {
+		return nil, fmt.Errorf("parsing registry index %s: %w", r.URL, err)
+	}
+	return skills, nil
+}
+
+// GetSkillByName returns the SkillInfo for a given skill name, or nil if not found.
+func (r *Remote) GetSkillByName(name string) *SkillInfo {
+	skills, err := r.LoadIndex()
+	if err != nil {
+		return nil
+	}
+	for i := range skills {
+		if skills[i].Name == name {
+			return &skills[i]
+		}
+	}
+	return nil
+}
+
+// LoadSkillFile returns the markdown file of the named skill.
+func (r *Remote) LoadSkillFile(name string) ([]byte, error) {
+	file := name + ".md"
+	if info := r.GetSkillByName(name); info != nil && info.SkillFile != "" {
+		file = info.SkillFile
+	}
+	if !skillNamePattern.MatchString(strings.TrimSuffix(file, ".md")) || !strings.HasSuffix(file, ".md") {
+		return nil, fmt.Errorf("invalid skill file %q for skill %q", file, name)
+	}
+	data, err := r.fetch("skills/" + file)
+	if err != nil {
+		return LoadSkillFile(name)
+	}
+	return data, nil
+}
+
+// HasSkillScript checks if the skill has a script; see LoadSkillScript.
+func (r *Remote) HasSkillScript(name string) bool {
+	_, err := r.LoadSkillScript(name)
+	return err == nil
+}
+
+// LoadSkillScript returns the script of the named skill. A skill in the
+// remote index gets only the remote's script, never a bundled one of the
+// same name; a missing script is not reported to OnError. Skills served
+// from the bundled registry, because the remote does not list them or
+// cannot be reached, get the bundled script.
+func (r *Remote) LoadSkillScript(name string) ([]byte, error) {
+	if !skillNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid skill name %q", name)
+	}
+	if !r.listed(name) {
+		return LoadSkillScript(name)
+	}
+	return r.fetchFile("scripts/"+name+".sh", true)
+}
+
+// listed reports whether the remote index, fresh or cached, lists the
+// named skill.
+func (r *Remote) listed(name string) bool {
+	data, err := r.fetch("index.json")
+	if err != nil {
+		return false
+	}
+	var skills []SkillInfo
+	if err := json.Unmarshal(data, &skills); err != nil {
+		return false
+	}
+	for _, s := range skills {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch returns the file at path under the registry URL, from the cache
+// while it is fresh. On failure it falls back to a stale cached copy, and
+// returns the error only when there is none.
+func (r *Remote) fetch(path string) ([]byte, error) {
+	return r.fetchFile(path, false)
+}
+
+// fetchFile is fetch for a file that may be absent: when optional, a file
+// the registry does not have is returned as an errNotFound error, without
+// calling OnError or using a cached copy.
+func (r *Remote) fetchFile(path string, optional bool) ([]byte, error) {
+	cachePath := filepath.Join(r.cacheRoot(), filepath.FromSlash(path))
+	if fi, err := os.Stat(cachePath); err == nil && time.Since(fi.ModTime()) < r.TTL {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			return data, nil
+		}
+	}
+
+	data, err := r.get(r.URL + "/" + path)
+	if err != nil {
+		if optional && errors.Is(err, errNotFound) {
+			_ = os.Remove(cachePath)
+			return nil, err
+		}
+		if r.OnError != nil {
+			r.OnError(err)
+		}
+		if stale, sErr := os.ReadFile(cachePath); sErr == nil {
+			return stale, nil
+		}
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+		_ = os.WriteFile(cachePath, data, 0o644)
+	}
+	return data, nil
+}
+
+func (r *Remote) get(url string) ([]byte, error) {
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("fetching %s: %w", url, errNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+}
+
+// cacheRoot is the cache directory of this registry URL, so caches of
+// different registries do not mix.
+func (r *Remote) cacheRoot() string {
+	sum := sha256.Sum256([]byte(r.URL))
+	return filepath.Join(r.CacheDir, hex.EncodeToString(sum[:8]))
+}
+
+var _ Source = (*Remote)(nil)
@@ -0,0 +1,159 @@
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeRegistry serves a registry with one private skill and counts requests.
+func fakeRegistry(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`[{"name":"deploy","display_name":"Deploy","description":"Deploy services","skill_file":"deploy.md","required_env":["DEPLOY_TOKEN"],"egress_domains":["deploy.internal"]}]`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/skills/deploy.md", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte("## Tool: deploy_service\n\nDeploy a service.\n")) //nolint:errcheck
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestRemote_FetchesAndCaches(t *testing.T) {
+	srv, hits := fakeRegistry(t)
+	r := NewRemote(srv.URL+"/", t.TempDir())
+
+	info := r.GetSkillByName("deploy")
+	if info == nil || info.RequiredEnv[0] != "DEPLOY_TOKEN" {
+		t.Fatalf("GetSkillByName(deploy) = %+v", info)
+	}
+	data, err := r.LoadSkillFile("deploy")
+	if err != nil || !strings.Contains(string(data), "deploy_service") {
+		t.Fatalf("LoadSkillFile(deploy) = %q, %v", data, err)
+	}
+	fetched := hits.Load()
+
+	// Cache hits: nothing more is fetched
+	if _, err := r.LoadIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.LoadSkillFile("deploy"); err != nil {
+		t.Fatal(err)
+	}
+	if got := hits.Load(); got != fetched {
+		t.Errorf("requests = %d after cache hits, want %d", got, fetched)
+	}
+
+	// Expired entries are fetched again
+	r.TTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if _, err := r.LoadIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if got := hits.Load(); got != fetched+1 {
+		t.Errorf("requests = %d after TTL expiry, want %d", got, fetched+1)
+	}
+}
+
+func TestRemote_FallsBackWhenUnreachable(t *testing.T) {
+	srv, _ := fakeRegistry(t)
+	cacheDir := t.TempDir()
+	var errs []error
+	r := NewRemote(srv.URL, cacheDir)
+	r.OnError = func(err error) { errs = append(errs, err) }
+
+	// A skill missing from the remote falls back to the bundled file
+	data, err := r.LoadSkillFile("github")
+	if err != nil || !strings.Contains(string(data), "github_create_issue") {
+		t.Fatalf("LoadSkillFile(github) = %q, %v; want the bundled file", data, err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "404") {
+		t.Errorf("OnError calls = %v, want one 404", errs)
+	}
+
+	// Stale cache is preferred over the bundled registry
+	if r.GetSkillByName("deploy") == nil {
+		t.Fatal("deploy not fetched")
+	}
+	srv.Close()
+	r.TTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if r.GetSkillByName("deploy") == nil {
+		t.Error("stale cached index not used while the registry is down")
+	}
+
+	// With no cache at all, the bundled registry is used
+	empty := NewRemote(srv.URL, t.TempDir())
+	if empty.GetSkillByName("github") == nil {
+		t.Error("bundled registry not used when the remote is unreachable")
+	}
+	if empty.GetSkillByName("deploy") != nil {
+		t.Error("deploy found without the remote or a cache")
+	}
+}
+
+func TestRemote_SkillScripts(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"deploy","skill_file":"deploy.md"},{"name":"tavily-search","skill_file":"tavily-search.md"}]`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/scripts/deploy.sh", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("#!/bin/sh\necho deploying\n")) //nolint:errcheck
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var errs []error
+	r := NewRemote(srv.URL, t.TempDir())
+	r.OnError = func(err error) { errs = append(errs, err) }
+
+	data, err := r.LoadSkillScript("deploy")
+	if err != nil || !strings.Contains(string(data), "echo deploying") {
+		t.Errorf("LoadSkillScript(deploy) = %q, %v", data, err)
+	}
+	// A remote skill without a script does not get the bundled one of the same name
+	if r.HasSkillScript("tavily-search") {
+		t.Error("remote tavily-search got the bundled script")
+	}
+	if len(errs) != 0 {
+		t.Errorf("OnError calls = %v, want none for a missing script", errs)
+	}
+	if _, err := r.LoadSkillScript("../deploy"); err == nil {
+		t.Error("expected an error for an invalid skill name")
+	}
+
+	// Skills served from the bundled registry get the bundled script
+	srv.Close()
+	down := NewRemote(srv.URL, t.TempDir())
+	if !down.HasSkillScript("tavily-search") {
+		t.Error("bundled tavily-search script not used while the registry is down")
+	}
+}
+
+func TestRemote_RejectsUnsafeSkillFile(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"evil","skill_file":"../../escape.md"}]`)) //nolint:errcheck
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cacheDir := t.TempDir()
+	r := NewRemote(srv.URL, cacheDir)
+	if _, err := r.LoadSkillFile("evil"); err == nil || !strings.Contains(err.Error(), "invalid skill file") {
+		t.Errorf("LoadSkillFile(evil) error = %v, want invalid skill file", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(cacheDir), "escape.md")); err == nil {
+		t.Error("skill file written outside the cache directory")
+	}
+}
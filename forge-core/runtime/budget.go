@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// toolBudget tracks accumulated tool cost within a single Execute run. Tools
+// without a declared cost are never limited.
+type toolBudget struct {
+	limit float64
+	costs map[string]float64
+	spent float64
+}
+
+func newToolBudget(limit float64, costs map[string]float64) *toolBudget {
+	return &toolBudget{limit: limit, costs: costs}
+}
+
+// allows reports whether calling the named tool fits in the remaining budget.
+func (b *toolBudget) allows(name string) bool {
+	cost := b.costs[name]
+	if b.limit <= 0 || cost <= 0 {
+		return true
+	}
+	return b.spent+cost <= b.limit
+}
+
+// charge records a call to the named tool.
+func (b *toolBudget) charge(name string) {
+	b.spent += b.costs[name]
+}
+
+// filter drops tool definitions the budget no longer allows so the model
+// stops being offered them.
+func (b *toolBudget) filter(defs []llm.ToolDefinition) []llm.ToolDefinition {
+	if b.limit <= 0 {
+		return defs
+	}
+	out := make([]llm.ToolDefinition, 0, len(defs))
+	for _, d := range defs {
+		if b.allows(d.Function.Name) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// exhaustedMessage is the tool result returned in place of a blocked call.
+func (b *toolBudget) exhaustedMessage(name string) string {
+	return fmt.Sprintf("Error: tool %s was not executed because the tool budget is exhausted (spent %g of %g). Answer using the information gathered so far.", name, b.spent, b.limit)
+}
@@ -0,0 +1,82 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// inputField is a tool input named in a clarifying question.
+type inputField struct {
+	name        string
+	description string
+}
+
+// missingInputs returns the fields schema requires that args leaves absent,
+// null, or empty, in the schema's order. Arguments that are not a JSON
+// object are missing every required field.
+func missingInputs(schema json.RawMessage, args string) []inputField {
+	var s struct {
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Description string `json:"description"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(schema, &s); err != nil || len(s.Required) == 0 {
+		return nil
+	}
+
+	var given map[string]json.RawMessage
+	_ = json.Unmarshal([]byte(args), &given)
+
+	var missing []inputField
+	for _, name := range s.Required {
+		switch strings.TrimSpace(string(given[name])) {
+		case "", "null", `""`:
+			missing = append(missing, inputField{name: name, description: s.Properties[name].Description})
+		}
+	}
+	return missing
+}
+
+// missingInputQuestion returns a reply asking the user for the inputs the
+// first incomplete call is missing, or nil when every call is complete.
+// Calls to tools without a definition are left to the executor.
+func missingInputQuestion(calls []llm.ToolCall, defs []llm.ToolDefinition) *a2a.Message {
+	schemas := make(map[string]json.RawMessage, len(defs))
+	for _, d := range defs {
+		schemas[d.Function.Name] = d.Function.Parameters
+	}
+	for _, tc := range calls {
+		schema, ok := schemas[tc.Function.Name]
+		if !ok {
+			continue
+		}
+		if missing := missingInputs(schema, tc.Function.Arguments); len(missing) > 0 {
+			return clarifyingQuestion(tc.Function.Name, missing)
+		}
+	}
+	return nil
+}
+
+// clarifyingQuestion builds the input-required reply for tool.
+func clarifyingQuestion(tool string, missing []inputField) *a2a.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "To use %s I need a bit more information:\n", tool)
+	for _, f := range missing {
+		if f.description != "" {
+			fmt.Fprintf(&b, "- %s: %s\n", f.name, f.description)
+		} else {
+			fmt.Fprintf(&b, "- %s\n", f.name)
+		}
+	}
+	b.WriteString("Please reply with the missing details.")
+	return &a2a.Message{
+		Role:     a2a.MessageRoleAgent,
+		Parts:    []a2a.Part{a2a.NewTextPart(b.String())},
+		Metadata: map[string]any{a2a.MetadataInputRequired: true},
+	}
+}
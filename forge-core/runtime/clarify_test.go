@@ -0,0 +1,140 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+const weatherSchema = `{
+	"type": "object",
+	"properties": {
+		"city": {"type": "string", "description": "City to report on"},
+		"units": {"type": "string"}
+	},
+	"required": ["city", "units"]
+}`
+
+func TestMissingInputs(t *testing.T) {
+	tests := []struct {
+		args string
+		want []string
+	}{
+		{`{"city":"Paris","units":"metric"}`, nil},
+		{`{"units":"metric"}`, []string{"city"}},
+		{`{"city":null,"units":""}`, []string{"city", "units"}},
+		{`{}`, []string{"city", "units"}},
+		{`not json`, []string{"city", "units"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, f := range missingInputs(json.RawMessage(weatherSchema), tt.args) {
+			got = append(got, f.name)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("missingInputs(%s) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestLLMExecutor_AskForInputs(t *testing.T) {
+	var lastReq *llm.ChatRequest
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			lastReq = req
+			last := req.Messages[len(req.Messages)-1]
+			switch {
+			case last.Role == llm.RoleTool:
+				return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "It is sunny."}, FinishReason: "stop"}, nil
+			case last.Content == "Paris":
+				return &llm.ChatResponse{
+					Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{ID: "c2", Type: "function", Function: llm.FunctionCall{Name: "weather", Arguments: `{"city":"Paris","units":"metric"}`}}}},
+					FinishReason: "tool_calls",
+				}, nil
+			default:
+				return &llm.ChatResponse{
+					Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "weather", Arguments: `{"units":"metric"}`}}}},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+		},
+	}
+	var executedWith []string
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			executedWith = append(executedWith, string(arguments))
+			return "sunny", nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "weather", Parameters: json.RawMessage(weatherSchema)}}},
+	}
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools, AskForInputs: true})
+
+	ask := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("weather please")}}
+	question, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, ask)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !a2a.IsInputRequired(question) {
+		t.Fatalf("reply is not input-required: %+v", question)
+	}
+	if text := question.Parts[0].Text; !strings.Contains(text, "city: City to report on") || strings.Contains(text, "units") {
+		t.Errorf("question = %q, want it to ask for city only", text)
+	}
+	if len(executedWith) != 0 {
+		t.Fatalf("tool ran with missing input: %v", executedWith)
+	}
+
+	// The follow-up carries the question in history; the model retries the call
+	answer := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("Paris")}}
+	task := &a2a.Task{ID: "t", History: []a2a.Message{*ask, *question}}
+	reply, err := exec.Execute(context.Background(), task, answer)
+	if err != nil {
+		t.Fatalf("Execute follow-up: %v", err)
+	}
+	if a2a.IsInputRequired(reply) || reply.Parts[0].Text != "It is sunny." {
+		t.Errorf("follow-up reply = %+v", reply)
+	}
+	if len(executedWith) != 1 || executedWith[0] != `{"city":"Paris","units":"metric"}` {
+		t.Errorf("tool executed with %v", executedWith)
+	}
+	if len(lastReq.Messages) < 3 || !strings.Contains(lastReq.Messages[1].Content, "more information") {
+		t.Errorf("follow-up conversation missing the question: %+v", lastReq.Messages)
+	}
+}
+
+func TestLLMExecutor_MissingInputsWithoutOption(t *testing.T) {
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls == 1 {
+				return &llm.ChatResponse{
+					Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "weather", Arguments: `{}`}}}},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "done"}, FinishReason: "stop"}, nil
+		},
+	}
+	executed := 0
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			executed++
+			return "ok", nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "weather", Parameters: json.RawMessage(weatherSchema)}}},
+	}
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("go")}}
+	reply, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if a2a.IsInputRequired(reply) || executed != 1 {
+		t.Errorf("without AskForInputs the call should run as before; executed %d, reply %+v", executed, reply)
+	}
+}
@@ -7,8 +7,9 @@ import (
 
 // ModelConfig holds the resolved model provider and configuration.
 type ModelConfig struct {
-	Provider string
-	Client   llm.ClientConfig
+	Provider  string
+	Client    llm.ClientConfig
+	Fallbacks []ModelConfig // from model.fallbacks, in order
 }
 
 // ResolveModelConfig resolves the LLM provider and configuration from multiple
@@ -18,6 +19,9 @@ type ModelConfig struct {
 //  2. Environment variables: FORGE_MODEL_PROVIDER, OPENAI_API_KEY, ANTHROPIC_API_KEY, LLM_API_KEY
 //  3. forge.yaml model section
 //
+// Base URLs follow the same order: a provider's *_BASE_URL environment
+// variable wins over model.base_url.
+//
 // Returns nil if no provider could be resolved.
 func ResolveModelConfig(cfg *types.ForgeConfig, envVars map[string]string, providerOverride string) *ModelConfig {
 	mc := &ModelConfig{}
@@ -27,6 +31,9 @@ func ResolveModelConfig(cfg *types.ForgeConfig, envVars map[string]string, provi
 		mc.Provider = cfg.Model.Provider
 		mc.Client.Model = cfg.Model.Name
 	}
+	mc.Client.MaxRetries = cfg.Model.MaxRetries
+	mc.Client.APIStyle = cfg.Model.APIStyle
+	mc.Client.ReasoningEffort = cfg.Model.ReasoningEffort
 
 	// Apply env vars
 	if p := envVars["FORGE_MODEL_PROVIDER"]; p != "" {
@@ -59,39 +66,104 @@ func ResolveModelConfig(cfg *types.ForgeConfig, envVars map[string]string, provi
 		}
 	}
 
-	// Apply base URL overrides
-	if u := envVars["OPENAI_BASE_URL"]; u != "" && mc.Provider == "openai" {
-		mc.Client.BaseURL = u
-	}
-	if u := envVars["ANTHROPIC_BASE_URL"]; u != "" && mc.Provider == "anthropic" {
-		mc.Client.BaseURL = u
+	// Apply base URL overrides. model.base_url only applies while the
+	// provider is still the one it was configured for.
+	if cfg.Model.BaseURL != "" && mc.Provider == cfg.Model.Provider {
+		mc.Client.BaseURL = cfg.Model.BaseURL
 	}
-	if u := envVars["OLLAMA_BASE_URL"]; u != "" && mc.Provider == "ollama" {
-		mc.Client.BaseURL = u
+	if env, ok := baseURLEnvVars[mc.Provider]; ok {
+		if u := envVars[env]; u != "" {
+			mc.Client.BaseURL = u
+		}
 	}
 
+	resolveRegion(mc, envVars)
+
 	// Return nil if no provider could be resolved
 	if mc.Provider == "" {
 		return nil
 	}
+	setDefaultModel(mc)
 
-	// Set default models per provider if not specified
-	if mc.Client.Model == "" {
-		switch mc.Provider {
-		case "openai":
-			mc.Client.Model = "gpt-4o"
-		case "anthropic":
-			mc.Client.Model = "claude-sonnet-4-20250514"
-		case "gemini":
-			mc.Client.Model = "gemini-2.5-flash"
-		case "ollama":
-			mc.Client.Model = "llama3"
+	// Fallbacks resolve their keys and base URLs the same way, and share the
+	// primary's retry setting
+	for _, fb := range cfg.Model.Fallbacks {
+		if fb.Provider == "" {
+			continue
+		}
+		fmc := ModelConfig{Provider: fb.Provider}
+		fmc.Client.Model = fb.Name
+		fmc.Client.MaxRetries = mc.Client.MaxRetries
+		resolveAPIKey(&fmc, envVars)
+		fmc.Client.BaseURL = fb.BaseURL
+		if env, ok := baseURLEnvVars[fmc.Provider]; ok && fb.BaseURL == "" {
+			fmc.Client.BaseURL = envVars[env]
 		}
+		resolveRegion(&fmc, envVars)
+		setDefaultModel(&fmc)
+		mc.Fallbacks = append(mc.Fallbacks, fmc)
 	}
 
 	return mc
 }
 
+// resolveRegion sets the region for Bedrock, which signs requests with AWS
+// credentials rather than an API key.
+func resolveRegion(mc *ModelConfig, envVars map[string]string) {
+	if mc.Provider == "bedrock" {
+		mc.Client.Region = envVars["AWS_REGION"]
+		if mc.Client.Region == "" {
+			mc.Client.Region = envVars["AWS_DEFAULT_REGION"]
+		}
+	}
+}
+
+// setDefaultModel picks the provider's default model if none is set.
+func setDefaultModel(mc *ModelConfig) {
+	if mc.Client.Model != "" {
+		return
+	}
+	switch mc.Provider {
+	case "openai":
+		mc.Client.Model = "gpt-4o"
+	case "anthropic":
+		mc.Client.Model = "claude-sonnet-4-20250514"
+	case "gemini":
+		mc.Client.Model = "gemini-2.5-flash"
+	case "ollama":
+		mc.Client.Model = "llama3"
+	case "bedrock":
+		mc.Client.Model = "anthropic.claude-3-5-sonnet-20241022-v2:0"
+	}
+}
+
+// ResolveEmbeddingConfig resolves the embedding provider for memory retrieval
+// from memory.embedding in forge.yaml, taking the API key from the same
+// environment variables as the chat model. Returns nil if no embedding
+// provider is configured.
+func ResolveEmbeddingConfig(cfg *types.ForgeConfig, envVars map[string]string) *ModelConfig {
+	emb := cfg.Memory.Embedding
+	if emb.Provider == "" {
+		return nil
+	}
+	mc := &ModelConfig{Provider: emb.Provider}
+	mc.Client.Model = emb.Model
+	resolveAPIKey(mc, envVars)
+	if u := envVars["OPENAI_BASE_URL"]; u != "" && mc.Provider == "openai" {
+		mc.Client.BaseURL = u
+	}
+	return mc
+}
+
+// baseURLEnvVars maps providers to the environment variable overriding
+// their base URL.
+var baseURLEnvVars = map[string]string{
+	"openai":    "OPENAI_BASE_URL",
+	"anthropic": "ANTHROPIC_BASE_URL",
+	"gemini":    "GEMINI_BASE_URL",
+	"ollama":    "OLLAMA_BASE_URL",
+}
+
 func resolveAPIKey(mc *ModelConfig, envVars map[string]string) {
 	switch mc.Provider {
 	case "openai":
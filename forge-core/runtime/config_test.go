@@ -0,0 +1,40 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestResolveModelConfig_OpenAIAPIStyle(t *testing.T) {
+	cfg := &types.ForgeConfig{Model: types.ModelRef{Provider: "openai", Name: "o3-mini", APIStyle: "responses", ReasoningEffort: "low"}}
+	mc := ResolveModelConfig(cfg, map[string]string{"OPENAI_API_KEY": "sk-o"}, "")
+	if mc.Client.APIStyle != "responses" || mc.Client.ReasoningEffort != "low" {
+		t.Errorf("client config = %+v", mc.Client)
+	}
+}
+
+func TestResolveModelConfig_Fallbacks(t *testing.T) {
+	cfg := &types.ForgeConfig{Model: types.ModelRef{
+		Provider:   "openai",
+		Name:       "gpt-4o",
+		MaxRetries: 2,
+		Fallbacks: []types.ModelFallbackRef{
+			{Provider: "anthropic"},
+			{Provider: "ollama", Name: "llama3.1", BaseURL: "http://gpu-box:11434/v1"},
+		},
+	}}
+	env := map[string]string{"OPENAI_API_KEY": "sk-o", "ANTHROPIC_API_KEY": "sk-a", "OLLAMA_BASE_URL": "http://ignored:11434/v1"}
+
+	mc := ResolveModelConfig(cfg, env, "")
+	if mc == nil || len(mc.Fallbacks) != 2 {
+		t.Fatalf("got %+v, want two fallbacks", mc)
+	}
+	a, o := mc.Fallbacks[0], mc.Fallbacks[1]
+	if a.Provider != "anthropic" || a.Client.APIKey != "sk-a" || a.Client.Model != "claude-sonnet-4-20250514" || a.Client.MaxRetries != 2 {
+		t.Errorf("anthropic fallback = %+v", a)
+	}
+	if o.Client.Model != "llama3.1" || o.Client.BaseURL != "http://gpu-box:11434/v1" || o.Client.APIKey != "ollama" {
+		t.Errorf("ollama fallback = %+v", o)
+	}
+}
@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// ErrTimeLimit is returned when MaxDuration passes before the model has
+// written any text.
+var ErrTimeLimit = errors.New("agent loop exceeded its time limit")
+
+// withMaxDuration bounds ctx by the executor's MaxDuration, if set.
+func (e *LLMExecutor) withMaxDuration(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.maxDuration <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, e.maxDuration)
+}
+
+// timedOut reports whether the loop context ended because MaxDuration passed,
+// as opposed to the caller canceling or its own deadline expiring.
+func (e *LLMExecutor) timedOut(loopCtx, parent context.Context) bool {
+	return e.maxDuration > 0 && errors.Is(loopCtx.Err(), context.DeadlineExceeded) && parent.Err() == nil
+}
+
+// timeLimitResult ends a run that hit MaxDuration. The last text the model
+// wrote is returned with a note that it is incomplete; with no text yet, the
+// run fails with ErrTimeLimit.
+func (e *LLMExecutor) timeLimitResult(ctx context.Context, last string) (*a2a.Message, error) {
+	limit := e.maxDuration.Round(time.Millisecond)
+	if last == "" {
+		return nil, fmt.Errorf("%w of %s before the model produced a response", ErrTimeLimit, limit)
+	}
+	_ = e.hooks.Fire(ctx, OnWarning, &HookContext{Warning: fmt.Sprintf(
+		"time limit of %s reached; returning the last partial response", limit)})
+	text := fmt.Sprintf("%s\n\n[Truncated: the agent reached its time limit of %s before finishing.]", last, limit)
+	return &a2a.Message{
+		Role:  a2a.MessageRoleAgent,
+		Parts: []a2a.Part{a2a.NewTextPart(text)},
+	}, nil
+}
@@ -0,0 +1,118 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// delayedResponse is one scripted LLM response returned after a delay.
+type delayedResponse struct {
+	delay time.Duration
+	resp  *llm.ChatResponse
+}
+
+// sequentialClient returns its scripted responses in order, honoring ctx
+// while it waits.
+func sequentialClient(steps ...delayedResponse) *mockLLMClient {
+	n := 0
+	return &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			step := steps[min(n, len(steps)-1)]
+			n++
+			select {
+			case <-time.After(step.delay):
+				return step.resp, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	}
+}
+
+func TestLLMExecutor_MaxDurationReturnsPartial(t *testing.T) {
+	client := sequentialClient(
+		delayedResponse{resp: &llm.ChatResponse{
+			Message: llm.ChatMessage{
+				Role:      llm.RoleAssistant,
+				Content:   "So far: the build is failing in the lint step.",
+				ToolCalls: []llm.ToolCall{toolCall("c1", "fetch_logs")},
+			},
+			FinishReason: "tool_calls",
+		}},
+		delayedResponse{delay: 5 * time.Second, resp: &llm.ChatResponse{
+			Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "too late"},
+			FinishReason: "stop",
+		}},
+	)
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			return "logs", nil
+		},
+	}
+	var warnings []string
+	hooks := NewHookRegistry()
+	hooks.Register(OnWarning, func(ctx context.Context, hctx *HookContext) error {
+		warnings = append(warnings, hctx.Warning)
+		return nil
+	})
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools, Hooks: hooks, MaxDuration: 100 * time.Millisecond})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("why is CI red?")}}
+
+	start := time.Now()
+	resp, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Execute took %s, want it stopped near the 100ms limit", elapsed)
+	}
+
+	text := resp.Parts[0].Text
+	if !strings.HasPrefix(text, "So far: the build is failing in the lint step.") {
+		t.Errorf("response = %q, want the last content produced", text)
+	}
+	if !strings.Contains(text, "[Truncated: the agent reached its time limit of 100ms") {
+		t.Errorf("response = %q, want a truncation note", text)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %v, want one time limit warning", warnings)
+	}
+}
+
+func TestLLMExecutor_MaxDurationWithoutContent(t *testing.T) {
+	client := sequentialClient(delayedResponse{delay: 5 * time.Second, resp: &llm.ChatResponse{
+		Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "too late"},
+		FinishReason: "stop",
+	}})
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, MaxDuration: 50 * time.Millisecond})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	_, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if !errors.Is(err, ErrTimeLimit) {
+		t.Fatalf("err = %v, want ErrTimeLimit", err)
+	}
+	if !strings.Contains(err.Error(), "50ms") {
+		t.Errorf("err = %q, want it to name the limit", err)
+	}
+}
+
+func TestLLMExecutor_CallerCancelIsNotTimeLimit(t *testing.T) {
+	client := sequentialClient(delayedResponse{delay: 5 * time.Second, resp: &llm.ChatResponse{}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, MaxDuration: time.Minute})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	_, err := exec.Execute(ctx, &a2a.Task{ID: "t"}, msg)
+	if err == nil || errors.Is(err, ErrTimeLimit) {
+		t.Fatalf("err = %v, want the ordinary failure for a caller deadline", err)
+	}
+}
@@ -1,19 +1,30 @@
 package runtime
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/llm"
 )
 
+// moderationTimeout bounds each moderation call so a slow provider cannot
+// stall the request.
+const moderationTimeout = 10 * time.Second
+
 // GuardrailEngine checks inbound and outbound messages against policy rules.
 type GuardrailEngine struct {
-	scaffold *agentspec.PolicyScaffold
-	enforce  bool
-	logger   Logger
+	scaffold  *agentspec.PolicyScaffold
+	enforce   bool
+	logger    Logger
+	moderator llm.Moderator
+	filters   map[int][]*regexp.Regexp // compiled content_filter patterns by guardrail index
 }
 
 // NewGuardrailEngine creates a GuardrailEngine. If scaffold is nil, a default
@@ -23,7 +34,54 @@ func NewGuardrailEngine(scaffold *agentspec.PolicyScaffold, enforce bool, logger
 	if scaffold == nil {
 		scaffold = &agentspec.PolicyScaffold{}
 	}
-	return &GuardrailEngine{scaffold: scaffold, enforce: enforce, logger: logger}
+	g := &GuardrailEngine{scaffold: scaffold, enforce: enforce, logger: logger}
+	g.filters = g.compileFilters()
+	return g
+}
+
+// compileFilters compiles the "patterns" of each content_filter guardrail.
+// Invalid patterns are logged and skipped; validate.CheckGuardrails rejects
+// them before the runner starts.
+func (g *GuardrailEngine) compileFilters() map[int][]*regexp.Regexp {
+	filters := make(map[int][]*regexp.Regexp)
+	for i, gr := range g.scaffold.Guardrails {
+		if gr.Type != "content_filter" {
+			continue
+		}
+		list, _ := gr.Config["patterns"].([]any)
+		for _, v := range list {
+			expr, ok := v.(string)
+			if !ok {
+				continue
+			}
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				g.logger.Warn("ignoring invalid content filter pattern", map[string]any{
+					"pattern": expr,
+					"error":   err.Error(),
+				})
+				continue
+			}
+			filters[i] = append(filters[i], re)
+		}
+	}
+	return filters
+}
+
+// SetModerator enables "moderation" guardrails, which are skipped while no
+// moderator is set.
+func (g *GuardrailEngine) SetModerator(m llm.Moderator) {
+	g.moderator = m
+}
+
+// HasGuardrail reports whether a guardrail of the given type is configured.
+func (g *GuardrailEngine) HasGuardrail(typ string) bool {
+	for _, gr := range g.scaffold.Guardrails {
+		if gr.Type == typ {
+			return true
+		}
+	}
+	return false
 }
 
 // CheckInbound validates an inbound (user) message against guardrails.
@@ -42,19 +100,54 @@ func (g *GuardrailEngine) check(msg *a2a.Message, direction string) error {
 		return nil
 	}
 
-	for _, gr := range g.scaffold.Guardrails {
+	for i, gr := range g.scaffold.Guardrails {
+		if !appliesTo(gr, direction) {
+			continue
+		}
 		var err error
 		switch gr.Type {
 		case "content_filter":
-			err = g.checkContentFilter(text, gr)
+			err = g.checkContentFilter(text, gr, g.filters[i])
 		case "no_pii":
 			err = g.checkNoPII(text)
+		case "no_secrets":
+			err = checkNoSecrets(text)
 		case "jailbreak_protection":
 			err = g.checkJailbreak(text)
+		case "moderation":
+			err = g.checkModeration(text, gr)
 		default:
 			continue
 		}
 		if err != nil {
+			// Outbound guardrails with action "ephemeral" deliver the reply
+			// ephemerally instead of blocking it
+			if direction == "outbound" && gr.Config["action"] == "ephemeral" {
+				if msg.Metadata == nil {
+					msg.Metadata = map[string]any{}
+				}
+				msg.Metadata[a2a.MetadataEphemeral] = true
+				g.logger.Info("guardrail marked reply ephemeral", map[string]any{
+					"guardrail": gr.Type,
+					"detail":    err.Error(),
+				})
+				continue
+			}
+			// Guardrails with action "redact" mask the matches and let the
+			// message through. Content filters redact in both directions;
+			// no_pii redacts only replies
+			if gr.Config["action"] == "redact" && (direction == "outbound" || gr.Type == "content_filter") {
+				if rules := g.redactRules(i, gr); rules != nil {
+					redactMessage(msg, rules)
+					text = extractText(msg)
+					g.logger.Info("guardrail redacted message", map[string]any{
+						"guardrail": gr.Type,
+						"direction": direction,
+						"detail":    err.Error(),
+					})
+					continue
+				}
+			}
 			if g.enforce {
 				return fmt.Errorf("guardrail %s (%s): %w", gr.Type, direction, err)
 			}
@@ -68,6 +161,70 @@ func (g *GuardrailEngine) check(msg *a2a.Message, direction string) error {
 	return nil
 }
 
+// StreamRedactor returns a redactor for outbound streams built from the
+// guardrails with action "redact", or nil if there are none.
+func (g *GuardrailEngine) StreamRedactor() *StreamRedactor {
+	var rules []redactRule
+	for i, gr := range g.scaffold.Guardrails {
+		if gr.Config["action"] == "redact" && appliesTo(gr, "outbound") {
+			rules = append(rules, g.redactRules(i, gr)...)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	return newStreamRedactor(rules, 0)
+}
+
+// appliesTo reports whether gr checks messages in direction. Config
+// "direction" may be "inbound", "outbound", or "both". It defaults to "both",
+// except for no_secrets, which only checks replies unless configured.
+func appliesTo(gr agentspec.Guardrail, direction string) bool {
+	d, _ := gr.Config["direction"].(string)
+	if d == "" && gr.Type == "no_secrets" {
+		d = "outbound"
+	}
+	return d == "" || d == "both" || d == direction
+}
+
+// redactRules returns what the guardrail at index i masks with action
+// "redact", or nil if the guardrail type cannot redact. Content filters
+// replace matches with config "replacement", defaulting to "[REDACTED]".
+func (g *GuardrailEngine) redactRules(i int, gr agentspec.Guardrail) []redactRule {
+	switch gr.Type {
+	case "no_pii":
+		return defaultRules(piiPatterns)
+	case "no_secrets":
+		return []redactRule{{replace: func(text string) string { return redactSecrets(text, redactedText) }}}
+	case "content_filter":
+		replacement := redactedText
+		if r, ok := gr.Config["replacement"].(string); ok {
+			replacement = r
+		}
+		var rules []redactRule
+		for _, word := range blockedWords(gr) {
+			if word == "" {
+				continue
+			}
+			rules = append(rules, redactRule{pattern: regexp.MustCompile(`(?i)` + regexp.QuoteMeta(word)), replacement: replacement})
+		}
+		for _, re := range g.filters[i] {
+			rules = append(rules, redactRule{pattern: re, replacement: replacement})
+		}
+		return rules
+	}
+	return nil
+}
+
+// redactMessage masks matches of rules in the text parts of msg.
+func redactMessage(msg *a2a.Message, rules []redactRule) {
+	for i, p := range msg.Parts {
+		if p.Kind == a2a.PartKindText {
+			msg.Parts[i].Text = redactText(p.Text, rules)
+		}
+	}
+}
+
 func extractText(msg *a2a.Message) string {
 	var parts []string
 	for _, p := range msg.Parts {
@@ -78,8 +235,9 @@ func extractText(msg *a2a.Message) string {
 	return strings.Join(parts, " ")
 }
 
-func (g *GuardrailEngine) checkContentFilter(text string, gr agentspec.Guardrail) error {
-	// Use blocked words from config, or defaults
+// blockedWords returns a content filter's "blocked_words", or the default
+// list when none are configured.
+func blockedWords(gr agentspec.Guardrail) []string {
 	blocked := []string{"BLOCKED_CONTENT"}
 	if gr.Config != nil {
 		if words, ok := gr.Config["blocked_words"]; ok {
@@ -93,12 +251,23 @@ func (g *GuardrailEngine) checkContentFilter(text string, gr agentspec.Guardrail
 			}
 		}
 	}
+	return blocked
+}
+
+// checkContentFilter matches text case-insensitively against the blocked
+// words and against the guardrail's compiled regex patterns.
+func (g *GuardrailEngine) checkContentFilter(text string, gr agentspec.Guardrail, patterns []*regexp.Regexp) error {
 	lower := strings.ToLower(text)
-	for _, word := range blocked {
+	for _, word := range blockedWords(gr) {
 		if strings.Contains(lower, strings.ToLower(word)) {
 			return fmt.Errorf("content filter: blocked word %q detected", word)
 		}
 	}
+	for _, re := range patterns {
+		if re.MatchString(text) {
+			return fmt.Errorf("content filter: pattern %q matched", re.String())
+		}
+	}
 	return nil
 }
 
@@ -117,6 +286,21 @@ func (g *GuardrailEngine) checkNoPII(text string) error {
 	return nil
 }
 
+// checkNoSecrets reports likely credentials without echoing them.
+func checkNoSecrets(text string) error {
+	matches := DetectSecrets(text)
+	if len(matches) == 0 {
+		return nil
+	}
+	kinds := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if !slices.Contains(kinds, m.Kind) {
+			kinds = append(kinds, m.Kind)
+		}
+	}
+	return fmt.Errorf("possible secret detected: %s", strings.Join(kinds, ", "))
+}
+
 var jailbreakPhrases = []string{
 	"ignore previous instructions",
 	"ignore all instructions",
@@ -135,3 +319,66 @@ func (g *GuardrailEngine) checkJailbreak(text string) error {
 	}
 	return nil
 }
+
+// checkModeration sends text to the moderator. By default the provider's own
+// verdict decides; config "categories" limits which categories count and
+// "threshold" blocks when any counted category's score reaches it. Failed
+// moderation calls are logged and the text is allowed.
+func (g *GuardrailEngine) checkModeration(text string, gr agentspec.Guardrail) error {
+	if g.moderator == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), moderationTimeout)
+	defer cancel()
+	res, err := g.moderator.Moderate(ctx, text)
+	if err != nil {
+		g.logger.Warn("moderation check failed; allowing message", map[string]any{"error": err.Error()})
+		return nil
+	}
+
+	counted := func(string) bool { return true }
+	if list, ok := gr.Config["categories"].([]any); ok && len(list) > 0 {
+		allowed := make(map[string]bool, len(list))
+		for _, c := range list {
+			if s, ok := c.(string); ok {
+				allowed[s] = true
+			}
+		}
+		counted = func(c string) bool { return allowed[c] }
+	}
+
+	var hits []string
+	if threshold, ok := configFloat(gr.Config["threshold"]); ok {
+		for cat, score := range res.Scores {
+			if counted(cat) && score >= threshold {
+				hits = append(hits, fmt.Sprintf("%s=%.2f", cat, score))
+			}
+		}
+	} else {
+		for cat, flagged := range res.Categories {
+			if flagged && counted(cat) {
+				hits = append(hits, cat)
+			}
+		}
+		if len(hits) == 0 && res.Flagged && gr.Config["categories"] == nil {
+			hits = append(hits, "flagged")
+		}
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+	sort.Strings(hits)
+	return fmt.Errorf("moderation: %s", strings.Join(hits, ", "))
+}
+
+// configFloat reads a numeric guardrail config value, which may decode from
+// JSON or YAML as a float or an int.
+func configFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
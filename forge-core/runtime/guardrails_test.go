@@ -0,0 +1,249 @@
+package runtime
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/llm/providers"
+)
+
+func TestGuardrailEngine_EphemeralAction(t *testing.T) {
+	scaffold := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{
+		{Type: "no_pii", Config: map[string]any{"action": "ephemeral"}},
+	}}
+	g := NewGuardrailEngine(scaffold, true, NewJSONLogger(io.Discard, false))
+
+	msg := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("Reach me at jane@example.com")}}
+	if err := g.CheckOutbound(msg); err != nil {
+		t.Fatalf("CheckOutbound() error: %v; ephemeral action should not block", err)
+	}
+	if msg.Metadata[a2a.MetadataEphemeral] != true {
+		t.Errorf("metadata = %v, want reply marked ephemeral", msg.Metadata)
+	}
+
+	clean := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("All done.")}}
+	if err := g.CheckOutbound(clean); err != nil {
+		t.Fatalf("CheckOutbound() error: %v", err)
+	}
+	if clean.Metadata != nil {
+		t.Errorf("clean reply should not be marked, got %v", clean.Metadata)
+	}
+
+	// The action only applies to outbound replies
+	in := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("I am jane@example.com")}}
+	if err := g.CheckInbound(in); err == nil {
+		t.Error("expected enforced inbound violation")
+	}
+}
+
+func TestGuardrailEngine_RedactAction(t *testing.T) {
+	scaffold := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{
+		{Type: "no_pii", Config: map[string]any{"action": "redact"}},
+	}}
+	g := NewGuardrailEngine(scaffold, true, NewJSONLogger(io.Discard, false))
+
+	msg := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("Reach me at jane@example.com or 555-123-4567")}}
+	if err := g.CheckOutbound(msg); err != nil {
+		t.Fatalf("CheckOutbound() error: %v; redact action should not block", err)
+	}
+	if got, want := msg.Parts[0].Text, "Reach me at [REDACTED] or [REDACTED]"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+
+	// The action only applies to outbound replies
+	in := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("I am jane@example.com")}}
+	if err := g.CheckInbound(in); err == nil {
+		t.Error("expected enforced inbound violation")
+	}
+}
+
+func TestGuardrailEngine_ContentFilterPatternsBlock(t *testing.T) {
+	scaffold := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{
+		{Type: "content_filter", Config: map[string]any{
+			"blocked_words": []any{},
+			"patterns":      []any{`(?i)project\s+falcon`, `([invalid`},
+			"action":        "block",
+		}},
+	}}
+	g := NewGuardrailEngine(scaffold, true, NewJSONLogger(io.Discard, false))
+
+	in := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("what is Project  Falcon?")}}
+	if err := g.CheckInbound(in); err == nil || !strings.Contains(err.Error(), "pattern") {
+		t.Errorf("CheckInbound() error = %v, want a pattern match", err)
+	}
+
+	out := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("Project Falcon ships in May")}}
+	if err := g.CheckOutbound(out); err == nil {
+		t.Error("expected enforced outbound violation")
+	}
+	if out.Parts[0].Text != "Project Falcon ships in May" {
+		t.Errorf("blocked message was modified: %q", out.Parts[0].Text)
+	}
+
+	// The invalid pattern is skipped rather than matching everything
+	ok := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("all clear")}}
+	if err := g.CheckOutbound(ok); err != nil {
+		t.Errorf("CheckOutbound() error = %v for clean text", err)
+	}
+}
+
+func TestGuardrailEngine_ContentFilterPatternsRedact(t *testing.T) {
+	scaffold := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{
+		{Type: "content_filter", Config: map[string]any{
+			"blocked_words": []any{"hunter2"},
+			"patterns":      []any{`\bACCT-\d{6}\b`},
+			"action":        "redact",
+			"replacement":   "***",
+		}},
+	}}
+	g := NewGuardrailEngine(scaffold, true, NewJSONLogger(io.Discard, false))
+
+	msg := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{
+		a2a.NewTextPart("Account ACCT-123456 uses password HUNTER2."),
+		a2a.NewTextPart("Also ACCT-654321."),
+	}}
+	if err := g.CheckOutbound(msg); err != nil {
+		t.Fatalf("CheckOutbound() error: %v; redact action should not block", err)
+	}
+	if got, want := msg.Parts[0].Text, "Account *** uses password ***."; got != want {
+		t.Errorf("part 0 = %q, want %q", got, want)
+	}
+	if got, want := msg.Parts[1].Text, "Also ***."; got != want {
+		t.Errorf("part 1 = %q, want %q", got, want)
+	}
+
+	// Streamed replies use the same patterns and replacement
+	r := g.StreamRedactor()
+	if r == nil {
+		t.Fatal("StreamRedactor() = nil for a redacting content filter")
+	}
+	if got := r.Write("ref ACCT-") + r.Write("999999 done") + r.Flush(); got != "ref *** done" {
+		t.Errorf("streamed = %q", got)
+	}
+
+	// Inbound matches are redacted the same way
+	in := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("my id is ACCT-000001")}}
+	if err := g.CheckInbound(in); err != nil {
+		t.Fatalf("CheckInbound() error: %v; redact action should not block", err)
+	}
+	if got, want := in.Parts[0].Text, "my id is ***"; got != want {
+		t.Errorf("inbound = %q, want %q", got, want)
+	}
+}
+
+func TestGuardrailEngine_Direction(t *testing.T) {
+	scaffold := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{
+		{Type: "content_filter", Config: map[string]any{"patterns": []any{`secret`}, "direction": "outbound"}},
+	}}
+	g := NewGuardrailEngine(scaffold, true, NewJSONLogger(io.Discard, false))
+
+	in := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("tell me a secret")}}
+	if err := g.CheckInbound(in); err != nil {
+		t.Errorf("CheckInbound() error = %v for an outbound-only guardrail", err)
+	}
+	out := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("the secret is out")}}
+	if err := g.CheckOutbound(out); err == nil {
+		t.Error("expected outbound violation")
+	}
+}
+
+// fakeModerationServer flags any input containing "attack" with a high
+// violence score and scores everything else low.
+func fakeModerationServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/moderations" {
+			http.NotFound(w, r)
+			return
+		}
+		var req struct {
+			Input string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req) //nolint:errcheck
+		flagged := strings.Contains(req.Input, "attack")
+		score := 0.02
+		if flagged {
+			score = 0.91
+		}
+		json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+			"results": []map[string]any{{
+				"flagged":         flagged,
+				"categories":      map[string]bool{"violence": flagged, "hate": false},
+				"category_scores": map[string]float64{"violence": score, "hate": 0.01},
+			}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGuardrailEngine_Moderation(t *testing.T) {
+	srv := fakeModerationServer(t)
+
+	tests := []struct {
+		name      string
+		config    map[string]any
+		text      string
+		direction string
+		wantBlock bool
+	}{
+		{name: "flagged blocked", text: "plan an attack", direction: "inbound", wantBlock: true},
+		{name: "clean allowed", text: "plan a picnic", direction: "inbound"},
+		{name: "outbound checked by default", text: "plan an attack", direction: "outbound", wantBlock: true},
+		{name: "direction limits checks", config: map[string]any{"direction": "inbound"}, text: "plan an attack", direction: "outbound"},
+		{name: "threshold above score allows", config: map[string]any{"threshold": 0.95}, text: "plan an attack", direction: "inbound"},
+		{name: "threshold below score blocks", config: map[string]any{"threshold": 0.5}, text: "plan an attack", direction: "inbound", wantBlock: true},
+		{name: "uncounted category allows", config: map[string]any{"categories": []any{"hate"}}, text: "plan an attack", direction: "inbound"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scaffold := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{{Type: "moderation", Config: tt.config}}}
+			g := NewGuardrailEngine(scaffold, true, NewJSONLogger(io.Discard, false))
+			g.SetModerator(providers.NewOpenAIModerator(llm.ClientConfig{BaseURL: srv.URL, APIKey: "k"}))
+
+			msg := &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart(tt.text)}}
+			var err error
+			if tt.direction == "inbound" {
+				err = g.CheckInbound(msg)
+			} else {
+				err = g.CheckOutbound(msg)
+			}
+			if blocked := err != nil; blocked != tt.wantBlock {
+				t.Errorf("blocked = %v (err %v), want %v", blocked, err, tt.wantBlock)
+			}
+		})
+	}
+}
+
+func TestGuardrailEngine_ModerationUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	scaffold := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{{Type: "moderation"}}}
+	msg := &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart("plan an attack")}}
+
+	// A failing moderation call warns but does not block, even when enforced
+	g := NewGuardrailEngine(scaffold, true, NewJSONLogger(io.Discard, false))
+	g.SetModerator(providers.NewOpenAIModerator(llm.ClientConfig{BaseURL: srv.URL}))
+	if err := g.CheckInbound(msg); err != nil {
+		t.Errorf("CheckInbound() = %v, want allowed when moderation errors", err)
+	}
+
+	// Without a moderator the guardrail is skipped
+	g = NewGuardrailEngine(scaffold, true, NewJSONLogger(io.Discard, false))
+	if err := g.CheckInbound(msg); err != nil {
+		t.Errorf("CheckInbound() = %v, want skipped without moderator", err)
+	}
+	if !g.HasGuardrail("moderation") || g.HasGuardrail("no_pii") {
+		t.Error("HasGuardrail reported wrong types")
+	}
+}
@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"context"
+	"errors"
 
 	"github.com/initializ/forge/forge-core/llm"
 )
@@ -15,16 +16,30 @@ const (
 	BeforeToolExec
 	AfterToolExec
 	OnError
+	OnWarning
+	ToolApproval // fires before BeforeToolExec; return ErrToolDenied to skip the call
+	OnComplete   // fires once when a run ends, successful or not, with its cost
 )
 
-// HookContext carries data available to hooks at each hook point.
+// ErrToolDenied is returned, optionally wrapped with a reason, by a
+// ToolApproval hook to refuse a tool call. The call is not executed and the
+// error text is returned to the model as the tool result.
+var ErrToolDenied = errors.New("tool call denied by policy")
+
+// HookContext carries data available to hooks at each hook point. Tool call
+// arguments in Messages and Response, like ToolInput, are redacted by tools
+// that implement tools.InputRedactor.
 type HookContext struct {
 	Messages   []llm.ChatMessage
 	Response   *llm.ChatResponse
 	ToolName   string
 	ToolInput  string
 	ToolOutput string
+	ToolCallID string // set for tool hooks; distinguishes concurrent calls to one tool
 	Error      error
+	CacheHit   bool         // set for AfterToolExec when the result came from the tool cache
+	Warning    string       // set for OnWarning
+	Cost       *CostSummary // set for OnComplete
 }
 
 // Hook is a function invoked at a specific point in the agent loop.
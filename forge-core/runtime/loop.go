@@ -2,13 +2,16 @@ package runtime
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/llm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ToolExecutor provides tool execution capabilities to the engine.
@@ -25,15 +28,53 @@ type LLMExecutor struct {
 	hooks        *HookRegistry
 	systemPrompt string
 	maxIter      int
+	toolBudget   float64
+	toolCosts    map[string]float64
+	caps         llm.Capabilities
+	maxHistory   int
+	keepFirst    bool
+	temperature  *float64
+	topP         *float64
+	stop         []string
+	cachePrompt  bool
+	format       *llm.ResponseFormat
+	window       int
+	counter      llm.TokenCounter
+	parallel     bool
+	askMissing   bool
+	repeatLimit  int
+	abortRepeat  bool
+	maxDuration  time.Duration
+	pricing      llm.PricingTable
+	tracer       trace.Tracer
 }
 
 // LLMExecutorConfig configures the LLM executor.
 type LLMExecutorConfig struct {
-	Client        llm.Client
-	Tools         ToolExecutor
-	Hooks         *HookRegistry
-	SystemPrompt  string
-	MaxIterations int
+	Client         llm.Client
+	Tools          ToolExecutor
+	Hooks          *HookRegistry
+	SystemPrompt   string
+	MaxIterations  int
+	ToolBudget     float64             // per-run tool cost limit; 0 disables
+	ToolCosts      map[string]float64  // estimated cost per tool name
+	Capabilities   *llm.Capabilities   // model capabilities; looked up from the client's model ID when nil
+	MaxHistory     int                 // prior task messages replayed per request; 0 = unlimited
+	KeepFirst      bool                // with MaxHistory, always retain the first history message
+	Temperature    *float64            // sampling temperature; nil uses the provider default
+	TopP           *float64            // nucleus sampling cutoff; nil uses the provider default
+	Stop           []string            // stop sequences sent with every request
+	CachePrompt    bool                // request provider prompt caching of the system prompt and tools
+	ResponseFormat *llm.ResponseFormat // constrains the final answer to JSON; nil means text
+	ContextWindow  int                 // model context window in tokens; oldest history is trimmed to fit; 0 disables
+	TokenCounter   llm.TokenCounter    // estimates prompt size for ContextWindow; nil uses llm.HeuristicCounter
+	ParallelTools  bool                // run the tool calls of one response concurrently
+	AskForInputs   bool                // ask the user for required tool inputs the model left out
+	RepeatLimit    int                 // identical tool call rounds in a row before the model is warned; 0 = 3, negative disables
+	AbortOnRepeat  bool                // fail with ErrToolLoop if the model repeats the calls again after the warning
+	MaxDuration    time.Duration       // overall time limit per run; the last text so far is returned when it passes; 0 disables
+	Pricing        llm.PricingTable    // token prices for the OnComplete cost summary; nil uses llm.DefaultPricing
+	Tracer         trace.Tracer        // records an OpenTelemetry span per run, LLM call, and tool call; nil records none
 }
 
 // NewLLMExecutor creates a new LLMExecutor with the given configuration.
@@ -46,136 +87,290 @@ func NewLLMExecutor(cfg LLMExecutorConfig) *LLMExecutor {
 	if hooks == nil {
 		hooks = NewHookRegistry()
 	}
+	caps := llm.DefaultCapabilities
+	if cfg.Capabilities != nil {
+		caps = *cfg.Capabilities
+	} else if cfg.Client != nil {
+		caps = llm.LookupCapabilities(cfg.Client.ModelID())
+	}
+	repeatLimit := cfg.RepeatLimit
+	if repeatLimit == 0 {
+		repeatLimit = defaultRepeatLimit
+	}
+	counter := cfg.TokenCounter
+	if counter == nil {
+		counter = llm.HeuristicCounter{}
+	}
 	return &LLMExecutor{
 		client:       cfg.Client,
 		tools:        cfg.Tools,
 		hooks:        hooks,
 		systemPrompt: cfg.SystemPrompt,
 		maxIter:      maxIter,
+		toolBudget:   cfg.ToolBudget,
+		toolCosts:    cfg.ToolCosts,
+		caps:         caps,
+		maxHistory:   cfg.MaxHistory,
+		keepFirst:    cfg.KeepFirst,
+		temperature:  cfg.Temperature,
+		topP:         cfg.TopP,
+		stop:         cfg.Stop,
+		cachePrompt:  cfg.CachePrompt,
+		format:       cfg.ResponseFormat,
+		window:       cfg.ContextWindow,
+		counter:      counter,
+		parallel:     cfg.ParallelTools,
+		askMissing:   cfg.AskForInputs,
+		repeatLimit:  repeatLimit,
+		abortRepeat:  cfg.AbortOnRepeat,
+		maxDuration:  cfg.MaxDuration,
+		pricing:      cfg.Pricing,
+		tracer:       cfg.Tracer,
 	}
 }
 
 // Execute processes a message through the LLM agent loop.
 func (e *LLMExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	return e.run(ctx, task, msg, nil)
+}
+
+// run is the agent loop. With emit set, LLM responses are streamed and each
+// piece of text is passed to emit as a partial message as it arrives.
+func (e *LLMExecutor) run(parent context.Context, task *a2a.Task, msg *a2a.Message, emit func(*a2a.Message)) (_ *a2a.Message, err error) {
+	parent, span := e.startSpan(parent, otelOpAgent, e.client.ModelID(),
+		otelTaskID.String(task.ID), otelModel.String(e.client.ModelID()))
+	ctx, cancel := e.withMaxDuration(parent)
+	defer cancel()
+
+	// Report the run's total cost once, however it ends. The run's tracker
+	// passes each call on to the caller's
+	ctx, tracker := withChildUsageTracker(ctx)
+	defer func() {
+		summary := tracker.Summary(e.pricing)
+		_ = e.hooks.Fire(ctx, OnComplete, &HookContext{Cost: &summary, Error: err})
+		endSpan(span, err, otelLLMCalls.Int(summary.LLMCalls),
+			otelInputTokens.Int(summary.PromptTokens), otelOutputTokens.Int(summary.CompletionTokens))
+	}()
+
 	mem := NewMemory(e.systemPrompt, 0)
 
 	// Load task history into memory
-	for _, histMsg := range task.History {
-		mem.Append(a2aMessageToLLM(histMsg))
+	for _, histMsg := range boundHistory(task.History, e.maxHistory, e.keepFirst) {
+		mem.Append(e.toLLMMessage(histMsg))
 	}
 
 	// Append the new user message
-	mem.Append(a2aMessageToLLM(*msg))
+	mem.Append(e.toLLMMessage(*msg))
 
 	// Build tool definitions
 	var toolDefs []llm.ToolDefinition
 	if e.tools != nil {
 		toolDefs = e.tools.ToolDefinitions()
 	}
+	budget := newToolBudget(e.toolBudget, e.toolCosts)
+	var repeats repeatDetector
+	var last string // latest text from the model, returned if MaxDuration passes
 
 	// Agent loop
 	for i := 0; i < e.maxIter; i++ {
-		messages := mem.Messages()
+		if e.timedOut(ctx, parent) {
+			return e.timeLimitResult(ctx, last)
+		}
+
+		availableTools := budget.filter(toolDefs)
+		messages := e.fitContext(ctx, mem, availableTools)
 
 		// Fire BeforeLLMCall hook
-		if err := e.hooks.Fire(ctx, BeforeLLMCall, &HookContext{Messages: messages}); err != nil {
+		if err := e.hooks.Fire(ctx, BeforeLLMCall, &HookContext{Messages: e.shownMessages(messages)}); err != nil {
 			return nil, fmt.Errorf("before LLM call hook: %w", err)
 		}
 
+		EmitStatus(ctx, StatusEvent{Type: StatusLLMCall, Iteration: i + 1})
+
 		// Call LLM
 		req := &llm.ChatRequest{
-			Messages: messages,
-			Tools:    toolDefs,
+			Messages:          messages,
+			Tools:             availableTools,
+			Temperature:       e.temperature,
+			TopP:              e.topP,
+			Stop:              e.stop,
+			CacheSystemPrompt: e.cachePrompt,
+			ResponseFormat:    e.format,
+		}
+		if err := e.caps.Gate(e.client.ModelID(), req); err != nil {
+			_ = e.hooks.Fire(ctx, OnError, &HookContext{Error: err})
+			return nil, err
 		}
 
-		resp, err := e.client.Chat(ctx, req)
+		callCtx, callSpan := e.startSpan(ctx, otelOpChat, e.client.ModelID(),
+			otelModel.String(e.client.ModelID()), otelIteration.Int(i+1))
+		resp, err := e.chat(callCtx, req, emit)
+		var usage []attribute.KeyValue
+		if resp != nil {
+			if resp.Model == "" {
+				resp.Model = e.client.ModelID()
+			}
+			usage = []attribute.KeyValue{
+				otelResponseModel.String(resp.Model),
+				otelInputTokens.Int(resp.Usage.PromptTokens),
+				otelOutputTokens.Int(resp.Usage.CompletionTokens),
+				otelFinishReason.StringSlice([]string{resp.FinishReason}),
+			}
+		}
+		endSpan(callSpan, err, usage...)
+		if err != nil && e.timedOut(ctx, parent) {
+			return e.timeLimitResult(ctx, last)
+		}
 		if err != nil {
 			_ = e.hooks.Fire(ctx, OnError, &HookContext{Error: err})
 			// Return user-friendly error (raw error is already logged via OnError hook)
 			return nil, fmt.Errorf("something went wrong while processing your request, please try again")
 		}
+		RecordUsage(ctx, resp.Model, resp.Usage)
+		resp.Message.ToolCalls = dedupToolCalls(resp.Message.ToolCalls)
 
 		// Fire AfterLLMCall hook
 		if err := e.hooks.Fire(ctx, AfterLLMCall, &HookContext{
-			Messages: messages,
-			Response: resp,
+			Messages: e.shownMessages(messages),
+			Response: e.shownResponse(resp),
 		}); err != nil {
 			return nil, fmt.Errorf("after LLM call hook: %w", err)
 		}
 
 		// Append assistant message to memory
 		mem.Append(resp.Message)
+		if resp.Message.Content != "" {
+			last = resp.Message.Content
+		}
+		RecordTranscript(ctx, TranscriptEntry{
+			Type:         TranscriptLLMCall,
+			Iteration:    i + 1,
+			Messages:     e.shownMessages(messages),
+			Content:      resp.Message.Content,
+			ToolCalls:    e.shownToolCalls(resp.Message.ToolCalls),
+			FinishReason: resp.FinishReason,
+		})
 
 		// Check if we're done (no tool calls)
-		if resp.FinishReason == "stop" || len(resp.Message.ToolCalls) == 0 {
-			return llmMessageToA2A(resp.Message), nil
+		if resp.FinishReason == "stop" || len(resp.Message.ToolCalls) == 0 || e.tools == nil {
+			err := e.format.Validate(resp.Message.Content)
+			if err == nil {
+				return llmMessageToA2A(resp.Message), nil
+			}
+			if i == e.maxIter-1 {
+				return nil, fmt.Errorf("model did not return a valid JSON response: %w", err)
+			}
+			// Ask again; the invalid answer stays in memory as context
+			mem.Append(llm.ChatMessage{
+				Role:    llm.RoleUser,
+				Content: fmt.Sprintf("Your previous reply was rejected: %s. Reply again with only the JSON response.", err),
+			})
+			continue
 		}
 
-		// Execute tool calls
-		if e.tools == nil {
-			return llmMessageToA2A(resp.Message), nil
+		// Text accompanying tool calls is intermediate reasoning, not the answer
+		if resp.Message.Content != "" {
+			EmitStatus(ctx, StatusEvent{Type: StatusReasoning, Iteration: i + 1, Text: resp.Message.Content})
 		}
 
-		for _, tc := range resp.Message.ToolCalls {
-			// Fire BeforeToolExec hook
-			if err := e.hooks.Fire(ctx, BeforeToolExec, &HookContext{
-				ToolName:  tc.Function.Name,
-				ToolInput: tc.Function.Arguments,
-			}); err != nil {
-				return nil, fmt.Errorf("before tool exec hook: %w", err)
-			}
-
-			// Execute tool
-			result, execErr := e.tools.Execute(ctx, tc.Function.Name, json.RawMessage(tc.Function.Arguments))
-			if execErr != nil {
-				result = fmt.Sprintf("Error executing tool %s: %s", tc.Function.Name, execErr.Error())
+		// Pause for the user rather than run a call missing required inputs
+		if e.askMissing {
+			if q := missingInputQuestion(resp.Message.ToolCalls, toolDefs); q != nil {
+				return q, nil
 			}
+		}
 
-			// Truncate oversized tool results to avoid LLM API errors.
-			// Use a limit below maxMessageChars so the suffix fits within the memory cap.
-			const maxToolResultChars = 49_000 // ~12K tokens, leaves room for truncation suffix
-			if len(result) > maxToolResultChars {
-				result = result[:maxToolResultChars] + "\n\n[OUTPUT TRUNCATED — original length: " + strconv.Itoa(len(result)) + " chars]"
-			}
+		repeated := repeats.observe(resp.Message.ToolCalls)
+		if e.abortRepeat && e.repeatLimit > 0 && repeated > e.repeatLimit {
+			return nil, fmt.Errorf("%w: %s called with the same arguments %d times in a row", ErrToolLoop, callNames(resp.Message.ToolCalls), repeated)
+		}
 
-			// Fire AfterToolExec hook
-			if err := e.hooks.Fire(ctx, AfterToolExec, &HookContext{
-				ToolName:   tc.Function.Name,
-				ToolInput:  tc.Function.Arguments,
-				ToolOutput: result,
-				Error:      execErr,
-			}); err != nil {
-				return nil, fmt.Errorf("after tool exec hook: %w", err)
-			}
+		outcomes, err := e.executeTools(ctx, i+1, resp.Message.ToolCalls, budget)
+		if err != nil {
+			return nil, err
+		}
 
-			// Append tool result to memory
-			mem.Append(llm.ChatMessage{
-				Role:       llm.RoleTool,
-				Content:    result,
-				ToolCallID: tc.ID,
-				Name:       tc.Function.Name,
+		// Append tool results to memory in the order the model asked for them
+		for _, o := range inRequestOrder(outcomes) {
+			mem.Append(llm.NewToolResultMessage(o.call, o.result, o.isError))
+			RecordTranscript(ctx, TranscriptEntry{
+				Type:       TranscriptToolCall,
+				Iteration:  i + 1,
+				ToolCallID: o.call.ID,
+				ToolName:   o.call.Function.Name,
+				ToolInput:  e.shownInput(o.call),
+				ToolOutput: o.result,
+				IsError:    o.isError,
 			})
 		}
+
+		// Nudge a model stuck on the same calls. A system message would
+		// replace the system prompt on some providers, so this is a user turn.
+		if e.repeatLimit > 0 && repeated >= e.repeatLimit {
+			warning := repeatWarning(resp.Message.ToolCalls, repeated)
+			_ = e.hooks.Fire(ctx, OnWarning, &HookContext{Warning: warning})
+			mem.Append(llm.ChatMessage{Role: llm.RoleUser, Content: warning})
+		}
 	}
 
-	return nil, fmt.Errorf("agent loop exceeded maximum iterations (%d)", e.maxIter)
+	return nil, fmt.Errorf("%w (%d)", ErrMaxIterations, e.maxIter)
+}
+
+// fitContext returns the conversation to send, first dropping the oldest
+// history from mem while the estimated prompt exceeds the context window.
+// The system prompt and most recent user turn are always kept; a prompt that
+// still does not fit is sent anyway. Either case fires an OnWarning hook.
+func (e *LLMExecutor) fitContext(ctx context.Context, mem *Memory, toolDefs []llm.ToolDefinition) []llm.ChatMessage {
+	messages := mem.Messages()
+	if e.window <= 0 {
+		return messages
+	}
+	estimate := e.counter.CountTokens(&llm.ChatRequest{Messages: messages, Tools: toolDefs})
+	if estimate <= e.window {
+		return messages
+	}
+
+	initial, dropped := estimate, 0
+	for estimate > e.window && mem.DropOldest() {
+		dropped++
+		messages = mem.Messages()
+		estimate = e.counter.CountTokens(&llm.ChatRequest{Messages: messages, Tools: toolDefs})
+	}
+	if dropped > 0 {
+		_ = e.hooks.Fire(ctx, OnWarning, &HookContext{Warning: fmt.Sprintf(
+			"prompt of ~%d tokens exceeded the %d-token context window; dropped %d oldest history group(s), now ~%d tokens",
+			initial, e.window, dropped, estimate)})
+	}
+	if estimate > e.window {
+		_ = e.hooks.Fire(ctx, OnWarning, &HookContext{Warning: fmt.Sprintf(
+			"prompt of ~%d tokens still exceeds the %d-token context window after trimming history", estimate, e.window)})
+	}
+	return messages
 }
 
-// ExecuteStream runs the tool-calling loop non-streaming, then emits the final
-// response as a single message on the channel. True word-by-word streaming is v2.
+// ExecuteStream runs the agent loop with streamed LLM responses. Text is
+// sent as partial messages (a2a.MetadataPartial) as it arrives, including
+// any text the model writes before calling tools. The complete final reply
+// is sent last as a normal message. Sends stop blocking once ctx is done.
 func (e *LLMExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
-	ch := make(chan *a2a.Message, 1)
+	ch := make(chan *a2a.Message, 16)
+	send := func(m *a2a.Message) {
+		select {
+		case ch <- m:
+		case <-ctx.Done():
+		}
+	}
 	go func() {
 		defer close(ch)
-		resp, err := e.Execute(ctx, task, msg)
+		resp, err := e.run(ctx, task, msg, send)
 		if err != nil {
-			ch <- &a2a.Message{
+			send(&a2a.Message{
 				Role:  a2a.MessageRoleAgent,
 				Parts: []a2a.Part{a2a.NewTextPart("Error: " + err.Error())},
-			}
+			})
 			return
 		}
-		ch <- resp
+		send(resp)
 	}()
 	return ch, nil
 }
@@ -183,7 +378,19 @@ func (e *LLMExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2
 // Close is a no-op for LLMExecutor.
 func (e *LLMExecutor) Close() error { return nil }
 
-// a2aMessageToLLM converts an A2A message to an LLM chat message.
+// toLLMMessage converts an A2A message for the model. Images from the
+// user's file parts are kept only when the model accepts images.
+func (e *LLMExecutor) toLLMMessage(msg a2a.Message) llm.ChatMessage {
+	m := a2aMessageToLLM(msg)
+	if !e.caps.Vision || m.Role != llm.RoleUser {
+		m.Images = nil
+	}
+	return m
+}
+
+// a2aMessageToLLM converts an A2A message to an LLM chat message. Text
+// parts become the content and image file parts become images; other parts
+// are dropped.
 func a2aMessageToLLM(msg a2a.Message) llm.ChatMessage {
 	role := llm.RoleUser
 	if msg.Role == a2a.MessageRoleAgent {
@@ -191,16 +398,37 @@ func a2aMessageToLLM(msg a2a.Message) llm.ChatMessage {
 	}
 
 	var textParts []string
+	var images []llm.ImageContent
 	for _, p := range msg.Parts {
-		if p.Kind == a2a.PartKindText && p.Text != "" {
+		switch {
+		case p.Kind == a2a.PartKindText && p.Text != "":
 			textParts = append(textParts, p.Text)
+		case p.Kind == a2a.PartKindFile && p.File != nil:
+			if img, ok := fileImage(p.File); ok {
+				images = append(images, img)
+			}
 		}
 	}
 
 	return llm.ChatMessage{
 		Role:    role,
 		Content: strings.Join(textParts, "\n"),
+		Images:  images,
+	}
+}
+
+// fileImage converts a file part holding an image, by URI or inline bytes.
+func fileImage(f *a2a.FileContent) (llm.ImageContent, bool) {
+	if !strings.HasPrefix(f.MimeType, "image/") {
+		return llm.ImageContent{}, false
+	}
+	switch {
+	case len(f.Bytes) > 0:
+		return llm.ImageContent{MediaType: f.MimeType, Data: base64.StdEncoding.EncodeToString(f.Bytes)}, true
+	case f.URI != "":
+		return llm.ImageContent{URL: f.URI, MediaType: f.MimeType}, true
 	}
+	return llm.ImageContent{}, false
 }
 
 // llmMessageToA2A converts an LLM chat message to an A2A message.
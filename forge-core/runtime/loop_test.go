@@ -13,7 +13,8 @@ import (
 
 // mockLLMClient implements llm.Client for testing.
 type mockLLMClient struct {
-	chatFunc func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error)
+	chatFunc   func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error)
+	streamFunc func(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error)
 }
 
 func (m *mockLLMClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
@@ -21,6 +22,9 @@ func (m *mockLLMClient) Chat(ctx context.Context, req *llm.ChatRequest) (*llm.Ch
 }
 
 func (m *mockLLMClient) ChatStream(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+	if m.streamFunc != nil {
+		return m.streamFunc(ctx, req)
+	}
 	return nil, fmt.Errorf("not implemented")
 }
 
@@ -250,3 +254,464 @@ func TestLLMErrorReturnsFriendlyMessage(t *testing.T) {
 		t.Errorf("error should contain friendly message, got: %s", errStr)
 	}
 }
+
+func TestStatusSideChannel(t *testing.T) {
+	callCount := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			callCount++
+			if callCount == 1 {
+				return &llm.ChatResponse{
+					Message: llm.ChatMessage{
+						Role:    llm.RoleAssistant,
+						Content: "Let me look that up.",
+						ToolCalls: []llm.ToolCall{
+							{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "lookup", Arguments: `{"q":"x"}`}},
+							{ID: "call_2", Type: "function", Function: llm.FunctionCall{Name: "fail", Arguments: `{}`}},
+						},
+					},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "The answer is 42."},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			if name == "fail" {
+				return "", fmt.Errorf("boom")
+			}
+			return "found", nil
+		},
+	}
+
+	executor := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools})
+
+	var events []StatusEvent
+	ctx := WithStatusFunc(context.Background(), func(ev StatusEvent) {
+		events = append(events, ev)
+	})
+
+	resp, err := executor.Execute(ctx, &a2a.Task{ID: "t"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("question")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Main response carries only the final answer
+	if len(resp.Parts) != 1 || resp.Parts[0].Text != "The answer is 42." {
+		t.Errorf("response = %+v, want only final text", resp.Parts)
+	}
+
+	wantTypes := []string{
+		StatusLLMCall, StatusReasoning,
+		StatusToolStart, StatusToolEnd,
+		StatusToolStart, StatusToolEnd,
+		StatusLLMCall,
+	}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(wantTypes), events)
+	}
+	for i, want := range wantTypes {
+		if events[i].Type != want {
+			t.Errorf("event[%d].Type = %q, want %q", i, events[i].Type, want)
+		}
+	}
+	if events[1].Text != "Let me look that up." {
+		t.Errorf("reasoning text = %q", events[1].Text)
+	}
+	if events[2].ToolName != "lookup" || events[2].ToolInput != `{"q":"x"}` {
+		t.Errorf("tool_start = %+v", events[2])
+	}
+	if events[3].ToolOutput != "found" || events[3].Error != "" {
+		t.Errorf("tool_end = %+v", events[3])
+	}
+	if events[5].ToolName != "fail" || events[5].Error != "boom" {
+		t.Errorf("failing tool_end = %+v", events[5])
+	}
+}
+
+func TestStatusSideChannel_NoFunc(t *testing.T) {
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "hi"},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	executor := NewLLMExecutor(LLMExecutorConfig{Client: client})
+	if _, err := executor.Execute(context.Background(), &a2a.Task{ID: "t"}, &a2a.Message{Role: a2a.MessageRoleUser}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestToolBudgetExhausted(t *testing.T) {
+	callCount := 0
+	var offered [][]string
+	var lastMessages []llm.ChatMessage
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			callCount++
+			var names []string
+			for _, d := range req.Tools {
+				names = append(names, d.Function.Name)
+			}
+			offered = append(offered, names)
+			lastMessages = req.Messages
+
+			if callCount <= 3 {
+				// Keep calling the expensive tool, plus a free one
+				return &llm.ChatResponse{
+					Message: llm.ChatMessage{
+						Role: llm.RoleAssistant,
+						ToolCalls: []llm.ToolCall{
+							{ID: fmt.Sprintf("exp_%d", callCount), Type: "function", Function: llm.FunctionCall{Name: "expensive", Arguments: `{}`}},
+							{ID: fmt.Sprintf("free_%d", callCount), Type: "function", Function: llm.FunctionCall{Name: "free", Arguments: `{}`}},
+						},
+					},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "best effort answer"},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+
+	executed := map[string]int{}
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			executed[name]++
+			return "result", nil
+		},
+		toolDefs: []llm.ToolDefinition{
+			{Type: "function", Function: llm.FunctionSchema{Name: "expensive"}},
+			{Type: "function", Function: llm.FunctionSchema{Name: "free"}},
+		},
+	}
+
+	executor := NewLLMExecutor(LLMExecutorConfig{
+		Client:     client,
+		Tools:      tools,
+		ToolBudget: 5,
+		ToolCosts:  map[string]float64{"expensive": 2},
+	})
+
+	resp, err := executor.Execute(context.Background(), &a2a.Task{ID: "t"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("research")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Parts[0].Text != "best effort answer" {
+		t.Errorf("response = %q", resp.Parts[0].Text)
+	}
+
+	// Budget of 5 covers two calls at cost 2; the third is blocked
+	if executed["expensive"] != 2 {
+		t.Errorf("expensive executed %d times, want 2", executed["expensive"])
+	}
+	if executed["free"] != 3 {
+		t.Errorf("free executed %d times, want 3", executed["free"])
+	}
+
+	// Once exhausted, the expensive tool is no longer offered
+	if len(offered[2]) != 1 || offered[2][0] != "free" {
+		t.Errorf("third call offered %v, want [free]", offered[2])
+	}
+
+	var blocked *llm.ChatMessage
+	for i := range lastMessages {
+		if lastMessages[i].ToolCallID == "exp_3" {
+			blocked = &lastMessages[i]
+		}
+	}
+	if blocked == nil || !strings.Contains(blocked.Content, "tool budget is exhausted") {
+		t.Errorf("expected blocked tool result for exp_3, got %+v", blocked)
+	}
+}
+
+func TestCapabilityGating_OmitsToolsForModel(t *testing.T) {
+	var gotTools []llm.ToolDefinition
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			gotTools = req.Tools
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "ok"},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	tools := &mockToolExecutor{
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "search"}}},
+	}
+
+	noTools := llm.Capabilities{Streaming: true}
+	executor := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools, Capabilities: &noTools})
+	if _, err := executor.Execute(context.Background(), &a2a.Task{ID: "t"}, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("hi")},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotTools != nil {
+		t.Errorf("tools sent to model without tool support: %v", gotTools)
+	}
+}
+
+func TestImageFilePartsSentToVisionModel(t *testing.T) {
+	var got llm.ChatMessage
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			got = req.Messages[len(req.Messages)-1]
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "a cat"},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	msg := &a2a.Message{
+		Role: a2a.MessageRoleUser,
+		Parts: []a2a.Part{
+			a2a.NewTextPart("what is this?"),
+			a2a.NewFilePart("cat.png", "image/png", []byte("png-bytes")),
+			{Kind: a2a.PartKindFile, File: &a2a.FileContent{MimeType: "image/jpeg", URI: "https://cdn.example.com/dog.jpg"}},
+			a2a.NewFilePart("notes.pdf", "application/pdf", []byte("%PDF")),
+		},
+	}
+
+	vision := llm.Capabilities{Vision: true}
+	executor := NewLLMExecutor(LLMExecutorConfig{Client: client, Capabilities: &vision})
+	if _, err := executor.Execute(context.Background(), &a2a.Task{ID: "t"}, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []llm.ImageContent{
+		{MediaType: "image/png", Data: "cG5nLWJ5dGVz"},
+		{MediaType: "image/jpeg", URL: "https://cdn.example.com/dog.jpg"},
+	}
+	if got.Content != "what is this?" || len(got.Images) != len(want) {
+		t.Fatalf("message = %+v, want the text and two images", got)
+	}
+	for i := range want {
+		if got.Images[i] != want[i] {
+			t.Errorf("image %d = %+v, want %+v", i, got.Images[i], want[i])
+		}
+	}
+
+	noVision := llm.Capabilities{}
+	executor = NewLLMExecutor(LLMExecutorConfig{Client: client, Capabilities: &noVision})
+	if _, err := executor.Execute(context.Background(), &a2a.Task{ID: "t"}, msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Images != nil {
+		t.Errorf("images sent to a model without vision: %+v", got.Images)
+	}
+}
+
+func TestMaxHistoryBoundsReplayedMessages(t *testing.T) {
+	var gotMessages []llm.ChatMessage
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			gotMessages = req.Messages
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "ok"},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	executor := NewLLMExecutor(LLMExecutorConfig{Client: client, SystemPrompt: "sys", MaxHistory: 2})
+
+	task := &a2a.Task{ID: "t"}
+	for _, text := range []string{"one", "two", "three", "four"} {
+		task.History = append(task.History, a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(text)}})
+	}
+	_, err := executor.Execute(context.Background(), task, &a2a.Message{
+		Role:  a2a.MessageRoleUser,
+		Parts: []a2a.Part{a2a.NewTextPart("now")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var contents []string
+	for _, m := range gotMessages {
+		contents = append(contents, m.Content)
+	}
+	if got, want := strings.Join(contents, ","), "sys,three,four,now"; got != want {
+		t.Errorf("messages = %q, want %q", got, want)
+	}
+}
+
+func TestExecute_RecordsUsage(t *testing.T) {
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			// The second call is served by a fallback model
+			resp := &llm.ChatResponse{
+				Model:   "fallback-model",
+				Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "Done"},
+				Usage:   llm.UsageInfo{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+			}
+			if calls == 1 {
+				resp.Model = ""
+				resp.Message = llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+					{ID: "call_1", Type: "function", Function: llm.FunctionCall{Name: "noop", Arguments: `{}`}},
+				}}
+			}
+			return resp, nil
+		},
+	}
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) { return "ok", nil },
+		toolDefs:    []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "noop"}}},
+	}
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools})
+
+	tracker := &UsageTracker{}
+	ctx := WithUsageTracker(context.Background(), tracker)
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	if _, err := exec.Execute(ctx, &a2a.Task{ID: "t1"}, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	model, usage, n := tracker.Totals()
+	if model != "fallback-model" || n != 2 {
+		t.Errorf("model = %q, calls = %d, want fallback-model and 2", model, n)
+	}
+	if usage.PromptTokens != 200 || usage.CompletionTokens != 40 || usage.TotalTokens != 240 {
+		t.Errorf("usage = %+v, want accumulated across both calls", usage)
+	}
+	if s := tracker.Summary(nil); len(s.Models) != 2 || s.Models[0].Model != "test-model" || s.Models[1].Model != "fallback-model" {
+		t.Errorf("models = %+v, want each call charged to the model that served it", s.Models)
+	}
+}
+
+func TestLLMExecutor_ResponseFormat(t *testing.T) {
+	format := &llm.ResponseFormat{
+		Type:   llm.ResponseFormatJSONSchema,
+		Schema: json.RawMessage(`{"type":"object","required":["answer"]}`),
+	}
+	replies := []string{"The answer is 4", `{"answer":4}`}
+	var lastReq *llm.ChatRequest
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			lastReq = req
+			reply := replies[0]
+			replies = replies[1:]
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: reply},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, ResponseFormat: format})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("2+2?")}}
+	resp, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if resp.Parts[0].Text != `{"answer":4}` {
+		t.Errorf("response = %q, want the valid JSON reply", resp.Parts[0].Text)
+	}
+	if lastReq.ResponseFormat != format {
+		t.Error("response format not sent with the request")
+	}
+	retry := lastReq.Messages[len(lastReq.Messages)-1]
+	if retry.Role != llm.RoleUser || !strings.Contains(retry.Content, "not valid JSON") {
+		t.Errorf("retry message = %+v, want a correction naming the JSON error", retry)
+	}
+}
+
+func TestLLMExecutor_ResponseFormatExhausted(t *testing.T) {
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "no json here"},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+
+	exec := NewLLMExecutor(LLMExecutorConfig{
+		Client:         client,
+		MaxIterations:  2,
+		ResponseFormat: &llm.ResponseFormat{Type: llm.ResponseFormatJSONObject},
+	})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	_, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if err == nil || !strings.Contains(err.Error(), "did not return a valid JSON response") {
+		t.Fatalf("Execute error = %v, want invalid JSON error", err)
+	}
+}
+
+func TestContextWindowTrimsOldestHistory(t *testing.T) {
+	var gotMessages []llm.ChatMessage
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			gotMessages = req.Messages
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "ok"},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	var warnings []string
+	hooks := NewHookRegistry()
+	hooks.Register(OnWarning, func(_ context.Context, hctx *HookContext) error {
+		warnings = append(warnings, hctx.Warning)
+		return nil
+	})
+
+	task := &a2a.Task{ID: "t"}
+	for _, prefix := range []string{"h1", "h2", "h3", "h4"} {
+		// ~100 tokens each by the heuristic
+		text := prefix + strings.Repeat(".", 398)
+		task.History = append(task.History, a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(text)}})
+	}
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("now")}}
+
+	tests := []struct {
+		window       int
+		want         string
+		wantWarnings int
+	}{
+		{window: 0, want: "sys,h1,h2,h3,h4,now"},
+		{window: 1000, want: "sys,h1,h2,h3,h4,now"},
+		{window: 250, want: "sys,h3,h4,now", wantWarnings: 1},
+		// Too small for even the latest turn: history goes, the request is still sent
+		{window: 5, want: "sys,now", wantWarnings: 2},
+	}
+	for _, tt := range tests {
+		warnings = nil
+		executor := NewLLMExecutor(LLMExecutorConfig{Client: client, Hooks: hooks, SystemPrompt: "sys", ContextWindow: tt.window})
+		if _, err := executor.Execute(context.Background(), task, msg); err != nil {
+			t.Fatalf("window %d: unexpected error: %v", tt.window, err)
+		}
+
+		var contents []string
+		for _, m := range gotMessages {
+			c := m.Content
+			if len(c) > 3 {
+				c = c[:2]
+			}
+			contents = append(contents, c)
+		}
+		if got := strings.Join(contents, ","); got != tt.want {
+			t.Errorf("window %d: messages = %q, want %q", tt.window, got, tt.want)
+		}
+		if len(warnings) != tt.wantWarnings {
+			t.Errorf("window %d: got %d warnings %v, want %d", tt.window, len(warnings), warnings, tt.wantWarnings)
+		}
+	}
+}
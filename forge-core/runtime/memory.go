@@ -3,6 +3,7 @@ package runtime
 import (
 	"sync"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/llm"
 )
 
@@ -67,29 +68,12 @@ func (m *Memory) Reset() {
 }
 
 // trim removes oldest messages when the total character count exceeds budget.
-// Messages are removed in structural groups to maintain valid sequences:
-//   - An assistant message with tool_calls is always removed together with its
-//     subsequent tool-result messages (they form one atomic group).
-//   - Orphaned tool-result messages at the front are removed as a group.
-//   - A plain user/assistant message is a single-message group.
-//
-// Trimming stops if removing the next group would leave zero messages,
-// preserving at least the last complete group even if it exceeds the budget.
+// Messages are removed in structural groups (see firstGroupLen). Trimming
+// stops if removing the next group would leave zero messages, preserving at
+// least the last complete group even if it exceeds the budget.
 func (m *Memory) trim() {
 	for m.totalChars() > m.maxChars && len(m.messages) > 1 {
-		// Determine the size of the first message group.
-		end := 1
-		if m.messages[0].Role == llm.RoleTool {
-			// Orphaned tool results — remove all contiguous tool messages.
-			for end < len(m.messages) && m.messages[end].Role == llm.RoleTool {
-				end++
-			}
-		} else if len(m.messages[0].ToolCalls) > 0 {
-			// Assistant with tool_calls — include all following tool results.
-			for end < len(m.messages) && m.messages[end].Role == llm.RoleTool {
-				end++
-			}
-		}
+		end := m.firstGroupLen()
 		// Don't remove everything — keep at least one complete group.
 		if end >= len(m.messages) {
 			break
@@ -98,6 +82,63 @@ func (m *Memory) trim() {
 	}
 }
 
+// firstGroupLen returns the size of the oldest message group. Groups keep
+// sequences valid:
+//   - An assistant message with tool_calls is always removed together with its
+//     subsequent tool-result messages (they form one atomic group).
+//   - Orphaned tool-result messages at the front are removed as a group.
+//   - A plain user/assistant message is a single-message group.
+func (m *Memory) firstGroupLen() int {
+	end := 1
+	if m.messages[0].Role == llm.RoleTool || len(m.messages[0].ToolCalls) > 0 {
+		// Orphaned tool results, or assistant tool_calls with their results
+		for end < len(m.messages) && m.messages[end].Role == llm.RoleTool {
+			end++
+		}
+	}
+	return end
+}
+
+// DropOldest removes the oldest message group and reports whether it did.
+// The most recent user message and everything after it are never removed.
+func (m *Memory) DropOldest() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lastUser := -1
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].Role == llm.RoleUser {
+			lastUser = i
+			break
+		}
+	}
+	// Groups never span a user message, so stopping when it is first suffices
+	if len(m.messages) == 0 || lastUser == 0 {
+		return false
+	}
+	end := m.firstGroupLen()
+	if end >= len(m.messages) {
+		return false
+	}
+	m.messages = m.messages[end:]
+	return true
+}
+
+// boundHistory returns the most recent max messages of history, or all of it
+// when max is 0. With keepFirst, the first message is retained as well and
+// counts toward max.
+func boundHistory(history []a2a.Message, max int, keepFirst bool) []a2a.Message {
+	if max <= 0 || len(history) <= max {
+		return history
+	}
+	if !keepFirst || max == 1 {
+		return history[len(history)-max:]
+	}
+	bounded := make([]a2a.Message, 0, max)
+	bounded = append(bounded, history[0])
+	return append(bounded, history[len(history)-(max-1):]...)
+}
+
 func (m *Memory) totalChars() int {
 	total := len(m.systemPrompt)
 	for _, msg := range m.messages {
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/initializ/forge/forge-core/a2a"
 	"github.com/initializ/forge/forge-core/llm"
 )
 
@@ -202,3 +203,63 @@ func TestMemoryReset(t *testing.T) {
 		t.Errorf("expected system message, got role %s", msgs[0].Role)
 	}
 }
+
+func TestBoundHistory(t *testing.T) {
+	var history []a2a.Message
+	for i := 0; i < 6; i++ {
+		history = append(history, a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart(string(rune('a' + i)))}})
+	}
+	texts := func(msgs []a2a.Message) string {
+		var s string
+		for _, m := range msgs {
+			s += m.Parts[0].Text
+		}
+		return s
+	}
+
+	tests := []struct {
+		name      string
+		max       int
+		keepFirst bool
+		want      string
+	}{
+		{"unlimited", 0, false, "abcdef"},
+		{"under cap", 10, false, "abcdef"},
+		{"most recent", 3, false, "def"},
+		{"keep first", 3, true, "aef"},
+		{"keep first cap one", 1, true, "f"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := texts(boundHistory(history, tt.max, tt.keepFirst)); got != tt.want {
+				t.Errorf("boundHistory(max=%d, keepFirst=%v) = %q, want %q", tt.max, tt.keepFirst, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropOldestKeepsLatestUserTurn(t *testing.T) {
+	mem := NewMemory("sys", 0)
+	call := llm.ToolCall{ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "search"}}
+	mem.Append(llm.ChatMessage{Role: llm.RoleUser, Content: "old question"})
+	mem.Append(llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{call}})
+	mem.Append(llm.NewToolResultMessage(call, "result", false))
+	mem.Append(llm.ChatMessage{Role: llm.RoleUser, Content: "new question"})
+	mem.Append(llm.ChatMessage{Role: llm.RoleAssistant, Content: "thinking"})
+
+	if !mem.DropOldest() {
+		t.Fatal("expected the old question to be dropped")
+	}
+	// The assistant tool call goes together with its result
+	if !mem.DropOldest() {
+		t.Fatal("expected the tool call group to be dropped")
+	}
+	if mem.DropOldest() {
+		t.Error("the latest user turn must not be dropped")
+	}
+
+	msgs := mem.Messages()
+	if len(msgs) != 3 || msgs[0].Role != llm.RoleSystem || msgs[1].Content != "new question" {
+		t.Errorf("messages = %+v, want system, new question, thinking", msgs)
+	}
+}
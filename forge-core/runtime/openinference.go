@@ -0,0 +1,247 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenInference semantic convention attribute names used on agent loop spans.
+const (
+	oiSpanKind       = attribute.Key("openinference.span.kind")
+	oiInputValue     = attribute.Key("input.value")
+	oiOutputValue    = attribute.Key("output.value")
+	oiSessionID      = attribute.Key("session.id")
+	oiModelName      = attribute.Key("llm.model_name")
+	oiProvider       = attribute.Key("llm.provider")
+	oiInputMessages  = "llm.input_messages"
+	oiOutputMessages = "llm.output_messages"
+	oiTokensPrompt   = attribute.Key("llm.token_count.prompt")
+	oiTokensOutput   = attribute.Key("llm.token_count.completion")
+	oiTokensTotal    = attribute.Key("llm.token_count.total")
+	oiToolName       = attribute.Key("tool.name")
+)
+
+// OpenInference span kinds.
+const (
+	oiKindAgent = "AGENT"
+	oiKindLLM   = "LLM"
+	oiKindTool  = "TOOL"
+)
+
+// openInference tracks the LLM and tool spans opened by Before hooks until
+// the matching After hook closes them, per parent span.
+type openInference struct {
+	tracer   trace.Tracer
+	model    string
+	provider string
+
+	mu    sync.Mutex
+	llm   map[trace.SpanID]trace.Span
+	tools map[toolSpanKey]trace.Span
+}
+
+type toolSpanKey struct {
+	parent trace.SpanID
+	name   string
+	callID string
+}
+
+// RegisterOpenInferenceHooks records each LLM call and tool execution as an
+// OpenTelemetry span carrying OpenInference attributes. Spans are children
+// of the span in the hook context, normally the AGENT span started by
+// NewTracingExecutor.
+func RegisterOpenInferenceHooks(hooks *HookRegistry, tracer trace.Tracer, provider, model string) {
+	oi := &openInference{
+		tracer:   tracer,
+		model:    model,
+		provider: provider,
+		llm:      make(map[trace.SpanID]trace.Span),
+		tools:    make(map[toolSpanKey]trace.Span),
+	}
+	hooks.Register(BeforeLLMCall, oi.beforeLLM)
+	hooks.Register(AfterLLMCall, oi.afterLLM)
+	hooks.Register(BeforeToolExec, oi.beforeTool)
+	hooks.Register(AfterToolExec, oi.afterTool)
+	hooks.Register(OnError, oi.onError)
+}
+
+func (oi *openInference) beforeLLM(ctx context.Context, hctx *HookContext) error {
+	attrs := []attribute.KeyValue{
+		oiSpanKind.String(oiKindLLM),
+		oiModelName.String(oi.model),
+		oiProvider.String(oi.provider),
+	}
+	for i, m := range hctx.Messages {
+		prefix := fmt.Sprintf("%s.%d.message.", oiInputMessages, i)
+		attrs = append(attrs, attribute.String(prefix+"role", m.Role))
+		if m.Content != "" {
+			attrs = append(attrs, attribute.String(prefix+"content", m.Content))
+		}
+		for j, tc := range m.ToolCalls {
+			tcPrefix := fmt.Sprintf("%stool_calls.%d.tool_call.", prefix, j)
+			attrs = append(attrs,
+				attribute.String(tcPrefix+"function.name", tc.Function.Name),
+				attribute.String(tcPrefix+"function.arguments", tc.Function.Arguments))
+		}
+	}
+	if n := len(hctx.Messages); n > 0 {
+		attrs = append(attrs, oiInputValue.String(hctx.Messages[n-1].Content))
+	}
+	_, span := oi.tracer.Start(ctx, "llm", trace.WithAttributes(attrs...))
+
+	oi.mu.Lock()
+	oi.llm[parentSpanID(ctx)] = span
+	oi.mu.Unlock()
+	return nil
+}
+
+func (oi *openInference) afterLLM(ctx context.Context, hctx *HookContext) error {
+	span := oi.takeLLM(ctx)
+	if span == nil || hctx.Response == nil {
+		return nil
+	}
+	msg := hctx.Response.Message
+	prefix := oiOutputMessages + ".0.message."
+	attrs := []attribute.KeyValue{attribute.String(prefix+"role", msg.Role)}
+	if msg.Content != "" {
+		attrs = append(attrs, attribute.String(prefix+"content", msg.Content))
+	}
+	for j, tc := range msg.ToolCalls {
+		tcPrefix := fmt.Sprintf("%stool_calls.%d.tool_call.", prefix, j)
+		attrs = append(attrs,
+			attribute.String(tcPrefix+"function.name", tc.Function.Name),
+			attribute.String(tcPrefix+"function.arguments", tc.Function.Arguments))
+	}
+	if model := hctx.Response.Model; model != "" {
+		attrs = append(attrs, oiModelName.String(model))
+	}
+	usage := hctx.Response.Usage
+	attrs = append(attrs,
+		oiOutputValue.String(msg.Content),
+		oiTokensPrompt.Int(usage.PromptTokens),
+		oiTokensOutput.Int(usage.CompletionTokens),
+		oiTokensTotal.Int(usage.TotalTokens))
+	endSpan(span, nil, attrs...)
+	return nil
+}
+
+func (oi *openInference) beforeTool(ctx context.Context, hctx *HookContext) error {
+	_, span := oi.tracer.Start(ctx, hctx.ToolName, trace.WithAttributes(
+		oiSpanKind.String(oiKindTool),
+		oiToolName.String(hctx.ToolName),
+		oiInputValue.String(hctx.ToolInput)))
+
+	oi.mu.Lock()
+	oi.tools[toolSpanKey{parentSpanID(ctx), hctx.ToolName, hctx.ToolCallID}] = span
+	oi.mu.Unlock()
+	return nil
+}
+
+func (oi *openInference) afterTool(ctx context.Context, hctx *HookContext) error {
+	key := toolSpanKey{parentSpanID(ctx), hctx.ToolName, hctx.ToolCallID}
+	oi.mu.Lock()
+	span := oi.tools[key]
+	delete(oi.tools, key)
+	oi.mu.Unlock()
+	if span == nil {
+		return nil
+	}
+	endSpan(span, hctx.Error, oiOutputValue.String(hctx.ToolOutput))
+	return nil
+}
+
+// onError closes an LLM span left open by a failed call.
+func (oi *openInference) onError(ctx context.Context, hctx *HookContext) error {
+	if span := oi.takeLLM(ctx); span != nil {
+		endSpan(span, hctx.Error)
+	}
+	return nil
+}
+
+func (oi *openInference) takeLLM(ctx context.Context) trace.Span {
+	parent := parentSpanID(ctx)
+	oi.mu.Lock()
+	defer oi.mu.Unlock()
+	span := oi.llm[parent]
+	delete(oi.llm, parent)
+	return span
+}
+
+// parentSpanID returns the ID of the span in ctx, which keys the spans a
+// Before hook leaves open for its After hook.
+func parentSpanID(ctx context.Context) trace.SpanID {
+	return trace.SpanContextFromContext(ctx).SpanID()
+}
+
+// TracingExecutor wraps an AgentExecutor so each task runs inside an
+// OpenInference AGENT span, which parents the spans recorded by
+// RegisterOpenInferenceHooks.
+type TracingExecutor struct {
+	inner  AgentExecutor
+	tracer trace.Tracer
+	name   string
+}
+
+// NewTracingExecutor creates a TracingExecutor whose spans are named name.
+func NewTracingExecutor(inner AgentExecutor, tracer trace.Tracer, name string) *TracingExecutor {
+	return &TracingExecutor{inner: inner, tracer: tracer, name: name}
+}
+
+func (e *TracingExecutor) start(ctx context.Context, task *a2a.Task, msg *a2a.Message) (context.Context, trace.Span) {
+	return e.tracer.Start(ctx, e.name, trace.WithAttributes(
+		oiSpanKind.String(oiKindAgent),
+		oiSessionID.String(task.ID),
+		oiInputValue.String(a2aMessageToLLM(*msg).Content)))
+}
+
+// Execute runs the inner executor inside an AGENT span.
+func (e *TracingExecutor) Execute(ctx context.Context, task *a2a.Task, msg *a2a.Message) (*a2a.Message, error) {
+	ctx, span := e.start(ctx, task, msg)
+	resp, err := e.inner.Execute(ctx, task, msg)
+	var output []attribute.KeyValue
+	if resp != nil {
+		output = append(output, oiOutputValue.String(a2aMessageToLLM(*resp).Content))
+	}
+	endSpan(span, err, output...)
+	return resp, err
+}
+
+// ExecuteStream runs the inner executor inside an AGENT span that ends when
+// the stream closes.
+func (e *TracingExecutor) ExecuteStream(ctx context.Context, task *a2a.Task, msg *a2a.Message) (<-chan *a2a.Message, error) {
+	ctx, span := e.start(ctx, task, msg)
+	in, err := e.inner.ExecuteStream(ctx, task, msg)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+
+	out := make(chan *a2a.Message, 1)
+	go func() {
+		defer close(out)
+		var parts []string
+		// Keep draining in after the consumer leaves so the inner executor
+		// never blocks
+		for m := range in {
+			// Partial messages are repeated by the complete reply
+			if !a2a.IsPartial(m) {
+				parts = append(parts, a2aMessageToLLM(*m).Content)
+			}
+			select {
+			case out <- m:
+			case <-ctx.Done():
+			}
+		}
+		endSpan(span, nil, oiOutputValue.String(strings.Join(parts, "")))
+	}()
+	return out, nil
+}
+
+// Close closes the inner executor.
+func (e *TracingExecutor) Close() error { return e.inner.Close() }
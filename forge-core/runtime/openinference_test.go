@@ -0,0 +1,145 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestOpenInferenceSpans(t *testing.T) {
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls == 1 {
+				return &llm.ChatResponse{
+					Message: llm.ChatMessage{
+						Role: llm.RoleAssistant,
+						ToolCalls: []llm.ToolCall{{
+							ID: "call_1", Type: "function",
+							Function: llm.FunctionCall{Name: "lookup", Arguments: `{"q":"go"}`},
+						}},
+					},
+					Usage:        llm.UsageInfo{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "Go is a language"},
+				Usage:        llm.UsageInfo{PromptTokens: 20, CompletionTokens: 4, TotalTokens: 24},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			return "lookup result", nil
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "lookup"}}},
+	}
+
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("forge")
+	hooks := NewHookRegistry()
+	RegisterOpenInferenceHooks(hooks, tracer, "openai", "test-model")
+	executor := NewTracingExecutor(NewLLMExecutor(LLMExecutorConfig{
+		Client: client, Tools: tools, Hooks: hooks, SystemPrompt: "be brief",
+	}), tracer, "test-agent")
+
+	task := &a2a.Task{ID: "task-1"}
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("what is go?")}}
+	if _, err := executor.Execute(context.Background(), task, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("got %d spans, want 4 (llm, tool, llm, agent)", len(spans))
+	}
+	firstLLM, tool, secondLLM, agent := spans[0], spans[1], spans[2], spans[3]
+
+	for _, s := range spans[:3] {
+		if s.Parent().SpanID() != agent.SpanContext().SpanID() || s.SpanContext().TraceID() != agent.SpanContext().TraceID() {
+			t.Errorf("span %q is not a child of the agent span", s.Name())
+		}
+	}
+
+	wantAttrs := []struct {
+		span sdktrace.ReadOnlySpan
+		key  string
+		want any
+	}{
+		{agent, "openinference.span.kind", "AGENT"},
+		{agent, "session.id", "task-1"},
+		{agent, "input.value", "what is go?"},
+		{agent, "output.value", "Go is a language"},
+		{firstLLM, "openinference.span.kind", "LLM"},
+		{firstLLM, "llm.model_name", "test-model"},
+		{firstLLM, "llm.provider", "openai"},
+		{firstLLM, "llm.input_messages.0.message.role", "system"},
+		{firstLLM, "llm.input_messages.0.message.content", "be brief"},
+		{firstLLM, "llm.input_messages.1.message.content", "what is go?"},
+		{firstLLM, "llm.output_messages.0.message.tool_calls.0.tool_call.function.name", "lookup"},
+		{firstLLM, "llm.output_messages.0.message.tool_calls.0.tool_call.function.arguments", `{"q":"go"}`},
+		{firstLLM, "llm.token_count.prompt", int64(10)},
+		{firstLLM, "llm.token_count.completion", int64(5)},
+		{firstLLM, "llm.token_count.total", int64(15)},
+		{tool, "openinference.span.kind", "TOOL"},
+		{tool, "tool.name", "lookup"},
+		{tool, "input.value", `{"q":"go"}`},
+		{tool, "output.value", "lookup result"},
+		{secondLLM, "llm.input_messages.3.message.role", "tool"},
+		{secondLLM, "llm.output_messages.0.message.content", "Go is a language"},
+		{secondLLM, "llm.token_count.total", int64(24)},
+	}
+	for _, w := range wantAttrs {
+		if got := spanAttribute(w.span, w.key); got != w.want {
+			t.Errorf("%s span %s = %v, want %v", w.span.Name(), w.key, got, w.want)
+		}
+	}
+}
+
+// spanAttribute returns the value of a span attribute, or nil.
+func spanAttribute(s sdktrace.ReadOnlySpan, key string) any {
+	for _, kv := range s.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value.AsInterface()
+		}
+	}
+	return nil
+}
+
+func TestOpenInferenceSpans_LLMError(t *testing.T) {
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			return nil, context.DeadlineExceeded
+		},
+	}
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("forge")
+	hooks := NewHookRegistry()
+	RegisterOpenInferenceHooks(hooks, tracer, "openai", "gpt-4o")
+	executor := NewTracingExecutor(NewLLMExecutor(LLMExecutorConfig{Client: client, Hooks: hooks}), tracer, "test-agent")
+
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	if _, err := executor.Execute(context.Background(), &a2a.Task{ID: "t"}, msg); err == nil {
+		t.Fatal("expected error")
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if st := spans[0].Status(); st.Code != codes.Error || st.Description != context.DeadlineExceeded.Error() {
+		t.Errorf("llm span status = %v %q", st.Code, st.Description)
+	}
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("agent span status = %v, want error", spans[1].Status().Code)
+	}
+}
@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute names for the spans LLMExecutor records when it has a Tracer,
+// following the OpenTelemetry GenAI semantic conventions where they apply.
+const (
+	otelOperation     = attribute.Key("gen_ai.operation.name")
+	otelModel         = attribute.Key("gen_ai.request.model")
+	otelResponseModel = attribute.Key("gen_ai.response.model")
+	otelInputTokens   = attribute.Key("gen_ai.usage.input_tokens")
+	otelOutputTokens  = attribute.Key("gen_ai.usage.output_tokens")
+	otelFinishReason  = attribute.Key("gen_ai.response.finish_reasons")
+	otelToolName      = attribute.Key("gen_ai.tool.name")
+	otelToolCallID    = attribute.Key("gen_ai.tool.call.id")
+	otelTaskID        = attribute.Key("forge.task.id")
+	otelIteration     = attribute.Key("forge.iteration")
+	otelLLMCalls      = attribute.Key("forge.llm_calls")
+)
+
+// Operation names, which also prefix the span names.
+const (
+	otelOpAgent = "invoke_agent"
+	otelOpChat  = "chat"
+	otelOpTool  = "execute_tool"
+)
+
+// startSpan starts a span for operation op on name, a model or tool name.
+// Without a tracer it returns ctx unchanged and a nil span.
+func (e *LLMExecutor) startSpan(ctx context.Context, op, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if e.tracer == nil {
+		return ctx, nil
+	}
+	attrs = append(attrs, otelOperation.String(op))
+	return e.tracer.Start(ctx, op+" "+name, trace.WithAttributes(attrs...))
+}
+
+// endSpan sets attrs on span, marks it failed when err is set, and ends it.
+// A nil span is a no-op.
+func endSpan(span trace.Span, err error, attrs ...attribute.KeyValue) {
+	if span == nil {
+		return
+	}
+	span.SetAttributes(attrs...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLLMExecutor_TracerSpans(t *testing.T) {
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls == 1 {
+				return &llm.ChatResponse{
+					Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{toolCall("call_1", "lookup")}},
+					Usage:        llm.UsageInfo{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "done"},
+				Usage:        llm.UsageInfo{PromptTokens: 20, CompletionTokens: 4, TotalTokens: 24},
+				FinishReason: "stop",
+			}, nil
+		},
+	}
+	var toolSpan trace.SpanContext
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			toolSpan = trace.SpanFromContext(ctx).SpanContext()
+			return "", errors.New("lookup failed")
+		},
+		toolDefs: []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "lookup"}}},
+	}
+
+	rec := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools, Tracer: provider.Tracer("forge")})
+
+	// The run continues the caller's trace
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	callerID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: callerID, TraceFlags: trace.FlagsSampled, Remote: true,
+	}))
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	if _, err := exec.Execute(ctx, &a2a.Task{ID: "task-1"}, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("got %d spans, want 4 (chat, tool, chat, agent)", len(spans))
+	}
+	first, tool, second, agent := spans[0], spans[1], spans[2], spans[3]
+
+	if agent.SpanContext().TraceID() != traceID {
+		t.Errorf("trace ID = %s, want the caller's %s", agent.SpanContext().TraceID(), traceID)
+	}
+	if agent.Parent().SpanID() != callerID {
+		t.Errorf("agent parent = %s, want the caller's span", agent.Parent().SpanID())
+	}
+	for _, s := range spans[:3] {
+		if s.Parent().SpanID() != agent.SpanContext().SpanID() || s.SpanContext().TraceID() != traceID {
+			t.Errorf("span %q is not a child of the agent span", s.Name())
+		}
+	}
+
+	attrs := func(s sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		m := make(map[attribute.Key]attribute.Value)
+		for _, kv := range s.Attributes() {
+			m[kv.Key] = kv.Value
+		}
+		return m
+	}
+	a := attrs(agent)
+	if agent.Name() != "invoke_agent test-model" || a[otelOperation].AsString() != otelOpAgent || a[otelTaskID].AsString() != "task-1" ||
+		a[otelLLMCalls].AsInt64() != 2 || a[otelInputTokens].AsInt64() != 30 || a[otelOutputTokens].AsInt64() != 9 {
+		t.Errorf("agent span = %s %v", agent.Name(), agent.Attributes())
+	}
+	f := attrs(first)
+	if first.Name() != "chat test-model" || f[otelFinishReason].Emit() != "[\"tool_calls\"]" ||
+		f[otelInputTokens].AsInt64() != 10 || f[otelOutputTokens].AsInt64() != 5 || f[otelIteration].AsInt64() != 1 {
+		t.Errorf("first chat span = %s %v", first.Name(), first.Attributes())
+	}
+	if sec := attrs(second); sec[otelFinishReason].Emit() != "[\"stop\"]" || sec[otelIteration].AsInt64() != 2 {
+		t.Errorf("second chat span = %v", second.Attributes())
+	}
+	ta := attrs(tool)
+	if tool.Name() != "execute_tool lookup" || ta[otelToolName].AsString() != "lookup" || ta[otelToolCallID].AsString() != "call_1" {
+		t.Errorf("tool span = %s %v", tool.Name(), tool.Attributes())
+	}
+	if tool.Status().Code != codes.Error || tool.Status().Description != "lookup failed" {
+		t.Errorf("tool status = %v, want error", tool.Status())
+	}
+	if toolSpan.SpanID() != tool.SpanContext().SpanID() {
+		t.Error("tool did not run in its span's context")
+	}
+}
+
+func TestLLMExecutor_NoTracer(t *testing.T) {
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			if trace.SpanFromContext(ctx).SpanContext().IsValid() {
+				t.Error("span in context without a tracer")
+			}
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "ok"}, FinishReason: "stop"}, nil
+		},
+	}
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	if _, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+}
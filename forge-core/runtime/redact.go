@@ -0,0 +1,181 @@
+package runtime
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/initializ/forge/forge-core/a2a"
+)
+
+// redactedText replaces each redacted match.
+const redactedText = "[REDACTED]"
+
+// defaultRedactWindow is how many bytes of trailing text a StreamRedactor
+// holds back by default. Matches longer than this that also contain
+// whitespace can straddle the emitted boundary.
+const defaultRedactWindow = 256
+
+// redactRule masks matches of pattern with replacement. Rules that cannot
+// be expressed as a single pattern set replace instead.
+type redactRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+	replace     func(text string) string
+}
+
+// defaultRules masks each of patterns with redactedText.
+func defaultRules(patterns []*regexp.Regexp) []redactRule {
+	rules := make([]redactRule, len(patterns))
+	for i, re := range patterns {
+		rules[i] = redactRule{pattern: re, replacement: redactedText}
+	}
+	return rules
+}
+
+// redactText replaces every match of rules in text with the rule's
+// replacement.
+func redactText(text string, rules []redactRule) string {
+	for _, r := range rules {
+		if r.replace != nil {
+			text = r.replace(text)
+			continue
+		}
+		text = r.pattern.ReplaceAllLiteralString(text, r.replacement)
+	}
+	return text
+}
+
+// StreamRedactor redacts patterns from text that arrives in chunks. It holds
+// back the end of the text seen so far and only emits up to a whitespace
+// boundary at least window bytes before the end, so a secret split across
+// two chunks is matched whole before any of it is emitted.
+type StreamRedactor struct {
+	rules   []redactRule
+	window  int
+	pending string
+}
+
+// NewStreamRedactor creates a StreamRedactor that replaces matches of
+// patterns with "[REDACTED]". A window of 0 uses a default of 256 bytes.
+func NewStreamRedactor(patterns []*regexp.Regexp, window int) *StreamRedactor {
+	return newStreamRedactor(defaultRules(patterns), window)
+}
+
+func newStreamRedactor(rules []redactRule, window int) *StreamRedactor {
+	if window <= 0 {
+		window = defaultRedactWindow
+	}
+	return &StreamRedactor{rules: rules, window: window}
+}
+
+// Write adds the next chunk and returns the redacted text that is now safe
+// to emit, which may be empty.
+func (r *StreamRedactor) Write(chunk string) string {
+	r.pending = redactText(r.pending+chunk, r.rules)
+	cut := r.safeCut()
+	out := r.pending[:cut]
+	r.pending = r.pending[cut:]
+	return out
+}
+
+// Flush returns the redacted text still held back. Call it when the stream
+// ends.
+func (r *StreamRedactor) Flush() string {
+	out := redactText(r.pending, r.rules)
+	r.pending = ""
+	return out
+}
+
+// safeCut returns how much of pending can be emitted: everything up to the
+// last whitespace at least window bytes from the end. Without such
+// whitespace the text is held, unless it has grown past four windows, in
+// which case it is cut at the window on a rune boundary.
+func (r *StreamRedactor) safeCut() int {
+	limit := len(r.pending) - r.window
+	if limit <= 0 {
+		return 0
+	}
+	if i := strings.LastIndexFunc(r.pending[:limit], unicode.IsSpace); i >= 0 {
+		_, size := utf8.DecodeRuneInString(r.pending[i:])
+		return i + size
+	}
+	if len(r.pending) <= 4*r.window {
+		return 0
+	}
+	for limit > 0 && !utf8.RuneStart(r.pending[limit]) {
+		limit--
+	}
+	return limit
+}
+
+// RedactStream passes the text parts of each partial message
+// (a2a.MetadataPartial) from in through r and forwards the cleaned messages,
+// treating each as the next piece of the reply. Partial messages whose text
+// is all held back are skipped unless they carry other parts. When in
+// closes, the held-back text is sent as a final message.
+//
+// A normal message is a complete reply and is redacted whole, so each one
+// in gives exactly one out. When it follows partial ones, the held-back
+// partial text is sent first.
+func RedactStream(in <-chan *a2a.Message, r *StreamRedactor) <-chan *a2a.Message {
+	out := make(chan *a2a.Message, cap(in))
+	go func() {
+		defer close(out)
+		role := a2a.MessageRoleAgent
+		var meta map[string]any
+		partials := false
+		for msg := range in {
+			if !a2a.IsPartial(msg) {
+				if partials {
+					if rest := r.Flush(); rest != "" {
+						out <- &a2a.Message{Role: role, Parts: []a2a.Part{a2a.NewTextPart(rest)}, Metadata: meta}
+					}
+					partials = false
+				}
+				out <- redactMessageText(msg, r.rules)
+				continue
+			}
+			partials = true
+			role, meta = msg.Role, msg.Metadata
+			var text strings.Builder
+			var other []a2a.Part
+			for _, p := range msg.Parts {
+				if p.Kind == a2a.PartKindText {
+					text.WriteString(p.Text)
+				} else {
+					other = append(other, p)
+				}
+			}
+			cleaned := r.Write(text.String())
+			if cleaned == "" && len(other) == 0 {
+				continue
+			}
+			redacted := *msg
+			redacted.Parts = nil
+			if cleaned != "" {
+				redacted.Parts = append(redacted.Parts, a2a.NewTextPart(cleaned))
+			}
+			redacted.Parts = append(redacted.Parts, other...)
+			out <- &redacted
+		}
+		if rest := r.Flush(); rest != "" {
+			out <- &a2a.Message{Role: role, Parts: []a2a.Part{a2a.NewTextPart(rest)}, Metadata: meta}
+		}
+	}()
+	return out
+}
+
+// redactMessageText returns a copy of msg with each text part redacted.
+func redactMessageText(msg *a2a.Message, rules []redactRule) *a2a.Message {
+	out := *msg
+	out.Parts = make([]a2a.Part, len(msg.Parts))
+	for i, p := range msg.Parts {
+		if p.Kind == a2a.PartKindText {
+			p.Text = redactText(p.Text, rules)
+		}
+		out.Parts[i] = p
+	}
+	return &out
+}
@@ -0,0 +1,139 @@
+package runtime
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/agentspec"
+)
+
+var testKeyPattern = regexp.MustCompile(`sk-[A-Za-z0-9]{20,}`)
+
+func TestStreamRedactor_SecretSplitAcrossChunks(t *testing.T) {
+	r := NewStreamRedactor([]*regexp.Regexp{testKeyPattern}, 16)
+	chunks := []string{
+		"Here is some harmless preamble text. Your key is sk-abc123",
+		"def456ghi789jkl0 so keep it somewhere safe and never share it with anyone.",
+	}
+
+	var emitted []string
+	for _, c := range chunks {
+		emitted = append(emitted, r.Write(c))
+	}
+	emitted = append(emitted, r.Flush())
+
+	for i, e := range emitted {
+		if strings.Contains(e, "sk-") {
+			t.Errorf("emitted piece %d leaks part of the secret: %q", i, e)
+		}
+	}
+	got := strings.Join(emitted, "")
+	want := "Here is some harmless preamble text. Your key is [REDACTED] so keep it somewhere safe and never share it with anyone."
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if emitted[0] == "" {
+		t.Error("text well before the window should be emitted without waiting for the end")
+	}
+}
+
+func TestStreamRedactor_HoldsUnbrokenText(t *testing.T) {
+	r := NewStreamRedactor([]*regexp.Regexp{testKeyPattern}, 8)
+	// No whitespace to cut at: held until it outgrows four windows
+	if out := r.Write(strings.Repeat("x", 30)); out != "" {
+		t.Errorf("Write = %q, want text held back", out)
+	}
+	out := r.Write(strings.Repeat("é", 10))
+	if out == "" || !strings.HasPrefix(out, "xxx") {
+		t.Errorf("Write = %q, want a forced cut", out)
+	}
+	if rest := r.Flush(); !strings.HasSuffix(out+rest, "é") || len(out+rest) != 30+20 {
+		t.Errorf("output = %q, want all text back on rune boundaries", out+rest)
+	}
+}
+
+func TestRedactStream(t *testing.T) {
+	scaffold := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{
+		{Type: "no_pii", Config: map[string]any{"action": "redact"}},
+	}}
+	g := NewGuardrailEngine(scaffold, true, NewJSONLogger(io.Discard, false))
+	redactor := g.StreamRedactor()
+	if redactor == nil {
+		t.Fatal("expected a stream redactor for a redacting guardrail")
+	}
+
+	in := make(chan *a2a.Message, 3)
+	for _, text := range []string{"Contact jane.doe@exa", "mple.com for access", "."} {
+		in <- &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(text)}, Metadata: map[string]any{a2a.MetadataPartial: true}}
+	}
+	close(in)
+
+	var b strings.Builder
+	for msg := range RedactStream(in, redactor) {
+		for _, p := range msg.Parts {
+			if strings.Contains(p.Text, "@") || strings.Contains(p.Text, "jane") {
+				t.Errorf("chunk leaks the address: %q", p.Text)
+			}
+			b.WriteString(p.Text)
+		}
+	}
+	if got, want := b.String(), "Contact [REDACTED] for access."; got != want {
+		t.Errorf("stream = %q, want %q", got, want)
+	}
+
+	plain := NewGuardrailEngine(&agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{{Type: "no_pii"}}}, true, NewJSONLogger(io.Discard, false))
+	if plain.StreamRedactor() != nil {
+		t.Error("no redactor expected without a redact action")
+	}
+}
+
+func TestRedactStream_PartialsThenFinal(t *testing.T) {
+	redactor := NewStreamRedactor(piiPatterns, 0)
+	partial := func(text string) *a2a.Message {
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(text)}, Metadata: map[string]any{a2a.MetadataPartial: true}}
+	}
+	in := make(chan *a2a.Message, 3)
+	in <- partial("Mail jane.doe@exa")
+	in <- partial("mple.com today")
+	in <- &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("Mail jane.doe@example.com today")}}
+	close(in)
+
+	var streamed strings.Builder
+	var final []string
+	for msg := range RedactStream(in, redactor) {
+		if a2a.IsPartial(msg) {
+			streamed.WriteString(msg.Parts[0].Text)
+		} else {
+			final = append(final, msg.Parts[0].Text)
+		}
+	}
+	if got := streamed.String(); got != "Mail [REDACTED] today" {
+		t.Errorf("partials = %q", got)
+	}
+	if len(final) != 1 || final[0] != "Mail [REDACTED] today" {
+		t.Errorf("final = %q, want the complete reply redacted whole", final)
+	}
+}
+
+func TestRedactStream_SingleFinalMessage(t *testing.T) {
+	redactor := NewStreamRedactor(piiPatterns, 0)
+	text := strings.Repeat("word ", 148) + "mail jane.doe@example.com " + strings.Repeat("more ", 50)
+	in := make(chan *a2a.Message, 1)
+	in <- &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart(text)}}
+	close(in)
+
+	var got []*a2a.Message
+	for msg := range RedactStream(in, redactor) {
+		got = append(got, msg)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d messages, want 1", len(got))
+	}
+	want := strings.Replace(text, "jane.doe@example.com", "[REDACTED]", 1)
+	if len(got[0].Parts) != 1 || got[0].Parts[0].Text != want {
+		t.Errorf("message = %+v, want the reply redacted whole", got[0].Parts)
+	}
+}
@@ -0,0 +1,82 @@
+package runtime
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// defaultRepeatLimit is how many consecutive rounds of identical tool calls
+// are allowed before the model is told it is repeating itself.
+const defaultRepeatLimit = 3
+
+// ErrMaxIterations is returned when the agent loop runs out of iterations.
+var ErrMaxIterations = errors.New("agent loop exceeded maximum iterations")
+
+// ErrToolLoop is returned, with AbortOnRepeat, when the model keeps making
+// the same tool calls after being warned.
+var ErrToolLoop = errors.New("agent is stuck repeating the same tool calls")
+
+// repeatDetector counts consecutive responses that make identical tool
+// calls.
+type repeatDetector struct {
+	last  [sha256.Size]byte
+	count int
+}
+
+// observe records one response's tool calls and returns how many responses
+// in a row, including this one, made exactly these calls. Arguments are
+// compared as JSON, so key order and spacing do not matter.
+func (d *repeatDetector) observe(calls []llm.ToolCall) int {
+	h := sha256.New()
+	for _, tc := range calls {
+		h.Write([]byte(tc.Function.Name))             //nolint:errcheck
+		h.Write([]byte{0})                            //nolint:errcheck
+		h.Write(canonicalArgs(tc.Function.Arguments)) //nolint:errcheck
+		h.Write([]byte{0})                            //nolint:errcheck
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+
+	if d.count > 0 && sum == d.last {
+		d.count++
+	} else {
+		d.last, d.count = sum, 1
+	}
+	return d.count
+}
+
+// canonicalArgs re-encodes JSON arguments with sorted keys and no spacing,
+// falling back to the raw text when they do not parse.
+func canonicalArgs(args string) []byte {
+	var v any
+	if err := json.Unmarshal([]byte(args), &v); err != nil {
+		return bytes.TrimSpace([]byte(args))
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return []byte(args)
+	}
+	return out
+}
+
+// callNames lists the tools called, for repeat messages.
+func callNames(calls []llm.ToolCall) string {
+	names := make([]string, len(calls))
+	for i, tc := range calls {
+		names[i] = tc.Function.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// repeatWarning tells the model it has made the same calls n times in a row.
+func repeatWarning(calls []llm.ToolCall, n int) string {
+	return fmt.Sprintf("You have called %s with the same arguments %d times in a row and received the same kind of result. "+
+		"Do not repeat the call. Use the results you already have, try a different approach, or answer with what you know.",
+		callNames(calls), n)
+}
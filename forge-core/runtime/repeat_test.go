@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func searchCall(args string) []llm.ToolCall {
+	return []llm.ToolCall{{ID: "c", Type: "function", Function: llm.FunctionCall{Name: "search", Arguments: args}}}
+}
+
+func TestRepeatDetector(t *testing.T) {
+	var d repeatDetector
+	steps := []struct {
+		args string
+		want int
+	}{
+		{`{"q":"go","n":1}`, 1},
+		{`{"n": 1, "q": "go"}`, 2}, // same arguments, different key order and spacing
+		{`{"q":"go","n":1}`, 3},
+		{`{"q":"rust","n":1}`, 1}, // different arguments reset the count
+		{`{"q":"rust","n":1}`, 2},
+	}
+	for i, s := range steps {
+		if got := d.observe(searchCall(s.args)); got != s.want {
+			t.Errorf("step %d: observe = %d, want %d", i, got, s.want)
+		}
+	}
+
+	other := []llm.ToolCall{{Function: llm.FunctionCall{Name: "fetch", Arguments: `{"q":"rust","n":1}`}}}
+	if got := d.observe(other); got != 1 {
+		t.Errorf("different tool: observe = %d, want 1", got)
+	}
+}
+
+// repeatingClient always asks for the same search call.
+func repeatingClient(reqs *[]*llm.ChatRequest) *mockLLMClient {
+	return &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			*reqs = append(*reqs, req)
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: searchCall(`{"q":"go"}`)},
+				FinishReason: "tool_calls",
+			}, nil
+		},
+	}
+}
+
+func TestLLMExecutor_RepeatWarning(t *testing.T) {
+	var reqs []*llm.ChatRequest
+	tools := &mockToolExecutor{executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+		return "no results", nil
+	}}
+	var warnings []string
+	hooks := NewHookRegistry()
+	hooks.Register(OnWarning, func(ctx context.Context, hctx *HookContext) error {
+		warnings = append(warnings, hctx.Warning)
+		return nil
+	})
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: repeatingClient(&reqs), Tools: tools, Hooks: hooks, MaxIterations: 4})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("find it")}}
+	_, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if !errors.Is(err, ErrMaxIterations) || errors.Is(err, ErrToolLoop) {
+		t.Fatalf("err = %v, want ErrMaxIterations without AbortOnRepeat", err)
+	}
+
+	// Warned after the third identical round, then again after the fourth
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "search with the same arguments 3 times") {
+		t.Fatalf("warnings = %q", warnings)
+	}
+	msgs := reqs[3].Messages
+	last := msgs[len(msgs)-1]
+	if last.Role != llm.RoleUser || last.Content != warnings[0] {
+		t.Errorf("fourth request should end with the warning, got %+v", last)
+	}
+	for _, m := range reqs[2].Messages {
+		if strings.Contains(m.Content, "times in a row") {
+			t.Error("warned before the repeat limit")
+		}
+	}
+}
+
+func TestLLMExecutor_AbortOnRepeat(t *testing.T) {
+	var reqs []*llm.ChatRequest
+	executed := 0
+	tools := &mockToolExecutor{executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+		executed++
+		return "no results", nil
+	}}
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: repeatingClient(&reqs), Tools: tools, RepeatLimit: 2, AbortOnRepeat: true})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("find it")}}
+	_, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if !errors.Is(err, ErrToolLoop) || errors.Is(err, ErrMaxIterations) {
+		t.Fatalf("err = %v, want ErrToolLoop", err)
+	}
+	if !strings.Contains(err.Error(), "search called with the same arguments 3 times") {
+		t.Errorf("err = %v", err)
+	}
+	if len(reqs) != 3 || executed != 2 {
+		t.Errorf("made %d LLM calls and %d tool calls, want 3 and 2", len(reqs), executed)
+	}
+}
+
+func TestLLMExecutor_RepeatDetectionDisabled(t *testing.T) {
+	var reqs []*llm.ChatRequest
+	tools := &mockToolExecutor{executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+		return "no results", nil
+	}}
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: repeatingClient(&reqs), Tools: tools, RepeatLimit: -1, AbortOnRepeat: true, MaxIterations: 5})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("find it")}}
+	if _, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg); !errors.Is(err, ErrMaxIterations) {
+		t.Fatalf("err = %v, want ErrMaxIterations", err)
+	}
+	for _, m := range reqs[4].Messages {
+		if strings.Contains(m.Content, "times in a row") {
+			t.Fatal("warned with repeat detection disabled")
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package runtime
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of secret reported by DetectSecrets.
+const (
+	SecretAWSAccessKey = "aws_access_key"
+	SecretOpenAIKey    = "openai_key"
+	SecretSlackToken   = "slack_token"
+	SecretGitHubToken  = "github_token"
+	SecretBearerToken  = "bearer_token"
+	SecretHighEntropy  = "high_entropy"
+)
+
+// SecretMatch is a likely credential found in text. Start and End are byte
+// offsets of the secret itself, excluding any prefix such as "Bearer ".
+type SecretMatch struct {
+	Kind  string
+	Start int
+	End   int
+	Value string
+}
+
+// secretPattern recognizes one kind of secret. When the pattern has a
+// capture group, only the group is the secret.
+type secretPattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+var secretPatterns = []secretPattern{
+	{SecretAWSAccessKey, regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{SecretOpenAIKey, regexp.MustCompile(`\bsk-(?:proj-|svcacct-)?[A-Za-z0-9_\-]{20,}`)},
+	{SecretSlackToken, regexp.MustCompile(`\b(?:xox[abpors]|xapp)-[A-Za-z0-9\-]{10,}`)},
+	{SecretGitHubToken, regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{SecretBearerToken, regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]{16,}=*)`)},
+}
+
+// entropyCandidate matches long runs of token characters that may be
+// random secrets.
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_\-]{32,}={0,2}`)
+
+// minSecretEntropy is the Shannon entropy, in bits per character, above
+// which a candidate counts as random. Hex digests top out at 4 bits, so git
+// SHAs and checksums stay below it.
+const minSecretEntropy = 4.2
+
+// DetectSecrets returns the likely credentials in text, ordered by
+// position: AWS access keys, OpenAI "sk-" keys, Slack and GitHub tokens,
+// bearer tokens, and long high-entropy strings mixing upper case, lower case,
+// and digits. Matches do not overlap.
+func DetectSecrets(text string) []SecretMatch {
+	var matches []SecretMatch
+	overlaps := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.End && m.Start < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, p := range secretPatterns {
+		for _, loc := range p.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) > 2 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			if !overlaps(start, end) {
+				matches = append(matches, SecretMatch{Kind: p.kind, Start: start, End: end, Value: text[start:end]})
+			}
+		}
+	}
+	for _, loc := range entropyCandidate.FindAllStringIndex(text, -1) {
+		s := text[loc[0]:loc[1]]
+		if !overlaps(loc[0], loc[1]) && mixedClasses(s) && shannonEntropy(s) >= minSecretEntropy {
+			matches = append(matches, SecretMatch{Kind: SecretHighEntropy, Start: loc[0], End: loc[1], Value: s})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	return matches
+}
+
+// redactSecrets replaces each secret in text with replacement.
+func redactSecrets(text, replacement string) string {
+	matches := DetectSecrets(text)
+	if len(matches) == 0 {
+		return text
+	}
+	var b strings.Builder
+	prev := 0
+	for _, m := range matches {
+		b.WriteString(text[prev:m.Start])
+		b.WriteString(replacement)
+		prev = m.End
+	}
+	b.WriteString(text[prev:])
+	return b.String()
+}
+
+// mixedClasses reports whether s has upper case, lower case, and digits.
+func mixedClasses(s string) bool {
+	var upper, lower, digit bool
+	for _, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9':
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, c := range s {
+		counts[c]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,42 @@
+package runtime
+
+import "context"
+
+// Status event types emitted by the agent loop.
+const (
+	StatusLLMCall   = "llm_call"
+	StatusReasoning = "reasoning"
+	StatusToolStart = "tool_start"
+	StatusToolEnd   = "tool_end"
+)
+
+// StatusEvent describes an intermediate step of the agent loop. Status events
+// go to a side channel so the user-facing response carries only the final answer.
+type StatusEvent struct {
+	Type       string `json:"type"`
+	Iteration  int    `json:"iteration"`
+	ToolName   string `json:"tool_name,omitempty"`
+	ToolInput  string `json:"tool_input,omitempty"`
+	ToolOutput string `json:"tool_output,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// StatusFunc receives status events from the agent loop. With
+// ParallelTools it may be called from several goroutines at once.
+type StatusFunc func(StatusEvent)
+
+type statusKey struct{}
+
+// WithStatusFunc returns a context that delivers agent loop status events to fn.
+func WithStatusFunc(ctx context.Context, fn StatusFunc) context.Context {
+	return context.WithValue(ctx, statusKey{}, fn)
+}
+
+// EmitStatus sends ev to the status function in ctx, if any. Executors other
+// than LLMExecutor may call it to report their own progress.
+func EmitStatus(ctx context.Context, ev StatusEvent) {
+	if fn, ok := ctx.Value(statusKey{}).(StatusFunc); ok && fn != nil {
+		fn(ev)
+	}
+}
@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// chat sends req to the model, streaming the response through emit when it
+// is set. Models without streaming get a plain call whose text is emitted
+// in one piece.
+func (e *LLMExecutor) chat(ctx context.Context, req *llm.ChatRequest, emit func(*a2a.Message)) (*llm.ChatResponse, error) {
+	if emit == nil {
+		return e.client.Chat(ctx, req)
+	}
+	onText := func(text string) {
+		emit(&a2a.Message{
+			Role:     a2a.MessageRoleAgent,
+			Parts:    []a2a.Part{a2a.NewTextPart(text)},
+			Metadata: map[string]any{a2a.MetadataPartial: true},
+		})
+	}
+	if !e.caps.Streaming {
+		resp, err := e.client.Chat(ctx, req)
+		if err == nil && resp.Message.Content != "" {
+			onText(resp.Message.Content)
+		}
+		return resp, err
+	}
+	return streamChat(ctx, e.client, req, onText)
+}
+
+// streamChat calls client.ChatStream and assembles the deltas into a
+// response, passing each piece of text to onText as it arrives. Tool calls
+// are only returned once the stream has ended and they are complete.
+func streamChat(ctx context.Context, client llm.Client, req *llm.ChatRequest, onText func(string)) (*llm.ChatResponse, error) {
+	ch, err := client.ChatStream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &llm.ChatResponse{Model: client.ModelID(), Message: llm.ChatMessage{Role: llm.RoleAssistant}}
+	var content strings.Builder
+	var calls toolCallAccumulator
+	for d := range ch {
+		if d.Model != "" {
+			resp.Model = d.Model
+		}
+		if d.Content != "" {
+			content.WriteString(d.Content)
+			onText(d.Content)
+		}
+		calls.add(d.ToolCalls)
+		if d.FinishReason != "" {
+			resp.FinishReason = d.FinishReason
+		}
+		if d.Usage != nil {
+			resp.Usage = *d.Usage
+		}
+		if d.Err != nil {
+			err = d.Err
+		}
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Message.Content = content.String()
+	resp.Message.ToolCalls = calls.calls
+	return resp, nil
+}
+
+// toolCallAccumulator assembles tool calls from stream deltas. Some
+// providers send each call whole; OpenAI-compatible ones send a first
+// fragment with the call's ID and name followed by fragments of its
+// arguments. A fragment with an ID starts a new call and one without
+// continues the last.
+type toolCallAccumulator struct {
+	calls []llm.ToolCall
+}
+
+func (a *toolCallAccumulator) add(fragments []llm.ToolCall) {
+	for _, f := range fragments {
+		n := len(a.calls)
+		if f.ID != "" || n == 0 {
+			if f.Type == "" {
+				f.Type = "function"
+			}
+			a.calls = append(a.calls, f)
+			continue
+		}
+		last := &a.calls[n-1]
+		last.Function.Name += f.Function.Name
+		last.Function.Arguments += f.Function.Arguments
+	}
+}
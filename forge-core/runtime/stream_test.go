@@ -0,0 +1,166 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// deltas returns a closed channel holding ds.
+func deltas(ds ...llm.StreamDelta) <-chan llm.StreamDelta {
+	ch := make(chan llm.StreamDelta, len(ds))
+	for _, d := range ds {
+		ch <- d
+	}
+	close(ch)
+	return ch
+}
+
+func TestToolCallAccumulator(t *testing.T) {
+	var a toolCallAccumulator
+	// OpenAI-style fragments for two calls, then a whole call
+	a.add([]llm.ToolCall{{ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "search"}}})
+	a.add([]llm.ToolCall{{Function: llm.FunctionCall{Arguments: `{"q":`}}})
+	a.add([]llm.ToolCall{{Function: llm.FunctionCall{Arguments: `"go"}`}}})
+	a.add([]llm.ToolCall{{ID: "c2", Function: llm.FunctionCall{Name: "fetch", Arguments: `{"url"`}}})
+	a.add([]llm.ToolCall{{Function: llm.FunctionCall{Arguments: `:"x"}`}}})
+	a.add([]llm.ToolCall{{ID: "c3", Type: "function", Function: llm.FunctionCall{Name: "time", Arguments: `{}`}}})
+
+	want := []llm.ToolCall{
+		{ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "search", Arguments: `{"q":"go"}`}},
+		{ID: "c2", Type: "function", Function: llm.FunctionCall{Name: "fetch", Arguments: `{"url":"x"}`}},
+		{ID: "c3", Type: "function", Function: llm.FunctionCall{Name: "time", Arguments: `{}`}},
+	}
+	if len(a.calls) != len(want) {
+		t.Fatalf("got %d calls, want %d: %+v", len(a.calls), len(want), a.calls)
+	}
+	for i := range want {
+		if a.calls[i] != want[i] {
+			t.Errorf("call %d = %+v, want %+v", i, a.calls[i], want[i])
+		}
+	}
+}
+
+func TestLLMExecutor_ExecuteStream(t *testing.T) {
+	turn := 0
+	client := &mockLLMClient{
+		streamFunc: func(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+			turn++
+			if turn == 1 {
+				return deltas(
+					llm.StreamDelta{Content: "Let me check. "},
+					llm.StreamDelta{ToolCalls: []llm.ToolCall{{ID: "c1", Type: "function", Function: llm.FunctionCall{Name: "search"}}}},
+					llm.StreamDelta{ToolCalls: []llm.ToolCall{{Function: llm.FunctionCall{Arguments: `{"q":`}}}},
+					llm.StreamDelta{ToolCalls: []llm.ToolCall{{Function: llm.FunctionCall{Arguments: `"go"}`}}}},
+					llm.StreamDelta{FinishReason: "tool_calls"},
+					llm.StreamDelta{Done: true},
+				), nil
+			}
+			if last := req.Messages[len(req.Messages)-1]; last.Role != llm.RoleTool || last.Content != "results" {
+				return nil, fmt.Errorf("second turn should follow the tool result, got %+v", last)
+			}
+			return deltas(
+				llm.StreamDelta{Content: "Go "},
+				llm.StreamDelta{Content: "is "},
+				llm.StreamDelta{Content: "great."},
+				llm.StreamDelta{FinishReason: "stop", Usage: &llm.UsageInfo{TotalTokens: 9}},
+				llm.StreamDelta{Done: true},
+			), nil
+		},
+	}
+	var executedWith string
+	tools := &mockToolExecutor{executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+		executedWith = name + string(arguments)
+		return "results", nil
+	}}
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("tell me about go")}}
+	ch, err := exec.ExecuteStream(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if err != nil {
+		t.Fatalf("ExecuteStream: %v", err)
+	}
+
+	var partials []string
+	var final []*a2a.Message
+	for m := range ch {
+		if a2a.IsPartial(m) {
+			partials = append(partials, m.Parts[0].Text)
+		} else {
+			final = append(final, m)
+		}
+	}
+
+	if executedWith != `search{"q":"go"}` {
+		t.Errorf("tool executed as %q, want the assembled call", executedWith)
+	}
+	if got := strings.Join(partials, "|"); got != "Let me check. |Go |is |great." {
+		t.Errorf("partials = %q", got)
+	}
+	if len(final) != 1 || final[0].Parts[0].Text != "Go is great." {
+		t.Fatalf("final messages = %+v, want one complete reply", final)
+	}
+}
+
+func TestLLMExecutor_ExecuteStreamError(t *testing.T) {
+	client := &mockLLMClient{
+		streamFunc: func(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+			return deltas(
+				llm.StreamDelta{Content: "Partial an"},
+				llm.StreamDelta{Done: true, Err: context.DeadlineExceeded},
+			), nil
+		},
+	}
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	ch, err := exec.ExecuteStream(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if err != nil {
+		t.Fatalf("ExecuteStream: %v", err)
+	}
+	var last *a2a.Message
+	for m := range ch {
+		last = m
+	}
+	if last == nil || a2a.IsPartial(last) || !strings.HasPrefix(last.Parts[0].Text, "Error:") {
+		t.Errorf("last message = %+v, want an error reply", last)
+	}
+}
+
+func TestLLMExecutor_ExecuteStreamWithoutStreaming(t *testing.T) {
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, Content: "All at once."},
+				FinishReason: "stop",
+			}, nil
+		},
+		streamFunc: func(ctx context.Context, req *llm.ChatRequest) (<-chan llm.StreamDelta, error) {
+			return nil, fmt.Errorf("ChatStream called for a model without streaming")
+		},
+	}
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Capabilities: &llm.Capabilities{}})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	ch, err := exec.ExecuteStream(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if err != nil {
+		t.Fatalf("ExecuteStream: %v", err)
+	}
+	var partials, final []string
+	for m := range ch {
+		if a2a.IsPartial(m) {
+			partials = append(partials, m.Parts[0].Text)
+		} else {
+			final = append(final, m.Parts[0].Text)
+		}
+	}
+	if len(partials) != 1 || partials[0] != "All at once." {
+		t.Errorf("partials = %q, want the whole reply once", partials)
+	}
+	if len(final) != 1 || final[0] != "All at once." {
+		t.Errorf("final = %q", final)
+	}
+}
@@ -0,0 +1,247 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// maxParallelTools bounds how many tool calls run at once with ParallelTools.
+const maxParallelTools = 8
+
+// maxToolResultChars truncates oversized tool results to avoid LLM API
+// errors. It sits below maxMessageChars so the suffix fits within the memory
+// cap (~12K tokens).
+const maxToolResultChars = 49_000
+
+// toolOutcome is the result of one tool call, tagged with the call's
+// position in the model's response.
+type toolOutcome struct {
+	index   int
+	call    llm.ToolCall
+	result  string
+	isError bool
+}
+
+// dedupToolCalls drops calls that repeat an earlier call's ID. Providers
+// reject a conversation that answers the same ID twice. Calls without an ID
+// are kept.
+func dedupToolCalls(calls []llm.ToolCall) []llm.ToolCall {
+	seen := make(map[string]bool, len(calls))
+	out := calls[:0:0]
+	for _, tc := range calls {
+		if tc.ID != "" {
+			if seen[tc.ID] {
+				continue
+			}
+			seen[tc.ID] = true
+		}
+		out = append(out, tc)
+	}
+	return out
+}
+
+// inRequestOrder sorts outcomes into the order the model requested the calls,
+// whatever order they completed in, so prompts and transcripts are
+// reproducible.
+func inRequestOrder(outcomes []toolOutcome) []toolOutcome {
+	sorted := append([]toolOutcome(nil), outcomes...)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].index < sorted[b].index })
+	return sorted
+}
+
+// executeTools runs the tool calls of one LLM response, one at a time or,
+// with ParallelTools, concurrently. The budget is checked in request order
+// before anything runs, so both modes reject the same calls. A failing tool
+// only produces an error result; a hook error aborts the loop once every
+// running call has finished.
+func (e *LLMExecutor) executeTools(ctx context.Context, iter int, calls []llm.ToolCall, budget *toolBudget) ([]toolOutcome, error) {
+	outcomes := make([]toolOutcome, 0, len(calls))
+	var runnable []int
+	for idx, tc := range calls {
+		if !budget.allows(tc.Function.Name) {
+			outcomes = append(outcomes, toolOutcome{index: idx, call: tc, result: budget.exhaustedMessage(tc.Function.Name), isError: true})
+			continue
+		}
+		budget.charge(tc.Function.Name)
+		runnable = append(runnable, idx)
+	}
+
+	if !e.parallel || len(runnable) < 2 {
+		for _, idx := range runnable {
+			o, err := e.runTool(ctx, iter, idx, calls[idx])
+			if err != nil {
+				return nil, err
+			}
+			outcomes = append(outcomes, o)
+		}
+		return outcomes, nil
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		errIdx   int
+	)
+	sem := make(chan struct{}, maxParallelTools)
+	for _, idx := range runnable {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			o, err := e.runTool(ctx, iter, idx, calls[idx])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// Report the earliest call's error, as a sequential run would
+				if firstErr == nil || idx < errIdx {
+					firstErr, errIdx = err, idx
+				}
+				return
+			}
+			outcomes = append(outcomes, o)
+		}(idx)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return outcomes, nil
+}
+
+// inputRedactor is implemented by tool executors, such as tools.Registry,
+// that can redact secrets from a tool call's input.
+type inputRedactor interface {
+	RedactInput(name, arguments string) string
+}
+
+// cachedExecutor is implemented by tool executors, such as tools.Registry,
+// that can serve results from a cache and report when they did.
+type cachedExecutor interface {
+	ExecuteCached(ctx context.Context, name string, arguments json.RawMessage) (string, bool, error)
+}
+
+// shownInput returns tc's arguments as hooks, status events, and transcripts
+// show them; the tool itself receives the originals.
+func (e *LLMExecutor) shownInput(tc llm.ToolCall) string {
+	if r, ok := e.tools.(inputRedactor); ok {
+		return r.RedactInput(tc.Function.Name, tc.Function.Arguments)
+	}
+	return tc.Function.Arguments
+}
+
+// shownToolCalls returns a copy of calls with shownInput arguments.
+func (e *LLMExecutor) shownToolCalls(calls []llm.ToolCall) []llm.ToolCall {
+	if len(calls) == 0 {
+		return calls
+	}
+	shown := make([]llm.ToolCall, len(calls))
+	for i, tc := range calls {
+		tc.Function.Arguments = e.shownInput(tc)
+		shown[i] = tc
+	}
+	return shown
+}
+
+// shownMessages returns a copy of msgs whose tool calls have shownInput
+// arguments, for hooks and transcripts.
+func (e *LLMExecutor) shownMessages(msgs []llm.ChatMessage) []llm.ChatMessage {
+	shown := make([]llm.ChatMessage, len(msgs))
+	for i, m := range msgs {
+		m.ToolCalls = e.shownToolCalls(m.ToolCalls)
+		shown[i] = m
+	}
+	return shown
+}
+
+// shownResponse returns a copy of resp whose tool calls have shownInput
+// arguments.
+func (e *LLMExecutor) shownResponse(resp *llm.ChatResponse) *llm.ChatResponse {
+	shown := *resp
+	shown.Message.ToolCalls = e.shownToolCalls(resp.Message.ToolCalls)
+	return &shown
+}
+
+// runTool executes one tool call between its BeforeToolExec and
+// AfterToolExec hooks, unless a ToolApproval hook denies it. The returned
+// error is a hook error; tool errors and denials are carried in the outcome.
+func (e *LLMExecutor) runTool(ctx context.Context, iter, idx int, tc llm.ToolCall) (toolOutcome, error) {
+	shown := e.shownInput(tc)
+	if err := e.hooks.Fire(ctx, ToolApproval, &HookContext{
+		ToolName:   tc.Function.Name,
+		ToolInput:  shown,
+		ToolCallID: tc.ID,
+	}); err != nil {
+		if !errors.Is(err, ErrToolDenied) {
+			return toolOutcome{}, fmt.Errorf("tool approval hook: %w", err)
+		}
+		return toolOutcome{index: idx, call: tc, result: err.Error(), isError: true}, nil
+	}
+
+	if err := e.hooks.Fire(ctx, BeforeToolExec, &HookContext{
+		ToolName:   tc.Function.Name,
+		ToolInput:  shown,
+		ToolCallID: tc.ID,
+	}); err != nil {
+		return toolOutcome{}, fmt.Errorf("before tool exec hook: %w", err)
+	}
+
+	EmitStatus(ctx, StatusEvent{
+		Type:      StatusToolStart,
+		Iteration: iter,
+		ToolName:  tc.Function.Name,
+		ToolInput: shown,
+	})
+
+	toolCtx, span := e.startSpan(ctx, otelOpTool, tc.Function.Name,
+		otelToolName.String(tc.Function.Name), otelToolCallID.String(tc.ID), otelIteration.Int(iter))
+	var (
+		result   string
+		cacheHit bool
+		execErr  error
+	)
+	if ce, ok := e.tools.(cachedExecutor); ok {
+		result, cacheHit, execErr = ce.ExecuteCached(toolCtx, tc.Function.Name, json.RawMessage(tc.Function.Arguments))
+	} else {
+		result, execErr = e.tools.Execute(toolCtx, tc.Function.Name, json.RawMessage(tc.Function.Arguments))
+	}
+	endSpan(span, execErr)
+	if execErr != nil {
+		result = fmt.Sprintf("Error executing tool %s: %s", tc.Function.Name, execErr.Error())
+	}
+	if len(result) > maxToolResultChars {
+		result = result[:maxToolResultChars] + "\n\n[OUTPUT TRUNCATED — original length: " + strconv.Itoa(len(result)) + " chars]"
+	}
+
+	endEv := StatusEvent{
+		Type:       StatusToolEnd,
+		Iteration:  iter,
+		ToolName:   tc.Function.Name,
+		ToolOutput: result,
+	}
+	if execErr != nil {
+		endEv.Error = execErr.Error()
+	}
+	EmitStatus(ctx, endEv)
+
+	if err := e.hooks.Fire(ctx, AfterToolExec, &HookContext{
+		ToolName:   tc.Function.Name,
+		ToolInput:  shown,
+		ToolOutput: result,
+		ToolCallID: tc.ID,
+		Error:      execErr,
+		CacheHit:   cacheHit,
+	}); err != nil {
+		return toolOutcome{}, fmt.Errorf("after tool exec hook: %w", err)
+	}
+
+	return toolOutcome{index: idx, call: tc, result: result, isError: execErr != nil}, nil
+}
@@ -0,0 +1,470 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func toolCall(id, name string) llm.ToolCall {
+	return llm.ToolCall{ID: id, Type: "function", Function: llm.FunctionCall{Name: name, Arguments: `{}`}}
+}
+
+func TestInRequestOrder(t *testing.T) {
+	// Outcomes as they might arrive from tools finishing out of order
+	completed := []toolOutcome{
+		{index: 2, call: toolCall("c3", "slow"), result: "third"},
+		{index: 0, call: toolCall("c1", "fast"), result: "first"},
+		{index: 1, call: toolCall("c2", "medium"), result: "second"},
+	}
+
+	got := inRequestOrder(completed)
+	for i, want := range []string{"first", "second", "third"} {
+		if got[i].result != want {
+			t.Errorf("outcome %d = %q, want %q", i, got[i].result, want)
+		}
+	}
+	if completed[0].index != 2 {
+		t.Error("inRequestOrder modified its input")
+	}
+}
+
+func TestDedupToolCalls(t *testing.T) {
+	calls := []llm.ToolCall{toolCall("a", "x"), toolCall("b", "y"), toolCall("a", "x"), toolCall("", "z"), toolCall("", "z")}
+	got := dedupToolCalls(calls)
+	var ids []string
+	for _, tc := range got {
+		ids = append(ids, tc.ID+tc.Function.Name)
+	}
+	want := []string{"ax", "by", "z", "z"}
+	if len(ids) != len(want) {
+		t.Fatalf("got %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("got %v, want %v", ids, want)
+		}
+	}
+	if len(calls) != 5 || calls[2].ID != "a" {
+		t.Error("dedupToolCalls modified its input")
+	}
+}
+
+func TestLLMExecutor_ToolResultsInRequestOrder(t *testing.T) {
+	var secondReq *llm.ChatRequest
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls == 1 {
+				return &llm.ChatResponse{
+					Message: llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+						toolCall("c1", "alpha"), toolCall("c2", "beta"), toolCall("c1", "alpha"), toolCall("c3", "gamma"),
+					}},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			secondReq = req
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "done"}, FinishReason: "stop"}, nil
+		},
+	}
+	executed := 0
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			executed++
+			return name + " result", nil
+		},
+	}
+
+	transcript := &Transcript{}
+	ctx := WithTranscript(context.Background(), transcript)
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("go")}}
+	if _, err := exec.Execute(ctx, &a2a.Task{ID: "t"}, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if executed != 3 {
+		t.Errorf("executed %d tools, want 3 (duplicate ID skipped)", executed)
+	}
+
+	// user, assistant with 3 calls, then one result per call in request order
+	msgs := secondReq.Messages
+	if len(msgs) != 5 {
+		t.Fatalf("got %d messages, want 5", len(msgs))
+	}
+	if n := len(msgs[1].ToolCalls); n != 3 {
+		t.Errorf("assistant message has %d tool calls, want 3", n)
+	}
+	for i, id := range []string{"c1", "c2", "c3"} {
+		if got := msgs[2+i].ToolCallID; got != id {
+			t.Errorf("tool result %d answers %q, want %q", i, got, id)
+		}
+	}
+
+	entries := transcript.Entries()
+	wantTypes := []string{TranscriptLLMCall, TranscriptToolCall, TranscriptToolCall, TranscriptToolCall, TranscriptLLMCall}
+	if len(entries) != len(wantTypes) {
+		t.Fatalf("got %d transcript entries, want %d", len(entries), len(wantTypes))
+	}
+	for i, want := range wantTypes {
+		if entries[i].Type != want {
+			t.Errorf("entry %d type = %q, want %q", i, entries[i].Type, want)
+		}
+	}
+	for i, id := range []string{"c1", "c2", "c3"} {
+		if e := entries[1+i]; e.ToolCallID != id || e.Iteration != 1 {
+			t.Errorf("tool entry %d = %+v, want %s in iteration 1", i, e, id)
+		}
+	}
+	if entries[4].Content != "done" || entries[4].Iteration != 2 {
+		t.Errorf("final entry = %+v", entries[4])
+	}
+}
+
+func TestLLMExecutor_ParallelTools(t *testing.T) {
+	var secondReq *llm.ChatRequest
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls == 1 {
+				return &llm.ChatResponse{
+					Message: llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+						toolCall("c1", "alpha"), toolCall("c2", "beta"), toolCall("c3", "gamma"),
+					}},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			secondReq = req
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "done"}, FinishReason: "stop"}, nil
+		},
+	}
+
+	// Every call waits until all three are running, so a sequential loop
+	// would time out; they then finish in reverse order
+	var started sync.WaitGroup
+	started.Add(3)
+	allStarted := make(chan struct{})
+	go func() { started.Wait(); close(allStarted) }()
+	delay := map[string]time.Duration{"alpha": 40 * time.Millisecond, "beta": 20 * time.Millisecond}
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			started.Done()
+			select {
+			case <-allStarted:
+			case <-time.After(2 * time.Second):
+				return "", fmt.Errorf("%s ran alone", name)
+			}
+			time.Sleep(delay[name])
+			if name == "beta" {
+				return "", fmt.Errorf("beta failed")
+			}
+			return name + " result", nil
+		},
+	}
+
+	var mu sync.Mutex
+	fired := map[string]int{}
+	hooks := NewHookRegistry()
+	for _, point := range []HookPoint{BeforeToolExec, AfterToolExec} {
+		hooks.Register(point, func(ctx context.Context, hctx *HookContext) error {
+			mu.Lock()
+			defer mu.Unlock()
+			fired[hctx.ToolCallID]++
+			return nil
+		})
+	}
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools, Hooks: hooks, ParallelTools: true})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("go")}}
+	if _, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	for _, id := range []string{"c1", "c2", "c3"} {
+		if fired[id] != 2 {
+			t.Errorf("hooks fired %d times for %s, want 2", fired[id], id)
+		}
+	}
+
+	msgs := secondReq.Messages
+	if len(msgs) != 5 {
+		t.Fatalf("got %d messages, want 5", len(msgs))
+	}
+	want := []struct{ id, content string }{
+		{"c1", "alpha result"},
+		{"c2", "Error executing tool beta: beta failed"},
+		{"c3", "gamma result"},
+	}
+	for i, w := range want {
+		m := msgs[2+i]
+		if m.ToolCallID != w.id || !strings.Contains(m.Content, w.content) {
+			t.Errorf("tool result %d = %s %q, want %s %q", i, m.ToolCallID, m.Content, w.id, w.content)
+		}
+	}
+}
+
+func TestLLMExecutor_ParallelToolsHookError(t *testing.T) {
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			return &llm.ChatResponse{
+				Message: llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{
+					toolCall("c1", "alpha"), toolCall("c2", "beta"), toolCall("c3", "gamma"),
+				}},
+				FinishReason: "tool_calls",
+			}, nil
+		},
+	}
+	var mu sync.Mutex
+	executed := 0
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			executed++
+			return "ok", nil
+		},
+	}
+	hooks := NewHookRegistry()
+	hooks.Register(BeforeToolExec, func(ctx context.Context, hctx *HookContext) error {
+		if hctx.ToolName != "alpha" {
+			return fmt.Errorf("%s blocked", hctx.ToolName)
+		}
+		return nil
+	})
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools, Hooks: hooks, ParallelTools: true})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("go")}}
+	_, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if err == nil || !strings.Contains(err.Error(), "beta blocked") {
+		t.Fatalf("err = %v, want the first call's hook error", err)
+	}
+	if executed != 1 {
+		t.Errorf("executed %d tools, want 1", executed)
+	}
+}
+
+func TestLLMExecutor_ToolApproval(t *testing.T) {
+	var secondReq *llm.ChatRequest
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls == 1 {
+				post := toolCall("c2", "http_request")
+				post.Function.Arguments = `{"method":"POST"}`
+				return &llm.ChatResponse{
+					Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{toolCall("c1", "http_request"), post}},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			secondReq = req
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "done"}, FinishReason: "stop"}, nil
+		},
+	}
+	var executed []string
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			executed = append(executed, string(arguments))
+			return "ok", nil
+		},
+	}
+	var before []string
+	hooks := NewHookRegistry()
+	hooks.Register(ToolApproval, func(ctx context.Context, hctx *HookContext) error {
+		if strings.Contains(hctx.ToolInput, "POST") {
+			return fmt.Errorf("%w: POST requires approval", ErrToolDenied)
+		}
+		return nil
+	})
+	hooks.Register(BeforeToolExec, func(ctx context.Context, hctx *HookContext) error {
+		before = append(before, hctx.ToolCallID)
+		return nil
+	})
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools, Hooks: hooks})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("go")}}
+	if _, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if len(executed) != 1 || executed[0] != `{}` {
+		t.Errorf("executed = %v, want only the approved call", executed)
+	}
+	if len(before) != 1 || before[0] != "c1" {
+		t.Errorf("BeforeToolExec fired for %v, want [c1]", before)
+	}
+	denied := secondReq.Messages[3]
+	if denied.ToolCallID != "c2" || denied.Content != "tool call denied by policy: POST requires approval" {
+		t.Errorf("denied result = %+v", denied)
+	}
+}
+
+func TestLLMExecutor_ToolApprovalHookError(t *testing.T) {
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			return &llm.ChatResponse{
+				Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{toolCall("c1", "alpha")}},
+				FinishReason: "tool_calls",
+			}, nil
+		},
+	}
+	hooks := NewHookRegistry()
+	hooks.Register(ToolApproval, func(ctx context.Context, hctx *HookContext) error {
+		return fmt.Errorf("approval service unavailable")
+	})
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: &mockToolExecutor{}, Hooks: hooks})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("go")}}
+	_, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg)
+	if err == nil || !strings.Contains(err.Error(), "tool approval hook: approval service unavailable") {
+		t.Fatalf("err = %v, want the approval hook error", err)
+	}
+}
+
+// redactingTools is a tool executor that redacts the string "s3cret".
+type redactingTools struct {
+	mockToolExecutor
+}
+
+func (r *redactingTools) RedactInput(name, arguments string) string {
+	return strings.ReplaceAll(arguments, "s3cret", "[REDACTED]")
+}
+
+func TestLLMExecutor_RedactsShownToolInput(t *testing.T) {
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls == 1 {
+				tc := toolCall("c1", "http_request")
+				tc.Function.Arguments = `{"token":"s3cret"}`
+				return &llm.ChatResponse{
+					Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{tc}},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "done"}, FinishReason: "stop"}, nil
+		},
+	}
+	var executed string
+	tools := &redactingTools{mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+			executed = string(arguments)
+			return "ok", nil
+		},
+	}}
+	var shown []string
+	hooks := NewHookRegistry()
+	for _, point := range []HookPoint{ToolApproval, BeforeToolExec, AfterToolExec} {
+		hooks.Register(point, func(ctx context.Context, hctx *HookContext) error {
+			shown = append(shown, hctx.ToolInput)
+			return nil
+		})
+	}
+
+	var llmHooks []string
+	for _, point := range []HookPoint{BeforeLLMCall, AfterLLMCall} {
+		hooks.Register(point, func(ctx context.Context, hctx *HookContext) error {
+			data, _ := json.Marshal(hctx)
+			llmHooks = append(llmHooks, string(data))
+			return nil
+		})
+	}
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("forge")
+	RegisterOpenInferenceHooks(hooks, tracer, "openai", "gpt-4o")
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: tools, Hooks: hooks})
+	transcript := &Transcript{}
+	ctx := WithTranscript(context.Background(), transcript)
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("go")}}
+	if _, err := exec.Execute(ctx, &a2a.Task{ID: "t"}, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if executed != `{"token":"s3cret"}` {
+		t.Errorf("tool received %s, want the original input", executed)
+	}
+	if len(shown) != 3 {
+		t.Fatalf("hooks fired %d times, want 3", len(shown))
+	}
+	for _, in := range shown {
+		if in != `{"token":"[REDACTED]"}` {
+			t.Errorf("hook saw %s, want the redacted input", in)
+		}
+	}
+	for _, e := range transcript.Entries() {
+		if e.Type == TranscriptToolCall && e.ToolInput != `{"token":"[REDACTED]"}` {
+			t.Errorf("transcript recorded %s", e.ToolInput)
+		}
+	}
+	if data, _ := json.Marshal(transcript.Entries()); strings.Contains(string(data), "s3cret") {
+		t.Errorf("transcript leaks the secret: %s", data)
+	}
+	for _, h := range llmHooks {
+		if strings.Contains(h, "s3cret") {
+			t.Errorf("LLM hook saw the secret: %s", h)
+		}
+	}
+	for _, span := range rec.Ended() {
+		for _, kv := range span.Attributes() {
+			if strings.Contains(kv.Value.Emit(), "s3cret") {
+				t.Errorf("span %s attribute %s leaks the secret", span.Name(), kv.Key)
+			}
+		}
+	}
+}
+
+// cachingTools serves every call after the first from a pretend cache.
+type cachingTools struct {
+	mockToolExecutor
+	seen bool
+}
+
+func (c *cachingTools) ExecuteCached(ctx context.Context, name string, arguments json.RawMessage) (string, bool, error) {
+	hit := c.seen
+	c.seen = true
+	return "result", hit, nil
+}
+
+func TestLLMExecutor_ReportsCacheHits(t *testing.T) {
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			if calls <= 2 {
+				return &llm.ChatResponse{
+					Message:      llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{toolCall(fmt.Sprintf("c%d", calls), "web_search")}},
+					FinishReason: "tool_calls",
+				}, nil
+			}
+			return &llm.ChatResponse{Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "done"}, FinishReason: "stop"}, nil
+		},
+	}
+	var hits []bool
+	hooks := NewHookRegistry()
+	hooks.Register(AfterToolExec, func(ctx context.Context, hctx *HookContext) error {
+		hits = append(hits, hctx.CacheHit)
+		return nil
+	})
+
+	exec := NewLLMExecutor(LLMExecutorConfig{Client: client, Tools: &cachingTools{}, Hooks: hooks})
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("go")}}
+	if _, err := exec.Execute(context.Background(), &a2a.Task{ID: "t"}, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(hits) != 2 || hits[0] || !hits[1] {
+		t.Errorf("AfterToolExec CacheHit = %v, want [false true]", hits)
+	}
+}
@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// Transcript entry types.
+const (
+	TranscriptLLMCall  = "llm_call"
+	TranscriptToolCall = "tool_call"
+)
+
+// TranscriptEntry is one recorded step of the agent loop.
+type TranscriptEntry struct {
+	Type      string `json:"type"`
+	Iteration int    `json:"iteration"`
+
+	// LLM calls
+	Messages     []llm.ChatMessage `json:"messages,omitempty"` // messages sent to the model
+	Content      string            `json:"content,omitempty"`
+	ToolCalls    []llm.ToolCall    `json:"tool_calls,omitempty"`
+	FinishReason string            `json:"finish_reason,omitempty"`
+
+	// Tool calls
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	ToolName   string `json:"tool_name,omitempty"`
+	ToolInput  string `json:"tool_input,omitempty"`
+	ToolOutput string `json:"tool_output,omitempty"`
+	IsError    bool   `json:"is_error,omitempty"`
+}
+
+// Transcript is the audit trail of a task: each LLM response and each tool
+// result, in the order they were added to the conversation.
+type Transcript struct {
+	mu      sync.Mutex
+	entries []TranscriptEntry
+}
+
+// Add appends an entry.
+func (t *Transcript) Add(e TranscriptEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, e)
+}
+
+// Entries returns a copy of the recorded entries.
+func (t *Transcript) Entries() []TranscriptEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TranscriptEntry(nil), t.entries...)
+}
+
+type transcriptKey struct{}
+
+// WithTranscript returns a context whose agent loop steps are recorded in t.
+func WithTranscript(ctx context.Context, t *Transcript) context.Context {
+	return context.WithValue(ctx, transcriptKey{}, t)
+}
+
+// RecordTranscript adds e to the transcript in ctx, if any.
+func RecordTranscript(ctx context.Context, e TranscriptEntry) {
+	if t, ok := ctx.Value(transcriptKey{}).(*Transcript); ok && t != nil {
+		t.Add(e)
+	}
+}
@@ -0,0 +1,146 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+// ModelCost is the usage and estimated cost of the LLM calls made with one
+// model.
+type ModelCost struct {
+	Model            string   `json:"model"`
+	LLMCalls         int      `json:"llm_calls"`
+	PromptTokens     int      `json:"prompt_tokens"`
+	CompletionTokens int      `json:"completion_tokens"`
+	TotalTokens      int      `json:"total_tokens"`
+	EstimatedCostUSD *float64 `json:"estimated_cost_usd"` // null when the model has no pricing
+}
+
+// CostSummary totals the LLM usage recorded by a UsageTracker, with a
+// breakdown by model in the order the models were first used.
+type CostSummary struct {
+	Models           []ModelCost `json:"models"`
+	LLMCalls         int         `json:"llm_calls"`
+	PromptTokens     int         `json:"prompt_tokens"`
+	CompletionTokens int         `json:"completion_tokens"`
+	TotalTokens      int         `json:"total_tokens"`
+	EstimatedCostUSD float64     `json:"estimated_cost_usd"` // sum over priced models only
+	Unpriced         []string    `json:"unpriced,omitempty"` // models left out of the cost
+}
+
+// UsageTracker accumulates LLM token usage per model across the calls made
+// for a task. The zero value is ready to use, and it is safe for concurrent
+// use.
+type UsageTracker struct {
+	parent *UsageTracker // also receives every call, if set
+
+	mu     sync.Mutex
+	models map[string]*modelUsage
+	order  []string
+	last   string
+}
+
+type modelUsage struct {
+	calls int
+	usage llm.UsageInfo
+}
+
+// Add records usage from one LLM call made with model.
+func (t *UsageTracker) Add(model string, usage llm.UsageInfo) {
+	t.mu.Lock()
+	if t.models == nil {
+		t.models = make(map[string]*modelUsage)
+	}
+	mu, ok := t.models[model]
+	if !ok {
+		mu = &modelUsage{}
+		t.models[model] = mu
+		t.order = append(t.order, model)
+	}
+	mu.calls++
+	mu.usage.PromptTokens += usage.PromptTokens
+	mu.usage.CompletionTokens += usage.CompletionTokens
+	mu.usage.TotalTokens += usage.TotalTokens
+	mu.usage.CacheCreationTokens += usage.CacheCreationTokens
+	mu.usage.CacheReadTokens += usage.CacheReadTokens
+	t.last = model
+	t.mu.Unlock()
+
+	if t.parent != nil {
+		t.parent.Add(model, usage)
+	}
+}
+
+// Totals returns the model of the most recent call, the usage accumulated
+// over all models, and the number of calls recorded.
+func (t *UsageTracker) Totals() (model string, usage llm.UsageInfo, calls int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, mu := range t.models {
+		calls += mu.calls
+		usage.PromptTokens += mu.usage.PromptTokens
+		usage.CompletionTokens += mu.usage.CompletionTokens
+		usage.TotalTokens += mu.usage.TotalTokens
+		usage.CacheCreationTokens += mu.usage.CacheCreationTokens
+		usage.CacheReadTokens += mu.usage.CacheReadTokens
+	}
+	return t.last, usage, calls
+}
+
+// Summary returns the accumulated usage per model, priced with pricing. A
+// nil pricing uses llm.DefaultPricing.
+func (t *UsageTracker) Summary(pricing llm.PricingTable) CostSummary {
+	if pricing == nil {
+		pricing = llm.DefaultPricing
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := CostSummary{Models: make([]ModelCost, 0, len(t.order))}
+	for _, model := range t.order {
+		mu := t.models[model]
+		mc := ModelCost{
+			Model:            model,
+			LLMCalls:         mu.calls,
+			PromptTokens:     mu.usage.PromptTokens,
+			CompletionTokens: mu.usage.CompletionTokens,
+			TotalTokens:      mu.usage.TotalTokens,
+		}
+		if cost, ok := pricing.EstimateCost(model, mu.usage); ok {
+			mc.EstimatedCostUSD = &cost
+			s.EstimatedCostUSD += cost
+		} else {
+			s.Unpriced = append(s.Unpriced, model)
+		}
+		s.Models = append(s.Models, mc)
+		s.LLMCalls += mc.LLMCalls
+		s.PromptTokens += mc.PromptTokens
+		s.CompletionTokens += mc.CompletionTokens
+		s.TotalTokens += mc.TotalTokens
+	}
+	return s
+}
+
+type usageKey struct{}
+
+// WithUsageTracker returns a context whose LLM calls are recorded in t.
+func WithUsageTracker(ctx context.Context, t *UsageTracker) context.Context {
+	return context.WithValue(ctx, usageKey{}, t)
+}
+
+// withChildUsageTracker returns a context recording LLM calls in a new
+// tracker, which passes each call on to the tracker already in ctx, if any.
+func withChildUsageTracker(ctx context.Context) (context.Context, *UsageTracker) {
+	parent, _ := ctx.Value(usageKey{}).(*UsageTracker)
+	t := &UsageTracker{parent: parent}
+	return WithUsageTracker(ctx, t), t
+}
+
+// RecordUsage adds usage to the tracker in ctx, if any. Executors other than
+// LLMExecutor may call it to report their own token usage.
+func RecordUsage(ctx context.Context, model string, usage llm.UsageInfo) {
+	if t, ok := ctx.Value(usageKey{}).(*UsageTracker); ok && t != nil {
+		t.Add(model, usage)
+	}
+}
@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/llm"
+)
+
+func TestUsageTracker_Summary(t *testing.T) {
+	pricing := llm.PricingTable{"big": {InputPerMillion: 10, OutputPerMillion: 30}, "small": {InputPerMillion: 1, OutputPerMillion: 2}}
+	ct := &UsageTracker{}
+	ct.Add("big", llm.UsageInfo{PromptTokens: 1000, CompletionTokens: 100, TotalTokens: 1100})
+	ct.Add("small", llm.UsageInfo{PromptTokens: 2000, CompletionTokens: 500, TotalTokens: 2500})
+	ct.Add("big", llm.UsageInfo{PromptTokens: 1000, CompletionTokens: 100, TotalTokens: 1100})
+	ct.Add("local-llama", llm.UsageInfo{PromptTokens: 50, CompletionTokens: 50, TotalTokens: 100})
+
+	s := ct.Summary(pricing)
+	if s.LLMCalls != 4 || s.PromptTokens != 4050 || s.CompletionTokens != 750 || s.TotalTokens != 4800 {
+		t.Errorf("totals = %+v", s)
+	}
+	// big: 2000*10/1M + 200*30/1M = 0.026; small: 2000*1/1M + 500*2/1M = 0.003
+	if got := fmt.Sprintf("%.6f", s.EstimatedCostUSD); got != "0.029000" {
+		t.Errorf("cost = %s, want 0.029000", got)
+	}
+	if len(s.Models) != 3 || s.Models[0].Model != "big" || s.Models[1].Model != "small" || s.Models[2].Model != "local-llama" {
+		t.Fatalf("models = %+v, want first-use order", s.Models)
+	}
+	if s.Models[0].LLMCalls != 2 || s.Models[0].EstimatedCostUSD == nil {
+		t.Errorf("big = %+v", s.Models[0])
+	}
+	if s.Models[2].EstimatedCostUSD != nil || len(s.Unpriced) != 1 || s.Unpriced[0] != "local-llama" {
+		t.Errorf("unpriced model = %+v, unpriced = %v", s.Models[2], s.Unpriced)
+	}
+}
+
+func TestUsageTracker_Child(t *testing.T) {
+	task := &UsageTracker{}
+	ctx, run := withChildUsageTracker(WithUsageTracker(context.Background(), task))
+	RecordUsage(ctx, "big", llm.UsageInfo{PromptTokens: 10, TotalTokens: 10})
+	task.Add("small", llm.UsageInfo{PromptTokens: 5, TotalTokens: 5})
+
+	if s := run.Summary(nil); s.LLMCalls != 1 || s.TotalTokens != 10 {
+		t.Errorf("run summary = %+v, want only its own call", s)
+	}
+	model, usage, calls := task.Totals()
+	if model != "small" || calls != 2 || usage.TotalTokens != 15 {
+		t.Errorf("task totals = %q %+v %d, want both calls", model, usage, calls)
+	}
+}
+
+func TestLLMExecutor_OnComplete(t *testing.T) {
+	calls := 0
+	client := &mockLLMClient{
+		chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			calls++
+			resp := &llm.ChatResponse{
+				Message: llm.ChatMessage{Role: llm.RoleAssistant, Content: "Done"},
+				Usage:   llm.UsageInfo{PromptTokens: 1000, CompletionTokens: 200, TotalTokens: 1200},
+			}
+			if calls == 1 {
+				resp.Message = llm.ChatMessage{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{toolCall("call_1", "noop")}}
+			}
+			return resp, nil
+		},
+	}
+	tools := &mockToolExecutor{
+		executeFunc: func(ctx context.Context, name string, arguments json.RawMessage) (string, error) { return "ok", nil },
+		toolDefs:    []llm.ToolDefinition{{Type: "function", Function: llm.FunctionSchema{Name: "noop"}}},
+	}
+	hooks := NewHookRegistry()
+	var completions []*HookContext
+	hooks.Register(OnComplete, func(ctx context.Context, hctx *HookContext) error {
+		completions = append(completions, hctx)
+		return errors.New("ignored")
+	})
+	exec := NewLLMExecutor(LLMExecutorConfig{
+		Client:  client,
+		Tools:   tools,
+		Hooks:   hooks,
+		Pricing: llm.PricingTable{"test-model": {InputPerMillion: 5, OutputPerMillion: 20}},
+	})
+
+	msg := &a2a.Message{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.NewTextPart("hi")}}
+	if _, err := exec.Execute(context.Background(), &a2a.Task{ID: "t1"}, msg); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if len(completions) != 1 {
+		t.Fatalf("OnComplete fired %d times, want 1", len(completions))
+	}
+	cost := completions[0].Cost
+	if cost == nil || cost.LLMCalls != 2 || cost.TotalTokens != 2400 || len(cost.Models) != 1 {
+		t.Fatalf("cost = %+v, want two calls of test-model", cost)
+	}
+	// 2000*5/1M + 400*20/1M
+	if got := fmt.Sprintf("%.4f", cost.EstimatedCostUSD); got != "0.0180" {
+		t.Errorf("cost = %s, want 0.0180", got)
+	}
+	if completions[0].Error != nil {
+		t.Errorf("Error = %v, want nil for a successful run", completions[0].Error)
+	}
+
+	// A failed run still reports once, with its error
+	completions = nil
+	failing := NewLLMExecutor(LLMExecutorConfig{
+		Client: &mockLLMClient{chatFunc: func(ctx context.Context, req *llm.ChatRequest) (*llm.ChatResponse, error) {
+			return nil, errors.New("provider down")
+		}},
+		Hooks: hooks,
+	})
+	if _, err := failing.Execute(context.Background(), &a2a.Task{ID: "t2"}, msg); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(completions) != 1 || completions[0].Error == nil || completions[0].Cost.LLMCalls != 0 {
+		t.Errorf("completions = %+v, want one with an error and no calls", completions)
+	}
+}
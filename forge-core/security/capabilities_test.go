@@ -31,8 +31,15 @@ func TestResolveCapabilities_Telegram(t *testing.T) {
 	}
 }
 
-func TestResolveCapabilities_Unknown(t *testing.T) {
+func TestResolveCapabilities_Discord(t *testing.T) {
 	domains := ResolveCapabilities([]string{"discord"})
+	if len(domains) != 2 || domains[0] != "discord.com" || domains[1] != "gateway.discord.gg" {
+		t.Errorf("got %v, want [discord.com gateway.discord.gg]", domains)
+	}
+}
+
+func TestResolveCapabilities_Unknown(t *testing.T) {
+	domains := ResolveCapabilities([]string{"teams"})
 	if len(domains) != 0 {
 		t.Errorf("expected empty for unknown capability, got %v", domains)
 	}
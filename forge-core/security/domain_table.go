@@ -0,0 +1,160 @@
+package security
+
+import "sort"
+
+// This file is the single table of domains that model providers, channels,
+// and builtin tools need. forge init, forge egress sync, and the build's
+// egress resolution all read it, so adding a provider's or tool's domains is
+// one edit here.
+
+// ProviderDomains maps model providers to their API domains. Local
+// providers such as ollama need none.
+var ProviderDomains = map[string][]string{
+	"openai":    {"api.openai.com"},
+	"anthropic": {"api.anthropic.com"},
+	"gemini":    {"generativelanguage.googleapis.com"},
+}
+
+// DefaultCapabilityBundles maps capability names, which include the channel
+// adapters, to their required domain sets.
+var DefaultCapabilityBundles = map[string][]string{
+	"slack":    {"slack.com", "hooks.slack.com", "api.slack.com"},
+	"telegram": {"api.telegram.org"},
+	"discord":  {"discord.com", "gateway.discord.gg"},
+}
+
+// DefaultToolDomains maps tool names to their known required domains. Tools
+// whose backend is chosen at runtime are listed in ToolBackends instead.
+var DefaultToolDomains = map[string][]string{
+	"http_request":      {}, // dynamic — depends on user config
+	"slack_notify":      {"slack.com", "hooks.slack.com"},
+	"github_api":        {"api.github.com", "github.com"},
+	"openai_completion": {"api.openai.com"},
+	"anthropic_api":     {"api.anthropic.com"},
+	"huggingface_api":   {"api-inference.huggingface.co", "huggingface.co"},
+	"google_vertex":     {"us-central1-aiplatform.googleapis.com"},
+	"sendgrid_email":    {"api.sendgrid.com"},
+	"twilio_sms":        {"api.twilio.com"},
+	"aws_bedrock":       {"bedrock-runtime.us-east-1.amazonaws.com"},
+	"azure_openai":      {"openai.azure.com"},
+}
+
+// ToolBackend lists the domains of a tool whose backend is selected by an
+// environment variable.
+type ToolBackend struct {
+	EnvVar   string              // variable naming the backend
+	Default  string              // backend used when EnvVar is unset or unknown
+	Fallback string              // keyless backend used instead of Default when its key is unset
+	Domains  map[string][]string // backend name -> domains
+}
+
+var webSearchBackend = ToolBackend{
+	EnvVar:   "WEB_SEARCH_PROVIDER",
+	Default:  "tavily",
+	Fallback: "duckduckgo",
+	Domains: map[string][]string{
+		"tavily":     {"api.tavily.com"},
+		"perplexity": {"api.perplexity.ai"},
+		"duckduckgo": {"html.duckduckgo.com"},
+	},
+}
+
+// ToolBackends maps tool names to their selectable backends.
+var ToolBackends = map[string]ToolBackend{
+	"web_search": webSearchBackend,
+	"web-search": webSearchBackend,
+}
+
+// ToolDomains returns the domains tool needs. For a tool in ToolBackends,
+// env selects the backend; when it names none, the domains of both the
+// default and the fallback backend are returned. With a nil env the domains
+// of every backend are returned, since any of them may be used at runtime.
+func ToolDomains(tool string, env map[string]string) []string {
+	b, ok := ToolBackends[tool]
+	if !ok {
+		return DefaultToolDomains[tool]
+	}
+	if env == nil {
+		names := make([]string, 0, len(b.Domains))
+		for name := range b.Domains {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var all []string
+		for _, name := range names {
+			all = append(all, b.Domains[name]...)
+		}
+		return all
+	}
+	if d, ok := b.Domains[env[b.EnvVar]]; ok {
+		return d
+	}
+	return append(append([]string{}, b.Domains[b.Default]...), b.Domains[b.Fallback]...)
+}
+
+// EgressSource describes the parts of an agent that need network egress.
+type EgressSource struct {
+	Provider string            // model provider, e.g. "openai"
+	Channels []string          // channel adapters, e.g. "slack"
+	Tools    []string          // builtin tool names
+	Env      map[string]string // selects tool backends, e.g. WEB_SEARCH_PROVIDER
+}
+
+// DeriveEgressDomains returns the sorted, deduplicated domains src needs,
+// looked up in the provider, channel, and tool tables.
+func DeriveEgressDomains(src EgressSource) []string {
+	env := src.Env
+	if env == nil {
+		env = map[string]string{}
+	}
+	all := append([]string{}, ProviderDomains[src.Provider]...)
+	all = append(all, ResolveCapabilities(src.Channels)...)
+	for _, tool := range src.Tools {
+		all = append(all, ToolDomains(tool, env)...)
+	}
+	domains := dedup(all)
+	sort.Strings(domains)
+	return domains
+}
+
+// DomainOrigin reports which table entry lists domain. kind is "provider",
+// "channel", or "tool" and name is the entry's key; ok is false when no
+// entry lists it. Providers are checked first, then channels, then tools.
+func DomainOrigin(domain string) (kind, name string, ok bool) {
+	tables := []struct {
+		kind    string
+		domains map[string][]string
+	}{
+		{"provider", ProviderDomains},
+		{"channel", DefaultCapabilityBundles},
+		{"tool", allToolDomains()},
+	}
+	for _, t := range tables {
+		m := t.domains
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, d := range m[k] {
+				if d == domain {
+					return t.kind, k, true
+				}
+			}
+		}
+	}
+	return "", "", false
+}
+
+// allToolDomains merges DefaultToolDomains with every backend's domains.
+func allToolDomains() map[string][]string {
+	m := make(map[string][]string, len(DefaultToolDomains)+len(ToolBackends))
+	for k, v := range DefaultToolDomains {
+		m[k] = v
+	}
+	for k := range ToolBackends {
+		m[k] = ToolDomains(k, nil)
+	}
+	return m
+}
@@ -0,0 +1,70 @@
+package security
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDeriveEgressDomains_OpenAISlackWebSearch(t *testing.T) {
+	got := DeriveEgressDomains(EgressSource{
+		Provider: "openai",
+		Channels: []string{"slack"},
+		Tools:    []string{"web_search"},
+	})
+	// Without WEB_SEARCH_PROVIDER, web_search uses Tavily, or DuckDuckGo
+	// when no key is set.
+	want := []string{"api.openai.com", "api.slack.com", "api.tavily.com", "hooks.slack.com", "html.duckduckgo.com", "slack.com"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDeriveEgressDomains_ToolBackend(t *testing.T) {
+	got := DeriveEgressDomains(EgressSource{
+		Provider: "ollama",
+		Tools:    []string{"web_search"},
+		Env:      map[string]string{"WEB_SEARCH_PROVIDER": "perplexity"},
+	})
+	if !slices.Equal(got, []string{"api.perplexity.ai"}) {
+		t.Errorf("got %v, want only the perplexity backend", got)
+	}
+
+	got = DeriveEgressDomains(EgressSource{Tools: []string{"web_search"}, Env: map[string]string{"WEB_SEARCH_PROVIDER": "unknown"}})
+	if !slices.Equal(got, []string{"api.tavily.com", "html.duckduckgo.com"}) {
+		t.Errorf("got %v, want the default and fallback backends for an unknown value", got)
+	}
+
+	got = DeriveEgressDomains(EgressSource{Tools: []string{"web_search"}, Env: map[string]string{"WEB_SEARCH_PROVIDER": "duckduckgo"}})
+	if !slices.Equal(got, []string{"html.duckduckgo.com"}) {
+		t.Errorf("got %v, want only the duckduckgo backend", got)
+	}
+}
+
+func TestInferToolDomains_AllBackends(t *testing.T) {
+	got := InferToolDomains([]string{"web_search", "github_api"})
+	for _, d := range []string{"api.tavily.com", "api.perplexity.ai", "api.github.com", "github.com"} {
+		if !slices.Contains(got, d) {
+			t.Errorf("missing %s in %v", d, got)
+		}
+	}
+}
+
+func TestDomainOrigin(t *testing.T) {
+	tests := []struct {
+		domain, kind, name string
+	}{
+		{"api.anthropic.com", "provider", "anthropic"}, // also listed by the anthropic_api tool
+		{"hooks.slack.com", "channel", "slack"},        // also listed by the slack_notify tool
+		{"api.perplexity.ai", "tool", "web-search"},
+		{"api.twilio.com", "tool", "twilio_sms"},
+	}
+	for _, tt := range tests {
+		kind, name, ok := DomainOrigin(tt.domain)
+		if !ok || kind != tt.kind || name != tt.name {
+			t.Errorf("DomainOrigin(%q) = %q, %q, %v; want %q, %q", tt.domain, kind, name, ok, tt.kind, tt.name)
+		}
+	}
+	if _, _, ok := DomainOrigin("example.com"); ok {
+		t.Error("unlisted domain reported as known")
+	}
+}
@@ -0,0 +1,157 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrEgressDenied is wrapped by errors for requests the egress config blocks.
+var ErrEgressDenied = errors.New("egress denied")
+
+// EgressEnforcer checks outbound hosts against a resolved egress config at
+// runtime. A nil *EgressEnforcer allows every host.
+type EgressEnforcer struct {
+	mode   EgressMode
+	exact  map[string]bool
+	suffix []string // ".example.com" for each "*.example.com" entry
+}
+
+// NewEgressEnforcer creates an enforcer for cfg. Allowlist mode permits
+// cfg.AllDomains, where "*.example.com" matches any subdomain of
+// example.com; deny-all permits nothing and dev-open everything. Loopback
+// hosts are always allowed, since traffic to them never leaves the machine.
+func NewEgressEnforcer(cfg *EgressConfig) *EgressEnforcer {
+	e := &EgressEnforcer{mode: cfg.Mode, exact: make(map[string]bool)}
+	for _, d := range cfg.AllDomains {
+		d = normalizeHost(d)
+		if rest, ok := strings.CutPrefix(d, "*."); ok {
+			e.suffix = append(e.suffix, "."+rest)
+		} else {
+			e.exact[d] = true
+		}
+	}
+	return e
+}
+
+// Mode returns the egress mode the enforcer applies.
+func (e *EgressEnforcer) Mode() EgressMode {
+	if e == nil {
+		return ModeDevOpen
+	}
+	return e.mode
+}
+
+// Allowed reports whether requests to host (without a port) may be sent.
+func (e *EgressEnforcer) Allowed(host string) bool {
+	allowed, _ := e.Match(host)
+	return allowed
+}
+
+// Match reports whether host may be reached and the rule that decided it:
+// the matching allowlist entry, or a description of the default.
+func (e *EgressEnforcer) Match(host string) (allowed bool, rule string) {
+	switch {
+	case e == nil:
+		return true, "no egress config"
+	case e.mode == ModeDevOpen:
+		return true, "dev-open mode"
+	}
+	host = normalizeHost(host)
+	if isLoopback(host) {
+		return true, "loopback"
+	}
+	if e.mode != ModeAllowlist {
+		return false, "default: deny-all mode"
+	}
+	if e.exact[host] {
+		return true, host
+	}
+	for _, s := range e.suffix {
+		if strings.HasSuffix(host, s) {
+			return true, "*" + s
+		}
+	}
+	return false, "default: not in allowlist"
+}
+
+// Check returns an error wrapping ErrEgressDenied if host is not allowed.
+func (e *EgressEnforcer) Check(host string) error {
+	if e.Allowed(host) {
+		return nil
+	}
+	if e.mode == ModeDenyAll {
+		return fmt.Errorf("%w: host %q blocked by deny-all egress mode", ErrEgressDenied, host)
+	}
+	return fmt.Errorf("%w: host %q not in allowlist", ErrEgressDenied, host)
+}
+
+// CheckURL is Check for the host of rawURL.
+func (e *EgressEnforcer) CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parsing url: %w", err)
+	}
+	return e.Check(u.Hostname())
+}
+
+// Transport wraps base so each request, including redirects, is checked
+// before it is sent.
+func (e *EgressEnforcer) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if e == nil || e.mode == ModeDevOpen {
+		return base
+	}
+	return &egressTransport{enforcer: e, base: base}
+}
+
+type egressTransport struct {
+	enforcer *EgressEnforcer
+	base     http.RoundTripper
+}
+
+func (t *egressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.enforcer.Check(req.URL.Hostname()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+type egressContextKey struct{}
+
+// WithEgressEnforcer returns a context carrying e, for tools that make
+// network requests.
+func WithEgressEnforcer(ctx context.Context, e *EgressEnforcer) context.Context {
+	return context.WithValue(ctx, egressContextKey{}, e)
+}
+
+// EgressEnforcerFrom returns the enforcer in ctx, or nil if there is none.
+func EgressEnforcerFrom(ctx context.Context) *EgressEnforcer {
+	e, _ := ctx.Value(egressContextKey{}).(*EgressEnforcer)
+	return e
+}
+
+// EgressClient returns an HTTP client with the given timeout whose requests
+// are checked against the enforcer in ctx.
+func EgressClient(ctx context.Context, timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: EgressEnforcerFrom(ctx).Transport(nil)}
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
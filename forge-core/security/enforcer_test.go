@@ -0,0 +1,102 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEgressEnforcer_Allowed(t *testing.T) {
+	allow := NewEgressEnforcer(&EgressConfig{Mode: ModeAllowlist, AllDomains: []string{"api.github.com", "*.slack.com"}})
+	deny := NewEgressEnforcer(&EgressConfig{Mode: ModeDenyAll})
+	open := NewEgressEnforcer(&EgressConfig{Mode: ModeDevOpen})
+
+	tests := []struct {
+		e    *EgressEnforcer
+		host string
+		want bool
+	}{
+		{allow, "api.github.com", true},
+		{allow, "API.GitHub.com.", true},
+		{allow, "github.com", false},
+		{allow, "hooks.slack.com", true},
+		{allow, "a.b.slack.com", true},
+		{allow, "slack.com", false},
+		{allow, "evilslack.com", false},
+		{allow, "localhost", true},
+		{allow, "127.0.0.1", true},
+		{allow, "::1", true},
+		{deny, "api.github.com", false},
+		{deny, "127.0.0.1", true},
+		{open, "anything.example.com", true},
+		{nil, "anything.example.com", true},
+	}
+	for _, tt := range tests {
+		if got := tt.e.Allowed(tt.host); got != tt.want {
+			t.Errorf("%s Allowed(%q) = %v, want %v", tt.e.Mode(), tt.host, got, tt.want)
+		}
+	}
+
+	err := allow.CheckURL("https://evil.example.com/steal")
+	if !errors.Is(err, ErrEgressDenied) || !strings.Contains(err.Error(), `egress denied: host "evil.example.com" not in allowlist`) {
+		t.Errorf("CheckURL() = %v", err)
+	}
+	if err := deny.Check("api.github.com"); err == nil || !strings.Contains(err.Error(), "deny-all") {
+		t.Errorf("deny-all Check() = %v", err)
+	}
+}
+
+type recordingTransport struct{ hosts []string }
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.hosts = append(rt.hosts, req.URL.Host)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestEgressEnforcer_Transport(t *testing.T) {
+	e := NewEgressEnforcer(&EgressConfig{Mode: ModeAllowlist, AllDomains: []string{"api.github.com"}})
+	base := &recordingTransport{}
+	client := &http.Client{Transport: e.Transport(base)}
+
+	resp, err := client.Get("https://api.github.com/repos")
+	if err != nil {
+		t.Fatalf("allowed host: %v", err)
+	}
+	_ = resp.Body.Close()
+	if _, err := client.Get("https://evil.example.com/"); !errors.Is(err, ErrEgressDenied) {
+		t.Errorf("denied host: err = %v", err)
+	}
+	if len(base.hosts) != 1 || base.hosts[0] != "api.github.com" {
+		t.Errorf("requests sent to %v, want only api.github.com", base.hosts)
+	}
+
+	ctx := WithEgressEnforcer(context.Background(), e)
+	if EgressEnforcerFrom(ctx) != e || EgressEnforcerFrom(context.Background()) != nil {
+		t.Error("EgressEnforcerFrom did not return the attached enforcer")
+	}
+}
+
+func TestEgressEnforcer_Match(t *testing.T) {
+	e := NewEgressEnforcer(&EgressConfig{Mode: ModeAllowlist, AllDomains: []string{"api.github.com", "*.slack.com"}})
+	tests := []struct {
+		host    string
+		allowed bool
+		rule    string
+	}{
+		{"API.github.com", true, "api.github.com"},
+		{"hooks.slack.com", true, "*.slack.com"},
+		{"localhost", true, "loopback"},
+		{"example.com", false, "default: not in allowlist"},
+	}
+	for _, tt := range tests {
+		allowed, rule := e.Match(tt.host)
+		if allowed != tt.allowed || rule != tt.rule {
+			t.Errorf("Match(%q) = %v, %q; want %v, %q", tt.host, allowed, rule, tt.allowed, tt.rule)
+		}
+	}
+	if _, rule := NewEgressEnforcer(&EgressConfig{Mode: ModeDenyAll}).Match("example.com"); rule != "default: deny-all mode" {
+		t.Errorf("deny-all rule = %q", rule)
+	}
+}
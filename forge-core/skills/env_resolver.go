@@ -3,6 +3,7 @@ package skills
 import (
 	"fmt"
 	"os/exec"
+	"runtime"
 )
 
 // EnvSource describes where an environment variable was found.
@@ -110,14 +111,15 @@ func (r *EnvResolver) lookup(key string) EnvSource {
 	return EnvSourceMissing
 }
 
-// BinDiagnostics checks binary availability via exec.LookPath.
+// BinDiagnostics checks binary availability via exec.LookPath. The message
+// for a missing binary suggests how to install it on this OS.
 func BinDiagnostics(bins []string) []ValidationDiagnostic {
 	var diags []ValidationDiagnostic
 	for _, bin := range bins {
 		if _, err := exec.LookPath(bin); err != nil {
 			diags = append(diags, ValidationDiagnostic{
 				Level:   "warning",
-				Message: fmt.Sprintf("binary %q not found in PATH", bin),
+				Message: fmt.Sprintf("binary %q not found in PATH; %s", bin, InstallHint(bin)),
 				Var:     bin,
 			})
 		}
@@ -125,6 +127,45 @@ func BinDiagnostics(bins []string) []ValidationDiagnostic {
 	return diags
 }
 
+// binPackage names the package providing a binary in each package manager.
+// An empty name means the default repositories lack it, as Debian and
+// Ubuntu lack gh and kubectl, so the generic hint is given instead.
+type binPackage struct {
+	brew, apt, choco string
+}
+
+// knownPackages maps binaries commonly required by skills to their packages.
+var knownPackages = map[string]binPackage{
+	"curl":    {"curl", "curl", "curl"},
+	"jq":      {"jq", "jq", "jq"},
+	"gh":      {"gh", "", "gh"},
+	"git":     {"git", "git", "git"},
+	"rg":      {"ripgrep", "ripgrep", "ripgrep"},
+	"ffmpeg":  {"ffmpeg", "ffmpeg", "ffmpeg"},
+	"node":    {"node", "nodejs", "nodejs"},
+	"python3": {"python", "python3", "python"},
+	"kubectl": {"kubernetes-cli", "", "kubernetes-cli"},
+}
+
+// InstallHint suggests how to install bin on the current OS, e.g.
+// "install it with: brew install jq".
+func InstallHint(bin string) string {
+	return installHint(bin, runtime.GOOS)
+}
+
+func installHint(bin, goos string) string {
+	pkg := knownPackages[bin]
+	switch {
+	case goos == "darwin" && pkg.brew != "":
+		return "install it with: brew install " + pkg.brew
+	case goos == "linux" && pkg.apt != "":
+		return "install it with: apt-get install -y " + pkg.apt
+	case goos == "windows" && pkg.choco != "":
+		return "install it with: choco install " + pkg.choco
+	}
+	return fmt.Sprintf("install %s and ensure it's on PATH", bin)
+}
+
 func joinVars(vars []string) string {
 	result := ""
 	for i, v := range vars {
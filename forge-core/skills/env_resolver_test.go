@@ -1,6 +1,7 @@
 package skills
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -99,3 +100,54 @@ func TestResolve_OneOfPartialSatisfied(t *testing.T) {
 		t.Errorf("expected 0 diagnostics, got %d: %+v", len(diags), diags)
 	}
 }
+
+func TestInstallHint_PerOS(t *testing.T) {
+	mac := installHint("jq", "darwin")
+	linux := installHint("jq", "linux")
+	if mac != "install it with: brew install jq" {
+		t.Errorf("darwin hint = %q", mac)
+	}
+	if linux != "install it with: apt-get install -y jq" {
+		t.Errorf("linux hint = %q", linux)
+	}
+	if mac == linux {
+		t.Error("darwin and linux hints should differ")
+	}
+	if got := installHint("node", "windows"); got != "install it with: choco install nodejs" {
+		t.Errorf("windows hint = %q", got)
+	}
+}
+
+func TestInstallHint_UnknownBinary(t *testing.T) {
+	for _, goos := range []string{"darwin", "linux", "plan9"} {
+		if got := installHint("frobnicate", goos); got != "install frobnicate and ensure it's on PATH" {
+			t.Errorf("%s hint = %q", goos, got)
+		}
+	}
+	if got := installHint("jq", "plan9"); !strings.Contains(got, "install jq") {
+		t.Errorf("unsupported OS hint = %q", got)
+	}
+	// apt needs a third-party repository for these
+	for _, bin := range []string{"gh", "kubectl"} {
+		if got := installHint(bin, "linux"); got != "install "+bin+" and ensure it's on PATH" {
+			t.Errorf("linux %s hint = %q", bin, got)
+		}
+	}
+	if got := installHint("kubectl", "darwin"); got != "install it with: brew install kubernetes-cli" {
+		t.Errorf("darwin kubectl hint = %q", got)
+	}
+}
+
+func TestBinDiagnostics_MissingIncludesHint(t *testing.T) {
+	diags := BinDiagnostics([]string{"forge-test-missing-binary"})
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+	want := `binary "forge-test-missing-binary" not found in PATH; install forge-test-missing-binary and ensure it's on PATH`
+	if diags[0].Message != want {
+		t.Errorf("Message = %q, want %q", diags[0].Message, want)
+	}
+	if diags[0].Level != "warning" || diags[0].Var != "forge-test-missing-binary" {
+		t.Errorf("diagnostic = %+v", diags[0])
+	}
+}
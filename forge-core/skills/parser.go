@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"bytes"
 	"io"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -121,6 +122,13 @@ func ParseWithMetadata(r io.Reader) ([]SkillEntry, *SkillMetadata, error) {
 	return entries, meta, nil
 }
 
+// SplitFrontmatter splits a skills file into its YAML frontmatter and the
+// markdown body. ok is false, and body is all of content, when there is no
+// frontmatter.
+func SplitFrontmatter(content []byte) (frontmatter, body []byte, ok bool) {
+	return extractFrontmatter(content)
+}
+
 // extractFrontmatter splits content at --- delimiters.
 // Returns (frontmatter, body, hasFrontmatter).
 func extractFrontmatter(content []byte) ([]byte, []byte, bool) {
@@ -168,10 +176,47 @@ func extractFrontmatter(content []byte) ([]byte, []byte, bool) {
 	return fm, body, true
 }
 
-// extractForgeReqs extracts SkillRequirements from the generic metadata map
-// by re-marshaling metadata["forge"] through yaml round-trip into ForgeSkillMeta.
+// extractForgeReqs merges the top-level requirement keys of the frontmatter
+// with metadata.forge.requires. It returns nil when neither is present.
 func extractForgeReqs(meta *SkillMetadata) *SkillRequirements {
-	if meta == nil || meta.Metadata == nil {
+	if meta == nil {
+		return nil
+	}
+	reqs := nestedForgeReqs(meta)
+	if len(meta.RequiredEnv)+len(meta.OneOfEnv)+len(meta.OptionalEnv)+len(meta.RequiredBins)+len(meta.EgressDomains) == 0 {
+		return reqs
+	}
+
+	if reqs == nil {
+		reqs = &SkillRequirements{}
+	}
+	reqs.Bins = appendMissing(reqs.Bins, meta.RequiredBins...)
+	reqs.EgressDomains = appendMissing(reqs.EgressDomains, meta.EgressDomains...)
+	if len(meta.RequiredEnv)+len(meta.OneOfEnv)+len(meta.OptionalEnv) > 0 {
+		if reqs.Env == nil {
+			reqs.Env = &EnvRequirements{}
+		}
+		reqs.Env.Required = appendMissing(reqs.Env.Required, meta.RequiredEnv...)
+		reqs.Env.OneOf = appendMissing(reqs.Env.OneOf, meta.OneOfEnv...)
+		reqs.Env.Optional = appendMissing(reqs.Env.Optional, meta.OptionalEnv...)
+	}
+	return reqs
+}
+
+// appendMissing appends the values not already in list.
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// nestedForgeReqs extracts SkillRequirements from the generic metadata map
+// by re-marshaling metadata["forge"] through yaml round-trip into ForgeSkillMeta.
+func nestedForgeReqs(meta *SkillMetadata) *SkillRequirements {
+	if meta.Metadata == nil {
 		return nil
 	}
 	forgeMap, ok := meta.Metadata["forge"]
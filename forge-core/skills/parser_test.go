@@ -243,6 +243,74 @@ Summarize URLs or files into concise text.
 	}
 }
 
+func TestParseWithMetadata_FlatRequirementKeys(t *testing.T) {
+	input := `---
+name: weather
+required_env:
+  - WEATHER_API_KEY
+one_of_env:
+  - OPENAI_API_KEY
+  - ANTHROPIC_API_KEY
+optional_env:
+  - WEATHER_UNITS
+required_bins:
+  - curl
+egress_domains:
+  - api.weather.example
+metadata:
+  forge:
+    requires:
+      bins:
+        - jq
+---
+## Tool: weather
+Look up the forecast.
+`
+	entries, _, err := ParseWithMetadata(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseWithMetadata error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ForgeReqs == nil {
+		t.Fatalf("expected 1 entry with requirements, got %+v", entries)
+	}
+	reqs := entries[0].ForgeReqs
+	if !reflect.DeepEqual(reqs.Bins, []string{"jq", "curl"}) {
+		t.Errorf("Bins = %v, want [jq curl]", reqs.Bins)
+	}
+	if !reflect.DeepEqual(reqs.EgressDomains, []string{"api.weather.example"}) {
+		t.Errorf("EgressDomains = %v", reqs.EgressDomains)
+	}
+	if reqs.Env == nil {
+		t.Fatal("expected non-nil Env")
+	}
+	if !reflect.DeepEqual(reqs.Env.Required, []string{"WEATHER_API_KEY"}) {
+		t.Errorf("Env.Required = %v", reqs.Env.Required)
+	}
+	if !reflect.DeepEqual(reqs.Env.OneOf, []string{"OPENAI_API_KEY", "ANTHROPIC_API_KEY"}) {
+		t.Errorf("Env.OneOf = %v", reqs.Env.OneOf)
+	}
+	if !reflect.DeepEqual(reqs.Env.Optional, []string{"WEATHER_UNITS"}) {
+		t.Errorf("Env.Optional = %v", reqs.Env.Optional)
+	}
+
+	agg := AggregateRequirements(entries)
+	if !reflect.DeepEqual(agg.Bins, []string{"curl", "jq"}) {
+		t.Errorf("aggregated Bins = %v, want [curl jq]", agg.Bins)
+	}
+	if !reflect.DeepEqual(agg.EnvRequired, []string{"WEATHER_API_KEY"}) {
+		t.Errorf("aggregated EnvRequired = %v", agg.EnvRequired)
+	}
+	if len(agg.EnvOneOf) != 1 || len(agg.EnvOneOf[0]) != 2 {
+		t.Errorf("aggregated EnvOneOf = %v", agg.EnvOneOf)
+	}
+	if !reflect.DeepEqual(agg.EnvOptional, []string{"WEATHER_UNITS"}) {
+		t.Errorf("aggregated EnvOptional = %v", agg.EnvOptional)
+	}
+	if !reflect.DeepEqual(agg.EgressDomains, []string{"api.weather.example"}) {
+		t.Errorf("aggregated EgressDomains = %v", agg.EgressDomains)
+	}
+}
+
 func TestParseWithMetadata_UnknownNamespaces(t *testing.T) {
 	input := `---
 name: myskill
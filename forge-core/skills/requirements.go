@@ -1,6 +1,13 @@
 package skills
 
-import "sort"
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/types"
+)
 
 // AggregatedRequirements is the union of all skill requirements.
 type AggregatedRequirements struct {
@@ -8,6 +15,8 @@ type AggregatedRequirements struct {
 	EnvRequired []string   // union of required vars (promoted from optional if needed)
 	EnvOneOf    [][]string // separate groups per skill (not merged across skills)
 	EnvOptional []string   // union of optional vars minus those promoted to required
+
+	EgressDomains []string // union of all egress domains, deduplicated, sorted
 }
 
 // AggregateRequirements merges requirements from all entries that have ForgeReqs set.
@@ -18,6 +27,7 @@ type AggregatedRequirements struct {
 //   - one_of groups kept separate per skill
 func AggregateRequirements(entries []SkillEntry) *AggregatedRequirements {
 	binSet := make(map[string]bool)
+	egressSet := make(map[string]bool)
 	reqSet := make(map[string]bool)
 	optSet := make(map[string]bool)
 	var oneOfGroups [][]string
@@ -29,6 +39,9 @@ func AggregateRequirements(entries []SkillEntry) *AggregatedRequirements {
 		for _, b := range e.ForgeReqs.Bins {
 			binSet[b] = true
 		}
+		for _, d := range e.ForgeReqs.EgressDomains {
+			egressSet[d] = true
+		}
 		if e.ForgeReqs.Env != nil {
 			for _, v := range e.ForgeReqs.Env.Required {
 				reqSet[v] = true
@@ -50,14 +63,42 @@ func AggregateRequirements(entries []SkillEntry) *AggregatedRequirements {
 	}
 
 	agg := &AggregatedRequirements{
-		Bins:     sortedKeys(binSet),
-		EnvOneOf: oneOfGroups,
+		Bins:          sortedKeys(binSet),
+		EnvOneOf:      oneOfGroups,
+		EgressDomains: sortedKeys(egressSet),
 	}
 	agg.EnvRequired = sortedKeys(reqSet)
 	agg.EnvOptional = sortedKeys(optSet)
 	return agg
 }
 
+// WithEgressDomains returns the configured allowed domains plus the egress
+// domains declared by skills. forge build and forge run both use it, so the
+// built allowlist and the runtime enforcer agree. reqs may be nil; allowed
+// is not modified.
+func WithEgressDomains(allowed []string, reqs *AggregatedRequirements) []string {
+	if reqs == nil || len(reqs.EgressDomains) == 0 {
+		return allowed
+	}
+	return append(slices.Clone(allowed), reqs.EgressDomains...)
+}
+
+// ResolveEgress resolves the egress section of forge.yaml, plus the egress
+// domains skills declare, into the allowlist for an agent with the given
+// tools. forge build, forge run, and forge egress check all use it, so they
+// agree. It returns nil when the section sets neither a profile nor a mode,
+// in which case egress is not restricted. reqs may be nil.
+func ResolveEgress(eg types.EgressRef, toolNames []string, reqs *AggregatedRequirements) (*security.EgressConfig, error) {
+	if eg.Profile == "" && eg.Mode == "" {
+		return nil, nil
+	}
+	resolved, err := security.Resolve(eg.Profile, eg.Mode, WithEgressDomains(eg.AllowedDomains, reqs), toolNames, eg.Capabilities)
+	if err != nil {
+		return nil, fmt.Errorf("resolving egress: %w", err)
+	}
+	return resolved, nil
+}
+
 func sortedKeys(m map[string]bool) []string {
 	if len(m) == 0 {
 		return nil
@@ -141,3 +141,19 @@ func TestAggregate_NoRequirements(t *testing.T) {
 		t.Errorf("expected 0 oneOf, got %d", len(reqs.EnvOneOf))
 	}
 }
+
+func TestWithEgressDomains(t *testing.T) {
+	allowed := []string{"api.example.com"}
+	reqs := &AggregatedRequirements{EgressDomains: []string{"api.github.com"}}
+
+	got := WithEgressDomains(allowed, reqs)
+	if len(got) != 2 || got[0] != "api.example.com" || got[1] != "api.github.com" {
+		t.Errorf("WithEgressDomains = %v", got)
+	}
+	if len(allowed) != 1 {
+		t.Errorf("allowed was modified: %v", allowed)
+	}
+	if got := WithEgressDomains(allowed, nil); len(got) != 1 {
+		t.Errorf("WithEgressDomains(nil reqs) = %v", got)
+	}
+}
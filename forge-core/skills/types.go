@@ -12,10 +12,20 @@ type SkillEntry struct {
 
 // SkillMetadata holds the full frontmatter parsed from YAML between --- delimiters.
 // Uses map to tolerate unknown namespaces (e.g. clawdbot:).
+//
+// Requirements can be declared with the top-level keys below, which use the
+// same names as the skill registry index, or under metadata.forge.requires.
+// Both forms are merged.
 type SkillMetadata struct {
 	Name        string                    `yaml:"name,omitempty"`
 	Description string                    `yaml:"description,omitempty"`
 	Metadata    map[string]map[string]any `yaml:"metadata,omitempty"`
+
+	RequiredEnv   []string `yaml:"required_env,omitempty"`
+	OneOfEnv      []string `yaml:"one_of_env,omitempty"`
+	OptionalEnv   []string `yaml:"optional_env,omitempty"`
+	RequiredBins  []string `yaml:"required_bins,omitempty"`
+	EgressDomains []string `yaml:"egress_domains,omitempty"`
 }
 
 // ForgeSkillMeta holds Forge-specific metadata from the "forge" namespace.
@@ -23,10 +33,12 @@ type ForgeSkillMeta struct {
 	Requires *SkillRequirements `yaml:"requires,omitempty" json:"requires,omitempty"`
 }
 
-// SkillRequirements declares CLI binaries and environment variables a skill needs.
+// SkillRequirements declares CLI binaries, environment variables, and
+// egress domains a skill needs.
 type SkillRequirements struct {
-	Bins []string         `yaml:"bins,omitempty" json:"bins,omitempty"`
-	Env  *EnvRequirements `yaml:"env,omitempty" json:"env,omitempty"`
+	Bins          []string         `yaml:"bins,omitempty" json:"bins,omitempty"`
+	Env           *EnvRequirements `yaml:"env,omitempty" json:"env,omitempty"`
+	EgressDomains []string         `yaml:"egress_domains,omitempty" json:"egress_domains,omitempty"`
 }
 
 // EnvRequirements declares environment variable requirements at different levels.
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/initializ/forge/forge-core/security"
 	"github.com/initializ/forge/forge-core/tools"
 )
 
@@ -23,6 +24,7 @@ type mcpCallInput struct {
 func (t *mcpCallTool) Name() string             { return "mcp_call" }
 func (t *mcpCallTool) Description() string      { return "Call a tool on an MCP server via JSON-RPC" }
 func (t *mcpCallTool) Category() tools.Category { return tools.CategoryAdapter }
+func (t *mcpCallTool) Mutation() tools.Mutation { return tools.MutationMutating }
 
 func (t *mcpCallTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -60,7 +62,7 @@ func (t *mcpCallTool) Execute(ctx context.Context, args json.RawMessage) (string
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := security.EgressClient(ctx, 30*time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("mcp call: %w", err)
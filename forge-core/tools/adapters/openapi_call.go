@@ -21,6 +21,7 @@ func (t *openapiCallTool) Description() string {
 	return "Call an OpenAPI endpoint by operation ID (stub)"
 }
 func (t *openapiCallTool) Category() tools.Category { return tools.CategoryAdapter }
+func (t *openapiCallTool) Mutation() tools.Mutation { return tools.MutationMutating }
 
 func (t *openapiCallTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
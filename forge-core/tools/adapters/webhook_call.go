@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/initializ/forge/forge-core/security"
 	"github.com/initializ/forge/forge-core/tools"
 )
 
@@ -24,6 +25,7 @@ type webhookCallInput struct {
 func (t *webhookCallTool) Name() string             { return "webhook_call" }
 func (t *webhookCallTool) Description() string      { return "POST JSON payload to a webhook URL" }
 func (t *webhookCallTool) Category() tools.Category { return tools.CategoryAdapter }
+func (t *webhookCallTool) Mutation() tools.Mutation { return tools.MutationMutating }
 
 func (t *webhookCallTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -53,7 +55,7 @@ func (t *webhookCallTool) Execute(ctx context.Context, args json.RawMessage) (st
 		req.Header.Set(k, v)
 	}
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := security.EgressClient(ctx, 30*time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("webhook call: %w", err)
@@ -19,8 +19,9 @@ func TestRegisterAll(t *testing.T) {
 	}
 
 	expected := []string{
-		"http_request", "json_parse", "csv_parse",
+		"http_request", "json_parse", "json_transform", "csv_parse",
 		"datetime_now", "uuid_generate", "math_calculate", "web_search",
+		"pdf_extract", "file_read", "file_write", "sql_query",
 	}
 	for _, name := range expected {
 		if reg.Get(name) == nil {
@@ -199,14 +200,115 @@ func TestWebSearchTool_NoKey(t *testing.T) {
 		}
 	}()
 
-	tool := GetByName("web_search")
-	args, _ := json.Marshal(map[string]string{"query": "test"})
-	result, err := tool.Execute(context.Background(), args)
+	// Without keys, the keyless DuckDuckGo provider is used
+	provider, err := resolveWebSearchProvider()
 	if err != nil {
-		t.Fatalf("Execute error: %v", err)
+		t.Fatalf("resolveWebSearchProvider error: %v", err)
+	}
+	if provider.name() != "duckduckgo" {
+		t.Errorf("expected duckduckgo provider, got %q", provider.name())
+	}
+}
+
+func TestWebSearchTool_ExplicitDuckDuckGo(t *testing.T) {
+	t.Setenv("TAVILY_API_KEY", "some-tavily-key")
+	t.Setenv("WEB_SEARCH_PROVIDER", "duckduckgo")
+
+	provider, err := resolveWebSearchProvider()
+	if err != nil {
+		t.Fatalf("resolveWebSearchProvider error: %v", err)
+	}
+	if provider.name() != "duckduckgo" {
+		t.Errorf("expected duckduckgo provider, got %q", provider.name())
+	}
+}
+
+func TestWebSearchTool_CacheableResult(t *testing.T) {
+	tool := &webSearchTool{}
+	if !tool.CacheableResult(`{"results": [{"title": "Go"}]}`) {
+		t.Error("search results are not cacheable")
+	}
+	if tool.CacheableResult(`{"error": "DuckDuckGo returned status 202; try again later"}`) {
+		t.Error("error result is cacheable")
+	}
+}
+
+const ddgTestPage = `<html><body>
+<div class="result results_links results_links_deep result--ad">
+  <h2 class="result__title"><a rel="nofollow" class="result__a" href="https://duckduckgo.com/y.js?ad_domain=ads.example&amp;u3=x">Sponsored thing</a></h2>
+  <a class="result__snippet" href="https://duckduckgo.com/y.js?u3=x">Buy now</a>
+</div>
+<div class="result results_links results_links_deep web-result">
+  <h2 class="result__title">
+    <a rel="nofollow" class="result__a" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fgo.dev%2Fdoc%2F&amp;rut=abc">The Go <b>Programming</b> Language</a>
+  </h2>
+  <a class="result__snippet" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fgo.dev%2Fdoc%2F">Documentation for <b>Go</b> &amp; its tools.</a>
+</div>
+<div class="result results_links results_links_deep web-result">
+  <h2 class="result__title"><a class="result__a" href="https://pkg.go.dev/">Go Packages</a></h2>
+</div>
+<div class="result results_links results_links_deep web-result">
+  <h2 class="result__title"><a class="result__a" href="https://gobyexample.com/">Go by Example</a></h2>
+  <a class="result__snippet" href="https://gobyexample.com/">Hands-on introduction.</a>
+</div>
+</body></html>`
+
+func TestWebSearchTool_DuckDuckGoProvider(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/html/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if q := r.FormValue("q"); q != "golang docs" {
+			t.Errorf("q = %q", q)
+		}
+		if df := r.FormValue("df"); df != "w" {
+			t.Errorf("df = %q, want w", df)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(ddgTestPage)) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	p := &duckduckgoProvider{baseURL: ts.URL}
+	result, err := p.search(context.Background(), "golang docs", webSearchOpts{MaxResults: 2, TimeRange: "week"})
+	if err != nil {
+		t.Fatalf("search error: %v", err)
+	}
+
+	var out struct {
+		Query   string              `json:"query"`
+		Results []map[string]string `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(result), &out); err != nil {
+		t.Fatalf("decoding %q: %v", result, err)
 	}
-	if !strings.Contains(result, "TAVILY_API_KEY") || !strings.Contains(result, "PERPLEXITY_API_KEY") {
-		t.Errorf("expected error mentioning both API keys, got: %q", result)
+	if out.Query != "golang docs" || len(out.Results) != 2 {
+		t.Fatalf("result = %s", result)
+	}
+	first := out.Results[0]
+	if first["title"] != "The Go Programming Language" || first["url"] != "https://go.dev/doc/" || first["content"] != "Documentation for Go & its tools." {
+		t.Errorf("first result = %v", first)
+	}
+	second := out.Results[1]
+	if second["title"] != "Go Packages" || second["url"] != "https://pkg.go.dev/" || second["content"] != "" {
+		t.Errorf("second result = %v", second)
+	}
+}
+
+func TestWebSearchTool_DuckDuckGoRateLimited(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	p := &duckduckgoProvider{baseURL: ts.URL}
+	result, err := p.search(context.Background(), "test", webSearchOpts{})
+	if err != nil {
+		t.Fatalf("search error: %v", err)
+	}
+	if !strings.Contains(result, "status 202") {
+		t.Errorf("expected a status error, got: %q", result)
 	}
 }
 
@@ -381,3 +483,47 @@ func TestAllToolsHaveCategory(t *testing.T) {
 		}
 	}
 }
+
+func TestRegistryReadOnly(t *testing.T) {
+	reg := tools.NewRegistry()
+	if err := RegisterAll(reg); err != nil {
+		t.Fatalf("RegisterAll error: %v", err)
+	}
+	safe := reg.ReadOnly(nil)
+	if safe.Get("file_write") != nil {
+		t.Error("mutating tool file_write should be excluded in safe mode")
+	}
+	for _, name := range []string{"json_parse", "json_transform", "csv_parse", "datetime_now", "uuid_generate", "math_calculate", "web_search", "pdf_extract", "file_read", "sql_query"} {
+		if safe.Get(name) == nil {
+			t.Errorf("read-only tool %q should remain in safe mode", name)
+		}
+	}
+
+	// http_request stays, restricted to GET
+	httpTool := safe.Get("http_request")
+	if httpTool == nil {
+		t.Fatal("http_request should remain in safe mode as GET-only")
+	}
+	if tools.MutationOf(httpTool) != tools.MutationReadOnly {
+		t.Error("safe-mode http_request should be read-only")
+	}
+	if strings.Contains(string(httpTool.InputSchema()), "POST") {
+		t.Errorf("safe-mode schema still offers POST: %s", httpTool.InputSchema())
+	}
+	_, err := httpTool.Execute(context.Background(), json.RawMessage(`{"method":"POST","url":"http://127.0.0.1:1"}`))
+	if err == nil || !strings.Contains(err.Error(), "safe mode") {
+		t.Errorf("POST in safe mode: err = %v, want safe mode rejection", err)
+	}
+	if tools.MutationOf(reg.Get("http_request")) != tools.MutationMutating {
+		t.Error("full http_request should be mutating")
+	}
+
+	// Explicitly allowed mutating tools are kept as they are
+	allowed := reg.ReadOnly([]string{"file_write", "http_request"})
+	if allowed.Get("file_write") == nil {
+		t.Error("allowed tool file_write should remain")
+	}
+	if tools.MutationOf(allowed.Get("http_request")) != tools.MutationMutating {
+		t.Error("allowed http_request should keep all methods")
+	}
+}
@@ -21,6 +21,7 @@ type csvParseInput struct {
 func (t *csvParseTool) Name() string             { return "csv_parse" }
 func (t *csvParseTool) Description() string      { return "Parse CSV data into JSON array" }
 func (t *csvParseTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *csvParseTool) Mutation() tools.Mutation { return tools.MutationReadOnly }
 
 func (t *csvParseTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
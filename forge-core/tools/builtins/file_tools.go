@@ -0,0 +1,190 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// fileMaxBytes bounds how much file_read returns and file_write accepts.
+const fileMaxBytes = 1 << 20 // 1MB
+
+// workspaceEnv names the directory the file tools are confined to. When it
+// is unset file_read and file_write are not registered, and pdf_extract
+// only accepts URLs.
+const workspaceEnv = "FORGE_WORKSPACE"
+
+type fileReadTool struct{}
+
+type fileReadInput struct {
+	Path   string `json:"path"`
+	Offset int64  `json:"offset,omitempty"`
+	Length int64  `json:"length,omitempty"`
+}
+
+func (t *fileReadTool) Name() string { return "file_read" }
+func (t *fileReadTool) Description() string {
+	return "Read a text file in the agent's workspace, optionally a byte range"
+}
+func (t *fileReadTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *fileReadTool) Mutation() tools.Mutation { return tools.MutationReadOnly }
+
+func (t *fileReadTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string", "description": "File path relative to the workspace root"},
+			"offset": {"type": "integer", "description": "Byte offset to start reading at (default 0)"},
+			"length": {"type": "integer", "description": "Maximum bytes to read (default and maximum 1048576)"}
+		},
+		"required": ["path"]
+	}`)
+}
+
+func (t *fileReadTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input fileReadInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	if input.Offset < 0 || input.Length < 0 {
+		return "", fmt.Errorf("offset and length must not be negative")
+	}
+	root, err := workspaceRoot()
+	if err != nil {
+		return "", err
+	}
+	resolved, err := resolveInWorkspace(root, input.Path)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(resolved)
+	if err != nil {
+		return "", fmt.Errorf("opening %s: %w", input.Path, err)
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("opening %s: %w", input.Path, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", input.Path)
+	}
+
+	length := input.Length
+	if length == 0 || length > fileMaxBytes {
+		length = fileMaxBytes
+	}
+	// Read one byte more than needed to tell whether the file goes on
+	data, err := io.ReadAll(io.LimitReader(io.NewSectionReader(f, input.Offset, length+1), length+1))
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", input.Path, err)
+	}
+	more := int64(len(data)) > length
+	if more {
+		data = data[:length]
+		// Don't split a multi-byte character at the cut
+		for i := 0; i < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("%s is not a text file", input.Path)
+	}
+
+	out := string(data)
+	// A requested range is returned as is; hitting the size limit is noted
+	if more && (input.Length == 0 || input.Length > fileMaxBytes) {
+		end := input.Offset + int64(len(data))
+		out += fmt.Sprintf("\n\n[TRUNCATED at byte %d of %d; read again with offset %d for more]", end, info.Size(), end)
+	}
+	return out, nil
+}
+
+type fileWriteTool struct{}
+
+type fileWriteInput struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+func (t *fileWriteTool) Name() string { return "file_write" }
+func (t *fileWriteTool) Description() string {
+	return "Create or overwrite a text file in the agent's workspace"
+}
+func (t *fileWriteTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *fileWriteTool) Mutation() tools.Mutation { return tools.MutationMutating }
+
+func (t *fileWriteTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string", "description": "File path relative to the workspace root; missing directories are created"},
+			"content": {"type": "string", "description": "Full file content (at most 1048576 bytes)"}
+		},
+		"required": ["path", "content"]
+	}`)
+}
+
+func (t *fileWriteTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input fileWriteInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	if len(input.Content) > fileMaxBytes {
+		return "", fmt.Errorf("content is %d bytes, exceeds limit of %d", len(input.Content), fileMaxBytes)
+	}
+	// Never default to the working directory, which holds the agent's own
+	// config and tools
+	if os.Getenv(workspaceEnv) == "" {
+		return "", fmt.Errorf("file_write requires %s to be set", workspaceEnv)
+	}
+	root, err := workspaceRoot()
+	if err != nil {
+		return "", err
+	}
+	path, err := workspacePath(root, input.Path)
+	if err != nil {
+		return "", err
+	}
+
+	// The nearest existing directory must resolve inside the workspace, so
+	// a symlinked directory cannot redirect the write
+	dir := filepath.Dir(path)
+	existing := dir
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+	resolvedDir, err := filepath.EvalSymlinks(existing)
+	if err != nil || !withinRoot(root, resolvedDir) {
+		return "", fmt.Errorf("path %s is outside the workspace", input.Path)
+	}
+	// An existing file may itself be a symlink
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		if !withinRoot(root, resolved) {
+			return "", fmt.Errorf("path %s is outside the workspace", input.Path)
+		}
+		path = resolved
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("opening %s: %w", input.Path, err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating directory for %s: %w", input.Path, err)
+	}
+	if err := os.WriteFile(path, []byte(input.Content), 0o644); err != nil {
+		return "", fmt.Errorf("writing %s: %w", input.Path, err)
+	}
+	return fmt.Sprintf("wrote %d bytes to %s", len(input.Content), filepath.ToSlash(filepath.Clean(input.Path))), nil
+}
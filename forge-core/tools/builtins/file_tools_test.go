@@ -0,0 +1,191 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+func runFileTool(t *testing.T, name string, input map[string]any) (string, error) {
+	t.Helper()
+	args, _ := json.Marshal(input)
+	return GetByName(name).Execute(context.Background(), args)
+}
+
+func TestFileTools_ReadWrite(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(workspaceEnv, dir)
+
+	out, err := runFileTool(t, "file_write", map[string]any{"path": "notes/today.txt", "content": "hello, world"})
+	if err != nil {
+		t.Fatalf("file_write: %v", err)
+	}
+	if out != "wrote 12 bytes to notes/today.txt" {
+		t.Errorf("file_write result = %q", out)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "notes", "today.txt")); string(data) != "hello, world" {
+		t.Errorf("file content = %q", data)
+	}
+
+	out, err = runFileTool(t, "file_read", map[string]any{"path": "notes/today.txt"})
+	if err != nil || out != "hello, world" {
+		t.Errorf("file_read = %q, %v", out, err)
+	}
+	out, err = runFileTool(t, "file_read", map[string]any{"path": "notes/today.txt", "offset": 7, "length": 5})
+	if err != nil || out != "world" {
+		t.Errorf("file_read range = %q, %v", out, err)
+	}
+
+	// Overwrite
+	if _, err := runFileTool(t, "file_write", map[string]any{"path": "notes/today.txt", "content": "bye"}); err != nil {
+		t.Fatalf("file_write overwrite: %v", err)
+	}
+	if out, _ := runFileTool(t, "file_read", map[string]any{"path": "notes/today.txt"}); out != "bye" {
+		t.Errorf("after overwrite = %q", out)
+	}
+}
+
+func TestFileTools_PathTraversal(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "workspace")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(parent, "secret.txt")
+	os.WriteFile(secret, []byte("top secret"), 0o644) //nolint:errcheck
+	if err := os.Symlink(secret, filepath.Join(dir, "link.txt")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := os.Symlink(parent, filepath.Join(dir, "up")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(workspaceEnv, dir)
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"dot dot", "../secret.txt", "outside the workspace"},
+		{"nested dot dot", "a/../../secret.txt", "outside the workspace"},
+		{"absolute", secret, "must be relative"},
+		{"symlinked file", "link.txt", "outside the workspace"},
+		{"symlinked dir", "up/secret.txt", "outside the workspace"},
+		{"empty", "", "path is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runFileTool(t, "file_read", map[string]any{"path": tt.path}); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("file_read error = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := runFileTool(t, "file_write", map[string]any{"path": tt.path, "content": "pwned"}); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("file_write error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := runFileTool(t, "file_write", map[string]any{"path": "up/new/file.txt", "content": "pwned"}); err == nil {
+		t.Error("file_write created directories through a symlink out of the workspace")
+	}
+
+	if data, _ := os.ReadFile(secret); string(data) != "top secret" {
+		t.Errorf("file outside the workspace was modified: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "new")); err == nil {
+		t.Error("directory created outside the workspace")
+	}
+}
+
+func TestFileTools_SizeLimit(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(workspaceEnv, dir)
+
+	big := strings.Repeat("x", fileMaxBytes+100)
+	os.WriteFile(filepath.Join(dir, "big.txt"), []byte(big), 0o644) //nolint:errcheck
+
+	out, err := runFileTool(t, "file_read", map[string]any{"path": "big.txt"})
+	if err != nil {
+		t.Fatalf("file_read: %v", err)
+	}
+	content, note, ok := strings.Cut(out, "\n\n[TRUNCATED")
+	if !ok {
+		t.Fatalf("expected a truncation note, got %d bytes", len(out))
+	}
+	if len(content) != fileMaxBytes {
+		t.Errorf("returned %d bytes, want %d", len(content), fileMaxBytes)
+	}
+	if !strings.Contains(note, "offset 1048576") {
+		t.Errorf("note = %q, want the offset to continue from", note)
+	}
+
+	// The rest can be read from the suggested offset
+	out, err = runFileTool(t, "file_read", map[string]any{"path": "big.txt", "offset": fileMaxBytes})
+	if err != nil || out != strings.Repeat("x", 100) {
+		t.Errorf("remainder = %d bytes, %v", len(out), err)
+	}
+
+	// Truncation never splits a multi-byte character
+	os.WriteFile(filepath.Join(dir, "utf8.txt"), []byte(strings.Repeat("a", fileMaxBytes-1)+"é"), 0o644) //nolint:errcheck
+	out, err = runFileTool(t, "file_read", map[string]any{"path": "utf8.txt"})
+	if err != nil {
+		t.Fatalf("file_read utf8: %v", err)
+	}
+	if content, _, _ := strings.Cut(out, "\n\n[TRUNCATED"); len(content) != fileMaxBytes-1 {
+		t.Errorf("returned %d bytes, want %d", len(content), fileMaxBytes-1)
+	}
+
+	if _, err := runFileTool(t, "file_write", map[string]any{"path": "huge.txt", "content": big}); err == nil || !strings.Contains(err.Error(), "exceeds limit") {
+		t.Errorf("file_write oversized content: err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "huge.txt")); err == nil {
+		t.Error("oversized content was written")
+	}
+}
+
+func TestFileTools_RequireWorkspace(t *testing.T) {
+	// The working directory holds the agent's .env, which must not be
+	// readable without an explicit workspace
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("OPENAI_API_KEY=sk-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	t.Setenv(workspaceEnv, "")
+	reg := tools.NewRegistry()
+	if err := RegisterAllWithConfig(reg, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"file_read", "file_write"} {
+		if reg.Get(name) != nil {
+			t.Errorf("%s registered without a workspace", name)
+		}
+		if Unavailable()[name] != workspaceEnv {
+			t.Errorf("Unavailable() = %v", Unavailable())
+		}
+	}
+	if _, err := runFileTool(t, "file_write", map[string]any{"path": "x.txt", "content": "x"}); err == nil || !strings.Contains(err.Error(), workspaceEnv) {
+		t.Errorf("file_write without a workspace: err = %v", err)
+	}
+	if out, err := runFileTool(t, "file_read", map[string]any{"path": ".env"}); err == nil || !strings.Contains(err.Error(), workspaceEnv) {
+		t.Errorf("file_read .env without a workspace = %q, %v", out, err)
+	}
+	if _, err := runPDFExtract(t, map[string]any{"path": ".env"}); err == nil || !strings.Contains(err.Error(), workspaceEnv) {
+		t.Errorf("pdf_extract .env without a workspace: err = %v", err)
+	}
+	if reg.Get("pdf_extract") == nil {
+		t.Error("pdf_extract should stay registered for URLs")
+	}
+
+	t.Setenv(workspaceEnv, t.TempDir())
+	reg = tools.NewRegistry()
+	if err := RegisterAllWithConfig(reg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if reg.Get("file_write") == nil {
+		t.Error("file_write not registered with a workspace")
+	}
+}
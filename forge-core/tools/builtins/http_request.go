@@ -2,18 +2,94 @@
 package builtins
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/initializ/forge/forge-core/security"
 	"github.com/initializ/forge/forge-core/tools"
 )
 
-type httpRequestTool struct{}
+// HTTPRequestConfig holds the retry and rate-limit settings of http_request,
+// read from its config block in forge.yaml. The zero value sends each
+// request once, unthrottled.
+type HTTPRequestConfig struct {
+	MaxRetries         int           // retries after a 429 or 5xx response to GET, HEAD, or OPTIONS
+	RetryUnsafeMethods bool          // also retry POST, PUT, DELETE, and other methods
+	RetryBackoff       time.Duration // first retry delay, doubled on each retry (default 500ms)
+	MaxRetryWait       time.Duration // longest single wait, including Retry-After (default 30s)
+	RateLimit          float64       // requests per second to any one host; 0 disables
+	Burst              int           // requests a host may receive at once (default 1)
+	RedactHeaders      []string      // header names or patterns such as "x-*-token" to redact, besides the defaults
+}
+
+// ParseHTTPRequestConfig reads max_retries, retry_unsafe_methods,
+// retry_backoff, max_retry_wait, rate_limit, burst, and redact_headers from
+// a tool config block. Durations are Go duration strings such as "500ms".
+func ParseHTTPRequestConfig(raw map[string]any) (HTTPRequestConfig, error) {
+	var cfg HTTPRequestConfig
+	cfg.MaxRetries = int(configNumber(raw["max_retries"]))
+	cfg.RateLimit = configNumber(raw["rate_limit"])
+	cfg.Burst = int(configNumber(raw["burst"]))
+	cfg.RetryUnsafeMethods, _ = raw["retry_unsafe_methods"].(bool)
+	if cfg.MaxRetries < 0 || cfg.RateLimit < 0 || cfg.Burst < 0 {
+		return HTTPRequestConfig{}, fmt.Errorf("max_retries, rate_limit, and burst must not be negative")
+	}
+	if list, ok := raw["redact_headers"].([]any); ok {
+		for _, v := range list {
+			if s, ok := v.(string); ok {
+				if _, err := path.Match(strings.ToLower(s), ""); err != nil {
+					return HTTPRequestConfig{}, fmt.Errorf("redact_headers: invalid pattern %q", s)
+				}
+				cfg.RedactHeaders = append(cfg.RedactHeaders, s)
+			}
+		}
+	}
+	for key, d := range map[string]*time.Duration{"retry_backoff": &cfg.RetryBackoff, "max_retry_wait": &cfg.MaxRetryWait} {
+		s, ok := raw[key].(string)
+		if !ok {
+			continue
+		}
+		v, err := time.ParseDuration(s)
+		if err != nil || v < 0 {
+			return HTTPRequestConfig{}, fmt.Errorf("%s: invalid duration %q", key, s)
+		}
+		*d = v
+	}
+	return cfg, nil
+}
+
+// configNumber converts a YAML or JSON number to float64, or 0.
+func configNumber(v any) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+// NewHTTPRequestTool creates http_request with the given settings.
+func NewHTTPRequestTool(cfg HTTPRequestConfig) tools.Tool {
+	return &httpRequestTool{cfg: cfg}
+}
+
+// httpRequestTool makes HTTP requests. Its read-only form, used in safe
+// mode, only sends GET.
+type httpRequestTool struct {
+	getOnly bool
+	cfg     HTTPRequestConfig
+}
 
 type httpRequestInput struct {
 	Method  string            `json:"method"`
@@ -24,14 +100,49 @@ type httpRequestInput struct {
 }
 
 func (t *httpRequestTool) Name() string             { return "http_request" }
-func (t *httpRequestTool) Description() string      { return "Make HTTP requests (GET, POST, PUT, DELETE)" }
 func (t *httpRequestTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *httpRequestTool) ReadOnly() tools.Tool {
+	return &httpRequestTool{getOnly: true, cfg: t.cfg}
+}
+
+func (t *httpRequestTool) Description() string {
+	if t.getOnly {
+		return "Make HTTP GET requests"
+	}
+	return "Make HTTP requests (GET, POST, PUT, DELETE)"
+}
+
+func (t *httpRequestTool) Mutation() tools.Mutation {
+	if t.getOnly {
+		return tools.MutationReadOnly
+	}
+	return tools.MutationMutating
+}
+
+// Cacheable allows GET requests to be cached; other methods change state.
+func (t *httpRequestTool) Cacheable(args json.RawMessage) bool {
+	var input httpRequestInput
+	return json.Unmarshal(args, &input) == nil && strings.EqualFold(input.Method, http.MethodGet)
+}
+
+// CacheableResult allows only 2xx responses to be cached, so a rate limit
+// or server error is not replayed after the service recovers.
+func (t *httpRequestTool) CacheableResult(result string) bool {
+	var out struct {
+		Status int `json:"status"`
+	}
+	return json.Unmarshal([]byte(result), &out) == nil && out.Status >= 200 && out.Status < 300
+}
 
 func (t *httpRequestTool) InputSchema() json.RawMessage {
+	methods := `["GET", "POST", "PUT", "DELETE"]`
+	if t.getOnly {
+		methods = `["GET"]`
+	}
 	return json.RawMessage(`{
 		"type": "object",
 		"properties": {
-			"method": {"type": "string", "enum": ["GET", "POST", "PUT", "DELETE"], "description": "HTTP method"},
+			"method": {"type": "string", "enum": ` + methods + `, "description": "HTTP method"},
 			"url": {"type": "string", "description": "URL to send the request to"},
 			"headers": {"type": "object", "additionalProperties": {"type": "string"}, "description": "Request headers"},
 			"body": {"type": "string", "description": "Request body"},
@@ -46,43 +157,242 @@ func (t *httpRequestTool) Execute(ctx context.Context, args json.RawMessage) (st
 	if err := json.Unmarshal(args, &input); err != nil {
 		return "", fmt.Errorf("parsing input: %w", err)
 	}
+	if t.getOnly && !strings.EqualFold(input.Method, http.MethodGet) {
+		return "", fmt.Errorf("method %s is not allowed in safe mode; only GET is", input.Method)
+	}
 
 	timeout := time.Duration(input.Timeout) * time.Second
 	if timeout == 0 {
 		timeout = 30 * time.Second
 	}
 
-	var bodyReader io.Reader
-	if input.Body != "" {
-		bodyReader = strings.NewReader(input.Body)
+	if err := security.EgressEnforcerFrom(ctx).CheckURL(input.URL); err != nil {
+		return "", err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, input.Method, input.URL, bodyReader)
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
-	}
+	// Buffer the body so every attempt can send it
+	body := []byte(input.Body)
+	client := security.EgressClient(ctx, timeout)
 
-	for k, v := range input.Headers {
-		req.Header.Set(k, v)
-	}
+	var (
+		resp    *http.Response
+		retries int
+	)
+	for {
+		req, err := http.NewRequestWithContext(ctx, input.Method, input.URL, bytes.NewReader(body))
+		if err != nil {
+			return "", fmt.Errorf("creating request: %w", err)
+		}
+		for k, v := range input.Headers {
+			req.Header.Set(k, v)
+		}
+		if t.cfg.RateLimit > 0 {
+			if err := httpHostLimiter.wait(ctx, req.URL.Host, t.cfg.RateLimit, t.cfg.Burst); err != nil {
+				return "", fmt.Errorf("waiting for rate limit: %w", err)
+			}
+		}
 
-	client := &http.Client{Timeout: timeout}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("executing request: %w", err)
+		resp, err = client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("executing request: %w", err)
+		}
+		if retries == t.cfg.MaxRetries || !retryableStatus(resp.StatusCode) ||
+			(!t.cfg.RetryUnsafeMethods && !idempotentMethod(req.Method)) {
+			break
+		}
+		wait, ok := t.retryWait(resp, retries)
+		if !ok {
+			break
+		}
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+		_ = resp.Body.Close()
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("executing request: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+		retries++
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 1MB limit
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 1MB limit
 	if err != nil {
 		return "", fmt.Errorf("reading response: %w", err)
 	}
 
+	headers := make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		headers[k] = strings.Join(v, ", ")
+	}
 	result := map[string]any{
 		"status":      resp.StatusCode,
 		"status_text": resp.Status,
-		"body":        string(body),
+		"headers":     redactHeaders(headers, t.cfg.RedactHeaders),
+		"body":        string(respBody),
+	}
+	if retries > 0 {
+		result["retries"] = retries
 	}
 	data, _ := json.Marshal(result)
 	return string(data), nil
 }
+
+// RedactInput replaces the values of sensitive request headers, so logs
+// and transcripts do not show credentials.
+func (t *httpRequestTool) RedactInput(args json.RawMessage) json.RawMessage {
+	var input map[string]any
+	if err := json.Unmarshal(args, &input); err != nil {
+		return args
+	}
+	raw, ok := input["headers"].(map[string]any)
+	if !ok {
+		return args
+	}
+	headers := make(map[string]string, len(raw))
+	for k, v := range raw {
+		headers[k] = fmt.Sprint(v)
+	}
+	input["headers"] = redactHeaders(headers, t.cfg.RedactHeaders)
+	out, err := json.Marshal(input)
+	if err != nil {
+		return args
+	}
+	return out
+}
+
+// redactedValue replaces the value of a sensitive header.
+const redactedValue = "[REDACTED]"
+
+// defaultRedactedHeaders are always redacted.
+var defaultRedactedHeaders = []string{"authorization", "proxy-authorization", "x-api-key", "cookie", "set-cookie"}
+
+// RedactHeaders returns a copy of headers with the values of credential
+// headers, such as Authorization, X-Api-Key, and Cookie, replaced by
+// "[REDACTED]". Names are matched case-insensitively.
+func RedactHeaders(headers map[string]string) map[string]string {
+	return redactHeaders(headers, nil)
+}
+
+// redactHeaders is RedactHeaders with extra name patterns, matched with
+// path.Match against the lower-cased header name.
+func redactHeaders(headers map[string]string, extra []string) map[string]string {
+	out := make(map[string]string, len(headers))
+	for k, v := range headers {
+		name := strings.ToLower(k)
+		redact := slices.Contains(defaultRedactedHeaders, name)
+		for _, p := range extra {
+			if ok, _ := path.Match(strings.ToLower(p), name); ok {
+				redact = true
+			}
+		}
+		if redact {
+			v = redactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// retryableStatus reports whether a response with status code is worth
+// retrying: 429 Too Many Requests and server errors.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// idempotentMethod reports whether repeating a request with method cannot
+// change server state twice. Other methods are retried only when
+// retry_unsafe_methods is set.
+func idempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryWait returns how long to wait before retry number n+1: the server's
+// Retry-After if it sent one, else exponential backoff. It returns false when
+// Retry-After asks for longer than MaxRetryWait, since waiting would stall
+// the agent.
+func (t *httpRequestTool) retryWait(resp *http.Response, n int) (time.Duration, bool) {
+	maxWait := t.cfg.MaxRetryWait
+	if maxWait == 0 {
+		maxWait = 30 * time.Second
+	}
+	if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		return after, after <= maxWait
+	}
+	backoff := t.cfg.RetryBackoff
+	if backoff == 0 {
+		backoff = 500 * time.Millisecond
+	}
+	return min(backoff<<n, maxWait), true
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// httpHostLimiter throttles http_request per host across all calls in the
+// process.
+var httpHostLimiter = &hostLimiter{buckets: make(map[string]*tokenBucket)}
+
+// hostLimiter keeps one token bucket per host.
+type hostLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// tokenBucket holds tokens that refill at a steady rate. Tokens may go
+// negative: each caller reserves one and waits until it would have been
+// available, so waiters are served in order.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// wait blocks until a request to host is allowed under rate requests per
+// second with the given burst, or ctx is done.
+func (l *hostLimiter) wait(ctx context.Context, host string, rate float64, burst int) error {
+	burst = max(burst, 1)
+	now := time.Now()
+
+	l.mu.Lock()
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &tokenBucket{tokens: float64(burst), last: now}
+		l.buckets[host] = b
+	}
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*rate, float64(burst))
+	b.last = now
+	b.tokens--
+	delay := time.Duration(-b.tokens / rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// Give the reserved token back
+		l.mu.Lock()
+		b.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
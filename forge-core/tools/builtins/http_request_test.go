@@ -0,0 +1,334 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+func runHTTPRequest(t *testing.T, tool tools.Tool, input map[string]any) map[string]any {
+	t.Helper()
+	args, _ := json.Marshal(input)
+	out, err := tool.Execute(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("decoding %q: %v", out, err)
+	}
+	return result
+}
+
+func TestHTTPRequestTool_RetriesThenSucceeds(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"forge"}` {
+			t.Errorf("attempt %d body = %q", calls.Load()+1, body)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("created")) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	// POST is not retried unless retry_unsafe_methods is set
+	tool := NewHTTPRequestTool(HTTPRequestConfig{MaxRetries: 2, RetryBackoff: time.Millisecond})
+	result := runHTTPRequest(t, tool, map[string]any{"method": "POST", "url": ts.URL, "body": `{"name":"forge"}`})
+	if result["status"] != float64(503) || result["retries"] != nil || calls.Load() != 1 {
+		t.Errorf("POST: result = %v after %d requests", result, calls.Load())
+	}
+
+	calls.Store(0)
+	tool = NewHTTPRequestTool(HTTPRequestConfig{MaxRetries: 2, RetryUnsafeMethods: true, RetryBackoff: time.Millisecond})
+	result = runHTTPRequest(t, tool, map[string]any{"method": "POST", "url": ts.URL, "body": `{"name":"forge"}`})
+	if result["status"] != float64(200) || result["body"] != "created" || result["retries"] != float64(1) {
+		t.Errorf("result = %v", result)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("server saw %d requests, want 2", n)
+	}
+}
+
+func TestHTTPRequestTool_RetryLimits(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/slow-down" {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	// Without config a failure is returned as is
+	result := runHTTPRequest(t, GetByName("http_request"), map[string]any{"method": "GET", "url": ts.URL})
+	if result["status"] != float64(502) || calls.Load() != 1 {
+		t.Errorf("default: result = %v after %d requests", result, calls.Load())
+	}
+
+	calls.Store(0)
+	tool := NewHTTPRequestTool(HTTPRequestConfig{MaxRetries: 2, RetryBackoff: time.Millisecond})
+	result = runHTTPRequest(t, tool, map[string]any{"method": "GET", "url": ts.URL})
+	if result["status"] != float64(502) || result["retries"] != float64(2) || calls.Load() != 3 {
+		t.Errorf("exhausted: result = %v after %d requests", result, calls.Load())
+	}
+
+	// A Retry-After beyond max_retry_wait is not waited out
+	calls.Store(0)
+	tool = NewHTTPRequestTool(HTTPRequestConfig{MaxRetries: 2, MaxRetryWait: time.Second})
+	result = runHTTPRequest(t, tool, map[string]any{"method": "GET", "url": ts.URL + "/slow-down"})
+	if result["status"] != float64(429) || calls.Load() != 1 {
+		t.Errorf("long Retry-After: result = %v after %d requests", result, calls.Load())
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"3", 3 * time.Second, true},
+		{"Fri, 02 Jan 2026 15:04:15 GMT", 10 * time.Second, true},
+		{"Fri, 02 Jan 2026 15:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHostLimiter(t *testing.T) {
+	l := &hostLimiter{buckets: make(map[string]*tokenBucket)}
+	ctx := context.Background()
+
+	// 50 requests per second with no burst: three requests span two intervals
+	start := time.Now()
+	for range 3 {
+		if err := l.wait(ctx, "api.example.com", 50, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("three requests took %v, want about 40ms", elapsed)
+	}
+
+	// Other hosts have their own bucket
+	start = time.Now()
+	if err := l.wait(ctx, "other.example.com", 50, 1); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("first request to another host waited %v", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	l.wait(ctx, "slow.example.com", 0.1, 1) //nolint:errcheck
+	if err := l.wait(cancelled, "slow.example.com", 0.1, 1); err == nil {
+		t.Error("expected an error when the context is done")
+	}
+}
+
+func TestParseHTTPRequestConfig(t *testing.T) {
+	cfg, err := ParseHTTPRequestConfig(map[string]any{
+		"max_retries":          3,
+		"retry_unsafe_methods": true,
+		"retry_backoff":        "250ms",
+		"max_retry_wait":       "10s",
+		"rate_limit":           2.5,
+		"burst":                5,
+		"redact_headers":       []any{"X-Session-Id", "x-*-token"},
+		"guidance":             "ignored",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := HTTPRequestConfig{
+		MaxRetries: 3, RetryUnsafeMethods: true, RetryBackoff: 250 * time.Millisecond, MaxRetryWait: 10 * time.Second, RateLimit: 2.5, Burst: 5,
+		RedactHeaders: []string{"X-Session-Id", "x-*-token"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("cfg = %+v, want %+v", cfg, want)
+	}
+
+	if _, err := ParseHTTPRequestConfig(map[string]any{"retry_backoff": "soon"}); err == nil {
+		t.Error("expected an error for an invalid duration")
+	}
+	if _, err := ParseHTTPRequestConfig(map[string]any{"max_retries": -1}); err == nil {
+		t.Error("expected an error for negative retries")
+	}
+	if _, err := ParseHTTPRequestConfig(map[string]any{"redact_headers": []any{"x-["}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestRegisterAllWithConfig(t *testing.T) {
+	reg := tools.NewRegistry()
+	err := RegisterAllWithConfig(reg, map[string]map[string]any{"http_request": {"max_retries": 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg := reg.Get("http_request").(*httpRequestTool).cfg; cfg.MaxRetries != 4 {
+		t.Errorf("http_request config = %+v", cfg)
+	}
+	// The safe-mode variant keeps the settings
+	if cfg := reg.ReadOnly(nil).Get("http_request").(*httpRequestTool).cfg; cfg.MaxRetries != 4 {
+		t.Errorf("read-only http_request config = %+v", cfg)
+	}
+
+	reg = tools.NewRegistry()
+	err = RegisterAllWithConfig(reg, map[string]map[string]any{"http_request": {"retry_backoff": "soon"}})
+	if err == nil {
+		t.Error("expected a config error")
+	}
+	if reg.Get("http_request") == nil || len(reg.List()) != len(All())-len(Unavailable()) {
+		t.Errorf("registered %v, want every available builtin", reg.List())
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	in := map[string]string{
+		"authorization": "Bearer secret",
+		"X-API-Key":     "k-123",
+		"Cookie":        "session=abc",
+		"Accept":        "application/json",
+	}
+	got := RedactHeaders(in)
+	want := map[string]string{
+		"authorization": "[REDACTED]",
+		"X-API-Key":     "[REDACTED]",
+		"Cookie":        "[REDACTED]",
+		"Accept":        "application/json",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactHeaders() = %v, want %v", got, want)
+	}
+	if in["authorization"] != "Bearer secret" {
+		t.Error("RedactHeaders modified its input")
+	}
+
+	got = redactHeaders(map[string]string{"X-Auth-Token": "t", "X-Request-Id": "r"}, []string{"x-*-token"})
+	if got["X-Auth-Token"] != "[REDACTED]" || got["X-Request-Id"] != "r" {
+		t.Errorf("redactHeaders() with patterns = %v", got)
+	}
+}
+
+func TestHTTPRequestTool_RedactsHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The outgoing request carries the real values
+		if got := r.Header.Get("Authorization"); got != "Bearer real-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := r.Header.Get("X-Auth-Token"); got != "real-custom" {
+			t.Errorf("X-Auth-Token = %q", got)
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "server-secret"})
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Write([]byte("ok")) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	tool := NewHTTPRequestTool(HTTPRequestConfig{RedactHeaders: []string{"x-*-token"}})
+	input := map[string]any{
+		"method":  "GET",
+		"url":     ts.URL,
+		"headers": map[string]string{"Authorization": "Bearer real-token", "X-Auth-Token": "real-custom", "Accept": "text/plain"},
+	}
+	result := runHTTPRequest(t, tool, input)
+	headers, _ := result["headers"].(map[string]any)
+	if headers["Set-Cookie"] != "[REDACTED]" || headers["X-Request-Id"] != "req-1" {
+		t.Errorf("result headers = %v", headers)
+	}
+
+	// Logs and transcripts see the redacted input
+	reg := tools.NewRegistry()
+	reg.Register(tool) //nolint:errcheck
+	args, _ := json.Marshal(input)
+	shown := reg.RedactInput("http_request", string(args))
+	if strings.Contains(shown, "real-token") || strings.Contains(shown, "real-custom") {
+		t.Errorf("RedactInput() leaked a secret: %s", shown)
+	}
+	if !strings.Contains(shown, "text/plain") || !strings.Contains(shown, ts.URL) {
+		t.Errorf("RedactInput() = %s, want other fields kept", shown)
+	}
+	if got := reg.RedactInput("json_parse", `{"data":"x"}`); got != `{"data":"x"}` {
+		t.Errorf("RedactInput() for an unknown tool = %s", got)
+	}
+}
+
+func TestHTTPRequestTool_Cacheable(t *testing.T) {
+	tool := GetByName("http_request").(tools.Cacheable)
+	if !tool.Cacheable(json.RawMessage(`{"method": "get", "url": "https://example.com"}`)) {
+		t.Error("GET request is not cacheable")
+	}
+	if tool.Cacheable(json.RawMessage(`{"method": "POST", "url": "https://example.com"}`)) {
+		t.Error("POST request is cacheable")
+	}
+
+	results := tool.(tools.ResultCacheable)
+	for status, want := range map[int]bool{200: true, 204: true, 304: false, 404: false, 429: false, 503: false} {
+		result := fmt.Sprintf(`{"status": %d, "body": ""}`, status)
+		if got := results.CacheableResult(result); got != want {
+			t.Errorf("CacheableResult(status %d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestHTTPRequestTool_Egress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://evil.example.com/", http.StatusFound)
+			return
+		}
+		w.Write([]byte("ok")) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	reg := tools.NewRegistry()
+	reg.Register(GetByName("http_request")) //nolint:errcheck
+	reg.SetEgress(security.NewEgressEnforcer(&security.EgressConfig{
+		Mode:       security.ModeAllowlist,
+		AllDomains: []string{"api.github.com"},
+	}))
+	run := func(url string) (string, error) {
+		args, _ := json.Marshal(map[string]any{"method": "GET", "url": url})
+		return reg.Execute(context.Background(), "http_request", args)
+	}
+
+	_, err := run("https://evil.example.com/data")
+	if !errors.Is(err, security.ErrEgressDenied) || !strings.Contains(err.Error(), "not in allowlist") {
+		t.Errorf("denied host: err = %v", err)
+	}
+	// The test server is on loopback, which is always allowed
+	if out, err := run(ts.URL); err != nil || !strings.Contains(out, `"body":"ok"`) {
+		t.Errorf("allowed host = %s, %v", out, err)
+	}
+	// Redirects are checked too
+	if _, err := run(ts.URL + "/redirect"); !errors.Is(err, security.ErrEgressDenied) {
+		t.Errorf("redirect to a denied host: err = %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package builtins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/itchyny/gojq"
+
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+const (
+	jsonTransformTimeout   = 5 * time.Second
+	jsonTransformMaxOutput = 1 << 20 // 1MB
+)
+
+type jsonTransformTool struct{}
+
+type jsonTransformInput struct {
+	Data       string `json:"data"`
+	Expression string `json:"expression"`
+}
+
+func (t *jsonTransformTool) Name() string { return "json_transform" }
+func (t *jsonTransformTool) Description() string {
+	return "Transform JSON data with a jq expression"
+}
+func (t *jsonTransformTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *jsonTransformTool) Mutation() tools.Mutation { return tools.MutationReadOnly }
+
+func (t *jsonTransformTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"data": {"type": "string", "description": "JSON string to transform, such as an http_request response body"},
+			"expression": {"type": "string", "description": "jq expression, e.g. '.items[] | {id, name}' or '[.[] | select(.active)] | length'"}
+		},
+		"required": ["data", "expression"]
+	}`)
+}
+
+func (t *jsonTransformTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input jsonTransformInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+
+	query, err := gojq.Parse(input.Expression)
+	if err != nil {
+		return "", fmt.Errorf("invalid expression: %w", err)
+	}
+	// Hide the process environment, which holds API keys, from env and $ENV
+	code, err := gojq.Compile(query, gojq.WithEnvironLoader(func() []string { return nil }))
+	if err != nil {
+		return "", fmt.Errorf("invalid expression: %w", err)
+	}
+
+	// Keep large integers exact; gojq normalizes json.Number values
+	dec := json.NewDecoder(strings.NewReader(input.Data))
+	dec.UseNumber()
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jsonTransformTimeout)
+	defer cancel()
+
+	// One compact JSON value per line, like jq -c
+	var out bytes.Buffer
+	iter := code.RunWithContext(ctx, data)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, isErr := v.(error); isErr {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return "", fmt.Errorf("expression did not finish within %s", jsonTransformTimeout)
+			}
+			return "", fmt.Errorf("evaluating expression: %w", err)
+		}
+		b, err := gojq.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("encoding result: %w", err)
+		}
+		if out.Len()+len(b) > jsonTransformMaxOutput {
+			return "", fmt.Errorf("result exceeds %d bytes; narrow the expression", jsonTransformMaxOutput)
+		}
+		out.Write(b)
+		out.WriteByte('\n')
+	}
+	return strings.TrimSuffix(out.String(), "\n"), nil
+}
@@ -0,0 +1,72 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func runJSONTransform(t *testing.T, data, expr string) (string, error) {
+	t.Helper()
+	args, _ := json.Marshal(map[string]string{"data": data, "expression": expr})
+	return GetByName("json_transform").Execute(context.Background(), args)
+}
+
+func TestJSONTransformTool(t *testing.T) {
+	data := `{"items": [
+		{"id": 1, "name": "alpha", "active": true, "tags": ["a"]},
+		{"id": 2, "name": "beta", "active": false, "tags": []},
+		{"id": 9007199254740993, "name": "gamma", "active": true, "tags": ["c", "d"]}
+	]}`
+
+	tests := []struct {
+		name, expr, want string
+	}{
+		{"array mapping", `[.items[] | .name | ascii_upcase]`, `["ALPHA","BETA","GAMMA"]`},
+		{"field selection", `.items[] | select(.active) | {id, name}`, "{\"id\":1,\"name\":\"alpha\"}\n{\"id\":9007199254740993,\"name\":\"gamma\"}"},
+		{"aggregate", `[.items[].tags | length] | add`, `3`},
+		{"no output", `.items[] | select(.id > 1e20)`, ``},
+		{"environment hidden", `env | length`, `0`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runJSONTransform(t, data, tt.expr)
+			if err != nil {
+				t.Fatalf("json_transform: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONTransformTool_Errors(t *testing.T) {
+	tests := []struct {
+		name, data, expr, wantErr string
+	}{
+		{"malformed expression", `{}`, `.items[] | {`, "invalid expression"},
+		{"unknown function", `{}`, `frobnicate(.)`, "invalid expression"},
+		{"invalid JSON", `{"a":`, `.`, "invalid JSON"},
+		{"runtime error", `{"a": "x"}`, `.a + 1`, "evaluating expression"},
+		{"oversized result", `0`, `range(1000000) | "xxxxxxxxxx"`, "exceeds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runJSONTransform(t, tt.data, tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJSONTransformTool_Timeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	args, _ := json.Marshal(map[string]string{"data": `0`, "expression": `last(range(infinite))`})
+	if _, err := GetByName("json_transform").Execute(ctx, args); err == nil {
+		t.Error("expected an endless expression to stop when the context is done")
+	}
+}
@@ -0,0 +1,196 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/initializ/forge/forge-core/security"
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+const (
+	pdfMaxFileSize     = 20 << 20 // 20MB
+	pdfDefaultMaxChars = 50000
+)
+
+type pdfExtractTool struct{}
+
+type pdfExtractInput struct {
+	Path     string `json:"path,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Pages    string `json:"pages,omitempty"`
+	MaxChars int    `json:"max_chars,omitempty"`
+}
+
+type pdfPageText struct {
+	Page int    `json:"page"`
+	Text string `json:"text"`
+}
+
+func (t *pdfExtractTool) Name() string             { return "pdf_extract" }
+func (t *pdfExtractTool) Description() string      { return "Extract plain text from a PDF file or URL" }
+func (t *pdfExtractTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *pdfExtractTool) Mutation() tools.Mutation { return tools.MutationReadOnly }
+
+func (t *pdfExtractTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"path": {"type": "string", "description": "Path to a PDF file, relative to the agent's working directory"},
+			"url": {"type": "string", "description": "http(s) URL of a PDF to download (instead of path)"},
+			"pages": {"type": "string", "description": "Pages to extract, e.g. \"1-3,5\" or \"4-\" (default all)"},
+			"max_chars": {"type": "integer", "description": "Maximum characters of text to return (default 50000)"}
+		}
+	}`)
+}
+
+func (t *pdfExtractTool) Execute(ctx context.Context, args json.RawMessage) (string, error) {
+	var input pdfExtractInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return "", fmt.Errorf("parsing input: %w", err)
+	}
+	if (input.Path == "") == (input.URL == "") {
+		return "", fmt.Errorf("exactly one of path or url is required")
+	}
+
+	var data []byte
+	var err error
+	if input.Path != "" {
+		data, err = readSandboxedFile(input.Path, pdfMaxFileSize)
+	} else {
+		data, err = fetchPDF(ctx, input.URL, pdfMaxFileSize)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := parsePDF(data)
+	if err != nil {
+		if errors.Is(err, errPDFEncrypted) {
+			return "", err
+		}
+		return "", fmt.Errorf("parsing PDF: %w", err)
+	}
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return "", fmt.Errorf("parsing PDF: no pages found; the file may be corrupt")
+	}
+
+	selected, err := parsePageRange(input.Pages, len(pages))
+	if err != nil {
+		return "", err
+	}
+
+	maxChars := input.MaxChars
+	if maxChars <= 0 {
+		maxChars = pdfDefaultMaxChars
+	}
+
+	var out []pdfPageText
+	remaining := maxChars
+	truncated := false
+	for _, n := range selected {
+		if remaining <= 0 {
+			truncated = true
+			break
+		}
+		text, err := doc.pageText(pages[n-1])
+		if err != nil {
+			return "", fmt.Errorf("extracting page %d: %w", n, err)
+		}
+		if utf8.RuneCountInString(text) > remaining {
+			text = string([]rune(text)[:remaining])
+			truncated = true
+		}
+		remaining -= utf8.RuneCountInString(text)
+		out = append(out, pdfPageText{Page: n, Text: text})
+	}
+
+	result := map[string]any{
+		"page_count": len(pages),
+		"pages":      out,
+		"truncated":  truncated,
+	}
+	data, _ = json.Marshal(result)
+	return string(data), nil
+}
+
+// parsePageRange parses a selection such as "1-3,5,8-" into 1-based page
+// numbers. An empty spec selects every page.
+func parsePageRange(spec string, total int) ([]int, error) {
+	if strings.TrimSpace(spec) == "" {
+		pages := make([]int, total)
+		for i := range pages {
+			pages[i] = i + 1
+		}
+		return pages, nil
+	}
+
+	var pages []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, fmt.Errorf("invalid page range %q", part)
+		}
+		end := start
+		if isRange {
+			end = total
+			if h := strings.TrimSpace(hi); h != "" {
+				if end, err = strconv.Atoi(h); err != nil {
+					return nil, fmt.Errorf("invalid page range %q", part)
+				}
+			}
+		}
+		if start < 1 || end < start || end > total {
+			return nil, fmt.Errorf("page range %q out of bounds (document has %d pages)", part, total)
+		}
+		for p := start; p <= end; p++ {
+			if !seen[p] {
+				seen[p] = true
+				pages = append(pages, p)
+			}
+		}
+	}
+	return pages, nil
+}
+
+// fetchPDF downloads a PDF, refusing responses larger than maxSize.
+func fetchPDF(ctx context.Context, url string, maxSize int64) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("url must use http or https")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	client := security.EgressClient(ctx, 60*time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching PDF: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching PDF: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading PDF: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("PDF exceeds size limit of %d bytes", maxSize)
+	}
+	return data, nil
+}
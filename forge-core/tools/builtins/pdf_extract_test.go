@@ -0,0 +1,158 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type pdfExtractResult struct {
+	PageCount int           `json:"page_count"`
+	Pages     []pdfPageText `json:"pages"`
+	Truncated bool          `json:"truncated"`
+}
+
+func runPDFExtract(t *testing.T, input map[string]any) (pdfExtractResult, error) {
+	t.Helper()
+	args, _ := json.Marshal(input)
+	out, err := GetByName("pdf_extract").Execute(context.Background(), args)
+	var res pdfExtractResult
+	if err == nil {
+		if jerr := json.Unmarshal([]byte(out), &res); jerr != nil {
+			t.Fatalf("unmarshal result: %v", jerr)
+		}
+	}
+	return res, err
+}
+
+// setPDFWorkspace makes the package directory, holding testdata, the
+// workspace.
+func setPDFWorkspace(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(workspaceEnv, wd)
+}
+
+func TestPDFExtractTool(t *testing.T) {
+	setPDFWorkspace(t)
+	res, err := runPDFExtract(t, map[string]any{"path": "testdata/sample.pdf"})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if res.PageCount != 2 || len(res.Pages) != 2 {
+		t.Fatalf("expected 2 pages, got %+v", res)
+	}
+	if want := "Hello PDF World\nSecond line"; res.Pages[0].Text != want {
+		t.Errorf("page 1 text = %q, want %q", res.Pages[0].Text, want)
+	}
+	// Page 2 uses a compressed content stream and a ToUnicode CMap
+	if want := "Page two"; res.Pages[1].Text != want {
+		t.Errorf("page 2 text = %q, want %q", res.Pages[1].Text, want)
+	}
+	if res.Truncated {
+		t.Error("unexpected truncation")
+	}
+}
+
+func TestPDFExtractTool_PagesAndLimit(t *testing.T) {
+	setPDFWorkspace(t)
+	res, err := runPDFExtract(t, map[string]any{"path": "testdata/sample.pdf", "pages": "2"})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if len(res.Pages) != 1 || res.Pages[0].Page != 2 {
+		t.Fatalf("expected only page 2, got %+v", res.Pages)
+	}
+
+	res, err = runPDFExtract(t, map[string]any{"path": "testdata/sample.pdf", "max_chars": 5})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if !res.Truncated || len(res.Pages) != 1 || res.Pages[0].Text != "Hello" {
+		t.Errorf("expected truncation to 5 chars, got %+v", res)
+	}
+
+	if _, err := runPDFExtract(t, map[string]any{"path": "testdata/sample.pdf", "pages": "3"}); err == nil || !strings.Contains(err.Error(), "out of bounds") {
+		t.Errorf("expected out of bounds error, got %v", err)
+	}
+}
+
+func TestPDFExtractTool_URL(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write(data) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	res, err := runPDFExtract(t, map[string]any{"url": ts.URL + "/sample.pdf", "pages": "1"})
+	if err != nil {
+		t.Fatalf("Execute error: %v", err)
+	}
+	if len(res.Pages) != 1 || !strings.HasPrefix(res.Pages[0].Text, "Hello PDF World") {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
+
+func TestPDFExtractTool_Errors(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.pdf")
+	os.WriteFile(corrupt, []byte("%PDF-1.4\nthis is not really a pdf\n%%EOF\n"), 0644) //nolint:errcheck
+	encrypted := filepath.Join(dir, "encrypted.pdf")
+	os.WriteFile(encrypted, []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R /Encrypt 2 0 R >>\n%%EOF\n"), 0644) //nolint:errcheck
+
+	t.Setenv(workspaceEnv, dir)
+
+	tests := []struct {
+		name    string
+		input   map[string]any
+		wantErr string
+	}{
+		{"corrupt", map[string]any{"path": "corrupt.pdf"}, "parsing PDF"},
+		{"encrypted", map[string]any{"path": "encrypted.pdf"}, "encrypted"},
+		{"escape", map[string]any{"path": "../outside.pdf"}, "outside the workspace"},
+		{"absolute", map[string]any{"path": corrupt}, "must be relative"},
+		{"missing source", map[string]any{}, "exactly one of path or url"},
+		{"bad scheme", map[string]any{"url": "file:///etc/passwd"}, "http or https"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runPDFExtract(t, tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParsePageRange(t *testing.T) {
+	got, err := parsePageRange("1-2, 4-,2", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{1, 2, 4, 5}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+	for _, bad := range []string{"0", "3-1", "x", "6"} {
+		if _, err := parsePageRange(bad, 5); err == nil {
+			t.Errorf("parsePageRange(%q) expected error", bad)
+		}
+	}
+}
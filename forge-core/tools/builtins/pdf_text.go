@@ -0,0 +1,865 @@
+package builtins
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// This file implements a small PDF text extractor: enough of the object
+// syntax to walk the page tree, decode Flate content streams, and map
+// glyph codes to Unicode through ToUnicode CMaps or WinAnsi.
+
+var (
+	errPDFEncrypted = errors.New("PDF is encrypted; password-protected documents are not supported")
+	errPDFEOF       = errors.New("unexpected end of data")
+)
+
+// pdfMaxDecodedStream bounds the decompressed size of a single stream.
+const pdfMaxDecodedStream = 64 << 20
+
+type (
+	pdfName    string
+	pdfString  string
+	pdfKeyword string
+	pdfDict    map[string]any
+	pdfArray   []any
+	pdfRef     struct{ num, gen int }
+	pdfStream  struct {
+		dict pdfDict
+		data []byte
+	}
+)
+
+// pdfLexer reads PDF objects from a byte slice.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFWhite(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+func isPDFDelim(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFWhite(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (l *pdfLexer) peek(off int) byte {
+	if l.pos+off < len(l.data) {
+		return l.data[l.pos+off]
+	}
+	return 0
+}
+
+// readObject reads the next object. Operators and unmatched closing
+// delimiters are returned as pdfKeyword values.
+func (l *pdfLexer) readObject() (any, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, errPDFEOF
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		return l.readName(), nil
+	case c == '(':
+		return l.readLiteral()
+	case c == '<' && l.peek(1) == '<':
+		l.pos += 2
+		return l.readDict()
+	case c == '<':
+		return l.readHex()
+	case c == '>' && l.peek(1) == '>':
+		l.pos += 2
+		return pdfKeyword(">>"), nil
+	case c == '[':
+		l.pos++
+		return l.readArray()
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.readNumberOrRef(), nil
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhite(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		// Stray delimiter such as ')' or '{'
+		l.pos++
+	}
+	switch kw := string(l.data[start:l.pos]); kw {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	default:
+		return pdfKeyword(kw), nil
+	}
+}
+
+func (l *pdfLexer) readName() pdfName {
+	l.pos++ // '/'
+	var b strings.Builder
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhite(c) || isPDFDelim(c) {
+			break
+		}
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		b.WriteByte(c)
+		l.pos++
+	}
+	return pdfName(b.String())
+}
+
+func (l *pdfLexer) readLiteral() (pdfString, error) {
+	l.pos++ // '('
+	var b bytes.Buffer
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(b.String()), nil
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return "", errPDFEOF
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case '\r':
+				if l.peek(0) == '\n' {
+					l.pos++
+				}
+			case '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					b.WriteByte(byte(v))
+				} else {
+					b.WriteByte(e)
+				}
+			}
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return "", errPDFEOF
+}
+
+func (l *pdfLexer) readHex() (pdfString, error) {
+	l.pos++ // '<'
+	var digits []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			if len(digits)%2 == 1 {
+				digits = append(digits, '0')
+			}
+			out := make([]byte, len(digits)/2)
+			if _, err := hex.Decode(out, digits); err != nil {
+				return "", fmt.Errorf("invalid hex string: %w", err)
+			}
+			return pdfString(out), nil
+		}
+		if !isPDFWhite(c) {
+			digits = append(digits, c)
+		}
+	}
+	return "", errPDFEOF
+}
+
+func (l *pdfLexer) readDict() (pdfDict, error) {
+	d := pdfDict{}
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return nil, errPDFEOF
+		}
+		if l.data[l.pos] == '>' && l.peek(1) == '>' {
+			l.pos += 2
+			return d, nil
+		}
+		key, err := l.readObject()
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			return nil, fmt.Errorf("malformed dictionary at offset %d", l.pos)
+		}
+		val, err := l.readObject()
+		if err != nil {
+			return nil, err
+		}
+		d[string(name)] = val
+	}
+}
+
+func (l *pdfLexer) readArray() (pdfArray, error) {
+	var a pdfArray
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return nil, errPDFEOF
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return a, nil
+		}
+		v, err := l.readObject()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+}
+
+// readNumberOrRef reads a number, or an indirect reference "num gen R".
+func (l *pdfLexer) readNumberOrRef() any {
+	start := l.pos
+	for l.pos < len(l.data) && strings.IndexByte("+-.0123456789", l.data[l.pos]) >= 0 {
+		l.pos++
+	}
+	tok := string(l.data[start:l.pos])
+	f, _ := strconv.ParseFloat(tok, 64)
+
+	num, err := strconv.Atoi(tok)
+	if err != nil || tok[0] == '+' || tok[0] == '-' {
+		return f
+	}
+	save := l.pos
+	l.skipSpace()
+	genStart := l.pos
+	for l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+		l.pos++
+	}
+	if l.pos > genStart {
+		gen, _ := strconv.Atoi(string(l.data[genStart:l.pos]))
+		l.skipSpace()
+		if l.peek(0) == 'R' && (l.pos+1 >= len(l.data) || isPDFWhite(l.data[l.pos+1]) || isPDFDelim(l.data[l.pos+1])) {
+			l.pos++
+			return pdfRef{num: num, gen: gen}
+		}
+	}
+	l.pos = save
+	return f
+}
+
+// pdfDocument holds the objects of a parsed PDF file.
+type pdfDocument struct {
+	objects map[int]any
+	trailer pdfDict
+}
+
+var pdfObjRe = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// parsePDF scans data for indirect objects rather than trusting the xref
+// table, which makes it tolerant of files with broken offsets.
+func parsePDF(data []byte) (*pdfDocument, error) {
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")) {
+		return nil, errors.New("not a PDF file")
+	}
+
+	doc := &pdfDocument{objects: map[int]any{}, trailer: pdfDict{}}
+	for pos := 0; pos < len(data); {
+		loc := pdfObjRe.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		num, _ := strconv.Atoi(string(data[pos+loc[2] : pos+loc[3]]))
+		l := &pdfLexer{data: data, pos: pos + loc[1]}
+		obj, err := l.readObject()
+		if err != nil {
+			pos += loc[1]
+			continue
+		}
+		if dict, ok := obj.(pdfDict); ok {
+			if s, end, ok := readStreamBody(data, l.pos, dict); ok {
+				obj = s
+				l.pos = end
+				if t, _ := dict["Type"].(pdfName); t == "XRef" {
+					doc.mergeTrailer(dict)
+				}
+			}
+		}
+		doc.objects[num] = obj
+		pos = l.pos
+	}
+
+	for rest := data; ; {
+		i := bytes.Index(rest, []byte("trailer"))
+		if i < 0 {
+			break
+		}
+		l := &pdfLexer{data: rest, pos: i + len("trailer")}
+		if d, err := l.readObject(); err == nil {
+			if dict, ok := d.(pdfDict); ok {
+				doc.mergeTrailer(dict)
+			}
+		}
+		rest = rest[i+len("trailer"):]
+	}
+
+	if doc.trailer["Encrypt"] != nil {
+		return nil, errPDFEncrypted
+	}
+	if len(doc.objects) == 0 {
+		return nil, errors.New("no objects found; the file may be corrupt")
+	}
+	doc.loadObjectStreams()
+	return doc, nil
+}
+
+func (d *pdfDocument) mergeTrailer(dict pdfDict) {
+	for k, v := range dict {
+		d.trailer[k] = v
+	}
+}
+
+// readStreamBody reads the stream following dict at pos, if any.
+func readStreamBody(data []byte, pos int, dict pdfDict) (*pdfStream, int, bool) {
+	for pos < len(data) && isPDFWhite(data[pos]) {
+		pos++
+	}
+	if !bytes.HasPrefix(data[pos:], []byte("stream")) {
+		return nil, 0, false
+	}
+	pos += len("stream")
+	if pos < len(data) && data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(data) && data[pos] == '\n' {
+		pos++
+	}
+
+	if n, ok := dict["Length"].(float64); ok && n >= 0 && pos+int(n) <= len(data) {
+		end := pos + int(n)
+		rest := bytes.TrimLeft(data[end:], " \t\r\n")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return &pdfStream{dict: dict, data: data[pos:end]}, len(data) - len(rest) + len("endstream"), true
+		}
+	}
+
+	i := bytes.Index(data[pos:], []byte("endstream"))
+	if i < 0 {
+		return nil, 0, false
+	}
+	body := data[pos : pos+i]
+	body = bytes.TrimSuffix(body, []byte("\n"))
+	body = bytes.TrimSuffix(body, []byte("\r"))
+	return &pdfStream{dict: dict, data: body}, pos + i + len("endstream"), true
+}
+
+// loadObjectStreams adds objects stored in compressed object streams.
+func (d *pdfDocument) loadObjectStreams() {
+	found := map[int]any{}
+	for _, obj := range d.objects {
+		s, ok := obj.(*pdfStream)
+		if !ok {
+			continue
+		}
+		if t, _ := s.dict["Type"].(pdfName); t != "ObjStm" {
+			continue
+		}
+		data, err := d.decodeStream(s)
+		if err != nil {
+			continue
+		}
+		n, _ := d.resolve(s.dict["N"]).(float64)
+		first, _ := d.resolve(s.dict["First"]).(float64)
+		header := &pdfLexer{data: data}
+		for i := 0; i < int(n); i++ {
+			numObj, err1 := header.readObject()
+			offObj, err2 := header.readObject()
+			num, ok1 := numObj.(float64)
+			off, ok2 := offObj.(float64)
+			if err1 != nil || err2 != nil || !ok1 || !ok2 {
+				break
+			}
+			l := &pdfLexer{data: data, pos: int(first) + int(off)}
+			if l.pos >= len(data) {
+				continue
+			}
+			if v, err := l.readObject(); err == nil {
+				found[int(num)] = v
+			}
+		}
+	}
+	for num, v := range found {
+		if _, exists := d.objects[num]; !exists {
+			d.objects[num] = v
+		}
+	}
+}
+
+// resolve follows indirect references.
+func (d *pdfDocument) resolve(v any) any {
+	for i := 0; i < 32; i++ {
+		r, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[r.num]
+	}
+	return nil
+}
+
+// dict resolves v to a dictionary, using a stream's dictionary if needed.
+func (d *pdfDocument) dict(v any) pdfDict {
+	switch x := d.resolve(v).(type) {
+	case pdfDict:
+		return x
+	case *pdfStream:
+		return x.dict
+	}
+	return nil
+}
+
+// decodeStream applies the stream's filters.
+func (d *pdfDocument) decodeStream(s *pdfStream) ([]byte, error) {
+	var filters []any
+	switch f := d.resolve(s.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{f}
+	case pdfArray:
+		filters = f
+	}
+
+	data := s.data
+	for _, f := range filters {
+		switch name, _ := d.resolve(f).(pdfName); name {
+		case "FlateDecode", "Fl":
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("flate stream: %w", err)
+			}
+			out, err := io.ReadAll(io.LimitReader(zr, pdfMaxDecodedStream))
+			_ = zr.Close()
+			// Truncated streams are common; keep whatever decoded
+			if err != nil && len(out) == 0 {
+				return nil, fmt.Errorf("flate stream: %w", err)
+			}
+			data = out
+		case "ASCIIHexDecode", "AHx":
+			l := &pdfLexer{data: append(append([]byte{'<'}, bytes.TrimSuffix(bytes.TrimSpace(data), []byte(">"))...), '>')}
+			s, err := l.readHex()
+			if err != nil {
+				return nil, err
+			}
+			data = []byte(s)
+		default:
+			return nil, fmt.Errorf("unsupported stream filter %s", name)
+		}
+	}
+	return data, nil
+}
+
+// pdfPage is a leaf of the page tree with its inherited resources.
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages returns the document's pages in order.
+func (d *pdfDocument) pages() []pdfPage {
+	var pages []pdfPage
+	seen := map[int]bool{}
+	var walk func(v any, res pdfDict, depth int)
+	walk = func(v any, res pdfDict, depth int) {
+		if depth > 64 {
+			return
+		}
+		if r, ok := v.(pdfRef); ok {
+			if seen[r.num] {
+				return
+			}
+			seen[r.num] = true
+		}
+		node := d.dict(v)
+		if node == nil {
+			return
+		}
+		if r := d.dict(node["Resources"]); r != nil {
+			res = r
+		}
+		if kids, ok := d.resolve(node["Kids"]).(pdfArray); ok {
+			for _, k := range kids {
+				walk(k, res, depth+1)
+			}
+			return
+		}
+		pages = append(pages, pdfPage{dict: node, resources: res})
+	}
+	if root := d.dict(d.trailer["Root"]); root != nil {
+		walk(root["Pages"], nil, 0)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	// No usable page tree: fall back to page objects in object order
+	nums := make([]int, 0, len(d.objects))
+	for n := range d.objects {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	for _, n := range nums {
+		if dict, ok := d.objects[n].(pdfDict); ok {
+			if t, _ := dict["Type"].(pdfName); t == "Page" {
+				pages = append(pages, pdfPage{dict: dict, resources: d.dict(dict["Resources"])})
+			}
+		}
+	}
+	return pages
+}
+
+// pageText extracts the text of a page.
+func (d *pdfDocument) pageText(p pdfPage) (string, error) {
+	var content []byte
+	var streams []any
+	switch c := d.resolve(p.dict["Contents"]).(type) {
+	case *pdfStream:
+		streams = []any{c}
+	case pdfArray:
+		streams = c
+	}
+	for _, v := range streams {
+		s, ok := d.resolve(v).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := d.decodeStream(s)
+		if err != nil {
+			return "", err
+		}
+		content = append(content, data...)
+		content = append(content, '\n')
+	}
+
+	fonts := map[string]*pdfFont{}
+	for name, ref := range d.dict(p.resources["Font"]) {
+		if fd := d.dict(ref); fd != nil {
+			fonts[name] = d.loadFont(fd)
+		}
+	}
+
+	var out strings.Builder
+	newline := func() {
+		if s := out.String(); s != "" && !strings.HasSuffix(s, "\n") {
+			out.WriteByte('\n')
+		}
+	}
+	var font *pdfFont
+	var operands []any
+	var lastY float64
+	haveY := false
+
+	l := &pdfLexer{data: content}
+	for {
+		obj, err := l.readObject()
+		if err != nil {
+			break
+		}
+		op, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+		n := len(operands)
+		switch op {
+		case "Tf":
+			if n >= 2 {
+				if name, ok := operands[n-2].(pdfName); ok {
+					font = fonts[string(name)]
+				}
+			}
+		case "Tj":
+			if n >= 1 {
+				if s, ok := operands[n-1].(pdfString); ok {
+					out.WriteString(font.decode(s))
+				}
+			}
+		case "'", "\"":
+			newline()
+			if n >= 1 {
+				if s, ok := operands[n-1].(pdfString); ok {
+					out.WriteString(font.decode(s))
+				}
+			}
+		case "TJ":
+			if n >= 1 {
+				arr, _ := operands[n-1].(pdfArray)
+				for _, el := range arr {
+					switch v := el.(type) {
+					case pdfString:
+						out.WriteString(font.decode(v))
+					case float64:
+						// A large negative adjustment is a word gap
+						if s := out.String(); v < -200 && s != "" && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+							out.WriteByte(' ')
+						}
+					}
+				}
+			}
+		case "T*":
+			newline()
+		case "Td", "TD":
+			if n >= 2 {
+				if ty, ok := operands[n-1].(float64); ok && ty != 0 {
+					newline()
+				}
+			}
+		case "Tm":
+			if n >= 6 {
+				if y, ok := operands[n-1].(float64); ok {
+					if haveY && y != lastY {
+						newline()
+					}
+					lastY, haveY = y, true
+				}
+			}
+		case "ID":
+			l.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// skipInlineImage skips inline image data up to the EI operator.
+func (l *pdfLexer) skipInlineImage() {
+	for i := l.pos; i+2 < len(l.data); i++ {
+		if isPDFWhite(l.data[i]) && l.data[i+1] == 'E' && l.data[i+2] == 'I' &&
+			(i+3 == len(l.data) || isPDFWhite(l.data[i+3])) {
+			l.pos = i + 3
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
+
+// pdfFont maps character codes in shown strings to Unicode text.
+type pdfFont struct {
+	codeLen int               // default code width in bytes
+	cmap    map[string]string // ToUnicode mappings keyed by code bytes
+	lengths []int             // code widths present in cmap, longest first
+}
+
+func (d *pdfDocument) loadFont(fd pdfDict) *pdfFont {
+	f := &pdfFont{codeLen: 1}
+	if st, _ := fd["Subtype"].(pdfName); st == "Type0" {
+		f.codeLen = 2
+	}
+	if s, ok := d.resolve(fd["ToUnicode"]).(*pdfStream); ok {
+		if data, err := d.decodeStream(s); err == nil {
+			f.parseCMap(data)
+		}
+	}
+	return f
+}
+
+// parseCMap reads bfchar and bfrange mappings from a ToUnicode CMap.
+func (f *pdfFont) parseCMap(data []byte) {
+	f.cmap = map[string]string{}
+	widths := map[int]bool{}
+	var operands []any
+
+	l := &pdfLexer{data: data}
+	for {
+		obj, err := l.readObject()
+		if err != nil {
+			break
+		}
+		op, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+		switch op {
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 && src != "" {
+					f.cmap[string(src)] = decodeUTF16BE(string(dst))
+					widths[len(src)] = true
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || lo == "" || len(lo) != len(hi) {
+					continue
+				}
+				widths[len(lo)] = true
+				start, end := codeValue(lo), codeValue(hi)
+				for c := start; c <= end && c-start < 1<<16; c++ {
+					code := codeBytes(c, len(lo))
+					switch dst := operands[i+2].(type) {
+					case pdfString:
+						f.cmap[code] = decodeUTF16BE(addToBytes(string(dst), c-start))
+					case pdfArray:
+						if k := c - start; k < len(dst) {
+							if s, ok := dst[k].(pdfString); ok {
+								f.cmap[code] = decodeUTF16BE(string(s))
+							}
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+
+	for w := range widths {
+		f.lengths = append(f.lengths, w)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(f.lengths)))
+}
+
+// decode converts the codes in s to text. A nil font decodes as WinAnsi.
+func (f *pdfFont) decode(s pdfString) string {
+	if f == nil || (f.cmap == nil && f.codeLen == 1) {
+		return winAnsiString(string(s))
+	}
+	if f.cmap == nil {
+		// Multi-byte codes without a ToUnicode map cannot be recovered
+		return ""
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		matched := false
+		for _, n := range f.lengths {
+			if i+n <= len(s) {
+				if u, ok := f.cmap[string(s[i:i+n])]; ok {
+					b.WriteString(u)
+					i += n
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			if f.codeLen == 1 {
+				b.WriteString(winAnsiString(string(s[i])))
+			}
+			i += f.codeLen
+		}
+	}
+	return b.String()
+}
+
+func codeValue(s pdfString) int {
+	v := 0
+	for i := 0; i < len(s); i++ {
+		v = v<<8 | int(s[i])
+	}
+	return v
+}
+
+func codeBytes(v, n int) string {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return string(b)
+}
+
+// addToBytes adds n to s interpreted as a big-endian number.
+func addToBytes(s string, n int) string {
+	b := []byte(s)
+	for i := len(b) - 1; i >= 0 && n > 0; i-- {
+		sum := int(b[i]) + n
+		b[i] = byte(sum)
+		n = sum >> 8
+	}
+	return string(b)
+}
+
+func decodeUTF16BE(s string) string {
+	units := make([]uint16, 0, len(s)/2)
+	for i := 0; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// winAnsiHigh maps the Windows-1252 bytes 0x80-0x9F that differ from Latin-1.
+var winAnsiHigh = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+func winAnsiString(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if r, ok := winAnsiHigh[c]; ok {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
@@ -1,21 +1,42 @@
 package builtins
 
-import "github.com/initializ/forge/forge-core/tools"
+import (
+	"errors"
+	"fmt"
+	"os"
 
-// All returns all built-in tools.
+	"github.com/initializ/forge/forge-core/tools"
+)
+
+// requiredEnv names, for the built-in tools an agent only gets when it is
+// configured for them, the env var that must be set.
+var requiredEnv = map[string]string{
+	"file_read":  workspaceEnv,
+	"file_write": workspaceEnv,
+	"sql_query":  sqlDSNEnv,
+}
+
+// All returns all built-in tools, including those RegisterAllWithConfig
+// skips because their required env var is unset.
 func All() []tools.Tool {
 	return []tools.Tool{
 		&httpRequestTool{},
 		&jsonParseTool{},
+		&jsonTransformTool{},
 		&csvParseTool{},
 		&datetimeNowTool{},
 		&uuidGenerateTool{},
 		&mathCalculateTool{},
 		&webSearchTool{},
+		&pdfExtractTool{},
+		&fileReadTool{},
+		&fileWriteTool{},
+		&sqlQueryTool{},
 	}
 }
 
-// RegisterAll registers all built-in tools with the given registry.
+// RegisterAll registers all built-in tools with the given registry, for
+// listing them. Agents use RegisterAllWithConfig.
 func RegisterAll(reg *tools.Registry) error {
 	for _, t := range All() {
 		if err := reg.Register(t); err != nil {
@@ -25,6 +46,58 @@ func RegisterAll(reg *tools.Registry) error {
 	return nil
 }
 
+// RegisterAllWithConfig registers the built-in tools available to an agent,
+// building those that take settings from configs, keyed by tool name as in
+// the tools section of forge.yaml. Tools whose required env var is unset
+// (see Unavailable) are skipped. A tool whose config is invalid is
+// registered with its defaults, and the config errors are returned after
+// all tools are registered.
+func RegisterAllWithConfig(reg *tools.Registry, configs map[string]map[string]any) error {
+	var configErrs []error
+	for _, t := range All() {
+		if env, ok := requiredEnv[t.Name()]; ok && os.Getenv(env) == "" {
+			continue
+		}
+		if raw, ok := configs[t.Name()]; ok {
+			configured, err := configure(t, raw)
+			if err != nil {
+				configErrs = append(configErrs, fmt.Errorf("configuring %s: %w", t.Name(), err))
+			}
+			t = configured
+		}
+		if err := reg.Register(t); err != nil {
+			return err
+		}
+	}
+	return errors.Join(configErrs...)
+}
+
+// Unavailable returns, for each built-in tool RegisterAllWithConfig skips,
+// the env var that enables it.
+func Unavailable() map[string]string {
+	skipped := make(map[string]string)
+	for name, env := range requiredEnv {
+		if os.Getenv(env) == "" {
+			skipped[name] = env
+		}
+	}
+	return skipped
+}
+
+// configure returns t built with the settings in raw, or t itself when it
+// takes none.
+func configure(t tools.Tool, raw map[string]any) (tools.Tool, error) {
+	switch t.Name() {
+	case "http_request":
+		cfg, err := ParseHTTPRequestConfig(raw)
+		if err != nil {
+			return t, err
+		}
+		return NewHTTPRequestTool(cfg), nil
+	}
+	return t, nil
+}
+
 // GetByName returns a built-in tool by name, or nil if not found.
 func GetByName(name string) tools.Tool {
 	for _, t := range All() {
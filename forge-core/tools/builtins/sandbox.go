@@ -0,0 +1,82 @@
+package builtins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// workspaceRoot returns the resolved directory the file tools are confined
+// to, $FORGE_WORKSPACE. It never defaults to the working directory, which
+// holds the agent's .env with its credentials.
+func workspaceRoot() (string, error) {
+	root := os.Getenv(workspaceEnv)
+	if root == "" {
+		return "", fmt.Errorf("local files require %s to be set", workspaceEnv)
+	}
+	resolved, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("resolving workspace: %w", err)
+	}
+	return resolved, nil
+}
+
+// workspacePath joins a relative path to root, rejecting absolute paths and
+// paths that climb out of it with "..".
+func workspacePath(root, path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("path must be relative to the workspace")
+	}
+	if !filepath.IsLocal(path) {
+		return "", fmt.Errorf("path %s is outside the workspace", path)
+	}
+	return filepath.Join(root, path), nil
+}
+
+// withinRoot reports whether the resolved path is root or below it.
+func withinRoot(root, resolved string) bool {
+	rel, err := filepath.Rel(root, resolved)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolveInWorkspace resolves a relative path, following symlinks, and
+// rejects it unless the result is inside root.
+func resolveInWorkspace(root, path string) (string, error) {
+	joined, err := workspacePath(root, path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(joined)
+	if err != nil {
+		return "", fmt.Errorf("opening %s: %w", path, err)
+	}
+	if !withinRoot(root, resolved) {
+		return "", fmt.Errorf("path %s is outside the workspace", path)
+	}
+	return resolved, nil
+}
+
+// readSandboxedFile reads a file that must resolve inside the workspace,
+// refusing files larger than maxSize.
+func readSandboxedFile(path string, maxSize int64) ([]byte, error) {
+	root, err := workspaceRoot()
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveInWorkspace(root, path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("file is %d bytes, exceeds limit of %d", info.Size(), maxSize)
+	}
+	return os.ReadFile(resolved)
+}
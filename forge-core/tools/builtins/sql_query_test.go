@@ -0,0 +1,169 @@
+package builtins
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/tools"
+	_ "modernc.org/sqlite"
+)
+
+// openTestDB creates a named in-memory sqlite database that stays alive
+// for the test and points FORGE_SQL_DSN at it.
+func openTestDB(t *testing.T) {
+	t.Helper()
+	source := "file:" + strings.ReplaceAll(t.Name(), "/", "_") + "?mode=memory&cache=shared"
+	db, err := sql.Open("sqlite", source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	for _, stmt := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)`,
+		`INSERT INTO users (name, email) VALUES ('alice', 'alice@example.com'), ('bob', 'bob@example.com'), ('carol', NULL)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(sqlDSNEnv, "sqlite://"+source)
+}
+
+func runSQLQuery(t *testing.T, input map[string]any) (sqlQueryResult, error) {
+	t.Helper()
+	args, _ := json.Marshal(input)
+	out, err := GetByName("sql_query").Execute(context.Background(), args)
+	var result sqlQueryResult
+	if err == nil {
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("decoding %q: %v", out, err)
+		}
+	}
+	return result, err
+}
+
+func TestSQLQuery_Select(t *testing.T) {
+	openTestDB(t)
+
+	result, err := runSQLQuery(t, map[string]any{"query": "SELECT id, name, email FROM users ORDER BY id;"})
+	if err != nil {
+		t.Fatalf("sql_query: %v", err)
+	}
+	if strings.Join(result.Columns, ",") != "id,name,email" {
+		t.Errorf("columns = %v", result.Columns)
+	}
+	if result.RowCount != 3 || result.Truncated {
+		t.Fatalf("row_count = %d, truncated = %v", result.RowCount, result.Truncated)
+	}
+	if r := result.Rows[0]; r["id"] != float64(1) || r["name"] != "alice" || r["email"] != "alice@example.com" {
+		t.Errorf("first row = %v", r)
+	}
+	if r := result.Rows[2]; r["email"] != nil {
+		t.Errorf("NULL email = %v", r["email"])
+	}
+
+	result, err = runSQLQuery(t, map[string]any{"query": "SELECT name FROM users WHERE name = ?", "params": []any{"bob"}})
+	if err != nil || result.RowCount != 1 || result.Rows[0]["name"] != "bob" {
+		t.Errorf("parameterized query = %+v, %v", result, err)
+	}
+
+	result, err = runSQLQuery(t, map[string]any{"query": "WITH n AS (SELECT name FROM users) SELECT * FROM n", "limit": 2})
+	if err != nil || result.RowCount != 2 || !result.Truncated {
+		t.Errorf("limited query = %+v, %v", result, err)
+	}
+}
+
+func TestSQLQuery_RejectsWrites(t *testing.T) {
+	openTestDB(t)
+
+	tests := []struct {
+		query   string
+		wantErr string
+	}{
+		{"DELETE FROM users", "DELETE statements are not allowed"},
+		{"  insert into users (name) values ('eve')", "INSERT statements are not allowed"},
+		{"UPDATE users SET name = 'x'", "UPDATE statements are not allowed"},
+		{"DROP TABLE users", "DROP statements are not allowed"},
+		{"/* hi */ CREATE TABLE t (x int)", "CREATE statements are not allowed"},
+		{"SELECT 1; DELETE FROM users", "single statement"},
+		{"WITH gone AS (SELECT id FROM users) DELETE FROM users WHERE id IN (SELECT id FROM gone)", "contains DELETE"},
+		{"SELECT * INTO backup FROM users", "contains INTO"},
+		{"SELECT * FROM users WHERE id IN (DELETE FROM users RETURNING id)", "contains DELETE"},
+		{"SELECT name FROM users INTO OUTFILE '/tmp/users'", "contains INTO"},
+		{"SELECT 1 /*! INTO OUTFILE '/tmp/x' */", "executable comments"},
+		{"SELECT /*+ MAX_EXECUTION_TIME(1) */ name FROM users", "executable comments"},
+		{"", "query is required"},
+	}
+	for _, tt := range tests {
+		_, err := runSQLQuery(t, map[string]any{"query": tt.query})
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("query %q: error = %v, want %q", tt.query, err, tt.wantErr)
+		}
+	}
+
+	result, err := runSQLQuery(t, map[string]any{"query": "SELECT COUNT(*) AS n FROM users"})
+	if err != nil || result.Rows[0]["n"] != float64(3) {
+		t.Errorf("users were modified: %+v, %v", result, err)
+	}
+}
+
+func TestCheckReadOnlySQL_AllowsKeywordNames(t *testing.T) {
+	for _, query := range []string{
+		`SELECT "into", "update" FROM events`,
+		"SELECT e.into, e.delete FROM events e",
+		"SELECT into, created FROM events",
+		"SELECT id AS into FROM events",
+		"SELECT id FROM events WHERE note = 'drop; insert into x'",
+		"SELECT id -- update later\nFROM events",
+	} {
+		if _, err := checkReadOnlySQL(query); err != nil {
+			t.Errorf("checkReadOnlySQL(%q): %v", query, err)
+		}
+	}
+}
+
+func TestSQLQuery_RequiresDSN(t *testing.T) {
+	t.Setenv(sqlDSNEnv, "")
+	reg := tools.NewRegistry()
+	if err := RegisterAllWithConfig(reg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if reg.Get("sql_query") != nil {
+		t.Error("sql_query registered without FORGE_SQL_DSN")
+	}
+	if Unavailable()["sql_query"] != sqlDSNEnv {
+		t.Errorf("Unavailable() = %v", Unavailable())
+	}
+
+	t.Setenv(sqlDSNEnv, "sqlite://:memory:")
+	reg = tools.NewRegistry()
+	if err := RegisterAllWithConfig(reg, nil); err != nil {
+		t.Fatal(err)
+	}
+	if reg.Get("sql_query") == nil {
+		t.Error("sql_query not registered with FORGE_SQL_DSN set")
+	}
+}
+
+func TestSQLDataSource(t *testing.T) {
+	tests := []struct {
+		dsn, driver, source string
+	}{
+		{"postgres://u:p@db:5432/app?sslmode=disable", "pgx", "postgres://u:p@db:5432/app?sslmode=disable"},
+		{"mysql://u:p@db:3306/app?parseTime=true", "mysql", "u:p@tcp(db:3306)/app?parseTime=true"},
+		{"mysql://db", "mysql", "tcp(db)/"},
+		{"sqlite:///var/data/app.db", "sqlite", "/var/data/app.db"},
+	}
+	for _, tt := range tests {
+		driver, source, err := sqlDataSource(tt.dsn)
+		if err != nil || driver != tt.driver || source != tt.source {
+			t.Errorf("sqlDataSource(%q) = %q, %q, %v; want %q, %q", tt.dsn, driver, source, err, tt.driver, tt.source)
+		}
+	}
+	if _, _, err := sqlDataSource("oracle://db"); err == nil {
+		t.Error("expected an error for an unknown scheme")
+	}
+}
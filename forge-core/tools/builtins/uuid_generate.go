@@ -14,6 +14,7 @@ type uuidGenerateTool struct{}
 func (t *uuidGenerateTool) Name() string             { return "uuid_generate" }
 func (t *uuidGenerateTool) Description() string      { return "Generate a random UUID v4" }
 func (t *uuidGenerateTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *uuidGenerateTool) Mutation() tools.Mutation { return tools.MutationReadOnly }
 
 func (t *uuidGenerateTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{"type": "object", "properties": {}}`)
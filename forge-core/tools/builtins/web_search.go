@@ -11,9 +11,25 @@ import (
 
 type webSearchTool struct{}
 
-func (t *webSearchTool) Name() string             { return "web_search" }
-func (t *webSearchTool) Description() string      { return "Search the web using Tavily or Perplexity AI" }
-func (t *webSearchTool) Category() tools.Category { return tools.CategoryBuiltin }
+func (t *webSearchTool) Name() string { return "web_search" }
+func (t *webSearchTool) Description() string {
+	return "Search the web using Tavily, Perplexity AI, or DuckDuckGo"
+}
+func (t *webSearchTool) Category() tools.Category       { return tools.CategoryBuiltin }
+func (t *webSearchTool) Mutation() tools.Mutation       { return tools.MutationReadOnly }
+func (t *webSearchTool) Cacheable(json.RawMessage) bool { return true }
+
+// CacheableResult rejects {"error": ...} results, such as a provider's rate
+// limit, so the search runs again next time.
+func (t *webSearchTool) CacheableResult(result string) bool {
+	var out struct {
+		Error any `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(result), &out); err != nil {
+		return true
+	}
+	return out.Error == nil
+}
 
 func (t *webSearchTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -22,7 +38,7 @@ func (t *webSearchTool) InputSchema() json.RawMessage {
 			"query": {"type": "string", "description": "Search query"},
 			"max_results": {"type": "integer", "description": "Maximum number of results (default 5)"},
 			"search_depth": {"type": "string", "description": "Search depth: basic or advanced (Tavily only)", "enum": ["basic", "advanced"]},
-			"time_range": {"type": "string", "description": "Time range filter: day, week, month, year (Tavily only)"},
+			"time_range": {"type": "string", "description": "Time range filter: day, week, month, year (Tavily and DuckDuckGo)"},
 			"include_domains": {"type": "array", "items": {"type": "string"}, "description": "Only include results from these domains (Tavily only)"},
 			"exclude_domains": {"type": "array", "items": {"type": "string"}, "description": "Exclude results from these domains (Tavily only)"}
 		},
@@ -65,7 +81,8 @@ func (t *webSearchTool) Execute(ctx context.Context, args json.RawMessage) (stri
 }
 
 // resolveWebSearchProvider selects the web search provider based on environment.
-// Priority: WEB_SEARCH_PROVIDER env > auto-detect (Tavily first, then Perplexity).
+// Priority: WEB_SEARCH_PROVIDER env > auto-detect (Tavily first, then
+// Perplexity, then the keyless DuckDuckGo).
 func resolveWebSearchProvider() (webSearchProvider, error) {
 	override := os.Getenv("WEB_SEARCH_PROVIDER")
 
@@ -84,17 +101,20 @@ func resolveWebSearchProvider() (webSearchProvider, error) {
 		}
 		return newPerplexityProvider(key), nil
 
+	case "duckduckgo":
+		return newDuckDuckGoProvider(), nil
+
 	case "":
-		// Auto-detect: try Tavily first, then Perplexity
+		// Auto-detect: try Tavily first, then Perplexity, then DuckDuckGo
 		if key := os.Getenv("TAVILY_API_KEY"); key != "" {
 			return newTavilyProvider(key), nil
 		}
 		if key := os.Getenv("PERPLEXITY_API_KEY"); key != "" {
 			return newPerplexityProvider(key), nil
 		}
-		return nil, fmt.Errorf("no web search API key set. Set TAVILY_API_KEY or PERPLEXITY_API_KEY in your .env file to enable web search")
+		return newDuckDuckGoProvider(), nil
 
 	default:
-		return nil, fmt.Errorf("unknown WEB_SEARCH_PROVIDER %q: must be tavily or perplexity", override)
+		return nil, fmt.Errorf("unknown WEB_SEARCH_PROVIDER %q: must be tavily, perplexity, or duckduckgo", override)
 	}
 }
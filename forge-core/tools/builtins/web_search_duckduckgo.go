@@ -0,0 +1,138 @@
+package builtins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/security"
+)
+
+// duckduckgoProvider implements webSearchProvider by scraping the DuckDuckGo
+// HTML endpoint. It needs no API key, so web_search falls back to it.
+type duckduckgoProvider struct {
+	baseURL string // defaults to "https://html.duckduckgo.com"
+}
+
+func newDuckDuckGoProvider() *duckduckgoProvider {
+	return &duckduckgoProvider{baseURL: "https://html.duckduckgo.com"}
+}
+
+func (p *duckduckgoProvider) name() string { return "duckduckgo" }
+
+func (p *duckduckgoProvider) egressDomains() []string {
+	return []string{"html.duckduckgo.com"}
+}
+
+// ddgTimeRanges maps the time_range option to DuckDuckGo's df parameter.
+var ddgTimeRanges = map[string]string{"day": "d", "week": "w", "month": "m", "year": "y"}
+
+func (p *duckduckgoProvider) search(ctx context.Context, query string, opts webSearchOpts) (string, error) {
+	form := url.Values{"q": {query}}
+	if df, ok := ddgTimeRanges[opts.TimeRange]; ok {
+		form.Set("df", df)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/html/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("creating DuckDuckGo request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("User-Agent", "Mozilla/5.0 (compatible; forge-agent)")
+
+	resp, err := security.EgressClient(ctx, 0).Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("calling DuckDuckGo: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading DuckDuckGo response: %w", err)
+	}
+
+	// DuckDuckGo answers 202 with a challenge page when it rate limits
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Sprintf(`{"error": "DuckDuckGo returned status %d; try again later or set TAVILY_API_KEY or PERPLEXITY_API_KEY"}`, resp.StatusCode), nil
+	}
+
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = 5
+	}
+	results := parseDuckDuckGoHTML(string(body))
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+
+	out, _ := json.Marshal(map[string]any{
+		"query":   query,
+		"results": results,
+	})
+	return string(out), nil
+}
+
+var (
+	ddgAnchor = regexp.MustCompile(`(?s)<a\s([^>]*)>(.*?)</a>`)
+	ddgAttr   = regexp.MustCompile(`(class|href)="([^"]*)"`)
+	htmlTag   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// parseDuckDuckGoHTML extracts organic results from a DuckDuckGo HTML
+// results page. Each result is a "result__a" link, optionally followed by a
+// "result__snippet" link; ads link through duckduckgo.com and are skipped.
+func parseDuckDuckGoHTML(page string) []map[string]any {
+	results := []map[string]any{}
+	var current map[string]any
+	for _, m := range ddgAnchor.FindAllStringSubmatch(page, -1) {
+		var class, href string
+		for _, a := range ddgAttr.FindAllStringSubmatch(m[1], -1) {
+			if a[1] == "class" {
+				class = a[2]
+			} else {
+				href = html.UnescapeString(a[2])
+			}
+		}
+		text := strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(m[2], ""))), " ")
+
+		switch {
+		case strings.Contains(class, "result__a"):
+			current = nil
+			target := ddgTarget(href)
+			if target == "" || text == "" {
+				continue
+			}
+			current = map[string]any{"title": text, "url": target}
+			results = append(results, current)
+		case strings.Contains(class, "result__snippet") && current != nil:
+			current["content"] = text
+		}
+	}
+	return results
+}
+
+// ddgTarget returns the destination of a result link, unwrapping
+// DuckDuckGo's redirect (//duckduckgo.com/l/?uddg=...). Links that stay on
+// duckduckgo.com, such as ads, yield "".
+func ddgTarget(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if strings.HasSuffix(u.Hostname(), "duckduckgo.com") {
+		if u.Path != "/l/" {
+			return ""
+		}
+		return u.Query().Get("uddg")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return href
+}
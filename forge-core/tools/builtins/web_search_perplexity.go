@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/initializ/forge/forge-core/security"
 )
 
 // perplexityProvider implements webSearchProvider using the Perplexity API.
@@ -46,7 +48,7 @@ func (p *perplexityProvider) search(ctx context.Context, query string, opts webS
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
 
-	resp, err := http.DefaultClient.Do(httpReq)
+	resp, err := security.EgressClient(ctx, 0).Do(httpReq)
 	if err != nil {
 		return "", fmt.Errorf("calling Perplexity API: %w", err)
 	}
@@ -2,7 +2,8 @@ package builtins
 
 import "context"
 
-// webSearchProvider abstracts a web search backend (Tavily, Perplexity, etc.).
+// webSearchProvider abstracts a web search backend (Tavily, Perplexity,
+// DuckDuckGo).
 type webSearchProvider interface {
 	name() string
 	search(ctx context.Context, query string, opts webSearchOpts) (string, error)
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheConfig configures the registry's result cache.
+type CacheConfig struct {
+	TTL        time.Duration // how long a result is reused; default 5m
+	MaxEntries int           // least recently used results are evicted beyond this; default 256
+}
+
+// resultCache is an LRU cache of tool results with a fixed TTL.
+type resultCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	max     int
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently used
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	key     string
+	result  string
+	expires time.Time
+}
+
+func newResultCache(cfg CacheConfig) *resultCache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 5 * time.Minute
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 256
+	}
+	return &resultCache{
+		ttl:     cfg.TTL,
+		max:     cfg.MaxEntries,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+func (c *resultCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	e := el.Value.(*cacheEntry)
+	if !c.now().Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return "", false
+	}
+	c.order.MoveToFront(el)
+	return e.result, true
+}
+
+func (c *resultCache) put(key, result string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*cacheEntry)
+		e.result, e.expires = result, expires
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, result: result, expires: expires})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// cacheKey identifies a call by tool name and a hash of its arguments.
+// Arguments that differ only in key order or spacing share a key.
+func cacheKey(name string, arguments json.RawMessage) string {
+	canonical := []byte(strings.TrimSpace(string(arguments)))
+	var v any
+	dec := json.NewDecoder(strings.NewReader(string(canonical)))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err == nil {
+		if b, err := json.Marshal(v); err == nil {
+			canonical = b
+		}
+	}
+	sum := sha256.Sum256(canonical)
+	return name + ":" + hex.EncodeToString(sum[:])
+}
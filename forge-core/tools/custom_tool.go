@@ -32,6 +32,7 @@ func (t *CustomTool) Description() string {
 	return fmt.Sprintf("Custom %s tool: %s", t.language, t.name)
 }
 func (t *CustomTool) Category() Category { return CategoryCustom }
+func (t *CustomTool) Mutation() Mutation { return MutationMutating }
 
 func (t *CustomTool) InputSchema() json.RawMessage {
 	return json.RawMessage(`{"type": "object", "properties": {}, "additionalProperties": true}`)
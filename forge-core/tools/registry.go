@@ -5,16 +5,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 
+	"github.com/xeipuuv/gojsonschema"
+
 	"github.com/initializ/forge/forge-core/llm"
+	"github.com/initializ/forge/forge-core/security"
 )
 
 // Registry is a thread-safe tool registry. It implements engine.ToolExecutor
 // via Go structural typing -- no direct import of the engine package is needed.
 type Registry struct {
-	mu    sync.RWMutex
-	tools map[string]Tool
+	mu             sync.RWMutex
+	tools          map[string]Tool
+	validateInputs bool
+	schemas        map[string]*gojsonschema.Schema // compiled on first use
+	cache          *resultCache                    // nil unless EnableCache was called
+	egress         *security.EgressEnforcer        // nil allows all hosts
 }
 
 // NewRegistry creates an empty tool registry.
@@ -60,14 +68,173 @@ func (r *Registry) List() []string {
 // Execute runs the named tool with the given arguments.
 // This method satisfies the engine.ToolExecutor interface.
 func (r *Registry) Execute(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+	result, _, err := r.ExecuteCached(ctx, name, arguments)
+	return result, err
+}
+
+// ExecuteCached is Execute that also reports whether the result came from
+// the result cache rather than running the tool.
+func (r *Registry) ExecuteCached(ctx context.Context, name string, arguments json.RawMessage) (result string, hit bool, err error) {
 	r.mu.RLock()
 	t, ok := r.tools[name]
+	cache := r.cache
+	egress := r.egress
 	r.mu.RUnlock()
 
 	if !ok {
-		return "", fmt.Errorf("unknown tool: %q", name)
+		return "", false, fmt.Errorf("unknown tool: %q", name)
+	}
+	if err := r.checkInput(t, arguments); err != nil {
+		return "", false, err
+	}
+
+	if egress != nil {
+		ctx = security.WithEgressEnforcer(ctx, egress)
+	}
+
+	c, cacheable := t.(Cacheable)
+	if cache == nil || !cacheable || !c.Cacheable(arguments) {
+		result, err = t.Execute(ctx, arguments)
+		return result, false, err
+	}
+	key := cacheKey(name, arguments)
+	if result, ok := cache.get(key); ok {
+		return result, true, nil
+	}
+	result, err = t.Execute(ctx, arguments)
+	if rc, ok := t.(ResultCacheable); err == nil && (!ok || rc.CacheableResult(result)) {
+		cache.put(key, result)
+	}
+	return result, false, err
+}
+
+// SetEgress makes network tools check each outbound host against e before
+// sending a request. Tools find the enforcer with
+// security.EgressEnforcerFrom.
+func (r *Registry) SetEgress(e *security.EgressEnforcer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.egress = e
+}
+
+// EnableCache turns on a result cache for tools that implement Cacheable.
+// Identical calls within cfg.TTL return the earlier result without running
+// the tool again; errors, and results a ResultCacheable tool rejects, are
+// never cached.
+func (r *Registry) EnableCache(cfg CacheConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = newResultCache(cfg)
+}
+
+// derive returns an empty registry with r's settings. The caller holds r.mu.
+func (r *Registry) derive() *Registry {
+	d := NewRegistry()
+	d.validateInputs = r.validateInputs
+	d.cache = r.cache
+	d.egress = r.egress
+	return d
+}
+
+// ValidateInputs sets whether Execute checks arguments against the tool's
+// InputSchema before running it. A call that does not match returns an
+// *InputValidationError, which the agent loop hands back to the model as the
+// tool result so it can correct the call.
+func (r *Registry) ValidateInputs(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validateInputs = enabled
+}
+
+// FieldError is one problem with a tool call's arguments. Field is a dotted
+// path, empty for the arguments as a whole.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// InputValidationError reports tool arguments that do not match the tool's
+// InputSchema.
+type InputValidationError struct {
+	Tool   string
+	Fields []FieldError
+}
+
+func (e *InputValidationError) Error() string {
+	problems := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		if f.Field == "" {
+			problems = append(problems, f.Message)
+		} else {
+			problems = append(problems, f.Field+": "+f.Message)
+		}
+	}
+	return fmt.Sprintf("invalid arguments for %s: %s. Fix the arguments to match the tool's input schema and call it again",
+		e.Tool, strings.Join(problems, "; "))
+}
+
+// checkInput validates arguments against t's InputSchema when validation is
+// enabled. A schema that does not compile is not enforced, since the model
+// cannot fix it.
+func (r *Registry) checkInput(t Tool, arguments json.RawMessage) error {
+	r.mu.Lock()
+	if !r.validateInputs {
+		r.mu.Unlock()
+		return nil
+	}
+	schema, ok := r.schemas[t.Name()]
+	if !ok {
+		schema, _ = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(t.InputSchema()))
+		if r.schemas == nil {
+			r.schemas = make(map[string]*gojsonschema.Schema)
+		}
+		r.schemas[t.Name()] = schema
+	}
+	r.mu.Unlock()
+	if schema == nil {
+		return nil
+	}
+
+	if len(strings.TrimSpace(string(arguments))) == 0 {
+		arguments = json.RawMessage(`{}`)
 	}
-	return t.Execute(ctx, arguments)
+	if !json.Valid(arguments) {
+		return &InputValidationError{Tool: t.Name(), Fields: []FieldError{{Message: "arguments are not valid JSON"}}}
+	}
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(arguments))
+	if err != nil || result.Valid() {
+		return nil
+	}
+
+	verr := &InputValidationError{Tool: t.Name()}
+	for _, e := range result.Errors() {
+		field := e.Field()
+		if field == gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+			field = ""
+		}
+		msg := e.Description()
+		// Name the missing property rather than its parent
+		if prop, ok := e.Details()["property"].(string); ok && e.Type() == "required" {
+			field = strings.TrimPrefix(field+"."+prop, ".")
+			msg = "is required"
+		}
+		verr.Fields = append(verr.Fields, FieldError{Field: field, Message: msg})
+	}
+	return verr
+}
+
+// RedactInput returns the input of a call to the named tool as it may be
+// shown in logs: redacted by the tool when it is an InputRedactor, else
+// unchanged.
+func (r *Registry) RedactInput(name, arguments string) string {
+	r.mu.RLock()
+	t := r.tools[name]
+	r.mu.RUnlock()
+
+	if ir, ok := t.(InputRedactor); ok {
+		return string(ir.RedactInput(json.RawMessage(arguments)))
+	}
+	return arguments
 }
 
 // Filter returns a new Registry containing only tools whose names are in the allowed list.
@@ -78,10 +245,10 @@ func (r *Registry) Filter(allowed []string) *Registry {
 		allowSet[name] = true
 	}
 
-	filtered := NewRegistry()
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	filtered := r.derive()
 	for name, tool := range r.tools {
 		if allowSet[name] {
 			filtered.tools[name] = tool
@@ -90,6 +257,61 @@ func (r *Registry) Filter(allowed []string) *Registry {
 	return filtered
 }
 
+// ReadOnly returns a new Registry for safe mode. It keeps read-only tools,
+// swaps mutating tools for their ReadOnlyVariant when they have one, and
+// drops the rest unless they are named in allowed.
+func (r *Registry) ReadOnly(allowed []string) *Registry {
+	allowSet := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		allowSet[name] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	filtered := r.derive()
+	for name, tool := range r.tools {
+		switch {
+		case allowSet[name] || MutationOf(tool) == MutationReadOnly:
+			filtered.tools[name] = tool
+		default:
+			if v, ok := tool.(ReadOnlyVariant); ok {
+				filtered.tools[name] = v.ReadOnly()
+			}
+		}
+	}
+	return filtered
+}
+
+// Guidance returns usage guidance for the registered tools as one
+// "- name: guidance" line per tool, sorted by name. An entry in overrides
+// replaces a tool's own GuidanceProvider text; overrides for tools that are
+// not registered are ignored.
+func (r *Registry) Guidance(overrides map[string]string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		text, ok := overrides[name]
+		if !ok {
+			if gp, isGP := r.tools[name].(GuidanceProvider); isGP {
+				text = gp.Guidance()
+			}
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			fmt.Fprintf(&b, "- %s: %s\n", name, text)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
 // ToolDefinitions returns LLM tool definitions for all registered tools.
 // This method satisfies the engine.ToolExecutor interface.
 func (r *Registry) ToolDefinitions() []llm.ToolDefinition {
@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type searchTool struct{ calls int }
+
+func (t *searchTool) Name() string        { return "search" }
+func (t *searchTool) Description() string { return "Search" }
+func (t *searchTool) Category() Category  { return CategoryBuiltin }
+func (t *searchTool) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"query": {"type": "string"},
+			"limit": {"type": "integer"}
+		},
+		"required": ["query"]
+	}`)
+}
+func (t *searchTool) Execute(_ context.Context, _ json.RawMessage) (string, error) {
+	t.calls++
+	return "ok", nil
+}
+
+func TestRegistry_ValidateInputs(t *testing.T) {
+	tool := &searchTool{}
+	reg := NewRegistry()
+	reg.Register(tool) //nolint:errcheck
+	ctx := context.Background()
+
+	// Off by default: the tool sees whatever the model sent
+	if _, err := reg.Execute(ctx, "search", json.RawMessage(`{}`)); err != nil || tool.calls != 1 {
+		t.Fatalf("unvalidated Execute = %v after %d calls", err, tool.calls)
+	}
+
+	reg.ValidateInputs(true)
+	tests := []struct {
+		args   string
+		fields []string
+	}{
+		{`{}`, []string{"query"}},
+		{``, []string{"query"}},
+		{`{"query": 42, "limit": "ten"}`, []string{"query", "limit"}},
+		{`{"query": `, []string{""}},
+	}
+	for _, tt := range tests {
+		_, err := reg.Execute(ctx, "search", json.RawMessage(tt.args))
+		var verr *InputValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("args %q: error = %v, want an InputValidationError", tt.args, err)
+			continue
+		}
+		var got []string
+		for _, f := range verr.Fields {
+			got = append(got, f.Field)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+			t.Errorf("args %q: fields = %v, want %v", tt.args, verr.Fields, tt.fields)
+		}
+	}
+	if tool.calls != 1 {
+		t.Errorf("tool ran %d times, want invalid calls skipped", tool.calls)
+	}
+
+	_, err := reg.Execute(ctx, "search", json.RawMessage(`{}`))
+	if msg := err.Error(); !strings.Contains(msg, "invalid arguments for search: query: is required") {
+		t.Errorf("error = %q", msg)
+	}
+
+	if out, err := reg.Execute(ctx, "search", json.RawMessage(`{"query": "forge", "limit": 3}`)); err != nil || out != "ok" {
+		t.Errorf("valid Execute = %q, %v", out, err)
+	}
+
+	// Filtered registries keep the setting
+	if _, err := reg.Filter([]string{"search"}).Execute(ctx, "search", json.RawMessage(`{}`)); err == nil {
+		t.Error("filtered registry did not validate")
+	}
+}
+
+// echoTool counts executions; only calls with "cache": true are cacheable.
+type echoTool struct{ calls atomic.Int32 }
+
+func (t *echoTool) Name() string                 { return "echo" }
+func (t *echoTool) Description() string          { return "Echo" }
+func (t *echoTool) Category() Category           { return CategoryBuiltin }
+func (t *echoTool) InputSchema() json.RawMessage { return json.RawMessage(`{"type": "object"}`) }
+func (t *echoTool) Cacheable(args json.RawMessage) bool {
+	var in struct {
+		Cache bool `json:"cache"`
+	}
+	return json.Unmarshal(args, &in) == nil && in.Cache
+}
+func (t *echoTool) Execute(_ context.Context, args json.RawMessage) (string, error) {
+	n := t.calls.Add(1)
+	if strings.Contains(string(args), "fail") {
+		return "", errors.New("failed")
+	}
+	return fmt.Sprintf("%s #%d", args, n), nil
+}
+func (t *echoTool) CacheableResult(result string) bool {
+	return !strings.Contains(result, "unavailable")
+}
+
+func TestRegistry_Cache(t *testing.T) {
+	tool := &echoTool{}
+	search := &searchTool{}
+	reg := NewRegistry()
+	reg.Register(tool)   //nolint:errcheck
+	reg.Register(search) //nolint:errcheck
+	ctx := context.Background()
+
+	// Disabled by default
+	reg.Execute(ctx, "echo", json.RawMessage(`{"cache": true}`)) //nolint:errcheck
+	if _, hit, _ := reg.ExecuteCached(ctx, "echo", json.RawMessage(`{"cache": true}`)); hit || tool.calls.Load() != 2 {
+		t.Fatalf("uncached registry hit = %v after %d calls", hit, tool.calls.Load())
+	}
+
+	reg.EnableCache(CacheConfig{TTL: time.Minute, MaxEntries: 2})
+	first, hit, err := reg.ExecuteCached(ctx, "echo", json.RawMessage(`{"cache": true, "q": 1}`))
+	if err != nil || hit {
+		t.Fatalf("first call = %q, %v, %v", first, hit, err)
+	}
+	// Key order and spacing do not matter
+	again, hit, _ := reg.ExecuteCached(ctx, "echo", json.RawMessage(`{"q":1,"cache":true}`))
+	if !hit || again != first {
+		t.Errorf("repeat call = %q, hit %v; want cached %q", again, hit, first)
+	}
+
+	// Calls the tool declines, errors, and rejected results are not cached
+	for range 2 {
+		reg.Execute(ctx, "echo", json.RawMessage(`{"cache": false}`))                    //nolint:errcheck
+		reg.Execute(ctx, "echo", json.RawMessage(`{"cache": true, "q": "fail"}`))        //nolint:errcheck
+		reg.Execute(ctx, "echo", json.RawMessage(`{"cache": true, "q": "unavailable"}`)) //nolint:errcheck
+		reg.Execute(ctx, "search", json.RawMessage(`{"query": "x"}`))                    //nolint:errcheck
+	}
+	if n := tool.calls.Load(); n != 9 {
+		t.Errorf("echo ran %d times, want 9", n)
+	}
+	if search.calls != 2 {
+		t.Errorf("non-cacheable tool ran %d times, want 2", search.calls)
+	}
+}
+
+func TestResultCache_LRUAndTTL(t *testing.T) {
+	now := time.Now()
+	c := newResultCache(CacheConfig{TTL: time.Minute, MaxEntries: 2})
+	c.now = func() time.Time { return now }
+
+	c.put("a", "1")
+	c.put("b", "2")
+	c.get("a") //nolint:errcheck
+	c.put("c", "3")
+	if _, ok := c.get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if v, ok := c.get("a"); !ok || v != "1" {
+		t.Errorf("get(a) = %q, %v", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.get("a"); ok {
+		t.Error("expired entry was returned")
+	}
+	if len(c.entries) != 1 || c.order.Len() != 1 {
+		t.Errorf("expired entry kept: %d entries", len(c.entries))
+	}
+}
+
+func TestRegistry_CacheConcurrent(t *testing.T) {
+	tool := &echoTool{}
+	reg := NewRegistry()
+	reg.Register(tool) //nolint:errcheck
+	reg.EnableCache(CacheConfig{TTL: time.Minute, MaxEntries: 8})
+
+	var wg sync.WaitGroup
+	for i := range 64 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			args := json.RawMessage(fmt.Sprintf(`{"cache": true, "q": %d}`, i%16))
+			if _, err := reg.Execute(context.Background(), "echo", args); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Each result is served from the cache or a fresh run, never corrupted
+	for i := range 16 {
+		args := json.RawMessage(fmt.Sprintf(`{"cache": true, "q": %d}`, i))
+		out, _, err := reg.ExecuteCached(context.Background(), "echo", args)
+		if err != nil || !strings.HasPrefix(out, string(args)) {
+			t.Errorf("q=%d: %q, %v", i, out, err)
+		}
+	}
+	reg.cache.mu.Lock()
+	defer reg.cache.mu.Unlock()
+	if len(reg.cache.entries) > 8 || reg.cache.order.Len() != len(reg.cache.entries) {
+		t.Errorf("cache holds %d entries (%d in order), want at most 8", len(reg.cache.entries), reg.cache.order.Len())
+	}
+}
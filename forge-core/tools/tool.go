@@ -33,6 +33,68 @@ type Tool interface {
 	Execute(ctx context.Context, args json.RawMessage) (string, error)
 }
 
+// GuidanceProvider is implemented by tools that want usage guidance added to
+// the agent's system prompt when they are registered.
+type GuidanceProvider interface {
+	// Guidance returns a short instruction on how to use the tool well.
+	Guidance() string
+}
+
+// InputRedactor is implemented by tools whose input can carry secrets. The
+// redacted input is what logs, traces, and transcripts show; the tool still
+// receives the original.
+type InputRedactor interface {
+	// RedactInput returns args with secret values replaced.
+	RedactInput(args json.RawMessage) json.RawMessage
+}
+
+// Cacheable is implemented by deterministic tools whose results may be
+// reused for identical arguments when the registry's cache is enabled.
+// Tools that do not implement it are never cached.
+type Cacheable interface {
+	// Cacheable reports whether the result of a call with args may be reused.
+	Cacheable(args json.RawMessage) bool
+}
+
+// ResultCacheable is implemented by Cacheable tools whose results can
+// report a failure without returning an error, such as an HTTP 503. The
+// registry caches a result only when CacheableResult returns true.
+type ResultCacheable interface {
+	// CacheableResult reports whether result may be reused.
+	CacheableResult(result string) bool
+}
+
+// Mutation classifies whether a tool can change state outside the agent.
+type Mutation string
+
+const (
+	MutationReadOnly Mutation = "read_only" // reads or computes only; safe to repeat
+	MutationMutating Mutation = "mutating"  // may write files, send requests, or run commands
+)
+
+// MutationClassifier is implemented by tools that declare their mutation
+// class. Tools that do not are treated as mutating.
+type MutationClassifier interface {
+	// Mutation returns the tool's mutation class.
+	Mutation() Mutation
+}
+
+// ReadOnlyVariant is implemented by mutating tools that can offer a
+// restricted read-only form, such as http_request limited to GET.
+type ReadOnlyVariant interface {
+	// ReadOnly returns the read-only form of the tool.
+	ReadOnly() Tool
+}
+
+// MutationOf returns t's declared mutation class, defaulting to
+// MutationMutating.
+func MutationOf(t Tool) Mutation {
+	if mc, ok := t.(MutationClassifier); ok && mc.Mutation() == MutationReadOnly {
+		return MutationReadOnly
+	}
+	return MutationMutating
+}
+
 // ToLLMDefinition converts a Tool to an llm.ToolDefinition for use with LLM APIs.
 func ToLLMDefinition(t Tool) llm.ToolDefinition {
 	return llm.ToolDefinition{
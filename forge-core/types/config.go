@@ -19,6 +19,114 @@ type ForgeConfig struct {
 	Registry   string    `yaml:"registry,omitempty"`
 	Egress     EgressRef `yaml:"egress,omitempty"`
 	Skills     SkillsRef `yaml:"skills,omitempty"`
+	Memory     MemoryRef `yaml:"memory,omitempty"`
+	Help       HelpRef   `yaml:"help,omitempty"`
+
+	// TaskTimeout is the overall deadline for a single task as a Go
+	// duration string (e.g. "5m"). Empty means no deadline.
+	TaskTimeout string `yaml:"task_timeout,omitempty"`
+
+	// ParallelTools runs the tool calls of one model response concurrently.
+	ParallelTools bool `yaml:"parallel_tools,omitempty"`
+
+	// AskForInputs turns a tool call missing required inputs into a question
+	// to the user; the task waits in input-required for the answer.
+	AskForInputs bool `yaml:"ask_for_inputs,omitempty"`
+
+	// ValidateToolInputs checks tool arguments against each tool's input
+	// schema and returns mismatches to the model instead of running the tool.
+	ValidateToolInputs bool `yaml:"validate_tool_inputs,omitempty"`
+
+	// Explain records the agent loop steps of recent tasks, including full
+	// prompts and tool output, and serves them through tasks/explain.
+	Explain bool `yaml:"explain,omitempty"`
+
+	// ToolCache reuses results of cacheable tools for identical calls.
+	ToolCache ToolCacheRef `yaml:"tool_cache,omitempty"`
+
+	// PushNotifications lets A2A clients register a webhook that receives a
+	// task when it finishes.
+	PushNotifications PushNotificationsRef `yaml:"push_notifications,omitempty"`
+
+	// TaskStore selects where the A2A server keeps tasks.
+	TaskStore TaskStoreRef `yaml:"task_store,omitempty"`
+
+	// Pricing overrides the built-in token prices used for cost estimates,
+	// keyed by model name prefix.
+	Pricing map[string]ModelPriceRef `yaml:"pricing,omitempty"`
+
+	Tracing TracingRef `yaml:"tracing,omitempty"`
+
+	// Readiness controls how forge run waits for a crewai or langchain
+	// agent process to start serving before it accepts requests.
+	Readiness ReadinessRef `yaml:"readiness,omitempty"`
+}
+
+// ReadinessRef configures the readiness probe of a subprocess agent.
+type ReadinessRef struct {
+	Path    string `yaml:"path,omitempty"`    // HTTP path polled until it returns 200 (default /healthz)
+	Timeout string `yaml:"timeout,omitempty"` // Go duration, e.g. "90s" (default 60s)
+}
+
+// TracingRef configures export of agent loop traces to an OTLP/HTTP
+// collector.
+type TracingRef struct {
+	Format   string            `yaml:"format,omitempty"`   // "openinference" or "otel"; empty disables export
+	Endpoint string            `yaml:"endpoint,omitempty"` // default: OTEL_EXPORTER_OTLP_ENDPOINT or http://localhost:4318
+	Headers  map[string]string `yaml:"headers,omitempty"`  // e.g. authorization for a hosted collector
+}
+
+// ToolCacheRef configures the tool result cache. It is enabled when TTL is set.
+type ToolCacheRef struct {
+	TTL        string `yaml:"ttl,omitempty"`         // Go duration, e.g. "5m"
+	MaxEntries int    `yaml:"max_entries,omitempty"` // default 256
+}
+
+// PushNotificationsRef configures A2A push notifications. Clients choose the
+// webhook URL, so headers holding operator secrets require allowed_hosts.
+type PushNotificationsRef struct {
+	Enabled      bool              `yaml:"enabled,omitempty"`
+	AllowedHosts []string          `yaml:"allowed_hosts,omitempty"` // webhook hosts clients may register; "*.example.com" matches subdomains
+	Headers      map[string]string `yaml:"headers,omitempty"`       // sent with every callback; values expand ${VAR}
+}
+
+// TaskStoreRef configures task persistence. With no path, tasks are kept in
+// memory and lost on restart.
+type TaskStoreRef struct {
+	Path string `yaml:"path,omitempty"` // SQLite database file, relative to the project directory
+}
+
+// ModelPriceRef sets token prices for a model in USD per million tokens.
+type ModelPriceRef struct {
+	Input      float64 `yaml:"input"`
+	Output     float64 `yaml:"output"`
+	CacheRead  float64 `yaml:"cache_read,omitempty"`  // prompt tokens read from the cache; default: input
+	CacheWrite float64 `yaml:"cache_write,omitempty"` // prompt tokens written to the cache; default: input
+}
+
+// HelpRef configures the deterministic help response returned, without an
+// LLM call, when a message matches one of the triggers.
+type HelpRef struct {
+	Disabled bool     `yaml:"disabled,omitempty"`
+	Triggers []string `yaml:"triggers,omitempty"` // default: "", "help", "/help", "/start"
+	Message  string   `yaml:"message,omitempty"`  // default: derived from the agent card
+}
+
+// MemoryRef configures how much conversation history is replayed to the
+// model and how stored memories are retrieved.
+type MemoryRef struct {
+	MaxHistory       int          `yaml:"max_history,omitempty"`        // prior task messages to include; 0 = unlimited
+	KeepFirstMessage bool         `yaml:"keep_first_message,omitempty"` // always retain the first history message
+	Embedding        EmbeddingRef `yaml:"embedding,omitempty"`          // semantic retrieval; keyword search when unset
+
+	SessionIdleTimeout string `yaml:"session_idle_timeout,omitempty"` // drop a session's history after this long unused (default 30m)
+	SessionMaxTurns    int    `yaml:"session_max_turns,omitempty"`    // user turns kept per session (default 20)
+}
+
+// EmbeddingRef selects the embedding model used for memory retrieval.
+type EmbeddingRef struct {
+	Provider string `yaml:"provider,omitempty"` // openai, gemini
+	Model    string `yaml:"model,omitempty"`    // default: text-embedding-3-small (openai), text-embedding-004 (gemini)
 }
 
 // EgressRef configures egress security controls.
@@ -27,11 +135,13 @@ type EgressRef struct {
 	Mode           string   `yaml:"mode,omitempty"`    // deny-all, allowlist, dev-open
 	AllowedDomains []string `yaml:"allowed_domains,omitempty"`
 	Capabilities   []string `yaml:"capabilities,omitempty"` // capability bundles (e.g., "slack", "telegram")
+	CNI            string   `yaml:"cni,omitempty"`          // "cilium" renders allowlist egress as FQDN rules
 }
 
 // SkillsRef references a skills definition file.
 type SkillsRef struct {
-	Path string `yaml:"path,omitempty"` // default: "skills.md"
+	Path     string `yaml:"path,omitempty"`     // default: "skills.md"
+	Registry string `yaml:"registry,omitempty"` // hosted skill registry URL; FORGE_SKILL_REGISTRY takes precedence
 }
 
 // ModelRef identifies the model an agent uses.
@@ -39,6 +149,56 @@ type ModelRef struct {
 	Provider string `yaml:"provider"`
 	Name     string `yaml:"name"`
 	Version  string `yaml:"version,omitempty"`
+
+	// BaseURL sends requests for any provider to a proxy or gateway that
+	// speaks that provider's API.
+	BaseURL string `yaml:"base_url,omitempty"`
+
+	// APIStyle selects the OpenAI API: "chat" (Chat Completions) or
+	// "responses". Empty uses Responses for o-series reasoning models.
+	APIStyle string `yaml:"api_style,omitempty"`
+
+	// ReasoningEffort is passed to OpenAI reasoning models (minimal, low,
+	// medium, high).
+	ReasoningEffort string `yaml:"reasoning_effort,omitempty"`
+
+	// MaxRetries caps retries of rate-limited or failed LLM requests.
+	// Zero uses the provider default; negative disables retries.
+	MaxRetries int `yaml:"max_retries,omitempty"`
+
+	// Sampling parameters sent with every request. Unset values are omitted
+	// so the provider default applies.
+	Temperature *float64 `yaml:"temperature,omitempty"`
+	TopP        *float64 `yaml:"top_p,omitempty"`
+	Stop        []string `yaml:"stop,omitempty"`
+
+	// CacheSystemPrompt enables explicit prompt caching of the system prompt
+	// and tool definitions on providers that support it (Anthropic).
+	CacheSystemPrompt bool `yaml:"cache_system_prompt,omitempty"`
+
+	ResponseFormat ResponseFormatRef `yaml:"response_format,omitempty"`
+
+	// ContextWindow is the model's context size in tokens. When set, the
+	// oldest history is trimmed before a request that would exceed it.
+	ContextWindow int `yaml:"context_window,omitempty"`
+
+	// Fallbacks are tried in order when the primary model fails with a
+	// connection or server error.
+	Fallbacks []ModelFallbackRef `yaml:"fallbacks,omitempty"`
+}
+
+// ModelFallbackRef is a secondary model used when the primary is down.
+type ModelFallbackRef struct {
+	Provider string `yaml:"provider"`
+	Name     string `yaml:"name,omitempty"`     // default model for the provider when empty
+	BaseURL  string `yaml:"base_url,omitempty"` // overrides the provider's *_BASE_URL variable
+}
+
+// ResponseFormatRef constrains the agent's final answer to JSON.
+type ResponseFormatRef struct {
+	Type   string         `yaml:"type,omitempty"`   // text (default), json_object, or json_schema
+	Name   string         `yaml:"name,omitempty"`   // schema name sent to the provider
+	Schema map[string]any `yaml:"schema,omitempty"` // JSON Schema; required for json_schema
 }
 
 // ToolRef is a lightweight reference to a tool in forge.yaml.
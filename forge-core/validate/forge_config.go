@@ -2,24 +2,32 @@ package validate
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/initializ/forge/forge-core/llm"
 	"github.com/initializ/forge/forge-core/types"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 var (
 	agentIDPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
 	semverPattern  = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
 
-	knownFrameworks     = map[string]bool{"crewai": true, "langchain": true, "custom": true}
-	knownEgressProfiles = map[string]bool{"strict": true, "standard": true, "permissive": true}
-	knownEgressModes    = map[string]bool{"deny-all": true, "allowlist": true, "dev-open": true}
-	knownGuardrailTypes = map[string]bool{
+	knownFrameworks       = map[string]bool{"crewai": true, "langchain": true, "custom": true}
+	knownEgressProfiles   = map[string]bool{"strict": true, "standard": true, "permissive": true}
+	knownEgressModes      = map[string]bool{"deny-all": true, "allowlist": true, "dev-open": true}
+	knownReasoningEfforts = map[string]bool{"minimal": true, "low": true, "medium": true, "high": true}
+	knownGuardrailTypes   = map[string]bool{
 		"no_pii":                   true,
 		"jailbreak_protection":     true,
 		"tool_scope_enforcement":   true,
 		"output_format_validation": true,
 		"content_filter":           true,
+		"moderation":               true,
+		"no_secrets":               true,
 	}
 )
 
@@ -63,11 +71,55 @@ func ValidateForgeConfig(cfg *types.ForgeConfig) *ValidationResult {
 	if cfg.Model.Provider != "" && cfg.Model.Name == "" {
 		r.Warnings = append(r.Warnings, "model.provider is set but model.name is empty")
 	}
+	if cfg.Model.BaseURL != "" && cfg.Model.Provider == "" {
+		r.Warnings = append(r.Warnings, "model.base_url is set but model.provider is empty; it will be ignored")
+	}
+	if u := cfg.Model.BaseURL; u != "" && !isHTTPURL(u) {
+		r.Errors = append(r.Errors, fmt.Sprintf("model.base_url %q must be an absolute http or https URL", u))
+	}
+	for i, fb := range cfg.Model.Fallbacks {
+		if fb.Provider == "" {
+			r.Errors = append(r.Errors, fmt.Sprintf("model.fallbacks[%d]: provider is required", i))
+		}
+		if u := fb.BaseURL; u != "" && !isHTTPURL(u) {
+			r.Errors = append(r.Errors, fmt.Sprintf("model.fallbacks[%d].base_url %q must be an absolute http or https URL", i, u))
+		}
+	}
+	if t := cfg.Model.Temperature; t != nil && (*t < 0 || *t > 2) {
+		r.Errors = append(r.Errors, fmt.Sprintf("model.temperature %g must be between 0 and 2", *t))
+	}
+	if p := cfg.Model.TopP; p != nil && (*p < 0 || *p > 1) {
+		r.Errors = append(r.Errors, fmt.Sprintf("model.top_p %g must be between 0 and 1", *p))
+	}
+	if cfg.Model.ContextWindow < 0 {
+		r.Errors = append(r.Errors, fmt.Sprintf("model.context_window %d must not be negative", cfg.Model.ContextWindow))
+	}
+	if s := cfg.Model.APIStyle; s != "" && s != llm.APIStyleChat && s != llm.APIStyleResponses {
+		r.Errors = append(r.Errors, fmt.Sprintf("model.api_style %q must be chat or responses", s))
+	}
+	if e := cfg.Model.ReasoningEffort; e != "" && !knownReasoningEfforts[e] {
+		r.Errors = append(r.Errors, fmt.Sprintf("model.reasoning_effort %q must be one of: minimal, low, medium, high", e))
+	}
+	if (cfg.Model.APIStyle != "" || cfg.Model.ReasoningEffort != "") && cfg.Model.Provider != "" && cfg.Model.Provider != "openai" {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("model.api_style and model.reasoning_effort only apply to the openai provider, not %q", cfg.Model.Provider))
+	}
+	validateResponseFormat(cfg.Model.ResponseFormat, r)
+	if cfg.Model.Name != "" && len(cfg.Tools) > 0 && !llm.LookupCapabilities(cfg.Model.Name).Tools {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("model %q does not support tool calling; configured tools will not be offered to it", cfg.Model.Name))
+	}
 
 	if cfg.Framework != "" && !knownFrameworks[cfg.Framework] {
 		r.Warnings = append(r.Warnings, fmt.Sprintf("unknown framework %q (known: crewai, langchain, custom)", cfg.Framework))
 	}
 
+	if f := cfg.Tracing.Format; f != "" && f != "openinference" && f != "otel" {
+		r.Errors = append(r.Errors, fmt.Sprintf("tracing.format %q must be openinference or otel", f))
+	}
+
+	if pn := cfg.PushNotifications; len(pn.Headers) > 0 && len(pn.AllowedHosts) == 0 {
+		r.Errors = append(r.Errors, "push_notifications.headers requires push_notifications.allowed_hosts, since clients choose the webhook URL")
+	}
+
 	// Validate egress config
 	if cfg.Egress.Profile != "" && !knownEgressProfiles[cfg.Egress.Profile] {
 		r.Errors = append(r.Errors, fmt.Sprintf("egress.profile %q must be one of: strict, standard, permissive", cfg.Egress.Profile))
@@ -75,9 +127,72 @@ func ValidateForgeConfig(cfg *types.ForgeConfig) *ValidationResult {
 	if cfg.Egress.Mode != "" && !knownEgressModes[cfg.Egress.Mode] {
 		r.Errors = append(r.Errors, fmt.Sprintf("egress.mode %q must be one of: deny-all, allowlist, dev-open", cfg.Egress.Mode))
 	}
+	if cfg.Egress.CNI != "" && cfg.Egress.CNI != "cilium" {
+		r.Errors = append(r.Errors, fmt.Sprintf("egress.cni %q must be empty or cilium", cfg.Egress.CNI))
+	}
 	if cfg.Egress.Mode == "dev-open" {
 		r.Warnings = append(r.Warnings, "egress mode 'dev-open' is not recommended for production")
 	}
 
+	if cfg.TaskTimeout != "" {
+		if d, err := time.ParseDuration(cfg.TaskTimeout); err != nil || d <= 0 {
+			r.Errors = append(r.Errors, fmt.Sprintf("task_timeout %q must be a positive duration such as \"5m\"", cfg.TaskTimeout))
+		}
+	}
+	if cfg.ToolCache.TTL != "" {
+		if d, err := time.ParseDuration(cfg.ToolCache.TTL); err != nil || d <= 0 {
+			r.Errors = append(r.Errors, fmt.Sprintf("tool_cache.ttl %q must be a positive duration such as \"5m\"", cfg.ToolCache.TTL))
+		}
+	}
+	if cfg.ToolCache.MaxEntries < 0 {
+		r.Errors = append(r.Errors, fmt.Sprintf("tool_cache.max_entries %d must not be negative", cfg.ToolCache.MaxEntries))
+	}
+	if cfg.Memory.SessionIdleTimeout != "" {
+		if d, err := time.ParseDuration(cfg.Memory.SessionIdleTimeout); err != nil || d <= 0 {
+			r.Errors = append(r.Errors, fmt.Sprintf("memory.session_idle_timeout %q must be a positive duration such as \"30m\"", cfg.Memory.SessionIdleTimeout))
+		}
+	}
+	if cfg.Memory.SessionMaxTurns < 0 {
+		r.Errors = append(r.Errors, fmt.Sprintf("memory.session_max_turns %d must not be negative", cfg.Memory.SessionMaxTurns))
+	}
+	if u := cfg.Skills.Registry; u != "" && !isHTTPURL(u) {
+		r.Errors = append(r.Errors, fmt.Sprintf("skills.registry %q must be an http or https URL", u))
+	}
+	if p := cfg.Readiness.Path; p != "" && !strings.HasPrefix(p, "/") {
+		r.Errors = append(r.Errors, fmt.Sprintf("readiness.path %q must start with /", p))
+	}
+	if cfg.Readiness.Timeout != "" {
+		if d, err := time.ParseDuration(cfg.Readiness.Timeout); err != nil || d <= 0 {
+			r.Errors = append(r.Errors, fmt.Sprintf("readiness.timeout %q must be a positive duration such as \"60s\"", cfg.Readiness.Timeout))
+		}
+	}
+	if cfg.Memory.MaxHistory < 0 {
+		r.Errors = append(r.Errors, fmt.Sprintf("memory.max_history %d must not be negative", cfg.Memory.MaxHistory))
+	}
+
 	return r
 }
+
+// isHTTPURL reports whether u is an absolute http or https URL.
+func isHTTPURL(u string) bool {
+	parsed, err := url.Parse(u)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// validateResponseFormat checks model.response_format, including that its
+// schema compiles.
+func validateResponseFormat(rf types.ResponseFormatRef, r *ValidationResult) {
+	switch rf.Type {
+	case "", "text", "json_object":
+	case "json_schema":
+		if len(rf.Schema) == 0 {
+			r.Errors = append(r.Errors, "model.response_format.schema is required for json_schema")
+			return
+		}
+		if _, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(rf.Schema)); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("model.response_format.schema is not a valid JSON Schema: %v", err))
+		}
+	default:
+		r.Errors = append(r.Errors, fmt.Sprintf("model.response_format.type %q must be one of: text, json_object, json_schema", rf.Type))
+	}
+}
@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/initializ/forge/forge-core/types"
@@ -80,6 +81,83 @@ func TestValidateForgeConfig_EmptyToolName(t *testing.T) {
 	}
 }
 
+func TestValidateForgeConfig_NegativeMaxHistory(t *testing.T) {
+	cfg := validConfig()
+	cfg.Memory.MaxHistory = -1
+	r := ValidateForgeConfig(cfg)
+	if r.IsValid() {
+		t.Fatal("expected invalid")
+	}
+}
+
+func TestValidateForgeConfig_SessionMemory(t *testing.T) {
+	cfg := validConfig()
+	cfg.Memory = types.MemoryRef{SessionIdleTimeout: "1h", SessionMaxTurns: 10}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	for _, bad := range []types.MemoryRef{{SessionIdleTimeout: "idle"}, {SessionIdleTimeout: "-5m"}, {SessionMaxTurns: -1}} {
+		cfg.Memory = bad
+		if r := ValidateForgeConfig(cfg); r.IsValid() {
+			t.Errorf("memory %+v: expected invalid", bad)
+		}
+	}
+}
+
+func TestValidateForgeConfig_TaskTimeout(t *testing.T) {
+	cfg := validConfig()
+	cfg.TaskTimeout = "5m"
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	for _, bad := range []string{"soon", "-1m", "0s"} {
+		cfg.TaskTimeout = bad
+		if r := ValidateForgeConfig(cfg); r.IsValid() {
+			t.Errorf("task_timeout %q: expected invalid", bad)
+		}
+	}
+}
+
+func TestValidateForgeConfig_SkillsRegistry(t *testing.T) {
+	cfg := validConfig()
+	cfg.Skills.Registry = "https://skills.example.com/forge"
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	cfg.Skills.Registry = "skills.example.com"
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("skills.registry without a scheme: expected invalid")
+	}
+}
+
+func TestValidateForgeConfig_Readiness(t *testing.T) {
+	cfg := validConfig()
+	cfg.Readiness = types.ReadinessRef{Path: "/ready", Timeout: "90s"}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	for _, bad := range []types.ReadinessRef{{Path: "ready"}, {Timeout: "soon"}, {Timeout: "0s"}} {
+		cfg.Readiness = bad
+		if r := ValidateForgeConfig(cfg); r.IsValid() {
+			t.Errorf("readiness %+v: expected invalid", bad)
+		}
+	}
+}
+
+func TestValidateForgeConfig_ToolCache(t *testing.T) {
+	cfg := validConfig()
+	cfg.ToolCache = types.ToolCacheRef{TTL: "10m", MaxEntries: 100}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	for _, bad := range []types.ToolCacheRef{{TTL: "later"}, {TTL: "0s"}, {TTL: "1m", MaxEntries: -1}} {
+		cfg.ToolCache = bad
+		if r := ValidateForgeConfig(cfg); r.IsValid() {
+			t.Errorf("tool_cache %+v: expected invalid", bad)
+		}
+	}
+}
+
 func TestValidateForgeConfig_ProviderWithoutName(t *testing.T) {
 	cfg := validConfig()
 	cfg.Model = types.ModelRef{Provider: "openai", Name: ""}
@@ -92,6 +170,18 @@ func TestValidateForgeConfig_ProviderWithoutName(t *testing.T) {
 	}
 }
 
+func TestValidateForgeConfig_ModelWithoutToolSupport(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model = types.ModelRef{Provider: "openai", Name: "o1-mini"}
+	r := ValidateForgeConfig(cfg)
+	if !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	if len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "does not support tool calling") {
+		t.Fatalf("expected tool calling warning, got: %v", r.Warnings)
+	}
+}
+
 func TestValidateForgeConfig_UnknownFramework(t *testing.T) {
 	cfg := validConfig()
 	cfg.Framework = "autogen"
@@ -103,3 +193,131 @@ func TestValidateForgeConfig_UnknownFramework(t *testing.T) {
 		t.Fatalf("expected 1 warning, got %d: %v", len(r.Warnings), r.Warnings)
 	}
 }
+
+func TestValidateForgeConfig_SamplingRanges(t *testing.T) {
+	cfg := validConfig()
+	temp, topP := 0.7, 0.9
+	cfg.Model.Temperature = &temp
+	cfg.Model.TopP = &topP
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+
+	badTemp, badTopP := 2.5, 1.5
+	cfg.Model.Temperature = &badTemp
+	cfg.Model.TopP = &badTopP
+	r := ValidateForgeConfig(cfg)
+	if len(r.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(r.Errors), r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_OpenAIAPIStyle(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.Provider = "openai"
+	cfg.Model.APIStyle = "responses"
+	cfg.Model.ReasoningEffort = "high"
+	if r := ValidateForgeConfig(cfg); !r.IsValid() || len(r.Warnings) != 0 {
+		t.Fatalf("expected valid without warnings, got errors %v, warnings %v", r.Errors, r.Warnings)
+	}
+
+	cfg.Model.APIStyle = "completions"
+	cfg.Model.ReasoningEffort = "max"
+	if r := ValidateForgeConfig(cfg); len(r.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(r.Errors), r.Errors)
+	}
+
+	cfg.Model.Provider = "anthropic"
+	cfg.Model.APIStyle = "chat"
+	cfg.Model.ReasoningEffort = ""
+	if r := ValidateForgeConfig(cfg); len(r.Warnings) != 1 {
+		t.Fatalf("expected 1 warning for a non-openai provider, got %v", r.Warnings)
+	}
+}
+
+func TestValidateForgeConfig_ContextWindow(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.ContextWindow = 128000
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	cfg.Model.ContextWindow = -1
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Error("negative context_window: expected invalid")
+	}
+}
+
+func TestValidateForgeConfig_Fallbacks(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.Fallbacks = []types.ModelFallbackRef{{Provider: "anthropic"}, {Provider: "ollama", BaseURL: "http://localhost:11434/v1"}}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	cfg.Model.Fallbacks = []types.ModelFallbackRef{{Name: "gpt-4o-mini"}, {Provider: "ollama", BaseURL: "localhost:11434"}}
+	if r := ValidateForgeConfig(cfg); len(r.Errors) != 2 {
+		t.Errorf("expected 2 errors, got %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_BaseURL(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.BaseURL = "https://gateway.example.com/openai/v1"
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+	for _, bad := range []string{"gateway.example.com", "ftp://gateway.example.com", "https://", "http://[::1"} {
+		cfg.Model.BaseURL = bad
+		if r := ValidateForgeConfig(cfg); r.IsValid() {
+			t.Errorf("base_url %q: expected invalid", bad)
+		}
+	}
+}
+
+func TestValidateForgeConfig_ResponseFormat(t *testing.T) {
+	cfg := validConfig()
+	cfg.Model.ResponseFormat = types.ResponseFormatRef{
+		Type:   "json_schema",
+		Schema: map[string]any{"type": "object", "required": []any{"answer"}},
+	}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Fatalf("expected valid, got errors: %v", r.Errors)
+	}
+
+	for _, bad := range []types.ResponseFormatRef{
+		{Type: "yaml"},
+		{Type: "json_schema"},
+		{Type: "json_schema", Schema: map[string]any{"type": 12}},
+	} {
+		cfg.Model.ResponseFormat = bad
+		if r := ValidateForgeConfig(cfg); r.IsValid() {
+			t.Errorf("response_format %+v: expected invalid", bad)
+		}
+	}
+}
+
+func TestValidateForgeConfig_PushHeadersNeedAllowedHosts(t *testing.T) {
+	cfg := validConfig()
+	cfg.PushNotifications = types.PushNotificationsRef{Enabled: true, Headers: map[string]string{"Authorization": "Bearer ${TOKEN}"}}
+	r := ValidateForgeConfig(cfg)
+	if r.IsValid() {
+		t.Fatal("expected error for headers without allowed_hosts")
+	}
+
+	cfg.PushNotifications.AllowedHosts = []string{"hooks.example.com"}
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("unexpected errors: %v", r.Errors)
+	}
+}
+
+func TestValidateForgeConfig_EgressCNI(t *testing.T) {
+	cfg := validConfig()
+	cfg.Egress = types.EgressRef{Mode: "allowlist", CNI: "calico"}
+	if r := ValidateForgeConfig(cfg); r.IsValid() {
+		t.Fatal("expected error for unsupported egress.cni")
+	}
+
+	cfg.Egress.CNI = "cilium"
+	if r := ValidateForgeConfig(cfg); !r.IsValid() {
+		t.Errorf("unexpected errors: %v", r.Errors)
+	}
+}
@@ -0,0 +1,43 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+)
+
+// CheckGuardrails verifies that every content_filter guardrail's "patterns"
+// are strings that compile as Go regular expressions. The runtime would
+// otherwise skip a bad pattern and filter less than configured.
+func CheckGuardrails(ps *agentspec.PolicyScaffold) error {
+	if ps == nil {
+		return nil
+	}
+	var bad []string
+	for i, gr := range ps.Guardrails {
+		if gr.Type != "content_filter" {
+			continue
+		}
+		list, ok := gr.Config["patterns"].([]any)
+		if !ok && gr.Config["patterns"] != nil {
+			bad = append(bad, fmt.Sprintf("guardrails[%d].config.patterns must be a list of strings", i))
+			continue
+		}
+		for j, v := range list {
+			expr, ok := v.(string)
+			if !ok {
+				bad = append(bad, fmt.Sprintf("guardrails[%d].config.patterns[%d] must be a string", i, j))
+				continue
+			}
+			if _, err := regexp.Compile(expr); err != nil {
+				bad = append(bad, fmt.Sprintf("guardrails[%d].config.patterns[%d] %q: %v", i, j, expr, err))
+			}
+		}
+	}
+	if len(bad) > 0 {
+		return fmt.Errorf("invalid content filter patterns: %s", strings.Join(bad, "; "))
+	}
+	return nil
+}
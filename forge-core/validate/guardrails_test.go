@@ -0,0 +1,34 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+)
+
+func TestCheckGuardrails(t *testing.T) {
+	valid := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{
+		{Type: "content_filter", Config: map[string]any{"patterns": []any{`\bACCT-\d{6}\b`}}},
+		{Type: "no_pii"},
+	}}
+	if err := CheckGuardrails(valid); err != nil {
+		t.Errorf("valid patterns: unexpected error %v", err)
+	}
+	if err := CheckGuardrails(nil); err != nil {
+		t.Errorf("nil scaffold: unexpected error %v", err)
+	}
+
+	invalid := &agentspec.PolicyScaffold{Guardrails: []agentspec.Guardrail{
+		{Type: "content_filter", Config: map[string]any{"patterns": []any{`ok`, `ACCT-(\d+`, 42}}},
+	}}
+	err := CheckGuardrails(invalid)
+	if err == nil {
+		t.Fatal("expected error for invalid patterns")
+	}
+	for _, want := range []string{`guardrails[0].config.patterns[1] "ACCT-(\\d+"`, "patterns[2] must be a string"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v, want it to contain %s", err, want)
+		}
+	}
+}
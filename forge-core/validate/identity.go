@@ -0,0 +1,26 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+// CheckSpecIdentity verifies that a built AgentSpec carries the agent_id and
+// version declared in forge.yaml. Image tags and export filenames are derived
+// from the spec, so a mismatch means they would not match the config.
+func CheckSpecIdentity(cfg *types.ForgeConfig, spec *agentspec.AgentSpec) error {
+	var drift []string
+	if spec.AgentID != cfg.AgentID {
+		drift = append(drift, fmt.Sprintf("agent_id %q in agent.json, %q in forge.yaml", spec.AgentID, cfg.AgentID))
+	}
+	if spec.Version != cfg.Version {
+		drift = append(drift, fmt.Sprintf("version %q in agent.json, %q in forge.yaml", spec.Version, cfg.Version))
+	}
+	if len(drift) > 0 {
+		return fmt.Errorf("build output does not match forge.yaml (%s); run 'forge build' again", strings.Join(drift, "; "))
+	}
+	return nil
+}
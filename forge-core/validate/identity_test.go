@@ -0,0 +1,30 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/agentspec"
+	"github.com/initializ/forge/forge-core/types"
+)
+
+func TestCheckSpecIdentity(t *testing.T) {
+	cfg := &types.ForgeConfig{AgentID: "my-agent", Version: "1.2.0"}
+
+	if err := CheckSpecIdentity(cfg, &agentspec.AgentSpec{AgentID: "my-agent", Version: "1.2.0"}); err != nil {
+		t.Errorf("matching identity: unexpected error %v", err)
+	}
+
+	err := CheckSpecIdentity(cfg, &agentspec.AgentSpec{AgentID: "my-agent", Version: "1.1.0"})
+	if err == nil {
+		t.Fatal("expected error for version drift")
+	}
+	if !strings.Contains(err.Error(), `version "1.1.0" in agent.json, "1.2.0" in forge.yaml`) {
+		t.Errorf("error = %v", err)
+	}
+
+	err = CheckSpecIdentity(cfg, &agentspec.AgentSpec{AgentID: "other-agent", Version: "1.2.0"})
+	if err == nil || !strings.Contains(err.Error(), "agent_id") {
+		t.Errorf("expected agent_id drift error, got %v", err)
+	}
+}
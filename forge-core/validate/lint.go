@@ -0,0 +1,275 @@
+package validate
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/initializ/forge/forge-core/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Lint issue severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// LintIssue is one finding from LintForgeConfig, located in forge.yaml.
+type LintIssue struct {
+	Severity   string `json:"severity"`             // SeverityError or SeverityWarning
+	Path       string `json:"path,omitempty"`       // config key, e.g. "egress.mode" or "tools[2]"
+	Line       int    `json:"line,omitempty"`       // 1-based; 0 when the key is not in the file
+	Column     int    `json:"column,omitempty"`     // 1-based
+	Message    string `json:"message"`              // as reported by ValidateForgeConfig
+	Suggestion string `json:"suggestion,omitempty"` // how to fix it, when known
+}
+
+var (
+	issuePathRe = regexp.MustCompile(`^([a-z_]+(?:\[\d+\])?(?:\.[a-z_]+(?:\[\d+\])?)*)(?:[ :]|$)`)
+	pathSegRe   = regexp.MustCompile(`^([a-z_]+)(?:\[(\d+)\])?$`)
+
+	// messagePaths locates validator messages that do not start with their key.
+	messagePaths = []struct{ prefix, path string }{
+		{"unknown framework", "framework"},
+		{"egress mode", "egress.mode"},
+		{"model ", "model.name"},
+	}
+
+	knownProviders = []string{"openai", "anthropic", "gemini", "ollama", "bedrock"}
+	topLevelKeys   = yamlKeys(reflect.TypeOf(types.ForgeConfig{}))
+)
+
+// LintForgeConfig runs ValidateForgeConfig and the cross-field checks below
+// it, locating each issue in source (the forge.yaml cfg was parsed from) and
+// suggesting a fix where one is known. Issues are sorted by line.
+func LintForgeConfig(cfg *types.ForgeConfig, source []byte) []LintIssue {
+	res := ValidateForgeConfig(cfg)
+	var issues []LintIssue
+	for _, msg := range res.Errors {
+		issues = append(issues, LintIssue{Severity: SeverityError, Path: issuePath(msg), Message: msg})
+	}
+	for _, msg := range res.Warnings {
+		issues = append(issues, LintIssue{Severity: SeverityWarning, Path: issuePath(msg), Message: msg})
+	}
+
+	issues = suggestKnownValues(cfg, issues)
+	issues = append(issues, lintCrossField(cfg)...)
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(source, &root); err == nil {
+		for i := range issues {
+			issues[i].Line, issues[i].Column = locate(&root, issues[i].Path)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
+
+// issuePath extracts the config key a validator message is about.
+func issuePath(msg string) string {
+	for _, mp := range messagePaths {
+		if strings.HasPrefix(msg, mp.prefix) {
+			return mp.path
+		}
+	}
+	if m := issuePathRe.FindStringSubmatch(msg); m != nil {
+		top := m[1]
+		if i := strings.IndexAny(top, ".["); i >= 0 {
+			top = top[:i]
+		}
+		if topLevelKeys[top] {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// yamlKeys returns the yaml key of each field of struct type t.
+func yamlKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// suggestKnownValues adds "did you mean" suggestions for enumerated fields
+// with a near-miss value. model.provider is not checked by the validator,
+// so an unknown provider is reported here.
+func suggestKnownValues(cfg *types.ForgeConfig, issues []LintIssue) []LintIssue {
+	fields := []struct {
+		path, value string
+		known       []string
+	}{
+		{"framework", cfg.Framework, mapKeys(knownFrameworks)},
+		{"egress.profile", cfg.Egress.Profile, mapKeys(knownEgressProfiles)},
+		{"egress.mode", cfg.Egress.Mode, mapKeys(knownEgressModes)},
+		{"model.reasoning_effort", cfg.Model.ReasoningEffort, mapKeys(knownReasoningEfforts)},
+		{"model.api_style", cfg.Model.APIStyle, []string{"chat", "responses"}},
+		{"model.response_format.type", cfg.Model.ResponseFormat.Type, []string{"text", "json_object", "json_schema"}},
+		{"tracing.format", cfg.Tracing.Format, []string{"openinference", "otel"}},
+		{"model.provider", cfg.Model.Provider, knownProviders},
+	}
+	for _, f := range fields {
+		if f.value == "" || slices.Contains(f.known, f.value) {
+			continue
+		}
+		suggestion := fmt.Sprintf("use one of: %s", strings.Join(f.known, ", "))
+		if near := closest(f.value, f.known); near != "" {
+			suggestion = fmt.Sprintf("did you mean %q?", near)
+		}
+
+		found := false
+		for i := range issues {
+			if issues[i].Path == f.path && strings.Contains(issues[i].Message, strconv.Quote(f.value)) {
+				issues[i].Suggestion = suggestion
+				found = true
+			}
+		}
+		if !found {
+			issues = append(issues, LintIssue{
+				Severity:   SeverityWarning,
+				Path:       f.path,
+				Message:    fmt.Sprintf("unknown %s %q", f.path, f.value),
+				Suggestion: suggestion,
+			})
+		}
+	}
+	return issues
+}
+
+// lintCrossField reports combinations of settings that are each valid but
+// do not work together.
+func lintCrossField(cfg *types.ForgeConfig) []LintIssue {
+	var issues []LintIssue
+
+	seen := make(map[string]int)
+	for i, t := range cfg.Tools {
+		path := fmt.Sprintf("tools[%d]", i)
+		if first, dup := seen[t.Name]; dup && t.Name != "" {
+			issues = append(issues, LintIssue{
+				Severity:   SeverityWarning,
+				Path:       path,
+				Message:    fmt.Sprintf("tool %q is listed more than once", t.Name),
+				Suggestion: fmt.Sprintf("merge it into tools[%d]", first),
+			})
+		} else {
+			seen[t.Name] = i
+		}
+
+		// Skills may supply the binaries at run time, which lint cannot see
+		if t.Name == "cli_execute" {
+			bins, _ := t.Config["allowed_binaries"].([]any)
+			if len(bins) == 0 {
+				issues = append(issues, LintIssue{
+					Severity:   SeverityWarning,
+					Path:       path,
+					Message:    "cli_execute has no allowed_binaries; the tool is registered only if skills require binaries",
+					Suggestion: fmt.Sprintf("list the binaries it may run under %s.config.allowed_binaries", path),
+				})
+			}
+		}
+	}
+
+	if cfg.Egress.Mode == "allowlist" && len(cfg.Egress.AllowedDomains) == 0 && len(cfg.Egress.Capabilities) == 0 {
+		issues = append(issues, LintIssue{
+			Severity:   SeverityWarning,
+			Path:       "egress.mode",
+			Message:    "egress.mode is allowlist but egress.allowed_domains is empty; only domains implied by the configured tools are reachable",
+			Suggestion: "run forge egress sync, or list the domains the agent needs under egress.allowed_domains",
+		})
+	}
+
+	if cfg.Model.Provider == "" && len(cfg.Model.Fallbacks) > 0 {
+		issues = append(issues, LintIssue{
+			Severity:   SeverityWarning,
+			Path:       "model.fallbacks",
+			Message:    "model.fallbacks is set but model.provider is empty; fallbacks apply only after a primary model",
+			Suggestion: "set model.provider and model.name",
+		})
+	}
+
+	return issues
+}
+
+// locate returns the line and column of the key at path in the YAML
+// document, or of its nearest ancestor present in the document.
+func locate(root *yaml.Node, path string) (int, int) {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || path == "" {
+		return 0, 0
+	}
+	node := root.Content[0]
+	line, col := 0, 0
+	for _, seg := range strings.Split(path, ".") {
+		m := pathSegRe.FindStringSubmatch(seg)
+		if m == nil || node.Kind != yaml.MappingNode {
+			break
+		}
+		var value *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == m[1] {
+				line, col = node.Content[i].Line, node.Content[i].Column
+				value = node.Content[i+1]
+				break
+			}
+		}
+		if value == nil {
+			break
+		}
+		node = value
+		if m[2] != "" {
+			idx, _ := strconv.Atoi(m[2])
+			if node.Kind != yaml.SequenceNode || idx >= len(node.Content) {
+				break
+			}
+			node = node.Content[idx]
+			line, col = node.Line, node.Column
+		}
+	}
+	return line, col
+}
+
+// closest returns the candidate nearest to s by edit distance, if it is
+// close enough to be a likely typo.
+func closest(s string, candidates []string) string {
+	best, bestDist := "", len(s)/2+1
+	for _, c := range candidates {
+		if d := editDistance(strings.ToLower(s), c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func mapKeys(m map[string]bool) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,165 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/initializ/forge/forge-core/types"
+	"gopkg.in/yaml.v3"
+)
+
+func lintYAML(t *testing.T, src string) []LintIssue {
+	t.Helper()
+	var cfg types.ForgeConfig
+	if err := yaml.Unmarshal([]byte(src), &cfg); err != nil {
+		t.Fatalf("parsing config: %v", err)
+	}
+	return LintForgeConfig(&cfg, []byte(src))
+}
+
+func findIssue(issues []LintIssue, path string) *LintIssue {
+	for i := range issues {
+		if issues[i].Path == path {
+			return &issues[i]
+		}
+	}
+	return nil
+}
+
+func TestLintForgeConfig_Clean(t *testing.T) {
+	issues := lintYAML(t, `agent_id: my-agent
+version: 0.1.0
+framework: langchain
+entrypoint: python agent.py
+model:
+  provider: openai
+  name: gpt-4o
+`)
+	if len(issues) != 0 {
+		t.Errorf("issues = %+v, want none", issues)
+	}
+}
+
+func TestLintForgeConfig_SuggestsKnownValues(t *testing.T) {
+	issues := lintYAML(t, `agent_id: my-agent
+version: 0.1.0
+framework: langchian
+entrypoint: python agent.py
+model:
+  provider: anthropc
+  name: claude-sonnet-4-20250514
+egress:
+  mode: alowlist
+  allowed_domains: [api.example.com]
+`)
+
+	fw := findIssue(issues, "framework")
+	if fw == nil || fw.Severity != SeverityWarning || fw.Suggestion != `did you mean "langchain"?` {
+		t.Errorf("framework issue = %+v", fw)
+	} else if fw.Line != 3 || fw.Column != 1 {
+		t.Errorf("framework at %d:%d, want 3:1", fw.Line, fw.Column)
+	}
+
+	// The validator does not check providers, so lint reports it itself.
+	p := findIssue(issues, "model.provider")
+	if p == nil || p.Message != `unknown model.provider "anthropc"` || p.Suggestion != `did you mean "anthropic"?` {
+		t.Errorf("provider issue = %+v", p)
+	} else if p.Line != 6 || p.Column != 3 {
+		t.Errorf("provider at %d:%d, want 6:3", p.Line, p.Column)
+	}
+
+	mode := findIssue(issues, "egress.mode")
+	if mode == nil || mode.Severity != SeverityError || mode.Suggestion != `did you mean "allowlist"?` || mode.Line != 9 {
+		t.Errorf("egress.mode issue = %+v", mode)
+	}
+
+	for i := 1; i < len(issues); i++ {
+		if issues[i].Line < issues[i-1].Line {
+			t.Errorf("issues not sorted by line: %+v", issues)
+		}
+	}
+}
+
+func TestLintForgeConfig_NoCloseMatch(t *testing.T) {
+	issues := lintYAML(t, `agent_id: my-agent
+version: 0.1.0
+entrypoint: python agent.py
+tracing:
+  format: zipkin
+`)
+	tr := findIssue(issues, "tracing.format")
+	if tr == nil || tr.Suggestion != "use one of: openinference, otel" {
+		t.Errorf("tracing.format issue = %+v", tr)
+	}
+}
+
+func TestLintForgeConfig_CrossField(t *testing.T) {
+	issues := lintYAML(t, `agent_id: my-agent
+version: 0.1.0
+entrypoint: python agent.py
+tools:
+  - name: web_search
+  - name: cli_execute
+    config:
+      timeout: 30
+  - name: web_search
+egress:
+  mode: allowlist
+`)
+
+	cli := findIssue(issues, "tools[1]")
+	if cli == nil || cli.Severity != SeverityWarning || !strings.Contains(cli.Message, "allowed_binaries") {
+		t.Fatalf("cli_execute issue = %+v", cli)
+	}
+	if cli.Line != 6 || cli.Column != 5 {
+		t.Errorf("cli_execute at %d:%d, want 6:5", cli.Line, cli.Column)
+	}
+
+	dup := findIssue(issues, "tools[2]")
+	if dup == nil || !strings.Contains(dup.Message, "more than once") || dup.Suggestion != "merge it into tools[0]" {
+		t.Errorf("duplicate tool issue = %+v", dup)
+	}
+
+	egress := findIssue(issues, "egress.mode")
+	if egress == nil || egress.Severity != SeverityWarning || !strings.Contains(egress.Suggestion, "forge egress sync") {
+		t.Errorf("egress issue = %+v", egress)
+	}
+}
+
+func TestLintForgeConfig_LocatesMissingKeys(t *testing.T) {
+	issues := lintYAML(t, `version: 0.1.0
+entrypoint: python agent.py
+model:
+  provider: openai
+  name: gpt-4o
+  temperature: 3
+`)
+
+	// A missing key has no line.
+	id := findIssue(issues, "agent_id")
+	if id == nil || id.Line != 0 {
+		t.Errorf("agent_id issue = %+v, want no line", id)
+	}
+	temp := findIssue(issues, "model.temperature")
+	if temp == nil || temp.Line != 6 {
+		t.Errorf("model.temperature issue = %+v, want line 6", temp)
+	}
+}
+
+func TestIssuePath(t *testing.T) {
+	tests := map[string]string{
+		"agent_id is required":                            "agent_id",
+		"tools[2]: name is required":                      "tools[2]",
+		`model.base_url "x" must be an absolute http URL`: "model.base_url",
+		`unknown framework "x" (known: crewai)`:           "framework",
+		"egress mode 'dev-open' is not recommended":       "egress.mode",
+		`model "llama2" does not support tool calling`:    "model.name",
+		"model.response_format.schema is required":        "model.response_format.schema",
+		"something without a key":                         "",
+	}
+	for msg, want := range tests {
+		if got := issuePath(msg); got != want {
+			t.Errorf("issuePath(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
@@ -0,0 +1,588 @@
+// Package discord implements the Discord channel plugin for the forge channel system.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+	"github.com/initializ/forge/forge-plugins/channels/markdown"
+)
+
+const (
+	discordAPIBase    = "https://discord.com/api/v10"
+	discordGatewayURL = "wss://gateway.discord.gg/?v=10&encoding=json"
+	gatewayQuery      = "/?v=10&encoding=json"
+
+	// messageLimit is the maximum length of a Discord message.
+	messageLimit = 2000
+
+	// maxGatewayPayload bounds a gateway message; READY can be large for
+	// bots in many guilds.
+	maxGatewayPayload = 8 << 20
+
+	// Gateway intents: guild messages, direct messages, and message content
+	// (a privileged intent enabled in the developer portal).
+	defaultIntents = 1<<9 | 1<<12 | 1<<15
+)
+
+// Gateway opcodes.
+const (
+	opDispatch       = 0
+	opHeartbeat      = 1
+	opIdentify       = 2
+	opResume         = 6
+	opReconnect      = 7
+	opInvalidSession = 9
+	opHello          = 10
+	opHeartbeatACK   = 11
+)
+
+// Plugin implements channels.ChannelPlugin for Discord. It receives
+// messages over the gateway websocket and replies through the REST API.
+type Plugin struct {
+	botToken       string
+	requireMention bool // in guild channels, only answer messages that mention the bot
+	client         *http.Client
+	apiBase        string // overridable for tests
+	gatewayURL     string // overridable for tests
+	stopCh         chan struct{}
+
+	mu        sync.Mutex
+	botUserID string
+	sessionID string // for resuming after a dropped connection
+	resumeURL string
+	seq       *int64 // last dispatch sequence number
+}
+
+// New creates an uninitialised Discord plugin.
+func New() *Plugin {
+	return &Plugin{
+		client:     &http.Client{Timeout: 30 * time.Second},
+		apiBase:    discordAPIBase,
+		gatewayURL: discordGatewayURL,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+func (p *Plugin) Name() string { return "discord" }
+
+func (p *Plugin) Init(cfg channels.ChannelConfig) error {
+	settings := channels.ResolveEnvVars(&cfg)
+
+	p.botToken = settings["bot_token"]
+	if p.botToken == "" {
+		return fmt.Errorf("discord: bot_token is required (set DISCORD_BOT_TOKEN)")
+	}
+
+	p.requireMention = true
+	if v := settings["require_mention"]; v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("discord: require_mention must be true or false, got %q", v)
+		}
+		p.requireMention = b
+	}
+	return nil
+}
+
+// Start connects to the gateway and dispatches messages until ctx is
+// cancelled or Stop is called. Dropped connections are resumed, or
+// re-identified when Discord rejects the resume.
+func (p *Plugin) Start(ctx context.Context, handler channels.EventHandler) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-p.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	fmt.Printf("  Discord adapter (gateway) started\n")
+	for {
+		err := p.runSession(ctx, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var fatal *fatalCloseError
+		if errors.As(err, &fatal) {
+			return err
+		}
+		if err != nil {
+			fmt.Printf("discord: gateway connection lost: %v\n", err)
+		}
+		// Don't flood on errors, sleep briefly
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func (p *Plugin) Stop() error {
+	select {
+	case <-p.stopCh:
+	default:
+		close(p.stopCh)
+	}
+	return nil
+}
+
+// gatewayPayload is a message on the Discord gateway.
+type gatewayPayload struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d,omitempty"`
+	S  *int64          `json:"s,omitempty"`
+	T  string          `json:"t,omitempty"`
+}
+
+// fatalCloseError is a gateway close code that reconnecting cannot fix.
+type fatalCloseError struct {
+	code   websocket.StatusCode
+	reason string
+}
+
+func (e *fatalCloseError) Error() string {
+	return fmt.Sprintf("discord: gateway closed with code %d: %s", e.code, e.reason)
+}
+
+// fatalCloseCodes are gateway close codes that need a configuration change.
+var fatalCloseCodes = map[websocket.StatusCode]string{
+	4004: "authentication failed; check DISCORD_BOT_TOKEN",
+	4010: "invalid shard",
+	4011: "sharding required",
+	4012: "invalid API version",
+	4013: "invalid intents",
+	4014: "disallowed intents; enable the Message Content intent for the bot in the Discord developer portal",
+}
+
+// runSession runs one gateway connection until it drops.
+func (p *Plugin) runSession(ctx context.Context, handler channels.EventHandler) error {
+	p.mu.Lock()
+	url, resuming := p.gatewayURL, p.sessionID != ""
+	if resuming && p.resumeURL != "" {
+		url = strings.TrimSuffix(p.resumeURL, "/") + gatewayQuery
+	}
+	p.mu.Unlock()
+
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		return fmt.Errorf("connecting to gateway: %w", err)
+	}
+	defer conn.CloseNow() //nolint:errcheck
+	conn.SetReadLimit(maxGatewayPayload)
+
+	var hello gatewayPayload
+	if err := wsjson.Read(ctx, conn, &hello); err != nil {
+		return p.closeError(err)
+	}
+	var helloData struct {
+		HeartbeatInterval int64 `json:"heartbeat_interval"`
+	}
+	if hello.Op != opHello || json.Unmarshal(hello.D, &helloData) != nil || helloData.HeartbeatInterval <= 0 {
+		return fmt.Errorf("expected hello from gateway, got op %d", hello.Op)
+	}
+
+	if resuming {
+		err = p.sendResume(ctx, conn)
+	} else {
+		err = p.sendIdentify(ctx, conn)
+	}
+	if err != nil {
+		return err
+	}
+
+	sessCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	acked := make(chan struct{}, 1)
+	go p.heartbeat(sessCtx, conn, time.Duration(helloData.HeartbeatInterval)*time.Millisecond, acked)
+
+	for {
+		var payload gatewayPayload
+		if err := wsjson.Read(ctx, conn, &payload); err != nil {
+			return p.closeError(err)
+		}
+		if payload.S != nil {
+			p.mu.Lock()
+			p.seq = payload.S
+			p.mu.Unlock()
+		}
+
+		switch payload.Op {
+		case opDispatch:
+			p.dispatch(ctx, payload, handler)
+		case opHeartbeat:
+			if err := p.sendHeartbeat(ctx, conn); err != nil {
+				return err
+			}
+		case opHeartbeatACK:
+			select {
+			case acked <- struct{}{}:
+			default:
+			}
+		case opReconnect:
+			return fmt.Errorf("gateway requested a reconnect")
+		case opInvalidSession:
+			var resumable bool
+			json.Unmarshal(payload.D, &resumable) //nolint:errcheck
+			if !resumable {
+				p.mu.Lock()
+				p.sessionID, p.resumeURL, p.seq = "", "", nil
+				p.mu.Unlock()
+			}
+			return fmt.Errorf("gateway invalidated the session")
+		}
+	}
+}
+
+// closeError turns a fatal gateway close code into a fatalCloseError.
+func (p *Plugin) closeError(err error) error {
+	code := websocket.CloseStatus(err)
+	if reason, ok := fatalCloseCodes[code]; ok {
+		return &fatalCloseError{code: code, reason: reason}
+	}
+	return err
+}
+
+// heartbeat sends a heartbeat every interval. A connection that did not
+// acknowledge the previous heartbeat is closed so it can be resumed.
+func (p *Plugin) heartbeat(ctx context.Context, conn *websocket.Conn, interval time.Duration, acked <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	waiting := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-acked:
+			waiting = false
+		case <-ticker.C:
+			if waiting {
+				conn.Close(websocket.StatusCode(4000), "heartbeat not acknowledged") //nolint:errcheck
+				return
+			}
+			if err := p.sendHeartbeat(ctx, conn); err != nil {
+				return
+			}
+			waiting = true
+		}
+	}
+}
+
+func (p *Plugin) sendHeartbeat(ctx context.Context, conn *websocket.Conn) error {
+	p.mu.Lock()
+	seq := p.seq
+	p.mu.Unlock()
+	return wsjson.Write(ctx, conn, map[string]any{"op": opHeartbeat, "d": seq})
+}
+
+func (p *Plugin) sendIdentify(ctx context.Context, conn *websocket.Conn) error {
+	return wsjson.Write(ctx, conn, map[string]any{
+		"op": opIdentify,
+		"d": map[string]any{
+			"token":   p.botToken,
+			"intents": defaultIntents,
+			"properties": map[string]string{
+				"os":      "linux",
+				"browser": "forge",
+				"device":  "forge",
+			},
+		},
+	})
+}
+
+func (p *Plugin) sendResume(ctx context.Context, conn *websocket.Conn) error {
+	p.mu.Lock()
+	d := map[string]any{"token": p.botToken, "session_id": p.sessionID, "seq": p.seq}
+	p.mu.Unlock()
+	return wsjson.Write(ctx, conn, map[string]any{"op": opResume, "d": d})
+}
+
+// dispatch handles a gateway event. READY records the bot's identity and
+// session; MESSAGE_CREATE is passed to the handler when the bot should
+// answer it.
+func (p *Plugin) dispatch(ctx context.Context, payload gatewayPayload, handler channels.EventHandler) {
+	switch payload.T {
+	case "READY":
+		var ready struct {
+			User             discordUser `json:"user"`
+			SessionID        string      `json:"session_id"`
+			ResumeGatewayURL string      `json:"resume_gateway_url"`
+		}
+		if err := json.Unmarshal(payload.D, &ready); err != nil {
+			fmt.Printf("discord: parsing READY: %v\n", err)
+			return
+		}
+		p.mu.Lock()
+		p.botUserID, p.sessionID, p.resumeURL = ready.User.ID, ready.SessionID, ready.ResumeGatewayURL
+		p.mu.Unlock()
+
+	case "MESSAGE_CREATE":
+		var msg discordMessage
+		if err := json.Unmarshal(payload.D, &msg); err != nil || !p.shouldRespond(&msg) {
+			return
+		}
+		event, err := p.NormalizeEvent(payload.D)
+		if err != nil {
+			return
+		}
+		go func() {
+			stopTyping := p.startTypingIndicator(ctx, event.WorkspaceID)
+			resp, err := handler(ctx, event)
+			stopTyping()
+			if err != nil {
+				fmt.Printf("discord: handler error: %v\n", err)
+				return
+			}
+			if err := p.SendResponse(event, resp); err != nil {
+				fmt.Printf("discord: send response error: %v\n", err)
+			}
+		}()
+	}
+}
+
+// shouldRespond reports whether the bot should answer msg. Messages from
+// bots, including itself, are ignored. In guild channels with
+// require_mention on, only messages that mention the bot are answered;
+// direct messages always are.
+func (p *Plugin) shouldRespond(msg *discordMessage) bool {
+	if msg.Author.Bot {
+		return false
+	}
+	if msg.GuildID == "" || !p.requireMention {
+		return true
+	}
+	p.mu.Lock()
+	botID := p.botUserID
+	p.mu.Unlock()
+	for _, u := range msg.Mentions {
+		if u.ID == botID {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeEvent parses a Discord message object, the data of a
+// MESSAGE_CREATE gateway event, into a ChannelEvent. Mentions of the bot
+// are removed from the text.
+func (p *Plugin) NormalizeEvent(raw []byte) (*channels.ChannelEvent, error) {
+	var msg discordMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return nil, fmt.Errorf("parsing discord message: %w", err)
+	}
+	if msg.ID == "" || msg.ChannelID == "" {
+		return nil, fmt.Errorf("discord event is not a message")
+	}
+
+	text := msg.Content
+	p.mu.Lock()
+	botID := p.botUserID
+	p.mu.Unlock()
+	if botID != "" {
+		text = strings.ReplaceAll(text, "<@"+botID+">", "")
+		text = strings.ReplaceAll(text, "<@!"+botID+">", "")
+		text = strings.TrimSpace(text)
+	}
+
+	event := &channels.ChannelEvent{
+		Channel:     "discord",
+		WorkspaceID: msg.ChannelID,
+		UserID:      msg.Author.ID,
+		ThreadID:    msg.ID,
+		MessageID:   msg.ID,
+		Message:     text,
+		Raw:         raw,
+	}
+	for _, a := range msg.Attachments {
+		event.Attachments = append(event.Attachments, channels.Attachment{Name: a.Filename, MimeType: a.ContentType, URL: a.URL})
+	}
+	if ref := msg.ReferencedMessage; ref != nil && ref.Content != "" {
+		event.Context = &channels.MessageContext{ReplyToText: ref.Content}
+	}
+	return event, nil
+}
+
+// SendResponse posts the response to the event's channel, as a reply to
+// the triggering message, converted to Discord markdown and split at the
+// 2000-character message limit.
+func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Message) error {
+	ctx, cancel := p.stopContext()
+	defer cancel()
+	text := markdown.ToDiscordMarkdown(extractText(response))
+	for i, chunk := range markdown.SplitMessage(text, messageLimit) {
+		payload := map[string]any{
+			"content": chunk,
+			// Agent output must not ping @everyone, roles, or users
+			"allowed_mentions": map[string]any{"parse": []string{}},
+		}
+		if i == 0 && event.ThreadID != "" {
+			payload["message_reference"] = map[string]any{"message_id": event.ThreadID, "fail_if_not_exists": false}
+		}
+		if err := p.post(ctx, "/channels/"+event.WorkspaceID+"/messages", payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startTypingIndicator shows the bot as typing until the returned stop
+// function is called. Discord's indicator lasts about 10 seconds, so it is
+// resent every 8.
+func (p *Plugin) startTypingIndicator(ctx context.Context, channelID string) (stop func()) {
+	done := make(chan struct{})
+	stop = func() {
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+	}
+
+	_ = p.post(ctx, "/channels/"+channelID+"/typing", nil)
+
+	go func() {
+		ticker := time.NewTicker(8 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = p.post(ctx, "/channels/"+channelID+"/typing", nil)
+			}
+		}
+	}()
+
+	return stop
+}
+
+// stopContext returns a context that is cancelled when Stop is called, for
+// calls such as SendResponse that are not given one.
+func (p *Plugin) stopContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-p.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// post sends a JSON payload to the REST API. A rate-limited request is
+// retried once after the delay Discord asks for, unless ctx ends first.
+func (p *Plugin) post(ctx context.Context, path string, payload any) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("marshalling discord request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiBase+path, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("creating discord request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bot "+p.botToken)
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("posting to discord: %w", err)
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt == 0 {
+			var limited struct {
+				RetryAfter float64 `json:"retry_after"` // seconds
+			}
+			json.Unmarshal(respBody, &limited) //nolint:errcheck
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("posting to discord: %w", ctx.Err())
+			case <-time.After(time.Duration(min(limited.RetryAfter, 10) * float64(time.Second))):
+			}
+			continue
+		}
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("discord API error %d: %s", resp.StatusCode, string(respBody))
+		}
+		return nil
+	}
+}
+
+// extractText concatenates all text parts from an A2A message. File and
+// data parts, which are not uploaded, are mentioned on their own lines.
+func extractText(msg *a2a.Message) string {
+	if msg == nil {
+		return "(no response)"
+	}
+	var text string
+	for _, p := range msg.Parts {
+		line := p.Text
+		if p.Kind != a2a.PartKindText {
+			line = channels.PartNote(p)
+		}
+		if line == "" {
+			continue
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += line
+	}
+	if text == "" {
+		text = "(no text response)"
+	}
+	return text
+}
+
+// Discord API types (minimal, for parsing).
+
+type discordMessage struct {
+	ID                string              `json:"id"`
+	ChannelID         string              `json:"channel_id"`
+	GuildID           string              `json:"guild_id,omitempty"`
+	Author            discordUser         `json:"author"`
+	Content           string              `json:"content"`
+	Mentions          []discordUser       `json:"mentions,omitempty"`
+	Attachments       []discordAttachment `json:"attachments,omitempty"`
+	ReferencedMessage *discordMessage     `json:"referenced_message,omitempty"`
+}
+
+type discordUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username,omitempty"`
+	Bot      bool   `json:"bot,omitempty"`
+}
+
+type discordAttachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type,omitempty"`
+	URL         string `json:"url"`
+}
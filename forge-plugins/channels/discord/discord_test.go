@@ -0,0 +1,407 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"github.com/initializ/forge/forge-core/a2a"
+	"github.com/initializ/forge/forge-core/channels"
+)
+
+func TestInit(t *testing.T) {
+	t.Setenv("DISCORD_BOT_TOKEN", "token-123")
+	p := New()
+	err := p.Init(channels.ChannelConfig{Settings: map[string]string{"bot_token_env": "DISCORD_BOT_TOKEN"}})
+	if err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+	if p.botToken != "token-123" || !p.requireMention {
+		t.Errorf("botToken = %q, requireMention = %v", p.botToken, p.requireMention)
+	}
+
+	err = p.Init(channels.ChannelConfig{Settings: map[string]string{"bot_token": "x", "require_mention": "false"}})
+	if err != nil || p.requireMention {
+		t.Errorf("require_mention false: err %v, requireMention %v", err, p.requireMention)
+	}
+
+	if err := New().Init(channels.ChannelConfig{}); err == nil {
+		t.Error("expected error without bot_token")
+	}
+	if err := New().Init(channels.ChannelConfig{Settings: map[string]string{"bot_token": "x", "require_mention": "sometimes"}}); err == nil {
+		t.Error("expected error for invalid require_mention")
+	}
+}
+
+func TestNormalizeEvent(t *testing.T) {
+	raw := `{
+		"id": "1001",
+		"channel_id": "C1",
+		"guild_id": "G1",
+		"author": {"id": "U1", "username": "alice"},
+		"content": "<@B1> what is the status of order 42?",
+		"mentions": [{"id": "B1", "bot": true}],
+		"attachments": [{"filename": "invoice.pdf", "content_type": "application/pdf", "url": "https://cdn.discordapp.com/invoice.pdf"}],
+		"referenced_message": {"id": "999", "channel_id": "C1", "author": {"id": "B1"}, "content": "Which order?"}
+	}`
+
+	p := New()
+	p.botUserID = "B1"
+	event, err := p.NormalizeEvent([]byte(raw))
+	if err != nil {
+		t.Fatalf("NormalizeEvent() error: %v", err)
+	}
+	if event.Channel != "discord" || event.WorkspaceID != "C1" || event.UserID != "U1" || event.ThreadID != "1001" || event.MessageID != "1001" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Message != "what is the status of order 42?" {
+		t.Errorf("Message = %q, want mention removed", event.Message)
+	}
+	if len(event.Attachments) != 1 || event.Attachments[0].Name != "invoice.pdf" || event.Attachments[0].MimeType != "application/pdf" {
+		t.Errorf("Attachments = %+v", event.Attachments)
+	}
+	if event.Context == nil || event.Context.ReplyToText != "Which order?" {
+		t.Errorf("Context = %+v", event.Context)
+	}
+
+	if _, err := p.NormalizeEvent([]byte(`{"op": 1}`)); err == nil {
+		t.Error("expected error for a non-message payload")
+	}
+}
+
+func TestShouldRespond(t *testing.T) {
+	p := New()
+	p.requireMention = true
+	p.botUserID = "B1"
+
+	mention := []discordUser{{ID: "B1"}}
+	tests := []struct {
+		name string
+		msg  discordMessage
+		want bool
+	}{
+		{"guild mention", discordMessage{GuildID: "G1", Author: discordUser{ID: "U1"}, Mentions: mention}, true},
+		{"guild without mention", discordMessage{GuildID: "G1", Author: discordUser{ID: "U1"}}, false},
+		{"guild mentioning someone else", discordMessage{GuildID: "G1", Author: discordUser{ID: "U1"}, Mentions: []discordUser{{ID: "U2"}}}, false},
+		{"direct message", discordMessage{Author: discordUser{ID: "U1"}}, true},
+		{"bot author", discordMessage{Author: discordUser{ID: "B2", Bot: true}, Mentions: mention}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.shouldRespond(&tt.msg); got != tt.want {
+				t.Errorf("shouldRespond() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	p.requireMention = false
+	if !p.shouldRespond(&discordMessage{GuildID: "G1", Author: discordUser{ID: "U1"}}) {
+		t.Error("with require_mention off, guild messages should be answered")
+	}
+}
+
+// apiRecorder records REST calls made to a fake Discord API.
+type apiRecorder struct {
+	mu    sync.Mutex
+	posts []recordedPost
+}
+
+type recordedPost struct {
+	path string
+	auth string
+	body map[string]any
+}
+
+func (r *apiRecorder) handler(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	var payload map[string]any
+	json.Unmarshal(body, &payload) //nolint:errcheck
+	r.mu.Lock()
+	r.posts = append(r.posts, recordedPost{path: req.URL.Path, auth: req.Header.Get("Authorization"), body: payload})
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{}`)) //nolint:errcheck
+}
+
+// messages returns the recorded posts that sent a message.
+func (r *apiRecorder) messages() []recordedPost {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []recordedPost
+	for _, p := range r.posts {
+		if strings.HasSuffix(p.path, "/messages") {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func TestSendResponse_ChunksLongReplies(t *testing.T) {
+	rec := &apiRecorder{}
+	srv := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer srv.Close()
+
+	p := New()
+	p.botToken = "token-123"
+	p.apiBase = srv.URL
+
+	para := strings.Repeat("word ", 300) // 1500 chars
+	text := para + "\n\n" + para + "\n\n" + para
+	event := &channels.ChannelEvent{WorkspaceID: "C1", ThreadID: "1001"}
+	if err := p.SendResponse(event, &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart(text)}}); err != nil {
+		t.Fatalf("SendResponse() error: %v", err)
+	}
+
+	posts := rec.messages()
+	if len(posts) != 3 {
+		t.Fatalf("sent %d messages, want 3", len(posts))
+	}
+	for i, post := range posts {
+		content, _ := post.body["content"].(string)
+		if len(content) > messageLimit {
+			t.Errorf("message %d has %d chars, over the limit", i, len(content))
+		}
+		if post.path != "/channels/C1/messages" || post.auth != "Bot token-123" {
+			t.Errorf("message %d sent to %s with auth %q", i, post.path, post.auth)
+		}
+		_, isReply := post.body["message_reference"]
+		if isReply != (i == 0) {
+			t.Errorf("message %d message_reference set = %v, want only on the first", i, isReply)
+		}
+	}
+}
+
+func TestSendResponse_ConvertsMarkdown(t *testing.T) {
+	rec := &apiRecorder{}
+	srv := httptest.NewServer(http.HandlerFunc(rec.handler))
+	defer srv.Close()
+
+	p := New()
+	p.apiBase = srv.URL
+	reply := &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart("## Summary\nAll done")}}
+	if err := p.SendResponse(&channels.ChannelEvent{WorkspaceID: "C1"}, reply); err != nil {
+		t.Fatalf("SendResponse() error: %v", err)
+	}
+	posts := rec.messages()
+	if len(posts) != 1 || posts[0].body["content"] != "**Summary**\nAll done" {
+		t.Errorf("posts = %+v", posts)
+	}
+}
+
+func TestSendResponse_RetriesRateLimit(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"retry_after": 0.01}`)) //nolint:errcheck
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	p := New()
+	p.apiBase = srv.URL
+	if err := p.SendResponse(&channels.ChannelEvent{WorkspaceID: "C1"}, &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart("hi")}}); err != nil {
+		t.Fatalf("SendResponse() error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestSendResponse_StopCancelsRateLimitWait(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"retry_after": 10}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := New()
+	p.apiBase = srv.URL
+	time.AfterFunc(50*time.Millisecond, func() { _ = p.Stop() })
+	start := time.Now()
+	err := p.SendResponse(&channels.ChannelEvent{WorkspaceID: "C1"}, &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart("hi")}})
+	if err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("SendResponse() error = %v, want context canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("SendResponse() took %s; Stop should end the wait", elapsed)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+// fakeGateway accepts one gateway connection, says hello, checks the
+// identify, and sends READY followed by the given message events.
+func fakeGateway(t *testing.T, messages ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			t.Errorf("accept: %v", err)
+			return
+		}
+		defer conn.CloseNow() //nolint:errcheck
+		ctx := r.Context()
+
+		wsjson.Write(ctx, conn, map[string]any{"op": opHello, "d": map[string]any{"heartbeat_interval": 45000}}) //nolint:errcheck
+		var identify struct {
+			Op int `json:"op"`
+			D  struct {
+				Token   string `json:"token"`
+				Intents int    `json:"intents"`
+			} `json:"d"`
+		}
+		if err := wsjson.Read(ctx, conn, &identify); err != nil {
+			t.Errorf("reading identify: %v", err)
+			return
+		}
+		if identify.Op != opIdentify || identify.D.Token != "token-123" || identify.D.Intents&(1<<15) == 0 {
+			t.Errorf("identify = %+v", identify)
+		}
+
+		wsjson.Write(ctx, conn, map[string]any{ //nolint:errcheck
+			"op": opDispatch, "s": 1, "t": "READY",
+			"d": map[string]any{"user": map[string]any{"id": "B1", "bot": true}, "session_id": "S1"},
+		})
+		for i, m := range messages {
+			wsjson.Write(ctx, conn, map[string]any{"op": opDispatch, "s": i + 2, "t": "MESSAGE_CREATE", "d": json.RawMessage(m)}) //nolint:errcheck
+		}
+		// Keep the connection open until the client goes away
+		for {
+			if _, _, err := conn.Read(ctx); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestStart_AnswersMentions(t *testing.T) {
+	rec := &apiRecorder{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gateway", fakeGateway(t,
+		`{"id": "1", "channel_id": "C1", "guild_id": "G1", "author": {"id": "U1"}, "content": "no mention here"}`,
+		`{"id": "2", "channel_id": "C1", "guild_id": "G1", "author": {"id": "B2", "bot": true}, "content": "<@B1> bot chatter", "mentions": [{"id": "B1"}]}`,
+		`{"id": "3", "channel_id": "C1", "guild_id": "G1", "author": {"id": "U1"}, "content": "<@B1> hello", "mentions": [{"id": "B1"}]}`,
+	))
+	mux.HandleFunc("/", rec.handler)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := New()
+	if err := p.Init(channels.ChannelConfig{Settings: map[string]string{"bot_token": "token-123"}}); err != nil {
+		t.Fatal(err)
+	}
+	p.apiBase = srv.URL
+	p.gatewayURL = "ws" + strings.TrimPrefix(srv.URL, "http") + "/gateway"
+
+	events := make(chan *channels.ChannelEvent, 3)
+	handler := func(ctx context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+		events <- event
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("hi alice")}}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Start(ctx, handler) }()
+
+	select {
+	case event := <-events:
+		if event.ThreadID != "3" || event.Message != "hello" {
+			t.Errorf("event = %+v, want only the mention, with the mention removed", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(rec.messages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	posts := rec.messages()
+	if len(posts) != 1 || posts[0].body["content"] != "hi alice" || posts[0].path != "/channels/C1/messages" {
+		t.Errorf("replies = %+v", posts)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event %+v", event)
+	default:
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start() = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return after cancel")
+	}
+}
+
+func TestStart_FatalCloseCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		wsjson.Write(r.Context(), conn, map[string]any{"op": opHello, "d": map[string]any{"heartbeat_interval": 45000}}) //nolint:errcheck
+		var identify map[string]any
+		wsjson.Read(r.Context(), conn, &identify)                       //nolint:errcheck
+		conn.Close(websocket.StatusCode(4014), "Disallowed intent(s).") //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := New()
+	p.botToken = "token-123"
+	p.gatewayURL = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Start(context.Background(), func(ctx context.Context, e *channels.ChannelEvent) (*a2a.Message, error) { return nil, nil })
+	}()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "Message Content intent") {
+			t.Errorf("Start() = %v, want a disallowed intents error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start kept reconnecting after a fatal close code")
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *a2a.Message
+		want string
+	}{
+		{"nil message", nil, "(no response)"},
+		{"multiple text", &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart("a"), a2a.NewTextPart("b")}}, "a\nb"},
+		{"no parts", &a2a.Message{}, "(no text response)"},
+		{"text and file", &a2a.Message{Parts: []a2a.Part{
+			a2a.NewTextPart("chart"),
+			a2a.NewFilePart("chart.png", "image/png", []byte{1}),
+		}}, "chart\n[Attachment: chart.png (image/png)]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractText(tt.msg); got != tt.want {
+				t.Errorf("extractText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
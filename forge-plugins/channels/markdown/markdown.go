@@ -4,17 +4,34 @@ package markdown
 import (
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // ToTelegramHTML converts standard markdown to Telegram-compatible HTML.
 func ToTelegramHTML(text string) string {
+	return convertOutsideCodeBlocks(text, convertTelegramBlockLine, func(lang string, codeLines []string) string {
+		code := escapeHTML(strings.Join(codeLines, "\n"))
+		if lang != "" {
+			return `<pre><code class="language-` + lang + `">` + code + "</code></pre>"
+		}
+		return "<pre><code>" + code + "</code></pre>"
+	})
+}
+
+// convertOutsideCodeBlocks applies convertLine to every line outside fenced
+// code blocks and replaces each code block, including one never closed,
+// with formatCode of its language hint and lines. Tables are laid out with
+// aligned columns and also passed to formatCode, with no language, since
+// no target platform renders markdown tables.
+func convertOutsideCodeBlocks(text string, convertLine func(string) string, formatCode func(lang string, codeLines []string) string) string {
 	lines := strings.Split(text, "\n")
 	var result []string
 	inCodeBlock := false
 	var codeLang string
 	var codeLines []string
 
-	for _, line := range lines {
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
 		// Check for fenced code block delimiters
 		if rest, ok := strings.CutPrefix(line, "```"); ok {
 			if !inCodeBlock {
@@ -25,12 +42,7 @@ func ToTelegramHTML(text string) string {
 			}
 			// Closing code block
 			inCodeBlock = false
-			code := escapeHTML(strings.Join(codeLines, "\n"))
-			if codeLang != "" {
-				result = append(result, `<pre><code class="language-`+codeLang+`">`+code+"</code></pre>")
-			} else {
-				result = append(result, "<pre><code>"+code+"</code></pre>")
-			}
+			result = append(result, formatCode(codeLang, codeLines))
 			codeLang = ""
 			codeLines = nil
 			continue
@@ -41,19 +53,19 @@ func ToTelegramHTML(text string) string {
 			continue
 		}
 
+		if n := tableAt(lines, i); n > 0 {
+			result = append(result, formatCode("", renderTable(lines[i:i+n])))
+			i += n - 1
+			continue
+		}
+
 		// Block-level transforms on non-code lines
-		line = convertTelegramBlockLine(line)
-		result = append(result, line)
+		result = append(result, convertLine(line))
 	}
 
 	// If code block was never closed, flush remaining lines as code
 	if inCodeBlock {
-		code := escapeHTML(strings.Join(codeLines, "\n"))
-		if codeLang != "" {
-			result = append(result, `<pre><code class="language-`+codeLang+`">`+code+"</code></pre>")
-		} else {
-			result = append(result, "<pre><code>"+code+"</code></pre>")
-		}
+		result = append(result, formatCode(codeLang, codeLines))
 	}
 
 	return strings.Join(result, "\n")
@@ -109,42 +121,10 @@ func applyTelegramInline(line string) string {
 
 // ToSlackMrkdwn converts standard markdown to Slack mrkdwn format.
 func ToSlackMrkdwn(text string) string {
-	lines := strings.Split(text, "\n")
-	var result []string
-	inCodeBlock := false
-	var codeLines []string
-
-	for _, line := range lines {
-		// Check for fenced code block delimiters
-		if _, ok := strings.CutPrefix(line, "```"); ok {
-			if !inCodeBlock {
-				inCodeBlock = true
-				codeLines = nil
-				continue
-			}
-			// Closing code block — strip language hint
-			inCodeBlock = false
-			result = append(result, "```\n"+strings.Join(codeLines, "\n")+"\n```")
-			codeLines = nil
-			continue
-		}
-
-		if inCodeBlock {
-			codeLines = append(codeLines, line)
-			continue
-		}
-
-		// Block-level transforms on non-code lines
-		line = convertSlackBlockLine(line)
-		result = append(result, line)
-	}
-
-	// If code block was never closed, flush remaining lines
-	if inCodeBlock {
-		result = append(result, "```\n"+strings.Join(codeLines, "\n")+"\n```")
-	}
-
-	return strings.Join(result, "\n")
+	// Slack does not support language hints, so they are dropped
+	return convertOutsideCodeBlocks(text, convertSlackBlockLine, func(_ string, codeLines []string) string {
+		return "```\n" + strings.Join(codeLines, "\n") + "\n```"
+	})
 }
 
 // convertSlackBlockLine handles block-level elements and inline transforms for a single line.
@@ -195,9 +175,40 @@ func applySlackInline(line string) string {
 	return line
 }
 
+// ToDiscordMarkdown converts standard markdown to Discord-flavored markdown.
+// Discord renders most of CommonMark itself, so the only conversion is
+// headers, which become bold lines. __text__ is left alone: it is more often
+// a Python dunder name than bold.
+func ToDiscordMarkdown(text string) string {
+	return convertOutsideCodeBlocks(text, convertDiscordBlockLine, func(lang string, codeLines []string) string {
+		return "```" + lang + "\n" + strings.Join(codeLines, "\n") + "\n```"
+	})
+}
+
+// convertDiscordBlockLine handles block-level elements and inline transforms for a single line.
+func convertDiscordBlockLine(line string) string {
+	// Headers: # Header → **Header** (bold markers inside are dropped so
+	// they do not close the header's own)
+	if m := headerRe.FindStringSubmatch(line); m != nil {
+		return "**" + strings.ReplaceAll(m[2], "**", "") + "**"
+	}
+
+	// Blockquotes, lists, emphasis, strikethrough and links are native
+	return line
+}
+
 // SplitMessage splits a long message into chunks that fit within limit.
-// It splits at paragraph boundaries first, then newlines, then hard-splits.
+// It splits at paragraph boundaries first, then newlines, then hard-splits,
+// never inside a fenced code block: a block longer than limit is closed at
+// the split and reopened, with its language, at the start of the next
+// chunk, so every chunk renders on its own.
 func SplitMessage(text string, limit int) []string {
+	return SplitMessageWith(text, SplitOptions{Limit: limit, PreserveCodeBlocks: true})
+}
+
+// splitPlain splits text by length alone, at paragraph boundaries first,
+// then newlines, then hard-splits.
+func splitPlain(text string, limit int) []string {
 	if len(text) <= limit {
 		return []string{text}
 	}
@@ -228,13 +239,168 @@ func SplitMessage(text string, limit int) []string {
 		}
 
 		// Hard split at limit
-		chunks = append(chunks, chunk)
-		remaining = remaining[limit:]
+		cut := runeBoundary(remaining, limit)
+		chunks = append(chunks, remaining[:cut])
+		remaining = remaining[cut:]
 	}
 
 	return chunks
 }
 
+// SplitOptions configures SplitMessageWith.
+type SplitOptions struct {
+	// Limit is the maximum length of each chunk.
+	Limit int
+	// PreserveCodeBlocks avoids splitting inside fenced code blocks. A code
+	// block longer than Limit is split at line boundaries and each piece is
+	// closed and re-opened with the original fence so every chunk renders.
+	// When false, text is split by length alone.
+	PreserveCodeBlocks bool
+}
+
+// codeFenceCloser is appended to a chunk that ends inside a code block.
+const codeFenceCloser = "\n```"
+
+// SplitMessageWith splits text into chunks according to opts.
+func SplitMessageWith(text string, opts SplitOptions) []string {
+	if !opts.PreserveCodeBlocks {
+		return splitPlain(text, opts.Limit)
+	}
+	limit := opts.Limit
+	if len(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	remaining := text
+
+	for len(remaining) > limit {
+		fences := fenceSpans(remaining)
+		window := remaining[:limit]
+
+		// Prefer a paragraph, then a line boundary outside any code block
+		if idx := lastBreakOutsideFences(window, "\n\n", fences); idx > 0 {
+			chunks = append(chunks, remaining[:idx])
+			remaining = remaining[idx+2:]
+			continue
+		}
+		if idx := lastBreakOutsideFences(window, "\n", fences); idx > 0 {
+			chunks = append(chunks, remaining[:idx])
+			remaining = remaining[idx+1:]
+			continue
+		}
+
+		// Nothing but blank lines precedes a code block in the window:
+		// drop them so the next pass starts at the block.
+		if len(fences) > 0 && fences[0].start > 0 && fences[0].start < limit &&
+			strings.TrimLeft(remaining[:fences[0].start], "\n") == "" {
+			remaining = remaining[fences[0].start:]
+			continue
+		}
+
+		// The chunk starts with a code block larger than limit: split it
+		// inside, closing the fence and re-opening it on the next chunk.
+		if len(fences) > 0 && fences[0].start == 0 {
+			opener := remaining[:strings.IndexByte(remaining+"\n", '\n')]
+			budget := limit - len(codeFenceCloser)
+			if budget > len(opener)+1 {
+				cut := strings.LastIndex(remaining[:budget], "\n")
+				skip := 1
+				if cut <= len(opener) {
+					cut, skip = runeBoundary(remaining, budget), 0
+				}
+				if cut > len(opener) {
+					chunks = append(chunks, remaining[:cut]+codeFenceCloser)
+					remaining = opener + "\n" + remaining[cut+skip:]
+					continue
+				}
+			}
+		}
+
+		// Hard split at limit
+		cut := runeBoundary(remaining, limit)
+		chunks = append(chunks, remaining[:cut])
+		remaining = remaining[cut:]
+	}
+	if len(remaining) > 0 {
+		chunks = append(chunks, remaining)
+	}
+
+	return chunks
+}
+
+// runeBoundary moves the byte offset i in s back to the start of the UTF-8
+// rune containing it, so a split at i never cuts a rune in half. If the
+// first rune alone is longer than i, it returns the end of that rune.
+func runeBoundary(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	if i == 0 {
+		_, size := utf8.DecodeRuneInString(s)
+		return size
+	}
+	return i
+}
+
+// fenceSpan is the byte range of a fenced code block, from the start of its
+// opening fence line to the end of its closing fence line.
+type fenceSpan struct {
+	start, end int
+}
+
+// fenceSpans locates fenced code blocks in text. An unclosed block extends
+// to the end of text.
+func fenceSpans(text string) []fenceSpan {
+	var spans []fenceSpan
+	open := -1
+	pos := 0
+	for pos <= len(text) {
+		lineEnd := strings.IndexByte(text[pos:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(text)
+		} else {
+			lineEnd += pos
+		}
+		if strings.HasPrefix(text[pos:lineEnd], "```") {
+			if open < 0 {
+				open = pos
+			} else {
+				spans = append(spans, fenceSpan{start: open, end: lineEnd})
+				open = -1
+			}
+		}
+		pos = lineEnd + 1
+	}
+	if open >= 0 {
+		spans = append(spans, fenceSpan{start: open, end: len(text)})
+	}
+	return spans
+}
+
+// lastBreakOutsideFences returns the index of the last occurrence of sep in
+// window that does not fall inside a code block, or -1.
+func lastBreakOutsideFences(window, sep string, fences []fenceSpan) int {
+	end := len(window)
+	for {
+		idx := strings.LastIndex(window[:end], sep)
+		if idx <= 0 {
+			return -1
+		}
+		inside := false
+		for _, f := range fences {
+			if idx > f.start && idx < f.end {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return idx
+		}
+		end = idx
+	}
+}
+
 // escapeHTML escapes special HTML characters.
 func escapeHTML(s string) string {
 	s = strings.ReplaceAll(s, "&", "&amp;")
@@ -1,8 +1,10 @@
 package markdown
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestToTelegramHTML_Bold(t *testing.T) {
@@ -228,6 +230,145 @@ func TestToSlackMrkdwn_MixedContent(t *testing.T) {
 	}
 }
 
+// --- Discord markdown tests ---
+
+func TestToDiscordMarkdown_BoldAndItalic(t *testing.T) {
+	input := "**bold** and *italic*"
+	if got := ToDiscordMarkdown(input); got != input {
+		t.Errorf("got %q, want it unchanged", got)
+	}
+}
+
+func TestToDiscordMarkdown_InlineCode(t *testing.T) {
+	// Dunder names stay as written, in or out of inline code
+	input := "call `__init__` from __main__"
+	if got := ToDiscordMarkdown(input); got != input {
+		t.Errorf("got %q, want it unchanged", got)
+	}
+}
+
+func TestToDiscordMarkdown_FencedCodeBlock(t *testing.T) {
+	input := "```python\nprint('hello')\n```"
+	got := ToDiscordMarkdown(input)
+	if got != input {
+		t.Errorf("got %q, want %q", got, input)
+	}
+}
+
+func TestToDiscordMarkdown_UnclosedCodeBlock(t *testing.T) {
+	got := ToDiscordMarkdown("```go\nfunc main() {}")
+	want := "```go\nfunc main() {}\n```"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestToDiscordMarkdown_Headers(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"# Title", "**Title**"},
+		{"## Subtitle", "**Subtitle**"},
+		{"### **Already** bold", "**Already bold**"},
+	}
+	for _, tt := range tests {
+		got := ToDiscordMarkdown(tt.input)
+		if got != tt.want {
+			t.Errorf("ToDiscordMarkdown(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestToDiscordMarkdown_NativeSyntax(t *testing.T) {
+	tests := []string{
+		"click [here](https://example.com)",
+		"> this is a quote",
+		"this is ~~deleted~~ text",
+		"- first\n- second\n* third",
+	}
+	for _, input := range tests {
+		if got := ToDiscordMarkdown(input); got != input {
+			t.Errorf("ToDiscordMarkdown(%q) = %q, want it unchanged", input, got)
+		}
+	}
+}
+
+func TestToDiscordMarkdown_NoTransformInsideCodeBlock(t *testing.T) {
+	input := "```\n# not a header and __not bold__\n```"
+	got := ToDiscordMarkdown(input)
+	if got != input {
+		t.Errorf("code block contents should not be transformed: %q", got)
+	}
+}
+
+// --- Table tests ---
+
+const sampleTable = `Deploy status:
+
+| Service | Region | Latency |
+|---------|:------:|--------:|
+| api | us-east-1 | 12ms |
+| **worker** | eu | 7ms |
+| scheduler | ap-south-1 |
+
+Done.`
+
+func TestToTelegramHTML_Table(t *testing.T) {
+	got := ToTelegramHTML(sampleTable)
+	want := "Deploy status:\n\n<pre><code>" +
+		"Service   |   Region   | Latency\n" +
+		"----------+------------+--------\n" +
+		"api       | us-east-1  |    12ms\n" +
+		"worker    |     eu     |     7ms\n" +
+		"scheduler | ap-south-1 |" +
+		"</code></pre>\n\nDone."
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToSlackMrkdwn_Table(t *testing.T) {
+	got := ToSlackMrkdwn("| a | b | c |\n|---|---|---|\n| 1 | 22 | 333 |\n| 4444 | 5 | 6 | 7 |")
+	want := "```\n" +
+		"a    | b  | c   |\n" +
+		"-----+----+-----+--\n" +
+		"1    | 22 | 333 |\n" +
+		"4444 | 5  | 6   | 7\n" +
+		"```"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToTelegramHTML_TableEscapesCells(t *testing.T) {
+	got := ToTelegramHTML("| op | meaning |\n|---|---|\n| `a<b` | a \\| b |")
+	if !strings.Contains(got, "a&lt;b | a | b") {
+		t.Errorf("cells not escaped or pipe not unescaped: %q", got)
+	}
+}
+
+func TestToSlackMrkdwn_StrayPipeIsNotATable(t *testing.T) {
+	inputs := []string{
+		"Run ls | grep foo to filter.",
+		"a | b\nnot a separator",
+		"| a | b |\n|---|\n| 1 | 2 |",
+		"Use a pipe | here\n---",
+	}
+	for _, input := range inputs {
+		if got := ToSlackMrkdwn(input); strings.Contains(got, "```") {
+			t.Errorf("ToSlackMrkdwn(%q) rendered a table: %q", input, got)
+		}
+	}
+}
+
+func TestToSlackMrkdwn_TableNotTransformedInsideCodeBlock(t *testing.T) {
+	input := "```\n| a | b |\n|---|---|\n```"
+	if got := ToSlackMrkdwn(input); got != input {
+		t.Errorf("got %q, want the code block unchanged", got)
+	}
+}
+
 // --- SplitMessage tests ---
 
 func TestSplitMessage_Short(t *testing.T) {
@@ -278,6 +419,40 @@ func TestSplitMessage_HardSplit(t *testing.T) {
 	}
 }
 
+func TestSplitMessage_HardSplitMultiByte(t *testing.T) {
+	text := strings.Repeat("héllo 日本語 👋 ", 5)
+	for _, preserve := range []bool{false, true} {
+		chunks := SplitMessageWith(text, SplitOptions{Limit: 10, PreserveCodeBlocks: preserve})
+		for _, c := range chunks {
+			if !utf8.ValidString(c) {
+				t.Errorf("preserve=%v: chunk is not valid UTF-8: %q", preserve, c)
+			}
+			if len(c) > 10 {
+				t.Errorf("preserve=%v: chunk exceeds limit: %q (%d bytes)", preserve, c, len(c))
+			}
+		}
+		if got := strings.Join(chunks, ""); got != text {
+			t.Errorf("preserve=%v: chunks rejoin to %q, want %q", preserve, got, text)
+		}
+	}
+}
+
+func TestSplitMessageWith_OversizedCodeBlockMultiByte(t *testing.T) {
+	text := "```\n" + strings.Repeat("日本語", 20) + "\n```"
+	chunks := SplitMessageWith(text, SplitOptions{Limit: 20, PreserveCodeBlocks: true})
+	if len(chunks) < 2 {
+		t.Fatalf("expected the code block to be split, got %v", chunks)
+	}
+	for _, c := range chunks {
+		if !utf8.ValidString(c) {
+			t.Errorf("chunk is not valid UTF-8: %q", c)
+		}
+		if len(c) > 20 {
+			t.Errorf("chunk exceeds limit: %q (%d bytes)", c, len(c))
+		}
+	}
+}
+
 func TestSplitMessage_ExactlyAtLimit(t *testing.T) {
 	text := strings.Repeat("x", 100)
 	chunks := SplitMessage(text, 100)
@@ -286,6 +461,107 @@ func TestSplitMessage_ExactlyAtLimit(t *testing.T) {
 	}
 }
 
+// fenceLines counts the lines of chunk that open or close a code block.
+func fenceLines(chunk string) int {
+	n := 0
+	for _, line := range strings.Split(chunk, "\n") {
+		if strings.HasPrefix(line, "```") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSplitMessage_LongCodeBlock(t *testing.T) {
+	var code []string
+	for i := 0; i < 60; i++ {
+		code = append(code, fmt.Sprintf("fmt.Println(%02d) // step", i))
+	}
+	text := "Here is the program:\n\n```go\n" + strings.Join(code, "\n") + "\n```\n\nRun it with go run."
+
+	chunks := SplitMessage(text, 300)
+	if len(chunks) < 3 {
+		t.Fatalf("expected the code block to span several chunks, got %d", len(chunks))
+	}
+	var joined []string
+	for i, c := range chunks {
+		if len(c) > 300 {
+			t.Errorf("chunk %d exceeds limit: %d chars", i, len(c))
+		}
+		if fenceLines(c)%2 != 0 {
+			t.Errorf("chunk %d has unbalanced fences:\n%s", i, c)
+		}
+		if strings.Contains(c, "fmt.Println") && !strings.Contains(c, "```go\n") {
+			t.Errorf("chunk %d does not reopen the block with its language:\n%s", i, c)
+		}
+		joined = append(joined, c)
+	}
+	all := strings.Join(joined, "\n")
+	for _, line := range code {
+		if strings.Count(all, line) != 1 {
+			t.Errorf("code line %q appears %d times across chunks", line, strings.Count(all, line))
+		}
+	}
+	if !strings.HasPrefix(chunks[0], "Here is the program:") || !strings.HasSuffix(chunks[len(chunks)-1], "Run it with go run.") {
+		t.Errorf("surrounding text not kept: first %q, last %q", chunks[0], chunks[len(chunks)-1])
+	}
+}
+
+func TestSplitMessage_CodeBlockAfterBlankLine(t *testing.T) {
+	text := "\n```python\n" + strings.Repeat("print('hello world')\n", 10) + "```"
+	for i, c := range SplitMessage(text, 80) {
+		if len(c) > 80 || fenceLines(c)%2 != 0 {
+			t.Errorf("chunk %d is not a complete block within the limit: %q", i, c)
+		}
+	}
+}
+
+func TestSplitMessageWith_KeepsCodeBlockIntact(t *testing.T) {
+	text := "intro paragraph\n\n```go\nfunc a() {}\n\nfunc b() {}\n```\n\noutro"
+	chunks := SplitMessageWith(text, SplitOptions{Limit: 40, PreserveCodeBlocks: true})
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %q", len(chunks), chunks)
+	}
+	if chunks[1] != "```go\nfunc a() {}\n\nfunc b() {}\n```" {
+		t.Errorf("code block was split: %q", chunks[1])
+	}
+}
+
+func TestSplitMessageWith_OversizedCodeBlock(t *testing.T) {
+	var lines []string
+	for i := 0; i < 40; i++ {
+		lines = append(lines, fmt.Sprintf("line %02d of code", i))
+	}
+	text := "```python\n" + strings.Join(lines, "\n") + "\n```"
+	chunks := SplitMessageWith(text, SplitOptions{Limit: 120, PreserveCodeBlocks: true})
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(chunks))
+	}
+	var joined []string
+	for i, c := range chunks {
+		if len(c) > 120 {
+			t.Errorf("chunk %d exceeds limit: %d chars", i, len(c))
+		}
+		if !strings.HasPrefix(c, "```python\n") || !strings.HasSuffix(c, "\n```") {
+			t.Errorf("chunk %d is not a complete code block: %q", i, c)
+		}
+		body := strings.TrimSuffix(strings.TrimPrefix(c, "```python\n"), "\n```")
+		joined = append(joined, body)
+	}
+	if got := strings.Join(joined, "\n"); got != strings.Join(lines, "\n") {
+		t.Errorf("code content not preserved across chunks:\n%s", got)
+	}
+}
+
+func TestSplitMessageWith_WithoutPreserve(t *testing.T) {
+	text := "aaaa\nbbbb\ncccc"
+	got := SplitMessageWith(text, SplitOptions{Limit: 10})
+	want := SplitMessage(text, 10)
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
 // --- Real LLM output test ---
 
 func TestToTelegramHTML_RealLLMOutput(t *testing.T) {
@@ -356,3 +632,42 @@ For more info, visit [OpenWeather](https://openweathermap.org).`
 		}
 	}
 }
+
+func TestToDiscordMarkdown_RealLLMOutput(t *testing.T) {
+	input := `# Weather Report
+
+**Current conditions** in *San Francisco*:
+
+- Temperature: 65°F
+- Humidity: 72%
+
+> Note: data from OpenWeather API
+
+For more info, visit [OpenWeather](https://openweathermap.org).
+
+` + "```json\n{\"temp\": 65, \"humidity\": 72}\n```"
+
+	got := ToDiscordMarkdown(input)
+
+	checks := []struct {
+		desc     string
+		contains string
+	}{
+		{"header converted", "**Weather Report**"},
+		{"bold kept", "**Current conditions**"},
+		{"italic kept", "*San Francisco*"},
+		{"bullet list", "- Temperature: 65°F"},
+		{"blockquote", "> Note: data from OpenWeather API"},
+		{"link kept", "[OpenWeather](https://openweathermap.org)"},
+		{"code block", "```json\n{\"temp\": 65, \"humidity\": 72}\n```"},
+	}
+
+	for _, c := range checks {
+		if !strings.Contains(got, c.contains) {
+			t.Errorf("%s: expected %q in output:\n%s", c.desc, c.contains, got)
+		}
+	}
+	if strings.Contains(got, "# Weather") {
+		t.Errorf("header marker left in output:\n%s", got)
+	}
+}
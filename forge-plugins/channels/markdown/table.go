@@ -0,0 +1,133 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// tableSeparatorCellRe matches one cell of a table's header separator row,
+// such as "---", ":---", "---:" or ":---:".
+var tableSeparatorCellRe = regexp.MustCompile(`^:?-+:?$`)
+
+type columnAlign int
+
+const (
+	alignLeft columnAlign = iota
+	alignRight
+	alignCenter
+)
+
+// tableAt reports how many lines starting at lines[i] form a markdown table:
+// a header row, a separator row with as many cells, and every following row
+// that contains a pipe. It returns 0 when no table starts there, so a line
+// with a stray pipe is left alone.
+func tableAt(lines []string, i int) int {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || !strings.Contains(lines[i+1], "|") {
+		return 0
+	}
+	sep := splitTableRow(lines[i+1])
+	if len(sep) != len(splitTableRow(lines[i])) {
+		return 0
+	}
+	for _, cell := range sep {
+		if !tableSeparatorCellRe.MatchString(cell) {
+			return 0
+		}
+	}
+	n := 2
+	for i+n < len(lines) && strings.Contains(lines[i+n], "|") && strings.TrimSpace(lines[i+n]) != "" {
+		n++
+	}
+	return n
+}
+
+// splitTableRow splits a table row into trimmed cells, dropping the
+// optional leading and trailing pipes. An escaped pipe (\|) stays in its
+// cell.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+	line = strings.ReplaceAll(line, `\|`, "\x00")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.ReplaceAll(strings.TrimSpace(cell), "\x00", "|")
+	}
+	return cells
+}
+
+// renderTable lays out a markdown table as plain text with padded,
+// aligned columns, for display in a monospace block. Inline code and bold
+// markers are removed from cells because the block shows them verbatim.
+// Rows with fewer cells than the header are padded; longer rows widen the
+// table.
+func renderTable(lines []string) []string {
+	var aligns []columnAlign
+	for _, cell := range splitTableRow(lines[1]) {
+		switch {
+		case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+			aligns = append(aligns, alignCenter)
+		case strings.HasSuffix(cell, ":"):
+			aligns = append(aligns, alignRight)
+		default:
+			aligns = append(aligns, alignLeft)
+		}
+	}
+
+	rows := [][]string{splitTableRow(lines[0])}
+	for _, line := range lines[2:] {
+		rows = append(rows, splitTableRow(line))
+	}
+	var widths []int
+	for _, row := range rows {
+		for c, cell := range row {
+			cell = boldRe.ReplaceAllString(inlineCodeRe.ReplaceAllString(cell, "$1"), "$1")
+			row[c] = cell
+			if c == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[c] = max(widths[c], utf8.RuneCountInString(cell))
+		}
+	}
+
+	out := make([]string, 0, len(rows)+1)
+	for r, row := range rows {
+		cells := make([]string, len(widths))
+		for c, width := range widths {
+			var cell string
+			if c < len(row) {
+				cell = row[c]
+			}
+			align := alignLeft
+			if c < len(aligns) {
+				align = aligns[c]
+			}
+			cells[c] = padCell(cell, width, align)
+		}
+		out = append(out, strings.TrimRight(strings.Join(cells, " | "), " "))
+		if r == 0 {
+			dashes := make([]string, len(widths))
+			for c, width := range widths {
+				dashes[c] = strings.Repeat("-", width)
+			}
+			out = append(out, strings.Join(dashes, "-+-"))
+		}
+	}
+	return out
+}
+
+// padCell pads cell with spaces to width runes according to align.
+func padCell(cell string, width int, align columnAlign) string {
+	pad := width - utf8.RuneCountInString(cell)
+	switch align {
+	case alignRight:
+		return strings.Repeat(" ", pad) + cell
+	case alignCenter:
+		return strings.Repeat(" ", pad/2) + cell + strings.Repeat(" ", pad-pad/2)
+	default:
+		return cell + strings.Repeat(" ", pad)
+	}
+}
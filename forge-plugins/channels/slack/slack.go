@@ -13,6 +13,7 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
@@ -26,6 +27,15 @@ const (
 	replayWindowSec    = 300 // 5 minutes
 )
 
+// Slack message size limits. Section block text is capped at 3000 chars and
+// a message may carry at most 50 blocks; the fallback text is kept short so
+// notifications stay readable.
+const (
+	defaultMessageLimit   = 4000
+	defaultBlockTextLimit = 3000
+	defaultMaxBlocks      = 50
+)
+
 const slackAPIBase = "https://slack.com/api"
 
 // Plugin implements channels.ChannelPlugin for Slack.
@@ -37,18 +47,36 @@ type Plugin struct {
 	srv           *http.Server
 	client        *http.Client
 	apiBase       string // overridable for tests
+
+	messageLimit   int  // max mrkdwn chars per post
+	blockTextLimit int  // max chars per section block
+	maxBlocks      int  // max blocks per post
+	ephemeral      bool // send every reply with chat.postEphemeral
+	threadReplies  bool // reply in a thread under the triggering message
+
+	feedback channels.FeedbackHandler // receives reactions to replies; nil ignores them
+	replies  *channels.ReplyIndex     // task IDs of sent replies, keyed by ts
 }
 
 // New creates an uninitialised Slack plugin.
 func New() *Plugin {
 	return &Plugin{
-		client:  &http.Client{Timeout: 30 * time.Second},
-		apiBase: slackAPIBase,
+		client:         &http.Client{Timeout: 30 * time.Second},
+		apiBase:        slackAPIBase,
+		messageLimit:   defaultMessageLimit,
+		blockTextLimit: defaultBlockTextLimit,
+		maxBlocks:      defaultMaxBlocks,
+		threadReplies:  true,
+		replies:        channels.NewReplyIndex(0),
 	}
 }
 
 func (p *Plugin) Name() string { return "slack" }
 
+// SetFeedbackHandler implements channels.FeedbackPlugin. Thumbs-up and
+// thumbs-down reactions to the bot's replies are passed to h.
+func (p *Plugin) SetFeedbackHandler(h channels.FeedbackHandler) { p.feedback = h }
+
 func (p *Plugin) Init(cfg channels.ChannelConfig) error {
 	settings := channels.ResolveEnvVars(&cfg)
 
@@ -70,9 +98,44 @@ func (p *Plugin) Init(cfg channels.ChannelConfig) error {
 		p.webhookPath = defaultWebhookPath
 	}
 
+	var err error
+	if p.messageLimit, err = intSetting(settings, "message_limit", defaultMessageLimit); err != nil {
+		return err
+	}
+	if p.blockTextLimit, err = intSetting(settings, "block_text_limit", defaultBlockTextLimit); err != nil {
+		return err
+	}
+	if p.maxBlocks, err = intSetting(settings, "max_blocks", defaultMaxBlocks); err != nil {
+		return err
+	}
+	if v := settings["ephemeral"]; v != "" {
+		if p.ephemeral, err = strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("slack: ephemeral must be true or false, got %q", v)
+		}
+	}
+	p.threadReplies = true
+	if v := settings["thread_replies"]; v != "" {
+		if p.threadReplies, err = strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("slack: thread_replies must be true or false, got %q", v)
+		}
+	}
+
 	return nil
 }
 
+// intSetting parses a positive integer setting, returning def when unset.
+func intSetting(settings map[string]string, key string, def int) (int, error) {
+	v := settings[key]
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("slack: %s must be a positive integer, got %q", key, v)
+	}
+	return n, nil
+}
+
 func (p *Plugin) Start(ctx context.Context, handler channels.EventHandler) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc(p.webhookPath, p.makeWebhookHandler(handler))
@@ -151,6 +214,13 @@ func (p *Plugin) makeWebhookHandler(handler channels.EventHandler) http.HandlerF
 			return
 		}
 
+		// Reactions are feedback on earlier replies, not agent input
+		if payload.Event.Type == "reaction_added" {
+			w.WriteHeader(http.StatusOK)
+			p.dispatchFeedback(body)
+			return
+		}
+
 		// Skip bot messages
 		if payload.Event.BotID != "" {
 			w.WriteHeader(http.StatusOK)
@@ -182,7 +252,11 @@ func (p *Plugin) makeWebhookHandler(handler channels.EventHandler) http.HandlerF
 	}
 }
 
-// NormalizeEvent parses raw Slack event JSON into a ChannelEvent.
+// NormalizeEvent parses raw Slack event JSON into a ChannelEvent. The
+// event's ThreadID is the thread the reply goes to: the message's own
+// thread, or else the message itself, so the reply starts a thread under
+// it. With thread_replies off, messages outside a thread get no ThreadID
+// and are answered in the channel.
 func (p *Plugin) NormalizeEvent(raw []byte) (*channels.ChannelEvent, error) {
 	var payload slackEventPayload
 	if err := json.Unmarshal(raw, &payload); err != nil {
@@ -190,69 +264,174 @@ func (p *Plugin) NormalizeEvent(raw []byte) (*channels.ChannelEvent, error) {
 	}
 
 	threadID := payload.Event.ThreadTS
-	if threadID == "" {
+	if threadID == "" && p.threadReplies {
 		threadID = payload.Event.TS
 	}
 
+	// client_msg_id is shared by the message and app_mention events of one
+	// message and kept across retries; event_id covers the rest.
+	messageID := payload.Event.ClientMsgID
+	if messageID == "" {
+		messageID = payload.EventID
+	}
+
 	return &channels.ChannelEvent{
 		Channel:     "slack",
 		WorkspaceID: payload.Event.Channel,
 		UserID:      payload.Event.User,
 		ThreadID:    threadID,
+		MessageID:   messageID,
 		Message:     payload.Event.Text,
 		Raw:         raw,
 	}, nil
 }
 
-// SendResponse posts a message back to Slack via chat.postMessage.
+// NormalizeReaction parses a raw Slack reaction_added event into a
+// FeedbackEvent, linking it to the task that produced the reacted-to reply
+// when that reply was sent by this plugin.
+func (p *Plugin) NormalizeReaction(raw []byte) (*channels.FeedbackEvent, error) {
+	var payload slackEventPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("parsing slack event: %w", err)
+	}
+	ev := payload.Event
+	if ev.Type != "reaction_added" {
+		return nil, fmt.Errorf("slack event %q is not a reaction", ev.Type)
+	}
+
+	return &channels.FeedbackEvent{
+		Channel:     "slack",
+		WorkspaceID: ev.Item.Channel,
+		UserID:      ev.User,
+		MessageID:   ev.Item.TS,
+		TaskID:      p.replies.TaskID(ev.Item.Channel, ev.Item.TS),
+		Reaction:    ev.Reaction,
+		Rating:      channels.ReactionRating(ev.Reaction),
+	}, nil
+}
+
+// dispatchFeedback passes a rated reaction on a known reply to the feedback
+// handler. Other reactions are ignored.
+func (p *Plugin) dispatchFeedback(raw []byte) {
+	if p.feedback == nil {
+		return
+	}
+	fb, err := p.NormalizeReaction(raw)
+	if err != nil || fb.TaskID == "" || fb.Rating == 0 {
+		return
+	}
+	go func() {
+		if err := p.feedback(context.Background(), fb); err != nil {
+			fmt.Printf("slack: feedback handler error: %v\n", err)
+		}
+	}()
+}
+
+// SendResponse posts a message back to Slack via chat.postMessage. Long
+// responses are split into multiple posts that respect the configured text
+// and block limits without breaking fenced code blocks; every post goes to
+// the event's thread, if it has one. Replies are sent with
+// chat.postEphemeral, visible only to the requesting user, when the
+// ephemeral setting is on or the response is marked ephemeral.
 func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Message) error {
 	text := extractText(response)
 	mrkdwn := markdown.ToSlackMrkdwn(text)
-	chunks := markdown.SplitMessage(mrkdwn, 4000)
-
-	for i, chunk := range chunks {
+	ephemeral := p.ephemeral || channels.IsEphemeral(response)
+
+	for _, post := range p.splitPosts(mrkdwn) {
+		blocks := make([]map[string]any, len(post))
+		for j, b := range post {
+			blocks[j] = map[string]any{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": b},
+			}
+		}
 		payload := map[string]any{
 			"channel": event.WorkspaceID,
-			"text":    chunk,
+			"text":    strings.Join(post, "\n\n"),
+			"blocks":  blocks,
 			"mrkdwn":  true,
 		}
-		if i == 0 {
+		if event.ThreadID != "" {
 			payload["thread_ts"] = event.ThreadID
 		}
-		if err := p.postMessage(payload); err != nil {
+		method := "chat.postMessage"
+		if ephemeral {
+			method = "chat.postEphemeral"
+			payload["user"] = event.UserID
+		}
+		ts, err := p.postMessage(method, payload)
+		if err != nil {
 			return err
 		}
+		p.replies.Add(event.WorkspaceID, ts, event.TaskID)
 	}
 	return nil
 }
 
-// postMessage posts a JSON payload to the Slack chat.postMessage API.
-func (p *Plugin) postMessage(payload map[string]any) error {
+// splitPosts splits mrkdwn into section-block texts and groups them into
+// posts, each holding at most maxBlocks blocks and messageLimit characters.
+func (p *Plugin) splitPosts(mrkdwn string) [][]string {
+	blockLimit := min(p.blockTextLimit, p.messageLimit)
+	blocks := markdown.SplitMessageWith(mrkdwn, markdown.SplitOptions{
+		Limit:              blockLimit,
+		PreserveCodeBlocks: true,
+	})
+
+	var posts [][]string
+	var cur []string
+	curLen := 0
+	for _, b := range blocks {
+		size := len(b)
+		if len(cur) > 0 {
+			size += 2 // "\n\n" separator in the fallback text
+		}
+		if len(cur) > 0 && (len(cur) >= p.maxBlocks || curLen+size > p.messageLimit) {
+			posts = append(posts, cur)
+			cur, curLen, size = nil, 0, len(b)
+		}
+		cur = append(cur, b)
+		curLen += size
+	}
+	if len(cur) > 0 {
+		posts = append(posts, cur)
+	}
+	return posts
+}
+
+// postMessage posts a JSON payload to a Slack messaging API method such as
+// chat.postMessage or chat.postEphemeral and returns the posted message's
+// ts, or "" if the response does not include it.
+func (p *Plugin) postMessage(method string, payload map[string]any) (string, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshalling slack response: %w", err)
+		return "", fmt.Errorf("marshalling slack response: %w", err)
 	}
 
-	url := p.apiBase + "/chat.postMessage"
+	url := p.apiBase + "/" + method
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("creating slack request: %w", err)
+		return "", fmt.Errorf("creating slack request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+p.botToken)
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("posting to slack: %w", err)
+		return "", fmt.Errorf("posting to slack: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("slack API error %d: %s", resp.StatusCode, string(respBody))
+		return "", fmt.Errorf("slack API error %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	return nil
+	var result struct {
+		TS string `json:"ts"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result) //nolint:errcheck
+	return result.TS, nil
 }
 
 // verifySlackSignature validates the X-Slack-Signature header using HMAC-SHA256.
@@ -268,19 +447,25 @@ func verifySlackSignature(signingSecret, timestamp string, body []byte, signatur
 	return hmac.Equal([]byte(expected), []byte(signature))
 }
 
-// extractText concatenates all text parts from an A2A message.
+// extractText concatenates all text parts from an A2A message. File and
+// data parts, which are not uploaded, are mentioned on their own lines.
 func extractText(msg *a2a.Message) string {
 	if msg == nil {
 		return "(no response)"
 	}
 	var text string
 	for _, p := range msg.Parts {
-		if p.Kind == a2a.PartKindText {
-			if text != "" {
-				text += "\n"
-			}
-			text += p.Text
+		line := p.Text
+		if p.Kind != a2a.PartKindText {
+			line = channels.PartNote(p)
+		}
+		if line == "" {
+			continue
 		}
+		if text != "" {
+			text += "\n"
+		}
+		text += line
 	}
 	if text == "" {
 		text = "(no text response)"
@@ -290,17 +475,28 @@ func extractText(msg *a2a.Message) string {
 
 // slackEventPayload represents the outer Slack event callback structure.
 type slackEventPayload struct {
-	TeamID string     `json:"team_id"`
-	Event  slackEvent `json:"event"`
+	TeamID  string     `json:"team_id"`
+	EventID string     `json:"event_id"`
+	Event   slackEvent `json:"event"`
 }
 
 // slackEvent represents the inner event fields we care about.
 type slackEvent struct {
-	Type     string `json:"type"`
-	Channel  string `json:"channel"`
-	User     string `json:"user"`
-	Text     string `json:"text"`
-	TS       string `json:"ts"`
-	ThreadTS string `json:"thread_ts"`
-	BotID    string `json:"bot_id"`
+	Type        string            `json:"type"`
+	Channel     string            `json:"channel"`
+	User        string            `json:"user"`
+	Text        string            `json:"text"`
+	TS          string            `json:"ts"`
+	ThreadTS    string            `json:"thread_ts"`
+	ClientMsgID string            `json:"client_msg_id"`
+	BotID       string            `json:"bot_id"`
+	Reaction    string            `json:"reaction"` // reaction_added only
+	Item        slackReactionItem `json:"item"`     // reaction_added only
+}
+
+// slackReactionItem identifies the message a reaction was added to.
+type slackReactionItem struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
 }
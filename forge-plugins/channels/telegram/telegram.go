@@ -4,11 +4,16 @@ package telegram
 import (
 	"bytes"
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/initializ/forge/forge-core/a2a"
@@ -21,18 +26,31 @@ const (
 	defaultWebhookPath = "/telegram/webhook"
 	telegramAPIBase    = "https://api.telegram.org"
 	pollingTimeout     = 30 // seconds for long polling
+
+	// maxCallbackData is Telegram's limit on an inline button's callback_data.
+	maxCallbackData = 64
+
+	// defaultEphemeralTTL is how long replies marked ephemeral stay visible
+	// when delete_after is not set.
+	defaultEphemeralTTL = 60 * time.Second
 )
 
 // Plugin implements channels.ChannelPlugin for Telegram.
 type Plugin struct {
-	botToken    string
-	mode        string // "polling" or "webhook"
-	webhookPort int
-	webhookPath string
-	srv         *http.Server
-	client      *http.Client
-	apiBase     string // overridable for tests
-	stopCh      chan struct{}
+	botToken      string
+	mode          string // "polling" or "webhook"
+	webhookPort   int
+	webhookPath   string
+	srv           *http.Server
+	client        *http.Client
+	apiBase       string        // overridable for tests
+	deleteAfter   time.Duration // delete every reply after this long; 0 keeps replies
+	webhookURL    string        // public URL registered with setWebhook on start; empty skips registration
+	webhookSecret string        // expected X-Telegram-Bot-Api-Secret-Token; empty accepts any request
+	stopCh        chan struct{}
+
+	feedback channels.FeedbackHandler // receives reactions to replies; nil ignores them
+	replies  *channels.ReplyIndex     // task IDs of sent replies, keyed by message ID
 }
 
 // New creates an uninitialised Telegram plugin.
@@ -41,11 +59,17 @@ func New() *Plugin {
 		client:  &http.Client{Timeout: 60 * time.Second},
 		apiBase: telegramAPIBase,
 		stopCh:  make(chan struct{}),
+		replies: channels.NewReplyIndex(0),
 	}
 }
 
 func (p *Plugin) Name() string { return "telegram" }
 
+// SetFeedbackHandler implements channels.FeedbackPlugin. Thumbs-up and
+// thumbs-down reactions to the bot's replies are passed to h. Telegram only
+// reports reactions in chats where the bot is an administrator.
+func (p *Plugin) SetFeedbackHandler(h channels.FeedbackHandler) { p.feedback = h }
+
 func (p *Plugin) Init(cfg channels.ChannelConfig) error {
 	settings := channels.ResolveEnvVars(&cfg)
 
@@ -71,6 +95,20 @@ func (p *Plugin) Init(cfg channels.ChannelConfig) error {
 		p.webhookPath = defaultWebhookPath
 	}
 
+	p.webhookURL = settings["webhook_url"]
+	p.webhookSecret = settings["webhook_secret"]
+	if p.webhookSecret != "" && !webhookSecretRe.MatchString(p.webhookSecret) {
+		return fmt.Errorf("telegram: webhook_secret must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
+
+	if v := settings["delete_after"]; v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("telegram: delete_after must be a positive duration, got %q", v)
+		}
+		p.deleteAfter = d
+	}
+
 	return nil
 }
 
@@ -111,6 +149,12 @@ func (p *Plugin) startWebhook(ctx context.Context, handler channels.EventHandler
 		p.Stop() //nolint:errcheck
 	}()
 
+	if p.webhookURL != "" {
+		if err := p.setWebhook(ctx); err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("  Telegram adapter (webhook) listening on :%d%s\n", p.webhookPort, p.webhookPath)
 	if err := p.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		return err
@@ -118,14 +162,33 @@ func (p *Plugin) startWebhook(ctx context.Context, handler channels.EventHandler
 	return nil
 }
 
+// makeWebhookHandler returns the handler for webhook updates. When a
+// webhook_secret is configured, requests without the matching
+// X-Telegram-Bot-Api-Secret-Token header are rejected.
 func (p *Plugin) makeWebhookHandler(handler channels.EventHandler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if p.webhookSecret != "" {
+			token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(p.webhookSecret)) != 1 {
+				http.Error(w, "invalid secret token", http.StatusForbidden)
+				return
+			}
+		}
+
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			http.Error(w, "failed to read body", http.StatusBadRequest)
 			return
 		}
 
+		// Reactions are feedback on earlier replies, not agent input
+		var update telegramUpdate
+		if json.Unmarshal(body, &update) == nil && update.MessageReaction != nil {
+			w.WriteHeader(http.StatusOK)
+			p.dispatchFeedback(body)
+			return
+		}
+
 		event, err := p.NormalizeEvent(body)
 		if err != nil {
 			http.Error(w, "invalid update", http.StatusBadRequest)
@@ -133,6 +196,9 @@ func (p *Plugin) makeWebhookHandler(handler channels.EventHandler) http.HandlerF
 		}
 
 		w.WriteHeader(http.StatusOK)
+		if update.CallbackQuery != nil {
+			go p.answerCallbackQuery(update.CallbackQuery.ID) //nolint:errcheck
+		}
 
 		go func() {
 			ctx := context.Background()
@@ -180,7 +246,12 @@ func (p *Plugin) startPolling(ctx context.Context, handler channels.EventHandler
 				offset = update.UpdateID + 1
 			}
 
-			if update.Message == nil {
+			if update.MessageReaction != nil {
+				raw, _ := json.Marshal(update)
+				p.dispatchFeedback(raw)
+				continue
+			}
+			if update.Message == nil && update.CallbackQuery == nil {
 				continue
 			}
 
@@ -189,6 +260,9 @@ func (p *Plugin) startPolling(ctx context.Context, handler channels.EventHandler
 			if err != nil {
 				continue
 			}
+			if update.CallbackQuery != nil {
+				go p.answerCallbackQuery(update.CallbackQuery.ID) //nolint:errcheck
+			}
 
 			go func() {
 				stopTyping := p.startTypingIndicator(ctx, event.WorkspaceID)
@@ -209,6 +283,10 @@ func (p *Plugin) startPolling(ctx context.Context, handler channels.EventHandler
 func (p *Plugin) getUpdates(ctx context.Context, offset int64) ([]telegramUpdate, error) {
 	url := fmt.Sprintf("%s/bot%s/getUpdates?offset=%d&timeout=%d",
 		p.apiBase, p.botToken, offset, pollingTimeout)
+	if updates := p.allowedUpdates(); updates != nil {
+		b, _ := json.Marshal(updates)
+		url += "&allowed_updates=" + neturl.QueryEscape(string(b))
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -236,50 +314,378 @@ func (p *Plugin) getUpdates(ctx context.Context, offset int64) ([]telegramUpdate
 	return result.Result, nil
 }
 
-// NormalizeEvent parses a Telegram Update JSON into a ChannelEvent.
+// allowedUpdates returns the update types to request, or nil for Telegram's
+// default. Reactions are only delivered when requested explicitly, so they
+// are listed, with the other types the plugin handles, once feedback is
+// wanted.
+func (p *Plugin) allowedUpdates() []string {
+	if p.feedback == nil {
+		return nil
+	}
+	return []string{"message", "callback_query", "message_reaction"}
+}
+
+// setWebhook registers webhookURL with Telegram, along with the secret
+// token Telegram then sends with every update.
+func (p *Plugin) setWebhook(ctx context.Context) error {
+	payload := map[string]any{"url": p.webhookURL}
+	if p.webhookSecret != "" {
+		payload["secret_token"] = p.webhookSecret
+	}
+	if updates := p.allowedUpdates(); updates != nil {
+		payload["allowed_updates"] = updates
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshalling setWebhook request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/setWebhook", p.apiBase, p.botToken)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating setWebhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("registering telegram webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("telegram setWebhook error %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// NormalizeEvent parses a Telegram Update JSON into a ChannelEvent. A
+// callback_query from an inline keyboard button becomes an event whose
+// message is the button's callback data.
 func (p *Plugin) NormalizeEvent(raw []byte) (*channels.ChannelEvent, error) {
 	var update telegramUpdate
 	if err := json.Unmarshal(raw, &update); err != nil {
 		return nil, fmt.Errorf("parsing telegram update: %w", err)
 	}
 
+	if cq := update.CallbackQuery; cq != nil {
+		return normalizeCallbackQuery(update.UpdateID, cq, raw)
+	}
 	if update.Message == nil {
 		return nil, fmt.Errorf("telegram update has no message")
 	}
 
+	msg := update.Message
+	text := msg.Text
+	if text == "" {
+		// Media messages carry their text in the caption
+		text = msg.Caption
+	}
+
 	return &channels.ChannelEvent{
 		Channel:     "telegram",
-		WorkspaceID: strconv.FormatInt(update.Message.Chat.ID, 10),
-		UserID:      strconv.FormatInt(update.Message.From.ID, 10),
-		ThreadID:    strconv.FormatInt(update.Message.MessageID, 10),
-		Message:     update.Message.Text,
+		WorkspaceID: strconv.FormatInt(msg.Chat.ID, 10),
+		UserID:      strconv.FormatInt(msg.From.ID, 10),
+		ThreadID:    strconv.FormatInt(msg.MessageID, 10),
+		MessageID:   strconv.FormatInt(update.UpdateID, 10),
+		Message:     text,
+		Context:     messageContext(msg),
+		Raw:         raw,
+	}, nil
+}
+
+// normalizeCallbackQuery converts a button press into a ChannelEvent. The
+// text of the message carrying the keyboard is kept as reply context so the
+// agent knows which question was answered.
+func normalizeCallbackQuery(updateID int64, cq *telegramCallbackQuery, raw []byte) (*channels.ChannelEvent, error) {
+	if cq.Message == nil {
+		return nil, fmt.Errorf("telegram callback query has no message")
+	}
+	event := &channels.ChannelEvent{
+		Channel:     "telegram",
+		WorkspaceID: strconv.FormatInt(cq.Message.Chat.ID, 10),
+		UserID:      strconv.FormatInt(cq.From.ID, 10),
+		ThreadID:    strconv.FormatInt(cq.Message.MessageID, 10),
+		MessageID:   strconv.FormatInt(updateID, 10),
+		Message:     cq.Data,
 		Raw:         raw,
+	}
+	if cq.Message.Text != "" {
+		event.Context = &channels.MessageContext{ReplyToText: cq.Message.Text}
+	}
+	return event, nil
+}
+
+// NormalizeReaction parses a Telegram message_reaction update into a
+// FeedbackEvent, linking it to the task that produced the reacted-to reply
+// when that reply was sent by this plugin. The first emoji of the new
+// reactions is used; a removed reaction has no reaction and rating 0.
+func (p *Plugin) NormalizeReaction(raw []byte) (*channels.FeedbackEvent, error) {
+	var update telegramUpdate
+	if err := json.Unmarshal(raw, &update); err != nil {
+		return nil, fmt.Errorf("parsing telegram update: %w", err)
+	}
+	mr := update.MessageReaction
+	if mr == nil {
+		return nil, fmt.Errorf("telegram update has no message_reaction")
+	}
+
+	var reaction string
+	for _, r := range mr.NewReaction {
+		if r.Type == "emoji" {
+			reaction = r.Emoji
+			break
+		}
+	}
+	var userID string
+	if mr.User != nil {
+		userID = strconv.FormatInt(mr.User.ID, 10)
+	}
+	chatID := strconv.FormatInt(mr.Chat.ID, 10)
+	messageID := strconv.FormatInt(mr.MessageID, 10)
+
+	return &channels.FeedbackEvent{
+		Channel:     "telegram",
+		WorkspaceID: chatID,
+		UserID:      userID,
+		MessageID:   messageID,
+		TaskID:      p.replies.TaskID(chatID, messageID),
+		Reaction:    reaction,
+		Rating:      channels.ReactionRating(reaction),
 	}, nil
 }
 
-// SendResponse sends a text message back to the Telegram chat.
+// dispatchFeedback passes a rated reaction on a known reply to the feedback
+// handler. Other reactions are ignored.
+func (p *Plugin) dispatchFeedback(raw []byte) {
+	if p.feedback == nil {
+		return
+	}
+	fb, err := p.NormalizeReaction(raw)
+	if err != nil || fb.TaskID == "" || fb.Rating == 0 {
+		return
+	}
+	go func() {
+		if err := p.feedback(context.Background(), fb); err != nil {
+			fmt.Printf("telegram: feedback handler error: %v\n", err)
+		}
+	}()
+}
+
+// messageContext extracts the caption, replied-to message, quote, and
+// forward source of msg. It returns nil when there is none.
+func messageContext(msg *telegramMessage) *channels.MessageContext {
+	c := &channels.MessageContext{Caption: msg.Caption}
+	if r := msg.ReplyToMessage; r != nil {
+		c.ReplyToText = r.Text
+		if c.ReplyToText == "" {
+			c.ReplyToText = r.Caption
+		}
+	}
+	if msg.Quote != nil {
+		c.QuotedText = msg.Quote.Text
+	}
+	c.ForwardedFrom = forwardSource(msg)
+
+	if *c == (channels.MessageContext{}) {
+		return nil
+	}
+	return c
+}
+
+// forwardSource describes where a forwarded message came from, preferring
+// forward_origin and falling back to the legacy forward_* fields.
+func forwardSource(msg *telegramMessage) string {
+	if o := msg.ForwardOrigin; o != nil {
+		switch {
+		case o.SenderUser != nil:
+			return o.SenderUser.displayName()
+		case o.SenderUserName != "":
+			return o.SenderUserName
+		case o.Chat != nil:
+			return o.Chat.displayName()
+		case o.SenderChat != nil:
+			return o.SenderChat.displayName()
+		}
+	}
+	switch {
+	case msg.ForwardFrom != nil:
+		return msg.ForwardFrom.displayName()
+	case msg.ForwardFromChat != nil:
+		return msg.ForwardFromChat.displayName()
+	}
+	return msg.ForwardSenderName
+}
+
+// SendResponse sends a text message back to the Telegram chat. Choices
+// offered in a data part are shown as an inline keyboard on the last
+// message. When delete_after is set, or the response is marked ephemeral,
+// the sent messages are deleted once the delay passes.
 func (p *Plugin) SendResponse(event *channels.ChannelEvent, response *a2a.Message) error {
-	text := extractText(response)
-	html := markdown.ToTelegramHTML(text)
-	chunks := markdown.SplitMessage(html, 4096)
+	keyboard, keyboardPart := inlineKeyboard(response)
+	text := extractText(response, keyboardPart)
+	chunks := splitForTelegram(text, maxMessageLength)
 
+	var sent []int64
 	for i, chunk := range chunks {
 		payload := map[string]any{
 			"chat_id":    event.WorkspaceID,
-			"text":       chunk,
+			"text":       chunk.html,
 			"parse_mode": "HTML",
 		}
 		if i == 0 {
 			payload["reply_to_message_id"] = event.ThreadID
 		}
-		if err := p.sendMessage(payload); err != nil {
+		if i == len(chunks)-1 && keyboard != nil {
+			payload["reply_markup"] = keyboard
+		}
+		id, err := p.sendMessage(payload)
+		if err != nil {
 			// Fallback: retry without parse_mode (plain text)
 			delete(payload, "parse_mode")
-			payload["text"] = text
-			if fbErr := p.sendMessage(payload); fbErr != nil {
+			payload["text"] = chunk.markdown
+			var fbErr error
+			if id, fbErr = p.sendMessage(payload); fbErr != nil {
 				return fbErr
 			}
 		}
+		if id != 0 {
+			sent = append(sent, id)
+			p.replies.Add(event.WorkspaceID, strconv.FormatInt(id, 10), event.TaskID)
+		}
+	}
+
+	ttl := p.deleteAfter
+	if ttl == 0 && channels.IsEphemeral(response) {
+		ttl = defaultEphemeralTTL
+	}
+	if ttl > 0 && len(sent) > 0 {
+		p.scheduleDelete(event.WorkspaceID, sent, ttl)
+	}
+	return nil
+}
+
+// maxMessageLength is Telegram's limit on the text of one message.
+const maxMessageLength = 4096
+
+// telegramChunk is one message of a reply, as HTML and as the markdown it
+// was converted from, which is sent as plain text when the HTML is rejected.
+type telegramChunk struct {
+	markdown string
+	html     string
+}
+
+// splitForTelegram splits markdown text into messages before converting
+// them to HTML, so code blocks stay intact in every message. Escaping can
+// push a chunk's HTML past the limit; such a chunk is split again with a
+// smaller limit.
+func splitForTelegram(text string, limit int) []telegramChunk {
+	var chunks []telegramChunk
+	for _, md := range markdown.SplitMessage(text, limit) {
+		html := markdown.ToTelegramHTML(md)
+		if len(html) > maxMessageLength && limit > 256 {
+			chunks = append(chunks, splitForTelegram(md, limit/2)...)
+			continue
+		}
+		chunks = append(chunks, telegramChunk{markdown: md, html: html})
+	}
+	return chunks
+}
+
+// inlineKeyboard builds a reply_markup inline keyboard, one button per row,
+// from the first choices part of msg. It also returns the index of that
+// part, or -1 when there is none. Choices whose reply exceeds Telegram's
+// 64-byte callback data limit cannot be buttons, so such a part is left to
+// be listed as text.
+func inlineKeyboard(msg *a2a.Message) (map[string]any, int) {
+	if msg == nil {
+		return nil, -1
+	}
+	for i, part := range msg.Parts {
+		choices, ok := channels.PartChoices(part)
+		if !ok {
+			continue
+		}
+		rows := make([][]map[string]string, 0, len(choices))
+		for _, c := range choices {
+			if len(c.Reply()) > maxCallbackData {
+				return nil, -1
+			}
+			rows = append(rows, []map[string]string{{"text": c.Label, "callback_data": c.Reply()}})
+		}
+		return map[string]any{"inline_keyboard": rows}, i
+	}
+	return nil, -1
+}
+
+// answerCallbackQuery acknowledges a button press, which stops the button's
+// loading indicator in the Telegram client.
+func (p *Plugin) answerCallbackQuery(queryID string) error {
+	body, err := json.Marshal(map[string]string{"callback_query_id": queryID})
+	if err != nil {
+		return fmt.Errorf("marshalling callback answer: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/answerCallbackQuery", p.apiBase, p.botToken)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating callback answer request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("answering telegram callback query: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("telegram API error %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// scheduleDelete deletes the given messages after ttl. Failures are logged;
+// Telegram only lets bots delete messages younger than 48 hours.
+func (p *Plugin) scheduleDelete(chatID string, messageIDs []int64, ttl time.Duration) {
+	time.AfterFunc(ttl, func() {
+		for _, id := range messageIDs {
+			if err := p.deleteMessage(chatID, id); err != nil {
+				fmt.Printf("telegram: delete message error: %v\n", err)
+			}
+		}
+	})
+}
+
+// deleteMessage deletes a message the bot sent.
+func (p *Plugin) deleteMessage(chatID string, messageID int64) error {
+	payload := map[string]any{
+		"chat_id":    chatID,
+		"message_id": messageID,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshalling delete request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/deleteMessage", p.apiBase, p.botToken)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating delete request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("deleting telegram message: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("telegram API error %d: %s", resp.StatusCode, string(respBody))
 	}
 	return nil
 }
@@ -345,47 +751,64 @@ func (p *Plugin) startTypingIndicator(ctx context.Context, chatID string) (stop
 	return stop
 }
 
-// sendMessage posts a JSON payload to the Telegram sendMessage API.
-func (p *Plugin) sendMessage(payload map[string]any) error {
+// sendMessage posts a JSON payload to the Telegram sendMessage API and
+// returns the sent message's ID, or 0 if the response does not include it.
+func (p *Plugin) sendMessage(payload map[string]any) (int64, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshalling telegram response: %w", err)
+		return 0, fmt.Errorf("marshalling telegram response: %w", err)
 	}
 
 	url := fmt.Sprintf("%s/bot%s/sendMessage", p.apiBase, p.botToken)
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("creating telegram request: %w", err)
+		return 0, fmt.Errorf("creating telegram request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("posting to telegram: %w", err)
+		return 0, fmt.Errorf("posting to telegram: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("telegram API error %d: %s", resp.StatusCode, string(respBody))
+		return 0, fmt.Errorf("telegram API error %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	return nil
+	var result struct {
+		Result struct {
+			MessageID int64 `json:"message_id"`
+		} `json:"result"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result) //nolint:errcheck
+	return result.Result.MessageID, nil
 }
 
-// extractText concatenates all text parts from an A2A message.
-func extractText(msg *a2a.Message) string {
+// extractText concatenates all text parts from an A2A message. File and
+// data parts, which are not uploaded, are mentioned on their own lines,
+// except the parts at the skip indexes, which are rendered otherwise.
+func extractText(msg *a2a.Message, skip ...int) string {
 	if msg == nil {
 		return "(no response)"
 	}
 	var text string
-	for _, p := range msg.Parts {
-		if p.Kind == a2a.PartKindText {
-			if text != "" {
-				text += "\n"
-			}
-			text += p.Text
+	for i, p := range msg.Parts {
+		if slices.Contains(skip, i) {
+			continue
+		}
+		line := p.Text
+		if p.Kind != a2a.PartKindText {
+			line = channels.PartNote(p)
+		}
+		if line == "" {
+			continue
 		}
+		if text != "" {
+			text += "\n"
+		}
+		text += line
 	}
 	if text == "" {
 		text = "(no text response)"
@@ -393,24 +816,98 @@ func extractText(msg *a2a.Message) string {
 	return text
 }
 
+// webhookSecretRe matches the secret tokens Telegram accepts.
+var webhookSecretRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 // Telegram API types (minimal, for parsing).
 
 type telegramUpdate struct {
-	UpdateID int64            `json:"update_id"`
-	Message  *telegramMessage `json:"message,omitempty"`
+	UpdateID        int64                    `json:"update_id"`
+	Message         *telegramMessage         `json:"message,omitempty"`
+	MessageReaction *telegramMessageReaction `json:"message_reaction,omitempty"`
+	CallbackQuery   *telegramCallbackQuery   `json:"callback_query,omitempty"`
+}
+
+// telegramCallbackQuery reports a press of an inline keyboard button.
+type telegramCallbackQuery struct {
+	ID      string           `json:"id"`
+	From    telegramUser     `json:"from"`
+	Message *telegramMessage `json:"message,omitempty"`
+	Data    string           `json:"data,omitempty"`
+}
+
+// telegramMessageReaction reports a change to a user's reactions on a message.
+type telegramMessageReaction struct {
+	Chat        telegramChat           `json:"chat"`
+	MessageID   int64                  `json:"message_id"`
+	User        *telegramUser          `json:"user,omitempty"` // absent for anonymous reactions
+	NewReaction []telegramReactionType `json:"new_reaction"`
+}
+
+type telegramReactionType struct {
+	Type  string `json:"type"` // "emoji", "custom_emoji", or "paid"
+	Emoji string `json:"emoji,omitempty"`
 }
 
 type telegramMessage struct {
-	MessageID int64        `json:"message_id"`
-	From      telegramUser `json:"from"`
-	Chat      telegramChat `json:"chat"`
-	Text      string       `json:"text"`
+	MessageID         int64                  `json:"message_id"`
+	From              telegramUser           `json:"from"`
+	Chat              telegramChat           `json:"chat"`
+	Text              string                 `json:"text"`
+	Caption           string                 `json:"caption,omitempty"`
+	ReplyToMessage    *telegramMessage       `json:"reply_to_message,omitempty"`
+	Quote             *telegramTextQuote     `json:"quote,omitempty"`
+	ForwardOrigin     *telegramForwardOrigin `json:"forward_origin,omitempty"`
+	ForwardFrom       *telegramUser          `json:"forward_from,omitempty"`
+	ForwardFromChat   *telegramChat          `json:"forward_from_chat,omitempty"`
+	ForwardSenderName string                 `json:"forward_sender_name,omitempty"`
 }
 
 type telegramUser struct {
-	ID int64 `json:"id"`
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+}
+
+func (u *telegramUser) displayName() string {
+	name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	switch {
+	case name != "" && u.Username != "":
+		return name + " (@" + u.Username + ")"
+	case name != "":
+		return name
+	case u.Username != "":
+		return "@" + u.Username
+	}
+	return strconv.FormatInt(u.ID, 10)
 }
 
 type telegramChat struct {
-	ID int64 `json:"id"`
+	ID       int64  `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+func (c *telegramChat) displayName() string {
+	switch {
+	case c.Title != "":
+		return c.Title
+	case c.Username != "":
+		return "@" + c.Username
+	}
+	return strconv.FormatInt(c.ID, 10)
+}
+
+type telegramTextQuote struct {
+	Text string `json:"text"`
+}
+
+// telegramForwardOrigin describes the origin of a forwarded message.
+type telegramForwardOrigin struct {
+	Type           string        `json:"type"`
+	SenderUser     *telegramUser `json:"sender_user,omitempty"`
+	SenderUserName string        `json:"sender_user_name,omitempty"`
+	SenderChat     *telegramChat `json:"sender_chat,omitempty"`
+	Chat           *telegramChat `json:"chat,omitempty"`
 }
@@ -43,11 +43,116 @@ func TestNormalizeEvent(t *testing.T) {
 	if event.ThreadID != "42" {
 		t.Errorf("ThreadID = %q, want 42", event.ThreadID)
 	}
+	if event.MessageID != "100" {
+		t.Errorf("MessageID = %q, want the update_id 100", event.MessageID)
+	}
 	if event.Message != "hello bot" {
 		t.Errorf("Message = %q, want 'hello bot'", event.Message)
 	}
 }
 
+func TestNormalizeEvent_PhotoWithCaption(t *testing.T) {
+	raw := `{
+		"update_id": 101,
+		"message": {
+			"message_id": 43,
+			"from": {"id": 12345},
+			"chat": {"id": 67890},
+			"photo": [{"file_id": "abc", "width": 90, "height": 90}],
+			"caption": "what is in this chart?"
+		}
+	}`
+
+	p := New()
+	event, err := p.NormalizeEvent([]byte(raw))
+	if err != nil {
+		t.Fatalf("NormalizeEvent() error: %v", err)
+	}
+	if event.Message != "what is in this chart?" {
+		t.Errorf("Message = %q, want caption", event.Message)
+	}
+	if event.Context == nil || event.Context.Caption != "what is in this chart?" {
+		t.Errorf("Context.Caption not captured: %+v", event.Context)
+	}
+}
+
+func TestNormalizeEvent_ReplyAndForward(t *testing.T) {
+	raw := `{
+		"update_id": 102,
+		"message": {
+			"message_id": 44,
+			"from": {"id": 12345},
+			"chat": {"id": 67890},
+			"text": "can you summarize this?",
+			"reply_to_message": {
+				"message_id": 40,
+				"from": {"id": 999},
+				"chat": {"id": 67890},
+				"text": "Quarterly revenue grew 12% while costs fell."
+			},
+			"quote": {"text": "revenue grew 12%"},
+			"forward_origin": {
+				"type": "user",
+				"sender_user": {"id": 555, "first_name": "Ada", "last_name": "Lovelace", "username": "ada"}
+			}
+		}
+	}`
+
+	p := New()
+	event, err := p.NormalizeEvent([]byte(raw))
+	if err != nil {
+		t.Fatalf("NormalizeEvent() error: %v", err)
+	}
+	if event.Message != "can you summarize this?" {
+		t.Errorf("Message = %q", event.Message)
+	}
+	c := event.Context
+	if c == nil {
+		t.Fatal("expected message context")
+	}
+	if c.ReplyToText != "Quarterly revenue grew 12% while costs fell." {
+		t.Errorf("ReplyToText = %q", c.ReplyToText)
+	}
+	if c.QuotedText != "revenue grew 12%" {
+		t.Errorf("QuotedText = %q", c.QuotedText)
+	}
+	if c.ForwardedFrom != "Ada Lovelace (@ada)" {
+		t.Errorf("ForwardedFrom = %q", c.ForwardedFrom)
+	}
+}
+
+func TestNormalizeEvent_LegacyForwardFromChat(t *testing.T) {
+	raw := `{
+		"update_id": 103,
+		"message": {
+			"message_id": 45,
+			"from": {"id": 12345},
+			"chat": {"id": 67890},
+			"text": "news",
+			"forward_from_chat": {"id": -100, "title": "Release Notes"}
+		}
+	}`
+
+	event, err := New().NormalizeEvent([]byte(raw))
+	if err != nil {
+		t.Fatalf("NormalizeEvent() error: %v", err)
+	}
+	if event.Context == nil || event.Context.ForwardedFrom != "Release Notes" {
+		t.Errorf("ForwardedFrom not captured: %+v", event.Context)
+	}
+}
+
+func TestNormalizeEvent_PlainTextHasNoContext(t *testing.T) {
+	raw := `{"update_id": 104, "message": {"message_id": 46, "from": {"id": 1}, "chat": {"id": 2}, "text": "hi"}}`
+	event, err := New().NormalizeEvent([]byte(raw))
+	if err != nil {
+		t.Fatalf("NormalizeEvent() error: %v", err)
+	}
+	if event.Context != nil {
+		t.Errorf("expected nil context, got %+v", event.Context)
+	}
+}
+
 func TestNormalizeEvent_NoMessage(t *testing.T) {
 	raw := `{"update_id": 100}`
 
@@ -210,6 +315,346 @@ func TestWebhookHandler(t *testing.T) {
 	}
 }
 
+func TestSendResponse_InlineKeyboard(t *testing.T) {
+	var payload map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)                           //nolint:errcheck
+		w.Write([]byte(`{"ok":true,"result":{"message_id":5}}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := New()
+	p.botToken = "test-token"
+	p.apiBase = srv.URL
+
+	msg := &a2a.Message{
+		Role: a2a.MessageRoleAgent,
+		Parts: []a2a.Part{
+			a2a.NewTextPart("Which environment?"),
+			channels.NewChoicesPart(channels.Choice{Label: "Staging", Value: "deploy to staging"}, channels.Choice{Label: "Production"}),
+		},
+	}
+	if err := p.SendResponse(&channels.ChannelEvent{WorkspaceID: "67890", ThreadID: "42"}, msg); err != nil {
+		t.Fatalf("SendResponse() error: %v", err)
+	}
+
+	if payload["text"] != "Which environment?" {
+		t.Errorf("text = %q, want the choices left out of the text", payload["text"])
+	}
+	markup, _ := json.Marshal(payload["reply_markup"])
+	want := `{"inline_keyboard":[[{"callback_data":"deploy to staging","text":"Staging"}],[{"callback_data":"Production","text":"Production"}]]}`
+	if string(markup) != want {
+		t.Errorf("reply_markup = %s, want %s", markup, want)
+	}
+}
+
+func TestSendResponse_LongCodeBlock(t *testing.T) {
+	var texts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload) //nolint:errcheck
+		texts = append(texts, payload["text"].(string))
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := New()
+	p.apiBase = srv.URL
+	code := strings.Repeat("if a < b && b > c { return }\n", 300)
+	msg := &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart("```go\n" + code + "```")}}
+	if err := p.SendResponse(&channels.ChannelEvent{WorkspaceID: "1"}, msg); err != nil {
+		t.Fatalf("SendResponse() error: %v", err)
+	}
+
+	if len(texts) < 2 {
+		t.Fatalf("sent %d messages, want the code block split", len(texts))
+	}
+	for i, text := range texts {
+		if len(text) > maxMessageLength {
+			t.Errorf("message %d is %d characters", i, len(text))
+		}
+		if !strings.HasPrefix(text, "<pre><code") || !strings.HasSuffix(text, "</code></pre>") {
+			t.Errorf("message %d is not a complete code block: %.60q...", i, text)
+		}
+	}
+}
+
+func TestSendResponse_ChoicesTooLongForButtons(t *testing.T) {
+	var payload map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload) //nolint:errcheck
+		w.Write([]byte(`{"ok":true}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := New()
+	p.apiBase = srv.URL
+	msg := &a2a.Message{Parts: []a2a.Part{
+		channels.NewChoicesPart(channels.Choice{Label: "Long", Value: strings.Repeat("x", 65)}),
+	}}
+	if err := p.SendResponse(&channels.ChannelEvent{WorkspaceID: "1"}, msg); err != nil {
+		t.Fatalf("SendResponse() error: %v", err)
+	}
+	if _, ok := payload["reply_markup"]; ok {
+		t.Error("reply_markup set for a choice over the callback data limit")
+	}
+	if payload["text"] != "[Options: Long]" {
+		t.Errorf("text = %q, want the choices listed", payload["text"])
+	}
+}
+
+func TestNormalizeEvent_CallbackQuery(t *testing.T) {
+	raw := `{
+		"update_id": 7,
+		"callback_query": {
+			"id": "cb-1",
+			"from": {"id": 12345, "first_name": "Alice"},
+			"message": {"message_id": 99, "chat": {"id": 67890}, "text": "Which environment?"},
+			"data": "deploy to staging"
+		}
+	}`
+
+	event, err := New().NormalizeEvent([]byte(raw))
+	if err != nil {
+		t.Fatalf("NormalizeEvent() error: %v", err)
+	}
+	if event.WorkspaceID != "67890" || event.UserID != "12345" || event.ThreadID != "99" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.MessageID != "7" {
+		t.Errorf("MessageID = %q, want the update_id 7", event.MessageID)
+	}
+	if event.Message != "deploy to staging" {
+		t.Errorf("Message = %q, want the callback data", event.Message)
+	}
+	if event.Context == nil || event.Context.ReplyToText != "Which environment?" {
+		t.Errorf("Context = %+v, want the keyboard message as reply context", event.Context)
+	}
+}
+
+func TestWebhookHandler_CallbackQueryAnswered(t *testing.T) {
+	answered := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/answerCallbackQuery") {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body) //nolint:errcheck
+			answered <- body["callback_query_id"]
+		}
+		w.Write([]byte(`{"ok":true}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := New()
+	p.botToken = "test-token"
+	p.apiBase = srv.URL
+
+	events := make(chan *channels.ChannelEvent, 1)
+	handler := p.makeWebhookHandler(func(_ context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+		events <- event
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("ok")}}, nil
+	})
+
+	body := `{"update_id":8,"callback_query":{"id":"cb-2","from":{"id":1},"message":{"message_id":3,"chat":{"id":2}},"data":"yes"}}`
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+
+	select {
+	case id := <-answered:
+		if id != "cb-2" {
+			t.Errorf("answered callback %q, want cb-2", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback query not answered")
+	}
+	select {
+	case event := <-events:
+		if event.Message != "yes" {
+			t.Errorf("event.Message = %q, want yes", event.Message)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler not called")
+	}
+}
+
+func TestWebhookHandler_SecretToken(t *testing.T) {
+	p := New()
+	if err := p.Init(channels.ChannelConfig{Settings: map[string]string{
+		"bot_token":      "test-token",
+		"mode":           "webhook",
+		"webhook_secret": "s3cret_token-1",
+	}}); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+	p.apiBase = srv.URL
+
+	events := make(chan *channels.ChannelEvent, 1)
+	handler := p.makeWebhookHandler(func(_ context.Context, event *channels.ChannelEvent) (*a2a.Message, error) {
+		events <- event
+		return &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("ok")}}, nil
+	})
+	body := `{"update_id":1,"message":{"message_id":10,"from":{"id":1},"chat":{"id":2},"text":"hello"}}`
+
+	for _, token := range []string{"", "wrong"} {
+		req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("X-Telegram-Bot-Api-Secret-Token", token)
+		}
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("token %q: status = %d, want 403", token, rr.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(body))
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", "s3cret_token-1")
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("correct token: status = %d, want 200", rr.Code)
+	}
+	select {
+	case event := <-events:
+		if event.Message != "hello" {
+			t.Errorf("event.Message = %q, want hello", event.Message)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler not called for the correct token")
+	}
+	select {
+	case event := <-events:
+		t.Errorf("rejected request reached the handler: %+v", event)
+	default:
+	}
+}
+
+func TestSetWebhook(t *testing.T) {
+	var path string
+	var payload map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&payload) //nolint:errcheck
+		w.Write([]byte(`{"ok":true}`))           //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := New()
+	if err := p.Init(channels.ChannelConfig{Settings: map[string]string{
+		"bot_token":      "test-token",
+		"mode":           "webhook",
+		"webhook_url":    "https://bot.example.com/telegram/webhook",
+		"webhook_secret": "s3cret",
+	}}); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+	p.apiBase = srv.URL
+
+	if err := p.setWebhook(context.Background()); err != nil {
+		t.Fatalf("setWebhook() error: %v", err)
+	}
+	if path != "/bottest-token/setWebhook" {
+		t.Errorf("path = %q", path)
+	}
+	if payload["url"] != "https://bot.example.com/telegram/webhook" || payload["secret_token"] != "s3cret" {
+		t.Errorf("payload = %v", payload)
+	}
+}
+
+func TestInit_InvalidWebhookSecret(t *testing.T) {
+	err := New().Init(channels.ChannelConfig{Settings: map[string]string{
+		"bot_token":      "test-token",
+		"webhook_secret": "has spaces!",
+	}})
+	if err == nil {
+		t.Fatal("expected error for a webhook_secret Telegram would refuse")
+	}
+}
+
+func TestReactionFeedback(t *testing.T) {
+	var allowedUpdates string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/getUpdates") {
+			allowedUpdates = r.URL.Query().Get("allowed_updates")
+			w.Write([]byte(`{"ok":true,"result":[]}`)) //nolint:errcheck
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{"message_id":77}}`)) //nolint:errcheck
+	}))
+	defer srv.Close()
+
+	p := New()
+	p.botToken = "test-token"
+	p.apiBase = srv.URL
+
+	// The reply sent for a task is remembered so reactions can link to it
+	event := &channels.ChannelEvent{WorkspaceID: "-100", ThreadID: "10", TaskID: "telegram--100-42"}
+	reply := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("answer")}}
+	if err := p.SendResponse(event, reply); err != nil {
+		t.Fatalf("SendResponse() error: %v", err)
+	}
+
+	feedback := make(chan *channels.FeedbackEvent, 1)
+	p.SetFeedbackHandler(func(_ context.Context, fb *channels.FeedbackEvent) error {
+		feedback <- fb
+		return nil
+	})
+
+	handlerCalled := false
+	handler := p.makeWebhookHandler(func(_ context.Context, _ *channels.ChannelEvent) (*a2a.Message, error) {
+		handlerCalled = true
+		return nil, nil
+	})
+	body := `{"update_id":5,"message_reaction":{"chat":{"id":-100},"message_id":77,"user":{"id":7},"date":1,"old_reaction":[],"new_reaction":[{"type":"emoji","emoji":"👍"}]}}`
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(body)))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rr.Code)
+	}
+	if handlerCalled {
+		t.Error("reactions should not be forwarded to the agent")
+	}
+
+	select {
+	case fb := <-feedback:
+		want := channels.FeedbackEvent{
+			Channel: "telegram", WorkspaceID: "-100", UserID: "7", MessageID: "77",
+			TaskID: "telegram--100-42", Reaction: "👍", Rating: 1,
+		}
+		if *fb != want {
+			t.Errorf("feedback = %+v, want %+v", *fb, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("feedback handler not called")
+	}
+
+	// Polling must ask for reactions explicitly once feedback is wanted
+	if _, err := p.getUpdates(context.Background(), 0); err != nil {
+		t.Fatalf("getUpdates() error: %v", err)
+	}
+	if allowedUpdates != `["message","callback_query","message_reaction"]` {
+		t.Errorf("allowed_updates = %q", allowedUpdates)
+	}
+
+	// A removed reaction carries no rating
+	removed, err := p.NormalizeReaction([]byte(`{"update_id":6,"message_reaction":{"chat":{"id":-100},"message_id":77,"user":{"id":7},"new_reaction":[]}}`))
+	if err != nil {
+		t.Fatalf("NormalizeReaction() error: %v", err)
+	}
+	if removed.Rating != 0 || removed.TaskID != "telegram--100-42" {
+		t.Errorf("removed reaction = %+v", removed)
+	}
+}
+
 func TestExtractText(t *testing.T) {
 	tests := []struct {
 		name string
@@ -219,7 +664,12 @@ func TestExtractText(t *testing.T) {
 		{"nil message", nil, "(no response)"},
 		{"single text", &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart("hello")}}, "hello"},
 		{"multiple text", &a2a.Message{Parts: []a2a.Part{a2a.NewTextPart("a"), a2a.NewTextPart("b")}}, "a\nb"},
-		{"no text parts", &a2a.Message{Parts: []a2a.Part{a2a.NewDataPart(42)}}, "(no text response)"},
+		{"no parts", &a2a.Message{}, "(no text response)"},
+		{"data part", &a2a.Message{Parts: []a2a.Part{a2a.NewDataPart(map[string]int{"total": 42})}}, `[Data: {"total":42}]`},
+		{"text and file", &a2a.Message{Parts: []a2a.Part{
+			a2a.NewTextPart("Here is the report"),
+			a2a.NewFilePart("report.csv", "text/csv", []byte("a,b\n")),
+		}}, "Here is the report\n[Attachment: report.csv (text/csv)]"},
 	}
 
 	for _, tt := range tests {
@@ -351,3 +801,61 @@ func TestInit_InvalidMode(t *testing.T) {
 		t.Fatal("expected error for invalid mode")
 	}
 }
+
+func TestSendResponse_DeleteAfter(t *testing.T) {
+	deleted := make(chan map[string]any, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload) //nolint:errcheck
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			w.Write([]byte(`{"ok":true,"result":{"message_id":777}}`)) //nolint:errcheck
+		case strings.HasSuffix(r.URL.Path, "/deleteMessage"):
+			deleted <- payload
+			w.Write([]byte(`{"ok":true,"result":true}`)) //nolint:errcheck
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test-token")
+	p := New()
+	if err := p.Init(channels.ChannelConfig{Settings: map[string]string{
+		"bot_token_env": "TELEGRAM_BOT_TOKEN",
+		"delete_after":  "50ms",
+	}}); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+	p.apiBase = srv.URL
+
+	event := &channels.ChannelEvent{WorkspaceID: "67890", ThreadID: "42"}
+	msg := &a2a.Message{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.NewTextPart("one-time code: 1234")}}
+	sentAt := time.Now()
+	if err := p.SendResponse(event, msg); err != nil {
+		t.Fatalf("SendResponse() error: %v", err)
+	}
+
+	select {
+	case payload := <-deleted:
+		if elapsed := time.Since(sentAt); elapsed < 50*time.Millisecond {
+			t.Errorf("message deleted after %v, before the 50ms TTL", elapsed)
+		}
+		if payload["chat_id"] != "67890" || payload["message_id"] != float64(777) {
+			t.Errorf("deleteMessage payload = %v", payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("deleteMessage was not called after the TTL")
+	}
+}
+
+func TestInit_InvalidDeleteAfter(t *testing.T) {
+	p := New()
+	err := p.Init(channels.ChannelConfig{Settings: map[string]string{
+		"bot_token":    "test-token",
+		"delete_after": "soon",
+	}})
+	if err == nil || !strings.Contains(err.Error(), "delete_after") {
+		t.Errorf("expected delete_after error, got %v", err)
+	}
+}
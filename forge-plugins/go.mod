@@ -2,6 +2,9 @@ module github.com/initializ/forge/forge-plugins
 
 go 1.25.0
 
-require github.com/initializ/forge/forge-core v0.0.0
+require (
+	github.com/coder/websocket v1.8.13
+	github.com/initializ/forge/forge-core v0.0.0
+)
 
 replace github.com/initializ/forge/forge-core => ../forge-core